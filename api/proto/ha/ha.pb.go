@@ -497,8 +497,31 @@ type SessionCheckpoint struct {
 	HandoffCvlan     uint32 `protobuf:"varint,46,opt,name=handoff_cvlan,json=handoffCvlan,proto3" json:"handoff_cvlan,omitempty"`
 	HandoffTpid      uint32 `protobuf:"varint,47,opt,name=handoff_tpid,json=handoffTpid,proto3" json:"handoff_tpid,omitempty"`
 	Transparent      bool   `protobuf:"varint,48,opt,name=transparent,proto3" json:"transparent,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// L2TPv2 session (access_type "l2tp"), LAC or LNS role. Each session
+	// carries its tunnel so the standby can rebuild the control
+	// connection. l2tp_ns / l2tp_nr are a snapshot taken at the last
+	// sync; the new active resumes from the sequence the peer suggests
+	// during RFC 4951 recovery, which l2tp_failover says both ends
+	// agreed to.
+	L2TpLocalIp            []byte `protobuf:"bytes,49,opt,name=l2tp_local_ip,json=l2tpLocalIp,proto3" json:"l2tp_local_ip,omitempty"`
+	L2TpPeerIp             []byte `protobuf:"bytes,50,opt,name=l2tp_peer_ip,json=l2tpPeerIp,proto3" json:"l2tp_peer_ip,omitempty"`
+	L2TpLocalTunnelId      uint32 `protobuf:"varint,51,opt,name=l2tp_local_tunnel_id,json=l2tpLocalTunnelId,proto3" json:"l2tp_local_tunnel_id,omitempty"`
+	L2TpPeerTunnelId       uint32 `protobuf:"varint,52,opt,name=l2tp_peer_tunnel_id,json=l2tpPeerTunnelId,proto3" json:"l2tp_peer_tunnel_id,omitempty"`
+	L2TpLocalSessionId     uint32 `protobuf:"varint,53,opt,name=l2tp_local_session_id,json=l2tpLocalSessionId,proto3" json:"l2tp_local_session_id,omitempty"`
+	L2TpPeerSessionId      uint32 `protobuf:"varint,54,opt,name=l2tp_peer_session_id,json=l2tpPeerSessionId,proto3" json:"l2tp_peer_session_id,omitempty"`
+	L2TpLocalPort          uint32 `protobuf:"varint,55,opt,name=l2tp_local_port,json=l2tpLocalPort,proto3" json:"l2tp_local_port,omitempty"`
+	L2TpPeerPort           uint32 `protobuf:"varint,56,opt,name=l2tp_peer_port,json=l2tpPeerPort,proto3" json:"l2tp_peer_port,omitempty"`
+	L2TpRole               string `protobuf:"bytes,57,opt,name=l2tp_role,json=l2tpRole,proto3" json:"l2tp_role,omitempty"`
+	L2TpLocalHostname      string `protobuf:"bytes,58,opt,name=l2tp_local_hostname,json=l2tpLocalHostname,proto3" json:"l2tp_local_hostname,omitempty"`
+	L2TpPeerHostname       string `protobuf:"bytes,59,opt,name=l2tp_peer_hostname,json=l2tpPeerHostname,proto3" json:"l2tp_peer_hostname,omitempty"`
+	L2TpNs                 uint32 `protobuf:"varint,60,opt,name=l2tp_ns,json=l2tpNs,proto3" json:"l2tp_ns,omitempty"`
+	L2TpNr                 uint32 `protobuf:"varint,61,opt,name=l2tp_nr,json=l2tpNr,proto3" json:"l2tp_nr,omitempty"`
+	L2TpHelloInterval      uint32 `protobuf:"varint,62,opt,name=l2tp_hello_interval,json=l2tpHelloInterval,proto3" json:"l2tp_hello_interval,omitempty"`
+	L2TpPppHdrSkip         uint32 `protobuf:"varint,63,opt,name=l2tp_ppp_hdr_skip,json=l2tpPppHdrSkip,proto3" json:"l2tp_ppp_hdr_skip,omitempty"`
+	L2TpFailover           bool   `protobuf:"varint,64,opt,name=l2tp_failover,json=l2tpFailover,proto3" json:"l2tp_failover,omitempty"`
+	L2TpPeerRecoveryTimeMs uint32 `protobuf:"varint,65,opt,name=l2tp_peer_recovery_time_ms,json=l2tpPeerRecoveryTimeMs,proto3" json:"l2tp_peer_recovery_time_ms,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *SessionCheckpoint) Reset() {
@@ -860,6 +883,125 @@ func (x *SessionCheckpoint) GetTransparent() bool {
 	return false
 }
 
+func (x *SessionCheckpoint) GetL2TpLocalIp() []byte {
+	if x != nil {
+		return x.L2TpLocalIp
+	}
+	return nil
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerIp() []byte {
+	if x != nil {
+		return x.L2TpPeerIp
+	}
+	return nil
+}
+
+func (x *SessionCheckpoint) GetL2TpLocalTunnelId() uint32 {
+	if x != nil {
+		return x.L2TpLocalTunnelId
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerTunnelId() uint32 {
+	if x != nil {
+		return x.L2TpPeerTunnelId
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpLocalSessionId() uint32 {
+	if x != nil {
+		return x.L2TpLocalSessionId
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerSessionId() uint32 {
+	if x != nil {
+		return x.L2TpPeerSessionId
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpLocalPort() uint32 {
+	if x != nil {
+		return x.L2TpLocalPort
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerPort() uint32 {
+	if x != nil {
+		return x.L2TpPeerPort
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpRole() string {
+	if x != nil {
+		return x.L2TpRole
+	}
+	return ""
+}
+
+func (x *SessionCheckpoint) GetL2TpLocalHostname() string {
+	if x != nil {
+		return x.L2TpLocalHostname
+	}
+	return ""
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerHostname() string {
+	if x != nil {
+		return x.L2TpPeerHostname
+	}
+	return ""
+}
+
+func (x *SessionCheckpoint) GetL2TpNs() uint32 {
+	if x != nil {
+		return x.L2TpNs
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpNr() uint32 {
+	if x != nil {
+		return x.L2TpNr
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpHelloInterval() uint32 {
+	if x != nil {
+		return x.L2TpHelloInterval
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpPppHdrSkip() uint32 {
+	if x != nil {
+		return x.L2TpPppHdrSkip
+	}
+	return 0
+}
+
+func (x *SessionCheckpoint) GetL2TpFailover() bool {
+	if x != nil {
+		return x.L2TpFailover
+	}
+	return false
+}
+
+func (x *SessionCheckpoint) GetL2TpPeerRecoveryTimeMs() uint32 {
+	if x != nil {
+		return x.L2TpPeerRecoveryTimeMs
+	}
+	return 0
+}
+
 type SyncSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SrgName       string                 `protobuf:"bytes,1,opt,name=srg_name,json=srgName,proto3" json:"srg_name,omitempty"`
@@ -1110,8 +1252,17 @@ type CGNATMappingCheckpoint struct {
 	PortBlockStart uint32                 `protobuf:"varint,6,opt,name=port_block_start,json=portBlockStart,proto3" json:"port_block_start,omitempty"`
 	PortBlockEnd   uint32                 `protobuf:"varint,7,opt,name=port_block_end,json=portBlockEnd,proto3" json:"port_block_end,omitempty"`
 	InsideVrfId    uint32                 `protobuf:"varint,8,opt,name=inside_vrf_id,json=insideVrfId,proto3" json:"inside_vrf_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Set only when the checkpoint is a single-port forward rather than a block.
+	ForwardProtocol    string `protobuf:"bytes,9,opt,name=forward_protocol,json=forwardProtocol,proto3" json:"forward_protocol,omitempty"`
+	ForwardInsidePort  uint32 `protobuf:"varint,10,opt,name=forward_inside_port,json=forwardInsidePort,proto3" json:"forward_inside_port,omitempty"`
+	ForwardSource      string `protobuf:"bytes,11,opt,name=forward_source,json=forwardSource,proto3" json:"forward_source,omitempty"`
+	ForwardExpiresUnix int64  `protobuf:"varint,12,opt,name=forward_expires_unix,json=forwardExpiresUnix,proto3" json:"forward_expires_unix,omitempty"`
+	ForwardNonce       []byte `protobuf:"bytes,13,opt,name=forward_nonce,json=forwardNonce,proto3" json:"forward_nonce,omitempty"`
+	// Set on a block held on top of the session's first, from a
+	// subscriber-limits escalation.
+	ExtraBlock    bool `protobuf:"varint,14,opt,name=extra_block,json=extraBlock,proto3" json:"extra_block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CGNATMappingCheckpoint) Reset() {
@@ -1200,6 +1351,48 @@ func (x *CGNATMappingCheckpoint) GetInsideVrfId() uint32 {
 	return 0
 }
 
+func (x *CGNATMappingCheckpoint) GetForwardProtocol() string {
+	if x != nil {
+		return x.ForwardProtocol
+	}
+	return ""
+}
+
+func (x *CGNATMappingCheckpoint) GetForwardInsidePort() uint32 {
+	if x != nil {
+		return x.ForwardInsidePort
+	}
+	return 0
+}
+
+func (x *CGNATMappingCheckpoint) GetForwardSource() string {
+	if x != nil {
+		return x.ForwardSource
+	}
+	return ""
+}
+
+func (x *CGNATMappingCheckpoint) GetForwardExpiresUnix() int64 {
+	if x != nil {
+		return x.ForwardExpiresUnix
+	}
+	return 0
+}
+
+func (x *CGNATMappingCheckpoint) GetForwardNonce() []byte {
+	if x != nil {
+		return x.ForwardNonce
+	}
+	return nil
+}
+
+func (x *CGNATMappingCheckpoint) GetExtraBlock() bool {
+	if x != nil {
+		return x.ExtraBlock
+	}
+	return false
+}
+
 type SyncCGNATMappingRequest struct {
 	state         protoimpl.MessageState  `protogen:"open.v1"`
 	SrgName       string                  `protobuf:"bytes,1,opt,name=srg_name,json=srgName,proto3" json:"srg_name,omitempty"`
@@ -1432,147 +1625,1055 @@ func (x *BulkSyncCGNATResponse) GetLastPage() bool {
 	return false
 }
 
-var File_api_proto_ha_ha_proto protoreflect.FileDescriptor
+// IPAMChunkCheckpoint is an on-demand pool chunk one node obtained from
+// the IPAM. The peer adds the same chunk to its allocator so either node
+// can serve, and restore, sessions addressed from it.
+type IPAMChunkCheckpoint struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Family          string                 `protobuf:"bytes,1,opt,name=family,proto3" json:"family,omitempty"`
+	Profile         string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	Pool            string                 `protobuf:"bytes,3,opt,name=pool,proto3" json:"pool,omitempty"`
+	Prefix          string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Vrf             string                 `protobuf:"bytes,5,opt,name=vrf,proto3" json:"vrf,omitempty"`
+	Owner           string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	IpamId          string                 `protobuf:"bytes,7,opt,name=ipam_id,json=ipamId,proto3" json:"ipam_id,omitempty"`
+	AllocatedAtUnix int64                  `protobuf:"varint,8,opt,name=allocated_at_unix,json=allocatedAtUnix,proto3" json:"allocated_at_unix,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *IPAMChunkCheckpoint) Reset() {
+	*x = IPAMChunkCheckpoint{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-const file_api_proto_ha_ha_proto_rawDesc = "" +
-	"\n" +
-	"\x15api/proto/ha/ha.proto\x12\fosvbng.ha.v1\"\xc7\x01\n" +
-	"\x10HeartbeatMessage\x12\x17\n" +
-	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
-	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12!\n" +
-	"\ftimestamp_ns\x18\x03 \x01(\x03R\vtimestampNs\x12:\n" +
-	"\fsrg_statuses\x18\x04 \x03(\v2\x17.osvbng.ha.v1.SRGStatusR\vsrgStatuses\x12\x1f\n" +
-	"\vconfig_hash\x18\x05 \x01(\tR\n" +
-	"configHash\"\xa1\x01\n" +
-	"\tSRGStatus\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x14\n" +
-	"\x05state\x18\x02 \x01(\tR\x05state\x12#\n" +
-	"\rsession_count\x18\x03 \x01(\x04R\fsessionCount\x12\"\n" +
-	"\rlast_sync_seq\x18\x04 \x01(\x04R\vlastSyncSeq\x12\x1a\n" +
-	"\bpriority\x18\x05 \x01(\rR\bpriority\"\x90\x01\n" +
-	"\x14SRGStateNotification\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1b\n" +
-	"\tnew_state\x18\x02 \x01(\tR\bnewState\x12\x1d\n" +
-	"\n" +
-	"prev_state\x18\x03 \x01(\tR\tprevState\x12!\n" +
-	"\ftimestamp_ns\x18\x04 \x01(\x03R\vtimestampNs\"1\n" +
-	"\vSRGStateAck\x12\"\n" +
-	"\facknowledged\x18\x01 \x01(\bR\facknowledged\"L\n" +
-	"\x11SwitchoverRequest\x12\x1b\n" +
-	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\x12\x1a\n" +
-	"\bgraceful\x18\x02 \x01(\bR\bgraceful\"H\n" +
-	"\x12SwitchoverResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xb6\r\n" +
-	"\x11SessionCheckpoint\x12\x1d\n" +
-	"\n" +
-	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
-	"\bsrg_name\x18\x02 \x01(\tR\asrgName\x12\x1f\n" +
-	"\vaccess_type\x18\x03 \x01(\tR\n" +
-	"accessType\x12\x10\n" +
-	"\x03mac\x18\x04 \x01(\fR\x03mac\x12\x1d\n" +
-	"\n" +
-	"outer_vlan\x18\x05 \x01(\rR\touterVlan\x12\x1d\n" +
-	"\n" +
-	"inner_vlan\x18\x06 \x01(\rR\tinnerVlan\x12\x1a\n" +
-	"\busername\x18\a \x01(\tR\busername\x12$\n" +
-	"\x0eaaa_session_id\x18\b \x01(\tR\faaaSessionId\x12!\n" +
-	"\fipv4_address\x18\t \x01(\fR\vipv4Address\x12!\n" +
-	"\fipv4_netmask\x18\n" +
-	" \x01(\fR\vipv4Netmask\x12!\n" +
-	"\fipv4_gateway\x18\v \x01(\fR\vipv4Gateway\x12!\n" +
-	"\fipv6_address\x18\f \x01(\fR\vipv6Address\x12\x1f\n" +
-	"\vipv6_prefix\x18\r \x01(\fR\n" +
-	"ipv6Prefix\x12&\n" +
-	"\x0fipv6_prefix_len\x18\x0e \x01(\rR\ripv6PrefixLen\x12&\n" +
-	"\x0fipv4_lease_time\x18\x0f \x01(\rR\ripv4LeaseTime\x12&\n" +
-	"\x0fipv6_lease_time\x18\x10 \x01(\rR\ripv6LeaseTime\x12\x1e\n" +
-	"\vbound_at_ns\x18\x11 \x01(\x03R\tboundAtNs\x12'\n" +
-	"\x10ipv6_bound_at_ns\x18\x12 \x01(\x03R\ripv6BoundAtNs\x12\x1d\n" +
-	"\n" +
-	"circuit_id\x18\x13 \x01(\fR\tcircuitId\x12\x1b\n" +
-	"\tremote_id\x18\x14 \x01(\fR\bremoteId\x12\x1b\n" +
-	"\tclient_id\x18\x15 \x01(\fR\bclientId\x12\x1a\n" +
-	"\bhostname\x18\x16 \x01(\tR\bhostname\x12\x1f\n" +
-	"\vdhcpv6_duid\x18\x17 \x01(\fR\n" +
-	"dhcpv6Duid\x12\x10\n" +
-	"\x03vrf\x18\x18 \x01(\tR\x03vrf\x12#\n" +
-	"\rservice_group\x18\x19 \x01(\tR\fserviceGroup\x12)\n" +
-	"\x10subscriber_group\x18\x1a \x01(\tR\x0fsubscriberGroup\x12(\n" +
-	"\x10pppoe_session_id\x18\x1b \x01(\rR\x0epppoeSessionId\x12\x1b\n" +
-	"\tlcp_state\x18\x1c \x01(\tR\blcpState\x12\x1d\n" +
-	"\n" +
-	"ipcp_state\x18\x1d \x01(\tR\tipcpState\x12!\n" +
-	"\fipv6cp_state\x18\x1e \x01(\tR\vipv6cpState\x12!\n" +
-	"\fservice_name\x18\x1f \x01(\tR\vserviceName\x12\x1b\n" +
-	"\tipv4_pool\x18  \x01(\tR\bipv4Pool\x12\x1b\n" +
-	"\tiana_pool\x18! \x01(\tR\bianaPool\x12\x17\n" +
-	"\apd_pool\x18\" \x01(\tR\x06pdPool\x12\x1b\n" +
-	"\tlcp_magic\x18$ \x01(\rR\blcpMagic\x12Y\n" +
-	"\x0eaaa_attributes\x18% \x03(\v22.osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntryR\raaaAttributes\x12,\n" +
-	"\x12negotiated_ppp_mtu\x18& \x01(\rR\x10negotiatedPppMtu\x12\x19\n" +
-	"\bipv4_mss\x18' \x01(\rR\aipv4Mss\x12\x19\n" +
-	"\bipv6_mss\x18( \x01(\rR\aipv6Mss\x12)\n" +
-	"\x10access_interface\x18) \x01(\tR\x0faccessInterface\x12\x1f\n" +
-	"\vaccess_tpid\x18* \x01(\rR\n" +
-	"accessTpid\x12#\n" +
-	"\rhandoff_group\x18+ \x01(\tR\fhandoffGroup\x12+\n" +
-	"\x11handoff_interface\x18, \x01(\tR\x10handoffInterface\x12#\n" +
-	"\rhandoff_svlan\x18- \x01(\rR\fhandoffSvlan\x12#\n" +
-	"\rhandoff_cvlan\x18. \x01(\rR\fhandoffCvlan\x12!\n" +
-	"\fhandoff_tpid\x18/ \x01(\rR\vhandoffTpid\x12 \n" +
-	"\vtransparent\x180 \x01(\bR\vtransparent\x1a@\n" +
-	"\x12AaaAttributesEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b#\x10$\"\xb8\x01\n" +
-	"\x12SyncSessionRequest\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1a\n" +
-	"\bsequence\x18\x02 \x01(\x04R\bsequence\x120\n" +
-	"\x06action\x18\x03 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x129\n" +
-	"\asession\x18\x04 \x01(\v2\x1f.osvbng.ha.v1.SessionCheckpointR\asession\"S\n" +
-	"\x13SyncSessionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\"\n" +
-	"\rlast_sync_seq\x18\x02 \x01(\x04R\vlastSyncSeq\"S\n" +
-	"\x0fBulkSyncRequest\x12\x1b\n" +
-	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\x12#\n" +
-	"\rfrom_sequence\x18\x02 \x01(\x04R\ffromSequence\"\xa3\x01\n" +
-	"\x10BulkSyncResponse\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12;\n" +
-	"\bsessions\x18\x02 \x03(\v2\x1f.osvbng.ha.v1.SessionCheckpointR\bsessions\x12\x1a\n" +
-	"\bsequence\x18\x03 \x01(\x04R\bsequence\x12\x1b\n" +
-	"\tlast_page\x18\x04 \x01(\bR\blastPage\"\x9f\x02\n" +
-	"\x16CGNATMappingCheckpoint\x12\x1d\n" +
-	"\n" +
-	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
-	"\bsrg_name\x18\x02 \x01(\tR\asrgName\x12\x1b\n" +
-	"\tpool_name\x18\x03 \x01(\tR\bpoolName\x12\x1b\n" +
-	"\tinside_ip\x18\x04 \x01(\fR\binsideIp\x12\x1d\n" +
-	"\n" +
-	"outside_ip\x18\x05 \x01(\fR\toutsideIp\x12(\n" +
-	"\x10port_block_start\x18\x06 \x01(\rR\x0eportBlockStart\x12$\n" +
-	"\x0eport_block_end\x18\a \x01(\rR\fportBlockEnd\x12\"\n" +
-	"\rinside_vrf_id\x18\b \x01(\rR\vinsideVrfId\"\xc2\x01\n" +
-	"\x17SyncCGNATMappingRequest\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1a\n" +
-	"\bsequence\x18\x02 \x01(\x04R\bsequence\x120\n" +
-	"\x06action\x18\x03 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x12>\n" +
-	"\amapping\x18\x04 \x01(\v2$.osvbng.ha.v1.CGNATMappingCheckpointR\amapping\"X\n" +
-	"\x18SyncCGNATMappingResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\"\n" +
-	"\rlast_sync_seq\x18\x02 \x01(\x04R\vlastSyncSeq\"3\n" +
-	"\x14BulkSyncCGNATRequest\x12\x1b\n" +
-	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\"\xad\x01\n" +
-	"\x15BulkSyncCGNATResponse\x12\x19\n" +
-	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12@\n" +
-	"\bmappings\x18\x02 \x03(\v2$.osvbng.ha.v1.CGNATMappingCheckpointR\bmappings\x12\x1a\n" +
-	"\bsequence\x18\x03 \x01(\x04R\bsequence\x12\x1b\n" +
-	"\tlast_page\x18\x04 \x01(\bR\blastPage*q\n" +
+func (x *IPAMChunkCheckpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPAMChunkCheckpoint) ProtoMessage() {}
+
+func (x *IPAMChunkCheckpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPAMChunkCheckpoint.ProtoReflect.Descriptor instead.
+func (*IPAMChunkCheckpoint) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *IPAMChunkCheckpoint) GetFamily() string {
+	if x != nil {
+		return x.Family
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetVrf() string {
+	if x != nil {
+		return x.Vrf
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetIpamId() string {
+	if x != nil {
+		return x.IpamId
+	}
+	return ""
+}
+
+func (x *IPAMChunkCheckpoint) GetAllocatedAtUnix() int64 {
+	if x != nil {
+		return x.AllocatedAtUnix
+	}
+	return 0
+}
+
+type SyncIPAMChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Action        SyncAction             `protobuf:"varint,2,opt,name=action,proto3,enum=osvbng.ha.v1.SyncAction" json:"action,omitempty"`
+	Chunk         *IPAMChunkCheckpoint   `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncIPAMChunkRequest) Reset() {
+	*x = SyncIPAMChunkRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncIPAMChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncIPAMChunkRequest) ProtoMessage() {}
+
+func (x *SyncIPAMChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncIPAMChunkRequest.ProtoReflect.Descriptor instead.
+func (*SyncIPAMChunkRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SyncIPAMChunkRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *SyncIPAMChunkRequest) GetAction() SyncAction {
+	if x != nil {
+		return x.Action
+	}
+	return SyncAction_SYNC_ACTION_UNSPECIFIED
+}
+
+func (x *SyncIPAMChunkRequest) GetChunk() *IPAMChunkCheckpoint {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type SyncIPAMChunkResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Set on a delete the peer refused because it still has leases in the chunk.
+	InUse         bool `protobuf:"varint,2,opt,name=in_use,json=inUse,proto3" json:"in_use,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncIPAMChunkResponse) Reset() {
+	*x = SyncIPAMChunkResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncIPAMChunkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncIPAMChunkResponse) ProtoMessage() {}
+
+func (x *SyncIPAMChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncIPAMChunkResponse.ProtoReflect.Descriptor instead.
+func (*SyncIPAMChunkResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SyncIPAMChunkResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SyncIPAMChunkResponse) GetInUse() bool {
+	if x != nil {
+		return x.InUse
+	}
+	return false
+}
+
+type ListIPAMChunksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIPAMChunksRequest) Reset() {
+	*x = ListIPAMChunksRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIPAMChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIPAMChunksRequest) ProtoMessage() {}
+
+func (x *ListIPAMChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIPAMChunksRequest.ProtoReflect.Descriptor instead.
+func (*ListIPAMChunksRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{19}
+}
+
+type ListIPAMChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        []*IPAMChunkCheckpoint `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIPAMChunksResponse) Reset() {
+	*x = ListIPAMChunksResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIPAMChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIPAMChunksResponse) ProtoMessage() {}
+
+func (x *ListIPAMChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIPAMChunksResponse.ProtoReflect.Descriptor instead.
+func (*ListIPAMChunksResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListIPAMChunksResponse) GetChunks() []*IPAMChunkCheckpoint {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+// BlackholeCheckpoint is a remote-triggered blackhole requested on one
+// node. The peer installs and advertises it too, so the traffic stays
+// dropped across a switchover.
+type BlackholeCheckpoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Vrf           string                 `protobuf:"bytes,2,opt,name=vrf,proto3" json:"vrf,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	CreatedAtUnix int64                  `protobuf:"varint,7,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	ExpiresAtUnix int64                  `protobuf:"varint,8,opt,name=expires_at_unix,json=expiresAtUnix,proto3" json:"expires_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlackholeCheckpoint) Reset() {
+	*x = BlackholeCheckpoint{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlackholeCheckpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlackholeCheckpoint) ProtoMessage() {}
+
+func (x *BlackholeCheckpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlackholeCheckpoint.ProtoReflect.Descriptor instead.
+func (*BlackholeCheckpoint) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BlackholeCheckpoint) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetVrf() string {
+	if x != nil {
+		return x.Vrf
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *BlackholeCheckpoint) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+func (x *BlackholeCheckpoint) GetExpiresAtUnix() int64 {
+	if x != nil {
+		return x.ExpiresAtUnix
+	}
+	return 0
+}
+
+type SyncBlackholeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Action        SyncAction             `protobuf:"varint,2,opt,name=action,proto3,enum=osvbng.ha.v1.SyncAction" json:"action,omitempty"`
+	Blackhole     *BlackholeCheckpoint   `protobuf:"bytes,3,opt,name=blackhole,proto3" json:"blackhole,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBlackholeRequest) Reset() {
+	*x = SyncBlackholeRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBlackholeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBlackholeRequest) ProtoMessage() {}
+
+func (x *SyncBlackholeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBlackholeRequest.ProtoReflect.Descriptor instead.
+func (*SyncBlackholeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SyncBlackholeRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *SyncBlackholeRequest) GetAction() SyncAction {
+	if x != nil {
+		return x.Action
+	}
+	return SyncAction_SYNC_ACTION_UNSPECIFIED
+}
+
+func (x *SyncBlackholeRequest) GetBlackhole() *BlackholeCheckpoint {
+	if x != nil {
+		return x.Blackhole
+	}
+	return nil
+}
+
+type SyncBlackholeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBlackholeResponse) Reset() {
+	*x = SyncBlackholeResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBlackholeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBlackholeResponse) ProtoMessage() {}
+
+func (x *SyncBlackholeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBlackholeResponse.ProtoReflect.Descriptor instead.
+func (*SyncBlackholeResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SyncBlackholeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListBlackholesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlackholesRequest) Reset() {
+	*x = ListBlackholesRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlackholesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlackholesRequest) ProtoMessage() {}
+
+func (x *ListBlackholesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlackholesRequest.ProtoReflect.Descriptor instead.
+func (*ListBlackholesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{24}
+}
+
+type ListBlackholesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blackholes    []*BlackholeCheckpoint `protobuf:"bytes,1,rep,name=blackholes,proto3" json:"blackholes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlackholesResponse) Reset() {
+	*x = ListBlackholesResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlackholesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlackholesResponse) ProtoMessage() {}
+
+func (x *ListBlackholesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlackholesResponse.ProtoReflect.Descriptor instead.
+func (*ListBlackholesResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListBlackholesResponse) GetBlackholes() []*BlackholeCheckpoint {
+	if x != nil {
+		return x.Blackholes
+	}
+	return nil
+}
+
+// ACLIndex is the dataplane index of a configured access list. The peers
+// exchange them so each list has the same index on both.
+type ACLIndex struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Index         uint32                 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ACLIndex) Reset() {
+	*x = ACLIndex{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACLIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLIndex) ProtoMessage() {}
+
+func (x *ACLIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLIndex.ProtoReflect.Descriptor instead.
+func (*ACLIndex) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ACLIndex) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ACLIndex) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type SyncACLIndexesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Indexes       []*ACLIndex            `protobuf:"bytes,3,rep,name=indexes,proto3" json:"indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncACLIndexesRequest) Reset() {
+	*x = SyncACLIndexesRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncACLIndexesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncACLIndexesRequest) ProtoMessage() {}
+
+func (x *SyncACLIndexesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncACLIndexesRequest.ProtoReflect.Descriptor instead.
+func (*SyncACLIndexesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SyncACLIndexesRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *SyncACLIndexesRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *SyncACLIndexesRequest) GetIndexes() []*ACLIndex {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+type SyncACLIndexesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncACLIndexesResponse) Reset() {
+	*x = SyncACLIndexesResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncACLIndexesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncACLIndexesResponse) ProtoMessage() {}
+
+func (x *SyncACLIndexesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncACLIndexesResponse.ProtoReflect.Descriptor instead.
+func (*SyncACLIndexesResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SyncACLIndexesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListACLIndexesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLIndexesRequest) Reset() {
+	*x = ListACLIndexesRequest{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLIndexesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLIndexesRequest) ProtoMessage() {}
+
+func (x *ListACLIndexesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLIndexesRequest.ProtoReflect.Descriptor instead.
+func (*ListACLIndexesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{29}
+}
+
+type ListACLIndexesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Indexes       []*ACLIndex            `protobuf:"bytes,2,rep,name=indexes,proto3" json:"indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListACLIndexesResponse) Reset() {
+	*x = ListACLIndexesResponse{}
+	mi := &file_api_proto_ha_ha_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListACLIndexesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListACLIndexesResponse) ProtoMessage() {}
+
+func (x *ListACLIndexesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_ha_ha_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListACLIndexesResponse.ProtoReflect.Descriptor instead.
+func (*ListACLIndexesResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_ha_ha_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListACLIndexesResponse) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ListACLIndexesResponse) GetIndexes() []*ACLIndex {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+var File_api_proto_ha_ha_proto protoreflect.FileDescriptor
+
+const file_api_proto_ha_ha_proto_rawDesc = "" +
+	"\n" +
+	"\x15api/proto/ha/ha.proto\x12\fosvbng.ha.v1\"\xc7\x01\n" +
+	"\x10HeartbeatMessage\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12!\n" +
+	"\ftimestamp_ns\x18\x03 \x01(\x03R\vtimestampNs\x12:\n" +
+	"\fsrg_statuses\x18\x04 \x03(\v2\x17.osvbng.ha.v1.SRGStatusR\vsrgStatuses\x12\x1f\n" +
+	"\vconfig_hash\x18\x05 \x01(\tR\n" +
+	"configHash\"\xa1\x01\n" +
+	"\tSRGStatus\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12#\n" +
+	"\rsession_count\x18\x03 \x01(\x04R\fsessionCount\x12\"\n" +
+	"\rlast_sync_seq\x18\x04 \x01(\x04R\vlastSyncSeq\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\rR\bpriority\"\x90\x01\n" +
+	"\x14SRGStateNotification\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1b\n" +
+	"\tnew_state\x18\x02 \x01(\tR\bnewState\x12\x1d\n" +
+	"\n" +
+	"prev_state\x18\x03 \x01(\tR\tprevState\x12!\n" +
+	"\ftimestamp_ns\x18\x04 \x01(\x03R\vtimestampNs\"1\n" +
+	"\vSRGStateAck\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\"L\n" +
+	"\x11SwitchoverRequest\x12\x1b\n" +
+	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\x12\x1a\n" +
+	"\bgraceful\x18\x02 \x01(\bR\bgraceful\"H\n" +
+	"\x12SwitchoverResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xf7\x12\n" +
+	"\x11SessionCheckpoint\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bsrg_name\x18\x02 \x01(\tR\asrgName\x12\x1f\n" +
+	"\vaccess_type\x18\x03 \x01(\tR\n" +
+	"accessType\x12\x10\n" +
+	"\x03mac\x18\x04 \x01(\fR\x03mac\x12\x1d\n" +
+	"\n" +
+	"outer_vlan\x18\x05 \x01(\rR\touterVlan\x12\x1d\n" +
+	"\n" +
+	"inner_vlan\x18\x06 \x01(\rR\tinnerVlan\x12\x1a\n" +
+	"\busername\x18\a \x01(\tR\busername\x12$\n" +
+	"\x0eaaa_session_id\x18\b \x01(\tR\faaaSessionId\x12!\n" +
+	"\fipv4_address\x18\t \x01(\fR\vipv4Address\x12!\n" +
+	"\fipv4_netmask\x18\n" +
+	" \x01(\fR\vipv4Netmask\x12!\n" +
+	"\fipv4_gateway\x18\v \x01(\fR\vipv4Gateway\x12!\n" +
+	"\fipv6_address\x18\f \x01(\fR\vipv6Address\x12\x1f\n" +
+	"\vipv6_prefix\x18\r \x01(\fR\n" +
+	"ipv6Prefix\x12&\n" +
+	"\x0fipv6_prefix_len\x18\x0e \x01(\rR\ripv6PrefixLen\x12&\n" +
+	"\x0fipv4_lease_time\x18\x0f \x01(\rR\ripv4LeaseTime\x12&\n" +
+	"\x0fipv6_lease_time\x18\x10 \x01(\rR\ripv6LeaseTime\x12\x1e\n" +
+	"\vbound_at_ns\x18\x11 \x01(\x03R\tboundAtNs\x12'\n" +
+	"\x10ipv6_bound_at_ns\x18\x12 \x01(\x03R\ripv6BoundAtNs\x12\x1d\n" +
+	"\n" +
+	"circuit_id\x18\x13 \x01(\fR\tcircuitId\x12\x1b\n" +
+	"\tremote_id\x18\x14 \x01(\fR\bremoteId\x12\x1b\n" +
+	"\tclient_id\x18\x15 \x01(\fR\bclientId\x12\x1a\n" +
+	"\bhostname\x18\x16 \x01(\tR\bhostname\x12\x1f\n" +
+	"\vdhcpv6_duid\x18\x17 \x01(\fR\n" +
+	"dhcpv6Duid\x12\x10\n" +
+	"\x03vrf\x18\x18 \x01(\tR\x03vrf\x12#\n" +
+	"\rservice_group\x18\x19 \x01(\tR\fserviceGroup\x12)\n" +
+	"\x10subscriber_group\x18\x1a \x01(\tR\x0fsubscriberGroup\x12(\n" +
+	"\x10pppoe_session_id\x18\x1b \x01(\rR\x0epppoeSessionId\x12\x1b\n" +
+	"\tlcp_state\x18\x1c \x01(\tR\blcpState\x12\x1d\n" +
+	"\n" +
+	"ipcp_state\x18\x1d \x01(\tR\tipcpState\x12!\n" +
+	"\fipv6cp_state\x18\x1e \x01(\tR\vipv6cpState\x12!\n" +
+	"\fservice_name\x18\x1f \x01(\tR\vserviceName\x12\x1b\n" +
+	"\tipv4_pool\x18  \x01(\tR\bipv4Pool\x12\x1b\n" +
+	"\tiana_pool\x18! \x01(\tR\bianaPool\x12\x17\n" +
+	"\apd_pool\x18\" \x01(\tR\x06pdPool\x12\x1b\n" +
+	"\tlcp_magic\x18$ \x01(\rR\blcpMagic\x12Y\n" +
+	"\x0eaaa_attributes\x18% \x03(\v22.osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntryR\raaaAttributes\x12,\n" +
+	"\x12negotiated_ppp_mtu\x18& \x01(\rR\x10negotiatedPppMtu\x12\x19\n" +
+	"\bipv4_mss\x18' \x01(\rR\aipv4Mss\x12\x19\n" +
+	"\bipv6_mss\x18( \x01(\rR\aipv6Mss\x12)\n" +
+	"\x10access_interface\x18) \x01(\tR\x0faccessInterface\x12\x1f\n" +
+	"\vaccess_tpid\x18* \x01(\rR\n" +
+	"accessTpid\x12#\n" +
+	"\rhandoff_group\x18+ \x01(\tR\fhandoffGroup\x12+\n" +
+	"\x11handoff_interface\x18, \x01(\tR\x10handoffInterface\x12#\n" +
+	"\rhandoff_svlan\x18- \x01(\rR\fhandoffSvlan\x12#\n" +
+	"\rhandoff_cvlan\x18. \x01(\rR\fhandoffCvlan\x12!\n" +
+	"\fhandoff_tpid\x18/ \x01(\rR\vhandoffTpid\x12 \n" +
+	"\vtransparent\x180 \x01(\bR\vtransparent\x12\"\n" +
+	"\rl2tp_local_ip\x181 \x01(\fR\vl2tpLocalIp\x12 \n" +
+	"\fl2tp_peer_ip\x182 \x01(\fR\n" +
+	"l2tpPeerIp\x12/\n" +
+	"\x14l2tp_local_tunnel_id\x183 \x01(\rR\x11l2tpLocalTunnelId\x12-\n" +
+	"\x13l2tp_peer_tunnel_id\x184 \x01(\rR\x10l2tpPeerTunnelId\x121\n" +
+	"\x15l2tp_local_session_id\x185 \x01(\rR\x12l2tpLocalSessionId\x12/\n" +
+	"\x14l2tp_peer_session_id\x186 \x01(\rR\x11l2tpPeerSessionId\x12&\n" +
+	"\x0fl2tp_local_port\x187 \x01(\rR\rl2tpLocalPort\x12$\n" +
+	"\x0el2tp_peer_port\x188 \x01(\rR\fl2tpPeerPort\x12\x1b\n" +
+	"\tl2tp_role\x189 \x01(\tR\bl2tpRole\x12.\n" +
+	"\x13l2tp_local_hostname\x18: \x01(\tR\x11l2tpLocalHostname\x12,\n" +
+	"\x12l2tp_peer_hostname\x18; \x01(\tR\x10l2tpPeerHostname\x12\x17\n" +
+	"\al2tp_ns\x18< \x01(\rR\x06l2tpNs\x12\x17\n" +
+	"\al2tp_nr\x18= \x01(\rR\x06l2tpNr\x12.\n" +
+	"\x13l2tp_hello_interval\x18> \x01(\rR\x11l2tpHelloInterval\x12)\n" +
+	"\x11l2tp_ppp_hdr_skip\x18? \x01(\rR\x0el2tpPppHdrSkip\x12#\n" +
+	"\rl2tp_failover\x18@ \x01(\bR\fl2tpFailover\x12:\n" +
+	"\x1al2tp_peer_recovery_time_ms\x18A \x01(\rR\x16l2tpPeerRecoveryTimeMs\x1a@\n" +
+	"\x12AaaAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b#\x10$\"\xb8\x01\n" +
+	"\x12SyncSessionRequest\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x120\n" +
+	"\x06action\x18\x03 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x129\n" +
+	"\asession\x18\x04 \x01(\v2\x1f.osvbng.ha.v1.SessionCheckpointR\asession\"S\n" +
+	"\x13SyncSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\"\n" +
+	"\rlast_sync_seq\x18\x02 \x01(\x04R\vlastSyncSeq\"S\n" +
+	"\x0fBulkSyncRequest\x12\x1b\n" +
+	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\x12#\n" +
+	"\rfrom_sequence\x18\x02 \x01(\x04R\ffromSequence\"\xa3\x01\n" +
+	"\x10BulkSyncResponse\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12;\n" +
+	"\bsessions\x18\x02 \x03(\v2\x1f.osvbng.ha.v1.SessionCheckpointR\bsessions\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x04R\bsequence\x12\x1b\n" +
+	"\tlast_page\x18\x04 \x01(\bR\blastPage\"\x99\x04\n" +
+	"\x16CGNATMappingCheckpoint\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bsrg_name\x18\x02 \x01(\tR\asrgName\x12\x1b\n" +
+	"\tpool_name\x18\x03 \x01(\tR\bpoolName\x12\x1b\n" +
+	"\tinside_ip\x18\x04 \x01(\fR\binsideIp\x12\x1d\n" +
+	"\n" +
+	"outside_ip\x18\x05 \x01(\fR\toutsideIp\x12(\n" +
+	"\x10port_block_start\x18\x06 \x01(\rR\x0eportBlockStart\x12$\n" +
+	"\x0eport_block_end\x18\a \x01(\rR\fportBlockEnd\x12\"\n" +
+	"\rinside_vrf_id\x18\b \x01(\rR\vinsideVrfId\x12)\n" +
+	"\x10forward_protocol\x18\t \x01(\tR\x0fforwardProtocol\x12.\n" +
+	"\x13forward_inside_port\x18\n" +
+	" \x01(\rR\x11forwardInsidePort\x12%\n" +
+	"\x0eforward_source\x18\v \x01(\tR\rforwardSource\x120\n" +
+	"\x14forward_expires_unix\x18\f \x01(\x03R\x12forwardExpiresUnix\x12#\n" +
+	"\rforward_nonce\x18\r \x01(\fR\fforwardNonce\x12\x1f\n" +
+	"\vextra_block\x18\x0e \x01(\bR\n" +
+	"extraBlock\"\xc2\x01\n" +
+	"\x17SyncCGNATMappingRequest\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x120\n" +
+	"\x06action\x18\x03 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x12>\n" +
+	"\amapping\x18\x04 \x01(\v2$.osvbng.ha.v1.CGNATMappingCheckpointR\amapping\"X\n" +
+	"\x18SyncCGNATMappingResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\"\n" +
+	"\rlast_sync_seq\x18\x02 \x01(\x04R\vlastSyncSeq\"3\n" +
+	"\x14BulkSyncCGNATRequest\x12\x1b\n" +
+	"\tsrg_names\x18\x01 \x03(\tR\bsrgNames\"\xad\x01\n" +
+	"\x15BulkSyncCGNATResponse\x12\x19\n" +
+	"\bsrg_name\x18\x01 \x01(\tR\asrgName\x12@\n" +
+	"\bmappings\x18\x02 \x03(\v2$.osvbng.ha.v1.CGNATMappingCheckpointR\bmappings\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x04R\bsequence\x12\x1b\n" +
+	"\tlast_page\x18\x04 \x01(\bR\blastPage\"\xe0\x01\n" +
+	"\x13IPAMChunkCheckpoint\x12\x16\n" +
+	"\x06family\x18\x01 \x01(\tR\x06family\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\x12\x12\n" +
+	"\x04pool\x18\x03 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x10\n" +
+	"\x03vrf\x18\x05 \x01(\tR\x03vrf\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x17\n" +
+	"\aipam_id\x18\a \x01(\tR\x06ipamId\x12*\n" +
+	"\x11allocated_at_unix\x18\b \x01(\x03R\x0fallocatedAtUnix\"\x9d\x01\n" +
+	"\x14SyncIPAMChunkRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x120\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x127\n" +
+	"\x05chunk\x18\x03 \x01(\v2!.osvbng.ha.v1.IPAMChunkCheckpointR\x05chunk\"H\n" +
+	"\x15SyncIPAMChunkResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x15\n" +
+	"\x06in_use\x18\x02 \x01(\bR\x05inUse\"\x17\n" +
+	"\x15ListIPAMChunksRequest\"S\n" +
+	"\x16ListIPAMChunksResponse\x129\n" +
+	"\x06chunks\x18\x01 \x03(\v2!.osvbng.ha.v1.IPAMChunkCheckpointR\x06chunks\"\xf4\x01\n" +
+	"\x13BlackholeCheckpoint\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x10\n" +
+	"\x03vrf\x18\x02 \x01(\tR\x03vrf\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12&\n" +
+	"\x0fcreated_at_unix\x18\a \x01(\x03R\rcreatedAtUnix\x12&\n" +
+	"\x0fexpires_at_unix\x18\b \x01(\x03R\rexpiresAtUnix\"\xa5\x01\n" +
+	"\x14SyncBlackholeRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x120\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x18.osvbng.ha.v1.SyncActionR\x06action\x12?\n" +
+	"\tblackhole\x18\x03 \x01(\v2!.osvbng.ha.v1.BlackholeCheckpointR\tblackhole\"1\n" +
+	"\x15SyncBlackholeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x17\n" +
+	"\x15ListBlackholesRequest\"[\n" +
+	"\x16ListBlackholesResponse\x12A\n" +
+	"\n" +
+	"blackholes\x18\x01 \x03(\v2!.osvbng.ha.v1.BlackholeCheckpointR\n" +
+	"blackholes\"4\n" +
+	"\bACLIndex\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\rR\x05index\"~\n" +
+	"\x15SyncACLIndexesRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x120\n" +
+	"\aindexes\x18\x03 \x03(\v2\x16.osvbng.ha.v1.ACLIndexR\aindexes\"2\n" +
+	"\x16SyncACLIndexesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x17\n" +
+	"\x15ListACLIndexesRequest\"c\n" +
+	"\x16ListACLIndexesResponse\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x120\n" +
+	"\aindexes\x18\x02 \x03(\v2\x16.osvbng.ha.v1.ACLIndexR\aindexes*q\n" +
 	"\n" +
 	"SyncAction\x12\x1b\n" +
 	"\x17SYNC_ACTION_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12SYNC_ACTION_CREATE\x10\x01\x12\x16\n" +
 	"\x12SYNC_ACTION_UPDATE\x10\x02\x12\x16\n" +
-	"\x12SYNC_ACTION_DELETE\x10\x032\xe9\x04\n" +
+	"\x12SYNC_ACTION_DELETE\x10\x032\x91\t\n" +
 	"\rHAPeerService\x12O\n" +
 	"\tHeartbeat\x12\x1e.osvbng.ha.v1.HeartbeatMessage\x1a\x1e.osvbng.ha.v1.HeartbeatMessage(\x010\x01\x12O\n" +
 	"\x0eNotifySRGState\x12\".osvbng.ha.v1.SRGStateNotification\x1a\x19.osvbng.ha.v1.SRGStateAck\x12V\n" +
@@ -1580,7 +2681,13 @@ const file_api_proto_ha_ha_proto_rawDesc = "" +
 	"\vSyncSession\x12 .osvbng.ha.v1.SyncSessionRequest\x1a!.osvbng.ha.v1.SyncSessionResponse\x12K\n" +
 	"\bBulkSync\x12\x1d.osvbng.ha.v1.BulkSyncRequest\x1a\x1e.osvbng.ha.v1.BulkSyncResponse0\x01\x12a\n" +
 	"\x10SyncCGNATMapping\x12%.osvbng.ha.v1.SyncCGNATMappingRequest\x1a&.osvbng.ha.v1.SyncCGNATMappingResponse\x12Z\n" +
-	"\rBulkSyncCGNAT\x12\".osvbng.ha.v1.BulkSyncCGNATRequest\x1a#.osvbng.ha.v1.BulkSyncCGNATResponse0\x01B5Z3github.com/veesix-networks/osvbng/api/proto/ha;hapbb\x06proto3"
+	"\rBulkSyncCGNAT\x12\".osvbng.ha.v1.BulkSyncCGNATRequest\x1a#.osvbng.ha.v1.BulkSyncCGNATResponse0\x01\x12X\n" +
+	"\rSyncIPAMChunk\x12\".osvbng.ha.v1.SyncIPAMChunkRequest\x1a#.osvbng.ha.v1.SyncIPAMChunkResponse\x12[\n" +
+	"\x0eListIPAMChunks\x12#.osvbng.ha.v1.ListIPAMChunksRequest\x1a$.osvbng.ha.v1.ListIPAMChunksResponse\x12X\n" +
+	"\rSyncBlackhole\x12\".osvbng.ha.v1.SyncBlackholeRequest\x1a#.osvbng.ha.v1.SyncBlackholeResponse\x12[\n" +
+	"\x0eListBlackholes\x12#.osvbng.ha.v1.ListBlackholesRequest\x1a$.osvbng.ha.v1.ListBlackholesResponse\x12[\n" +
+	"\x0eSyncACLIndexes\x12#.osvbng.ha.v1.SyncACLIndexesRequest\x1a$.osvbng.ha.v1.SyncACLIndexesResponse\x12[\n" +
+	"\x0eListACLIndexes\x12#.osvbng.ha.v1.ListACLIndexesRequest\x1a$.osvbng.ha.v1.ListACLIndexesResponseB5Z3github.com/veesix-networks/osvbng/api/proto/ha;hapbb\x06proto3"
 
 var (
 	file_api_proto_ha_ha_proto_rawDescOnce sync.Once
@@ -1595,7 +2702,7 @@ func file_api_proto_ha_ha_proto_rawDescGZIP() []byte {
 }
 
 var file_api_proto_ha_ha_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_proto_ha_ha_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_proto_ha_ha_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_api_proto_ha_ha_proto_goTypes = []any{
 	(SyncAction)(0),                  // 0: osvbng.ha.v1.SyncAction
 	(*HeartbeatMessage)(nil),         // 1: osvbng.ha.v1.HeartbeatMessage
@@ -1614,36 +2721,71 @@ var file_api_proto_ha_ha_proto_goTypes = []any{
 	(*SyncCGNATMappingResponse)(nil), // 14: osvbng.ha.v1.SyncCGNATMappingResponse
 	(*BulkSyncCGNATRequest)(nil),     // 15: osvbng.ha.v1.BulkSyncCGNATRequest
 	(*BulkSyncCGNATResponse)(nil),    // 16: osvbng.ha.v1.BulkSyncCGNATResponse
-	nil,                              // 17: osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntry
+	(*IPAMChunkCheckpoint)(nil),      // 17: osvbng.ha.v1.IPAMChunkCheckpoint
+	(*SyncIPAMChunkRequest)(nil),     // 18: osvbng.ha.v1.SyncIPAMChunkRequest
+	(*SyncIPAMChunkResponse)(nil),    // 19: osvbng.ha.v1.SyncIPAMChunkResponse
+	(*ListIPAMChunksRequest)(nil),    // 20: osvbng.ha.v1.ListIPAMChunksRequest
+	(*ListIPAMChunksResponse)(nil),   // 21: osvbng.ha.v1.ListIPAMChunksResponse
+	(*BlackholeCheckpoint)(nil),      // 22: osvbng.ha.v1.BlackholeCheckpoint
+	(*SyncBlackholeRequest)(nil),     // 23: osvbng.ha.v1.SyncBlackholeRequest
+	(*SyncBlackholeResponse)(nil),    // 24: osvbng.ha.v1.SyncBlackholeResponse
+	(*ListBlackholesRequest)(nil),    // 25: osvbng.ha.v1.ListBlackholesRequest
+	(*ListBlackholesResponse)(nil),   // 26: osvbng.ha.v1.ListBlackholesResponse
+	(*ACLIndex)(nil),                 // 27: osvbng.ha.v1.ACLIndex
+	(*SyncACLIndexesRequest)(nil),    // 28: osvbng.ha.v1.SyncACLIndexesRequest
+	(*SyncACLIndexesResponse)(nil),   // 29: osvbng.ha.v1.SyncACLIndexesResponse
+	(*ListACLIndexesRequest)(nil),    // 30: osvbng.ha.v1.ListACLIndexesRequest
+	(*ListACLIndexesResponse)(nil),   // 31: osvbng.ha.v1.ListACLIndexesResponse
+	nil,                              // 32: osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntry
 }
 var file_api_proto_ha_ha_proto_depIdxs = []int32{
 	2,  // 0: osvbng.ha.v1.HeartbeatMessage.srg_statuses:type_name -> osvbng.ha.v1.SRGStatus
-	17, // 1: osvbng.ha.v1.SessionCheckpoint.aaa_attributes:type_name -> osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntry
+	32, // 1: osvbng.ha.v1.SessionCheckpoint.aaa_attributes:type_name -> osvbng.ha.v1.SessionCheckpoint.AaaAttributesEntry
 	0,  // 2: osvbng.ha.v1.SyncSessionRequest.action:type_name -> osvbng.ha.v1.SyncAction
 	7,  // 3: osvbng.ha.v1.SyncSessionRequest.session:type_name -> osvbng.ha.v1.SessionCheckpoint
 	7,  // 4: osvbng.ha.v1.BulkSyncResponse.sessions:type_name -> osvbng.ha.v1.SessionCheckpoint
 	0,  // 5: osvbng.ha.v1.SyncCGNATMappingRequest.action:type_name -> osvbng.ha.v1.SyncAction
 	12, // 6: osvbng.ha.v1.SyncCGNATMappingRequest.mapping:type_name -> osvbng.ha.v1.CGNATMappingCheckpoint
 	12, // 7: osvbng.ha.v1.BulkSyncCGNATResponse.mappings:type_name -> osvbng.ha.v1.CGNATMappingCheckpoint
-	1,  // 8: osvbng.ha.v1.HAPeerService.Heartbeat:input_type -> osvbng.ha.v1.HeartbeatMessage
-	3,  // 9: osvbng.ha.v1.HAPeerService.NotifySRGState:input_type -> osvbng.ha.v1.SRGStateNotification
-	5,  // 10: osvbng.ha.v1.HAPeerService.RequestSwitchover:input_type -> osvbng.ha.v1.SwitchoverRequest
-	8,  // 11: osvbng.ha.v1.HAPeerService.SyncSession:input_type -> osvbng.ha.v1.SyncSessionRequest
-	10, // 12: osvbng.ha.v1.HAPeerService.BulkSync:input_type -> osvbng.ha.v1.BulkSyncRequest
-	13, // 13: osvbng.ha.v1.HAPeerService.SyncCGNATMapping:input_type -> osvbng.ha.v1.SyncCGNATMappingRequest
-	15, // 14: osvbng.ha.v1.HAPeerService.BulkSyncCGNAT:input_type -> osvbng.ha.v1.BulkSyncCGNATRequest
-	1,  // 15: osvbng.ha.v1.HAPeerService.Heartbeat:output_type -> osvbng.ha.v1.HeartbeatMessage
-	4,  // 16: osvbng.ha.v1.HAPeerService.NotifySRGState:output_type -> osvbng.ha.v1.SRGStateAck
-	6,  // 17: osvbng.ha.v1.HAPeerService.RequestSwitchover:output_type -> osvbng.ha.v1.SwitchoverResponse
-	9,  // 18: osvbng.ha.v1.HAPeerService.SyncSession:output_type -> osvbng.ha.v1.SyncSessionResponse
-	11, // 19: osvbng.ha.v1.HAPeerService.BulkSync:output_type -> osvbng.ha.v1.BulkSyncResponse
-	14, // 20: osvbng.ha.v1.HAPeerService.SyncCGNATMapping:output_type -> osvbng.ha.v1.SyncCGNATMappingResponse
-	16, // 21: osvbng.ha.v1.HAPeerService.BulkSyncCGNAT:output_type -> osvbng.ha.v1.BulkSyncCGNATResponse
-	15, // [15:22] is the sub-list for method output_type
-	8,  // [8:15] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	0,  // 8: osvbng.ha.v1.SyncIPAMChunkRequest.action:type_name -> osvbng.ha.v1.SyncAction
+	17, // 9: osvbng.ha.v1.SyncIPAMChunkRequest.chunk:type_name -> osvbng.ha.v1.IPAMChunkCheckpoint
+	17, // 10: osvbng.ha.v1.ListIPAMChunksResponse.chunks:type_name -> osvbng.ha.v1.IPAMChunkCheckpoint
+	0,  // 11: osvbng.ha.v1.SyncBlackholeRequest.action:type_name -> osvbng.ha.v1.SyncAction
+	22, // 12: osvbng.ha.v1.SyncBlackholeRequest.blackhole:type_name -> osvbng.ha.v1.BlackholeCheckpoint
+	22, // 13: osvbng.ha.v1.ListBlackholesResponse.blackholes:type_name -> osvbng.ha.v1.BlackholeCheckpoint
+	27, // 14: osvbng.ha.v1.SyncACLIndexesRequest.indexes:type_name -> osvbng.ha.v1.ACLIndex
+	27, // 15: osvbng.ha.v1.ListACLIndexesResponse.indexes:type_name -> osvbng.ha.v1.ACLIndex
+	1,  // 16: osvbng.ha.v1.HAPeerService.Heartbeat:input_type -> osvbng.ha.v1.HeartbeatMessage
+	3,  // 17: osvbng.ha.v1.HAPeerService.NotifySRGState:input_type -> osvbng.ha.v1.SRGStateNotification
+	5,  // 18: osvbng.ha.v1.HAPeerService.RequestSwitchover:input_type -> osvbng.ha.v1.SwitchoverRequest
+	8,  // 19: osvbng.ha.v1.HAPeerService.SyncSession:input_type -> osvbng.ha.v1.SyncSessionRequest
+	10, // 20: osvbng.ha.v1.HAPeerService.BulkSync:input_type -> osvbng.ha.v1.BulkSyncRequest
+	13, // 21: osvbng.ha.v1.HAPeerService.SyncCGNATMapping:input_type -> osvbng.ha.v1.SyncCGNATMappingRequest
+	15, // 22: osvbng.ha.v1.HAPeerService.BulkSyncCGNAT:input_type -> osvbng.ha.v1.BulkSyncCGNATRequest
+	18, // 23: osvbng.ha.v1.HAPeerService.SyncIPAMChunk:input_type -> osvbng.ha.v1.SyncIPAMChunkRequest
+	20, // 24: osvbng.ha.v1.HAPeerService.ListIPAMChunks:input_type -> osvbng.ha.v1.ListIPAMChunksRequest
+	23, // 25: osvbng.ha.v1.HAPeerService.SyncBlackhole:input_type -> osvbng.ha.v1.SyncBlackholeRequest
+	25, // 26: osvbng.ha.v1.HAPeerService.ListBlackholes:input_type -> osvbng.ha.v1.ListBlackholesRequest
+	28, // 27: osvbng.ha.v1.HAPeerService.SyncACLIndexes:input_type -> osvbng.ha.v1.SyncACLIndexesRequest
+	30, // 28: osvbng.ha.v1.HAPeerService.ListACLIndexes:input_type -> osvbng.ha.v1.ListACLIndexesRequest
+	1,  // 29: osvbng.ha.v1.HAPeerService.Heartbeat:output_type -> osvbng.ha.v1.HeartbeatMessage
+	4,  // 30: osvbng.ha.v1.HAPeerService.NotifySRGState:output_type -> osvbng.ha.v1.SRGStateAck
+	6,  // 31: osvbng.ha.v1.HAPeerService.RequestSwitchover:output_type -> osvbng.ha.v1.SwitchoverResponse
+	9,  // 32: osvbng.ha.v1.HAPeerService.SyncSession:output_type -> osvbng.ha.v1.SyncSessionResponse
+	11, // 33: osvbng.ha.v1.HAPeerService.BulkSync:output_type -> osvbng.ha.v1.BulkSyncResponse
+	14, // 34: osvbng.ha.v1.HAPeerService.SyncCGNATMapping:output_type -> osvbng.ha.v1.SyncCGNATMappingResponse
+	16, // 35: osvbng.ha.v1.HAPeerService.BulkSyncCGNAT:output_type -> osvbng.ha.v1.BulkSyncCGNATResponse
+	19, // 36: osvbng.ha.v1.HAPeerService.SyncIPAMChunk:output_type -> osvbng.ha.v1.SyncIPAMChunkResponse
+	21, // 37: osvbng.ha.v1.HAPeerService.ListIPAMChunks:output_type -> osvbng.ha.v1.ListIPAMChunksResponse
+	24, // 38: osvbng.ha.v1.HAPeerService.SyncBlackhole:output_type -> osvbng.ha.v1.SyncBlackholeResponse
+	26, // 39: osvbng.ha.v1.HAPeerService.ListBlackholes:output_type -> osvbng.ha.v1.ListBlackholesResponse
+	29, // 40: osvbng.ha.v1.HAPeerService.SyncACLIndexes:output_type -> osvbng.ha.v1.SyncACLIndexesResponse
+	31, // 41: osvbng.ha.v1.HAPeerService.ListACLIndexes:output_type -> osvbng.ha.v1.ListACLIndexesResponse
+	29, // [29:42] is the sub-list for method output_type
+	16, // [16:29] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_ha_ha_proto_init() }
@@ -1657,7 +2799,7 @@ func file_api_proto_ha_ha_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_ha_ha_proto_rawDesc), len(file_api_proto_ha_ha_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   17,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
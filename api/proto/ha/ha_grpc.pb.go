@@ -30,6 +30,12 @@ const (
 	HAPeerService_BulkSync_FullMethodName          = "/osvbng.ha.v1.HAPeerService/BulkSync"
 	HAPeerService_SyncCGNATMapping_FullMethodName  = "/osvbng.ha.v1.HAPeerService/SyncCGNATMapping"
 	HAPeerService_BulkSyncCGNAT_FullMethodName     = "/osvbng.ha.v1.HAPeerService/BulkSyncCGNAT"
+	HAPeerService_SyncIPAMChunk_FullMethodName     = "/osvbng.ha.v1.HAPeerService/SyncIPAMChunk"
+	HAPeerService_ListIPAMChunks_FullMethodName    = "/osvbng.ha.v1.HAPeerService/ListIPAMChunks"
+	HAPeerService_SyncBlackhole_FullMethodName     = "/osvbng.ha.v1.HAPeerService/SyncBlackhole"
+	HAPeerService_ListBlackholes_FullMethodName    = "/osvbng.ha.v1.HAPeerService/ListBlackholes"
+	HAPeerService_SyncACLIndexes_FullMethodName    = "/osvbng.ha.v1.HAPeerService/SyncACLIndexes"
+	HAPeerService_ListACLIndexes_FullMethodName    = "/osvbng.ha.v1.HAPeerService/ListACLIndexes"
 )
 
 // HAPeerServiceClient is the client API for HAPeerService service.
@@ -43,6 +49,12 @@ type HAPeerServiceClient interface {
 	BulkSync(ctx context.Context, in *BulkSyncRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkSyncResponse], error)
 	SyncCGNATMapping(ctx context.Context, in *SyncCGNATMappingRequest, opts ...grpc.CallOption) (*SyncCGNATMappingResponse, error)
 	BulkSyncCGNAT(ctx context.Context, in *BulkSyncCGNATRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkSyncCGNATResponse], error)
+	SyncIPAMChunk(ctx context.Context, in *SyncIPAMChunkRequest, opts ...grpc.CallOption) (*SyncIPAMChunkResponse, error)
+	ListIPAMChunks(ctx context.Context, in *ListIPAMChunksRequest, opts ...grpc.CallOption) (*ListIPAMChunksResponse, error)
+	SyncBlackhole(ctx context.Context, in *SyncBlackholeRequest, opts ...grpc.CallOption) (*SyncBlackholeResponse, error)
+	ListBlackholes(ctx context.Context, in *ListBlackholesRequest, opts ...grpc.CallOption) (*ListBlackholesResponse, error)
+	SyncACLIndexes(ctx context.Context, in *SyncACLIndexesRequest, opts ...grpc.CallOption) (*SyncACLIndexesResponse, error)
+	ListACLIndexes(ctx context.Context, in *ListACLIndexesRequest, opts ...grpc.CallOption) (*ListACLIndexesResponse, error)
 }
 
 type hAPeerServiceClient struct {
@@ -144,6 +156,66 @@ func (c *hAPeerServiceClient) BulkSyncCGNAT(ctx context.Context, in *BulkSyncCGN
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type HAPeerService_BulkSyncCGNATClient = grpc.ServerStreamingClient[BulkSyncCGNATResponse]
 
+func (c *hAPeerServiceClient) SyncIPAMChunk(ctx context.Context, in *SyncIPAMChunkRequest, opts ...grpc.CallOption) (*SyncIPAMChunkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncIPAMChunkResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_SyncIPAMChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hAPeerServiceClient) ListIPAMChunks(ctx context.Context, in *ListIPAMChunksRequest, opts ...grpc.CallOption) (*ListIPAMChunksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIPAMChunksResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_ListIPAMChunks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hAPeerServiceClient) SyncBlackhole(ctx context.Context, in *SyncBlackholeRequest, opts ...grpc.CallOption) (*SyncBlackholeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncBlackholeResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_SyncBlackhole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hAPeerServiceClient) ListBlackholes(ctx context.Context, in *ListBlackholesRequest, opts ...grpc.CallOption) (*ListBlackholesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlackholesResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_ListBlackholes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hAPeerServiceClient) SyncACLIndexes(ctx context.Context, in *SyncACLIndexesRequest, opts ...grpc.CallOption) (*SyncACLIndexesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncACLIndexesResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_SyncACLIndexes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hAPeerServiceClient) ListACLIndexes(ctx context.Context, in *ListACLIndexesRequest, opts ...grpc.CallOption) (*ListACLIndexesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListACLIndexesResponse)
+	err := c.cc.Invoke(ctx, HAPeerService_ListACLIndexes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HAPeerServiceServer is the server API for HAPeerService service.
 // All implementations must embed UnimplementedHAPeerServiceServer
 // for forward compatibility.
@@ -155,6 +227,12 @@ type HAPeerServiceServer interface {
 	BulkSync(*BulkSyncRequest, grpc.ServerStreamingServer[BulkSyncResponse]) error
 	SyncCGNATMapping(context.Context, *SyncCGNATMappingRequest) (*SyncCGNATMappingResponse, error)
 	BulkSyncCGNAT(*BulkSyncCGNATRequest, grpc.ServerStreamingServer[BulkSyncCGNATResponse]) error
+	SyncIPAMChunk(context.Context, *SyncIPAMChunkRequest) (*SyncIPAMChunkResponse, error)
+	ListIPAMChunks(context.Context, *ListIPAMChunksRequest) (*ListIPAMChunksResponse, error)
+	SyncBlackhole(context.Context, *SyncBlackholeRequest) (*SyncBlackholeResponse, error)
+	ListBlackholes(context.Context, *ListBlackholesRequest) (*ListBlackholesResponse, error)
+	SyncACLIndexes(context.Context, *SyncACLIndexesRequest) (*SyncACLIndexesResponse, error)
+	ListACLIndexes(context.Context, *ListACLIndexesRequest) (*ListACLIndexesResponse, error)
 	mustEmbedUnimplementedHAPeerServiceServer()
 }
 
@@ -186,6 +264,24 @@ func (UnimplementedHAPeerServiceServer) SyncCGNATMapping(context.Context, *SyncC
 func (UnimplementedHAPeerServiceServer) BulkSyncCGNAT(*BulkSyncCGNATRequest, grpc.ServerStreamingServer[BulkSyncCGNATResponse]) error {
 	return status.Error(codes.Unimplemented, "method BulkSyncCGNAT not implemented")
 }
+func (UnimplementedHAPeerServiceServer) SyncIPAMChunk(context.Context, *SyncIPAMChunkRequest) (*SyncIPAMChunkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SyncIPAMChunk not implemented")
+}
+func (UnimplementedHAPeerServiceServer) ListIPAMChunks(context.Context, *ListIPAMChunksRequest) (*ListIPAMChunksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListIPAMChunks not implemented")
+}
+func (UnimplementedHAPeerServiceServer) SyncBlackhole(context.Context, *SyncBlackholeRequest) (*SyncBlackholeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SyncBlackhole not implemented")
+}
+func (UnimplementedHAPeerServiceServer) ListBlackholes(context.Context, *ListBlackholesRequest) (*ListBlackholesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBlackholes not implemented")
+}
+func (UnimplementedHAPeerServiceServer) SyncACLIndexes(context.Context, *SyncACLIndexesRequest) (*SyncACLIndexesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SyncACLIndexes not implemented")
+}
+func (UnimplementedHAPeerServiceServer) ListACLIndexes(context.Context, *ListACLIndexesRequest) (*ListACLIndexesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListACLIndexes not implemented")
+}
 func (UnimplementedHAPeerServiceServer) mustEmbedUnimplementedHAPeerServiceServer() {}
 func (UnimplementedHAPeerServiceServer) testEmbeddedByValue()                       {}
 
@@ -308,6 +404,114 @@ func _HAPeerService_BulkSyncCGNAT_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type HAPeerService_BulkSyncCGNATServer = grpc.ServerStreamingServer[BulkSyncCGNATResponse]
 
+func _HAPeerService_SyncIPAMChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncIPAMChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).SyncIPAMChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_SyncIPAMChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).SyncIPAMChunk(ctx, req.(*SyncIPAMChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HAPeerService_ListIPAMChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIPAMChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).ListIPAMChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_ListIPAMChunks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).ListIPAMChunks(ctx, req.(*ListIPAMChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HAPeerService_SyncBlackhole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncBlackholeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).SyncBlackhole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_SyncBlackhole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).SyncBlackhole(ctx, req.(*SyncBlackholeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HAPeerService_ListBlackholes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlackholesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).ListBlackholes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_ListBlackholes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).ListBlackholes(ctx, req.(*ListBlackholesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HAPeerService_SyncACLIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncACLIndexesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).SyncACLIndexes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_SyncACLIndexes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).SyncACLIndexes(ctx, req.(*SyncACLIndexesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HAPeerService_ListACLIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLIndexesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HAPeerServiceServer).ListACLIndexes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HAPeerService_ListACLIndexes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HAPeerServiceServer).ListACLIndexes(ctx, req.(*ListACLIndexesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HAPeerService_ServiceDesc is the grpc.ServiceDesc for HAPeerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -331,6 +535,30 @@ var HAPeerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SyncCGNATMapping",
 			Handler:    _HAPeerService_SyncCGNATMapping_Handler,
 		},
+		{
+			MethodName: "SyncIPAMChunk",
+			Handler:    _HAPeerService_SyncIPAMChunk_Handler,
+		},
+		{
+			MethodName: "ListIPAMChunks",
+			Handler:    _HAPeerService_ListIPAMChunks_Handler,
+		},
+		{
+			MethodName: "SyncBlackhole",
+			Handler:    _HAPeerService_SyncBlackhole_Handler,
+		},
+		{
+			MethodName: "ListBlackholes",
+			Handler:    _HAPeerService_ListBlackholes_Handler,
+		},
+		{
+			MethodName: "SyncACLIndexes",
+			Handler:    _HAPeerService_SyncACLIndexes_Handler,
+		},
+		{
+			MethodName: "ListACLIndexes",
+			Handler:    _HAPeerService_ListACLIndexes_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
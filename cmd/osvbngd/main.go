@@ -16,16 +16,23 @@ import (
 	"time"
 
 	"github.com/veesix-networks/osvbng/internal/aaa"
+	"github.com/veesix-networks/osvbng/internal/aclindex"
 	"github.com/veesix-networks/osvbng/internal/arp"
 	cgnatcomp "github.com/veesix-networks/osvbng/internal/cgnat"
 	"github.com/veesix-networks/osvbng/internal/dataplane"
+	"github.com/veesix-networks/osvbng/internal/flowspec"
 	"github.com/veesix-networks/osvbng/internal/gateway"
+	ipamcomp "github.com/veesix-networks/osvbng/internal/ipam"
 	"github.com/veesix-networks/osvbng/internal/ipoe"
 	l2gwcomp "github.com/veesix-networks/osvbng/internal/l2gw"
 	"github.com/veesix-networks/osvbng/internal/l2tp"
+	"github.com/veesix-networks/osvbng/internal/localswitch"
 	"github.com/veesix-networks/osvbng/internal/monitor"
+	"github.com/veesix-networks/osvbng/internal/nptv6"
 	"github.com/veesix-networks/osvbng/internal/pppoe"
 	"github.com/veesix-networks/osvbng/internal/routing"
+	"github.com/veesix-networks/osvbng/internal/rtbh"
+	"github.com/veesix-networks/osvbng/internal/steering"
 	"github.com/veesix-networks/osvbng/internal/subscriber"
 	"github.com/veesix-networks/osvbng/internal/watchdog"
 	"github.com/veesix-networks/osvbng/internal/watchdog/targets"
@@ -50,6 +57,7 @@ import (
 	"github.com/veesix-networks/osvbng/pkg/handlers/show"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/all"
 	"github.com/veesix-networks/osvbng/pkg/ifmgr"
+	"github.com/veesix-networks/osvbng/pkg/ipam"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 	"github.com/veesix-networks/osvbng/pkg/netbind"
 	"github.com/veesix-networks/osvbng/pkg/northbound"
@@ -229,6 +237,21 @@ func main() {
 		PluginComponents: nil,
 	})
 
+	opdbStore, err := sqlite.Open("/var/lib/osvbng/opdb.db")
+	if err != nil {
+		log.Fatalf("Failed to open OpDB: %v", err)
+	}
+	defer opdbStore.Close()
+	mainLog.Info("OpDB initialized", "path", "/var/lib/osvbng/opdb.db")
+
+	// Access lists are created at the indexes they had before, so the
+	// indexes are pinned before the startup configuration creates them.
+	aclIndexComp := aclindex.New(aclindex.Config{OpDB: opdbStore, Southbound: vpp})
+	vpp.SetACLIndexObserver(aclIndexComp.Changed)
+	if err := aclIndexComp.Restore(context.Background()); err != nil {
+		mainLog.Warn("Failed to restore ACL indexes", "error", err)
+	}
+
 	if err := bootstrapDataplane(mainLog, configd, vpp, vrfMgr, evpnMgr, svcGroupResolver, cppmManager, cfg, accessInterface); err != nil {
 		log.Fatalf("Failed to bootstrap dataplane: %v", err)
 	}
@@ -238,13 +261,6 @@ func main() {
 
 	evpnMgr.SetEventBus(eventBus)
 
-	opdbStore, err := sqlite.Open("/var/lib/osvbng/opdb.db")
-	if err != nil {
-		log.Fatalf("Failed to open OpDB: %v", err)
-	}
-	defer opdbStore.Close()
-	mainLog.Info("OpDB initialized", "path", "/var/lib/osvbng/opdb.db")
-
 	exclusivityRegistry := session.NewRegistry()
 
 	showRegistry := show.NewRegistry()
@@ -376,6 +392,7 @@ func main() {
 		}
 		ipoeComp.SetL2GWChannel(l2gwComp.TriggerChan())
 		l2gwComp.SetPacketTriggerChan(dataplaneComp.L2GWTriggerChan)
+		l2gwComp.SetInterfaceWatch(watchSet.Add)
 		mainLog.Info("L2GW component created")
 	}
 
@@ -404,11 +421,18 @@ func main() {
 		l2tpComp.SetVRFManager(vrfMgr)
 		l2tpComp.SetServiceGroupResolver(svcGroupResolver)
 		l2tpComp.SetLocalHostname("osvbng")
+		l2tpComp.SetOpDB(coreDeps.OpDB)
+		if srgProvider != nil {
+			l2tpComp.SetSRGProvider(srgProvider)
+		}
 		if reg := allocator.GetGlobalRegistry(); reg != nil {
 			l2tpComp.SetAllocator(reg)
 		}
 
 		l2tpCfg := cfg.L2TP
+		if fo := l2tpCfg.FailoverSettings(); !fo.Disabled {
+			l2tpComp.SetFailover(fo.RecoveryTime)
+		}
 		l2tpComp.SetLNSConfigResolver(func(hostname string) (l2tp.LNSConfig, bool) {
 			policy := l2tpCfg.GetPeerPolicyByHostname(hostname)
 			if policy == nil {
@@ -447,6 +471,7 @@ func main() {
 			specs := l2tp.ParseTunnelSpecs(attrs.AAAAttrs)
 			return l2tpComp.StartLACSession(l2tp.LACBringUpRequest{
 				PPPoESessionID:       attrs.PPPoESessionID,
+				SRGName:              attrs.SRGName,
 				Username:             attrs.Username,
 				TunnelSpecs:          specs,
 				PPPoESwIfIndex:       attrs.PPPoESwIfIndex,
@@ -467,9 +492,47 @@ func main() {
 		if err != nil {
 			log.Fatalf("Failed to create CGNAT component: %v", err)
 		}
+		cgnat.SetRouting(routingComp)
 		mainLog.Info("CGNAT component created", "pools", len(cfg.CGNAT.Pools))
 	}
 
+	var ipamComp *ipamcomp.Component
+	if names := cfg.OnDemandProviders(); len(names) > 0 {
+		reg := allocator.GetGlobalRegistry()
+		if reg == nil {
+			log.Fatalf("On-demand pools need the allocator registry")
+		}
+		ipamProviders := make(map[string]ipam.Provider, len(names))
+		for _, name := range names {
+			p, err := ipam.New(name, cfg)
+			if err != nil {
+				log.Fatalf("Failed to create IPAM provider '%s': %v", name, err)
+			}
+			ipamProviders[name] = p
+			mainLog.Info("Loaded IPAM provider", "name", name)
+		}
+
+		ipamCfg := ipamcomp.Config{
+			EventBus:      eventBus,
+			ConfigManager: configd,
+			OpDB:          opdbStore,
+			Registry:      reg,
+			Routing:       routingComp,
+			Providers:     ipamProviders,
+		}
+		if haMgr != nil {
+			ipamCfg.SRG = haMgr
+			ipamCfg.Replicator = haMgr
+		}
+		ipamComp, err = ipamcomp.New(ipamCfg)
+		if err != nil {
+			log.Fatalf("Failed to create IPAM component: %v", err)
+		}
+		if haMgr != nil {
+			haMgr.RegisterIPAMChunkStore(ipamComp)
+		}
+	}
+
 	if haMgr != nil {
 		haMgr.RegisterSessionIterator(ipoeComp)
 		haMgr.RegisterSessionIterator(pppoeComp)
@@ -477,6 +540,10 @@ func main() {
 			haMgr.RegisterSessionIterator(l2gwComp)
 			haMgr.RegisterSyncApplier("l2gw", l2gwComp.ApplySyncedCircuit)
 		}
+		if l2tpComp != nil {
+			haMgr.RegisterSessionIterator(l2tpComp)
+			haMgr.RegisterSyncApplier("l2tp", l2tpComp.ApplySyncedSession)
+		}
 	}
 
 	wdCfg := cfg.Watchdog
@@ -521,6 +588,13 @@ func main() {
 							mainLog.Error("IPoE session recovery failed", "error", err)
 						}
 
+						if l2tpComp != nil {
+							mainLog.Info("VPP recovery: recovering L2TP tunnels")
+							if err := l2tpComp.RecoverSessions(ctx); err != nil {
+								mainLog.Error("L2TP session recovery failed", "error", err)
+							}
+						}
+
 						mainLog.Info("VPP recovery: recovering PPPoE sessions")
 						if err := pppoeComp.RecoverSessions(ctx); err != nil {
 							mainLog.Error("PPPoE session recovery failed", "error", err)
@@ -560,7 +634,63 @@ func main() {
 		log.Fatalf("Failed to create gateway component: %v", err)
 	}
 
-	monitorComp := monitor.New(monitor.Config{})
+	monitorCfg := monitor.Config{
+		EventBus:      eventBus,
+		ConfigManager: configd,
+		Routing:       routingComp,
+	}
+	if cgnat != nil {
+		monitorCfg.CGNAT = cgnat
+	}
+	monitorComp := monitor.New(monitorCfg)
+
+	nptv6Comp := nptv6.New(nptv6.Config{
+		EventBus:      eventBus,
+		ConfigManager: configd,
+		IfMgr:         ifMgr,
+		Southbound:    vpp,
+	})
+
+	steeringComp := steering.New(steering.Config{
+		ConfigManager: configd,
+		Southbound:    vpp,
+	})
+
+	rtbhCfg := rtbh.Config{
+		EventBus:      eventBus,
+		ConfigManager: configd,
+		OpDB:          opdbStore,
+		Southbound:    vpp,
+		Routing:       routingComp,
+		Sessions:      subscriberComp,
+	}
+	if haMgr != nil {
+		rtbhCfg.Replicator = haMgr
+	}
+	rtbhComp, err := rtbh.New(rtbhCfg)
+	if err != nil {
+		log.Fatalf("Failed to create RTBH component: %v", err)
+	}
+	if haMgr != nil {
+		haMgr.RegisterBlackholeStore(rtbhComp)
+		haMgr.RegisterACLIndexStore(aclIndexComp)
+		aclIndexComp.SetReplicator(haMgr)
+	}
+
+	flowspecComp := flowspec.New(flowspec.Config{
+		ConfigManager: configd,
+		Routing:       routingComp,
+		IfMgr:         ifMgr,
+		Southbound:    vpp,
+	})
+
+	localSwitchComp := localswitch.New(localswitch.Config{
+		EventBus:      eventBus,
+		ConfigManager: configd,
+		Southbound:    vpp,
+	})
+	arpComp.SetLocalSwitching(localSwitchComp)
+	ipoeComp.SetLocalSwitching(localSwitchComp)
 
 	orch := component.NewOrchestrator()
 	if haMgr != nil {
@@ -569,19 +699,33 @@ func main() {
 	orch.Register(aaaComp)
 	orch.Register(routingComp)
 	orch.Register(dataplaneComp)
+	orch.Register(aclIndexComp)
+	// On-demand chunks go back into the allocator before the access
+	// components restore sessions addressed from them.
+	if ipamComp != nil {
+		orch.Register(ipamComp)
+	}
+	// Local switching follows the sessions IPoE restores as it starts.
+	orch.Register(localSwitchComp)
 	orch.Register(ipoeComp)
 	if l2gwComp != nil {
 		orch.Register(l2gwComp)
 	}
 	orch.Register(subscriberComp)
 	orch.Register(arpComp)
-	orch.Register(pppoeComp)
+	// L2TP restores its tunnels before PPPoE replays LAC bindings
+	// through ResolveLACSessionIndex.
 	if l2tpComp != nil {
 		orch.Register(l2tpComp)
 	}
+	orch.Register(pppoeComp)
 	if cgnat != nil {
 		orch.Register(cgnat)
 	}
+	orch.Register(nptv6Comp)
+	orch.Register(steeringComp)
+	orch.Register(rtbhComp)
+	orch.Register(flowspecComp)
 	orch.Register(monitorComp)
 	orch.Register(gatewayComp)
 	if wd != nil {
@@ -632,6 +776,10 @@ func main() {
 		CGNAT:            cgnat,
 		L2TP:             l2tpComp,
 		L2GW:             l2gwComp,
+		NPTv6:            nptv6Comp,
+		Steering:         steeringComp,
+		RTBH:             rtbhComp,
+		FlowSpec:         flowspecComp,
 		RunningConfig:    configd,
 		Orchestrator:     orch,
 	})
@@ -643,6 +791,8 @@ func main() {
 		HAManager:        haMgr,
 		PluginComponents: pluginComponentsMap,
 		CGNAT:            cgnat,
+		L2TP:             l2tpComp,
+		RTBH:             rtbhComp,
 		ConfigReloader:   configd,
 	})
 
@@ -806,6 +956,7 @@ func bootstrapDataplane(
 ) error {
 	configd.ResetForRecovery()
 	vrfMgr.Reset()
+	sb.ResetACLs()
 
 	log.Info("Applying startup configuration")
 	if err := configd.ApplyLoadedConfig(); err != nil {
@@ -813,6 +964,9 @@ func bootstrapDataplane(
 			return fmt.Errorf("apply startup config: %w", err)
 		}
 	}
+	if err := sb.PruneAdoptedACLs(); err != nil {
+		log.Warn("Failed to prune ACLs left by an earlier run", "error", err)
+	}
 
 	if err := sb.LoadInterfaces(); err != nil {
 		log.Warn("Failed to load interfaces", "error", err)
@@ -0,0 +1,47 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package aaa
+
+import (
+	"github.com/veesix-networks/osvbng/pkg/accessline"
+	"github.com/veesix-networks/osvbng/pkg/events"
+)
+
+// handleAccessLine records the line rates a renew reported for a
+// session, so the next interim carries them. Unlike a schedule boundary
+// no update is sent straight away: the line retraining is not a change
+// to what the subscriber is billed for.
+func (c *Component) handleAccessLine(event events.Event) {
+	data, ok := event.Data.(*events.AccessLineEvent)
+	if !ok {
+		return
+	}
+
+	c.acctCacheMu.RLock()
+	acctSession, exists := c.acctCache[data.SessionID]
+	c.acctCacheMu.RUnlock()
+	if !exists {
+		return
+	}
+
+	// Copy on write: the attribute map may be in the hands of an
+	// accounting request in flight.
+	acctSession.mu.Lock()
+	attrs := make(map[string]string, len(acctSession.attributes))
+	for k, v := range acctSession.attributes {
+		attrs[k] = v
+	}
+	for _, name := range accessline.AttributeNames() {
+		if v, ok := data.Attributes[name]; ok {
+			attrs[name] = v
+		} else {
+			delete(attrs, name)
+		}
+	}
+	acctSession.attributes = attrs
+	acctSession.mu.Unlock()
+
+	c.checkpointAcctSession(acctSession)
+}
@@ -98,6 +98,10 @@ type Component struct {
 	aaaReqSub    events.Subscription
 	lifecycleSub events.Subscription
 	restoredSub  events.Subscription
+	tunnelSub    events.Subscription
+	nptv6Sub     events.Subscription
+	scheduleSub  events.Subscription
+	lineSub      events.Subscription
 
 	buckets  map[int][]string
 	bucketMu sync.RWMutex
@@ -152,6 +156,10 @@ func (c *Component) Start(ctx context.Context) error {
 	// RFC 2866.
 	c.lifecycleSub = c.eventBus.Subscribe(events.TopicSessionLifecycle, c.handleSessionLifecycle)
 	c.restoredSub = c.eventBus.Subscribe(events.TopicSessionRestored, c.handleSessionRestored)
+	c.tunnelSub = c.eventBus.Subscribe(events.TopicL2TPTunnelAccounting, c.handleTunnelAccounting)
+	c.nptv6Sub = c.eventBus.Subscribe(events.TopicNPTv6Binding, c.handleNPTv6Binding)
+	c.scheduleSub = c.eventBus.Subscribe(events.TopicServiceSchedule, c.handleServiceSchedule)
+	c.lineSub = c.eventBus.Subscribe(events.TopicAccessLine, c.handleAccessLine)
 
 	c.BuildAccountingBuckets()
 	c.Go(c.orphanPruneLoop)
@@ -166,6 +174,18 @@ func (c *Component) Stop(ctx context.Context) error {
 	if c.restoredSub != nil {
 		c.restoredSub.Unsubscribe()
 	}
+	if c.tunnelSub != nil {
+		c.tunnelSub.Unsubscribe()
+	}
+	if c.nptv6Sub != nil {
+		c.nptv6Sub.Unsubscribe()
+	}
+	if c.scheduleSub != nil {
+		c.scheduleSub.Unsubscribe()
+	}
+	if c.lineSub != nil {
+		c.lineSub.Unsubscribe()
+	}
 	c.StopContext()
 	return nil
 }
@@ -331,6 +351,7 @@ func (c *Component) sendAccountingUpdate(acctSession *AccountingSession, statsBy
 	} else if stats, ok := statsByIdx[acctSession.swIfIndex]; ok {
 		rxBytes, txBytes, rxPackets, txPackets = acctSession.applyVPPCounters(stats)
 	}
+	attributes := acctSession.attributes
 	acctSession.mu.Unlock()
 
 	session := &auth.Session{
@@ -349,7 +370,7 @@ func (c *Component) sendAccountingUpdate(acctSession *AccountingSession, statsBy
 		RxPackets:         rxPackets,
 		TxPackets:         txPackets,
 		SessionDuration:   uint32(time.Since(acctSession.authDate).Seconds()),
-		Attributes:        acctSession.attributes,
+		Attributes:        attributes,
 	}
 
 	if err := c.authProvider.UpdateAccounting(c.Ctx, session); err != nil {
@@ -743,6 +764,7 @@ func (c *Component) handleSessionRelease(sessionId, username, mac, acctSessionID
 		cvlan = acctSession.cvlan
 		accessIfIndex = acctSession.accessIfIndex
 		subscriberIfIndex = acctSession.swIfIndex
+		attributes = withNPTv6Attributes(attributes, acctSession)
 
 		statsByIdx := c.fetchInterfaceStats()
 		acctSession.mu.Lock()
@@ -623,3 +623,49 @@ func TestHandleSessionLifecycleAcctStartCarriesZeros(t *testing.T) {
 		t.Fatalf("Acct-Start must carry zero counters, got %+v", ap.lastSession)
 	}
 }
+
+func TestNPTv6BindingReachesInterimAndStop(t *testing.T) {
+	ap := &recordingAuthProvider{}
+	ss := &stubShowSource{result: []southbound.InterfaceStats{}}
+	c := newCounterTestComponent(t, ap, ss)
+
+	acctSess := &AccountingSession{
+		sessionID:     "abc",
+		acctSessionID: "acct-abc",
+		authDate:      time.Now(),
+		swIfIndex:     42,
+		attributes:    map[string]string{"ipv4_address": "10.0.0.5"},
+	}
+	c.acctCache[acctSess.sessionID] = acctSess
+	c.placeSessionInBucket(acctSess.sessionID)
+
+	c.handleNPTv6Binding(events.Event{Data: &events.NPTv6BindingEvent{
+		SessionID: "abc",
+		IsAdd:     true,
+		Binding:   &models.NPTv6Binding{SessionID: "abc", Internal: "fd00:42::/56", External: "2001:db8:100::/56"},
+	}})
+	c.ProcessAccountingBucket(bucketForSession(acctSess.sessionID))
+	ap.waitFor(t, "update")
+	ap.mu.Lock()
+	attrs := ap.lastSession.Attributes
+	ap.mu.Unlock()
+	if attrs["nptv6.internal-prefix"] != "fd00:42::/56" || attrs["nptv6.external-prefix"] != "2001:db8:100::/56" || attrs["ipv4_address"] != "10.0.0.5" {
+		t.Fatalf("interim attributes = %v", attrs)
+	}
+
+	// The binding is removed before the session's release reaches AAA;
+	// Stop still reports it.
+	c.handleNPTv6Binding(events.Event{Data: &events.NPTv6BindingEvent{
+		SessionID: "abc",
+		Binding:   &models.NPTv6Binding{SessionID: "abc", Internal: "fd00:42::/56", External: "2001:db8:100::/56"},
+	}})
+	if err := c.handleSessionRelease("abc", "", "", "acct-abc", map[string]string{}); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	ap.waitFor(t, "stop")
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	if ap.lastSession.Attributes["nptv6.external-prefix"] != "2001:db8:100::/56" {
+		t.Fatalf("stop attributes = %v", ap.lastSession.Attributes)
+	}
+}
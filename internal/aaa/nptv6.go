@@ -0,0 +1,62 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package aaa
+
+import (
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/events"
+)
+
+// nptv6Attributes are the accounting attributes the NPTv6 component's
+// bindings set on a session.
+var nptv6Attributes = []string{aaa.AttrNPTv6InternalPrefix, aaa.AttrNPTv6ExternalPrefix}
+
+// handleNPTv6Binding records a session's NPTv6 translation so its
+// Interim-Update and Stop records report it. Removals are ignored: a
+// binding is removed as the session ends, and Stop should still carry
+// it; a changed translation arrives as a new binding.
+func (c *Component) handleNPTv6Binding(event events.Event) {
+	data, ok := event.Data.(*events.NPTv6BindingEvent)
+	if !ok || data.Binding == nil || !data.IsAdd {
+		return
+	}
+
+	c.acctCacheMu.RLock()
+	acctSession, exists := c.acctCache[data.SessionID]
+	c.acctCacheMu.RUnlock()
+	if !exists {
+		return
+	}
+
+	// Copy on write: the attribute map may be in the hands of an
+	// accounting request in flight.
+	acctSession.mu.Lock()
+	attrs := make(map[string]string, len(acctSession.attributes)+len(nptv6Attributes))
+	for k, v := range acctSession.attributes {
+		attrs[k] = v
+	}
+	attrs[aaa.AttrNPTv6InternalPrefix] = data.Binding.Internal
+	attrs[aaa.AttrNPTv6ExternalPrefix] = data.Binding.External
+	acctSession.attributes = attrs
+	acctSession.mu.Unlock()
+
+	c.checkpointAcctSession(acctSession)
+}
+
+// withNPTv6Attributes adds the NPTv6 attributes recorded on a cached
+// session to the attributes of its Stop record.
+func withNPTv6Attributes(attributes map[string]string, acctSession *AccountingSession) map[string]string {
+	acctSession.mu.Lock()
+	defer acctSession.mu.Unlock()
+	for _, k := range nptv6Attributes {
+		if v, ok := acctSession.attributes[k]; ok {
+			if attributes == nil {
+				attributes = make(map[string]string)
+			}
+			attributes[k] = v
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package aaa
+
+import (
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/events"
+)
+
+// handleServiceSchedule records the schedules now shaping a session and
+// sends an Interim-Update straight away, so billing sees the boundary
+// at the time it happened rather than at the next interim tick. The
+// record closes the counters out up to the change: bytes before it were
+// carried on the old bindings, bytes after on the new.
+func (c *Component) handleServiceSchedule(event events.Event) {
+	data, ok := event.Data.(*events.ServiceScheduleEvent)
+	if !ok {
+		return
+	}
+
+	c.acctCacheMu.RLock()
+	acctSession, exists := c.acctCache[data.SessionID]
+	c.acctCacheMu.RUnlock()
+	if !exists {
+		return
+	}
+
+	// Copy on write: the attribute map may be in the hands of an
+	// accounting request in flight.
+	acctSession.mu.Lock()
+	attrs := make(map[string]string, len(acctSession.attributes)+1)
+	for k, v := range acctSession.attributes {
+		attrs[k] = v
+	}
+	if len(data.Schedules) > 0 {
+		attrs[aaa.AttrServiceSchedules] = strings.Join(data.Schedules, ",")
+	} else {
+		delete(attrs, aaa.AttrServiceSchedules)
+	}
+	acctSession.attributes = attrs
+	acctSession.mu.Unlock()
+
+	c.checkpointAcctSession(acctSession)
+
+	// l2gw circuits have no service group, so never reach here and need
+	// no l2gw counters.
+	go c.sendAccountingUpdate(acctSession, c.fetchInterfaceStats(), nil)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package aaa
+
+import (
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"github.com/veesix-networks/osvbng/pkg/events"
+)
+
+// handleTunnelAccounting forwards RFC 2867 tunnel and link records to
+// the auth provider. Providers without tunnel accounting never see
+// them; subscriber accounting is unaffected either way.
+func (c *Component) handleTunnelAccounting(event events.Event) {
+	ta, ok := c.authProvider.(auth.TunnelAccounter)
+	if !ok {
+		return
+	}
+	data, ok := event.Data.(*events.L2TPTunnelAccountingEvent)
+	if !ok {
+		return
+	}
+
+	rec := &auth.TunnelAccountingRecord{
+		Status:             auth.TunnelAcctStatus(data.Status),
+		AcctSessionID:      data.AcctSessionID,
+		AcctMultiSessionID: data.AcctMultiSessionID,
+		TunnelConnectionID: data.TunnelConnectionID,
+		Medium:             data.Medium,
+		ClientEndpoint:     data.ClientEndpoint,
+		ServerEndpoint:     data.ServerEndpoint,
+		ClientAuthID:       data.ClientAuthID,
+		ServerAuthID:       data.ServerAuthID,
+		SessionID:          data.SessionID,
+		Username:           data.Username,
+		SessionDuration:    data.SessionDuration,
+		PacketsLost:        data.PacketsLost,
+		TerminateCause:     data.TerminateCause,
+		Attributes:         data.Attributes,
+	}
+
+	go func() {
+		if err := ta.TunnelAccounting(c.Ctx, rec); err != nil {
+			c.logger.Warn("Tunnel accounting failed",
+				"status", data.Status, "acct_session_id", data.AcctSessionID, "error", err)
+		}
+	}()
+}
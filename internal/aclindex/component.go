@@ -0,0 +1,222 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package aclindex keeps the dataplane indexes of the access lists the
+// same across restarts and on both peers of an HA pair. The indexes are
+// persisted, and pinned before the configuration is applied so a
+// dataplane that lost its state creates each list where it was. The
+// peers exchange their indexes: a list the peer already has is created
+// at the peer's index, and where the peers differ the node with the
+// higher node ID moves its list to the other's index.
+package aclindex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+)
+
+// opdbNamespace holds the index of each access list, keyed by name.
+const opdbNamespace = "acl_indexes"
+
+// Southbound programs the access lists.
+type Southbound interface {
+	ACLIndexes() map[string]uint32
+	PinACLIndexes(indexes map[string]uint32)
+	MoveACL(name string, index uint32) error
+}
+
+// Replicator sends this node's indexes to the HA peer.
+type Replicator interface {
+	ReplicateACLIndexes(ctx context.Context, indexes map[string]uint32) error
+}
+
+// Config wires the component. OpDB and Replicator are optional: without
+// OpDB indexes are only kept while the daemon runs, and without
+// Replicator the node is treated as standalone.
+type Config struct {
+	OpDB       opdb.Store
+	Southbound Southbound
+	Replicator Replicator
+}
+
+// Component persists and exchanges the access-list indexes.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+
+	// changed is signalled by the southbound when an index changes.
+	changed chan struct{}
+
+	mu sync.Mutex
+	// saved is what the OpDB holds.
+	saved map[string]uint32
+}
+
+func New(cfg Config) *Component {
+	return &Component{
+		Base:    component.NewBase("aclindex"),
+		logger:  logger.Get("aclindex"),
+		cfg:     cfg,
+		changed: make(chan struct{}, 1),
+		saved:   make(map[string]uint32),
+	}
+}
+
+// SetReplicator sets the Replicator of a component created before the
+// HA manager. It is called before Start.
+func (c *Component) SetReplicator(r Replicator) {
+	c.cfg.Replicator = r
+}
+
+// Restore pins the persisted indexes. It is called before the startup
+// configuration is applied.
+func (c *Component) Restore(ctx context.Context) error {
+	if c.cfg.OpDB == nil {
+		return nil
+	}
+	indexes := make(map[string]uint32)
+	err := c.cfg.OpDB.Load(ctx, opdbNamespace, func(key string, value []byte) error {
+		if len(value) != 4 {
+			c.logger.Warn("Skipping undecodable ACL index", "acl_name", key)
+			return nil
+		}
+		indexes[key] = binary.BigEndian.Uint32(value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for name, index := range indexes {
+		c.saved[name] = index
+	}
+	c.mu.Unlock()
+
+	c.cfg.Southbound.PinACLIndexes(indexes)
+	if len(indexes) > 0 {
+		c.logger.Info("Pinned ACL indexes", "count", len(indexes))
+	}
+	return nil
+}
+
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting ACL index component")
+	c.Go(c.run)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping ACL index component")
+	c.StopContext()
+	return nil
+}
+
+// Changed is the southbound's index observer. It only signals, so it
+// can be called with the southbound's registry locked.
+func (c *Component) Changed() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Component) run() {
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return
+		case <-c.changed:
+			c.sync(c.Ctx)
+		}
+	}
+}
+
+// sync persists the current indexes and sends them to the peer.
+func (c *Component) sync(ctx context.Context) {
+	indexes := c.cfg.Southbound.ACLIndexes()
+	c.persist(ctx, indexes)
+
+	if c.cfg.Replicator == nil {
+		return
+	}
+	if err := c.cfg.Replicator.ReplicateACLIndexes(ctx, indexes); err != nil {
+		c.logger.Warn("Failed to replicate ACL indexes to peer", "error", err)
+	}
+}
+
+func (c *Component) persist(ctx context.Context, indexes map[string]uint32) {
+	if c.cfg.OpDB == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name := range c.saved {
+		if _, ok := indexes[name]; ok {
+			continue
+		}
+		if err := c.cfg.OpDB.Delete(ctx, opdbNamespace, name); err != nil {
+			c.logger.Error("Failed to delete ACL index", "acl_name", name, "error", err)
+			continue
+		}
+		delete(c.saved, name)
+	}
+	for name, index := range indexes {
+		if saved, ok := c.saved[name]; ok && saved == index {
+			continue
+		}
+		value := binary.BigEndian.AppendUint32(nil, index)
+		if err := c.cfg.OpDB.Put(ctx, opdbNamespace, name, value); err != nil {
+			c.logger.Error("Failed to persist ACL index", "acl_name", name, "error", err)
+			continue
+		}
+		c.saved[name] = index
+	}
+}
+
+// ACLIndexes returns the index of each access list on this node, for
+// the HA peer.
+func (c *Component) ACLIndexes() map[string]uint32 {
+	return c.cfg.Southbound.ACLIndexes()
+}
+
+// ApplyPeerACLIndexes takes the HA peer's indexes. Lists this node has
+// not created yet are pinned to them; with move, lists it has at another
+// index are moved there too.
+func (c *Component) ApplyPeerACLIndexes(indexes map[string]uint32, move bool) error {
+	local := c.cfg.Southbound.ACLIndexes()
+
+	pins := make(map[string]uint32, len(indexes))
+	for name, index := range indexes {
+		if _, ok := local[name]; !ok || move {
+			pins[name] = index
+		}
+	}
+	c.cfg.Southbound.PinACLIndexes(pins)
+
+	if !move {
+		return nil
+	}
+	var errs []error
+	for name, index := range indexes {
+		current, ok := local[name]
+		if !ok || current == index {
+			continue
+		}
+		if err := c.cfg.Southbound.MoveACL(name, index); err != nil {
+			errs = append(errs, fmt.Errorf("access-list %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
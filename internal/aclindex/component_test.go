@@ -0,0 +1,130 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package aclindex
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+)
+
+type fakeSB struct {
+	indexes map[string]uint32
+	pinned  map[string]uint32
+	moved   map[string]uint32
+}
+
+func (f *fakeSB) ACLIndexes() map[string]uint32 {
+	out := make(map[string]uint32, len(f.indexes))
+	for k, v := range f.indexes {
+		out[k] = v
+	}
+	return out
+}
+
+func (f *fakeSB) PinACLIndexes(indexes map[string]uint32) {
+	for k, v := range indexes {
+		f.pinned[k] = v
+	}
+}
+
+func (f *fakeSB) MoveACL(name string, index uint32) error {
+	f.indexes[name] = index
+	f.moved[name] = index
+	return nil
+}
+
+type fakeReplicator struct{ sent []map[string]uint32 }
+
+func (r *fakeReplicator) ReplicateACLIndexes(ctx context.Context, indexes map[string]uint32) error {
+	r.sent = append(r.sent, indexes)
+	return nil
+}
+
+type fakeOpDB struct {
+	opdb.Store
+	data map[string][]byte
+}
+
+func (f *fakeOpDB) Put(ctx context.Context, namespace, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeOpDB) Delete(ctx context.Context, namespace, key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeOpDB) Load(ctx context.Context, namespace string, fn opdb.LoadFunc) error {
+	for k, v := range f.data {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newFakeSB() *fakeSB {
+	return &fakeSB{indexes: map[string]uint32{}, pinned: map[string]uint32{}, moved: map[string]uint32{}}
+}
+
+func TestPersistAndRestore(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOpDB{data: map[string][]byte{}}
+	sb := newFakeSB()
+	peer := &fakeReplicator{}
+	c := New(Config{OpDB: store, Southbound: sb, Replicator: peer})
+
+	sb.indexes = map[string]uint32{"residential-in": 0, "business-in": 3}
+	c.sync(ctx)
+	delete(sb.indexes, "business-in")
+	sb.indexes["residential-in"] = 2
+	c.sync(ctx)
+
+	if len(store.data) != 1 || binary.BigEndian.Uint32(store.data["residential-in"]) != 2 {
+		t.Fatalf("persisted %v", store.data)
+	}
+	if len(peer.sent) != 2 || !reflect.DeepEqual(peer.sent[1], map[string]uint32{"residential-in": 2}) {
+		t.Fatalf("replicated %v", peer.sent)
+	}
+
+	// A restart, with a dataplane that lost its ACLs, pins them where
+	// they were.
+	restarted := newFakeSB()
+	if err := New(Config{OpDB: store, Southbound: restarted}).Restore(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restarted.pinned, map[string]uint32{"residential-in": 2}) {
+		t.Fatalf("pinned %v", restarted.pinned)
+	}
+}
+
+func TestApplyPeerACLIndexes(t *testing.T) {
+	peer := map[string]uint32{"residential-in": 5, "business-in": 6}
+
+	// The node that does not move only pins the lists it lacks.
+	sb := newFakeSB()
+	sb.indexes["residential-in"] = 1
+	if err := New(Config{Southbound: sb}).ApplyPeerACLIndexes(peer, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sb.pinned, map[string]uint32{"business-in": 6}) || len(sb.moved) != 0 {
+		t.Fatalf("pinned %v, moved %v", sb.pinned, sb.moved)
+	}
+
+	// The other moves the lists it has elsewhere.
+	sb = newFakeSB()
+	sb.indexes["residential-in"] = 1
+	if err := New(Config{Southbound: sb}).ApplyPeerACLIndexes(peer, true); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sb.pinned, peer) || !reflect.DeepEqual(sb.moved, map[string]uint32{"residential-in": 5}) {
+		t.Fatalf("pinned %v, moved %v", sb.pinned, sb.moved)
+	}
+}
@@ -35,6 +35,14 @@ type Component struct {
 	vrfMgr    *vrfmgr.Manager
 	configMgr component.ConfigManager
 	arpChan   <-chan *dataplane.ParsedPacket
+
+	localSwitching LocalSwitching
+}
+
+// LocalSwitching decides whether the BNG answers a subscriber's ARP
+// request for another subscriber's address.
+type LocalSwitching interface {
+	ProxyNeighbor(sessionID string, target net.IP) bool
 }
 
 func New(deps component.Dependencies, srgMgr ha.SRGProvider, ifMgr *ifmgr.Manager) (*Component, error) {
@@ -53,6 +61,12 @@ func New(deps component.Dependencies, srgMgr ha.SRGProvider, ifMgr *ifmgr.Manage
 	}, nil
 }
 
+// SetLocalSwitching enables proxy ARP between subscribers as their
+// group's local-switching mode allows. Call before Start.
+func (c *Component) SetLocalSwitching(ls LocalSwitching) {
+	c.localSwitching = ls
+}
+
 func (c *Component) resolveSRGName(svlan, cvlan uint16) string {
 	if c.srgMgr == nil {
 		return ""
@@ -128,13 +142,26 @@ func (c *Component) handlePacket(pkt *dataplane.ParsedPacket) error {
 		"dst_ip", dstIP.String(),
 	)
 
-	if c.ifMgr == nil || !c.ifMgr.HasIPv4(dstIP) {
+	owned := c.ifMgr != nil && c.ifMgr.HasIPv4(dstIP)
+	if !owned && (c.ifMgr == nil || c.localSwitching == nil) {
 		c.logger.Debug("Ignoring ARP request for non-owned IP",
 			"dst_ip", dstIP.String())
 		return nil
 	}
 
 	sess := c.lookupSubscriberSession(pkt)
+	if !owned {
+		// The group's local-switching mode may have the BNG answer for
+		// another subscriber, so their traffic hairpins through it.
+		if sess == nil || !c.localSwitching.ProxyNeighbor(sess.SessionID, dstIP) {
+			c.logger.Debug("Ignoring ARP request for non-owned IP",
+				"dst_ip", dstIP.String())
+			return nil
+		}
+		c.logger.Debug("Proxying ARP for subscriber in the same group",
+			"dst_ip", dstIP.String(),
+			"session_id", sess.SessionID)
+	}
 	if sess != nil && sess.IPv4Address != nil && sess.IPv4Address.Equal(dstIP) {
 		c.logger.Debug("Ignoring ARP for client's own assigned IP",
 			"dst_ip", dstIP.String(),
@@ -142,7 +169,7 @@ func (c *Component) handlePacket(pkt *dataplane.ParsedPacket) error {
 		return nil
 	}
 
-	if sess != nil {
+	if sess != nil && owned {
 		if sess.VRF != "" {
 			if !c.isOwnedIPInVRF(dstIP, sess.VRF) {
 				c.logger.Debug("Ignoring ARP request for IP not in subscriber VRF",
@@ -0,0 +1,904 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+const (
+	// addressNamespace holds the runtime outside address changes, keyed
+	// by CGNATAddressChange.Key.
+	addressNamespace = "cgnat_pool_addresses"
+	// drainCheckInterval matches the pool watermark checks.
+	drainCheckInterval = 10 * time.Second
+)
+
+// BGPNetworkController advertises the outside prefixes added to a pool
+// while running and withdraws those removed.
+type BGPNetworkController interface {
+	AdvertiseBGPNetworkPolicy(asn uint32, vrf string, prefix string, routePolicy string, ipv6 bool) error
+	RemoveBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error
+}
+
+// SetRouting lets outside prefixes added while running be advertised as
+// configured ones are. Without it they are only programmed in the
+// dataplane.
+func (c *Component) SetRouting(r BGPNetworkController) { c.routing = r }
+
+func (a *outsideAddressState) allocatable() bool {
+	return !a.Excluded && !a.Draining
+}
+
+func (a *outsideAddressState) allocatedBlocks() uint32 {
+	var n uint32
+	for _, word := range a.AllocatedBits {
+		n += uint32(popcount(word))
+	}
+	return n
+}
+
+func (a *outsideAddressState) state() string {
+	switch {
+	case !a.Draining:
+		return models.CGNATAddressActive
+	case a.allocatedBlocks() > 0:
+		return models.CGNATAddressDraining
+	}
+	return models.CGNATAddressDrained
+}
+
+// parseAddressPrefix parses an outside address or prefix as the pool
+// config takes it.
+func parseAddressPrefix(s string) (*net.IPNet, error) {
+	n := parseOutsideAddr(s)
+	if n == nil || n.IP.To4() == nil {
+		return nil, fmt.Errorf("invalid outside address %q: expected an IPv4 address or prefix", s)
+	}
+	return n, nil
+}
+
+// withAddressChanges returns cfg with the runtime changes applied to
+// its pools' outside addresses, leaving cfg itself untouched.
+func withAddressChanges(cfg *config.Config, changes []*models.CGNATAddressChange) *config.Config {
+	if cfg.CGNAT == nil || len(changes) == 0 {
+		return cfg
+	}
+
+	pools := make(map[string]*cgnat.Pool, len(cfg.CGNAT.Pools))
+	for name, p := range cfg.CGNAT.Pools {
+		pools[name] = p
+	}
+	for _, ch := range changes {
+		p := pools[ch.Pool]
+		if p == nil {
+			continue
+		}
+		cp := *p
+		switch ch.Action {
+		case models.CGNATAddressAdd:
+			cp.OutsideAddresses = append(append([]string(nil), p.OutsideAddresses...), ch.Prefix)
+		case models.CGNATAddressRemove:
+			cp.OutsideAddresses = nil
+			for _, addr := range p.OutsideAddresses {
+				if n := parseOutsideAddr(addr); n == nil || n.String() != ch.Prefix {
+					cp.OutsideAddresses = append(cp.OutsideAddresses, addr)
+				}
+			}
+		default:
+			continue
+		}
+		pools[ch.Pool] = &cp
+	}
+
+	out := *cfg
+	cgnatCfg := *cfg.CGNAT
+	cgnatCfg.Pools = pools
+	out.CGNAT = &cgnatCfg
+	return &out
+}
+
+// configuredPrefix reports whether prefix is one of the pool's
+// configured outside addresses.
+func configuredPrefix(pool *cgnat.Pool, prefix string) bool {
+	for _, addr := range pool.OutsideAddresses {
+		if n := parseOutsideAddr(addr); n != nil && n.String() == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// loadAddressChanges reads the runtime outside address changes.
+// Changes the configuration has caught up with are dropped: an add of a
+// prefix now configured, a remove of one no longer configured, and any
+// change to a pool that is gone or not PBA.
+func (c *Component) loadAddressChanges(ctx context.Context, cfg *cgnat.Config) []*models.CGNATAddressChange {
+	if c.opdb == nil {
+		return nil
+	}
+
+	var changes []*models.CGNATAddressChange
+	var stale []string
+	c.opdb.Load(ctx, addressNamespace, func(key string, value []byte) error {
+		var ch models.CGNATAddressChange
+		if err := json.Unmarshal(value, &ch); err != nil {
+			stale = append(stale, key)
+			return nil
+		}
+		pool := cfg.Pools[ch.Pool]
+		if pool == nil || pool.GetMode() != "pba" {
+			stale = append(stale, key)
+			return nil
+		}
+		switch ch.Action {
+		case models.CGNATAddressAdd:
+			if configuredPrefix(pool, ch.Prefix) {
+				stale = append(stale, key)
+				return nil
+			}
+		case models.CGNATAddressRemove:
+			if !configuredPrefix(pool, ch.Prefix) {
+				stale = append(stale, key)
+				return nil
+			}
+		}
+		changes = append(changes, &ch)
+		return nil
+	})
+	for _, key := range stale {
+		c.opdb.Delete(ctx, addressNamespace, key)
+	}
+
+	c.addrMu.Lock()
+	for _, ch := range changes {
+		c.addrChanges[ch.Key()] = ch
+	}
+	c.addrMu.Unlock()
+
+	// Removes go first so a prefix removed and added back is not added
+	// twice.
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Action == models.CGNATAddressRemove && changes[j].Action != models.CGNATAddressRemove
+	})
+	if len(changes) > 0 {
+		c.logger.Info("Loaded runtime CGNAT outside address changes", "count", len(changes))
+	}
+	return changes
+}
+
+// applyAddressState marks the pools' runtime addresses and drains once
+// reconcile has rebuilt them.
+func (c *Component) applyAddressState(changes []*models.CGNATAddressChange) {
+	for _, ch := range changes {
+		n, err := parseAddressPrefix(ch.Prefix)
+		if err != nil {
+			continue
+		}
+		switch ch.Action {
+		case models.CGNATAddressAdd:
+			c.pools.markRuntime(ch.Pool, n)
+		case models.CGNATAddressDrain:
+			c.pools.setDraining(ch.Pool, n, true, ch.Started, ch.MigrateAt)
+		}
+	}
+}
+
+func (c *Component) persistAddressChange(ctx context.Context, ch *models.CGNATAddressChange) {
+	c.addrChanges[ch.Key()] = ch
+	if c.opdb == nil {
+		return
+	}
+	if data, err := json.Marshal(ch); err == nil {
+		if err := c.opdb.Put(ctx, addressNamespace, ch.Key(), data); err != nil {
+			c.logger.Error("Failed to persist CGNAT outside address change", "change", ch.Key(), "error", err)
+		}
+	}
+}
+
+func (c *Component) forgetAddressChange(ctx context.Context, key string) bool {
+	if _, ok := c.addrChanges[key]; !ok {
+		return false
+	}
+	delete(c.addrChanges, key)
+	if c.opdb != nil {
+		c.opdb.Delete(ctx, addressNamespace, key)
+	}
+	return true
+}
+
+// AddPoolAddress adds an outside address or prefix to a running PBA
+// pool. It takes new blocks straight away and is kept across restarts
+// until it is added to the configuration.
+func (c *Component) AddPoolAddress(ctx context.Context, poolName, address string) ([]models.CGNATOutsideAddress, error) {
+	n, err := parseAddressPrefix(address)
+	if err != nil {
+		return nil, err
+	}
+	prefix := n.String()
+
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
+
+	pool := c.pools.poolConfig(poolName)
+	if pool == nil {
+		return nil, fmt.Errorf("unknown pool %q", poolName)
+	}
+	if pool.GetMode() != "pba" {
+		return nil, fmt.Errorf("pool %q: outside addresses can only be added while running to pba pools", poolName)
+	}
+	if other, ip := c.pools.overlappingAddress(n); ip != nil {
+		return nil, fmt.Errorf("%s overlaps outside address %s of pool %q", prefix, ip, other)
+	}
+	if err := c.rejectLocalAddressOverlap(poolName, &cgnat.Pool{OutsideAddresses: []string{prefix}}); err != nil {
+		return nil, err
+	}
+
+	poolID := c.poolIDMap[poolName]
+	if err := c.dataplane.CGNATPoolAddOutsideAddress(poolID, *n, true); err != nil {
+		return nil, fmt.Errorf("add outside address: %w", err)
+	}
+	if err := c.pools.addOutsideAddresses(poolName, n); err != nil {
+		c.dataplane.CGNATPoolAddOutsideAddress(poolID, *n, false)
+		return nil, err
+	}
+
+	removeKey := (&models.CGNATAddressChange{Pool: poolName, Action: models.CGNATAddressRemove, Prefix: prefix}).Key()
+	if !c.forgetAddressChange(ctx, removeKey) {
+		c.pools.markRuntime(poolName, n)
+		c.persistAddressChange(ctx, &models.CGNATAddressChange{
+			Pool:    poolName,
+			Action:  models.CGNATAddressAdd,
+			Prefix:  prefix,
+			Started: time.Now(),
+		})
+	}
+	c.advertiseAddress(pool, prefix, true)
+
+	c.logger.Info("Added CGNAT outside address", "pool", poolName, "prefix", prefix)
+	return c.pools.outsideAddresses(poolName, n), nil
+}
+
+// DrainPoolAddress stops the pool's outside addresses within address
+// taking new blocks. The blocks they hold are released as their
+// subscribers disconnect or, once migrateAfter has passed, moved to
+// other addresses of the pool. A zero migrateAfter waits for the
+// subscribers.
+func (c *Component) DrainPoolAddress(ctx context.Context, poolName, address string, migrateAfter time.Duration) ([]models.CGNATOutsideAddress, error) {
+	n, err := parseAddressPrefix(address)
+	if err != nil {
+		return nil, err
+	}
+	if migrateAfter < 0 {
+		return nil, fmt.Errorf("migrate-after must not be negative")
+	}
+
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
+
+	ch := &models.CGNATAddressChange{Pool: poolName, Action: models.CGNATAddressDrain, Prefix: n.String(), Started: time.Now()}
+	if prev, ok := c.addrChanges[ch.Key()]; ok {
+		ch.Started = prev.Started
+	}
+	if migrateAfter > 0 {
+		ch.MigrateAt = time.Now().Add(migrateAfter)
+	}
+
+	if c.pools.setDraining(poolName, n, true, ch.Started, ch.MigrateAt) == 0 {
+		return nil, fmt.Errorf("pool %q has no outside address in %s", poolName, ch.Prefix)
+	}
+	c.persistAddressChange(ctx, ch)
+
+	c.logger.Info("Draining CGNAT outside addresses", "pool", poolName, "prefix", ch.Prefix, "migrate_after", migrateAfter)
+	return c.pools.outsideAddresses(poolName, n), nil
+}
+
+// UndrainPoolAddress lets drained or draining addresses take new blocks
+// again. A drain is cancelled whole: address must cover every address
+// it was started on.
+func (c *Component) UndrainPoolAddress(ctx context.Context, poolName, address string) ([]models.CGNATOutsideAddress, error) {
+	n, err := parseAddressPrefix(address)
+	if err != nil {
+		return nil, err
+	}
+
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
+
+	var drains []string
+	for key, ch := range c.addrChanges {
+		if ch.Pool != poolName || ch.Action != models.CGNATAddressDrain {
+			continue
+		}
+		d, err := parseAddressPrefix(ch.Prefix)
+		if err != nil {
+			continue
+		}
+		if !prefixOverlaps(n, d) {
+			continue
+		}
+		if !prefixWithin(d, n) {
+			return nil, fmt.Errorf("%s is part of the drain of %s; undrain %s instead", n, ch.Prefix, ch.Prefix)
+		}
+		drains = append(drains, key)
+	}
+	if len(drains) == 0 {
+		return nil, fmt.Errorf("pool %q has no drain within %s", poolName, n)
+	}
+
+	for _, key := range drains {
+		d, _ := parseAddressPrefix(c.addrChanges[key].Prefix)
+		c.pools.setDraining(poolName, d, false, time.Time{}, time.Time{})
+		c.forgetAddressChange(ctx, key)
+	}
+
+	c.logger.Info("Cancelled CGNAT outside address drain", "pool", poolName, "prefix", n)
+	return c.pools.outsideAddresses(poolName, n), nil
+}
+
+// RemovePoolAddress takes a drained outside prefix out of a running
+// pool. address must be one of the pool's outside address entries, and
+// every address in it drained.
+func (c *Component) RemovePoolAddress(ctx context.Context, poolName, address string) error {
+	n, err := parseAddressPrefix(address)
+	if err != nil {
+		return err
+	}
+	prefix := n.String()
+
+	c.addrMu.Lock()
+	defer c.addrMu.Unlock()
+
+	pool := c.pools.poolConfig(poolName)
+	if pool == nil {
+		return fmt.Errorf("unknown pool %q", poolName)
+	}
+	if err := c.pools.checkRemovable(poolName, n); err != nil {
+		return err
+	}
+
+	poolID := c.poolIDMap[poolName]
+	if err := c.dataplane.CGNATPoolAddOutsideAddress(poolID, *n, false); err != nil && !isNoSuchEntry(err) {
+		return fmt.Errorf("remove outside address: %w", err)
+	}
+	c.pools.removeOutsideAddresses(poolName, n)
+
+	for key, ch := range c.addrChanges {
+		if ch.Pool != poolName || ch.Action != models.CGNATAddressDrain {
+			continue
+		}
+		if d, err := parseAddressPrefix(ch.Prefix); err == nil && prefixWithin(d, n) {
+			c.forgetAddressChange(ctx, key)
+		}
+	}
+	addKey := (&models.CGNATAddressChange{Pool: poolName, Action: models.CGNATAddressAdd, Prefix: prefix}).Key()
+	if !c.forgetAddressChange(ctx, addKey) {
+		c.persistAddressChange(ctx, &models.CGNATAddressChange{
+			Pool:    poolName,
+			Action:  models.CGNATAddressRemove,
+			Prefix:  prefix,
+			Started: time.Now(),
+		})
+	}
+	c.advertiseAddress(pool, prefix, false)
+
+	c.logger.Info("Removed CGNAT outside address", "pool", poolName, "prefix", prefix)
+	return nil
+}
+
+// OutsideAddresses returns the outside addresses of every pool, or of
+// one, with their state and how many blocks they hold. A non-empty
+// state keeps only the addresses in it.
+func (c *Component) OutsideAddresses(poolName, state string) ([]models.CGNATOutsideAddress, error) {
+	out := []models.CGNATOutsideAddress{}
+	if c == nil {
+		return out, nil
+	}
+	if poolName != "" && c.pools.poolConfig(poolName) == nil {
+		return nil, fmt.Errorf("unknown pool %q", poolName)
+	}
+	for _, a := range c.pools.outsideAddresses(poolName, nil) {
+		if state == "" || a.State == state {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// advertiseAddress originates or removes an outside prefix as the
+// configuration does for configured ones: from the default VRF, and
+// only without HA, where the SRG owns the advertisement.
+func (c *Component) advertiseAddress(pool *cgnat.Pool, prefix string, advertise bool) {
+	if c.routing == nil {
+		return
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.HA.Enabled || cfg.Protocols.BGP == nil {
+		return
+	}
+	asn := cfg.Protocols.BGP.ASN
+	if advertise {
+		err = c.routing.AdvertiseBGPNetworkPolicy(asn, "", prefix, pool.NetworkRoutePolicy, false)
+	} else {
+		err = c.routing.RemoveBGPNetwork(asn, "", prefix, false)
+	}
+	if err != nil {
+		c.logger.Error("Failed to update CGNAT outside prefix BGP network", "prefix", prefix, "advertise", advertise, "error", err)
+	}
+}
+
+func (c *Component) watchDrains() {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.checkDrains(now)
+		case <-c.Ctx.Done():
+			return
+		}
+	}
+}
+
+// checkDrains moves the blocks off draining addresses whose migration
+// window has passed and logs addresses that have finished draining.
+func (c *Component) checkDrains(now time.Time) {
+	moves, drained := c.pools.drainWork(now)
+	for _, addr := range drained {
+		c.logger.Info("CGNAT outside address drained", "pool", addr.pool, "address", addr.ip)
+	}
+	for _, m := range moves {
+		if err := c.migrateBlock(m); err != nil {
+			c.logger.Warn("Failed to migrate CGNAT block off draining address", "pool", m.pool,
+				"inside", m.insideIP, "outside", m.old.OutsideIP, "error", err)
+		}
+	}
+}
+
+// migrateBlock moves one subscriber block off a draining address. The
+// replacement is programmed before the old block is removed, so only
+// the translations on the old block are lost.
+func (c *Component) migrateBlock(m blockMove) error {
+	oldMapping, newMapping, first, err := c.pools.moveBlock(m.pool, m.insideIP, m.insideVRF, m.old)
+	if err != nil {
+		return err
+	}
+	oldMapping.SessionID = m.sessionID
+	newMapping.SessionID = m.sessionID
+
+	poolID := c.poolIDMap[m.pool]
+	if err := c.dataplane.CGNATAddDelSubscriberMapping(poolID, newMapping.SwIfIndex, m.insideIP, m.insideVRF,
+		newMapping.OutsideIP, newMapping.PortBlockStart, newMapping.PortBlockEnd, false, true); err != nil {
+		c.pools.moveBlockBack(m.pool, m.insideIP, m.insideVRF, newMapping, m.old)
+		return fmt.Errorf("add mapping: %w", err)
+	}
+	if err := c.dataplane.CGNATAddDelSubscriberMapping(poolID, oldMapping.SwIfIndex, m.insideIP, m.insideVRF,
+		oldMapping.OutsideIP, oldMapping.PortBlockStart, oldMapping.PortBlockEnd, false, false); err != nil {
+		c.logger.Error("remove mapping failed", "inside", m.insideIP, "error", err)
+	}
+
+	c.reverse.Remove(oldMapping.OutsideIP, oldMapping.PortBlockStart)
+	c.reverse.Add(newMapping)
+
+	srgName := c.sessionSRGName(context.Background(), m.sessionID)
+	c.deactivateForwards(m.sessionID, srgName)
+	c.publishMappingEvent(srgName, oldMapping, false)
+	c.publishMappingEvent(srgName, newMapping, true)
+	if first && c.opdb != nil && m.sessionID != "" {
+		if data, err := json.Marshal(newMapping); err == nil {
+			c.opdb.Put(context.Background(), opdbNamespace, m.sessionID, data)
+		}
+	} else if !first {
+		c.forgetExtraBlock(oldMapping)
+		c.persistExtraBlock(newMapping)
+	}
+	c.activateForwards(m.sessionID, srgName, m.insideIP)
+
+	c.logger.Info("Migrated CGNAT block off draining address", "pool", m.pool, "session", m.sessionID,
+		"inside", m.insideIP, "from", oldMapping.OutsideIP, "to", newMapping.OutsideIP)
+	return nil
+}
+
+func prefixWithin(inner, outer *net.IPNet) bool {
+	innerOnes, _ := inner.Mask.Size()
+	outerOnes, _ := outer.Mask.Size()
+	return innerOnes >= outerOnes && outer.Contains(inner.IP)
+}
+
+func prefixOverlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// blockMove is a subscriber block to move off a draining address.
+type blockMove struct {
+	pool      string
+	sessionID string
+	insideIP  net.IP
+	insideVRF uint32
+	old       blockAllocation
+}
+
+type drainedAddress struct {
+	pool string
+	ip   net.IP
+}
+
+func (pm *PoolManager) poolConfig(poolName string) *cgnat.Pool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	if ps, ok := pm.pools[poolName]; ok {
+		return ps.Config
+	}
+	return nil
+}
+
+// overlappingAddress returns an outside address of any pool within n.
+func (pm *PoolManager) overlappingAddress(n *net.IPNet) (string, net.IP) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	for name, ps := range pm.pools {
+		for _, addr := range ps.OutsideAddresses {
+			if n.Contains(addr.IP) {
+				return name, addr.IP
+			}
+		}
+	}
+	return "", nil
+}
+
+func (pm *PoolManager) addOutsideAddresses(poolName string, n *net.IPNet) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return fmt.Errorf("pool %s not found", poolName)
+	}
+	ips, err := expandCIDR(n.String())
+	if err != nil {
+		return fmt.Errorf("pool %s: invalid outside address %s: %w", poolName, n, err)
+	}
+
+	blocksPerAddr := ps.Config.GetPortRangeSize() / uint32(ps.Config.GetBlockSize())
+	for _, ip := range ips {
+		ps.OutsideAddresses = append(ps.OutsideAddresses, &outsideAddressState{
+			IP:            ip,
+			TotalBlocks:   blocksPerAddr,
+			AllocatedBits: make([]uint64, (blocksPerAddr+63)/64),
+		})
+	}
+	ps.prefixes = append(ps.prefixes, n.String())
+	return nil
+}
+
+func (pm *PoolManager) markRuntime(poolName string, n *net.IPNet) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if ps, ok := pm.pools[poolName]; ok {
+		for _, addr := range ps.OutsideAddresses {
+			if n.Contains(addr.IP) {
+				addr.Runtime = true
+			}
+		}
+	}
+}
+
+// setDraining starts or cancels the drain of the pool's addresses
+// within n, returning how many there are.
+func (pm *PoolManager) setDraining(poolName string, n *net.IPNet, draining bool, started, migrateAt time.Time) int {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return 0
+	}
+	var count int
+	for _, addr := range ps.OutsideAddresses {
+		if !n.Contains(addr.IP) {
+			continue
+		}
+		addr.Draining = draining
+		addr.DrainStarted = started
+		addr.MigrateAt = migrateAt
+		addr.drainedLogged = false
+		count++
+	}
+	return count
+}
+
+// checkRemovable checks that n is one of the pool's outside address
+// entries and that every address in it has drained.
+func (pm *PoolManager) checkRemovable(poolName string, n *net.IPNet) error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return fmt.Errorf("pool %s not found", poolName)
+	}
+	var entry bool
+	for _, p := range ps.prefixes {
+		if p == n.String() {
+			entry = true
+			break
+		}
+	}
+	if !entry {
+		return fmt.Errorf("%s is not an outside address entry of pool %q", n, poolName)
+	}
+	for _, addr := range ps.OutsideAddresses {
+		if !n.Contains(addr.IP) {
+			continue
+		}
+		if !addr.Draining {
+			return fmt.Errorf("%s is not draining; drain it first", addr.IP)
+		}
+		if blocks := addr.allocatedBlocks(); blocks > 0 {
+			return fmt.Errorf("%s still holds %d block(s)", addr.IP, blocks)
+		}
+	}
+	return nil
+}
+
+func (pm *PoolManager) removeOutsideAddresses(poolName string, n *net.IPNet) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return
+	}
+	kept := ps.OutsideAddresses[:0]
+	for _, addr := range ps.OutsideAddresses {
+		if !n.Contains(addr.IP) {
+			kept = append(kept, addr)
+		}
+	}
+	ps.OutsideAddresses = kept
+	prefixes := ps.prefixes[:0]
+	for _, p := range ps.prefixes {
+		if p != n.String() {
+			prefixes = append(prefixes, p)
+		}
+	}
+	ps.prefixes = prefixes
+}
+
+// outsideAddresses describes the addresses of one pool, or of every
+// pool when poolName is empty, within n, or all of them when n is nil.
+func (pm *PoolManager) outsideAddresses(poolName string, n *net.IPNet) []models.CGNATOutsideAddress {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	names := make([]string, 0, len(pm.pools))
+	for name := range pm.pools {
+		if poolName == "" || name == poolName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := []models.CGNATOutsideAddress{}
+	for _, name := range names {
+		ps := pm.pools[name]
+		if ps.Config.GetMode() != "pba" {
+			continue
+		}
+		subscribers := make(map[string]uint32)
+		for _, sub := range ps.Subscribers {
+			seen := make(map[string]bool, len(sub.Blocks))
+			for _, b := range sub.Blocks {
+				ip := b.OutsideIP.String()
+				if !seen[ip] {
+					seen[ip] = true
+					subscribers[ip]++
+				}
+			}
+		}
+		for _, addr := range ps.OutsideAddresses {
+			if n != nil && !n.Contains(addr.IP) {
+				continue
+			}
+			out = append(out, models.CGNATOutsideAddress{
+				PoolName:        name,
+				Address:         addr.IP,
+				State:           addr.state(),
+				Runtime:         addr.Runtime,
+				Excluded:        addr.Excluded,
+				TotalBlocks:     addr.TotalBlocks,
+				AllocatedBlocks: addr.allocatedBlocks(),
+				Subscribers:     subscribers[addr.IP.String()],
+				DrainStarted:    addr.DrainStarted,
+				MigrateAt:       addr.MigrateAt,
+			})
+		}
+	}
+	return out
+}
+
+// drainWork returns the blocks due to move off draining addresses and
+// the addresses that have drained since the last call.
+func (pm *PoolManager) drainWork(now time.Time) ([]blockMove, []drainedAddress) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	var moves []blockMove
+	var drained []drainedAddress
+	for _, ps := range pm.pools {
+		due := make(map[string]bool)
+		for _, addr := range ps.OutsideAddresses {
+			if !addr.Draining {
+				continue
+			}
+			if addr.allocatedBlocks() == 0 {
+				if !addr.drainedLogged {
+					addr.drainedLogged = true
+					drained = append(drained, drainedAddress{pool: ps.Name, ip: addr.IP})
+				}
+				continue
+			}
+			if !addr.MigrateAt.IsZero() && !now.Before(addr.MigrateAt) {
+				due[addr.IP.String()] = true
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+		for key, sub := range ps.Subscribers {
+			for _, b := range sub.Blocks {
+				if !due[b.OutsideIP.String()] {
+					continue
+				}
+				moves = append(moves, blockMove{
+					pool:      ps.Name,
+					sessionID: sub.SessionID,
+					insideIP:  subscriberIP(append(net.IP(nil), key.InsideIP[:]...)),
+					insideVRF: key.InsideVRF,
+					old:       b,
+				})
+			}
+		}
+	}
+	return moves, drained
+}
+
+// moveBlock replaces a subscriber's block with one on an address that
+// takes new blocks, preferring, for paired pools, one the subscriber
+// already holds a block on. first reports whether the block was the
+// subscriber's first, the one persisted for its session.
+func (pm *PoolManager) moveBlock(poolName string, insideIP net.IP, insideVRF uint32, old blockAllocation) (oldMapping, newMapping *models.CGNATMapping, first bool, err error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("pool %s not found", poolName)
+	}
+	sub, ok := ps.Subscribers[makeSubscriberKey(insideVRF, insideIP)]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("subscriber %s no longer holds blocks", insideIP)
+	}
+	idx := -1
+	for i, b := range sub.Blocks {
+		if b.PortBlockStart == old.PortBlockStart && b.OutsideIP.Equal(old.OutsideIP) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, nil, false, fmt.Errorf("subscriber %s no longer holds %s:%d", insideIP, old.OutsideIP, old.PortBlockStart)
+	}
+
+	var target *outsideAddressState
+	if ps.Config.GetAddressPooling() == "paired" {
+		for _, b := range sub.Blocks {
+			if addr := ps.findAddress(b.OutsideIP); addr != nil && addr.allocatable() && hasFreeBlock(addr) {
+				target = addr
+				break
+			}
+		}
+	}
+	if target == nil {
+		for _, addr := range ps.OutsideAddresses {
+			if addr.allocatable() && hasFreeBlock(addr) {
+				target = addr
+				break
+			}
+		}
+	}
+	if target == nil {
+		return nil, nil, false, fmt.Errorf("pool %s: no free blocks available", poolName)
+	}
+	blockIdx := allocateBlock(target)
+	if blockIdx < 0 {
+		return nil, nil, false, fmt.Errorf("pool %s: block allocation failed on %s", poolName, target.IP)
+	}
+
+	blockSize := ps.Config.GetBlockSize()
+	portStart := ps.Config.GetPortRangeStart()
+	block := blockAllocation{
+		OutsideIP:      append(net.IP(nil), target.IP.To4()...),
+		PortBlockStart: portStart + uint16(blockIdx)*blockSize,
+	}
+	block.PortBlockEnd = block.PortBlockStart + blockSize - 1
+	if addr := ps.findAddress(old.OutsideIP); addr != nil {
+		freeBlock(addr, int(old.PortBlockStart-portStart)/int(blockSize))
+	}
+	sub.Blocks[idx] = block
+
+	oldMapping, newMapping = ps.mapping(insideIP, insideVRF, sub, old), ps.mapping(insideIP, insideVRF, sub, block)
+	oldMapping.Extra, newMapping.Extra = idx > 0, idx > 0
+	return oldMapping, newMapping, idx == 0, nil
+}
+
+// moveBlockBack undoes a moveBlock whose replacement the dataplane
+// refused.
+func (pm *PoolManager) moveBlockBack(poolName string, insideIP net.IP, insideVRF uint32, moved *models.CGNATMapping, old blockAllocation) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[poolName]
+	if !ok {
+		return
+	}
+	sub, ok := ps.Subscribers[makeSubscriberKey(insideVRF, insideIP)]
+	if !ok {
+		return
+	}
+	blockSize := ps.Config.GetBlockSize()
+	portStart := ps.Config.GetPortRangeStart()
+	for i, b := range sub.Blocks {
+		if b.PortBlockStart != moved.PortBlockStart || !b.OutsideIP.Equal(moved.OutsideIP) {
+			continue
+		}
+		if addr := ps.findAddress(b.OutsideIP); addr != nil {
+			freeBlock(addr, int(b.PortBlockStart-portStart)/int(blockSize))
+		}
+		if addr := ps.findAddress(old.OutsideIP); addr != nil {
+			blockIdx := int(old.PortBlockStart-portStart) / int(blockSize)
+			if word := blockIdx / 64; word < len(addr.AllocatedBits) {
+				addr.AllocatedBits[word] |= 1 << uint(blockIdx%64)
+			}
+		}
+		sub.Blocks[i] = old
+		return
+	}
+}
+
+func (ps *poolState) findAddress(ip net.IP) *outsideAddressState {
+	for _, addr := range ps.OutsideAddresses {
+		if addr.IP.Equal(ip) {
+			return addr
+		}
+	}
+	return nil
+}
+
+func (ps *poolState) mapping(insideIP net.IP, insideVRF uint32, sub *subscriberAllocation, b blockAllocation) *models.CGNATMapping {
+	return &models.CGNATMapping{
+		PoolName:       ps.Name,
+		PoolID:         ps.ID,
+		InsideIP:       subscriberIP(insideIP),
+		InsideVRFID:    insideVRF,
+		OutsideIP:      b.OutsideIP,
+		PortBlockStart: b.PortBlockStart,
+		PortBlockEnd:   b.PortBlockEnd,
+		SwIfIndex:      sub.SwIfIndex,
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/ifmgr"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// newAddressComponent returns a component for pool p1, 100.64.0.0/30,
+// with session s1 holding one block for 10.0.0.5, and the channel its
+// mapping events arrive on.
+func newAddressComponent(t *testing.T) (*Component, chan *events.CGNATMappingEvent) {
+	t.Helper()
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, pbaConfig())
+	c.ifMgr = ifmgr.New()
+	bus := local.NewBus()
+	c.eventBus = bus
+	mappingCh := make(chan *events.CGNATMappingEvent, 8)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		mappingCh <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	block, err := c.pools.AllocateBlock("p1", net.ParseIP("10.0.0.5").To4(), 0, 17)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	block.SessionID = "s1"
+	c.commitMapping("s1", "p1", block, "", true)
+	nextMappingEvent(t, mappingCh)
+	return c, mappingCh
+}
+
+func addressStates(t *testing.T, c *Component) map[string]models.CGNATOutsideAddress {
+	t.Helper()
+	addrs, err := c.OutsideAddresses("p1", "")
+	if err != nil {
+		t.Fatalf("OutsideAddresses: %v", err)
+	}
+	out := make(map[string]models.CGNATOutsideAddress, len(addrs))
+	for _, a := range addrs {
+		out[a.Address.String()] = a
+	}
+	return out
+}
+
+func TestPoolAddress_DrainAndMigrate(t *testing.T) {
+	c, mappingCh := newAddressComponent(t)
+	ctx := context.Background()
+
+	if _, err := c.DrainPoolAddress(ctx, "p1", "100.64.0.0", time.Hour); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	states := addressStates(t, c)
+	if a := states["100.64.0.0"]; a.State != models.CGNATAddressDraining || a.AllocatedBlocks != 1 || a.Subscribers != 1 {
+		t.Fatalf("drained address = %+v", a)
+	}
+
+	// New blocks go to the other addresses.
+	block, err := c.pools.AllocateBlock("p1", net.ParseIP("10.0.0.6").To4(), 0, 18)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	if block.OutsideIP.Equal(net.ParseIP("100.64.0.0")) {
+		t.Fatal("block allocated on a draining address")
+	}
+
+	if err := c.RemovePoolAddress(ctx, "p1", "100.64.0.0/30"); err == nil || !strings.Contains(err.Error(), "still holds") {
+		t.Fatalf("remove of a partly drained entry = %v", err)
+	}
+
+	// Nothing moves before the migration window has passed.
+	c.checkDrains(time.Now())
+	if got := c.pools.GetMappings("p1", net.ParseIP("10.0.0.5").To4(), 0); got[0].OutsideIP.String() != "100.64.0.0" {
+		t.Fatalf("block moved early to %s", got[0].OutsideIP)
+	}
+
+	c.checkDrains(time.Now().Add(2 * time.Hour))
+	var del, add *events.CGNATMappingEvent
+	for i := 0; i < 2; i++ {
+		if ev := nextMappingEvent(t, mappingCh); ev.IsAdd {
+			add = ev
+		} else {
+			del = ev
+		}
+	}
+	if del == nil || del.Mapping.OutsideIP.String() != "100.64.0.0" || del.Mapping.SessionID != "s1" {
+		t.Fatalf("release = %+v", del)
+	}
+	if add == nil || add.Mapping.OutsideIP.String() == "100.64.0.0" || add.Mapping.SessionID != "s1" {
+		t.Fatalf("replacement = %+v", add)
+	}
+	if got := c.pools.GetMappings("p1", net.ParseIP("10.0.0.5").To4(), 0); len(got) != 1 || !got[0].OutsideIP.Equal(add.Mapping.OutsideIP) {
+		t.Fatalf("mappings = %+v", got)
+	}
+	if c.reverse.Lookup(net.ParseIP("100.64.0.0").To4(), 1024) != nil {
+		t.Fatal("reverse index still has the drained block")
+	}
+	if a := addressStates(t, c)["100.64.0.0"]; a.State != models.CGNATAddressDrained {
+		t.Fatalf("state after migration = %q", a.State)
+	}
+
+	if _, err := c.UndrainPoolAddress(ctx, "p1", "100.64.0.0"); err != nil {
+		t.Fatalf("undrain: %v", err)
+	}
+	if a := addressStates(t, c)["100.64.0.0"]; a.State != models.CGNATAddressActive {
+		t.Fatalf("state after undrain = %q", a.State)
+	}
+	if len(c.opdb.(*fakeOpDB).ns[addressNamespace]) != 0 {
+		t.Fatal("undrained drain still persisted")
+	}
+}
+
+func TestPoolAddress_AddAndRemove(t *testing.T) {
+	c, _ := newAddressComponent(t)
+	ctx := context.Background()
+
+	if _, err := c.AddPoolAddress(ctx, "p1", "100.64.0.2"); err == nil {
+		t.Fatal("added an address already in the pool")
+	}
+	added, err := c.AddPoolAddress(ctx, "p1", "100.64.1.0/31")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if len(added) != 2 || !added[0].Runtime || added[0].State != models.CGNATAddressActive {
+		t.Fatalf("added = %+v", added)
+	}
+	if len(addressStates(t, c)) != 6 {
+		t.Fatal("pool did not grow")
+	}
+
+	if err := c.RemovePoolAddress(ctx, "p1", "100.64.1.0/31"); err == nil {
+		t.Fatal("removed an address that was not draining")
+	}
+	if _, err := c.DrainPoolAddress(ctx, "p1", "100.64.1.0/31", 0); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if err := c.RemovePoolAddress(ctx, "p1", "100.64.1.0/31"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if len(addressStates(t, c)) != 4 {
+		t.Fatal("pool did not shrink")
+	}
+	// Adding and removing a prefix leaves nothing to keep.
+	if n := len(c.opdb.(*fakeOpDB).ns[addressNamespace]); n != 0 {
+		t.Fatalf("%d changes persisted, want 0", n)
+	}
+}
+
+func TestPoolAddress_ChangesSurviveRestart(t *testing.T) {
+	c, _ := newAddressComponent(t)
+	ctx := context.Background()
+	cfg := pbaConfig()
+
+	if _, err := c.AddPoolAddress(ctx, "p1", "100.64.1.0/31"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := c.DrainPoolAddress(ctx, "p1", "100.64.0.1", 0); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+
+	restarted := newRestoreComponent(t, &fakeDP{}, c.opdb.(*fakeOpDB), &fakeProvider{}, cfg)
+	changes := restarted.loadAddressChanges(ctx, cfg.CGNAT)
+	if len(changes) != 2 {
+		t.Fatalf("loaded %d changes, want 2", len(changes))
+	}
+	effective := withAddressChanges(cfg, changes)
+	if got := effective.CGNAT.Pools["p1"].OutsideAddresses; len(got) != 2 || got[1] != "100.64.1.0/31" {
+		t.Fatalf("effective outside addresses = %v", got)
+	}
+	if len(cfg.CGNAT.Pools["p1"].OutsideAddresses) != 1 {
+		t.Fatal("running config modified")
+	}
+
+	if err := restarted.pools.ConfigurePool("p1", 1, effective.CGNAT.Pools["p1"]); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	restarted.applyAddressState(changes)
+	states := addressStates(t, restarted)
+	if a := states["100.64.1.1"]; !a.Runtime || a.State != models.CGNATAddressActive {
+		t.Fatalf("added address = %+v", a)
+	}
+	if a := states["100.64.0.1"]; a.State != models.CGNATAddressDrained {
+		t.Fatalf("drained address = %+v", a)
+	}
+
+	// Once the configuration has the prefix the add is dropped.
+	cfg.CGNAT.Pools["p1"].OutsideAddresses = append(cfg.CGNAT.Pools["p1"].OutsideAddresses, "100.64.1.0/31")
+	if changes := restarted.loadAddressChanges(ctx, cfg.CGNAT); len(changes) != 1 || changes[0].Action != models.CGNATAddressDrain {
+		t.Fatalf("changes after config caught up = %+v", changes)
+	}
+}
@@ -0,0 +1,465 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+const (
+	archivePrefix        = "mappings-"
+	archiveSuffix        = ".jsonl.gz"
+	archiveDateLayout    = "2006-01-02"
+	archiveSegmentLayout = "2006-01-02T150405.000Z"
+)
+
+// mappingArchive is the append-only record of port block allocations
+// and releases, as gzip-compressed JSON lines. A new segment file is
+// opened at each start and at UTC midnight, named by the time it was
+// opened, so a segment is only ever written by one writer. Once seeded,
+// each segment opens with a snapshot of the blocks held at that time, so
+// a lookup need not read back past the segment covering it. Every
+// record is flushed, so a crash loses at most the record being written
+// and leaves a segment that reads up to that point.
+type mappingArchive struct {
+	dir       string
+	retention uint32
+	maxBytes  int64
+	logger    *logger.Logger
+	now       func() time.Time
+
+	mu   sync.Mutex
+	day  string
+	file *os.File
+	gz   *gzip.Writer
+	// active holds the allocation of every block held, keyed by
+	// outside address and block start. It is only complete, and
+	// snapshots are only written, once seeded.
+	active map[archiveBlockKey]*models.CGNATArchiveRecord
+	seeded bool
+}
+
+type archiveBlockKey struct {
+	ip    string
+	start uint16
+}
+
+func archiveKey(rec *models.CGNATArchiveRecord) archiveBlockKey {
+	return archiveBlockKey{ip: rec.OutsideIP.String(), start: rec.PortBlockStart}
+}
+
+func newMappingArchive(cfg *cgnat.ArchiveConfig, log *logger.Logger) (*mappingArchive, error) {
+	a := &mappingArchive{
+		dir:       cfg.GetDirectory(),
+		retention: cfg.GetRetentionDays(),
+		maxBytes:  int64(cfg.MaxSizeMB) << 20,
+		logger:    log,
+		now:       time.Now,
+		active:    make(map[archiveBlockKey]*models.CGNATArchiveRecord),
+	}
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	return a, nil
+}
+
+// open seeds the active blocks with those held at start, which carry
+// no allocation time, and opens a segment headed by their snapshot.
+func (a *mappingArchive) open(held []*models.CGNATArchiveRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, rec := range held {
+		if _, ok := a.active[archiveKey(rec)]; !ok {
+			a.active[archiveKey(rec)] = rec
+		}
+	}
+	a.seeded = true
+	now := a.now().UTC()
+	return a.rotateLocked(now.Format(archiveDateLayout), now)
+}
+
+// append writes one record to the current segment, rotating at UTC
+// midnight. A record stamped before the current segment's day, delivered
+// late, goes to the current segment; lookups order records by time.
+func (a *mappingArchive) append(rec *models.CGNATArchiveRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if day := rec.Time.UTC().Format(archiveDateLayout); day > a.day || a.gz == nil {
+		if err := a.rotateLocked(day, rec.Time); err != nil {
+			return err
+		}
+	}
+	if err := a.writeLocked(rec); err != nil {
+		return err
+	}
+	switch rec.Event {
+	case models.CGNATArchiveAllocate:
+		a.active[archiveKey(rec)] = rec
+	case models.CGNATArchiveRelease:
+		delete(a.active, archiveKey(rec))
+	}
+	return a.gz.Flush()
+}
+
+func (a *mappingArchive) writeLocked(rec *models.CGNATArchiveRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = a.gz.Write(append(data, '\n'))
+	return err
+}
+
+func (a *mappingArchive) rotateLocked(day string, opened time.Time) error {
+	a.closeLocked()
+	name := archivePrefix + opened.UTC().Format(archiveSegmentLayout) + archiveSuffix
+	f, err := os.OpenFile(filepath.Join(a.dir, name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o640)
+	if err != nil {
+		return fmt.Errorf("open archive file: %w", err)
+	}
+	a.day, a.file, a.gz = day, f, gzip.NewWriter(f)
+	if a.seeded {
+		if err := a.snapshotLocked(opened.UTC()); err != nil {
+			return fmt.Errorf("write archive snapshot: %w", err)
+		}
+	}
+	a.pruneLocked()
+	return nil
+}
+
+// snapshotLocked writes the snapshot marker and an active record for
+// every held block at the head of a new segment.
+func (a *mappingArchive) snapshotLocked(at time.Time) error {
+	if err := a.writeLocked(&models.CGNATArchiveRecord{Time: at, Event: models.CGNATArchiveSnapshot}); err != nil {
+		return err
+	}
+	for _, held := range a.active {
+		rec := *held
+		rec.Time, rec.Event, rec.AllocatedAt = at, models.CGNATArchiveActive, held.AllocatedAt
+		if held.Event == models.CGNATArchiveAllocate && !held.Time.IsZero() {
+			allocated := held.Time
+			rec.AllocatedAt = &allocated
+		}
+		if err := a.writeLocked(&rec); err != nil {
+			return err
+		}
+	}
+	return a.gz.Flush()
+}
+
+func (a *mappingArchive) closeLocked() {
+	if a.gz != nil {
+		a.gz.Close()
+		a.gz = nil
+	}
+	if a.file != nil {
+		a.file.Close()
+		a.file = nil
+	}
+}
+
+func (a *mappingArchive) close() {
+	a.mu.Lock()
+	a.closeLocked()
+	a.mu.Unlock()
+}
+
+// archiveFile is one segment, the time it was opened and the UTC day
+// it holds.
+type archiveFile struct {
+	day    string
+	opened time.Time
+	path   string
+	size   int64
+}
+
+// files returns the archive's segments, oldest first.
+func (a *mappingArchive) files() ([]archiveFile, error) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []archiveFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, archivePrefix) || !strings.HasSuffix(name, archiveSuffix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, archivePrefix), archiveSuffix)
+		opened, err := time.Parse(archiveSegmentLayout, stamp)
+		if err != nil {
+			continue
+		}
+		day := stamp[:len(archiveDateLayout)]
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, archiveFile{day: day, opened: opened, path: filepath.Join(a.dir, name), size: info.Size()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// pruneLocked deletes segments of days past the retention period, then
+// the oldest segments while the archive exceeds its size limit. The
+// current segment is never deleted.
+func (a *mappingArchive) pruneLocked() {
+	files, err := a.files()
+	if err != nil {
+		a.logger.Warn("Failed to list CGNAT archive", "dir", a.dir, "error", err)
+		return
+	}
+	cutoff := a.now().UTC().AddDate(0, 0, -int(a.retention)).Format(archiveDateLayout)
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	current := ""
+	if a.file != nil {
+		current = a.file.Name()
+	}
+	for _, f := range files {
+		if f.path == current {
+			break
+		}
+		if f.day >= cutoff && (a.maxBytes == 0 || total <= a.maxBytes) {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			a.logger.Warn("Failed to delete CGNAT archive file", "file", f.path, "error", err)
+			continue
+		}
+		total -= f.size
+		a.logger.Info("Deleted CGNAT archive file", "file", f.path)
+	}
+}
+
+// lookup returns the allocation that held ip:port at the given time, or
+// nil when the archive shows the port unallocated then. Segments are
+// read newest first, from the first one opened after at, which may hold
+// records delivered late, back to one whose snapshot covers at; the
+// latest event at or before at for a block containing the port decides.
+// The lock is not held while reading: segments are only appended to,
+// and one removed by pruning meanwhile is skipped.
+func (a *mappingArchive) lookup(ip net.IP, port uint16, at time.Time) (*models.CGNATArchiveRecord, error) {
+	files, err := a.files()
+	if err != nil {
+		return nil, err
+	}
+	var last *models.CGNATArchiveRecord
+	for i := len(files) - 1; i >= 0; i-- {
+		if i > 0 && files[i-1].opened.After(at) {
+			continue
+		}
+		rec, snapshot, err := lastArchiveEvent(files[i].path, ip, port, at)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil && (last == nil || rec.Time.After(last.Time)) {
+			last = rec
+		}
+		if !files[i].opened.After(at) && (snapshot || last != nil) {
+			break
+		}
+	}
+	if last == nil || (last.Event != models.CGNATArchiveAllocate && last.Event != models.CGNATArchiveActive) {
+		return nil, nil
+	}
+	return last, nil
+}
+
+// lastArchiveEvent scans one segment for the latest event at or before
+// at whose block contains ip:port, and reports whether the segment's
+// snapshot was taken by then. A truncated end, left by a crash or by the
+// segment still being written, ends the scan without error.
+func lastArchiveEvent(path string, ip net.IP, port uint16, at time.Time) (*models.CGNATArchiveRecord, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	defer zr.Close()
+
+	var last *models.CGNATArchiveRecord
+	snapshot := false
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var rec models.CGNATArchiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if rec.Time.After(at) {
+			continue
+		}
+		if rec.Event == models.CGNATArchiveSnapshot {
+			snapshot = true
+			continue
+		}
+		if !rec.OutsideIP.Equal(ip) || port < rec.PortBlockStart || port > rec.PortBlockEnd {
+			continue
+		}
+		if last == nil || !rec.Time.Before(last.Time) {
+			last = &rec
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, false, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return last, snapshot, nil
+}
+
+// startArchive opens the mapping archive and records every block
+// mapping event from here on. Forward events are skipped: the block
+// holding a forward's port already names its subscriber.
+func (c *Component) startArchive(cfg *cgnat.ArchiveConfig) error {
+	a, err := newMappingArchive(cfg, c.logger)
+	if err != nil {
+		return err
+	}
+	c.archive = a
+	c.archiveSub = c.eventBus.Subscribe(events.TopicCGNATMapping, c.archiveMapping)
+	c.logger.Info("CGNAT mapping archive enabled", "dir", a.dir, "retention_days", a.retention)
+	return nil
+}
+
+// openArchive seeds the archive with the blocks held after restore and
+// opens the first segment of this run, headed by their snapshot.
+func (c *Component) openArchive() error {
+	mappings := c.pools.GetAllMappings()
+	held := make([]*models.CGNATArchiveRecord, 0, len(mappings))
+	for i := range mappings {
+		m := &mappings[i]
+		rec := &models.CGNATArchiveRecord{
+			Event:          models.CGNATArchiveActive,
+			PoolName:       m.PoolName,
+			OutsideIP:      m.OutsideIP,
+			PortBlockStart: m.PortBlockStart,
+			PortBlockEnd:   m.PortBlockEnd,
+			InsideIP:       m.InsideIP,
+			InsideVRFID:    m.InsideVRFID,
+			SessionID:      m.SessionID,
+		}
+		c.archiveSubscriber(rec)
+		held = append(held, rec)
+	}
+	return c.archive.open(held)
+}
+
+// archiveSubscriber fills the username and MAC of the record's session.
+func (c *Component) archiveSubscriber(rec *models.CGNATArchiveRecord) {
+	if c.sessionProvider == nil || rec.SessionID == "" {
+		return
+	}
+	if sess, ok := c.sessionProvider.SessionSnapshot(context.Background(), rec.SessionID); ok {
+		rec.Username = sess.GetUsername()
+		if mac := sess.GetMAC(); mac != nil {
+			rec.MAC = mac.String()
+		}
+	}
+}
+
+func (c *Component) archiveMapping(ev events.Event) {
+	data, ok := ev.Data.(*events.CGNATMappingEvent)
+	if !ok || data.Mapping == nil || data.Mapping.Forward != nil {
+		return
+	}
+	m := data.Mapping
+	at := ev.Timestamp
+	if at.IsZero() {
+		at = c.archive.now()
+	}
+	rec := &models.CGNATArchiveRecord{
+		Time:           at.UTC(),
+		Event:          models.CGNATArchiveRelease,
+		PoolName:       m.PoolName,
+		OutsideIP:      m.OutsideIP,
+		PortBlockStart: m.PortBlockStart,
+		PortBlockEnd:   m.PortBlockEnd,
+		InsideIP:       m.InsideIP,
+		InsideVRFID:    m.InsideVRFID,
+		SessionID:      m.SessionID,
+	}
+	if data.IsAdd {
+		rec.Event = models.CGNATArchiveAllocate
+		c.archiveSubscriber(rec)
+	}
+	if err := c.archive.append(rec); err != nil {
+		c.logger.Warn("Failed to archive CGNAT mapping", "session", m.SessionID, "error", err)
+	}
+}
+
+// LookupMappingOwner answers who held an outside address and port at a
+// point in time: arithmetically for deterministic pools, otherwise from
+// the mapping archive.
+func (c *Component) LookupMappingOwner(ip net.IP, port uint16, at time.Time) (*models.CGNATMappingOwner, error) {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil {
+		return nil, fmt.Errorf("no CGNAT configuration")
+	}
+	if owner := deterministicLookup(cfg.CGNAT, ip, port); owner != nil {
+		return owner, nil
+	}
+	if c.archive == nil {
+		return nil, fmt.Errorf("mapping archive is not enabled")
+	}
+	rec, err := c.archive.lookup(ip, port, at)
+	if err != nil {
+		return nil, fmt.Errorf("search archive: %w", err)
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("no archived allocation held %s:%d at %s", ip, port, at.UTC().Format(time.RFC3339))
+	}
+	allocated := &rec.Time
+	if rec.Event == models.CGNATArchiveActive {
+		allocated = rec.AllocatedAt
+	}
+	return &models.CGNATMappingOwner{
+		Method:         "archive",
+		PoolName:       rec.PoolName,
+		OutsideIP:      rec.OutsideIP,
+		OutsidePort:    port,
+		PortBlockStart: rec.PortBlockStart,
+		PortBlockEnd:   rec.PortBlockEnd,
+		InsideIP:       rec.InsideIP,
+		InsideVRFID:    rec.InsideVRFID,
+		SessionID:      rec.SessionID,
+		Username:       rec.Username,
+		MAC:            rec.MAC,
+		AllocatedAt:    allocated,
+	}, nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func archiveRecord(at time.Time, event, sessionID string) *models.CGNATArchiveRecord {
+	return &models.CGNATArchiveRecord{
+		Time:           at,
+		Event:          event,
+		PoolName:       "p1",
+		OutsideIP:      net.ParseIP("100.64.0.1").To4(),
+		PortBlockStart: 1024,
+		PortBlockEnd:   1087,
+		InsideIP:       net.ParseIP("10.0.0.5").To4(),
+		SessionID:      sessionID,
+	}
+}
+
+func TestMappingArchive_LookupAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	a, err := newMappingArchive(&cgnat.ArchiveConfig{Directory: dir}, logger.Get("cgnat-test"))
+	if err != nil {
+		t.Fatalf("newMappingArchive: %v", err)
+	}
+	a.now = func() time.Time { return day2 }
+	for _, rec := range []*models.CGNATArchiveRecord{
+		archiveRecord(day1.Add(10*time.Hour), models.CGNATArchiveAllocate, "s1"),
+		archiveRecord(day1.Add(12*time.Hour), models.CGNATArchiveRelease, "s1"),
+		archiveRecord(day1.Add(13*time.Hour), models.CGNATArchiveAllocate, "s2"),
+	} {
+		if err := a.append(rec); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	a.close()
+
+	// A restart opens a new segment rather than appending to the old one.
+	a, err = newMappingArchive(&cgnat.ArchiveConfig{Directory: dir}, logger.Get("cgnat-test"))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	a.now = func() time.Time { return day2 }
+	if err := a.append(archiveRecord(day2.Add(9*time.Hour), models.CGNATArchiveRelease, "s2")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, archivePrefix+"*")); len(files) != 2 {
+		t.Fatalf("segments = %v, want 2", files)
+	}
+
+	ip := net.ParseIP("100.64.0.1")
+	cases := []struct {
+		name    string
+		port    uint16
+		at      time.Time
+		session string
+	}{
+		{"before any allocation", 1030, day1.Add(9 * time.Hour), ""},
+		{"first holder", 1030, day1.Add(11 * time.Hour), "s1"},
+		{"between holders", 1030, day1.Add(12*time.Hour + 30*time.Minute), ""},
+		{"held across midnight", 1087, day2.Add(8 * time.Hour), "s2"},
+		{"after release", 1030, day2.Add(10 * time.Hour), ""},
+		{"other block", 1088, day1.Add(11 * time.Hour), ""},
+	}
+	for _, tc := range cases {
+		rec, err := a.lookup(ip, tc.port, tc.at)
+		if err != nil {
+			t.Fatalf("%s: lookup: %v", tc.name, err)
+		}
+		got := ""
+		if rec != nil {
+			got = rec.SessionID
+		}
+		if got != tc.session {
+			t.Errorf("%s: session = %q, want %q", tc.name, got, tc.session)
+		}
+	}
+	a.close()
+}
+
+func TestMappingArchive_SnapshotAtRotation(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+
+	a, err := newMappingArchive(&cgnat.ArchiveConfig{Directory: dir}, logger.Get("cgnat-test"))
+	if err != nil {
+		t.Fatalf("newMappingArchive: %v", err)
+	}
+	a.now = func() time.Time { return day1 }
+	restored := archiveRecord(time.Time{}, models.CGNATArchiveActive, "s0")
+	restored.PortBlockStart, restored.PortBlockEnd = 2048, 2111
+	if err := a.open([]*models.CGNATArchiveRecord{restored}); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, rec := range []*models.CGNATArchiveRecord{
+		archiveRecord(day1.Add(2*time.Hour), models.CGNATArchiveAllocate, "s1"),
+		archiveRecord(day1.Add(3*time.Hour), models.CGNATArchiveRelease, "s1"),
+		archiveRecord(day1.Add(4*time.Hour), models.CGNATArchiveAllocate, "s2"),
+	} {
+		if err := a.append(rec); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	other := archiveRecord(day2, models.CGNATArchiveAllocate, "s3")
+	other.PortBlockStart, other.PortBlockEnd = 4096, 4159
+	if err := a.append(other); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	a.close()
+
+	// With the first day's segment pruned, the second segment's
+	// snapshot still answers for the blocks held across midnight.
+	files, err := a.files()
+	if err != nil || len(files) != 2 {
+		t.Fatalf("segments = %v, %v", files, err)
+	}
+	if err := os.Remove(files[0].path); err != nil {
+		t.Fatal(err)
+	}
+
+	ip := net.ParseIP("100.64.0.1")
+	rec, err := a.lookup(ip, 1030, day2.Add(time.Hour))
+	if err != nil || rec == nil || rec.SessionID != "s2" || rec.Event != models.CGNATArchiveActive {
+		t.Fatalf("held across midnight = %+v, %v", rec, err)
+	}
+	if rec.AllocatedAt == nil || !rec.AllocatedAt.Equal(day1.Add(4*time.Hour)) {
+		t.Fatalf("allocated at = %v", rec.AllocatedAt)
+	}
+	rec, err = a.lookup(ip, 2050, day2.Add(time.Hour))
+	if err != nil || rec == nil || rec.SessionID != "s0" || rec.AllocatedAt != nil {
+		t.Fatalf("held since start = %+v, %v", rec, err)
+	}
+	if rec, err := a.lookup(ip, 3000, day2.Add(time.Hour)); err != nil || rec != nil {
+		t.Fatalf("unallocated block = %+v, %v", rec, err)
+	}
+}
+
+func TestMappingArchive_OrdersRecordsByTime(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 3, 2, 0, 0, 1, 0, time.UTC)
+
+	a, err := newMappingArchive(&cgnat.ArchiveConfig{Directory: dir}, logger.Get("cgnat-test"))
+	if err != nil {
+		t.Fatalf("newMappingArchive: %v", err)
+	}
+	a.now = func() time.Time { return day1 }
+	if err := a.open(nil); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// The release is delivered before the allocation it follows, and
+	// the allocation of s2 after the archive rotated past its day.
+	for _, rec := range []*models.CGNATArchiveRecord{
+		archiveRecord(day1.Add(20*time.Minute), models.CGNATArchiveRelease, "s1"),
+		archiveRecord(day1.Add(10*time.Minute), models.CGNATArchiveAllocate, "s1"),
+		archiveRecord(day2, models.CGNATArchiveRelease, "s0"),
+		archiveRecord(day2.Add(-2*time.Second), models.CGNATArchiveAllocate, "s2"),
+	} {
+		if err := a.append(rec); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	a.close()
+
+	ip := net.ParseIP("100.64.0.1")
+	cases := []struct {
+		name    string
+		at      time.Time
+		session string
+	}{
+		{"held before late release", day1.Add(15 * time.Minute), "s1"},
+		{"released", day1.Add(30 * time.Minute), ""},
+		{"late allocation", day2.Add(-time.Second), "s2"},
+	}
+	for _, tc := range cases {
+		rec, err := a.lookup(ip, 1030, tc.at)
+		if err != nil {
+			t.Fatalf("%s: lookup: %v", tc.name, err)
+		}
+		got := ""
+		if rec != nil {
+			got = rec.SessionID
+		}
+		if got != tc.session {
+			t.Errorf("%s: session = %q, want %q", tc.name, got, tc.session)
+		}
+	}
+}
+
+func TestMappingArchive_Prune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := filepath.Join(dir, archivePrefix+now.AddDate(0, 0, -40).Format(archiveSegmentLayout)+archiveSuffix)
+	recent := filepath.Join(dir, archivePrefix+now.AddDate(0, 0, -2).Format(archiveSegmentLayout)+archiveSuffix)
+	for _, path := range []string{old, recent} {
+		if err := os.WriteFile(path, make([]byte, 1<<20), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := newMappingArchive(&cgnat.ArchiveConfig{Directory: dir, RetentionDays: 30}, logger.Get("cgnat-test"))
+	if err != nil {
+		t.Fatalf("newMappingArchive: %v", err)
+	}
+	a.now = func() time.Time { return now }
+	if err := a.append(archiveRecord(now, models.CGNATArchiveAllocate, "s1")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatal("segment past retention kept")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Fatal("segment within retention deleted")
+	}
+	a.close()
+
+	a.maxBytes = 1 << 20
+	if err := a.append(archiveRecord(now.Add(time.Minute), models.CGNATArchiveRelease, "s1")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := os.Stat(recent); !os.IsNotExist(err) {
+		t.Fatal("oldest segment kept over the size limit")
+	}
+	a.close()
+}
+
+func TestDeterministicOwner(t *testing.T) {
+	pool := &cgnat.Pool{
+		Mode:               "deterministic",
+		InsidePrefixes:     []cgnat.InsidePrefix{{Prefix: "100.64.0.0/26"}, {Prefix: "100.64.1.0/24"}},
+		OutsideAddresses:   []string{"203.0.113.0/30"},
+		ExcludedAddresses:  []string{"203.0.113.0"},
+		PortRange:          "1024-65535",
+		PortsPerSubscriber: 1024,
+	}
+	// 63 subscribers per outside address; 203.0.113.2 is the second
+	// usable one, so its sixth range is subscriber 68, the fifth address
+	// of the second inside prefix.
+	owner := deterministicOwner("det", pool, net.ParseIP("203.0.113.2"), 1024+5*1024+7)
+	if owner == nil {
+		t.Fatal("no owner")
+	}
+	if !owner.InsideIP.Equal(net.ParseIP("100.64.1.4")) || owner.PortBlockStart != 6144 || owner.PortBlockEnd != 7167 {
+		t.Fatalf("owner = %+v", owner)
+	}
+
+	last := deterministicOwner("det", pool, net.ParseIP("203.0.113.3"), 1024+62*1024)
+	if last == nil || !last.InsideIP.Equal(net.ParseIP("100.64.1.124")) {
+		t.Fatalf("last range of last address = %+v", last)
+	}
+
+	for _, tc := range []struct {
+		ip   string
+		port uint16
+	}{
+		{"203.0.113.0", 2000},
+		{"203.0.113.2", 80},
+		{"198.51.100.1", 2000},
+	} {
+		if owner := deterministicOwner("det", pool, net.ParseIP(tc.ip), tc.port); owner != nil {
+			t.Errorf("%s:%d = %+v, want none", tc.ip, tc.port, owner)
+		}
+	}
+
+	pool.InsidePrefixes = pool.InsidePrefixes[:1]
+	if owner := deterministicOwner("det", pool, net.ParseIP("203.0.113.3"), 1024+62*1024); owner != nil {
+		t.Fatalf("range past the last inside address = %+v", owner)
+	}
+}
+
+func TestLookupMappingOwner_ArchivesBlockEvents(t *testing.T) {
+	mac, _ := net.ParseMAC("02:00:00:00:00:05")
+	sp := &fakeProvider{sessions: map[string]models.SubscriberSession{
+		"s1": &models.IPoESession{SessionID: "s1", Username: "alice@example.net", MAC: mac},
+	}}
+	cfg := pbaConfig()
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), sp, cfg)
+	c.eventBus = local.NewBus()
+
+	if _, err := c.LookupMappingOwner(net.ParseIP("100.64.0.1"), 1024, time.Now()); err == nil {
+		t.Fatal("lookup without an archive succeeded")
+	}
+	if err := c.startArchive(&cgnat.ArchiveConfig{Directory: t.TempDir()}); err != nil {
+		t.Fatalf("startArchive: %v", err)
+	}
+	defer c.archive.close()
+
+	block, err := c.pools.AllocateBlock("p1", net.ParseIP("10.0.0.5").To4(), 0, 17)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	block.SessionID = "s1"
+	c.commitMapping("s1", "p1", block, "", false)
+
+	var owner *models.CGNATMappingOwner
+	deadline := time.Now().Add(2 * time.Second)
+	for owner == nil && time.Now().Before(deadline) {
+		owner, _ = c.LookupMappingOwner(block.OutsideIP, block.PortBlockStart+1, time.Now())
+		time.Sleep(10 * time.Millisecond)
+	}
+	if owner == nil {
+		t.Fatal("allocation never archived")
+	}
+	if owner.Method != "archive" || owner.SessionID != "s1" || owner.Username != "alice@example.net" ||
+		owner.MAC != mac.String() || !owner.InsideIP.Equal(block.InsideIP) {
+		t.Fatalf("owner = %+v", owner)
+	}
+}
+
+func TestArchiveMapping_UsesEventTime(t *testing.T) {
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, pbaConfig())
+	c.eventBus = local.NewBus()
+	if err := c.startArchive(&cgnat.ArchiveConfig{Directory: t.TempDir()}); err != nil {
+		t.Fatalf("startArchive: %v", err)
+	}
+	defer c.archive.close()
+
+	published := time.Now().Add(-time.Hour)
+	m := &models.CGNATMapping{
+		PoolName:       "p1",
+		OutsideIP:      net.ParseIP("100.64.0.1").To4(),
+		PortBlockStart: 1024,
+		PortBlockEnd:   1087,
+		InsideIP:       net.ParseIP("10.0.0.5").To4(),
+		SessionID:      "s1",
+	}
+	c.archiveMapping(events.Event{Timestamp: published, Data: &events.CGNATMappingEvent{Mapping: m, IsAdd: true}})
+
+	if rec, _ := c.archive.lookup(m.OutsideIP, 1030, published.Add(-time.Millisecond)); rec != nil {
+		t.Fatalf("allocation archived before it was published: %+v", rec)
+	}
+	rec, err := c.archive.lookup(m.OutsideIP, 1030, published)
+	if err != nil || rec == nil || !rec.Time.Equal(published) {
+		t.Fatalf("lookup at publish time = %+v, %v", rec, err)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/component"
 	"github.com/veesix-networks/osvbng/pkg/config"
@@ -32,12 +34,20 @@ const (
 
 const opdbNamespace = "cgnat_mappings"
 
+// extraBlockNamespace holds the blocks subscriber-limits escalations
+// give on top of a session's first, keyed by ExtraBlockKey.
+const extraBlockNamespace = "cgnat_extra_blocks"
+
 type Component struct {
 	*component.Base
 
 	logger    *logger.Logger
 	eventBus  events.Bus
 	dataplane southbound.CGNATDataplane
+	dslite    southbound.DSLite
+	nat64     southbound.NAT64
+	mapBR     southbound.MAP
+	pnat      southbound.PNAT
 	opdb      opdb.Store
 	cfgMgr    component.ConfigManager
 	ifMgr     *ifmgr.Manager
@@ -50,11 +60,41 @@ type Component struct {
 
 	poolIDMap      map[string]uint32
 	sessionPoolMap map[string]string
+	// sessionIPv6 holds the inside address of each DS-Lite or NAT64
+	// session and sessionNAT64If the NAT64 inside interface, guarded by
+	// actMu alongside sessionPoolMap.
+	sessionIPv6    map[string]net.IP
+	sessionNAT64If map[string]uint32
+	// nat64Owners indexes the addresses and delegated prefixes of the
+	// NAT64 sessions, guarded by actMu.
+	nat64Owners *nat64Owners
+	// nat64Outside records the interfaces already enabled as NAT64
+	// outside; reset when the dataplane restarts.
+	nat64Outside map[uint32]bool
+	// nat64BIB holds the NAT64 bindings the BIB poll has published,
+	// keyed by outside transport address. Only the poll touches it.
+	nat64BIB map[nat64BIBKey]*models.CGNATMapping
+	// sessionMAPIf holds the session interface MAP is enabled on for
+	// each MAP subscriber and mapOutside the MAP outside interfaces.
+	sessionMAPIf map[string]uint32
+	mapOutside   map[uint32]bool
+
+	// fwdMu guards the port forward table, keyed by forward ID, and
+	// poolOutside, the outside interfaces of each pool that out2in
+	// forward bindings attach to.
+	fwdMu       sync.Mutex
+	forwards    map[string]*portForward
+	poolOutside map[string][]uint32
 
 	lifecycleSub  events.Subscription
 	programmedSub events.Subscription
 	restoredSub   events.Subscription
 
+	// archive records block allocations and releases when
+	// cgnat.archive is configured, fed by archiveSub.
+	archive    *mappingArchive
+	archiveSub events.Subscription
+
 	sessionProvider SessionProvider
 
 	// actMu serializes the activation-state guard. It protects both
@@ -63,6 +103,27 @@ type Component struct {
 	// lifecycle events for the same session each allocate a fresh block.
 	actMu       sync.Mutex
 	activations map[string]struct{}
+	// escalated holds the inside address of each session taken out of
+	// CGNAT by a subscriber-limits bypass escalation, guarded by actMu.
+	escalated map[string]net.IP
+
+	// limitMu guards limits, the subscriber-limits poll's state keyed by
+	// session sw_if_index, and limitLast, the limit counters' last
+	// reading once limitSeen.
+	limitMu   sync.Mutex
+	limits    map[uint32]*subscriberLimitState
+	limitLast southbound.CGNATLimitDrops
+	limitSeen bool
+	// limitCountersMissing is set once the poll has reported that the
+	// dataplane exports no CGNAT limit counters.
+	limitCountersMissing atomic.Bool
+
+	// addrMu serializes outside address changes made while running and
+	// guards addrChanges, those changes keyed by CGNATAddressChange.Key.
+	// routing advertises the prefixes they add.
+	addrMu      sync.Mutex
+	addrChanges map[string]*models.CGNATAddressChange
+	routing     BGPNetworkController
 
 	// Event queue: subscribers attach BEFORE the restore loop runs and
 	// queue events into pendingEvents; once restore completes drainQueue
@@ -97,6 +158,10 @@ func NewComponent(deps component.Dependencies, ifMgr *ifmgr.Manager, vrfMgr *vrf
 		logger:          logger.Get(logger.CGNAT),
 		eventBus:        deps.EventBus,
 		dataplane:       deps.Southbound,
+		dslite:          deps.Southbound,
+		nat64:           deps.Southbound,
+		mapBR:           deps.Southbound,
+		pnat:            deps.Southbound,
 		opdb:            deps.OpDB,
 		cfgMgr:          deps.ConfigManager,
 		ifMgr:           ifMgr,
@@ -107,8 +172,20 @@ func NewComponent(deps component.Dependencies, ifMgr *ifmgr.Manager, vrfMgr *vrf
 		blacklist:       NewBlacklistManager(),
 		poolIDMap:       make(map[string]uint32),
 		sessionPoolMap:  make(map[string]string),
+		sessionIPv6:     make(map[string]net.IP),
+		sessionNAT64If:  make(map[string]uint32),
+		nat64Owners:     newNAT64Owners(),
+		nat64Outside:    make(map[uint32]bool),
+		nat64BIB:        make(map[nat64BIBKey]*models.CGNATMapping),
+		sessionMAPIf:    make(map[string]uint32),
+		mapOutside:      make(map[uint32]bool),
+		forwards:        make(map[string]*portForward),
+		poolOutside:     make(map[string][]uint32),
 		sessionProvider: sessionProvider,
 		activations:     make(map[string]struct{}),
+		escalated:       make(map[string]net.IP),
+		limits:          make(map[uint32]*subscriberLimitState),
+		addrChanges:     make(map[string]*models.CGNATAddressChange),
 	}
 
 	return c, nil
@@ -130,14 +207,34 @@ func (c *Component) Start(ctx context.Context) error {
 
 	c.SetReadyState(component.StateRestoring)
 
+	// Outside addresses added or removed while running are kept until
+	// the configuration catches up with them.
+	addrChanges := c.loadAddressChanges(ctx, cfg.CGNAT)
+	cfg = withAddressChanges(cfg, addrChanges)
+
 	if err := c.reconcile(ctx, cfg); err != nil {
 		return fmt.Errorf("reconcile: %w", err)
 	}
+	c.applyAddressState(addrChanges)
 
 	if err := c.setupOutsideInterfaces(cfg); err != nil {
 		c.logger.Warn("Failed to setup outside interfaces", "error", err)
 	}
 
+	if _, pool := nat64PoolConfig(cfg.CGNAT); pool != nil {
+		if err := c.startDNS64(pool); err != nil {
+			c.logger.Warn("Failed to start DNS64 forwarder", "error", err)
+		}
+	}
+
+	if cfg.CGNAT.Archive != nil {
+		if err := c.startArchive(cfg.CGNAT.Archive); err != nil {
+			c.logger.Warn("Failed to start CGNAT mapping archive", "error", err)
+		}
+	}
+
+	c.loadForwards(ctx, cfg.CGNAT)
+
 	// Subscribe BEFORE the restore loop so live activation events that
 	// arrive during restore are queued, not dropped by the no-replay bus.
 	c.lifecycleSub = c.eventBus.Subscribe(events.TopicSessionLifecycle, c.handleSessionLifecycle)
@@ -147,8 +244,20 @@ func (c *Component) Start(ctx context.Context) error {
 	if err := c.restoreFromOpDB(ctx); err != nil {
 		c.logger.Warn("Failed to restore CGNAT state from OpDB", "error", err)
 	}
+	if c.archive != nil {
+		if err := c.openArchive(); err != nil {
+			c.logger.Warn("Failed to open CGNAT mapping archive", "error", err)
+		}
+	}
 
 	c.drainQueue()
+	c.Go(c.watchSubscriberLimits)
+	c.Go(c.watchNAT64BIB)
+	c.Go(c.watchDrains)
+
+	if err := c.startPCP(cfg.CGNAT.PCP); err != nil {
+		c.logger.Warn("Failed to start PCP server", "error", err)
+	}
 
 	if c.restoreDegraded {
 		c.logger.Error("CGNAT entered degraded restore state",
@@ -177,6 +286,12 @@ func (c *Component) Stop(ctx context.Context) error {
 	if c.restoredSub != nil {
 		c.restoredSub.Unsubscribe()
 	}
+	if c.archiveSub != nil {
+		c.archiveSub.Unsubscribe()
+	}
+	if c.archive != nil {
+		c.archive.close()
+	}
 	c.StopContext()
 	return nil
 }
@@ -204,6 +319,13 @@ func (c *Component) setupOutsideInterfaces(cfg *config.Config) error {
 			return err
 		}
 
+		// The DS-Lite AFTR and NAT64 translate outside the CGNAT plugin,
+		// so there is no plugin pool to bind the outside VRF or
+		// interfaces to.
+		if isIPv6Mode(pool.GetMode()) {
+			continue
+		}
+
 		poolID, ok := c.poolIDMap[poolName]
 		if !ok {
 			return fmt.Errorf("cgnat: pool %q: not registered in poolIDMap (configurePools must run first)", poolName)
@@ -212,6 +334,7 @@ func (c *Component) setupOutsideInterfaces(cfg *config.Config) error {
 			return fmt.Errorf("cgnat: pool %q: set outside VRF: %w", poolName, err)
 		}
 
+		var outside []uint32
 		for _, name := range pool.OutsideInterfaces {
 			swIfIndex, ok := c.ifMgr.GetSwIfIndex(name)
 			if !ok {
@@ -220,7 +343,11 @@ func (c *Component) setupOutsideInterfaces(cfg *config.Config) error {
 			if err := c.dataplane.CGNATPoolOutsideInterfaceAddDel(poolID, swIfIndex, true); err != nil {
 				return fmt.Errorf("cgnat: pool %q: refcnt-enable sv-reass on %q (sw_if %d): %w", poolName, name, swIfIndex, err)
 			}
+			outside = append(outside, swIfIndex)
 		}
+		c.fwdMu.Lock()
+		c.poolOutside[poolName] = outside
+		c.fwdMu.Unlock()
 
 		c.logger.Info("Outside VRF configured",
 			"pool", poolName,
@@ -340,10 +467,11 @@ func (c *Component) dispatchLifecycle(event events.Event) {
 
 	switch data.State {
 	case models.SessionStateActive:
-		if data.AccessType == models.AccessTypePPPoE || data.AccessType == models.AccessTypeIPoE {
+		if data.Protocol == models.ProtocolDHCPv6 {
+			c.dispatchIPv6Lifecycle(data)
 			return
 		}
-		if data.Protocol == models.ProtocolDHCPv6 {
+		if data.AccessType == models.AccessTypePPPoE || data.AccessType == models.AccessTypeIPoE {
 			return
 		}
 		c.handleSessionActivate(data)
@@ -441,6 +569,17 @@ func (c *Component) handleSessionActivateLocked(data *events.SessionLifecycleEve
 		return
 	}
 
+	if sess, ok := data.Session.(models.SubscriberSession); ok {
+		if pool, mode, inside := c.ipv6Target(sess); pool != "" {
+			if inside == nil {
+				done()
+				return
+			}
+			c.handleIPv6Activate(pool, mode, inside, subscriberNets(sess), swIfIndex, data.SessionID, srgName, done)
+			return
+		}
+	}
+
 	if insideIP == nil || insideIP.To4() == nil {
 		done()
 		return
@@ -560,8 +699,10 @@ func (c *Component) handlePBAActivate(poolName string, insideIP net.IP, vrfName
 func (c *Component) commitMapping(sessionID, poolName string, mapping *models.CGNATMapping, srgName string, persist bool) {
 	c.actMu.Lock()
 	c.sessionPoolMap[sessionID] = poolName
+	c.trackIPv6Locked(sessionID, mapping)
 	c.actMu.Unlock()
 
+	c.pools.BindSession(poolName, mapping.InsideIP, mapping.InsideVRFID, sessionID)
 	c.reverse.Add(mapping)
 
 	if persist && c.opdb != nil {
@@ -571,30 +712,15 @@ func (c *Component) commitMapping(sessionID, poolName string, mapping *models.CG
 	}
 
 	c.publishMappingEvent(srgName, mapping, true)
+	c.activateForwards(sessionID, srgName, mapping.InsideIP)
 }
 
 func (c *Component) tryRestoreSyncedMapping(sessionID string, swIfIndex uint32, poolName string, srgName string, done func()) bool {
-	if c.opdb == nil {
-		return false
-	}
-
-	var found []byte
-	c.opdb.Load(context.Background(), opdb.NamespaceHASyncedCGNAT, func(key string, value []byte) error {
-		if key == sessionID {
-			found = make([]byte, len(value))
-			copy(found, value)
-		}
-		return nil
-	})
-
-	if found == nil {
-		return false
-	}
-
-	var mapping models.CGNATMapping
-	if err := json.Unmarshal(found, &mapping); err != nil {
+	synced, ok := c.loadSyncedMapping(sessionID)
+	if !ok {
 		return false
 	}
+	mapping := *synced
 
 	mapping.SwIfIndex = swIfIndex
 	mapping.SessionID = sessionID
@@ -616,6 +742,7 @@ func (c *Component) tryRestoreSyncedMapping(sessionID string, swIfIndex uint32,
 				return
 			}
 
+			c.restoreSyncedExtraBlocks(sessionID, poolID, swIfIndex, srgName)
 			c.commitMapping(sessionID, poolName, &mapping, srgName, true)
 
 			if c.opdb != nil {
@@ -688,6 +815,14 @@ func (c *Component) handleSessionRelease(data *events.SessionLifecycleEvent) {
 		return
 	}
 
+	c.disableMAPSession(data.SessionID)
+	if c.releaseIPv6(data.SessionID, srgName) {
+		return
+	}
+	if c.releaseEscalated(data.SessionID) {
+		return
+	}
+
 	if insideIP == nil || insideIP.To4() == nil {
 		return
 	}
@@ -733,8 +868,12 @@ func (c *Component) handleSessionRelease(data *events.SessionLifecycleEvent) {
 
 	poolID := c.poolIDMap[poolName]
 
+	c.deactivateForwards(data.SessionID, srgName)
+
 	for i := range mappings {
 		mapping := &mappings[i]
+		mapping.SessionID = data.SessionID
+		c.forgetExtraBlock(mapping)
 		c.dataplane.CGNATAddDelSubscriberMappingAsync(poolID, swIfIndex, insideIP,
 			0, mapping.OutsideIP, mapping.PortBlockStart, mapping.PortBlockEnd,
 			false, false, func(err error) {
@@ -760,9 +899,9 @@ func (c *Component) handleSessionRelease(data *events.SessionLifecycleEvent) {
 }
 
 // beginActivation atomically reserves the (sessionID) activation slot. Returns
-// (false, no-op) when a committed mapping already exists for the session or
-// another activation is in flight — both cases are correctly idempotent
-// no-ops. Callers MUST invoke done() exactly once on the proceed path; for
+// (false, no-op) when a committed mapping already exists for the session,
+// the session was escalated to bypass, or another activation is in flight —
+// all correctly idempotent no-ops. Callers MUST invoke done() exactly once on the proceed path; for
 // async southbound callbacks that means the callback owns done().
 func (c *Component) beginActivation(sessionID string) (bool, func()) {
 	c.actMu.Lock()
@@ -774,6 +913,9 @@ func (c *Component) beginActivation(sessionID string) (bool, func()) {
 	if _, ok := c.activations[sessionID]; ok {
 		return false, func() {}
 	}
+	if _, ok := c.escalated[sessionID]; ok {
+		return false, func() {}
+	}
 	c.activations[sessionID] = struct{}{}
 
 	return true, func() {
@@ -906,9 +1048,10 @@ func (c *Component) RecoverDataplane(ctx context.Context) error {
 	c.restoreMu.Unlock()
 
 	var (
-		toProgram = map[uint32][]southbound.CGNATMapping{}
-		ctxByPool = map[uint32][]restoreCtx{}
-		failed    []string
+		toProgram   = map[uint32][]southbound.CGNATMapping{}
+		ctxByPool   = map[uint32][]restoreCtx{}
+		nat64Inside = map[string]uint32{}
+		failed      []string
 	)
 
 	err := c.opdb.Load(ctx, opdbNamespace, func(key string, value []byte) error {
@@ -917,6 +1060,17 @@ func (c *Component) RecoverDataplane(ctx context.Context) error {
 			return nil
 		}
 		mapping.SessionID = key
+		if isIPv6Mapping(&mapping) {
+			// DS-Lite and NAT64 blocks live only in local state; the
+			// AFTR and NAT64 globals are reprogrammed below, and a
+			// NAT64 session's inside interface with them.
+			if c.isNAT64Pool(mapping.PoolName) {
+				if live, present := resolveIfIndex(ctx, c.sessionProvider, key); present {
+					nat64Inside[key] = live
+				}
+			}
+			return nil
+		}
 
 		liveSwIfIndex, present := resolveIfIndex(ctx, c.sessionProvider, key)
 		if !present {
@@ -949,6 +1103,7 @@ func (c *Component) RecoverDataplane(ctx context.Context) error {
 		return err
 	}
 
+	live := make(map[string]uint32)
 	for poolID, batch := range toProgram {
 		results, callErr := c.dataplane.CGNATAddSubscriberMappingBulk(poolID, batch)
 		if callErr != nil {
@@ -970,8 +1125,14 @@ func (c *Component) RecoverDataplane(ctx context.Context) error {
 			if data, err := json.Marshal(&rc.mapping); err == nil {
 				c.opdb.Put(ctx, opdbNamespace, rc.sessionID, data)
 			}
+			live[rc.sessionID] = rc.mapping.SwIfIndex
 		}
 	}
+	c.restoreExtraBlocks(ctx, live, nil)
+
+	// Port forward bindings went with the dataplane; reinstall them on
+	// the sessions' live interfaces.
+	c.reinstallForwards(ctx)
 
 	// Deterministic and bypass need their feature re-enabled per session
 	// since the plugin lost that state. The non-PBA scan handles it via
@@ -981,6 +1142,30 @@ func (c *Component) RecoverDataplane(ctx context.Context) error {
 	// repeat as a no-op.
 	c.scanNonPBASessionsForRecover(ctx)
 
+	if cfg, err := c.cfgMgr.GetRunning(); err == nil && cfg != nil {
+		if name, pool := dsLitePoolConfig(cfg.CGNAT); pool != nil {
+			if err := c.programDSLite(name, pool); err != nil {
+				c.logger.Error("CGNAT recover: DS-Lite AFTR reprogram failed", "pool", name, "error", err)
+			}
+		}
+		if name, pool := nat64PoolConfig(cfg.CGNAT); pool != nil {
+			c.nat64Outside = make(map[uint32]bool)
+			if err := c.programNAT64(name, pool); err != nil {
+				c.logger.Error("CGNAT recover: NAT64 reprogram failed", "pool", name, "error", err)
+			}
+			for sessionID, swIfIndex := range nat64Inside {
+				if !c.enableNAT64Inside(sessionID, swIfIndex, nil) {
+					c.markRestoreDegraded(sessionID)
+					failed = append(failed, sessionID)
+				}
+			}
+		}
+		c.mapOutside = make(map[uint32]bool)
+		if err := c.reconcileMAP(cfg); err != nil {
+			c.logger.Error("CGNAT recover: MAP reprogram failed", "error", err)
+		}
+	}
+
 	c.logger.Info("CGNAT watchdog dataplane recovery complete",
 		"pba_reprogrammed", countRestored(toProgram, failed),
 		"failed", len(failed))
@@ -994,7 +1179,14 @@ func (c *Component) scanNonPBASessionsForRecover(ctx context.Context) {
 		c.logger.Warn("CGNAT recover: session scan failed", "error", err)
 		return
 	}
+	// The restarted dataplane lost the MAP session interfaces.
+	c.actMu.Lock()
+	c.sessionMAPIf = make(map[string]uint32)
+	c.actMu.Unlock()
 	for _, sess := range sessions {
+		if c.activateMAP(sess) {
+			continue
+		}
 		insideIP := sess.GetIPv4Address()
 		if insideIP == nil || insideIP.To4() == nil {
 			continue
@@ -1035,6 +1227,7 @@ func (c *Component) restoreFromOpDB(ctx context.Context) error {
 		ctxByPool     = map[uint32][]restoreCtx{}
 		toOrphan      []restoreCtx
 		alreadyKnown  = map[string]struct{}{}
+		live          = map[string]uint32{}
 		restoreErrors []string
 	)
 
@@ -1074,6 +1267,17 @@ func (c *Component) restoreFromOpDB(ctx context.Context) error {
 			return nil
 		}
 
+		if isIPv6Mapping(&mapping) {
+			// Nothing per-B4 to reprogram on the AFTR; a NAT64
+			// session only needs its inside interface back.
+			if c.isNAT64Pool(mapping.PoolName) && !c.enableNAT64Inside(key, liveSwIfIndex, c.sessionNets(ctx, key)) {
+				c.markRestoreDegraded(key)
+			}
+			c.commitRestoredPBA(ctx, &mapping)
+			alreadyKnown[key] = struct{}{}
+			return nil
+		}
+
 		toProgram[poolID] = append(toProgram[poolID], southbound.CGNATMapping{
 			PoolID:         poolID,
 			SwIfIndex:      liveSwIfIndex,
@@ -1113,9 +1317,16 @@ func (c *Component) restoreFromOpDB(ctx context.Context) error {
 			}
 			c.commitRestoredPBA(ctx, &rc.mapping)
 			alreadyKnown[rc.sessionID] = struct{}{}
+			live[rc.sessionID] = rc.mapping.SwIfIndex
 		}
 	}
 
+	degraded := make(map[string]struct{}, len(restoreErrors))
+	for _, sid := range restoreErrors {
+		degraded[sid] = struct{}{}
+	}
+	c.restoreExtraBlocks(ctx, live, degraded)
+
 	c.scanNonPBASessions(ctx, alreadyKnown)
 
 	for _, rc := range toOrphan {
@@ -1181,6 +1392,7 @@ func (c *Component) commitRestoredPBA(ctx context.Context, mapping *models.CGNAT
 
 	c.actMu.Lock()
 	c.sessionPoolMap[mapping.SessionID] = mapping.PoolName
+	c.trackIPv6Locked(mapping.SessionID, mapping)
 	c.actMu.Unlock()
 
 	// Refresh the persisted entry if the sw_if_index changed (VPP renumbered
@@ -1188,6 +1400,8 @@ func (c *Component) commitRestoredPBA(ctx context.Context, mapping *models.CGNAT
 	if data, err := json.Marshal(mapping); err == nil {
 		c.opdb.Put(ctx, opdbNamespace, mapping.SessionID, data)
 	}
+
+	c.activateForwards(mapping.SessionID, c.sessionSRGName(ctx, mapping.SessionID), mapping.InsideIP)
 }
 
 func (c *Component) deleteOrphan(ctx context.Context, mapping *models.CGNATMapping) {
@@ -1222,6 +1436,21 @@ func (c *Component) scanNonPBASessions(ctx context.Context, alreadyKnown map[str
 		if committed {
 			continue
 		}
+		if c.activateMAP(sess) {
+			continue
+		}
+
+		if pool, mode, inside := c.ipv6Target(sess); pool != "" {
+			if inside == nil {
+				continue
+			}
+			proceed, done := c.beginActivation(sid)
+			if !proceed {
+				continue
+			}
+			c.handleIPv6Activate(pool, mode, inside, subscriberNets(sess), sess.GetIfIndex(), sid, sess.GetSRGName(), done)
+			continue
+		}
 
 		insideIP := sess.GetIPv4Address()
 		if insideIP == nil || insideIP.To4() == nil {
@@ -1274,7 +1503,8 @@ func countRestored(toProgram map[uint32][]southbound.CGNATMapping, errs []string
 
 func (c *Component) publishMappingEvent(srgName string, mapping *models.CGNATMapping, isAdd bool) {
 	c.eventBus.Publish(events.TopicCGNATMapping, events.Event{
-		Source: c.Name(),
+		Source:    c.Name(),
+		Timestamp: time.Now(),
 		Data: &events.CGNATMappingEvent{
 			SRGName:   srgName,
 			SessionID: mapping.SessionID,
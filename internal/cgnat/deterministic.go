@@ -0,0 +1,103 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"net"
+	"sort"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// deterministicOwner computes which inside address a deterministic pool
+// maps ip:port to (RFC 7422 §2). Inside addresses, taken in
+// inside-prefixes order, fill the outside addresses in order, skipping
+// excluded ones; each outside address holds as many subscribers as the
+// port range has ranges of ports-per-subscriber. It returns nil when
+// ip:port is outside the pool or maps past its last inside address.
+func deterministicOwner(name string, pool *cgnat.Pool, ip net.IP, port uint16) *models.CGNATMappingOwner {
+	ip4 := ip.To4()
+	if ip4 == nil || pool.GetMode() != "deterministic" {
+		return nil
+	}
+	start, end := pool.GetPortRangeStart(), pool.GetPortRangeEnd()
+	size := uint32(pool.GetPortsPerSubscriber())
+	if size == 0 || port < start || port > end {
+		return nil
+	}
+	perAddress := (uint32(end) - uint32(start) + 1) / size
+	slot := (uint32(port) - uint32(start)) / size
+	if slot >= perAddress {
+		return nil
+	}
+
+	excluded := make(map[string]bool, len(pool.ExcludedAddresses))
+	for _, ex := range pool.ExcludedAddresses {
+		excluded[ex] = true
+	}
+	index := -1
+	n := 0
+	for _, cidr := range pool.OutsideAddresses {
+		ips, err := expandCIDR(cidr)
+		if err != nil {
+			return nil
+		}
+		for _, a := range ips {
+			if excluded[a.String()] {
+				continue
+			}
+			if a.Equal(ip4) {
+				index = n
+			}
+			n++
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+
+	subscriber := uint64(index)*uint64(perAddress) + uint64(slot)
+	for _, prefix := range pool.InsidePrefixes {
+		_, ipNet, err := net.ParseCIDR(prefix.Prefix)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		count := uint64(1) << uint(bits-ones)
+		if subscriber >= count {
+			subscriber -= count
+			continue
+		}
+		blockStart := uint32(start) + slot*size
+		return &models.CGNATMappingOwner{
+			Method:         "deterministic",
+			PoolName:       name,
+			OutsideIP:      ip4,
+			OutsidePort:    port,
+			PortBlockStart: uint16(blockStart),
+			PortBlockEnd:   uint16(blockStart + size - 1),
+			InsideIP:       u32ToIP(ipToU32(ipNet.IP) + uint32(subscriber)).To4(),
+		}
+	}
+	return nil
+}
+
+// deterministicLookup tries every deterministic pool, in name order.
+func deterministicLookup(cfg *cgnat.Config, ip net.IP, port uint16) *models.CGNATMappingOwner {
+	names := make([]string, 0, len(cfg.Pools))
+	for name := range cfg.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if pool := cfg.Pools[name]; pool != nil {
+			if owner := deterministicOwner(name, pool, ip, port); owner != nil {
+				return owner
+			}
+		}
+	}
+	return nil
+}
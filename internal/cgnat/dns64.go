@@ -0,0 +1,493 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dns64UpstreamTimeout bounds one exchange with an upstream resolver.
+const dns64UpstreamTimeout = 2 * time.Second
+
+// dns64MaxMessage is the largest UDP DNS message read or relayed.
+const dns64MaxMessage = 4096
+
+// dns64MaxInflight bounds the queries being resolved at once; datagrams
+// arriving beyond it wait in the socket buffer.
+const dns64MaxInflight = 64
+
+// dns64MaxTCPConns bounds the open client TCP connections, and
+// dns64TCPIdle closes one that sends nothing for that long.
+const (
+	dns64MaxTCPConns = 16
+	dns64TCPIdle     = 10 * time.Second
+)
+
+// dns64Server is the DNS64 (RFC 6147) forwarder of a NAT64 pool. Queries
+// are relayed to the upstream resolvers; an AAAA query that comes back
+// without AAAA records is answered from the name's A records, each
+// embedded in the NAT64 prefix per RFC 6052. It answers only the pool's
+// subscribers and the configured allowed-clients, over UDP and TCP.
+type dns64Server struct {
+	logger    *logger.Logger
+	prefix    net.IPNet
+	binding   netbind.Binding
+	upstreams []*net.UDPAddr
+	conn      *net.UDPConn
+	ln        net.Listener
+
+	// allowNets are the allowed-clients prefixes; allowed, when set,
+	// admits the pool's subscribers.
+	allowNets []*net.IPNet
+	allowed   func(net.IP) bool
+
+	sem     chan struct{}
+	tcpConn chan struct{}
+
+	// exchange sends one query upstream and returns the response;
+	// replaced in tests.
+	exchange func(ctx context.Context, query []byte) ([]byte, error)
+}
+
+func newDNS64Server(log *logger.Logger, pool *cgnat.Pool, allowed func(net.IP) bool) (*dns64Server, error) {
+	dns := pool.NAT64.DNS64
+	_, prefix, err := net.ParseCIDR(pool.NAT64.GetPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("nat64 prefix: %w", err)
+	}
+	addr, err := netip.ParseAddr(dns.Address)
+	if err != nil {
+		return nil, fmt.Errorf("dns64 address: %w", err)
+	}
+	s := &dns64Server{
+		logger:  log,
+		prefix:  *prefix,
+		binding: netbind.Binding{VRF: dns.VRF, SourceIP: addr},
+		allowed: allowed,
+		sem:     make(chan struct{}, dns64MaxInflight),
+		tcpConn: make(chan struct{}, dns64MaxTCPConns),
+	}
+	for _, up := range dns.Upstreams {
+		ua, err := cgnat.ParseDNS64Upstream(up)
+		if err != nil {
+			return nil, fmt.Errorf("dns64 upstream: %w", err)
+		}
+		s.upstreams = append(s.upstreams, ua)
+	}
+	for _, pfx := range dns.AllowedClients {
+		_, n, err := net.ParseCIDR(pfx)
+		if err != nil {
+			return nil, fmt.Errorf("dns64 allowed client: %w", err)
+		}
+		s.allowNets = append(s.allowNets, n)
+	}
+	s.exchange = s.exchangeUpstream
+	return s, nil
+}
+
+// start binds the UDP and TCP listeners; serve must then run until ctx
+// is done.
+func (s *dns64Server) start(ctx context.Context) error {
+	conn, err := netbind.ListenUDP(ctx, "udp6", cgnat.DefaultDNS64Port, s.binding)
+	if err != nil {
+		return err
+	}
+	ln, err := netbind.ListenTCP(ctx, "tcp6", net.JoinHostPort("::", strconv.Itoa(cgnat.DefaultDNS64Port)), s.binding)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	s.conn = conn
+	s.ln = ln
+	return nil
+}
+
+// permitted reports whether a client may use the forwarder; anything
+// else is dropped so the listener is not an open resolver.
+func (s *dns64Server) permitted(ip net.IP) bool {
+	for _, n := range s.allowNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return s.allowed != nil && s.allowed(ip)
+}
+
+// acquire takes a worker slot, waiting for one to free up.
+func (s *dns64Server) acquire(ctx context.Context) bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *dns64Server) release() { <-s.sem }
+
+func (s *dns64Server) serve(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		s.conn.Close()
+		s.ln.Close()
+	}()
+	go s.serveTCP(ctx)
+
+	buf := make([]byte, dns64MaxMessage)
+	for {
+		n, peer, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.logger.Debug("DNS64 read failed", "error", err)
+			continue
+		}
+		if !s.permitted(peer.IP) {
+			continue
+		}
+		query := make([]byte, n)
+		copy(query, buf[:n])
+		if !s.acquire(ctx) {
+			return
+		}
+		go func() {
+			defer s.release()
+			resp := s.answerUDP(ctx, query, peer)
+			if resp == nil {
+				return
+			}
+			if _, err := s.conn.WriteToUDP(resp, peer); err != nil {
+				s.logger.Debug("DNS64 reply failed", "client", peer, "error", err)
+			}
+		}()
+	}
+}
+
+// answerUDP resolves a datagram query, truncating a reply larger than
+// the client advertised so it retries over TCP. Returns nil when there
+// is nothing to send.
+func (s *dns64Server) answerUDP(ctx context.Context, query []byte, peer *net.UDPAddr) []byte {
+	resp, err := s.answer(ctx, query)
+	if err != nil {
+		s.logger.Debug("DNS64 query failed", "client", peer, "error", err)
+		return nil
+	}
+	if len(resp) <= clientUDPSize(query) {
+		return resp
+	}
+	tc, err := truncated(resp)
+	if err != nil {
+		s.logger.Debug("DNS64 truncate failed", "client", peer, "error", err)
+		return nil
+	}
+	return tc
+}
+
+func (s *dns64Server) serveTCP(ctx context.Context) {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.logger.Debug("DNS64 accept failed", "error", err)
+			continue
+		}
+		addr, _ := conn.RemoteAddr().(*net.TCPAddr)
+		if addr == nil || !s.permitted(addr.IP) {
+			conn.Close()
+			continue
+		}
+		select {
+		case s.tcpConn <- struct{}{}:
+		default:
+			conn.Close()
+			continue
+		}
+		go func() {
+			defer func() { <-s.tcpConn }()
+			defer conn.Close()
+			s.handleTCP(ctx, conn)
+		}()
+	}
+}
+
+// handleTCP answers the length-prefixed queries of one client connection
+// (RFC 7766) in turn until it goes idle or closes.
+func (s *dns64Server) handleTCP(ctx context.Context, conn net.Conn) {
+	for {
+		conn.SetDeadline(time.Now().Add(dns64TCPIdle))
+		query, err := readTCPMessage(conn)
+		if err != nil {
+			return
+		}
+		if !s.acquire(ctx) {
+			return
+		}
+		resp, err := s.answer(ctx, query)
+		s.release()
+		if err != nil {
+			s.logger.Debug("DNS64 query failed", "client", conn.RemoteAddr(), "error", err)
+			return
+		}
+		if err := writeTCPMessage(conn, resp); err != nil {
+			return
+		}
+	}
+}
+
+// clientUDPSize is the largest reply the client accepts over UDP: 512
+// bytes, or the size in its EDNS(0) OPT record.
+func clientUDPSize(query []byte) int {
+	size := 512
+	var m dnsmessage.Message
+	if err := m.Unpack(query); err != nil {
+		return size
+	}
+	for _, rr := range m.Additionals {
+		if rr.Header.Type == dnsmessage.TypeOPT && int(rr.Header.Class) > size {
+			size = int(rr.Header.Class)
+		}
+	}
+	return min(size, dns64MaxMessage)
+}
+
+// truncated strips a response to its header and question with TC set.
+func truncated(resp []byte) ([]byte, error) {
+	var m dnsmessage.Message
+	if err := m.Unpack(resp); err != nil {
+		return nil, err
+	}
+	m.Truncated = true
+	m.Answers, m.Authorities, m.Additionals = nil, nil, nil
+	return m.Pack()
+}
+
+func readTCPMessage(r io.Reader) ([]byte, error) {
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func writeTCPMessage(w io.Writer, msg []byte) error {
+	if len(msg) > 0xffff {
+		return fmt.Errorf("message of %d bytes too large for TCP", len(msg))
+	}
+	out := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(out, uint16(len(msg)))
+	copy(out[2:], msg)
+	_, err := w.Write(out)
+	return err
+}
+
+// answer resolves one client query, synthesizing AAAA records when the
+// name has none of its own.
+func (s *dns64Server) answer(ctx context.Context, query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	hdr, err := p.Start(query)
+	if err != nil {
+		return nil, fmt.Errorf("parse query: %w", err)
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, fmt.Errorf("parse question: %w", err)
+	}
+
+	resp, err := s.exchange(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if q.Type != dnsmessage.TypeAAAA || q.Class != dnsmessage.ClassINET {
+		return resp, nil
+	}
+
+	var upstream dnsmessage.Message
+	if err := upstream.Unpack(resp); err != nil {
+		return nil, fmt.Errorf("parse upstream response: %w", err)
+	}
+	// RFC 6147 5.1.2: only a NOERROR answer without AAAA records
+	// triggers synthesis; NXDOMAIN and errors pass through.
+	if upstream.RCode != dnsmessage.RCodeSuccess || hasAAAA(upstream.Answers) {
+		return resp, nil
+	}
+
+	aQuery := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: hdr.ID + 1, RecursionDesired: hdr.RecursionDesired},
+		Questions: []dnsmessage.Question{{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}
+	aPacked, err := aQuery.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("pack A query: %w", err)
+	}
+	aResp, err := s.exchange(ctx, aPacked)
+	if err != nil {
+		return resp, nil
+	}
+	var aMsg dnsmessage.Message
+	if err := aMsg.Unpack(aResp); err != nil || aMsg.RCode != dnsmessage.RCodeSuccess {
+		return resp, nil
+	}
+
+	synth := s.synthesize(aMsg.Answers)
+	if len(synth) == 0 {
+		return resp, nil
+	}
+	upstream.Answers = synth
+	upstream.Authorities = nil
+	upstream.Additionals = nil
+	upstream.Header.ID = hdr.ID
+	return upstream.Pack()
+}
+
+// synthesize rewrites an A answer into AAAA records under the NAT64
+// prefix, keeping the CNAME chain that leads to them.
+func (s *dns64Server) synthesize(answers []dnsmessage.Resource) []dnsmessage.Resource {
+	var out []dnsmessage.Resource
+	found := false
+	for _, rr := range answers {
+		switch body := rr.Body.(type) {
+		case *dnsmessage.CNAMEResource:
+			out = append(out, rr)
+		case *dnsmessage.AResource:
+			var aaaa dnsmessage.AAAAResource
+			copy(aaaa.AAAA[:], embedIPv4(s.prefix, net.IP(body.A[:])))
+			hdr := rr.Header
+			hdr.Type = dnsmessage.TypeAAAA
+			out = append(out, dnsmessage.Resource{Header: hdr, Body: &aaaa})
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return out
+}
+
+func hasAAAA(answers []dnsmessage.Resource) bool {
+	for _, rr := range answers {
+		if rr.Header.Type == dnsmessage.TypeAAAA {
+			return true
+		}
+	}
+	return false
+}
+
+// embedIPv4 builds the IPv4-embedded IPv6 address of v4 under prefix
+// (RFC 6052 2.2): the IPv4 bytes follow the prefix, skipping bits 64-71.
+func embedIPv4(prefix net.IPNet, v4 net.IP) net.IP {
+	out := make(net.IP, net.IPv6len)
+	copy(out, prefix.IP.To16())
+	ones, _ := prefix.Mask.Size()
+	pos := ones / 8
+	for _, b := range v4.To4() {
+		if pos == 8 {
+			pos++
+		}
+		out[pos] = b
+		pos++
+	}
+	return out
+}
+
+// exchangeUpstream tries each upstream resolver in turn.
+func (s *dns64Server) exchangeUpstream(ctx context.Context, query []byte) ([]byte, error) {
+	var lastErr error
+	for _, up := range s.upstreams {
+		resp, err := s.exchangeOne(ctx, up, query)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no upstream answered: %w", lastErr)
+}
+
+// exchangeOne queries one upstream over UDP, retrying over TCP when the
+// answer comes back truncated.
+func (s *dns64Server) exchangeOne(ctx context.Context, up *net.UDPAddr, query []byte) ([]byte, error) {
+	network := "udp4"
+	if up.IP.To4() == nil {
+		network = "udp6"
+	}
+	conn, err := netbind.DialUDP(ctx, network, up, netbind.Binding{VRF: s.binding.VRF})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dns64UpstreamTimeout))
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, fmt.Errorf("send to %s: %w", up, err)
+	}
+	buf := make([]byte, dns64MaxMessage)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read from %s: %w", up, err)
+	}
+	if n >= 3 && buf[2]&0x02 != 0 {
+		return s.exchangeTCP(ctx, up, query)
+	}
+	return buf[:n], nil
+}
+
+func (s *dns64Server) exchangeTCP(ctx context.Context, up *net.UDPAddr, query []byte) ([]byte, error) {
+	network := "tcp4"
+	if up.IP.To4() == nil {
+		network = "tcp6"
+	}
+	conn, err := netbind.DialTCP(ctx, network, up.String(), netbind.Binding{VRF: s.binding.VRF}, dns64UpstreamTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dns64UpstreamTimeout))
+
+	if err := writeTCPMessage(conn, query); err != nil {
+		return nil, fmt.Errorf("send to %s over tcp: %w", up, err)
+	}
+	resp, err := readTCPMessage(conn)
+	if err != nil {
+		return nil, fmt.Errorf("read from %s over tcp: %w", up, err)
+	}
+	return resp, nil
+}
+
+// startDNS64 runs the NAT64 pool's DNS64 forwarder, if configured, for
+// the life of the component.
+func (c *Component) startDNS64(pool *cgnat.Pool) error {
+	if pool == nil || pool.NAT64 == nil || pool.NAT64.DNS64 == nil {
+		return nil
+	}
+	srv, err := newDNS64Server(c.logger, pool, c.isNAT64Client)
+	if err != nil {
+		return err
+	}
+	if err := srv.start(c.Ctx); err != nil {
+		return err
+	}
+	c.Go(func() { srv.serve(c.Ctx) })
+	c.logger.Info("DNS64 forwarder listening", "address", pool.NAT64.DNS64.Address, "prefix", srv.prefix.String(), "upstreams", len(srv.upstreams))
+	return nil
+}
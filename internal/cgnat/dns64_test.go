@@ -0,0 +1,241 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestEmbedIPv4_RFC6052Vectors(t *testing.T) {
+	v4 := net.ParseIP("192.0.2.33")
+	cases := map[string]string{
+		"2001:db8::/32":         "2001:db8:c000:221::",
+		"2001:db8:100::/40":     "2001:db8:1c0:2:21::",
+		"2001:db8:122::/48":     "2001:db8:122:c000:2:2100::",
+		"2001:db8:122:300::/56": "2001:db8:122:3c0:0:221::",
+		"2001:db8:122:344::/64": "2001:db8:122:344:c0:2:2100:0",
+		"2001:db8:122:344::/96": "2001:db8:122:344::c000:221",
+		"64:ff9b::/96":          "64:ff9b::c000:221",
+	}
+	for prefix, want := range cases {
+		_, ipNet, err := net.ParseCIDR(prefix)
+		if err != nil {
+			t.Fatalf("%s: %v", prefix, err)
+		}
+		if got := embedIPv4(*ipNet, v4); !got.Equal(net.ParseIP(want)) {
+			t.Errorf("%s: got %s, want %s", prefix, got, want)
+		}
+	}
+}
+
+// fakeResolver answers A queries from a table and AAAA queries with an
+// empty NOERROR, like a resolver for an IPv4-only name.
+func fakeResolver(t *testing.T, a map[string][4]byte, aaaa map[string][16]byte) func(context.Context, []byte) ([]byte, error) {
+	return func(_ context.Context, query []byte) ([]byte, error) {
+		var q dnsmessage.Message
+		if err := q.Unpack(query); err != nil {
+			t.Fatalf("upstream got unparsable query: %v", err)
+		}
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: q.Header.ID, Response: true, RCode: dnsmessage.RCodeSuccess},
+			Questions: q.Questions,
+		}
+		question := q.Questions[0]
+		hdr := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 300}
+		switch question.Type {
+		case dnsmessage.TypeA:
+			if ip, ok := a[question.Name.String()]; ok {
+				hdr.Type = dnsmessage.TypeA
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AResource{A: ip}})
+			}
+		case dnsmessage.TypeAAAA:
+			if ip, ok := aaaa[question.Name.String()]; ok {
+				hdr.Type = dnsmessage.TypeAAAA
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AAAAResource{AAAA: ip}})
+			}
+		}
+		return resp.Pack()
+	}
+}
+
+func aaaaQuery(t *testing.T, name string) []byte {
+	t.Helper()
+	q := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 4242, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  dnsmessage.TypeAAAA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	b, err := q.Pack()
+	if err != nil {
+		t.Fatalf("pack query: %v", err)
+	}
+	return b
+}
+
+func TestDNS64Answer_SynthesizesFromA(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("64:ff9b::/96")
+	s := &dns64Server{logger: logger.Get("cgnat-test"), prefix: *prefix}
+	native := [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}
+	s.exchange = fakeResolver(t,
+		map[string][4]byte{"v4only.example.": {192, 0, 2, 33}, "dual.example.": {192, 0, 2, 34}},
+		map[string][16]byte{"dual.example.": native})
+
+	resp, err := s.answer(context.Background(), aaaaQuery(t, "v4only.example."))
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	var m dnsmessage.Message
+	if err := m.Unpack(resp); err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	if m.Header.ID != 4242 || len(m.Answers) != 1 {
+		t.Fatalf("synthesized response: id=%d answers=%+v", m.Header.ID, m.Answers)
+	}
+	body, ok := m.Answers[0].Body.(*dnsmessage.AAAAResource)
+	if !ok || !net.IP(body.AAAA[:]).Equal(net.ParseIP("64:ff9b::c000:221")) {
+		t.Fatalf("synthesized answer = %+v", m.Answers[0])
+	}
+
+	resp, err = s.answer(context.Background(), aaaaQuery(t, "dual.example."))
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if err := m.Unpack(resp); err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	body, ok = m.Answers[0].Body.(*dnsmessage.AAAAResource)
+	if len(m.Answers) != 1 || !ok || body.AAAA != native {
+		t.Fatalf("native AAAA not passed through: %+v", m.Answers)
+	}
+}
+
+func TestDNS64Permitted_SubscribersAndAllowList(t *testing.T) {
+	_, allow, _ := net.ParseCIDR("2001:db8:ff::/48")
+	sub := net.ParseIP("2001:db8:1::42")
+	s := &dns64Server{
+		allowNets: []*net.IPNet{allow},
+		allowed:   func(ip net.IP) bool { return ip.Equal(sub) },
+	}
+	cases := map[string]bool{
+		"2001:db8:1::42":  true,
+		"2001:db8:ff:1::": true,
+		"2001:db8:1::43":  false,
+		"2001:db8:9::1":   false,
+	}
+	for ip, want := range cases {
+		if got := s.permitted(net.ParseIP(ip)); got != want {
+			t.Errorf("permitted(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestDNS64AnswerUDP_TruncatesToClientSize(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("64:ff9b::/96")
+	s := &dns64Server{logger: logger.Get("cgnat-test"), prefix: *prefix}
+	s.exchange = func(_ context.Context, query []byte) ([]byte, error) {
+		var q dnsmessage.Message
+		if err := q.Unpack(query); err != nil {
+			t.Fatalf("upstream got unparsable query: %v", err)
+		}
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: q.Header.ID, Response: true},
+			Questions: q.Questions,
+		}
+		if q.Questions[0].Type == dnsmessage.TypeA {
+			hdr := dnsmessage.ResourceHeader{Name: q.Questions[0].Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 300}
+			for i := 0; i < 40; i++ {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, byte(i)}}})
+			}
+		}
+		return resp.Pack()
+	}
+
+	resp := s.answerUDP(context.Background(), aaaaQuery(t, "many.example."), &net.UDPAddr{})
+	var m dnsmessage.Message
+	if err := m.Unpack(resp); err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	if !m.Truncated || len(m.Answers) != 0 || len(m.Questions) != 1 || m.Header.ID != 4242 {
+		t.Fatalf("oversized reply not truncated: tc=%v answers=%d", m.Truncated, len(m.Answers))
+	}
+
+	full, err := s.answer(context.Background(), aaaaQuery(t, "many.example."))
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if err := m.Unpack(full); err != nil || len(m.Answers) != 40 {
+		t.Fatalf("TCP-sized answer has %d records (%v)", len(m.Answers), err)
+	}
+}
+
+func TestDNS64ExchangeOne_RetriesTruncatedOverTCP(t *testing.T) {
+	udp, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	defer udp.Close()
+	up := udp.LocalAddr().(*net.UDPAddr)
+	tcp, err := net.ListenTCP("tcp4", &net.TCPAddr{IP: up.IP, Port: up.Port})
+	if err != nil {
+		t.Skipf("tcp port %d taken: %v", up.Port, err)
+	}
+	defer tcp.Close()
+
+	query := aaaaQuery(t, "big.example.")
+	reply := func(tc bool, answers int) []byte {
+		var q dnsmessage.Message
+		q.Unpack(query)
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: q.Header.ID, Response: true, Truncated: tc},
+			Questions: q.Questions,
+		}
+		hdr := dnsmessage.ResourceHeader{Name: q.Questions[0].Name, Type: dnsmessage.TypeAAAA, Class: dnsmessage.ClassINET, TTL: 300}
+		for i := 0; i < answers; i++ {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 15: byte(i)}}})
+		}
+		b, _ := resp.Pack()
+		return b
+	}
+	go func() {
+		buf := make([]byte, dns64MaxMessage)
+		n, peer, err := udp.ReadFromUDP(buf)
+		if err != nil || n == 0 {
+			return
+		}
+		udp.WriteToUDP(reply(true, 0), peer)
+	}()
+	go func() {
+		conn, err := tcp.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := readTCPMessage(conn); err != nil {
+			return
+		}
+		writeTCPMessage(conn, reply(false, 3))
+	}()
+
+	s := &dns64Server{logger: logger.Get("cgnat-test")}
+	resp, err := s.exchangeOne(context.Background(), up, query)
+	if err != nil {
+		t.Fatalf("exchangeOne: %v", err)
+	}
+	var m dnsmessage.Message
+	if err := m.Unpack(resp); err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	if m.Truncated || len(m.Answers) != 3 {
+		t.Fatalf("got tc=%v answers=%d, want the full TCP answer", m.Truncated, len(m.Answers))
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+)
+
+// DS-Lite (RFC 6333) pools terminate B4 softwires on the dataplane's
+// AFTR instead of the CGNAT plugin. Subscribers are handled by the
+// IPv6 subscriber path in ipv6.go, keyed by the B4 address.
+
+func dsLitePoolConfig(cfg *cgnat.Config) (string, *cgnat.Pool) {
+	if cfg == nil {
+		return "", nil
+	}
+	for name, p := range cfg.Pools {
+		if p != nil && p.GetMode() == cgnat.ModeDSLite {
+			return name, p
+		}
+	}
+	return "", nil
+}
+
+// reconcileDSLite registers the DS-Lite pool with the PBA allocator and
+// converges the dataplane AFTR endpoint and address range onto it.
+func (c *Component) reconcileDSLite(cfg *config.Config) error {
+	name, pool := dsLitePoolConfig(cfg.CGNAT)
+	if pool == nil {
+		return nil
+	}
+	id := poolID(name)
+	c.poolIDMap[name] = id
+	if err := c.pools.ConfigurePool(name, id, pool); err != nil {
+		return fmt.Errorf("cgnat: pool %q: %w", name, err)
+	}
+	return c.programDSLite(name, pool)
+}
+
+func (c *Component) programDSLite(name string, pool *cgnat.Pool) error {
+	if c.dslite == nil {
+		return nil
+	}
+	aftr6 := net.ParseIP(pool.AFTR.Address)
+	aftr4 := net.ParseIP(pool.AFTR.GetIPv4Address())
+	if err := c.dslite.DSLiteSetAFTRAddr(aftr6, aftr4); err != nil {
+		return fmt.Errorf("cgnat: pool %q: set AFTR address: %w", name, err)
+	}
+
+	desired, err := poolAddresses(pool)
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: %w", name, err)
+	}
+	current, err := c.dslite.DSLiteAddressDump()
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: dump DS-Lite addresses: %w", name, err)
+	}
+	stale, missing := diffAddresses(desired, current)
+
+	for _, r := range addressRanges(stale) {
+		if err := c.dslite.DSLiteAddDelPoolAddrRange(r[0], r[1], false); err != nil {
+			return fmt.Errorf("cgnat: pool %q: remove DS-Lite range %s-%s: %w", name, r[0], r[1], err)
+		}
+	}
+	for _, r := range addressRanges(missing) {
+		if err := c.dslite.DSLiteAddDelPoolAddrRange(r[0], r[1], true); err != nil {
+			return fmt.Errorf("cgnat: pool %q: add DS-Lite range %s-%s: %w", name, r[0], r[1], err)
+		}
+	}
+
+	c.logger.Info("DS-Lite AFTR configured",
+		"pool", name,
+		"aftr", aftr6,
+		"addresses", len(desired),
+		"added", len(missing),
+		"removed", len(stale))
+	return nil
+}
+
+// addressRanges collapses IPv4 addresses into contiguous [start, end]
+// runs so the dataplane gets one call per range.
+func addressRanges(ips []net.IP) [][2]net.IP {
+	if len(ips) == 0 {
+		return nil
+	}
+	vals := make([]uint32, 0, len(ips))
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			vals = append(vals, ipToU32(ip))
+		}
+	}
+	sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+
+	var out [][2]net.IP
+	for i := 0; i < len(vals); {
+		j := i
+		for j+1 < len(vals) && vals[j+1] == vals[j]+1 {
+			j++
+		}
+		out = append(out, [2]net.IP{u32ToIP(vals[i]).To4(), u32ToIP(vals[j]).To4()})
+		i = j + 1
+	}
+	return out
+}
+
+// poolAddresses expands a pool's outside addresses, less the excluded
+// ones, keyed by address.
+func poolAddresses(pool *cgnat.Pool) (map[string]net.IP, error) {
+	excluded := make(map[string]bool, len(pool.ExcludedAddresses))
+	for _, ex := range pool.ExcludedAddresses {
+		excluded[ex] = true
+	}
+	desired := make(map[string]net.IP)
+	for _, addrStr := range pool.OutsideAddresses {
+		ips, err := expandCIDR(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outside address %s: %w", addrStr, err)
+		}
+		for _, ip := range ips {
+			if !excluded[ip.String()] {
+				desired[ip.String()] = ip
+			}
+		}
+	}
+	return desired, nil
+}
+
+// diffAddresses splits the dataplane's current addresses against the
+// desired set into those to remove and those to add.
+func diffAddresses(desired map[string]net.IP, current []net.IP) (stale, missing []net.IP) {
+	have := make(map[string]bool, len(current))
+	for _, ip := range current {
+		have[ip.String()] = true
+		if _, ok := desired[ip.String()]; !ok {
+			stale = append(stale, ip)
+		}
+	}
+	for key, ip := range desired {
+		if !have[key] {
+			missing = append(missing, ip)
+		}
+	}
+	return stale, missing
+}
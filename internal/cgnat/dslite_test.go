@@ -0,0 +1,154 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+type rangeCall struct {
+	start, end string
+	isAdd      bool
+}
+
+type fakeDSLite struct {
+	aftr6, aftr4 net.IP
+	addresses    []net.IP
+	ranges       []rangeCall
+}
+
+func (f *fakeDSLite) DSLiteSetAFTRAddr(ip6, ip4 net.IP) error {
+	f.aftr6, f.aftr4 = ip6, ip4
+	return nil
+}
+
+func (f *fakeDSLite) DSLiteGetAFTRAddr() (net.IP, net.IP, error) { return f.aftr6, f.aftr4, nil }
+
+func (f *fakeDSLite) DSLiteAddDelPoolAddrRange(start, end net.IP, isAdd bool) error {
+	f.ranges = append(f.ranges, rangeCall{start.String(), end.String(), isAdd})
+	return nil
+}
+
+func (f *fakeDSLite) DSLiteAddressDump() ([]net.IP, error) { return f.addresses, nil }
+
+func dsLiteConfig() *config.Config {
+	return &config.Config{
+		CGNAT: &cgnat.Config{
+			Pools: map[string]*cgnat.Pool{
+				"softwire": {
+					Mode:                   cgnat.ModeDSLite,
+					BlockSize:              512,
+					MaxBlocksPerSubscriber: 2,
+					PortRange:              "1024-65535",
+					OutsideAddresses:       []string{"100.64.0.0/30"},
+					ExcludedAddresses:      []string{"100.64.0.3"},
+					AFTR:                   &cgnat.AFTRConfig{Address: "2001:db8:ffff::1", Name: "aftr.example.net"},
+				},
+			},
+		},
+		ServiceGroups: map[string]*servicegroup.Config{
+			"v6only": {CGNAT: &servicegroup.CGNATConfig{Policy: "softwire"}},
+		},
+	}
+}
+
+func TestReconcileDSLite_ProgramsAFTRAndRanges(t *testing.T) {
+	cfg := dsLiteConfig()
+	dp := &fakeDSLite{addresses: []net.IP{net.ParseIP("100.64.0.3").To4(), net.ParseIP("100.64.0.9").To4()}}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, pbaConfig())
+	c.dslite = dp
+
+	if err := c.reconcileDSLite(cfg); err != nil {
+		t.Fatalf("reconcileDSLite: %v", err)
+	}
+	if !dp.aftr6.Equal(net.ParseIP("2001:db8:ffff::1")) || !dp.aftr4.Equal(net.ParseIP(cgnat.DefaultAFTRIPv4)) {
+		t.Fatalf("AFTR = %s / %s", dp.aftr6, dp.aftr4)
+	}
+	want := []rangeCall{
+		{"100.64.0.3", "100.64.0.3", false},
+		{"100.64.0.9", "100.64.0.9", false},
+		{"100.64.0.0", "100.64.0.2", true},
+	}
+	if len(dp.ranges) != len(want) {
+		t.Fatalf("range calls = %+v, want %+v", dp.ranges, want)
+	}
+	for i := range want {
+		if dp.ranges[i] != want[i] {
+			t.Fatalf("range call %d = %+v, want %+v", i, dp.ranges[i], want[i])
+		}
+	}
+}
+
+func TestDSLiteLifecycle_AllocatesAndReleasesB4Block(t *testing.T) {
+	cfg := dsLiteConfig()
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, cfg)
+	bus := local.NewBus()
+	c.eventBus = bus
+	mappings := make(chan *events.CGNATMappingEvent, 4)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		mappings <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	b4 := net.ParseIP("2001:db8:1::42")
+	sess := &models.IPoESession{
+		SessionID:    "s1",
+		AccessType:   string(models.AccessTypeIPoE),
+		Protocol:     string(models.ProtocolDHCPv6),
+		ServiceGroup: "v6only",
+		IPv6Address:  b4,
+	}
+	c.dispatchLifecycle(events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		Protocol:   models.ProtocolDHCPv6,
+		SessionID:  "s1",
+		State:      models.SessionStateActive,
+		Session:    sess,
+	}})
+
+	add := nextMappingEvent(t, mappings)
+	if !add.IsAdd || !add.Mapping.InsideIP.Equal(b4) || add.Mapping.PoolName != "softwire" {
+		t.Fatalf("add event: %+v", add.Mapping)
+	}
+	if got := len(c.pools.GetMappings("softwire", b4, 0)); got != 1 {
+		t.Fatalf("B4 holds %d blocks, want 1", got)
+	}
+
+	sess.State = models.SessionStateReleased
+	c.dispatchLifecycle(events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		Protocol:   models.ProtocolDHCPv6,
+		SessionID:  "s1",
+		State:      models.SessionStateReleased,
+		Session:    sess,
+	}})
+
+	del := nextMappingEvent(t, mappings)
+	if del.IsAdd || !del.Mapping.InsideIP.Equal(b4) {
+		t.Fatalf("release event: %+v", del.Mapping)
+	}
+	if got := len(c.pools.GetMappings("softwire", b4, 0)); got != 0 {
+		t.Fatalf("B4 still holds %d blocks after release", got)
+	}
+}
+
+func nextMappingEvent(t *testing.T, ch <-chan *events.CGNATMappingEvent) *events.CGNATMappingEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no mapping event")
+		return nil
+	}
+}
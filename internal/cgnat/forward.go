@@ -0,0 +1,623 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// forwardNamespace holds every port forward keyed by forward ID. Static
+// forwards are rebuilt from config at start; their entries are kept so
+// the bindings of a previous run can be found and removed.
+const forwardNamespace = "cgnat_forwards"
+
+// forwardSweepInterval is how often expired PCP forwards are removed.
+const forwardSweepInterval = 10 * time.Second
+
+// pnatBinding is one policy NAT binding and the interfaces it is
+// attached to.
+type pnatBinding struct {
+	index    uint32
+	attached []uint32
+}
+
+// portForward is one forward and, while active, the bindings carrying
+// it: out2in on the pool's outside interfaces rewrites the destination
+// to the subscriber, in2out on the session interface rewrites the
+// subscriber's replies to the outside port. Both bypass the block
+// mapping, which only translates flows the subscriber opens.
+type portForward struct {
+	mapping models.CGNATMapping
+	srgName string
+	active  bool
+	in2out  *pnatBinding
+	out2in  *pnatBinding
+}
+
+// snapshot returns a copy of the forward's mapping that later state
+// changes do not reach.
+func (f *portForward) snapshot() models.CGNATMapping {
+	m := f.mapping
+	fwd := *m.Forward
+	m.Forward = &fwd
+	return m
+}
+
+func forwardProtocolNumber(proto string) uint8 {
+	switch proto {
+	case "tcp":
+		return 6
+	case "udp":
+		return 17
+	}
+	return 0
+}
+
+func outsideForwardKey(m *models.CGNATMapping) string {
+	return fmt.Sprintf("%s/%s/%d", m.Forward.Protocol, m.OutsideIP, m.PortBlockStart)
+}
+
+// forwardTuples returns the match and rewrite of both directions of a
+// forward.
+func forwardTuples(m *models.CGNATMapping) (in2outMatch, in2outRewrite, out2inMatch, out2inRewrite southbound.PNATTuple) {
+	proto := forwardProtocolNumber(m.Forward.Protocol)
+	in2outMatch = southbound.PNATTuple{Src: m.InsideIP.To4(), Proto: proto, SrcPort: m.Forward.InsidePort}
+	in2outRewrite = southbound.PNATTuple{Src: m.OutsideIP.To4(), SrcPort: m.PortBlockStart}
+	out2inMatch = southbound.PNATTuple{Dst: m.OutsideIP.To4(), Proto: proto, DstPort: m.PortBlockStart}
+	out2inRewrite = southbound.PNATTuple{Dst: m.InsideIP.To4(), DstPort: m.Forward.InsidePort}
+	return
+}
+
+func staticForwardMapping(pf cgnat.PortForward) *models.CGNATMapping {
+	proto, _ := cgnat.NormalizeForwardProtocol(pf.Protocol)
+	return &models.CGNATMapping{
+		InsideIP:       net.ParseIP(pf.InsideIP).To4(),
+		OutsideIP:      net.ParseIP(pf.OutsideIP).To4(),
+		PortBlockStart: pf.OutsidePort,
+		PortBlockEnd:   pf.OutsidePort,
+		Forward: &models.CGNATPortForward{
+			Protocol:    proto,
+			InsidePort:  pf.InsidePort,
+			Source:      models.CGNATForwardStatic,
+			State:       models.CGNATForwardPending,
+			Description: pf.Description,
+		},
+	}
+}
+
+// AddPortForward installs an API forward. The outside port must sit in
+// a block the inside address holds now and carry no live translation;
+// unlike static forwards, API forwards are never left pending.
+func (c *Component) AddPortForward(ctx context.Context, req *models.CGNATPortForwardRequest) (*models.CGNATMapping, error) {
+	insideIP := net.ParseIP(req.InsideIP)
+	if insideIP == nil || insideIP.To4() == nil {
+		return nil, fmt.Errorf("inside_ip %q is not an IPv4 address", req.InsideIP)
+	}
+	outsideIP := net.ParseIP(req.OutsideIP)
+	if outsideIP == nil || outsideIP.To4() == nil {
+		return nil, fmt.Errorf("outside_ip %q is not an IPv4 address", req.OutsideIP)
+	}
+	m := &models.CGNATMapping{
+		InsideIP:       insideIP.To4(),
+		OutsideIP:      outsideIP.To4(),
+		PortBlockStart: req.OutsidePort,
+		PortBlockEnd:   req.OutsidePort,
+		Forward: &models.CGNATPortForward{
+			Protocol:    req.Protocol,
+			InsidePort:  req.InsidePort,
+			Source:      models.CGNATForwardAPI,
+			Description: req.Description,
+		},
+	}
+	return c.addForward(ctx, m, true)
+}
+
+// addForward validates and records a forward, installing it when the
+// inside address holds the outside port's block. With requireActive a
+// forward that cannot be installed now is rejected instead of left
+// pending.
+func (c *Component) addForward(ctx context.Context, m *models.CGNATMapping, requireActive bool) (*models.CGNATMapping, error) {
+	proto, ok := cgnat.NormalizeForwardProtocol(m.Forward.Protocol)
+	if !ok {
+		return nil, fmt.Errorf("protocol must be tcp or udp, got %q", m.Forward.Protocol)
+	}
+	m.Forward.Protocol = proto
+	if m.Forward.InsidePort == 0 || m.PortBlockStart == 0 {
+		return nil, fmt.Errorf("inside and outside ports are required")
+	}
+	m.PortBlockEnd = m.PortBlockStart
+
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil {
+		return nil, fmt.Errorf("no CGNAT configuration")
+	}
+	m.PoolName = cfg.CGNAT.ForwardPool(m.OutsideIP, m.PortBlockStart)
+	if m.PoolName == "" {
+		return nil, fmt.Errorf("%s:%d is not in the outside addresses and port range of any pba pool", m.OutsideIP, m.PortBlockStart)
+	}
+	m.PoolID = c.poolIDMap[m.PoolName]
+
+	c.fwdMu.Lock()
+	id := m.ForwardID()
+	if _, exists := c.forwards[id]; exists {
+		c.fwdMu.Unlock()
+		return nil, fmt.Errorf("forward %s already exists", id)
+	}
+	if other := c.forwardByOutsideLocked(outsideForwardKey(m)); other != nil {
+		c.fwdMu.Unlock()
+		return nil, fmt.Errorf("%s %s:%d is already forwarded to %s:%d", proto, m.OutsideIP, m.PortBlockStart,
+			other.mapping.InsideIP, other.mapping.Forward.InsidePort)
+	}
+
+	f := &portForward{mapping: *m}
+	f.mapping.Forward.State = models.CGNATForwardPending
+	block := c.reverse.Lookup(m.OutsideIP, m.PortBlockStart)
+	if block != nil && block.InsideIP.Equal(m.InsideIP) {
+		inUse, err := c.outsidePortInUse(m)
+		if err != nil {
+			c.fwdMu.Unlock()
+			return nil, err
+		}
+		if inUse {
+			c.fwdMu.Unlock()
+			return nil, fmt.Errorf("%s %s:%d is in use by a live translation", proto, m.OutsideIP, m.PortBlockStart)
+		}
+		if err := c.installForwardLocked(f, block, c.sessionSRGName(ctx, block.SessionID)); err != nil {
+			c.fwdMu.Unlock()
+			return nil, err
+		}
+	} else if requireActive {
+		c.fwdMu.Unlock()
+		return nil, fmt.Errorf("%s:%d is not in a port block held by %s", m.OutsideIP, m.PortBlockStart, m.InsideIP)
+	}
+	c.forwards[id] = f
+	c.persistForward(ctx, f)
+	out := f.snapshot()
+	c.fwdMu.Unlock()
+
+	if out.Forward.State == models.CGNATForwardActive {
+		c.publishMappingEvent(f.srgName, &out, true)
+	}
+	c.logger.Info("CGNAT port forward added",
+		"forward", id,
+		"outside", fmt.Sprintf("%s:%d", out.OutsideIP, out.PortBlockStart),
+		"source", out.Forward.Source,
+		"state", out.Forward.State)
+	return &out, nil
+}
+
+// DeletePortForward removes an API or PCP forward. Static forwards
+// belong to the configuration.
+func (c *Component) DeletePortForward(ctx context.Context, protocol string, insideIP net.IP, insidePort uint16) error {
+	proto, _ := cgnat.NormalizeForwardProtocol(protocol)
+	probe := &models.CGNATMapping{InsideIP: insideIP.To4(), Forward: &models.CGNATPortForward{Protocol: proto, InsidePort: insidePort}}
+	id := probe.ForwardID()
+
+	c.fwdMu.Lock()
+	f, ok := c.forwards[id]
+	if !ok {
+		c.fwdMu.Unlock()
+		return fmt.Errorf("forward %s not found", id)
+	}
+	if f.mapping.Forward.Source == models.CGNATForwardStatic {
+		c.fwdMu.Unlock()
+		return fmt.Errorf("forward %s is static; remove it from the configuration", id)
+	}
+	released, wasActive := c.dropForwardLocked(ctx, id, f)
+	c.fwdMu.Unlock()
+
+	if wasActive {
+		c.publishMappingEvent(f.srgName, &released, false)
+	}
+	c.logger.Info("CGNAT port forward deleted", "forward", id)
+	return nil
+}
+
+// GetPortForwards returns every forward, active and pending, ordered by
+// forward ID.
+func (c *Component) GetPortForwards() []models.CGNATMapping {
+	c.fwdMu.Lock()
+	out := make([]models.CGNATMapping, 0, len(c.forwards))
+	for _, f := range c.forwards {
+		out = append(out, f.snapshot())
+	}
+	c.fwdMu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ForwardID() < out[j].ForwardID() })
+	return out
+}
+
+func (c *Component) forwardByOutsideLocked(key string) *portForward {
+	for _, f := range c.forwards {
+		if outsideForwardKey(&f.mapping) == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// dropForwardLocked removes a forward's bindings and its record,
+// returning the mapping as it was while installed.
+func (c *Component) dropForwardLocked(ctx context.Context, id string, f *portForward) (models.CGNATMapping, bool) {
+	released := f.snapshot()
+	wasActive := f.active
+	c.removeForwardLocked(f)
+	delete(c.forwards, id)
+	if c.opdb != nil {
+		c.opdb.Delete(ctx, forwardNamespace, id)
+	}
+	return released, wasActive
+}
+
+// installForwardLocked binds a forward to the subscriber holding its
+// outside port and programs both directions.
+func (c *Component) installForwardLocked(f *portForward, block *models.CGNATMapping, srgName string) error {
+	m := &f.mapping
+	m.SessionID = block.SessionID
+	m.SwIfIndex = block.SwIfIndex
+	m.InsideVRFID = block.InsideVRFID
+
+	outside := c.poolOutside[m.PoolName]
+	if len(outside) == 0 {
+		return fmt.Errorf("pool %q has no outside interfaces in the dataplane", m.PoolName)
+	}
+
+	in2outMatch, in2outRewrite, out2inMatch, out2inRewrite := forwardTuples(m)
+	out2in, err := c.addBinding(out2inMatch, out2inRewrite, outside)
+	if err != nil {
+		return fmt.Errorf("out2in binding: %w", err)
+	}
+	in2out, err := c.addBinding(in2outMatch, in2outRewrite, []uint32{m.SwIfIndex})
+	if err != nil {
+		c.removeBinding(out2in)
+		return fmt.Errorf("in2out binding: %w", err)
+	}
+
+	f.out2in, f.in2out = out2in, in2out
+	f.srgName = srgName
+	f.active = true
+	m.Forward.State = models.CGNATForwardActive
+	return nil
+}
+
+// removeForwardLocked removes a forward's bindings and marks it pending.
+func (c *Component) removeForwardLocked(f *portForward) {
+	c.removeBinding(f.out2in)
+	c.removeBinding(f.in2out)
+	f.out2in, f.in2out = nil, nil
+	f.active = false
+	f.mapping.SessionID = ""
+	f.mapping.SwIfIndex = 0
+	f.mapping.Forward.State = models.CGNATForwardPending
+}
+
+func (c *Component) addBinding(match, rewrite southbound.PNATTuple, swIfIndexes []uint32) (*pnatBinding, error) {
+	index, err := c.pnat.PNATBindingAdd(match, rewrite)
+	if err != nil {
+		return nil, err
+	}
+	b := &pnatBinding{index: index}
+	for _, swIfIndex := range swIfIndexes {
+		if err := c.pnat.PNATBindingAttach(swIfIndex, index); err != nil {
+			c.removeBinding(b)
+			return nil, err
+		}
+		b.attached = append(b.attached, swIfIndex)
+	}
+	return b, nil
+}
+
+func (c *Component) removeBinding(b *pnatBinding) {
+	if b == nil {
+		return
+	}
+	for _, swIfIndex := range b.attached {
+		if err := c.pnat.PNATBindingDetach(swIfIndex, b.index); err != nil {
+			c.logger.Warn("Failed to detach port forward binding", "binding", b.index, "sw_if_index", swIfIndex, "error", err)
+		}
+	}
+	if err := c.pnat.PNATBindingDel(b.index); err != nil {
+		c.logger.Warn("Failed to delete port forward binding", "binding", b.index, "error", err)
+	}
+}
+
+// outsidePortInUse reports whether a live translation already holds the
+// forward's outside port; a forward over it would steal its replies.
+func (c *Component) outsidePortInUse(m *models.CGNATMapping) (bool, error) {
+	sessions, err := c.dataplane.CGNATDumpSessions(southbound.CGNATSessionFilter{
+		OutsideIP:   m.OutsideIP,
+		OutsidePort: m.PortBlockStart,
+		Proto:       forwardProtocolNumber(m.Forward.Protocol),
+		Limit:       1,
+	})
+	if err != nil {
+		return false, fmt.Errorf("check live translations: %w", err)
+	}
+	return len(sessions) > 0, nil
+}
+
+func (c *Component) persistForward(ctx context.Context, f *portForward) {
+	if c.opdb == nil {
+		return
+	}
+	m := f.snapshot()
+	if data, err := json.Marshal(&m); err == nil {
+		c.opdb.Put(ctx, forwardNamespace, m.ForwardID(), data)
+	}
+}
+
+func (c *Component) sessionSRGName(ctx context.Context, sessionID string) string {
+	if c.sessionProvider == nil || sessionID == "" {
+		return ""
+	}
+	if sess, ok := c.sessionProvider.SessionSnapshot(ctx, sessionID); ok {
+		return sess.GetSRGName()
+	}
+	return ""
+}
+
+// activateForwards installs the pending forwards of a subscriber whose
+// block was just committed, including any synced from the HA peer.
+func (c *Component) activateForwards(sessionID, srgName string, insideIP net.IP) {
+	if insideIP.To4() == nil {
+		return
+	}
+	c.restoreSyncedForwards(sessionID)
+
+	var added []models.CGNATMapping
+	c.fwdMu.Lock()
+	for id, f := range c.forwards {
+		if f.active || !f.mapping.InsideIP.Equal(insideIP) {
+			continue
+		}
+		block := c.reverse.Lookup(f.mapping.OutsideIP, f.mapping.PortBlockStart)
+		if block == nil || !block.InsideIP.Equal(insideIP) {
+			continue
+		}
+		if err := c.installForwardLocked(f, block, srgName); err != nil {
+			c.logger.Warn("Failed to install CGNAT port forward", "forward", id, "session", sessionID, "error", err)
+			continue
+		}
+		c.persistForward(context.Background(), f)
+		added = append(added, f.snapshot())
+	}
+	c.fwdMu.Unlock()
+
+	for i := range added {
+		c.publishMappingEvent(srgName, &added[i], true)
+	}
+}
+
+// deactivateForwards removes a released subscriber's forwards from the
+// dataplane. Static and API forwards wait for the next holder of their
+// block; PCP forwards belong to the CPE's session and are dropped.
+func (c *Component) deactivateForwards(sessionID, srgName string) {
+	var released []models.CGNATMapping
+	c.fwdMu.Lock()
+	for id, f := range c.forwards {
+		if !f.active || f.mapping.SessionID != sessionID {
+			continue
+		}
+		if f.mapping.Forward.Source == models.CGNATForwardPCP {
+			m, _ := c.dropForwardLocked(context.Background(), id, f)
+			released = append(released, m)
+			continue
+		}
+		released = append(released, f.snapshot())
+		c.removeForwardLocked(f)
+		c.persistForward(context.Background(), f)
+	}
+	c.fwdMu.Unlock()
+
+	for i := range released {
+		c.publishMappingEvent(srgName, &released[i], false)
+	}
+}
+
+// restoreSyncedForwards picks up the forwards the HA peer synced for a
+// session, keyed "<session>/<forward>" beside its block, as pending
+// forwards for activateForwards to install.
+func (c *Component) restoreSyncedForwards(sessionID string) {
+	if c.opdb == nil {
+		return
+	}
+	prefix := sessionID + "/"
+	synced := map[string]*models.CGNATMapping{}
+	c.opdb.Load(context.Background(), opdb.NamespaceHASyncedCGNAT, func(key string, value []byte) error {
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if m, err := ha.DecodeCGNATCheckpoint(value); err == nil && m.Forward != nil {
+			synced[key] = m
+		}
+		return nil
+	})
+	if len(synced) == 0 {
+		return
+	}
+
+	cfg, _ := c.cfgMgr.GetRunning()
+	now := time.Now()
+	c.fwdMu.Lock()
+	for key, m := range synced {
+		c.opdb.Delete(context.Background(), opdb.NamespaceHASyncedCGNAT, key)
+		id := m.ForwardID()
+		if _, exists := c.forwards[id]; exists || c.forwardByOutsideLocked(outsideForwardKey(m)) != nil {
+			continue
+		}
+		if m.Forward.ExpiresAt != nil && !m.Forward.ExpiresAt.After(now) {
+			continue
+		}
+		if cfg != nil {
+			m.PoolName = cfg.CGNAT.ForwardPool(m.OutsideIP, m.PortBlockStart)
+			m.PoolID = c.poolIDMap[m.PoolName]
+		}
+		m.SessionID = ""
+		m.Forward.State = models.CGNATForwardPending
+		c.forwards[id] = &portForward{mapping: *m}
+	}
+	c.fwdMu.Unlock()
+}
+
+// loadForwards builds the forward table at start: static forwards from
+// config, API forwards and unexpired PCP forwards from opdb, all
+// pending until restore commits their blocks. Bindings a previous run
+// left in the dataplane are removed first so reinstalling cannot
+// collide with them.
+func (c *Component) loadForwards(ctx context.Context, cfg *cgnat.Config) {
+	c.fwdMu.Lock()
+	defer c.fwdMu.Unlock()
+
+	for _, pf := range cfg.PortForwards {
+		m := staticForwardMapping(pf)
+		m.PoolName = cfg.ForwardPool(m.OutsideIP, m.PortBlockStart)
+		m.PoolID = c.poolIDMap[m.PoolName]
+		c.forwards[m.ForwardID()] = &portForward{mapping: *m}
+	}
+	if c.opdb == nil {
+		return
+	}
+
+	now := time.Now()
+	var stale []string
+	c.opdb.Load(ctx, forwardNamespace, func(key string, value []byte) error {
+		var m models.CGNATMapping
+		if err := json.Unmarshal(value, &m); err != nil || m.Forward == nil {
+			stale = append(stale, key)
+			return nil
+		}
+		if m.SessionID != "" {
+			c.clearStaleBindings(&m)
+		}
+		switch {
+		case m.Forward.Source == models.CGNATForwardStatic:
+			if _, ok := c.forwards[key]; !ok {
+				stale = append(stale, key)
+			}
+			return nil
+		case m.Forward.Source == models.CGNATForwardPCP && (m.Forward.ExpiresAt == nil || !m.Forward.ExpiresAt.After(now)):
+			stale = append(stale, key)
+			return nil
+		}
+		if _, ok := c.forwards[key]; ok || c.forwardByOutsideLocked(outsideForwardKey(&m)) != nil {
+			c.logger.Warn("Dropping persisted CGNAT port forward that conflicts with configuration", "forward", key)
+			stale = append(stale, key)
+			return nil
+		}
+		m.PoolName = cfg.ForwardPool(m.OutsideIP, m.PortBlockStart)
+		if m.PoolName == "" {
+			stale = append(stale, key)
+			return nil
+		}
+		m.PoolID = c.poolIDMap[m.PoolName]
+		m.SessionID = ""
+		m.SwIfIndex = 0
+		m.Forward.State = models.CGNATForwardPending
+		c.forwards[key] = &portForward{mapping: m}
+		return nil
+	})
+	for _, key := range stale {
+		c.opdb.Delete(ctx, forwardNamespace, key)
+	}
+}
+
+// clearStaleBindings finds a persisted forward's bindings by their match
+// and removes them.
+func (c *Component) clearStaleBindings(m *models.CGNATMapping) {
+	in2outMatch, _, out2inMatch, _ := forwardTuples(m)
+	found := map[uint32][]uint32{}
+	for _, swIfIndex := range c.poolOutside[m.PoolName] {
+		if index, err := c.pnat.PNATFlowLookup(swIfIndex, out2inMatch); err == nil {
+			found[index] = append(found[index], swIfIndex)
+		}
+	}
+	if index, err := c.pnat.PNATFlowLookup(m.SwIfIndex, in2outMatch); err == nil {
+		found[index] = append(found[index], m.SwIfIndex)
+	}
+	for index, attached := range found {
+		c.removeBinding(&pnatBinding{index: index, attached: attached})
+	}
+}
+
+// reinstallForwards reprograms active forwards after the dataplane
+// restarted and lost every binding, on the sessions' live interfaces.
+func (c *Component) reinstallForwards(ctx context.Context) {
+	c.fwdMu.Lock()
+	defer c.fwdMu.Unlock()
+
+	for id, f := range c.forwards {
+		if !f.active {
+			continue
+		}
+		sessionID, srgName := f.mapping.SessionID, f.srgName
+		f.out2in, f.in2out = nil, nil
+		f.active = false
+		f.mapping.Forward.State = models.CGNATForwardPending
+
+		block := c.reverse.Lookup(f.mapping.OutsideIP, f.mapping.PortBlockStart)
+		if block == nil || block.SessionID != sessionID {
+			continue
+		}
+		live := *block
+		if swIfIndex, ok := resolveIfIndex(ctx, c.sessionProvider, sessionID); ok {
+			live.SwIfIndex = swIfIndex
+		}
+		if err := c.installForwardLocked(f, &live, srgName); err != nil {
+			c.logger.Error("CGNAT recover: port forward reinstall failed", "forward", id, "error", err)
+			continue
+		}
+		c.persistForward(ctx, f)
+	}
+}
+
+// expireForwards drops PCP forwards whose lifetime has run out.
+func (c *Component) expireForwards(now time.Time) {
+	type expired struct {
+		srgName string
+		mapping models.CGNATMapping
+	}
+	var gone []expired
+	c.fwdMu.Lock()
+	for id, f := range c.forwards {
+		exp := f.mapping.Forward.ExpiresAt
+		if exp == nil || exp.After(now) {
+			continue
+		}
+		m, wasActive := c.dropForwardLocked(context.Background(), id, f)
+		if wasActive {
+			gone = append(gone, expired{f.srgName, m})
+		}
+	}
+	c.fwdMu.Unlock()
+
+	for i := range gone {
+		c.publishMappingEvent(gone[i].srgName, &gone[i].mapping, false)
+	}
+}
+
+func (c *Component) sweepForwards(ctx context.Context) {
+	ticker := time.NewTicker(forwardSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.expireForwards(now)
+		}
+	}
+}
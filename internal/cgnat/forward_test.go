@@ -0,0 +1,271 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type pnatAttach struct {
+	swIfIndex, index uint32
+	isAttach         bool
+}
+
+type fakePNAT struct {
+	next     uint32
+	bindings map[uint32][2]southbound.PNATTuple
+	attaches []pnatAttach
+	flows    map[string]uint32
+}
+
+func newFakePNAT() *fakePNAT {
+	return &fakePNAT{bindings: map[uint32][2]southbound.PNATTuple{}, flows: map[string]uint32{}}
+}
+
+func (f *fakePNAT) PNATBindingAdd(match, rewrite southbound.PNATTuple) (uint32, error) {
+	f.next++
+	f.bindings[f.next] = [2]southbound.PNATTuple{match, rewrite}
+	return f.next, nil
+}
+
+func (f *fakePNAT) PNATBindingDel(index uint32) error {
+	delete(f.bindings, index)
+	return nil
+}
+
+func (f *fakePNAT) PNATBindingAttach(swIfIndex, index uint32) error {
+	f.attaches = append(f.attaches, pnatAttach{swIfIndex, index, true})
+	return nil
+}
+
+func (f *fakePNAT) PNATBindingDetach(swIfIndex, index uint32) error {
+	f.attaches = append(f.attaches, pnatAttach{swIfIndex, index, false})
+	return nil
+}
+
+func (f *fakePNAT) PNATFlowLookup(swIfIndex uint32, match southbound.PNATTuple) (uint32, error) {
+	if index, ok := f.flows[fmt.Sprintf("%d/%+v", swIfIndex, match)]; ok {
+		return index, nil
+	}
+	return 0, fmt.Errorf("no flow")
+}
+
+// newForwardComponent returns a component with one subscriber block
+// committed for 10.0.0.5 on interface 17 and outside interface 5.
+func newForwardComponent(t *testing.T) (*Component, *fakePNAT, *models.CGNATMapping, chan *events.CGNATMappingEvent) {
+	t.Helper()
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, pbaConfig())
+	pnat := newFakePNAT()
+	c.pnat = pnat
+	c.poolOutside["p1"] = []uint32{5}
+	bus := local.NewBus()
+	c.eventBus = bus
+	ch := make(chan *events.CGNATMappingEvent, 8)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		ch <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	block, err := c.pools.AllocateBlock("p1", net.ParseIP("10.0.0.5").To4(), 0, 17)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	block.SessionID = "s1"
+	c.commitMapping("s1", "p1", block, "", false)
+	nextMappingEvent(t, ch)
+	return c, pnat, block, ch
+}
+
+func TestAddPortForward_InstallsBothDirections(t *testing.T) {
+	c, pnat, block, ch := newForwardComponent(t)
+
+	m, err := c.AddPortForward(context.Background(), &models.CGNATPortForwardRequest{
+		Protocol:    "TCP",
+		InsideIP:    "10.0.0.5",
+		InsidePort:  8080,
+		OutsideIP:   block.OutsideIP.String(),
+		OutsidePort: block.PortBlockStart + 3,
+	})
+	if err != nil {
+		t.Fatalf("AddPortForward: %v", err)
+	}
+	if m.Forward.State != models.CGNATForwardActive || m.SessionID != "s1" || m.Forward.Protocol != "tcp" {
+		t.Fatalf("forward = %+v / %+v", m, m.Forward)
+	}
+	if len(pnat.bindings) != 2 {
+		t.Fatalf("bindings = %d, want 2", len(pnat.bindings))
+	}
+	want := []pnatAttach{{5, 1, true}, {17, 2, true}}
+	for i := range want {
+		if pnat.attaches[i] != want[i] {
+			t.Fatalf("attach %d = %+v, want %+v", i, pnat.attaches[i], want[i])
+		}
+	}
+	out2in := pnat.bindings[1]
+	if !out2in[0].Dst.Equal(block.OutsideIP) || out2in[0].DstPort != block.PortBlockStart+3 || out2in[1].DstPort != 8080 {
+		t.Fatalf("out2in = %+v", out2in)
+	}
+	if ev := nextMappingEvent(t, ch); !ev.IsAdd || ev.Mapping.Forward == nil {
+		t.Fatalf("add event = %+v", ev.Mapping)
+	}
+	if _, ok := c.opdb.(*fakeOpDB).ns[forwardNamespace]["tcp/10.0.0.5/8080"]; !ok {
+		t.Fatal("forward not persisted")
+	}
+
+	if err := c.DeletePortForward(context.Background(), "tcp", net.ParseIP("10.0.0.5"), 8080); err != nil {
+		t.Fatalf("DeletePortForward: %v", err)
+	}
+	if len(pnat.bindings) != 0 {
+		t.Fatalf("bindings left after delete: %+v", pnat.bindings)
+	}
+	if ev := nextMappingEvent(t, ch); ev.IsAdd {
+		t.Fatal("delete published an add event")
+	}
+}
+
+func TestAddPortForward_Rejects(t *testing.T) {
+	c, _, block, _ := newForwardComponent(t)
+	req := func(insidePort, outsidePort uint16) *models.CGNATPortForwardRequest {
+		return &models.CGNATPortForwardRequest{
+			Protocol:    "udp",
+			InsideIP:    "10.0.0.5",
+			InsidePort:  insidePort,
+			OutsideIP:   block.OutsideIP.String(),
+			OutsidePort: outsidePort,
+		}
+	}
+	if _, err := c.AddPortForward(context.Background(), req(53, block.PortBlockStart)); err != nil {
+		t.Fatalf("first forward: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		req  *models.CGNATPortForwardRequest
+		want string
+	}{
+		{"duplicate inside", req(53, block.PortBlockStart+1), "already exists"},
+		{"outside taken", req(54, block.PortBlockStart), "already forwarded"},
+		{"not held", req(55, block.PortBlockEnd+1), "not in a port block held"},
+		{"bad protocol", &models.CGNATPortForwardRequest{Protocol: "icmp", InsideIP: "10.0.0.5", InsidePort: 1, OutsideIP: block.OutsideIP.String(), OutsidePort: block.PortBlockStart + 2}, "tcp or udp"},
+	}
+	for _, tc := range cases {
+		_, err := c.AddPortForward(context.Background(), tc.req)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	c.dataplane.(*fakeDP).sessions = []southbound.CGNATSession{{OutsideIP: block.OutsideIP, OutsidePort: block.PortBlockStart + 2}}
+	_, err := c.AddPortForward(context.Background(), req(56, block.PortBlockStart+2))
+	if err == nil || !strings.Contains(err.Error(), "live translation") {
+		t.Fatalf("live translation: err = %v", err)
+	}
+}
+
+func TestStaticForward_FollowsBlockHolder(t *testing.T) {
+	c, pnat, block, ch := newForwardComponent(t)
+	cfg := pbaConfig().CGNAT
+	cfg.PortForwards = []cgnat.PortForward{{
+		Protocol:    "tcp",
+		InsideIP:    "10.0.0.5",
+		InsidePort:  22,
+		OutsideIP:   block.OutsideIP.String(),
+		OutsidePort: block.PortBlockStart + 10,
+	}}
+	c.loadForwards(context.Background(), cfg)
+	if got := c.GetPortForwards(); len(got) != 1 || got[0].Forward.State != models.CGNATForwardPending {
+		t.Fatalf("forwards after load = %+v", got)
+	}
+
+	c.activateForwards("s1", "", block.InsideIP)
+	if got := c.GetPortForwards()[0]; got.Forward.State != models.CGNATForwardActive || got.SessionID != "s1" {
+		t.Fatalf("forward after activate = %+v / %+v", got, got.Forward)
+	}
+	nextMappingEvent(t, ch)
+
+	c.deactivateForwards("s1", "")
+	got := c.GetPortForwards()
+	if len(got) != 1 || got[0].Forward.State != models.CGNATForwardPending || got[0].SessionID != "" {
+		t.Fatalf("forward after release = %+v", got)
+	}
+	if len(pnat.bindings) != 0 {
+		t.Fatalf("bindings left after release: %+v", pnat.bindings)
+	}
+	if ev := nextMappingEvent(t, ch); ev.IsAdd {
+		t.Fatal("release published an add event")
+	}
+
+	err := c.DeletePortForward(context.Background(), "tcp", block.InsideIP, 22)
+	if err == nil || !strings.Contains(err.Error(), "static") {
+		t.Fatalf("delete static: err = %v", err)
+	}
+}
+
+func TestLoadForwards_ClearsStaleBindingsAndExpired(t *testing.T) {
+	c, pnat, block, _ := newForwardComponent(t)
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	api := models.CGNATMapping{
+		SessionID:      "old",
+		SwIfIndex:      9,
+		PoolName:       "p1",
+		InsideIP:       block.InsideIP,
+		OutsideIP:      block.OutsideIP,
+		PortBlockStart: block.PortBlockStart + 4,
+		PortBlockEnd:   block.PortBlockStart + 4,
+		Forward:        &models.CGNATPortForward{Protocol: "tcp", InsidePort: 443, Source: models.CGNATForwardAPI, State: models.CGNATForwardActive},
+	}
+	expired := api
+	expired.SessionID = ""
+	expired.PortBlockStart, expired.PortBlockEnd = block.PortBlockStart+5, block.PortBlockStart+5
+	expired.Forward = &models.CGNATPortForward{Protocol: "udp", InsidePort: 5000, Source: models.CGNATForwardPCP, ExpiresAt: &past}
+	live := expired
+	live.PortBlockStart, live.PortBlockEnd = block.PortBlockStart+6, block.PortBlockStart+6
+	live.Forward = &models.CGNATPortForward{Protocol: "udp", InsidePort: 5001, Source: models.CGNATForwardPCP, ExpiresAt: &future}
+
+	store := c.opdb.(*fakeOpDB)
+	for _, m := range []models.CGNATMapping{api, expired, live} {
+		data, _ := json.Marshal(&m)
+		store.Put(context.Background(), forwardNamespace, m.ForwardID(), data)
+	}
+	_, _, out2inMatch, _ := forwardTuples(&api)
+	pnat.flows[fmt.Sprintf("%d/%+v", 5, out2inMatch)] = 42
+
+	c.loadForwards(context.Background(), pbaConfig().CGNAT)
+
+	if _, ok := store.ns[forwardNamespace][expired.ForwardID()]; ok {
+		t.Fatal("expired PCP forward kept")
+	}
+	got := c.GetPortForwards()
+	if len(got) != 2 {
+		t.Fatalf("forwards = %+v, want api and live PCP", got)
+	}
+	for _, m := range got {
+		if m.Forward.State != models.CGNATForwardPending || m.SessionID != "" {
+			t.Fatalf("loaded forward not pending: %+v", m)
+		}
+	}
+	detached := false
+	for _, a := range pnat.attaches {
+		if a == (pnatAttach{5, 42, false}) {
+			detached = true
+		}
+	}
+	if !detached {
+		t.Fatalf("stale binding not detached: %+v", pnat.attaches)
+	}
+}
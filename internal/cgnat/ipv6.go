@@ -0,0 +1,229 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+)
+
+// IPv6-only subscribers reach IPv4 through a DS-Lite AFTR or the NAT64
+// translator rather than the CGNAT plugin. Either way, port blocks are
+// allocated per subscriber from the pool's PBA state, keyed by the
+// subscriber's IPv6 address, and published as ordinary mapping events
+// so the logging exporters and HA sync cover them like NAT44 users.
+
+// isIPv6Mode reports whether a pool mode translates IPv6-only
+// subscribers.
+func isIPv6Mode(mode string) bool {
+	return mode == cgnat.ModeDSLite || mode == cgnat.ModeNAT64
+}
+
+// natPools returns the pools the CGNAT plugin owns: every pool except
+// the DS-Lite and NAT64 ones.
+func natPools(pools map[string]*cgnat.Pool) map[string]*cgnat.Pool {
+	out := make(map[string]*cgnat.Pool, len(pools))
+	for name, p := range pools {
+		if p != nil && isIPv6Mode(p.GetMode()) {
+			continue
+		}
+		out[name] = p
+	}
+	return out
+}
+
+// isIPv6Mapping reports whether a mapping belongs to an IPv6-only
+// subscriber: its inside address is IPv6.
+func isIPv6Mapping(m *models.CGNATMapping) bool {
+	return m.InsideIP != nil && m.InsideIP.To4() == nil
+}
+
+// ipv6Pool returns the DS-Lite or NAT64 pool a service group's CGNAT
+// policy selects, and its mode, or "".
+func (c *Component) ipv6Pool(serviceGroup string) (string, string) {
+	if serviceGroup == "" {
+		return "", ""
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil || cfg.ServiceGroups == nil {
+		return "", ""
+	}
+	sg, ok := cfg.ServiceGroups[serviceGroup]
+	if !ok || sg.CGNAT == nil || sg.CGNAT.Bypass {
+		return "", ""
+	}
+	if p := cfg.CGNAT.Pools[sg.CGNAT.Policy]; p != nil && isIPv6Mode(p.GetMode()) {
+		return sg.CGNAT.Policy, p.GetMode()
+	}
+	return "", ""
+}
+
+// ipv6Target returns the pool, mode and inside address of an IPv6-only
+// subscriber, or an empty pool when the session is not one. The inside
+// address is the IA_NA address (the B4 for DS-Lite); a NAT64 subscriber
+// with only a delegated prefix is keyed by the prefix. Nil until DHCPv6
+// binds.
+func (c *Component) ipv6Target(sess models.SubscriberSession) (string, string, net.IP) {
+	if sess == nil {
+		return "", "", nil
+	}
+	pool, mode := c.ipv6Pool(sess.GetServiceGroup())
+	if pool == "" {
+		return "", "", nil
+	}
+	if addr := sess.GetIPv6Address(); addr != nil {
+		return pool, mode, addr
+	}
+	if mode == cgnat.ModeNAT64 {
+		if _, pd, err := net.ParseCIDR(sess.GetIPv6Prefix()); err == nil {
+			return pool, mode, pd.IP
+		}
+	}
+	return pool, mode, nil
+}
+
+// handleIPv6Activate allocates (or re-uses) the port block of an
+// IPv6-only subscriber. clients are the subscriber's addresses, from
+// subscriberNets. A DS-Lite subscriber needs nothing programmed:
+// the AFTR decapsulates every softwire. A NAT64 subscriber's session
+// interface is made a NAT64 inside interface first.
+func (c *Component) handleIPv6Activate(poolName, mode string, inside net.IP, clients []*net.IPNet, swIfIndex uint32, sessionID, srgName string, done func()) {
+	defer done()
+
+	if mode == cgnat.ModeNAT64 && !c.enableNAT64Inside(sessionID, swIfIndex, clients) {
+		return
+	}
+
+	if mapping, ok := c.loadSyncedMapping(sessionID); ok && isIPv6Mapping(mapping) {
+		if err := c.pools.RestoreMappingIfAbsent(mapping); err == nil {
+			mapping.SessionID = sessionID
+			mapping.SwIfIndex = swIfIndex
+			c.commitMapping(sessionID, poolName, mapping, srgName, true)
+			if c.opdb != nil {
+				c.opdb.Delete(context.Background(), opdb.NamespaceHASyncedCGNAT, sessionID)
+			}
+			c.logger.Info("IPv6 subscriber mapping restored from HA sync",
+				"session", sessionID, "mode", mode, "inside_ip", mapping.InsideIP, "outside_ip", mapping.OutsideIP,
+				"ports", fmt.Sprintf("%d-%d", mapping.PortBlockStart, mapping.PortBlockEnd))
+			return
+		}
+	}
+
+	mapping, isNew, err := c.pools.GetOrAllocate(poolName, inside, 0, swIfIndex)
+	if err != nil {
+		c.logger.Error("IPv6 subscriber block allocation failed", "pool", poolName, "mode", mode, "inside_ip", inside, "error", err)
+		return
+	}
+	mapping.SessionID = sessionID
+	c.commitMapping(sessionID, poolName, mapping, srgName, isNew)
+
+	c.logger.Debug("IPv6 subscriber mapping created",
+		"session", sessionID,
+		"mode", mode,
+		"inside_ip", inside,
+		"outside", fmt.Sprintf("%s:%d-%d", mapping.OutsideIP, mapping.PortBlockStart, mapping.PortBlockEnd),
+		"pool", poolName)
+}
+
+// releaseIPv6 frees an IPv6-only subscriber's port blocks and, for
+// NAT64, its inside interface. Returns false when the session holds no
+// IPv6 mapping.
+func (c *Component) releaseIPv6(sessionID, srgName string) bool {
+	c.actMu.Lock()
+	inside, ok := c.sessionIPv6[sessionID]
+	if !ok {
+		c.actMu.Unlock()
+		return false
+	}
+	poolName := c.sessionPoolMap[sessionID]
+	delete(c.sessionIPv6, sessionID)
+	delete(c.sessionPoolMap, sessionID)
+	c.actMu.Unlock()
+
+	c.disableNAT64Inside(sessionID)
+
+	mappings := c.pools.GetMappings(poolName, inside, 0)
+	for i := range mappings {
+		mapping := &mappings[i]
+		mapping.SessionID = sessionID
+		c.reverse.Remove(mapping.OutsideIP, mapping.PortBlockStart)
+		c.publishMappingEvent(srgName, mapping, false)
+	}
+	c.pools.ReleaseBlocks(poolName, inside, 0)
+
+	if c.opdb != nil {
+		c.opdb.Delete(context.Background(), opdbNamespace, sessionID)
+	}
+
+	c.logger.Debug("IPv6 subscriber mappings released", "session", sessionID, "inside_ip", inside, "blocks", len(mappings))
+	return true
+}
+
+// trackIPv6Locked remembers the inside address of a committed IPv6
+// subscriber mapping so the release can find its blocks without the
+// session's address. Caller holds actMu.
+func (c *Component) trackIPv6Locked(sessionID string, mapping *models.CGNATMapping) {
+	if !isIPv6Mapping(mapping) {
+		return
+	}
+	if c.sessionIPv6 == nil {
+		c.sessionIPv6 = make(map[string]net.IP)
+	}
+	c.sessionIPv6[sessionID] = mapping.InsideIP
+}
+
+// loadSyncedMapping returns the mapping an HA peer synced for a session.
+func (c *Component) loadSyncedMapping(sessionID string) (*models.CGNATMapping, bool) {
+	if c.opdb == nil {
+		return nil, false
+	}
+
+	var found []byte
+	c.opdb.Load(context.Background(), opdb.NamespaceHASyncedCGNAT, func(key string, value []byte) error {
+		if key == sessionID {
+			found = make([]byte, len(value))
+			copy(found, value)
+		}
+		return nil
+	})
+	if found == nil {
+		return nil, false
+	}
+
+	mapping, err := ha.DecodeCGNATCheckpoint(found)
+	if err != nil {
+		return nil, false
+	}
+	return mapping, true
+}
+
+// dispatchIPv6Lifecycle is the lifecycle entry point for IPv6-only
+// sessions: they have no IPv4 address, so their block is allocated
+// once DHCPv6 hands out the IPv6 address or prefix.
+func (c *Component) dispatchIPv6Lifecycle(data *events.SessionLifecycleEvent) {
+	sess, ok := data.Session.(models.SubscriberSession)
+	if !ok {
+		return
+	}
+	if c.activateMAP(sess) {
+		return
+	}
+	pool, mode, inside := c.ipv6Target(sess)
+	if pool == "" || inside == nil {
+		return
+	}
+	proceed, done := c.beginActivation(data.SessionID)
+	if !proceed {
+		return
+	}
+	c.handleIPv6Activate(pool, mode, inside, subscriberNets(sess), sess.GetIfIndex(), data.SessionID, sess.GetSRGName(), done)
+}
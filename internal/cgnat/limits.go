@@ -0,0 +1,713 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+const (
+	// limitPollInterval matches the pool watermark checks.
+	limitPollInterval = 10 * time.Second
+	// defaultTopSubscribers is how many subscribers TopSubscribers
+	// returns when no limit is given.
+	defaultTopSubscribers = 20
+	// limitSessionPage is the page size of the session walk that checks
+	// a subscriber for free ports.
+	limitSessionPage = 1000
+)
+
+// limitSubscriber is a PBA subscriber as the limit poll sees it.
+type limitSubscriber struct {
+	pool        string
+	sessionID   string
+	insideIP    net.IP
+	insideVRF   uint32
+	blocks      int
+	maxBlocks   int
+	ports       uint32
+	maxSessions uint32
+	portBlocks  []blockAllocation
+}
+
+// subscriberLimitState is the poll's view of one subscriber's drops:
+// total is what the subscriber has been charged since it was first
+// seen and unreported what has not been in an event yet.
+type subscriberLimitState struct {
+	total         southbound.CGNATLimitDrops
+	unreported    southbound.CGNATLimitDrops
+	sessionsEvent time.Time
+	portsEvent    time.Time
+	// settling is set for the poll after an escalation, whose drops
+	// may predate it.
+	settling bool
+}
+
+// limitSubscribers returns the subscribers of the PBA pools keyed by
+// their session sw_if_index.
+func (pm *PoolManager) limitSubscribers() map[uint32]limitSubscriber {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	subs := make(map[uint32]limitSubscriber)
+	for _, ps := range pm.pools {
+		if ps.Config.GetMode() != "pba" {
+			continue
+		}
+		blockSize := uint32(ps.Config.GetBlockSize())
+		for key, sub := range ps.Subscribers {
+			if sub.SwIfIndex == 0 {
+				continue
+			}
+			subs[sub.SwIfIndex] = limitSubscriber{
+				pool:        ps.Name,
+				sessionID:   sub.SessionID,
+				insideIP:    subscriberIP(append(net.IP(nil), key.InsideIP[:]...)),
+				insideVRF:   key.InsideVRF,
+				blocks:      len(sub.Blocks),
+				maxBlocks:   int(ps.Config.GetMaxBlocksPerSubscriber()),
+				ports:       uint32(len(sub.Blocks)) * blockSize,
+				maxSessions: ps.Config.GetMaxSessionsPerSubscriber(),
+				portBlocks:  append([]blockAllocation(nil), sub.Blocks...),
+			}
+		}
+	}
+	return subs
+}
+
+// BindSession records the session a subscriber's blocks belong to.
+func (pm *PoolManager) BindSession(poolName string, insideIP net.IP, insideVRF uint32, sessionID string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if ps, ok := pm.pools[poolName]; ok {
+		if sub, ok := ps.Subscribers[makeSubscriberKey(insideVRF, insideIP)]; ok {
+			sub.SessionID = sessionID
+		}
+	}
+}
+
+// releaseBlock gives back one block of a subscriber, leaving the rest.
+func (pm *PoolManager) releaseBlock(mapping *models.CGNATMapping) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ps, ok := pm.pools[mapping.PoolName]
+	if !ok {
+		return
+	}
+	sub, ok := ps.Subscribers[makeSubscriberKey(mapping.InsideVRFID, mapping.InsideIP)]
+	if !ok {
+		return
+	}
+	for i, b := range sub.Blocks {
+		if b.PortBlockStart != mapping.PortBlockStart || !b.OutsideIP.Equal(mapping.OutsideIP) {
+			continue
+		}
+		sub.Blocks = append(sub.Blocks[:i], sub.Blocks[i+1:]...)
+		break
+	}
+	for _, addr := range ps.OutsideAddresses {
+		if addr.IP.Equal(mapping.OutsideIP) {
+			freeBlock(addr, int(mapping.PortBlockStart-ps.Config.GetPortRangeStart())/int(ps.Config.GetBlockSize()))
+			break
+		}
+	}
+}
+
+func (pm *PoolManager) addLimitDrops(poolName string, sessions, ports uint64) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if ps, ok := pm.pools[poolName]; ok {
+		ps.sessionLimitDrops += sessions
+		ps.portExhaustionDrops += ports
+	}
+}
+
+func (c *Component) watchSubscriberLimits() {
+	ticker := time.NewTicker(limitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.checkSubscriberLimits(now)
+		case <-c.Ctx.Done():
+			return
+		}
+	}
+}
+
+// limitHit is one subscriber's outcome of a poll: the events to publish
+// and the escalation to apply before publishing them.
+type limitHit struct {
+	swIfIndex  uint32
+	sub        limitSubscriber
+	escalation string
+	limits     *cgnat.SubscriberLimitsConfig
+	events     []*events.CGNATSubscriberLimitEvent
+}
+
+// configuresSubscriberLimits reports whether any pool sets
+// subscriber-limits.
+func configuresSubscriberLimits(cfg *cgnat.Config) bool {
+	for _, pool := range cfg.Pools {
+		if pool != nil && pool.SubscriberLimits != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSubscriberLimits reads the plugin's limit error counters and
+// acts on the subscribers that dropped packets at their limits since
+// the last poll. The plugin counts these drops per node, not per
+// subscriber, so when they rise the poll finds the subscribers at a
+// limit and shares the drops among them. The first reading is only a
+// baseline.
+func (c *Component) checkSubscriberLimits(now time.Time) {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil {
+		return
+	}
+	drops, err := c.dataplane.GetCGNATLimitDrops()
+	if errors.Is(err, southbound.ErrCGNATLimitCountersUnavailable) {
+		if configuresSubscriberLimits(cfg.CGNAT) && !c.limitCountersMissing.Swap(true) {
+			c.logger.Error("CGNAT subscriber-limits disabled: the dataplane exports no CGNAT limit error counters, so no limit events are raised and no subscriber is escalated",
+				"error", err)
+		}
+		return
+	}
+	if err != nil {
+		c.logger.Debug("Failed to read CGNAT limit counters", "error", err)
+		return
+	}
+	c.limitCountersMissing.Store(false)
+	subs := c.pools.limitSubscribers()
+
+	c.limitMu.Lock()
+	for swIfIndex := range c.limits {
+		if _, ok := subs[swIfIndex]; !ok {
+			delete(c.limits, swIfIndex)
+		}
+	}
+	last, seen := c.limitLast, c.limitSeen
+	c.limitLast, c.limitSeen = drops, true
+	c.limitMu.Unlock()
+	if !seen {
+		return
+	}
+	sessions := counterDelta(last.SessionLimitDrops, drops.SessionLimitDrops)
+	ports := counterDelta(last.PortExhaustionDrops, drops.PortExhaustionDrops)
+
+	var sessionShares, portShares map[uint32]uint64
+	if sessions > 0 || ports > 0 {
+		atSessions, atPorts := c.subscribersAtLimit(subs, sessions > 0, ports > 0)
+		sessionShares = shareDrops(sessions, atSessions)
+		portShares = shareDrops(ports, atPorts)
+		if (sessions > 0 && len(atSessions) == 0) || (ports > 0 && len(atPorts) == 0) {
+			c.logger.Debug("CGNAT limit drops with no subscriber at the limit",
+				"session_limit_drops", sessions, "port_exhaustion_drops", ports)
+		}
+	}
+
+	var hits []*limitHit
+	c.limitMu.Lock()
+	for swIfIndex, sub := range subs {
+		st, ok := c.limits[swIfIndex]
+		if !ok {
+			st = &subscriberLimitState{}
+			c.limits[swIfIndex] = st
+		}
+		sessions := sessionShares[swIfIndex]
+		ports := portShares[swIfIndex]
+		settling := st.settling
+		st.settling = false
+		if sessions == 0 && ports == 0 {
+			continue
+		}
+
+		st.total.SessionLimitDrops += sessions
+		st.total.PortExhaustionDrops += ports
+		st.unreported.SessionLimitDrops += sessions
+		st.unreported.PortExhaustionDrops += ports
+		c.pools.addLimitDrops(sub.pool, sessions, ports)
+
+		var limits *cgnat.SubscriberLimitsConfig
+		if pool := cfg.CGNAT.Pools[sub.pool]; pool != nil {
+			limits = pool.SubscriberLimits
+		}
+		hit := &limitHit{swIfIndex: swIfIndex, sub: sub, limits: limits}
+		if !settling {
+			switch limits.GetEscalate() {
+			case cgnat.EscalateAdditionalBlock:
+				if ports > 0 && sub.blocks < sub.maxBlocks {
+					hit.escalation = cgnat.EscalateAdditionalBlock
+				}
+			case cgnat.EscalateBypass:
+				hit.escalation = cgnat.EscalateBypass
+			}
+		}
+		if hit.escalation != "" {
+			st.settling = true
+		}
+
+		interval := limits.GetNotifyInterval()
+		if sessions > 0 && (hit.escalation != "" || now.Sub(st.sessionsEvent) >= interval) {
+			hit.events = append(hit.events, limitEvent(sub, events.CGNATLimitSessions, st.unreported.SessionLimitDrops))
+			st.unreported.SessionLimitDrops = 0
+			st.sessionsEvent = now
+		}
+		if ports > 0 && (hit.escalation != "" || now.Sub(st.portsEvent) >= interval) {
+			hit.events = append(hit.events, limitEvent(sub, events.CGNATLimitPorts, st.unreported.PortExhaustionDrops))
+			st.unreported.PortExhaustionDrops = 0
+			st.portsEvent = now
+		}
+		hits = append(hits, hit)
+	}
+	c.limitMu.Unlock()
+
+	for _, hit := range hits {
+		c.applyLimitHit(hit)
+	}
+}
+
+// subscribersAtLimit returns, by sw_if_index, the subscribers at their
+// max-sessions-per-subscriber by the sessions open on their blocks, and
+// when ports is set those with a translation on every port of their
+// blocks for some protocol. Each used port holds at least one session,
+// so only a subscriber with as many sessions as ports has its sessions
+// walked.
+func (c *Component) subscribersAtLimit(subs map[uint32]limitSubscriber, sessions, ports bool) (atSessions, atPorts []uint32) {
+	mappings, err := c.dataplane.CGNATDumpSubscriberMappings(^uint32(0))
+	if err != nil {
+		c.logger.Debug("Failed to dump CGNAT mappings for subscriber limits", "error", err)
+		return nil, nil
+	}
+	active := make(map[uint32]uint32)
+	for _, m := range mappings {
+		active[m.SwIfIndex] += m.ActiveSessions
+	}
+
+	for swIfIndex, sub := range subs {
+		n := active[swIfIndex]
+		if sessions && n >= sub.maxSessions {
+			atSessions = append(atSessions, swIfIndex)
+		}
+		if !ports || sub.ports == 0 || n < sub.ports {
+			continue
+		}
+		full, err := c.portsExhausted(sub)
+		if err != nil {
+			c.logger.Debug("Failed to dump CGNAT sessions for subscriber limits", "inside", sub.insideIP, "error", err)
+			continue
+		}
+		if full {
+			atPorts = append(atPorts, swIfIndex)
+		}
+	}
+	sort.Slice(atSessions, func(i, j int) bool { return atSessions[i] < atSessions[j] })
+	sort.Slice(atPorts, func(i, j int) bool { return atPorts[i] < atPorts[j] })
+	return atSessions, atPorts
+}
+
+// portsExhausted reports whether the subscriber's translations hold
+// every port of its blocks for some protocol.
+func (c *Component) portsExhausted(sub limitSubscriber) (bool, error) {
+	used := make(map[uint8]map[string]struct{})
+	filter := southbound.CGNATSessionFilter{
+		InsideIP: sub.insideIP,
+		PoolID:   c.poolIDMap[sub.pool],
+		Limit:    limitSessionPage,
+	}
+	for {
+		page, err := c.dataplane.CGNATDumpSessions(filter)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range page {
+			if !sub.holdsPort(s.OutsideIP, s.OutsidePort) {
+				continue
+			}
+			if used[s.Proto] == nil {
+				used[s.Proto] = make(map[string]struct{})
+			}
+			used[s.Proto][fmt.Sprintf("%s/%d", s.OutsideIP, s.OutsidePort)] = struct{}{}
+			if uint32(len(used[s.Proto])) >= sub.ports {
+				return true, nil
+			}
+		}
+		if len(page) < int(filter.Limit) {
+			return false, nil
+		}
+		filter.StartIndex = page[len(page)-1].SessionIndex + 1
+	}
+}
+
+// holdsPort reports whether an outside address and port lie in one of
+// the subscriber's blocks.
+func (sub limitSubscriber) holdsPort(ip net.IP, port uint16) bool {
+	for _, b := range sub.portBlocks {
+		if b.OutsideIP.Equal(ip) && port >= b.PortBlockStart && port <= b.PortBlockEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// shareDrops splits drops evenly among the subscribers, the first ones
+// taking the remainder.
+func shareDrops(drops uint64, subs []uint32) map[uint32]uint64 {
+	if drops == 0 || len(subs) == 0 {
+		return nil
+	}
+	shares := make(map[uint32]uint64, len(subs))
+	each, rest := drops/uint64(len(subs)), drops%uint64(len(subs))
+	for i, swIfIndex := range subs {
+		shares[swIfIndex] = each
+		if uint64(i) < rest {
+			shares[swIfIndex]++
+		}
+	}
+	return shares
+}
+
+func (c *Component) applyLimitHit(hit *limitHit) {
+	var err error
+	switch hit.escalation {
+	case cgnat.EscalateAdditionalBlock:
+		err = c.escalateBlock(hit.swIfIndex, hit.sub)
+	case cgnat.EscalateBypass:
+		err = c.escalateBypass(hit.swIfIndex, hit.sub)
+	}
+	if err != nil {
+		c.logger.Error("CGNAT subscriber escalation failed", "pool", hit.sub.pool, "inside", hit.sub.insideIP,
+			"escalation", hit.escalation, "error", err)
+		hit.escalation = ""
+	} else if hit.escalation != "" {
+		c.logger.Info("Escalated CGNAT subscriber", "pool", hit.sub.pool, "inside", hit.sub.insideIP,
+			"session", hit.sub.sessionID, "escalation", hit.escalation)
+	}
+
+	for _, ev := range hit.events {
+		ev.Escalation = hit.escalation
+		if hit.escalation == cgnat.EscalateBypass {
+			ev.ServiceGroup = hit.limits.BypassServiceGroup
+		}
+		if hit.escalation == cgnat.EscalateAdditionalBlock {
+			ev.Blocks++
+		}
+		c.logger.Warn("CGNAT subscriber hit limit", "pool", ev.Pool, "inside", ev.InsideIP, "session", ev.SessionID,
+			"limit", ev.Limit, "drops", ev.Drops, "blocks", ev.Blocks, "escalation", ev.Escalation)
+		c.eventBus.Publish(events.TopicCGNATSubscriberLimit, events.Event{
+			Source:    c.Name(),
+			Timestamp: time.Now(),
+			Data:      ev,
+		})
+	}
+}
+
+func limitEvent(sub limitSubscriber, limit string, drops uint64) *events.CGNATSubscriberLimitEvent {
+	return &events.CGNATSubscriberLimitEvent{
+		Pool:        sub.pool,
+		SessionID:   sub.sessionID,
+		InsideIP:    sub.insideIP,
+		InsideVRFID: sub.insideVRF,
+		Limit:       limit,
+		Drops:       drops,
+		Blocks:      sub.blocks,
+		MaxBlocks:   sub.maxBlocks,
+	}
+}
+
+// counterDelta is the increase of a cumulative counter, which starts
+// again from zero when the dataplane restarts.
+func counterDelta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// escalateBlock gives a subscriber that ran out of ports another block.
+// The block is persisted beside the session's first block and synced
+// to the HA peer, so it survives a restart or switchover for the rest
+// of the session.
+func (c *Component) escalateBlock(swIfIndex uint32, sub limitSubscriber) error {
+	mapping, err := c.pools.AllocateBlock(sub.pool, sub.insideIP, sub.insideVRF, swIfIndex)
+	if err != nil {
+		return err
+	}
+	mapping.SessionID = sub.sessionID
+	mapping.Extra = true
+
+	// The session interface already has the feature from its first block.
+	if err := c.dataplane.CGNATAddDelSubscriberMapping(c.poolIDMap[sub.pool], swIfIndex, sub.insideIP,
+		sub.insideVRF, mapping.OutsideIP, mapping.PortBlockStart, mapping.PortBlockEnd, false, true); err != nil {
+		c.pools.releaseBlock(mapping)
+		return fmt.Errorf("add mapping: %w", err)
+	}
+
+	c.reverse.Add(mapping)
+	c.persistExtraBlock(mapping)
+	c.publishMappingEvent(c.sessionSRGName(context.Background(), sub.sessionID), mapping, true)
+	return nil
+}
+
+// persistExtraBlock records an extra block in extraBlockNamespace,
+// keyed beside its session.
+func (c *Component) persistExtraBlock(mapping *models.CGNATMapping) {
+	if c.opdb == nil || mapping.SessionID == "" {
+		return
+	}
+	if data, err := json.Marshal(mapping); err == nil {
+		c.opdb.Put(context.Background(), extraBlockNamespace, mapping.ExtraBlockKey(), data)
+	}
+}
+
+func (c *Component) forgetExtraBlock(mapping *models.CGNATMapping) {
+	if c.opdb == nil || mapping.SessionID == "" || !mapping.Extra {
+		return
+	}
+	c.opdb.Delete(context.Background(), extraBlockNamespace, mapping.ExtraBlockKey())
+}
+
+// restoreExtraBlocks reprograms the persisted extra blocks of the
+// sessions whose first block is back, live mapping each to its session
+// interface, after that first block so the blocks keep their order.
+// Unless keep is nil, extra blocks of sessions in neither live nor keep
+// are dropped, as are those the dataplane refuses: the subscriber is
+// escalated again if it still needs them.
+func (c *Component) restoreExtraBlocks(ctx context.Context, live map[string]uint32, keep map[string]struct{}) {
+	if c.opdb == nil {
+		return
+	}
+	var extras []*models.CGNATMapping
+	var stale []string
+	c.opdb.Load(ctx, extraBlockNamespace, func(key string, value []byte) error {
+		var m models.CGNATMapping
+		if err := json.Unmarshal(value, &m); err != nil {
+			stale = append(stale, key)
+			return nil
+		}
+		if _, ok := live[m.SessionID]; ok {
+			extras = append(extras, &m)
+		} else if _, ok := keep[m.SessionID]; !ok && keep != nil {
+			stale = append(stale, key)
+		}
+		return nil
+	})
+
+	for _, m := range extras {
+		m.SwIfIndex = live[m.SessionID]
+		m.Extra = true
+		poolID, ok := c.poolIDMap[m.PoolName]
+		if !ok {
+			stale = append(stale, m.ExtraBlockKey())
+			continue
+		}
+		if err := c.dataplane.CGNATAddDelSubscriberMapping(poolID, m.SwIfIndex, m.InsideIP, m.InsideVRFID,
+			m.OutsideIP, m.PortBlockStart, m.PortBlockEnd, false, true); err != nil {
+			c.logger.Error("CGNAT restore: extra block reprogram failed; dropping it",
+				"session", m.SessionID, "outside", m.OutsideIP, "port_block_start", m.PortBlockStart, "error", err)
+			stale = append(stale, m.ExtraBlockKey())
+			continue
+		}
+		if err := c.pools.RestoreMappingIfAbsent(m); err != nil {
+			c.logger.Warn("CGNAT restore: local pool restore", "session", m.SessionID, "error", err)
+		}
+		c.reverse.Add(m)
+		c.persistExtraBlock(m)
+	}
+	for _, key := range stale {
+		c.opdb.Delete(ctx, extraBlockNamespace, key)
+	}
+}
+
+// restoreSyncedExtraBlocks takes over the extra blocks the HA peer
+// synced for a session, keyed "<session>/block/..." beside its first
+// block, once that first block is programmed. Blocks the dataplane or
+// the local pools refuse are dropped.
+func (c *Component) restoreSyncedExtraBlocks(sessionID string, poolID uint32, swIfIndex uint32, srgName string) {
+	if c.opdb == nil {
+		return
+	}
+	prefix := sessionID + "/block/"
+	synced := map[string]*models.CGNATMapping{}
+	c.opdb.Load(context.Background(), opdb.NamespaceHASyncedCGNAT, func(key string, value []byte) error {
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if m, err := ha.DecodeCGNATCheckpoint(value); err == nil && m.Extra {
+			synced[key] = m
+		}
+		return nil
+	})
+
+	for key, m := range synced {
+		c.opdb.Delete(context.Background(), opdb.NamespaceHASyncedCGNAT, key)
+		m.SessionID = sessionID
+		m.SwIfIndex = swIfIndex
+		if err := c.pools.RestoreMappingIfAbsent(m); err != nil {
+			c.logger.Warn("Failed to restore synced CGNAT extra block", "session", sessionID, "error", err)
+			continue
+		}
+		if err := c.dataplane.CGNATAddDelSubscriberMapping(poolID, swIfIndex, m.InsideIP, m.InsideVRFID,
+			m.OutsideIP, m.PortBlockStart, m.PortBlockEnd, false, true); err != nil {
+			c.logger.Error("restore synced extra block failed", "session", sessionID, "error", err)
+			c.pools.releaseBlock(m)
+			continue
+		}
+		c.reverse.Add(m)
+		c.persistExtraBlock(m)
+		c.publishMappingEvent(srgName, m, true)
+	}
+}
+
+// escalateBypass takes a subscriber out of CGNAT for the rest of its
+// session, as the bypass service group would have: the bypass goes in
+// before the blocks come out, so the subscriber's traffic is never left
+// untranslated. Unlike an extra block, this is not carried across a
+// restart or switchover.
+func (c *Component) escalateBypass(swIfIndex uint32, sub limitSubscriber) error {
+	prefix := net.IPNet{IP: sub.insideIP.To4(), Mask: net.CIDRMask(32, 32)}
+	if err := c.dataplane.CGNATAddDelBypass(prefix, 0, true); err != nil {
+		return fmt.Errorf("add bypass: %w", err)
+	}
+	c.bypass.AddIP(sub.insideIP, 0)
+
+	srgName := c.sessionSRGName(context.Background(), sub.sessionID)
+	c.deactivateForwards(sub.sessionID, srgName)
+
+	poolID := c.poolIDMap[sub.pool]
+	mappings := c.pools.GetMappings(sub.pool, sub.insideIP, sub.insideVRF)
+	for i := range mappings {
+		mapping := &mappings[i]
+		mapping.SessionID = sub.sessionID
+		if err := c.dataplane.CGNATAddDelSubscriberMapping(poolID, swIfIndex, sub.insideIP, sub.insideVRF,
+			mapping.OutsideIP, mapping.PortBlockStart, mapping.PortBlockEnd, false, false); err != nil {
+			c.logger.Error("remove mapping failed", "inside", sub.insideIP, "error", err)
+		}
+		c.reverse.Remove(mapping.OutsideIP, mapping.PortBlockStart)
+		c.forgetExtraBlock(mapping)
+		c.publishMappingEvent(srgName, mapping, false)
+	}
+	c.pools.ReleaseBlocks(sub.pool, sub.insideIP, sub.insideVRF)
+
+	c.actMu.Lock()
+	delete(c.sessionPoolMap, sub.sessionID)
+	c.escalated[sub.sessionID] = sub.insideIP
+	c.actMu.Unlock()
+
+	if c.opdb != nil && sub.sessionID != "" {
+		c.opdb.Delete(context.Background(), opdbNamespace, sub.sessionID)
+	}
+	return nil
+}
+
+// releaseEscalated removes the bypass of a subscriber escalated to it,
+// reporting whether the session was one.
+func (c *Component) releaseEscalated(sessionID string) bool {
+	c.actMu.Lock()
+	insideIP, ok := c.escalated[sessionID]
+	delete(c.escalated, sessionID)
+	c.actMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	prefix := net.IPNet{IP: insideIP.To4(), Mask: net.CIDRMask(32, 32)}
+	if err := c.dataplane.CGNATAddDelBypass(prefix, 0, false); err != nil {
+		c.logger.Error("remove bypass failed", "ip", insideIP, "error", err)
+	}
+	c.bypass.RemovePrefix(&prefix, 0)
+	return true
+}
+
+// TopSubscribers returns the PBA subscribers using the most ports, by
+// the sessions open on their blocks, optionally only those of one pool.
+// limit defaults to 20.
+func (c *Component) TopSubscribers(poolName string, limit int) ([]models.CGNATSubscriberUsage, error) {
+	out := []models.CGNATSubscriberUsage{}
+	if c == nil || c.dataplane == nil {
+		return out, nil
+	}
+
+	poolID := ^uint32(0)
+	if poolName != "" {
+		id, ok := c.poolIDMap[poolName]
+		if !ok {
+			return nil, fmt.Errorf("unknown pool %q", poolName)
+		}
+		poolID = id
+	}
+	mappings, err := c.dataplane.CGNATDumpSubscriberMappings(poolID)
+	if err != nil {
+		return nil, fmt.Errorf("dump cgnat mappings: %w", err)
+	}
+	active := make(map[uint32]uint32)
+	for _, m := range mappings {
+		active[m.SwIfIndex] += m.ActiveSessions
+	}
+
+	subs := c.pools.limitSubscribers()
+	c.limitMu.Lock()
+	for swIfIndex, sub := range subs {
+		if poolName != "" && sub.pool != poolName {
+			continue
+		}
+		u := models.CGNATSubscriberUsage{
+			PoolName:       sub.pool,
+			InsideIP:       sub.insideIP,
+			InsideVRFID:    sub.insideVRF,
+			SessionID:      sub.sessionID,
+			Blocks:         sub.blocks,
+			Ports:          sub.ports,
+			ActiveSessions: active[swIfIndex],
+		}
+		if u.Ports > 0 {
+			u.Utilization = float64(u.ActiveSessions) / float64(u.Ports)
+		}
+		if st, ok := c.limits[swIfIndex]; ok {
+			u.SessionLimitDrops = st.total.SessionLimitDrops
+			u.PortExhaustionDrops = st.total.PortExhaustionDrops
+		}
+		out = append(out, u)
+	}
+	c.limitMu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ActiveSessions != out[j].ActiveSessions {
+			return out[i].ActiveSessions > out[j].ActiveSessions
+		}
+		if out[i].PortExhaustionDrops != out[j].PortExhaustionDrops {
+			return out[i].PortExhaustionDrops > out[j].PortExhaustionDrops
+		}
+		return out[i].InsideIP.String() < out[j].InsideIP.String()
+	})
+
+	if limit <= 0 {
+		limit = defaultTopSubscribers
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
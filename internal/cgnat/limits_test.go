@@ -0,0 +1,417 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"google.golang.org/protobuf/proto"
+)
+
+// newLimitComponent returns a component with session s1 holding one
+// 64-port block for 10.0.0.5 on interface 17, in a pool allowing 100
+// sessions per subscriber, and the channels its limit and mapping
+// events arrive on.
+func newLimitComponent(t *testing.T, limits *cgnat.SubscriberLimitsConfig) (*Component, *fakeDP, chan *events.CGNATSubscriberLimitEvent, chan *events.CGNATMappingEvent) {
+	t.Helper()
+	cfg := pbaConfig()
+	cfg.CGNAT.Pools["p1"].SubscriberLimits = limits
+	cfg.CGNAT.Pools["p1"].MaxSessionsPerSubscriber = 100
+	dp := &fakeDP{}
+	c := newRestoreComponent(t, dp, newFakeOpDB(), &fakeProvider{}, cfg)
+	bus := local.NewBus()
+	c.eventBus = bus
+	limitCh := make(chan *events.CGNATSubscriberLimitEvent, 8)
+	bus.Subscribe(events.TopicCGNATSubscriberLimit, func(ev events.Event) {
+		limitCh <- ev.Data.(*events.CGNATSubscriberLimitEvent)
+	})
+	mappingCh := make(chan *events.CGNATMappingEvent, 8)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		mappingCh <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	block, err := c.pools.AllocateBlock("p1", net.ParseIP("10.0.0.5").To4(), 0, 17)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	block.SessionID = "s1"
+	c.commitMapping("s1", "p1", block, "", true)
+	nextMappingEvent(t, mappingCh)
+	return c, dp, limitCh, mappingCh
+}
+
+// fillBlocks opens a TCP translation on every port of the subscriber's
+// blocks, plus extra sessions, in the dataplane.
+func fillBlocks(c *Component, dp *fakeDP, insideIP net.IP, swIfIndex uint32, extra uint32) {
+	dp.sessions = nil
+	dp.mappings = nil
+	for _, m := range c.pools.GetMappings("p1", insideIP, 0) {
+		for port := uint32(m.PortBlockStart); port <= uint32(m.PortBlockEnd); port++ {
+			dp.sessions = append(dp.sessions, southbound.CGNATSession{
+				SessionIndex: uint32(len(dp.sessions)),
+				InsideIP:     insideIP,
+				OutsideIP:    m.OutsideIP,
+				OutsidePort:  uint16(port),
+				Proto:        6,
+			})
+		}
+		dp.mappings = append(dp.mappings, southbound.CGNATMapping{
+			SwIfIndex:      swIfIndex,
+			ActiveSessions: uint32(m.PortBlockEnd-m.PortBlockStart) + 1,
+		})
+	}
+	dp.mappings[0].ActiveSessions += extra
+}
+
+func nextLimitEvent(t *testing.T, ch <-chan *events.CGNATSubscriberLimitEvent) *events.CGNATSubscriberLimitEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no subscriber limit event")
+		return nil
+	}
+}
+
+func noLimitEvent(t *testing.T, ch <-chan *events.CGNATSubscriberLimitEvent) {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected subscriber limit event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscriberLimits_EventsAreRateLimited(t *testing.T) {
+	c, dp, limitCh, _ := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{NotifyInterval: time.Minute})
+	now := time.Now()
+	ip := net.ParseIP("10.0.0.5").To4()
+	fillBlocks(c, dp, ip, 17, 0)
+
+	// The first reading is a baseline, whatever the counters say.
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 5}
+	c.checkSubscriberLimits(now)
+	noLimitEvent(t, limitCh)
+
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 12}
+	c.checkSubscriberLimits(now.Add(10 * time.Second))
+	ev := nextLimitEvent(t, limitCh)
+	if ev.Limit != events.CGNATLimitPorts || ev.Drops != 7 || ev.SessionID != "s1" || ev.Blocks != 1 || ev.Escalation != "" {
+		t.Fatalf("event = %+v", ev)
+	}
+
+	fillBlocks(c, dp, ip, 17, 40)
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 20, SessionLimitDrops: 3}
+	c.checkSubscriberLimits(now.Add(20 * time.Second))
+	ev = nextLimitEvent(t, limitCh)
+	if ev.Limit != events.CGNATLimitSessions || ev.Drops != 3 {
+		t.Fatalf("event = %+v", ev)
+	}
+	noLimitEvent(t, limitCh)
+
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 21, SessionLimitDrops: 3}
+	c.checkSubscriberLimits(now.Add(2 * time.Minute))
+	ev = nextLimitEvent(t, limitCh)
+	if ev.Limit != events.CGNATLimitPorts || ev.Drops != 9 {
+		t.Fatalf("held-back drops = %+v, want 9", ev)
+	}
+
+	stats := c.pools.GetPoolStats("p1")
+	if stats.PortExhaustionDrops != 16 || stats.SessionLimitDrops != 3 {
+		t.Fatalf("pool drops = %d/%d, want 16/3", stats.PortExhaustionDrops, stats.SessionLimitDrops)
+	}
+
+	dp.mappings = []southbound.CGNATMapping{{SwIfIndex: 17, ActiveSessions: 40}}
+	top, err := c.TopSubscribers("", 0)
+	if err != nil {
+		t.Fatalf("TopSubscribers: %v", err)
+	}
+	if len(top) != 1 || top[0].SessionID != "s1" || top[0].ActiveSessions != 40 || top[0].Ports != 64 || top[0].PortExhaustionDrops != 16 {
+		t.Fatalf("top = %+v", top)
+	}
+}
+
+func TestSubscriberLimits_DropsChargedToSubscribersAtLimit(t *testing.T) {
+	c, dp, limitCh, mappingCh := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{})
+	now := time.Now()
+	ip := net.ParseIP("10.0.0.5").To4()
+	other := net.ParseIP("10.0.0.6").To4()
+	block, err := c.pools.AllocateBlock("p1", other, 0, 18)
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	block.SessionID = "s2"
+	c.commitMapping("s2", "p1", block, "", true)
+	nextMappingEvent(t, mappingCh)
+
+	// As many sessions as ports, but over two protocols: no protocol
+	// has run out.
+	fillBlocks(c, dp, ip, 17, 0)
+	for i := range dp.sessions[32:] {
+		dp.sessions[32+i].Proto = 17
+	}
+	dp.mappings = append(dp.mappings, southbound.CGNATMapping{SwIfIndex: 18, ActiveSessions: 10})
+	c.checkSubscriberLimits(now)
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 10}
+	c.checkSubscriberLimits(now.Add(10 * time.Second))
+	noLimitEvent(t, limitCh)
+
+	fillBlocks(c, dp, ip, 17, 0)
+	dp.mappings = append(dp.mappings, southbound.CGNATMapping{SwIfIndex: 18, ActiveSessions: 10})
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 15}
+	c.checkSubscriberLimits(now.Add(20 * time.Second))
+	ev := nextLimitEvent(t, limitCh)
+	if ev.SessionID != "s1" || ev.Limit != events.CGNATLimitPorts || ev.Drops != 5 {
+		t.Fatalf("event = %+v", ev)
+	}
+	noLimitEvent(t, limitCh)
+
+	// Both at the session limit: the drops are shared.
+	dp.mappings = []southbound.CGNATMapping{{SwIfIndex: 17, ActiveSessions: 100}, {SwIfIndex: 18, ActiveSessions: 100}}
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 15, SessionLimitDrops: 5}
+	c.checkSubscriberLimits(now.Add(30 * time.Second))
+	drops := map[string]uint64{}
+	for range 2 {
+		ev := nextLimitEvent(t, limitCh)
+		if ev.Limit != events.CGNATLimitSessions {
+			t.Fatalf("event = %+v", ev)
+		}
+		drops[ev.SessionID] = ev.Drops
+	}
+	if drops["s1"] != 3 || drops["s2"] != 2 {
+		t.Fatalf("shared drops = %v, want s1 3, s2 2", drops)
+	}
+	stats := c.pools.GetPoolStats("p1")
+	if stats.PortExhaustionDrops != 5 || stats.SessionLimitDrops != 5 {
+		t.Fatalf("pool drops = %d/%d, want 5/5", stats.PortExhaustionDrops, stats.SessionLimitDrops)
+	}
+}
+
+func TestSubscriberLimits_CounterReset(t *testing.T) {
+	if got := counterDelta(100, 30); got != 30 {
+		t.Fatalf("delta after reset = %d, want 30", got)
+	}
+	if got := counterDelta(30, 100); got != 70 {
+		t.Fatalf("delta = %d, want 70", got)
+	}
+}
+
+func TestSubscriberLimits_EscalateAdditionalBlock(t *testing.T) {
+	c, dp, limitCh, mappingCh := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{Escalate: cgnat.EscalateAdditionalBlock})
+	now := time.Now()
+	ip := net.ParseIP("10.0.0.5").To4()
+
+	fillBlocks(c, dp, ip, 17, 0)
+	c.checkSubscriberLimits(now)
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 4}
+	c.checkSubscriberLimits(now.Add(10 * time.Second))
+
+	ev := nextLimitEvent(t, limitCh)
+	if ev.Escalation != cgnat.EscalateAdditionalBlock || ev.Blocks != 2 || ev.MaxBlocks != 4 {
+		t.Fatalf("event = %+v", ev)
+	}
+	if add := nextMappingEvent(t, mappingCh); !add.IsAdd || add.Mapping.SessionID != "s1" {
+		t.Fatalf("mapping event = %+v", add.Mapping)
+	}
+	if got := len(c.pools.GetMappings("p1", ip, 0)); got != 2 {
+		t.Fatalf("blocks = %d, want 2", got)
+	}
+
+	// Drops seen on the poll after an escalation may predate it.
+	fillBlocks(c, dp, ip, 17, 0)
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 6}
+	c.checkSubscriberLimits(now.Add(20 * time.Second))
+	if got := len(c.pools.GetMappings("p1", ip, 0)); got != 2 {
+		t.Fatalf("blocks while settling = %d, want 2", got)
+	}
+
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 9}
+	c.checkSubscriberLimits(now.Add(30 * time.Second))
+	if got := len(c.pools.GetMappings("p1", ip, 0)); got != 3 {
+		t.Fatalf("blocks = %d, want 3", got)
+	}
+}
+
+func TestSubscriberLimits_EscalateBypass(t *testing.T) {
+	c, dp, limitCh, mappingCh := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{
+		Escalate:           cgnat.EscalateBypass,
+		BypassServiceGroup: "no-cgnat",
+	})
+	now := time.Now()
+	ip := net.ParseIP("10.0.0.5").To4()
+
+	fillBlocks(c, dp, ip, 17, 40)
+	c.checkSubscriberLimits(now)
+	dp.limitDrops = southbound.CGNATLimitDrops{SessionLimitDrops: 2}
+	c.checkSubscriberLimits(now.Add(10 * time.Second))
+
+	ev := nextLimitEvent(t, limitCh)
+	if ev.Escalation != cgnat.EscalateBypass || ev.ServiceGroup != "no-cgnat" || ev.Limit != events.CGNATLimitSessions {
+		t.Fatalf("event = %+v", ev)
+	}
+	if del := nextMappingEvent(t, mappingCh); del.IsAdd {
+		t.Fatalf("mapping event = %+v, want release", del.Mapping)
+	}
+	if !c.bypass.IsBypassed(ip, 0) {
+		t.Fatal("subscriber not bypassed")
+	}
+	if got := len(c.pools.GetMappings("p1", ip, 0)); got != 0 {
+		t.Fatalf("subscriber still holds %d blocks", got)
+	}
+	if _, ok := c.opdb.(*fakeOpDB).ns[opdbNamespace]["s1"]; ok {
+		t.Fatal("mapping still persisted")
+	}
+	if ok, _ := c.beginActivation("s1"); ok {
+		t.Fatal("escalated session activated again")
+	}
+
+	c.handleSessionRelease(&events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		SessionID:  "s1",
+		Session:    &models.IPoESession{SessionID: "s1", IPv4Address: ip, IfIndex: 17},
+	})
+	if c.bypass.IsBypassed(ip, 0) {
+		t.Fatal("bypass outlived the session")
+	}
+	if ok, done := c.beginActivation("s1"); !ok {
+		t.Fatal("released session still escalated")
+	} else {
+		done()
+	}
+}
+
+func TestSubscriberLimits_CountersUnavailable(t *testing.T) {
+	c, dp, limitCh, _ := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{Escalate: cgnat.EscalateAdditionalBlock})
+	dp.noLimitCounters = true
+
+	c.checkSubscriberLimits(time.Now())
+	if !c.limitCountersMissing.Load() {
+		t.Fatal("missing limit counters not reported")
+	}
+	noLimitEvent(t, limitCh)
+
+	dp.noLimitCounters = false
+	c.checkSubscriberLimits(time.Now())
+	if c.limitCountersMissing.Load() {
+		t.Fatal("counters still reported missing once the dataplane exports them")
+	}
+}
+
+func TestSubscriberLimits_ExtraBlockSurvivesRestart(t *testing.T) {
+	c, dp, _, mappingCh := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{Escalate: cgnat.EscalateAdditionalBlock})
+	now := time.Now()
+	ip := net.ParseIP("10.0.0.5").To4()
+
+	fillBlocks(c, dp, ip, 17, 0)
+	c.checkSubscriberLimits(now)
+	dp.limitDrops = southbound.CGNATLimitDrops{PortExhaustionDrops: 4}
+	c.checkSubscriberLimits(now.Add(10 * time.Second))
+	add := nextMappingEvent(t, mappingCh)
+	if !add.Mapping.Extra {
+		t.Fatalf("escalated block not marked extra: %+v", add.Mapping)
+	}
+	store := c.opdb.(*fakeOpDB)
+	key := add.Mapping.ExtraBlockKey()
+	if _, ok := store.ns[extraBlockNamespace][key]; !ok {
+		t.Fatalf("extra block %s not persisted", key)
+	}
+
+	sp := &fakeProvider{sessions: map[string]models.SubscriberSession{
+		"s1": &models.IPoESession{SessionID: "s1", AccessType: string(models.AccessTypeIPoE), IfIndex: 23, IPv4Address: ip},
+	}}
+	restarted := newRestoreComponent(t, &fakeDP{}, store, sp, pbaConfig())
+	if err := restarted.restoreFromOpDB(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	blocks := restarted.pools.GetMappings("p1", ip, 0)
+	if len(blocks) != 2 || !blocks[1].OutsideIP.Equal(add.Mapping.OutsideIP) || blocks[1].PortBlockStart != add.Mapping.PortBlockStart {
+		t.Fatalf("restored blocks = %+v", blocks)
+	}
+	if owner := restarted.reverse.Lookup(add.Mapping.OutsideIP, add.Mapping.PortBlockStart); owner == nil || !owner.InsideIP.Equal(ip) {
+		t.Fatalf("extra block owner = %+v", owner)
+	}
+
+	restarted.handleSessionRelease(&events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		SessionID:  "s1",
+		Session:    &models.IPoESession{SessionID: "s1", IPv4Address: ip, IfIndex: 23},
+	})
+	if _, ok := store.ns[extraBlockNamespace][key]; ok {
+		t.Fatal("extra block outlived the session")
+	}
+}
+
+func TestSubscriberLimits_RestoreDropsOrphanExtraBlock(t *testing.T) {
+	store := newFakeOpDB()
+	orphan := &models.CGNATMapping{
+		SessionID:      "gone",
+		PoolName:       "p1",
+		InsideIP:       net.ParseIP("10.0.0.9").To4(),
+		OutsideIP:      net.ParseIP("100.64.0.1").To4(),
+		PortBlockStart: 1088,
+		PortBlockEnd:   1151,
+		Extra:          true,
+	}
+	c := newRestoreComponent(t, &fakeDP{}, store, &fakeProvider{}, pbaConfig())
+	c.persistExtraBlock(orphan)
+
+	if err := c.restoreFromOpDB(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if len(store.ns[extraBlockNamespace]) != 0 {
+		t.Fatalf("orphan extra block kept: %v", store.ns[extraBlockNamespace])
+	}
+	if got := len(c.pools.GetMappings("p1", orphan.InsideIP, 0)); got != 0 {
+		t.Fatalf("orphan extra block restored into the pool: %d blocks", got)
+	}
+}
+
+func TestSubscriberLimits_TakesOverSyncedExtraBlock(t *testing.T) {
+	c, _, _, mappingCh := newLimitComponent(t, &cgnat.SubscriberLimitsConfig{Escalate: cgnat.EscalateAdditionalBlock})
+	ip := net.ParseIP("10.0.0.5").To4()
+	first := c.pools.GetMappings("p1", ip, 0)[0]
+
+	cp := &hapb.CGNATMappingCheckpoint{
+		SessionId:      "s1",
+		PoolName:       "p1",
+		InsideIp:       ip,
+		OutsideIp:      first.OutsideIP.To4(),
+		PortBlockStart: uint32(first.PortBlockEnd) + 1,
+		PortBlockEnd:   uint32(first.PortBlockEnd) + 64,
+		ExtraBlock:     true,
+	}
+	data, err := proto.Marshal(cp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	store := c.opdb.(*fakeOpDB)
+	synced := "s1/block/" + first.OutsideIP.String() + "/" + "1088"
+	store.Put(context.Background(), opdb.NamespaceHASyncedCGNAT, synced, data)
+
+	c.restoreSyncedExtraBlocks("s1", 1, 17, "")
+	if add := nextMappingEvent(t, mappingCh); !add.IsAdd || !add.Mapping.Extra || add.Mapping.PortBlockStart != 1088 {
+		t.Fatalf("mapping event = %+v", add.Mapping)
+	}
+	if got := len(c.pools.GetMappings("p1", ip, 0)); got != 2 {
+		t.Fatalf("blocks = %d, want 2", got)
+	}
+	if _, ok := store.ns[opdb.NamespaceHASyncedCGNAT][synced]; ok {
+		t.Fatal("synced extra block not consumed")
+	}
+	if len(store.ns[extraBlockNamespace]) != 1 {
+		t.Fatalf("taken-over extra block not persisted: %v", store.ns[extraBlockNamespace])
+	}
+}
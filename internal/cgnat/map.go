@@ -0,0 +1,351 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// MAP-E/MAP-T (RFC 7597/7599) domains are stateless: a CE derives its
+// IPv4 address and port set from its delegated prefix, so the border
+// relay only needs the domains and the feature on its interfaces. Each
+// subscriber's session interface is enabled when DHCPv6 binds.
+
+// mapTagPrefix marks the dataplane MAP domains osvbng owns; the rest of
+// the tag is the domain name.
+const mapTagPrefix = "osvbng:"
+
+func mapDomainTag(name string) string { return mapTagPrefix + name }
+
+// desiredMAPDomains renders the configured domains as the dataplane
+// holds them, keyed by tag.
+func desiredMAPDomains(m *cgnat.MAPConfig) (map[string]southbound.MAPDomain, error) {
+	out := make(map[string]southbound.MAPDomain)
+	if m == nil {
+		return out, nil
+	}
+	var src *net.IPNet
+	switch m.Mode {
+	case cgnat.MAPModeE:
+		br := net.ParseIP(m.BRAddress)
+		if br == nil {
+			return nil, fmt.Errorf("invalid br-address %q", m.BRAddress)
+		}
+		src = &net.IPNet{IP: br.To16(), Mask: net.CIDRMask(128, 128)}
+	case cgnat.MAPModeT:
+		var err error
+		if _, src, err = net.ParseCIDR(m.DMR); err != nil {
+			return nil, fmt.Errorf("invalid dmr %q: %w", m.DMR, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown mode %q", m.Mode)
+	}
+	for name, d := range m.Domains {
+		if d == nil {
+			continue
+		}
+		_, v6, err := net.ParseCIDR(d.IPv6Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("domain %q: %w", name, err)
+		}
+		_, v4, err := net.ParseCIDR(d.IPv4Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("domain %q: %w", name, err)
+		}
+		tag := mapDomainTag(name)
+		out[tag] = southbound.MAPDomain{
+			IPv6Prefix: *v6,
+			IPv4Prefix: *v4,
+			Src:        *src,
+			EABitsLen:  d.EABitsLength,
+			PSIDOffset: d.GetPSIDOffset(),
+			PSIDLength: d.GetPSIDLength(),
+			MTU:        m.GetMTU(),
+			Tag:        tag,
+		}
+	}
+	return out, nil
+}
+
+func sameMAPDomain(a, b southbound.MAPDomain) bool {
+	return a.IPv6Prefix.String() == b.IPv6Prefix.String() &&
+		a.IPv4Prefix.String() == b.IPv4Prefix.String() &&
+		a.Src.String() == b.Src.String() &&
+		a.EABitsLen == b.EABitsLen &&
+		a.PSIDOffset == b.PSIDOffset &&
+		a.PSIDLength == b.PSIDLength &&
+		a.MTU == b.MTU
+}
+
+// reconcileMAP converges the dataplane's osvbng-owned MAP domains onto
+// the configured ones and enables the border relay on the outside
+// interfaces. Domains whose parameters changed are replaced.
+func (c *Component) reconcileMAP(cfg *config.Config) error {
+	if c.mapBR == nil {
+		return nil
+	}
+	var m *cgnat.MAPConfig
+	if cfg.CGNAT != nil {
+		m = cfg.CGNAT.MAP
+	}
+	desired, err := desiredMAPDomains(m)
+	if err != nil {
+		return fmt.Errorf("cgnat: map: %w", err)
+	}
+	current, err := c.mapBR.MAPDomainDump()
+	if err != nil {
+		return fmt.Errorf("cgnat: map: dump domains: %w", err)
+	}
+
+	have := make(map[string]bool, len(current))
+	var added, removed int
+	for _, d := range current {
+		if !strings.HasPrefix(d.Tag, mapTagPrefix) {
+			continue
+		}
+		if want, ok := desired[d.Tag]; ok && sameMAPDomain(want, d) && !have[d.Tag] {
+			have[d.Tag] = true
+			continue
+		}
+		if err := c.mapBR.MAPDelDomain(d.Index); err != nil {
+			return fmt.Errorf("cgnat: map: remove domain %q: %w", strings.TrimPrefix(d.Tag, mapTagPrefix), err)
+		}
+		removed++
+	}
+
+	tags := make([]string, 0, len(desired))
+	for tag := range desired {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		if have[tag] {
+			continue
+		}
+		if _, err := c.mapBR.MAPAddDomain(desired[tag]); err != nil {
+			return fmt.Errorf("cgnat: map: add domain %q: %w", strings.TrimPrefix(tag, mapTagPrefix), err)
+		}
+		added++
+	}
+
+	if m == nil {
+		return nil
+	}
+	translation := m.Mode == cgnat.MAPModeT
+	for _, ifName := range m.OutsideInterfaces {
+		swIfIndex, ok := c.ifMgr.GetSwIfIndex(ifName)
+		if !ok {
+			return fmt.Errorf("cgnat: map: outside interface %q not found in dataplane", ifName)
+		}
+		if c.mapOutside[swIfIndex] {
+			continue
+		}
+		if err := c.mapBR.MAPInterfaceEnableDisable(swIfIndex, translation, true); err != nil {
+			return fmt.Errorf("cgnat: map: outside interface %q: %w", ifName, err)
+		}
+		c.mapOutside[swIfIndex] = true
+	}
+
+	c.logger.Info("MAP border relay configured",
+		"mode", m.Mode,
+		"domains", len(desired),
+		"added", added,
+		"removed", removed)
+	return nil
+}
+
+// mapDomain returns the MAP domain a service group places its
+// subscribers in and whether the relay translates (MAP-T), or "".
+func (c *Component) mapDomain(serviceGroup string) (string, bool) {
+	if serviceGroup == "" {
+		return "", false
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil || cfg.CGNAT.MAP == nil {
+		return "", false
+	}
+	sg, ok := cfg.ServiceGroups[serviceGroup]
+	if !ok || sg.CGNAT == nil || sg.CGNAT.Bypass || sg.CGNAT.MAPDomain == "" {
+		return "", false
+	}
+	if cfg.CGNAT.MAP.Domains[sg.CGNAT.MAPDomain] == nil {
+		return "", false
+	}
+	return sg.CGNAT.MAPDomain, cfg.CGNAT.MAP.Mode == cgnat.MAPModeT
+}
+
+// enableMAPSession runs the border relay on a MAP subscriber's session
+// interface. Re-enabling an interface already tracked is a no-op.
+func (c *Component) enableMAPSession(sessionID string, swIfIndex uint32, translation bool) {
+	if c.mapBR == nil || swIfIndex == 0 {
+		return
+	}
+	c.actMu.Lock()
+	if cur, ok := c.sessionMAPIf[sessionID]; ok && cur == swIfIndex {
+		c.actMu.Unlock()
+		return
+	}
+	c.actMu.Unlock()
+
+	if err := c.mapBR.MAPInterfaceEnableDisable(swIfIndex, translation, true); err != nil {
+		c.logger.Error("Failed to enable MAP on session", "session", sessionID, "sw_if_index", swIfIndex, "error", err)
+		return
+	}
+	c.actMu.Lock()
+	c.sessionMAPIf[sessionID] = swIfIndex
+	c.actMu.Unlock()
+	c.logger.Debug("MAP enabled on session", "session", sessionID, "sw_if_index", swIfIndex)
+}
+
+func (c *Component) disableMAPSession(sessionID string) {
+	c.actMu.Lock()
+	swIfIndex, ok := c.sessionMAPIf[sessionID]
+	delete(c.sessionMAPIf, sessionID)
+	c.actMu.Unlock()
+	if !ok || c.mapBR == nil {
+		return
+	}
+	translation := c.mapTranslation()
+	if err := c.mapBR.MAPInterfaceEnableDisable(swIfIndex, translation, false); err != nil {
+		c.logger.Debug("Failed to disable MAP on session", "session", sessionID, "sw_if_index", swIfIndex, "error", err)
+	}
+}
+
+// mapTranslation reports whether the running MAP relay is MAP-T.
+func (c *Component) mapTranslation() bool {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil || cfg.CGNAT.MAP == nil {
+		return false
+	}
+	return cfg.CGNAT.MAP.Mode == cgnat.MAPModeT
+}
+
+// activateMAP enables the relay for a session whose service group
+// selects a MAP domain. Returns false when it does not.
+func (c *Component) activateMAP(sess models.SubscriberSession) bool {
+	domain, translation := c.mapDomain(sess.GetServiceGroup())
+	if domain == "" {
+		return false
+	}
+	c.enableMAPSession(sess.GetSessionID(), sess.GetIfIndex(), translation)
+	return true
+}
+
+// MAPLookup finds the CE that owns an outside IPv4 address and port: the
+// mapping rule of the domain holding the address gives the PSID and the
+// CE's end-user prefix, which is matched against the subscribers'
+// delegated prefixes.
+func (c *Component) MAPLookup(ctx context.Context, ip net.IP, port uint16) (*models.MAPOwner, error) {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil || cfg.CGNAT.MAP == nil {
+		return nil, fmt.Errorf("MAP not configured")
+	}
+
+	names := make([]string, 0, len(cfg.CGNAT.MAP.Domains))
+	for name := range cfg.CGNAT.MAP.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		params := cfg.CGNAT.MAPParams(name)
+		if params == nil {
+			continue
+		}
+		rule := params.Rules[0]
+		if !rule.IPv4Prefix.Contains(ip) {
+			continue
+		}
+		psid, prefix, ok := mapRuleOwner(rule, ip, port)
+		if !ok {
+			return nil, fmt.Errorf("port %d is outside every port set of MAP domain %q (PSID offset %d)", port, name, rule.PSIDOffset)
+		}
+		owner := &models.MAPOwner{
+			Domain:        name,
+			IPv4Address:   ip.To4(),
+			Port:          port,
+			PSID:          psid,
+			EndUserPrefix: prefix.String(),
+		}
+		if sess := c.findMAPSession(ctx, prefix); sess != nil {
+			owner.SessionID = sess.GetSessionID()
+			owner.Username = sess.GetUsername()
+			owner.MAC = sess.GetMAC().String()
+			owner.IPv6Prefix = sess.GetIPv6Prefix()
+		}
+		return owner, nil
+	}
+	return nil, fmt.Errorf("%s is not in any MAP domain", ip)
+}
+
+// findMAPSession returns the subscriber whose delegated prefix covers a
+// CE's end-user prefix.
+func (c *Component) findMAPSession(ctx context.Context, prefix *net.IPNet) models.SubscriberSession {
+	if c.sessionProvider == nil {
+		return nil
+	}
+	sessions, err := c.sessionProvider.GetSessions(ctx, "", "", 0)
+	if err != nil {
+		return nil
+	}
+	want, _ := prefix.Mask.Size()
+	for _, sess := range sessions {
+		_, pd, err := net.ParseCIDR(sess.GetIPv6Prefix())
+		if err != nil {
+			continue
+		}
+		if ones, _ := pd.Mask.Size(); ones <= want && pd.Contains(prefix.IP) {
+			return sess
+		}
+	}
+	return nil
+}
+
+// mapRuleOwner applies a mapping rule in reverse (RFC 7597 5.1, 6): the
+// port's PSID and the address's suffix form the EA bits, which follow
+// the rule's IPv6 prefix to give the CE's end-user prefix. ok is false
+// for ports in the excluded range below 2^(16-offset).
+func mapRuleOwner(rule cgnat.MAPRule, ip net.IP, port uint16) (uint16, *net.IPNet, bool) {
+	v4 := ip.To4()
+	if v4 == nil || !rule.IPv4Prefix.Contains(v4) {
+		return 0, nil, false
+	}
+	v4Len, _ := rule.IPv4Prefix.Mask.Size()
+	v6Len, _ := rule.IPv6Prefix.Mask.Size()
+	suffixBits := uint(32 - v4Len)
+	suffix := uint64(ipToU32(v4)) & (uint64(1)<<suffixBits - 1)
+
+	k := uint(rule.PSIDLength)
+	a := uint(rule.PSIDOffset)
+	var psid uint16
+	if k > 0 {
+		if a > 0 && uint32(port)>>(16-a) == 0 {
+			return 0, nil, false
+		}
+		m := 16 - a - k
+		psid = uint16(uint32(port)>>m) & uint16(1<<k-1)
+	}
+
+	eaLen := int(rule.EABitsLength)
+	ea := suffix<<k | uint64(psid)
+	out := make(net.IP, net.IPv6len)
+	copy(out, rule.IPv6Prefix.IP.To16())
+	for i := 0; i < eaLen; i++ {
+		if ea>>(eaLen-1-i)&1 == 0 {
+			continue
+		}
+		bit := v6Len + i
+		out[bit/8] |= 0x80 >> (bit % 8)
+	}
+	return psid, &net.IPNet{IP: out, Mask: net.CIDRMask(v6Len+eaLen, 128)}, true
+}
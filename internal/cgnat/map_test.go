@@ -0,0 +1,181 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type mapIfCall struct {
+	swIfIndex           uint32
+	translation, enable bool
+}
+
+type fakeMAP struct {
+	domains []southbound.MAPDomain
+	added   []southbound.MAPDomain
+	deleted []uint32
+	ifOps   []mapIfCall
+}
+
+func (f *fakeMAP) MAPAddDomain(d southbound.MAPDomain) (uint32, error) {
+	f.added = append(f.added, d)
+	return uint32(len(f.added)), nil
+}
+
+func (f *fakeMAP) MAPDelDomain(index uint32) error {
+	f.deleted = append(f.deleted, index)
+	return nil
+}
+
+func (f *fakeMAP) MAPDomainDump() ([]southbound.MAPDomain, error) { return f.domains, nil }
+
+func (f *fakeMAP) MAPInterfaceEnableDisable(swIfIndex uint32, translation, enable bool) error {
+	f.ifOps = append(f.ifOps, mapIfCall{swIfIndex, translation, enable})
+	return nil
+}
+
+// mapConfig is the RFC 7597 Appendix A example 1 domain: a /40 rule
+// prefix with 16 EA bits over 192.0.2.0/24, giving an 8-bit PSID.
+func mapConfig() *config.Config {
+	return &config.Config{
+		CGNAT: &cgnat.Config{
+			MAP: &cgnat.MAPConfig{
+				Mode: cgnat.MAPModeT,
+				DMR:  "2001:db8:ffff::/64",
+				MTU:  1500,
+				Domains: map[string]*cgnat.MAPDomain{
+					"east": {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16},
+				},
+			},
+		},
+		ServiceGroups: map[string]*servicegroup.Config{
+			"mapt": {CGNAT: &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeMAP, MAPDomain: "east"}},
+		},
+	}
+}
+
+func TestReconcileMAP_ReplacesChangedDomains(t *testing.T) {
+	cfg := mapConfig()
+	desired, err := desiredMAPDomains(cfg.CGNAT.MAP)
+	if err != nil {
+		t.Fatalf("desiredMAPDomains: %v", err)
+	}
+	stale := desired["osvbng:east"]
+	stale.Index = 3
+	stale.MTU = 1280
+	foreign := southbound.MAPDomain{Index: 4, Tag: "manual"}
+	dp := &fakeMAP{domains: []southbound.MAPDomain{stale, foreign, {Index: 5, Tag: "osvbng:west"}}}
+
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, cfg)
+	c.mapBR = dp
+	if err := c.reconcileMAP(cfg); err != nil {
+		t.Fatalf("reconcileMAP: %v", err)
+	}
+
+	if len(dp.deleted) != 2 || dp.deleted[0] != 3 || dp.deleted[1] != 5 {
+		t.Fatalf("deleted = %v, want [3 5]", dp.deleted)
+	}
+	if len(dp.added) != 1 {
+		t.Fatalf("added %d domains, want 1", len(dp.added))
+	}
+	got := dp.added[0]
+	if got.Tag != "osvbng:east" || got.MTU != 1500 || got.PSIDOffset != 6 || got.PSIDLength != 8 || got.EABitsLen != 16 {
+		t.Fatalf("added domain = %+v", got)
+	}
+	if got.Src.String() != "2001:db8:ffff::/64" {
+		t.Fatalf("src = %s, want the DMR", got.Src.String())
+	}
+
+	dp.domains = []southbound.MAPDomain{got}
+	dp.added, dp.deleted = nil, nil
+	if err := c.reconcileMAP(cfg); err != nil {
+		t.Fatalf("second reconcileMAP: %v", err)
+	}
+	if len(dp.added) != 0 || len(dp.deleted) != 0 {
+		t.Fatalf("converged reconcile changed domains: added=%v deleted=%v", dp.added, dp.deleted)
+	}
+}
+
+func TestMAPLifecycle_EnablesSessionInterface(t *testing.T) {
+	cfg := mapConfig()
+	dp := &fakeMAP{}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, cfg)
+	c.mapBR = dp
+
+	sess := &models.IPoESession{
+		SessionID:    "s1",
+		AccessType:   string(models.AccessTypeIPoE),
+		Protocol:     string(models.ProtocolDHCPv6),
+		ServiceGroup: "mapt",
+		IPv6Prefix:   "2001:db8:12:3400::/56",
+		IfIndex:      17,
+	}
+	for _, state := range []models.SessionState{models.SessionStateActive, models.SessionStateActive, models.SessionStateReleased} {
+		c.dispatchLifecycle(events.Event{Data: &events.SessionLifecycleEvent{
+			AccessType: models.AccessTypeIPoE,
+			Protocol:   models.ProtocolDHCPv6,
+			SessionID:  "s1",
+			State:      state,
+			Session:    sess,
+		}})
+	}
+
+	want := []mapIfCall{{17, true, true}, {17, true, false}}
+	if len(dp.ifOps) != len(want) || dp.ifOps[0] != want[0] || dp.ifOps[1] != want[1] {
+		t.Fatalf("interface calls = %+v, want %+v", dp.ifOps, want)
+	}
+	if len(c.sessionMAPIf) != 0 {
+		t.Fatalf("session still tracked after release: %v", c.sessionMAPIf)
+	}
+}
+
+func TestMAPLookup_FindsCE(t *testing.T) {
+	cfg := mapConfig()
+	sess := &models.IPoESession{
+		SessionID:  "s1",
+		Username:   "ce-1",
+		IPv6Prefix: "2001:db8:12:3400::/56",
+	}
+	sp := &fakeProvider{sessions: map[string]models.SubscriberSession{"s1": sess}}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), sp, cfg)
+
+	// RFC 7597 Appendix A: EA bits 0x1234 are suffix 0x12 and PSID 0x34;
+	// the first port of that PSID past the excluded range is 1232.
+	owner, err := c.MAPLookup(context.Background(), net.ParseIP("192.0.2.18"), 1232)
+	if err != nil {
+		t.Fatalf("MAPLookup: %v", err)
+	}
+	if owner.Domain != "east" || owner.PSID != 0x34 || owner.EndUserPrefix != "2001:db8:12:3400::/56" {
+		t.Fatalf("owner = %+v", owner)
+	}
+	if owner.SessionID != "s1" || owner.Username != "ce-1" {
+		t.Fatalf("owner session = %q/%q, want s1/ce-1", owner.SessionID, owner.Username)
+	}
+
+	if _, err := c.MAPLookup(context.Background(), net.ParseIP("192.0.2.18"), 1000); err == nil {
+		t.Fatal("port in the excluded range resolved to a CE")
+	}
+	if _, err := c.MAPLookup(context.Background(), net.ParseIP("198.51.100.1"), 1232); err == nil {
+		t.Fatal("address outside every domain resolved to a CE")
+	}
+
+	owner, err = c.MAPLookup(context.Background(), net.ParseIP("192.0.2.19"), 65535)
+	if err != nil {
+		t.Fatalf("MAPLookup without session: %v", err)
+	}
+	if owner.PSID != 0xff || owner.EndUserPrefix != "2001:db8:13:ff00::/56" || owner.SessionID != "" {
+		t.Fatalf("unowned lookup = %+v", owner)
+	}
+}
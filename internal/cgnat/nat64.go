@@ -0,0 +1,368 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// NAT64 (RFC 6146) pools translate on the dataplane's NAT64 node. The
+// translation prefix and address pool are global; each subscriber's
+// session interface is made an inside interface when its block is
+// allocated. Subscribers are handled by the IPv6 subscriber path in
+// ipv6.go, keyed by the IA_NA address (or delegated prefix). The node
+// chooses translated ports itself, so the bindings it makes are read
+// back from its BIB and published too; see checkNAT64BIB.
+
+// nat64AnyVRF is the tenant VRF of the NAT64 address pool: usable by
+// subscribers in any VRF.
+const nat64AnyVRF = ^uint32(0)
+
+func nat64PoolConfig(cfg *cgnat.Config) (string, *cgnat.Pool) {
+	if cfg == nil {
+		return "", nil
+	}
+	for name, p := range cfg.Pools {
+		if p != nil && p.GetMode() == cgnat.ModeNAT64 {
+			return name, p
+		}
+	}
+	return "", nil
+}
+
+// isNAT64Pool reports whether a configured pool is the NAT64 pool.
+func (c *Component) isNAT64Pool(name string) bool {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.CGNAT == nil {
+		return false
+	}
+	p := cfg.CGNAT.Pools[name]
+	return p != nil && p.GetMode() == cgnat.ModeNAT64
+}
+
+// reconcileNAT64 registers the NAT64 pool with the PBA allocator and
+// converges the dataplane prefix, address pool and outside interfaces
+// onto it.
+func (c *Component) reconcileNAT64(cfg *config.Config) error {
+	name, pool := nat64PoolConfig(cfg.CGNAT)
+	if pool == nil {
+		return nil
+	}
+	id := poolID(name)
+	c.poolIDMap[name] = id
+	if err := c.pools.ConfigurePool(name, id, pool); err != nil {
+		return fmt.Errorf("cgnat: pool %q: %w", name, err)
+	}
+	return c.programNAT64(name, pool)
+}
+
+func (c *Component) programNAT64(name string, pool *cgnat.Pool) error {
+	if c.nat64 == nil {
+		return nil
+	}
+	if err := c.nat64.NAT64Enable(); err != nil {
+		return fmt.Errorf("cgnat: pool %q: enable NAT64: %w", name, err)
+	}
+
+	_, want, err := net.ParseCIDR(pool.NAT64.GetPrefix())
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: nat64 prefix: %w", name, err)
+	}
+	prefixes, err := c.nat64.NAT64PrefixDump()
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: dump NAT64 prefixes: %w", name, err)
+	}
+	havePrefix := false
+	for _, p := range prefixes {
+		if p.VRFID == 0 && p.Prefix.String() == want.String() {
+			havePrefix = true
+			continue
+		}
+		if err := c.nat64.NAT64AddDelPrefix(p.Prefix, p.VRFID, false); err != nil {
+			return fmt.Errorf("cgnat: pool %q: remove NAT64 prefix %s: %w", name, p.Prefix.String(), err)
+		}
+	}
+	if !havePrefix {
+		if err := c.nat64.NAT64AddDelPrefix(*want, 0, true); err != nil {
+			return fmt.Errorf("cgnat: pool %q: add NAT64 prefix %s: %w", name, want, err)
+		}
+	}
+
+	desired, err := poolAddresses(pool)
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: %w", name, err)
+	}
+	current, err := c.nat64.NAT64PoolAddrDump()
+	if err != nil {
+		return fmt.Errorf("cgnat: pool %q: dump NAT64 addresses: %w", name, err)
+	}
+	stale, missing := diffAddresses(desired, current)
+	for _, r := range addressRanges(stale) {
+		if err := c.nat64.NAT64AddDelPoolAddrRange(r[0], r[1], nat64AnyVRF, false); err != nil {
+			return fmt.Errorf("cgnat: pool %q: remove NAT64 range %s-%s: %w", name, r[0], r[1], err)
+		}
+	}
+	for _, r := range addressRanges(missing) {
+		if err := c.nat64.NAT64AddDelPoolAddrRange(r[0], r[1], nat64AnyVRF, true); err != nil {
+			return fmt.Errorf("cgnat: pool %q: add NAT64 range %s-%s: %w", name, r[0], r[1], err)
+		}
+	}
+
+	for _, ifName := range pool.OutsideInterfaces {
+		swIfIndex, ok := c.ifMgr.GetSwIfIndex(ifName)
+		if !ok {
+			return fmt.Errorf("cgnat: pool %q: outside interface %q not found in dataplane", name, ifName)
+		}
+		if c.nat64Outside[swIfIndex] {
+			continue
+		}
+		if err := c.nat64.NAT64AddDelInterface(swIfIndex, false, true); err != nil {
+			return fmt.Errorf("cgnat: pool %q: NAT64 outside interface %q: %w", name, ifName, err)
+		}
+		c.nat64Outside[swIfIndex] = true
+	}
+
+	t := pool.GetTimeouts()
+	if err := c.nat64.NAT64SetTimeouts(t.UDP, t.TCPEstablished, t.TCPTransitory, t.ICMP); err != nil {
+		return fmt.Errorf("cgnat: pool %q: NAT64 timeouts: %w", name, err)
+	}
+
+	c.logger.Info("NAT64 configured",
+		"pool", name,
+		"prefix", want,
+		"addresses", len(desired),
+		"added", len(missing),
+		"removed", len(stale))
+	return nil
+}
+
+// subscriberNets returns the addresses a subscriber's hosts send from:
+// its IA_NA address and its delegated prefix.
+func subscriberNets(sess models.SubscriberSession) []*net.IPNet {
+	if sess == nil {
+		return nil
+	}
+	var nets []*net.IPNet
+	if addr := sess.GetIPv6Address(); addr != nil {
+		nets = append(nets, &net.IPNet{IP: addr, Mask: net.CIDRMask(128, 128)})
+	}
+	if _, pd, err := net.ParseCIDR(sess.GetIPv6Prefix()); err == nil {
+		nets = append(nets, pd)
+	}
+	return nets
+}
+
+// sessionNets returns the subscriberNets of a stored session.
+func (c *Component) sessionNets(ctx context.Context, sessionID string) []*net.IPNet {
+	if c.sessionProvider == nil {
+		return nil
+	}
+	sess, ok := c.sessionProvider.SessionSnapshot(ctx, sessionID)
+	if !ok {
+		return nil
+	}
+	return subscriberNets(sess)
+}
+
+// enableNAT64Inside makes a subscriber's session interface a NAT64
+// inside interface and records the addresses its hosts send from, which
+// the BIB poll and the DNS64 forwarder match against; nil nets keep
+// those already recorded. Returns false when the dataplane refused.
+func (c *Component) enableNAT64Inside(sessionID string, swIfIndex uint32, nets []*net.IPNet) bool {
+	if c.nat64 == nil || swIfIndex == 0 {
+		return true
+	}
+	if err := c.nat64.NAT64AddDelInterface(swIfIndex, true, true); err != nil {
+		c.logger.Error("Failed to enable NAT64 on session", "session", sessionID, "sw_if_index", swIfIndex, "error", err)
+		return false
+	}
+	c.actMu.Lock()
+	c.sessionNAT64If[sessionID] = swIfIndex
+	if nets != nil {
+		c.nat64Owners.set(sessionID, nets)
+	}
+	c.actMu.Unlock()
+	return true
+}
+
+func (c *Component) disableNAT64Inside(sessionID string) {
+	c.actMu.Lock()
+	swIfIndex, ok := c.sessionNAT64If[sessionID]
+	delete(c.sessionNAT64If, sessionID)
+	c.nat64Owners.remove(sessionID)
+	c.actMu.Unlock()
+	if !ok || c.nat64 == nil {
+		return
+	}
+	if err := c.nat64.NAT64AddDelInterface(swIfIndex, true, false); err != nil {
+		c.logger.Debug("Failed to disable NAT64 on session", "session", sessionID, "sw_if_index", swIfIndex, "error", err)
+	}
+}
+
+// isNAT64Client reports whether ip is an address of a NAT64
+// subscriber: its IA_NA address or inside its delegated prefix.
+func (c *Component) isNAT64Client(ip net.IP) bool {
+	c.actMu.Lock()
+	defer c.actMu.Unlock()
+	_, ok := c.nat64Owners.lookup(ip)
+	return ok
+}
+
+// nat64BIBKey identifies a NAT64 binding by the outside transport
+// address the node chose for it.
+type nat64BIBKey struct {
+	proto uint8
+	ip    string
+	port  uint16
+}
+
+// nat64Owners matches inside addresses to the NAT64 subscribers that
+// hold them: an IA_NA address exactly, a delegated prefix by its
+// length. Guarded by the component's actMu.
+type nat64Owners struct {
+	addrs    map[string]string
+	prefixes map[int]map[string]string
+	sessions map[string][]*net.IPNet
+}
+
+func newNAT64Owners() *nat64Owners {
+	return &nat64Owners{
+		addrs:    make(map[string]string),
+		prefixes: make(map[int]map[string]string),
+		sessions: make(map[string][]*net.IPNet),
+	}
+}
+
+func (o *nat64Owners) set(sessionID string, nets []*net.IPNet) {
+	o.remove(sessionID)
+	for _, n := range nets {
+		ones, bits := n.Mask.Size()
+		if ones == bits {
+			o.addrs[n.IP.String()] = sessionID
+			continue
+		}
+		if o.prefixes[ones] == nil {
+			o.prefixes[ones] = make(map[string]string)
+		}
+		o.prefixes[ones][n.IP.Mask(n.Mask).String()] = sessionID
+	}
+	o.sessions[sessionID] = nets
+}
+
+func (o *nat64Owners) remove(sessionID string) {
+	for _, n := range o.sessions[sessionID] {
+		ones, bits := n.Mask.Size()
+		if ones == bits {
+			if o.addrs[n.IP.String()] == sessionID {
+				delete(o.addrs, n.IP.String())
+			}
+			continue
+		}
+		key := n.IP.Mask(n.Mask).String()
+		if o.prefixes[ones][key] == sessionID {
+			delete(o.prefixes[ones], key)
+		}
+	}
+	delete(o.sessions, sessionID)
+}
+
+func (o *nat64Owners) lookup(ip net.IP) (string, bool) {
+	if id, ok := o.addrs[ip.String()]; ok {
+		return id, true
+	}
+	for ones, nets := range o.prefixes {
+		if id, ok := nets[ip.Mask(net.CIDRMask(ones, 128)).String()]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func (c *Component) watchNAT64BIB() {
+	ticker := time.NewTicker(limitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkNAT64BIB()
+		case <-c.Ctx.Done():
+			return
+		}
+	}
+}
+
+// checkNAT64BIB attributes the NAT64 node's bindings to subscribers.
+// The node picks each outside address and port from the whole pool, so
+// a subscriber's port block does not say which ports it used; each
+// binding of a subscriber's address is published as a single-port
+// mapping when it appears and released when it goes, which is what the
+// logging exporters and the archive attribute by. Bindings are node
+// state that does not survive a switchover, so they are published
+// without an SRG and are not synced to the HA peer.
+func (c *Component) checkNAT64BIB() {
+	if c.nat64 == nil {
+		return
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil {
+		return
+	}
+	poolName, pool := nat64PoolConfig(cfg.CGNAT)
+	if pool == nil && len(c.nat64BIB) == 0 {
+		return
+	}
+	var entries []southbound.NAT64BIBEntry
+	if pool != nil {
+		if entries, err = c.nat64.NAT64BIBDump(); err != nil {
+			c.logger.Debug("Failed to dump NAT64 BIB", "error", err)
+			return
+		}
+	}
+
+	current := make(map[nat64BIBKey]*models.CGNATMapping, len(entries))
+	c.actMu.Lock()
+	for _, e := range entries {
+		sessionID, ok := c.nat64Owners.lookup(e.InsideIP)
+		if !ok {
+			continue
+		}
+		key := nat64BIBKey{proto: e.Protocol, ip: e.OutsideIP.String(), port: e.OutsidePort}
+		if prev, ok := c.nat64BIB[key]; ok && prev.SessionID == sessionID && prev.InsideIP.Equal(e.InsideIP) {
+			current[key] = prev
+			continue
+		}
+		current[key] = &models.CGNATMapping{
+			SessionID:      sessionID,
+			PoolName:       poolName,
+			PoolID:         poolID(poolName),
+			InsideIP:       e.InsideIP,
+			OutsideIP:      e.OutsideIP,
+			PortBlockStart: e.OutsidePort,
+			PortBlockEnd:   e.OutsidePort,
+		}
+	}
+	c.actMu.Unlock()
+
+	for key, m := range c.nat64BIB {
+		if cur, ok := current[key]; !ok || cur != m {
+			c.publishMappingEvent("", m, false)
+		}
+	}
+	for key, m := range current {
+		if prev, ok := c.nat64BIB[key]; !ok || prev != m {
+			c.publishMappingEvent("", m, true)
+		}
+	}
+	c.nat64BIB = current
+}
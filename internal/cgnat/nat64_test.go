@@ -0,0 +1,241 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type prefixCall struct {
+	prefix string
+	vrfID  uint32
+	isAdd  bool
+}
+
+type ifCall struct {
+	swIfIndex     uint32
+	inside, isAdd bool
+}
+
+type fakeNAT64 struct {
+	enabled   bool
+	prefixes  []southbound.NAT64Prefix
+	addresses []net.IP
+	prefixOps []prefixCall
+	ranges    []rangeCall
+	ifOps     []ifCall
+	timeouts  [4]uint32
+	bib       []southbound.NAT64BIBEntry
+}
+
+func (f *fakeNAT64) NAT64Enable() error { f.enabled = true; return nil }
+
+func (f *fakeNAT64) NAT64AddDelPrefix(prefix net.IPNet, vrfID uint32, isAdd bool) error {
+	f.prefixOps = append(f.prefixOps, prefixCall{prefix.String(), vrfID, isAdd})
+	return nil
+}
+
+func (f *fakeNAT64) NAT64PrefixDump() ([]southbound.NAT64Prefix, error) { return f.prefixes, nil }
+
+func (f *fakeNAT64) NAT64AddDelPoolAddrRange(start, end net.IP, vrfID uint32, isAdd bool) error {
+	f.ranges = append(f.ranges, rangeCall{start.String(), end.String(), isAdd})
+	return nil
+}
+
+func (f *fakeNAT64) NAT64PoolAddrDump() ([]net.IP, error) { return f.addresses, nil }
+
+func (f *fakeNAT64) NAT64AddDelInterface(swIfIndex uint32, inside, isAdd bool) error {
+	f.ifOps = append(f.ifOps, ifCall{swIfIndex, inside, isAdd})
+	return nil
+}
+
+func (f *fakeNAT64) NAT64SetTimeouts(udp, tcpEstablished, tcpTransitory, icmp uint32) error {
+	f.timeouts = [4]uint32{udp, tcpEstablished, tcpTransitory, icmp}
+	return nil
+}
+
+func (f *fakeNAT64) NAT64BIBDump() ([]southbound.NAT64BIBEntry, error) { return f.bib, nil }
+
+func nat64Config() *config.Config {
+	return &config.Config{
+		CGNAT: &cgnat.Config{
+			Pools: map[string]*cgnat.Pool{
+				"xlat": {
+					Mode:                   cgnat.ModeNAT64,
+					BlockSize:              512,
+					MaxBlocksPerSubscriber: 2,
+					PortRange:              "1024-65535",
+					OutsideAddresses:       []string{"100.64.1.0/31"},
+					NAT64:                  &cgnat.NAT64Config{Prefix: "2001:db8:64::/96"},
+				},
+			},
+		},
+		ServiceGroups: map[string]*servicegroup.Config{
+			"v6only": {CGNAT: &servicegroup.CGNATConfig{Policy: "xlat", Mode: servicegroup.CGNATModeNAT64}},
+		},
+	}
+}
+
+func TestReconcileNAT64_ProgramsPrefixAndRanges(t *testing.T) {
+	cfg := nat64Config()
+	_, wkp, _ := net.ParseCIDR(cgnat.DefaultNAT64Prefix)
+	dp := &fakeNAT64{
+		prefixes:  []southbound.NAT64Prefix{{Prefix: *wkp}},
+		addresses: []net.IP{net.ParseIP("100.64.9.9").To4()},
+	}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, pbaConfig())
+	c.nat64 = dp
+
+	if err := c.reconcileNAT64(cfg); err != nil {
+		t.Fatalf("reconcileNAT64: %v", err)
+	}
+	if !dp.enabled {
+		t.Fatal("NAT64 plugin not enabled")
+	}
+	wantPrefix := []prefixCall{
+		{"64:ff9b::/96", 0, false},
+		{"2001:db8:64::/96", 0, true},
+	}
+	if len(dp.prefixOps) != len(wantPrefix) || dp.prefixOps[0] != wantPrefix[0] || dp.prefixOps[1] != wantPrefix[1] {
+		t.Fatalf("prefix calls = %+v, want %+v", dp.prefixOps, wantPrefix)
+	}
+	wantRanges := []rangeCall{
+		{"100.64.9.9", "100.64.9.9", false},
+		{"100.64.1.0", "100.64.1.1", true},
+	}
+	if len(dp.ranges) != len(wantRanges) || dp.ranges[0] != wantRanges[0] || dp.ranges[1] != wantRanges[1] {
+		t.Fatalf("range calls = %+v, want %+v", dp.ranges, wantRanges)
+	}
+	if _, ok := c.poolIDMap["xlat"]; !ok {
+		t.Fatal("NAT64 pool not registered in poolIDMap")
+	}
+}
+
+func TestNAT64Lifecycle_EnablesInsideAndReleasesBlock(t *testing.T) {
+	cfg := nat64Config()
+	dp := &fakeNAT64{}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, cfg)
+	c.nat64 = dp
+	bus := local.NewBus()
+	c.eventBus = bus
+	mappings := make(chan *events.CGNATMappingEvent, 4)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		mappings <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	addr := net.ParseIP("2001:db8:1::42")
+	sess := &models.IPoESession{
+		SessionID:    "s1",
+		AccessType:   string(models.AccessTypeIPoE),
+		Protocol:     string(models.ProtocolDHCPv6),
+		ServiceGroup: "v6only",
+		IPv6Address:  addr,
+		IfIndex:      17,
+	}
+	c.dispatchLifecycle(events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		Protocol:   models.ProtocolDHCPv6,
+		SessionID:  "s1",
+		State:      models.SessionStateActive,
+		Session:    sess,
+	}})
+
+	add := nextMappingEvent(t, mappings)
+	if !add.IsAdd || !add.Mapping.InsideIP.Equal(addr) || add.Mapping.PoolName != "xlat" {
+		t.Fatalf("add event: %+v", add.Mapping)
+	}
+	if len(dp.ifOps) != 1 || dp.ifOps[0] != (ifCall{17, true, true}) {
+		t.Fatalf("interface calls after activate = %+v", dp.ifOps)
+	}
+	if !c.isNAT64Client(addr) {
+		t.Fatal("active subscriber is not a DNS64 client")
+	}
+
+	c.dispatchLifecycle(events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		Protocol:   models.ProtocolDHCPv6,
+		SessionID:  "s1",
+		State:      models.SessionStateReleased,
+		Session:    sess,
+	}})
+
+	del := nextMappingEvent(t, mappings)
+	if del.IsAdd || !del.Mapping.InsideIP.Equal(addr) {
+		t.Fatalf("release event: %+v", del.Mapping)
+	}
+	if len(dp.ifOps) != 2 || dp.ifOps[1] != (ifCall{17, true, false}) {
+		t.Fatalf("interface calls after release = %+v", dp.ifOps)
+	}
+	if c.isNAT64Client(addr) {
+		t.Fatal("released subscriber is still a DNS64 client")
+	}
+	if got := len(c.pools.GetMappings("xlat", addr, 0)); got != 0 {
+		t.Fatalf("subscriber still holds %d blocks after release", got)
+	}
+}
+
+func TestNAT64BIB_PublishesSubscriberBindings(t *testing.T) {
+	dp := &fakeNAT64{}
+	c := newRestoreComponent(t, &fakeDP{}, newFakeOpDB(), &fakeProvider{}, nat64Config())
+	c.nat64 = dp
+	c.nat64Owners.set("s1", subscriberNets(&models.IPoESession{SessionID: "s1", IPv6Address: net.ParseIP("2001:db8:1::42")}))
+	c.nat64Owners.set("s2", subscriberNets(&models.IPoESession{SessionID: "s2", IPv6Prefix: "2001:db8:200::/56"}))
+	bus := local.NewBus()
+	c.eventBus = bus
+	mappings := make(chan *events.CGNATMappingEvent, 8)
+	bus.Subscribe(events.TopicCGNATMapping, func(ev events.Event) {
+		mappings <- ev.Data.(*events.CGNATMappingEvent)
+	})
+
+	s1 := southbound.NAT64BIBEntry{InsideIP: net.ParseIP("2001:db8:1::42"), InsidePort: 5000,
+		OutsideIP: net.ParseIP("100.64.1.0").To4(), OutsidePort: 40000, Protocol: 6}
+	s2 := southbound.NAT64BIBEntry{InsideIP: net.ParseIP("2001:db8:200:5::9"), InsidePort: 53,
+		OutsideIP: net.ParseIP("100.64.1.1").To4(), OutsidePort: 1234, Protocol: 17}
+	stranger := southbound.NAT64BIBEntry{InsideIP: net.ParseIP("2001:db8:9::1"), InsidePort: 80,
+		OutsideIP: net.ParseIP("100.64.1.1").To4(), OutsidePort: 1235, Protocol: 6}
+	dp.bib = []southbound.NAT64BIBEntry{s1, s2, stranger}
+
+	c.checkNAT64BIB()
+	added := map[string]*events.CGNATMappingEvent{}
+	for i := 0; i < 2; i++ {
+		ev := nextMappingEvent(t, mappings)
+		added[ev.SessionID] = ev
+	}
+	for id, want := range map[string]southbound.NAT64BIBEntry{"s1": s1, "s2": s2} {
+		ev := added[id]
+		if ev == nil || !ev.IsAdd || ev.SRGName != "" {
+			t.Fatalf("%s: add event %+v", id, ev)
+		}
+		m := ev.Mapping
+		if !m.InsideIP.Equal(want.InsideIP) || !m.OutsideIP.Equal(want.OutsideIP) ||
+			m.PortBlockStart != want.OutsidePort || m.PortBlockEnd != want.OutsidePort || m.PoolName != "xlat" {
+			t.Fatalf("%s: mapping %+v, want binding %+v", id, m, want)
+		}
+	}
+
+	// An unchanged BIB publishes nothing; an expired binding is released.
+	c.checkNAT64BIB()
+	dp.bib = []southbound.NAT64BIBEntry{s2, stranger}
+	c.checkNAT64BIB()
+	del := nextMappingEvent(t, mappings)
+	if del.IsAdd || del.SessionID != "s1" || del.Mapping.PortBlockStart != 40000 {
+		t.Fatalf("release event: %+v", del.Mapping)
+	}
+	select {
+	case ev := <-mappings:
+		t.Fatalf("unexpected mapping event: %+v", ev.Mapping)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
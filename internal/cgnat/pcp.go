@@ -0,0 +1,471 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// PCP wire constants (RFC 6887 §7, §11, §19).
+const (
+	pcpVersion       = 2
+	pcpMaxMessage    = 1100
+	pcpHeaderLen     = 24
+	pcpMAPPayloadLen = 36
+	pcpResponseBit   = 0x80
+
+	pcpOpAnnounce = 0
+	pcpOpMAP      = 1
+
+	pcpOptionPreferFailure = 2
+	pcpOptionalFrom        = 128
+)
+
+// PCP result codes (RFC 6887 §7.4).
+const (
+	pcpSuccess               = 0
+	pcpUnsuppVersion         = 1
+	pcpNotAuthorized         = 2
+	pcpMalformedRequest      = 3
+	pcpUnsuppOpcode          = 4
+	pcpUnsuppOption          = 5
+	pcpMalformedOption       = 6
+	pcpNoResources           = 8
+	pcpUnsuppProtocol        = 9
+	pcpUserExQuota           = 10
+	pcpCannotProvideExternal = 11
+	pcpAddressMismatch       = 12
+)
+
+// pcpErrorLifetime is how long a client should wait before retrying a
+// request that failed for a reason that may clear up.
+const pcpErrorLifetime = 30
+
+// pcpRequest is a parsed PCP request. Payload is the raw opcode payload,
+// echoed back in error responses.
+type pcpRequest struct {
+	Opcode        uint8
+	Lifetime      uint32
+	ClientIP      net.IP
+	Payload       []byte
+	Nonce         [12]byte
+	Protocol      uint8
+	InternalPort  uint16
+	ExternalPort  uint16
+	ExternalIP    net.IP
+	PreferFailure bool
+}
+
+// pcpError is a request the server answers with a non-success result.
+type pcpError struct {
+	result uint8
+	msg    string
+}
+
+func (e *pcpError) Error() string { return e.msg }
+
+func pcpErrorf(result uint8, format string, args ...interface{}) error {
+	return &pcpError{result: result, msg: fmt.Sprintf(format, args...)}
+}
+
+// parsePCPRequest decodes a request. A nil request with an error means
+// the message must be dropped silently (RFC 6887 §8.3); a request with a
+// *pcpError is answered with that result.
+func parsePCPRequest(b []byte) (*pcpRequest, error) {
+	if len(b) < 2 || b[1]&pcpResponseBit != 0 {
+		return nil, errors.New("not a PCP request")
+	}
+	req := &pcpRequest{Opcode: b[1] & 0x7f}
+	if len(b) < pcpHeaderLen {
+		return req, pcpErrorf(pcpMalformedRequest, "short request of %d bytes", len(b))
+	}
+	req.Lifetime = binary.BigEndian.Uint32(b[4:8])
+	req.ClientIP = net.IP(append([]byte(nil), b[8:24]...))
+	if b[0] != pcpVersion {
+		return req, pcpErrorf(pcpUnsuppVersion, "unsupported version %d", b[0])
+	}
+	if len(b) > pcpMaxMessage || len(b)%4 != 0 {
+		return req, pcpErrorf(pcpMalformedRequest, "bad request length %d", len(b))
+	}
+
+	body := b[pcpHeaderLen:]
+	switch req.Opcode {
+	case pcpOpAnnounce:
+		return req, nil
+	case pcpOpMAP:
+	default:
+		return req, pcpErrorf(pcpUnsuppOpcode, "unsupported opcode %d", req.Opcode)
+	}
+
+	if len(body) < pcpMAPPayloadLen {
+		return req, pcpErrorf(pcpMalformedRequest, "short MAP payload")
+	}
+	req.Payload = append([]byte(nil), body[:pcpMAPPayloadLen]...)
+	copy(req.Nonce[:], body[0:12])
+	req.Protocol = body[12]
+	req.InternalPort = binary.BigEndian.Uint16(body[16:18])
+	req.ExternalPort = binary.BigEndian.Uint16(body[18:20])
+	req.ExternalIP = net.IP(append([]byte(nil), body[20:36]...))
+
+	opts := body[pcpMAPPayloadLen:]
+	for len(opts) > 0 {
+		if len(opts) < 4 {
+			return req, pcpErrorf(pcpMalformedOption, "truncated option header")
+		}
+		code := opts[0]
+		length := int(binary.BigEndian.Uint16(opts[2:4]))
+		padded := (length + 3) &^ 3
+		if len(opts) < 4+padded {
+			return req, pcpErrorf(pcpMalformedOption, "option %d overruns the message", code)
+		}
+		switch {
+		case code == pcpOptionPreferFailure:
+			if length != 0 {
+				return req, pcpErrorf(pcpMalformedOption, "PREFER_FAILURE carries data")
+			}
+			req.PreferFailure = true
+		case code < pcpOptionalFrom:
+			return req, pcpErrorf(pcpUnsuppOption, "unsupported mandatory option %d", code)
+		}
+		opts = opts[4+padded:]
+	}
+	return req, nil
+}
+
+// marshalPCPResponse encodes the response to req. For MAP the payload
+// echoes the request's nonce, protocol and internal port with the
+// assigned external address and port; error responses echo the request
+// payload unchanged.
+func marshalPCPResponse(req *pcpRequest, result uint8, lifetime, epoch uint32, extIP net.IP, extPort uint16) []byte {
+	out := make([]byte, pcpHeaderLen, pcpHeaderLen+pcpMAPPayloadLen)
+	out[0] = pcpVersion
+	out[1] = pcpResponseBit | req.Opcode
+	out[3] = result
+	binary.BigEndian.PutUint32(out[4:8], lifetime)
+	binary.BigEndian.PutUint32(out[8:12], epoch)
+
+	if req.Opcode != pcpOpMAP || len(req.Payload) != pcpMAPPayloadLen {
+		return out
+	}
+	payload := append([]byte(nil), req.Payload...)
+	if result == pcpSuccess {
+		binary.BigEndian.PutUint16(payload[18:20], extPort)
+		copy(payload[20:36], extIP.To16())
+	}
+	return append(out, payload...)
+}
+
+// pcpServer is the subscriber-facing PCP server. MAP requests become
+// PCP port forwards inside the requesting subscriber's port block.
+type pcpServer struct {
+	logger  *logger.Logger
+	binding netbind.Binding
+	conn    *net.UDPConn
+	started time.Time
+
+	// handle answers one MAP request from client; replaced in tests.
+	handle func(ctx context.Context, client net.IP, req *pcpRequest) (lifetime uint32, extIP net.IP, extPort uint16, err error)
+}
+
+func (s *pcpServer) start(ctx context.Context) error {
+	conn, err := netbind.ListenUDP(ctx, "udp4", cgnat.DefaultPCPPort, s.binding)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.started = time.Now()
+	return nil
+}
+
+func (s *pcpServer) serve(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		s.conn.Close()
+	}()
+
+	buf := make([]byte, pcpMaxMessage+1)
+	for {
+		n, peer, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.logger.Debug("PCP read failed", "error", err)
+			continue
+		}
+		resp := s.answer(ctx, peer.IP, buf[:n])
+		if resp == nil {
+			continue
+		}
+		if _, err := s.conn.WriteToUDP(resp, peer); err != nil {
+			s.logger.Debug("PCP reply failed", "client", peer, "error", err)
+		}
+	}
+}
+
+// epoch is the server's epoch time (RFC 6887 §8.5): seconds since it
+// started, so clients notice a restart and refresh their mappings.
+func (s *pcpServer) epoch() uint32 {
+	return uint32(time.Since(s.started) / time.Second)
+}
+
+// answer handles one datagram from client, returning nil when it must
+// be dropped.
+func (s *pcpServer) answer(ctx context.Context, client net.IP, msg []byte) []byte {
+	req, err := parsePCPRequest(msg)
+	if req == nil {
+		return nil
+	}
+	if err == nil && !req.ClientIP.Equal(client) {
+		err = pcpErrorf(pcpAddressMismatch, "client address %s does not match source %s", req.ClientIP, client)
+	}
+	if err == nil && req.Opcode == pcpOpAnnounce {
+		return marshalPCPResponse(req, pcpSuccess, 0, s.epoch(), nil, 0)
+	}
+
+	var lifetime uint32
+	var extIP net.IP
+	var extPort uint16
+	if err == nil {
+		lifetime, extIP, extPort, err = s.handle(ctx, client, req)
+	}
+	if err != nil {
+		var perr *pcpError
+		if !errors.As(err, &perr) {
+			perr = &pcpError{result: pcpNoResources, msg: err.Error()}
+		}
+		s.logger.Debug("PCP request refused", "client", client, "opcode", req.Opcode, "result", perr.result, "reason", perr.msg)
+		return marshalPCPResponse(req, perr.result, pcpErrorLifetime, s.epoch(), nil, 0)
+	}
+	return marshalPCPResponse(req, pcpSuccess, lifetime, s.epoch(), extIP, extPort)
+}
+
+// startPCP runs the PCP server, if configured, for the life of the
+// component.
+func (c *Component) startPCP(pcp *cgnat.PCPConfig) error {
+	if pcp == nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(pcp.Address)
+	if err != nil {
+		return fmt.Errorf("pcp address: %w", err)
+	}
+	srv := &pcpServer{
+		logger:  c.logger,
+		binding: netbind.Binding{VRF: pcp.VRF, SourceIP: addr},
+	}
+	srv.handle = func(ctx context.Context, client net.IP, req *pcpRequest) (uint32, net.IP, uint16, error) {
+		return c.pcpMap(ctx, pcp, client, req, time.Now())
+	}
+	if err := srv.start(c.Ctx); err != nil {
+		return err
+	}
+	c.Go(func() { srv.serve(c.Ctx) })
+	c.Go(func() { c.sweepForwards(c.Ctx) })
+	c.logger.Info("PCP server listening", "address", pcp.Address, "vrf", pcp.VRF)
+	return nil
+}
+
+// pcpMap creates, renews or deletes the PCP forward a MAP request names
+// (RFC 6887 §11.3). The external address is always the one of the
+// subscriber's block; a suggested port is honoured when it is in the
+// block and free.
+func (c *Component) pcpMap(ctx context.Context, pcp *cgnat.PCPConfig, client net.IP, req *pcpRequest, now time.Time) (uint32, net.IP, uint16, error) {
+	insideIP := client.To4()
+	if insideIP == nil {
+		return 0, nil, 0, pcpErrorf(pcpNotAuthorized, "PCP is only offered to IPv4 subscribers")
+	}
+	if req.Lifetime == 0 {
+		return 0, nil, 0, c.pcpDelete(ctx, insideIP, req)
+	}
+
+	var proto string
+	switch req.Protocol {
+	case 6:
+		proto = "tcp"
+	case 17:
+		proto = "udp"
+	default:
+		return 0, nil, 0, pcpErrorf(pcpUnsuppProtocol, "protocol %d cannot be forwarded", req.Protocol)
+	}
+	if req.InternalPort == 0 {
+		return 0, nil, 0, pcpErrorf(pcpMalformedRequest, "internal port 0 needs protocol 0")
+	}
+
+	lifetime := req.Lifetime
+	if min := pcp.GetMinLifetime(); lifetime < min {
+		lifetime = min
+	}
+	if max := pcp.GetMaxLifetime(); lifetime > max {
+		lifetime = max
+	}
+	expires := now.Add(time.Duration(lifetime) * time.Second)
+
+	probe := &models.CGNATMapping{InsideIP: insideIP, Forward: &models.CGNATPortForward{Protocol: proto, InsidePort: req.InternalPort}}
+	id := probe.ForwardID()
+
+	c.fwdMu.Lock()
+	if f, ok := c.forwards[id]; ok {
+		defer c.fwdMu.Unlock()
+		if f.mapping.Forward.Source != models.CGNATForwardPCP {
+			return 0, nil, 0, pcpErrorf(pcpNotAuthorized, "%s is forwarded by the operator", id)
+		}
+		if subtle.ConstantTimeCompare(f.mapping.Forward.Nonce, req.Nonce[:]) != 1 {
+			return 0, nil, 0, pcpErrorf(pcpNotAuthorized, "nonce does not match the mapping of %s", id)
+		}
+		f.mapping.Forward.ExpiresAt = &expires
+		c.persistForward(ctx, f)
+		return lifetime, f.mapping.OutsideIP, f.mapping.PortBlockStart, nil
+	}
+	count := 0
+	for _, f := range c.forwards {
+		if f.mapping.Forward.Source == models.CGNATForwardPCP && f.mapping.InsideIP.Equal(insideIP) {
+			count++
+		}
+	}
+	c.fwdMu.Unlock()
+	if count >= int(pcp.GetMaxMappingsPerSubscriber()) {
+		return 0, nil, 0, pcpErrorf(pcpUserExQuota, "%s holds %d PCP mappings", insideIP, count)
+	}
+
+	blocks := c.pools.GetSubscriberMappings(insideIP, 0)
+	if len(blocks) == 0 {
+		return 0, nil, 0, pcpErrorf(pcpNotAuthorized, "%s holds no port block", insideIP)
+	}
+	extIP, extPort, err := c.pcpPickPort(proto, insideIP, blocks, req)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	m := &models.CGNATMapping{
+		InsideIP:       insideIP,
+		OutsideIP:      extIP,
+		PortBlockStart: extPort,
+		PortBlockEnd:   extPort,
+		Forward: &models.CGNATPortForward{
+			Protocol:   proto,
+			InsidePort: req.InternalPort,
+			Source:     models.CGNATForwardPCP,
+			ExpiresAt:  &expires,
+			Nonce:      append([]byte(nil), req.Nonce[:]...),
+		},
+	}
+	if _, err := c.addForward(ctx, m, true); err != nil {
+		return 0, nil, 0, pcpErrorf(pcpNoResources, "%v", err)
+	}
+	return lifetime, extIP, extPort, nil
+}
+
+// pcpDelete removes the client's mapping for the request's internal
+// port, or all its mappings under the nonce when the protocol is 0.
+// Deleting a mapping that does not exist succeeds (RFC 6887 §15).
+func (c *Component) pcpDelete(ctx context.Context, insideIP net.IP, req *pcpRequest) error {
+	type gone struct {
+		srgName string
+		mapping models.CGNATMapping
+	}
+	var released []gone
+	c.fwdMu.Lock()
+	for id, f := range c.forwards {
+		fwd := f.mapping.Forward
+		if fwd.Source != models.CGNATForwardPCP || !f.mapping.InsideIP.Equal(insideIP) {
+			continue
+		}
+		if req.Protocol != 0 && (forwardProtocolNumber(fwd.Protocol) != req.Protocol || fwd.InsidePort != req.InternalPort) {
+			continue
+		}
+		if subtle.ConstantTimeCompare(fwd.Nonce, req.Nonce[:]) != 1 {
+			if req.Protocol != 0 {
+				c.fwdMu.Unlock()
+				return pcpErrorf(pcpNotAuthorized, "nonce does not match the mapping of %s", id)
+			}
+			continue
+		}
+		if m, wasActive := c.dropForwardLocked(ctx, id, f); wasActive {
+			released = append(released, gone{f.srgName, m})
+		}
+	}
+	c.fwdMu.Unlock()
+
+	for i := range released {
+		c.publishMappingEvent(released[i].srgName, &released[i].mapping, false)
+	}
+	return nil
+}
+
+// pcpPickPort chooses the external port for a new mapping: the suggested
+// one when it is in the subscriber's blocks and free, otherwise the
+// first free port, unless the client asked to fail instead.
+func (c *Component) pcpPickPort(proto string, insideIP net.IP, blocks []models.CGNATMapping, req *pcpRequest) (net.IP, uint16, error) {
+	used, err := c.usedOutsidePorts(proto, insideIP)
+	if err != nil {
+		return nil, 0, err
+	}
+	free := func(ip net.IP, port uint16) bool {
+		_, taken := used[fmt.Sprintf("%s/%s/%d", proto, ip, port)]
+		return !taken
+	}
+
+	if req.ExternalPort != 0 {
+		wantIP := req.ExternalIP.To4()
+		for _, b := range blocks {
+			if wantIP != nil && !wantIP.IsUnspecified() && !wantIP.Equal(b.OutsideIP) {
+				continue
+			}
+			if req.ExternalPort >= b.PortBlockStart && req.ExternalPort <= b.PortBlockEnd && free(b.OutsideIP, req.ExternalPort) {
+				return b.OutsideIP, req.ExternalPort, nil
+			}
+		}
+		if req.PreferFailure {
+			return nil, 0, pcpErrorf(pcpCannotProvideExternal, "suggested port %d is not available", req.ExternalPort)
+		}
+	}
+
+	for _, b := range blocks {
+		for port := uint32(b.PortBlockStart); port <= uint32(b.PortBlockEnd); port++ {
+			if free(b.OutsideIP, uint16(port)) {
+				return b.OutsideIP, uint16(port), nil
+			}
+		}
+	}
+	return nil, 0, pcpErrorf(pcpNoResources, "no free port in the blocks of %s", insideIP)
+}
+
+// usedOutsidePorts returns the outside ports of proto the subscriber
+// already uses, by forwards or live translations, keyed like
+// outsideForwardKey.
+func (c *Component) usedOutsidePorts(proto string, insideIP net.IP) (map[string]struct{}, error) {
+	used := map[string]struct{}{}
+	c.fwdMu.Lock()
+	for _, f := range c.forwards {
+		used[outsideForwardKey(&f.mapping)] = struct{}{}
+	}
+	c.fwdMu.Unlock()
+
+	sessions, err := c.dataplane.CGNATDumpSessions(southbound.CGNATSessionFilter{
+		InsideIP: insideIP,
+		Proto:    forwardProtocolNumber(proto),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dump live translations: %w", err)
+	}
+	for _, s := range sessions {
+		used[fmt.Sprintf("%s/%s/%d", proto, s.OutsideIP, s.OutsidePort)] = struct{}{}
+	}
+	return used, nil
+}
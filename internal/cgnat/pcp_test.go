@@ -0,0 +1,200 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+)
+
+func pcpMAPRequest(client net.IP, lifetime uint32, proto uint8, internal, external uint16, options ...byte) []byte {
+	b := make([]byte, pcpHeaderLen+pcpMAPPayloadLen, pcpHeaderLen+pcpMAPPayloadLen+len(options))
+	b[0] = pcpVersion
+	b[1] = pcpOpMAP
+	binary.BigEndian.PutUint32(b[4:8], lifetime)
+	copy(b[8:24], client.To16())
+	body := b[pcpHeaderLen:]
+	copy(body[0:12], "nonce-nonce!")
+	body[12] = proto
+	binary.BigEndian.PutUint16(body[16:18], internal)
+	binary.BigEndian.PutUint16(body[18:20], external)
+	copy(body[20:36], net.IPv4zero.To16())
+	return append(b, options...)
+}
+
+func pcpResult(err error) uint8 {
+	var perr *pcpError
+	if errors.As(err, &perr) {
+		return perr.result
+	}
+	return 0xff
+}
+
+func TestParsePCPRequest(t *testing.T) {
+	client := net.ParseIP("10.0.0.5")
+
+	req, err := parsePCPRequest(pcpMAPRequest(client, 600, 6, 8080, 2000, pcpOptionPreferFailure, 0, 0, 0))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if req.Opcode != pcpOpMAP || req.Lifetime != 600 || req.Protocol != 6 || req.InternalPort != 8080 ||
+		req.ExternalPort != 2000 || !req.ClientIP.Equal(client) || !req.PreferFailure {
+		t.Fatalf("request = %+v", req)
+	}
+
+	if req, _ := parsePCPRequest([]byte{pcpVersion, pcpResponseBit | pcpOpMAP}); req != nil {
+		t.Fatal("response was not dropped")
+	}
+
+	cases := []struct {
+		name string
+		msg  []byte
+		want uint8
+	}{
+		{"short", make([]byte, 8), pcpMalformedRequest},
+		{"version", func() []byte { b := pcpMAPRequest(client, 1, 6, 1, 0); b[0] = 1; return b }(), pcpUnsuppVersion},
+		{"opcode", func() []byte { b := pcpMAPRequest(client, 1, 6, 1, 0); b[1] = 2; return b }(), pcpUnsuppOpcode},
+		{"mandatory option", pcpMAPRequest(client, 1, 6, 1, 0, 3, 0, 0, 0), pcpUnsuppOption},
+		{"truncated option", pcpMAPRequest(client, 1, 6, 1, 0, 128, 0, 0, 8), pcpMalformedOption},
+	}
+	for _, tc := range cases {
+		_, err := parsePCPRequest(tc.msg)
+		if got := pcpResult(err); got != tc.want {
+			t.Errorf("%s: result = %d, want %d (%v)", tc.name, got, tc.want, err)
+		}
+	}
+
+	if _, err := parsePCPRequest(pcpMAPRequest(client, 1, 6, 1, 0, 200, 0, 0, 4, 1, 2, 3, 4)); err != nil {
+		t.Fatalf("optional option rejected: %v", err)
+	}
+}
+
+func TestMarshalPCPResponse(t *testing.T) {
+	client := net.ParseIP("10.0.0.5")
+	req, err := parsePCPRequest(pcpMAPRequest(client, 600, 17, 5000, 0))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	out := marshalPCPResponse(req, pcpSuccess, 300, 7, net.ParseIP("100.64.0.1"), 1030)
+	if len(out) != pcpHeaderLen+pcpMAPPayloadLen {
+		t.Fatalf("length = %d", len(out))
+	}
+	if out[1] != pcpResponseBit|pcpOpMAP || out[3] != pcpSuccess ||
+		binary.BigEndian.Uint32(out[4:8]) != 300 || binary.BigEndian.Uint32(out[8:12]) != 7 {
+		t.Fatalf("header = %x", out[:pcpHeaderLen])
+	}
+	body := out[pcpHeaderLen:]
+	if string(body[0:12]) != "nonce-nonce!" || body[12] != 17 || binary.BigEndian.Uint16(body[16:18]) != 5000 {
+		t.Fatalf("echoed payload = %x", body)
+	}
+	if binary.BigEndian.Uint16(body[18:20]) != 1030 || !net.IP(body[20:36]).Equal(net.ParseIP("100.64.0.1")) {
+		t.Fatalf("external = %x", body[18:36])
+	}
+}
+
+func TestPCPServerAnswer(t *testing.T) {
+	client := net.ParseIP("10.0.0.5")
+	srv := &pcpServer{logger: logger.Get("cgnat-test"), started: time.Now()}
+	srv.handle = func(ctx context.Context, c net.IP, req *pcpRequest) (uint32, net.IP, uint16, error) {
+		return 0, nil, 0, pcpErrorf(pcpUserExQuota, "quota")
+	}
+
+	out := srv.answer(context.Background(), net.ParseIP("10.0.0.6"), pcpMAPRequest(client, 600, 6, 80, 0))
+	if out[3] != pcpAddressMismatch {
+		t.Fatalf("mismatch result = %d", out[3])
+	}
+	out = srv.answer(context.Background(), client, pcpMAPRequest(client, 600, 6, 80, 0))
+	if out[3] != pcpUserExQuota || binary.BigEndian.Uint32(out[4:8]) != pcpErrorLifetime {
+		t.Fatalf("quota result = %d lifetime %d", out[3], binary.BigEndian.Uint32(out[4:8]))
+	}
+
+	announce := pcpMAPRequest(client, 0, 0, 0, 0)[:pcpHeaderLen]
+	announce[1] = pcpOpAnnounce
+	if out := srv.answer(context.Background(), client, announce); out[3] != pcpSuccess || len(out) != pcpHeaderLen {
+		t.Fatalf("announce = %x", out)
+	}
+}
+
+func TestPCPMap_CreateRenewDelete(t *testing.T) {
+	c, pnat, block, _ := newForwardComponent(t)
+	pcp := &cgnat.PCPConfig{Address: "10.0.0.1", MaxMappingsPerSubscriber: 1}
+	now := time.Now()
+	client := block.InsideIP
+
+	suggested := block.PortBlockStart + 7
+	req, _ := parsePCPRequest(pcpMAPRequest(client, 60, 6, 8080, suggested))
+	lifetime, extIP, extPort, err := c.pcpMap(context.Background(), pcp, client, req, now)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	if lifetime != cgnat.DefaultPCPMinLifetime || !extIP.Equal(block.OutsideIP) || extPort != suggested {
+		t.Fatalf("map = %d %s:%d", lifetime, extIP, extPort)
+	}
+	if len(pnat.bindings) != 2 {
+		t.Fatalf("bindings = %d, want 2", len(pnat.bindings))
+	}
+
+	if _, _, port, err := c.pcpMap(context.Background(), pcp, client, req, now.Add(time.Minute)); err != nil || port != suggested {
+		t.Fatalf("renew: port %d err %v", port, err)
+	}
+	wrong := *req
+	wrong.Nonce[0] ^= 0xff
+	if _, _, _, err := c.pcpMap(context.Background(), pcp, client, &wrong, now); pcpResult(err) != pcpNotAuthorized {
+		t.Fatalf("renew with wrong nonce: %v", err)
+	}
+
+	other, _ := parsePCPRequest(pcpMAPRequest(client, 600, 17, 53, 0))
+	if _, _, _, err := c.pcpMap(context.Background(), pcp, client, other, now); pcpResult(err) != pcpUserExQuota {
+		t.Fatalf("quota: %v", err)
+	}
+
+	del := *req
+	del.Lifetime = 0
+	if _, _, _, err := c.pcpMap(context.Background(), pcp, client, &del, now); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(c.GetPortForwards()) != 0 || len(pnat.bindings) != 0 {
+		t.Fatalf("forward left after delete: %+v", c.GetPortForwards())
+	}
+}
+
+func TestPCPMap_PortSelectionAndExpiry(t *testing.T) {
+	c, _, block, _ := newForwardComponent(t)
+	pcp := &cgnat.PCPConfig{Address: "10.0.0.1"}
+	now := time.Now()
+	client := block.InsideIP
+
+	outside, _ := parsePCPRequest(pcpMAPRequest(client, 600, 6, 80, block.PortBlockEnd+1, pcpOptionPreferFailure, 0, 0, 0))
+	if _, _, _, err := c.pcpMap(context.Background(), pcp, client, outside, now); pcpResult(err) != pcpCannotProvideExternal {
+		t.Fatalf("PREFER_FAILURE: %v", err)
+	}
+
+	first, _ := parsePCPRequest(pcpMAPRequest(client, 600, 6, 80, block.PortBlockEnd+1))
+	_, _, port, err := c.pcpMap(context.Background(), pcp, client, first, now)
+	if err != nil || port != block.PortBlockStart {
+		t.Fatalf("fallback port = %d err %v", port, err)
+	}
+	second, _ := parsePCPRequest(pcpMAPRequest(client, 600, 6, 81, 0))
+	if _, _, port, err := c.pcpMap(context.Background(), pcp, client, second, now); err != nil || port != block.PortBlockStart+1 {
+		t.Fatalf("second port = %d err %v", port, err)
+	}
+
+	if _, _, _, err := c.pcpMap(context.Background(), pcp, net.ParseIP("10.0.0.99"), second, now); pcpResult(err) != pcpNotAuthorized {
+		t.Fatalf("no block: %v", err)
+	}
+
+	c.expireForwards(now.Add(time.Duration(cgnat.DefaultPCPMaxLifetime+1) * time.Second))
+	if got := c.GetPortForwards(); len(got) != 0 {
+		t.Fatalf("forwards after expiry = %+v", got)
+	}
+}
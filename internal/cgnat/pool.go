@@ -8,14 +8,18 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
 	"github.com/veesix-networks/osvbng/pkg/models"
 )
 
+// subscriberKey identifies a subscriber in a pool: an IPv4 inside
+// address, or the IPv6 B4 address of a DS-Lite softwire.
 type subscriberKey struct {
 	InsideVRF uint32
-	InsideIP  [4]byte
+	InsideIP  [16]byte
 }
 
 type blockAllocation struct {
@@ -28,14 +32,25 @@ type subscriberAllocation struct {
 	PoolName  string
 	PoolID    uint32
 	SwIfIndex uint32
+	SessionID string
 	Blocks    []blockAllocation
 }
 
 type outsideAddressState struct {
-	IP             net.IP
-	TotalBlocks    uint32
-	AllocatedBits  []uint64
-	Excluded       bool
+	IP            net.IP
+	TotalBlocks   uint32
+	AllocatedBits []uint64
+	Excluded      bool
+
+	// Runtime is set for an address added while running. A draining
+	// address takes no new blocks; its blocks are moved elsewhere from
+	// MigrateAt, if set. drainedLogged records that the drain finishing
+	// has been logged.
+	Runtime       bool
+	Draining      bool
+	DrainStarted  time.Time
+	MigrateAt     time.Time
+	drainedLogged bool
 }
 
 type PoolManager struct {
@@ -50,6 +65,19 @@ type poolState struct {
 
 	OutsideAddresses []*outsideAddressState
 	Subscribers      map[subscriberKey]*subscriberAllocation
+	// prefixes are the outside address entries as programmed in the
+	// dataplane, each an IPv4 prefix.
+	prefixes []string
+
+	// level is the pool's watermark level as of the last threshold
+	// check, and empty whether it had no blocks allocated then.
+	level allocator.PoolLevel
+	empty bool
+
+	// sessionLimitDrops and portExhaustionDrops sum the plugin's
+	// per-subscriber drop counters over the pool's subscribers.
+	sessionLimitDrops   uint64
+	portExhaustionDrops uint64
 
 	nextPoolID uint32
 }
@@ -71,6 +99,7 @@ func (pm *PoolManager) ConfigurePool(name string, id uint32, cfg *cgnat.Pool) er
 	blocksPerAddr := usablePorts / uint32(blockSize)
 
 	var addresses []*outsideAddressState
+	var prefixes []string
 	for _, addrStr := range cfg.OutsideAddresses {
 		ips, err := expandCIDR(addrStr)
 		if err != nil {
@@ -84,6 +113,9 @@ func (pm *PoolManager) ConfigurePool(name string, id uint32, cfg *cgnat.Pool) er
 				AllocatedBits: make([]uint64, bitmapWords),
 			})
 		}
+		if n := parseOutsideAddr(addrStr); n != nil {
+			prefixes = append(prefixes, n.String())
+		}
 	}
 
 	excluded := make(map[string]bool)
@@ -102,6 +134,7 @@ func (pm *PoolManager) ConfigurePool(name string, id uint32, cfg *cgnat.Pool) er
 		Config:           cfg,
 		OutsideAddresses: addresses,
 		Subscribers:      make(map[subscriberKey]*subscriberAllocation),
+		prefixes:         prefixes,
 	}
 
 	return nil
@@ -137,7 +170,7 @@ func (pm *PoolManager) AllocateBlock(poolName string, insideIP net.IP, insideVRF
 	if sub != nil && len(sub.Blocks) > 0 && ps.Config.GetAddressPooling() == "paired" {
 		existingIP := sub.Blocks[0].OutsideIP
 		for _, addr := range ps.OutsideAddresses {
-			if addr.IP.Equal(existingIP) && !addr.Excluded {
+			if addr.IP.Equal(existingIP) && addr.allocatable() {
 				targetAddr = addr
 				break
 			}
@@ -146,7 +179,7 @@ func (pm *PoolManager) AllocateBlock(poolName string, insideIP net.IP, insideVRF
 
 	if targetAddr == nil {
 		for _, addr := range ps.OutsideAddresses {
-			if addr.Excluded {
+			if !addr.allocatable() {
 				continue
 			}
 			if hasFreeBlock(addr) {
@@ -188,12 +221,13 @@ func (pm *PoolManager) AllocateBlock(poolName string, insideIP net.IP, insideVRF
 	return &models.CGNATMapping{
 		PoolName:       poolName,
 		PoolID:         ps.ID,
-		InsideIP:       insideIP.To4(),
+		InsideIP:       subscriberIP(insideIP),
 		InsideVRFID:    insideVRF,
 		OutsideIP:      block.OutsideIP,
 		PortBlockStart: portBlockStart,
 		PortBlockEnd:   portBlockEnd,
 		SwIfIndex:      swIfIndex,
+		Extra:          len(sub.Blocks) > 1,
 	}, nil
 }
 
@@ -246,21 +280,43 @@ func (pm *PoolManager) GetMappings(poolName string, insideIP net.IP, insideVRF u
 	}
 
 	var mappings []models.CGNATMapping
-	for _, block := range sub.Blocks {
+	for i, block := range sub.Blocks {
 		mappings = append(mappings, models.CGNATMapping{
 			PoolName:       poolName,
 			PoolID:         ps.ID,
-			InsideIP:       insideIP.To4(),
+			InsideIP:       subscriberIP(insideIP),
 			InsideVRFID:    insideVRF,
 			OutsideIP:      block.OutsideIP,
 			PortBlockStart: block.PortBlockStart,
 			PortBlockEnd:   block.PortBlockEnd,
 			SwIfIndex:      sub.SwIfIndex,
+			Extra:          i > 0,
 		})
 	}
 	return mappings
 }
 
+// GetSubscriberMappings returns the blocks held by insideIP in whichever
+// pool it is allocated from, for callers that know the subscriber but
+// not the pool.
+func (pm *PoolManager) GetSubscriberMappings(insideIP net.IP, insideVRF uint32) []models.CGNATMapping {
+	pm.mu.RLock()
+	var poolName string
+	key := makeSubscriberKey(insideVRF, insideIP)
+	for name, ps := range pm.pools {
+		if _, ok := ps.Subscribers[key]; ok {
+			poolName = name
+			break
+		}
+	}
+	pm.mu.RUnlock()
+
+	if poolName == "" {
+		return nil
+	}
+	return pm.GetMappings(poolName, insideIP, insideVRF)
+}
+
 func (pm *PoolManager) GetAllMappings() []models.CGNATMapping {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
@@ -268,8 +324,8 @@ func (pm *PoolManager) GetAllMappings() []models.CGNATMapping {
 	var mappings []models.CGNATMapping
 	for _, ps := range pm.pools {
 		for key, sub := range ps.Subscribers {
-			insideIP := net.IP(key.InsideIP[:])
-			for _, block := range sub.Blocks {
+			insideIP := subscriberIP(append(net.IP(nil), key.InsideIP[:]...))
+			for i, block := range sub.Blocks {
 				mappings = append(mappings, models.CGNATMapping{
 					PoolName:       ps.Name,
 					PoolID:         ps.ID,
@@ -279,6 +335,8 @@ func (pm *PoolManager) GetAllMappings() []models.CGNATMapping {
 					PortBlockStart: block.PortBlockStart,
 					PortBlockEnd:   block.PortBlockEnd,
 					SwIfIndex:      sub.SwIfIndex,
+					SessionID:      sub.SessionID,
+					Extra:          i > 0,
 				})
 			}
 		}
@@ -295,15 +353,11 @@ func (pm *PoolManager) GetPoolStats(poolName string) *models.CGNATPoolStats {
 		return nil
 	}
 
-	var totalBlocks, allocatedBlocks, excludedAddrs uint32
+	totalBlocks, allocatedBlocks := ps.blockCounts()
+	var excludedAddrs uint32
 	for _, addr := range ps.OutsideAddresses {
 		if addr.Excluded {
 			excludedAddrs++
-			continue
-		}
-		totalBlocks += addr.TotalBlocks
-		for _, word := range addr.AllocatedBits {
-			allocatedBlocks += uint32(popcount(word))
 		}
 	}
 
@@ -318,15 +372,19 @@ func (pm *PoolManager) GetPoolStats(poolName string) *models.CGNATPoolStats {
 	}
 
 	return &models.CGNATPoolStats{
-		Name:               poolName,
-		Mode:               ps.Config.GetMode(),
-		TotalAddresses:     uint32(len(ps.OutsideAddresses)),
-		AllocatedAddresses: allocatedBlocks,
-		FreeBlocks:         freeBlocks,
-		TotalBlocks:        totalBlocks,
-		ExcludedAddresses:  excludedAddrs,
-		SubscriberCount:    uint32(len(ps.Subscribers)),
-		Utilization:        utilization,
+		Name:                poolName,
+		Mode:                ps.Config.GetMode(),
+		TotalAddresses:      uint32(len(ps.OutsideAddresses)),
+		AllocatedAddresses:  allocatedBlocks,
+		FreeBlocks:          freeBlocks,
+		TotalBlocks:         totalBlocks,
+		ExcludedAddresses:   excludedAddrs,
+		SubscriberCount:     uint32(len(ps.Subscribers)),
+		Utilization:         utilization,
+		Level:               string(ps.currentLevel()),
+		LevelCode:           ps.currentLevel().Code(),
+		SessionLimitDrops:   ps.sessionLimitDrops,
+		PortExhaustionDrops: ps.portExhaustionDrops,
 	}
 }
 
@@ -411,6 +469,7 @@ func (pm *PoolManager) restoreLocked(mapping *models.CGNATMapping, ifAbsent bool
 			PoolName:  mapping.PoolName,
 			PoolID:    ps.ID,
 			SwIfIndex: mapping.SwIfIndex,
+			SessionID: mapping.SessionID,
 		}
 		ps.Subscribers[key] = sub
 	}
@@ -445,7 +504,7 @@ func (pm *PoolManager) GetOrAllocate(poolName string, insideIP net.IP, insideVRF
 		return &models.CGNATMapping{
 			PoolName:       poolName,
 			PoolID:         ps.ID,
-			InsideIP:       insideIP.To4(),
+			InsideIP:       subscriberIP(insideIP),
 			InsideVRFID:    insideVRF,
 			OutsideIP:      dup,
 			PortBlockStart: b.PortBlockStart,
@@ -477,7 +536,7 @@ func (pm *PoolManager) allocateLocked(ps *poolState, insideIP net.IP, insideVRF
 	if sub != nil && len(sub.Blocks) > 0 && ps.Config.GetAddressPooling() == "paired" {
 		existingIP := sub.Blocks[0].OutsideIP
 		for _, addr := range ps.OutsideAddresses {
-			if addr.IP.Equal(existingIP) && !addr.Excluded {
+			if addr.IP.Equal(existingIP) && addr.allocatable() {
 				targetAddr = addr
 				break
 			}
@@ -486,7 +545,7 @@ func (pm *PoolManager) allocateLocked(ps *poolState, insideIP net.IP, insideVRF
 
 	if targetAddr == nil {
 		for _, addr := range ps.OutsideAddresses {
-			if addr.Excluded {
+			if !addr.allocatable() {
 				continue
 			}
 			if hasFreeBlock(addr) {
@@ -528,7 +587,7 @@ func (pm *PoolManager) allocateLocked(ps *poolState, insideIP net.IP, insideVRF
 	return &models.CGNATMapping{
 		PoolName:       ps.Name,
 		PoolID:         ps.ID,
-		InsideIP:       insideIP.To4(),
+		InsideIP:       subscriberIP(insideIP),
 		InsideVRFID:    insideVRF,
 		OutsideIP:      block.OutsideIP,
 		PortBlockStart: portBlockStart,
@@ -540,13 +599,21 @@ func (pm *PoolManager) allocateLocked(ps *poolState, insideIP net.IP, insideVRF
 func makeSubscriberKey(vrf uint32, ip net.IP) subscriberKey {
 	var key subscriberKey
 	key.InsideVRF = vrf
-	ip4 := ip.To4()
-	if ip4 != nil {
-		copy(key.InsideIP[:], ip4)
+	if ip16 := ip.To16(); ip16 != nil {
+		copy(key.InsideIP[:], ip16)
 	}
 	return key
 }
 
+// subscriberIP returns the 4-byte form of an IPv4 subscriber address and
+// the 16-byte form of a DS-Lite B4 address.
+func subscriberIP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip.To16()
+}
+
 func expandCIDR(cidr string) ([]net.IP, error) {
 	ip := net.ParseIP(cidr)
 	if ip != nil {
@@ -9,7 +9,9 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
 	"github.com/veesix-networks/osvbng/pkg/models"
 )
 
@@ -153,3 +155,82 @@ func TestGetOrAllocate_ConcurrentSameSubscriber(t *testing.T) {
 		t.Fatalf("expected exactly 1 block allocated for subscriber after %d concurrent callers, got %d", N, len(mappings))
 	}
 }
+
+func TestPoolManager_CheckThresholds(t *testing.T) {
+	pm := NewPoolManager()
+	cfg := &cgnat.Pool{
+		Mode:                   "pba",
+		BlockSize:              64,
+		MaxBlocksPerSubscriber: 1,
+		PortRange:              "1024-1279",
+		OutsideAddresses:       []string{"100.64.0.1"},
+		Thresholds:             &ip.PoolThresholds{High: 75, Low: 25},
+	}
+	if err := pm.ConfigurePool("p1", 1, cfg); err != nil {
+		t.Fatalf("configure pool: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if _, err := pm.AllocateBlock("p1", net.IPv4(10, 0, 0, byte(i)).To4(), 0, 1); err != nil {
+			t.Fatalf("allocate %d: %v", i, err)
+		}
+	}
+	changes := pm.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != allocator.PoolLevelHigh ||
+		changes[0].Usage.Family != allocator.PoolFamilyCGNAT || changes[0].Usage.Size != 4 || changes[0].Usage.Available != 1 {
+		t.Fatalf("changes at 75%% = %+v", changes)
+	}
+
+	if _, err := pm.AllocateBlock("p1", net.IPv4(10, 0, 0, 4).To4(), 0, 1); err != nil {
+		t.Fatalf("allocate last: %v", err)
+	}
+	changes = pm.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != allocator.PoolLevelExhausted || changes[0].Previous != allocator.PoolLevelHigh {
+		t.Fatalf("changes when full = %+v", changes)
+	}
+	if s := pm.GetPoolStats("p1"); s.Level != string(allocator.PoolLevelExhausted) || s.LevelCode != 2 {
+		t.Fatalf("stats level = %q/%d", s.Level, s.LevelCode)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := pm.ReleaseBlocks("p1", net.IPv4(10, 0, 0, byte(i)).To4(), 0); err != nil {
+			t.Fatalf("release %d: %v", i, err)
+		}
+	}
+	changes = pm.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != allocator.PoolLevelNormal {
+		t.Fatalf("changes after release = %+v", changes)
+	}
+}
+
+func TestPoolManager_CheckThresholdsReportsDrained(t *testing.T) {
+	pm := NewPoolManager()
+	cfg := &cgnat.Pool{
+		Mode:                   "pba",
+		BlockSize:              64,
+		MaxBlocksPerSubscriber: 1,
+		PortRange:              "1024-1279",
+		OutsideAddresses:       []string{"100.64.0.1"},
+		Thresholds:             &ip.PoolThresholds{WithdrawRoute: true},
+	}
+	if err := pm.ConfigurePool("p1", 1, cfg); err != nil {
+		t.Fatalf("configure pool: %v", err)
+	}
+	if changes := pm.CheckThresholds(); len(changes) != 1 || !changes[0].Drained() {
+		t.Fatalf("first check of an empty pool = %+v", changes)
+	}
+
+	inside := net.IPv4(10, 0, 0, 1).To4()
+	if _, err := pm.AllocateBlock("p1", inside, 0, 1); err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	if changes := pm.CheckThresholds(); len(changes) != 1 || !changes[0].Refilled() {
+		t.Fatalf("first block = %+v", changes)
+	}
+	if err := pm.ReleaseBlocks("p1", inside, 0); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if changes := pm.CheckThresholds(); len(changes) != 1 || !changes[0].Drained() {
+		t.Fatalf("last block released = %+v", changes)
+	}
+}
@@ -80,7 +80,16 @@ func (c *Component) reconcile(ctx context.Context, cfg *config.Config) error {
 		poolIDMap: c.poolIDMap,
 		log:       c.logger,
 	}
-	return reconcileWith(ctx, deps, cfg)
+	if err := reconcileWith(ctx, deps, cfg); err != nil {
+		return err
+	}
+	if err := c.reconcileDSLite(cfg); err != nil {
+		return err
+	}
+	if err := c.reconcileNAT64(cfg); err != nil {
+		return err
+	}
+	return c.reconcileMAP(cfg)
 }
 
 func reconcileWith(ctx context.Context, deps reconcileDeps, cfg *config.Config) error {
@@ -88,7 +97,7 @@ func reconcileWith(ctx context.Context, deps reconcileDeps, cfg *config.Config)
 		return nil
 	}
 
-	desiredPools := cfg.CGNAT.Pools
+	desiredPools := natPools(cfg.CGNAT.Pools)
 	rc := cfg.CGNAT.Reconcile
 
 	actualPools, err := deps.dp.CGNATPoolDump()
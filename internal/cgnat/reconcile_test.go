@@ -44,6 +44,10 @@ type stubDP struct {
 	sessions          []southbound.CGNATSession
 	sessionCount      uint64
 	lastSessionFilter southbound.CGNATSessionFilter
+
+	mappings        []southbound.CGNATMapping
+	limitDrops      southbound.CGNATLimitDrops
+	noLimitCounters bool
 }
 
 type outsideIfaceCall struct {
@@ -192,7 +196,7 @@ func (s *stubDP) CGNATPoolOutsideInterfaceAddDel(poolID, swIfIndex uint32, isAdd
 }
 func (s *stubDP) CGNATAddDelBypass(prefix net.IPNet, vrfID uint32, isAdd bool) error { return nil }
 func (s *stubDP) CGNATDumpSubscriberMappings(poolID uint32) ([]southbound.CGNATMapping, error) {
-	return nil, nil
+	return s.mappings, nil
 }
 func (s *stubDP) CGNATPoolDump() ([]southbound.CGNATPoolState, error) {
 	return append([]southbound.CGNATPoolState(nil), s.pools...), nil
@@ -208,6 +212,12 @@ func (s *stubDP) CGNATDumpSessions(f southbound.CGNATSessionFilter) ([]southboun
 	return s.sessions, nil
 }
 func (s *stubDP) CGNATSessionCount() (uint64, error) { return s.sessionCount, nil }
+func (s *stubDP) GetCGNATLimitDrops() (southbound.CGNATLimitDrops, error) {
+	if s.noLimitCounters {
+		return southbound.CGNATLimitDrops{}, southbound.ErrCGNATLimitCountersUnavailable
+	}
+	return s.limitDrops, nil
+}
 
 func mustCIDR(s string) net.IPNet {
 	_, n, err := net.ParseCIDR(s)
@@ -143,8 +143,20 @@ func newRestoreComponent(t *testing.T, dp *fakeDP, opdbStore *fakeOpDB, sp Sessi
 		blacklist:       NewBlacklistManager(),
 		poolIDMap:       map[string]uint32{"p1": 1},
 		sessionPoolMap:  map[string]string{},
+		sessionIPv6:     map[string]net.IP{},
+		sessionNAT64If:  map[string]uint32{},
+		nat64Owners:     newNAT64Owners(),
+		nat64Outside:    map[uint32]bool{},
+		nat64BIB:        map[nat64BIBKey]*models.CGNATMapping{},
+		sessionMAPIf:    map[string]uint32{},
+		mapOutside:      map[uint32]bool{},
+		forwards:        map[string]*portForward{},
+		poolOutside:     map[string][]uint32{},
 		sessionProvider: sp,
 		activations:     map[string]struct{}{},
+		escalated:       map[string]net.IP{},
+		limits:          map[uint32]*subscriberLimitState{},
+		addrChanges:     map[string]*models.CGNATAddressChange{},
 	}
 }
 
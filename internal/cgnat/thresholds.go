@@ -0,0 +1,88 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"sort"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+)
+
+// blockCounts returns the pool's port blocks on outside addresses that
+// take new blocks, neither excluded nor draining, and how many of them
+// are allocated.
+func (ps *poolState) blockCounts() (total, allocated uint32) {
+	for _, addr := range ps.OutsideAddresses {
+		if !addr.allocatable() {
+			continue
+		}
+		total += addr.TotalBlocks
+		for _, word := range addr.AllocatedBits {
+			allocated += uint32(popcount(word))
+		}
+	}
+	if allocated > total {
+		allocated = total
+	}
+	return total, allocated
+}
+
+// currentLevel is the level of the last threshold check; a pool
+// without thresholds is only ever normal or exhausted.
+func (ps *poolState) currentLevel() allocator.PoolLevel {
+	if ps.Config.Thresholds != nil && ps.level != "" {
+		return ps.level
+	}
+	total, allocated := ps.blockCounts()
+	return allocator.NextLevel(nil, allocator.PoolLevelNormal, uint64(total), uint64(total-allocated))
+}
+
+// CheckThresholds re-evaluates the watermarks of every pool that has
+// them, counting port blocks, and returns the pools whose level
+// changed, and those with WithdrawRoute that were drained or refilled.
+func (pm *PoolManager) CheckThresholds() []allocator.PoolLevelChange {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	names := make([]string, 0, len(pm.pools))
+	for name, ps := range pm.pools {
+		if ps.Config.Thresholds != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []allocator.PoolLevelChange
+	for _, name := range names {
+		ps := pm.pools[name]
+		previous := ps.currentLevel()
+		total, allocated := ps.blockCounts()
+		next := allocator.NextLevel(ps.Config.Thresholds, previous, uint64(total), uint64(total-allocated))
+		wasEmpty, empty := ps.empty, total > 0 && allocated == 0
+		ps.level, ps.empty = next, empty
+		if next == previous && (!ps.Config.Thresholds.WithdrawRoute || wasEmpty == empty) {
+			continue
+		}
+		changes = append(changes, allocator.PoolLevelChange{
+			Usage: allocator.PoolUsage{
+				Family:    allocator.PoolFamilyCGNAT,
+				Pool:      name,
+				Size:      int(total),
+				Available: int(total - allocated),
+				Level:     next,
+			},
+			Previous:   previous,
+			WasEmpty:   wasEmpty,
+			Thresholds: ps.Config.Thresholds,
+		})
+	}
+	return changes
+}
+
+// CheckPoolThresholds re-evaluates the CGNAT pools' watermarks; the
+// monitor component calls it on its threshold cadence.
+func (c *Component) CheckPoolThresholds() []allocator.PoolLevelChange {
+	return c.pools.CheckThresholds()
+}
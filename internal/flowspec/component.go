@@ -0,0 +1,368 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package flowspec programs the BGP FlowSpec rules the routing daemon
+// installs: it reads them from FRR, translates each into ACL entries
+// and a deny, rate-limit or redirect action, and programs the rules of
+// each address family, in match order and up to its max-rules, on the
+// core-facing interfaces the family lists.
+package flowspec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// tick is how often FRR's FlowSpec rules are read.
+const tick = 5 * time.Second
+
+// Routing reads the FlowSpec rules the routing daemon installed.
+type Routing interface {
+	GetBGPFlowSpec(afi string) ([]byte, error)
+}
+
+// InterfaceResolver resolves interface names to dataplane indexes.
+type InterfaceResolver interface {
+	GetSwIfIndex(name string) (uint32, bool)
+}
+
+// Config wires the component.
+type Config struct {
+	ConfigManager component.ConfigManager
+	Routing       Routing
+	IfMgr         InterfaceResolver
+	Southbound    southbound.FlowSpec
+}
+
+// Component follows the FlowSpec address families of the running BGP
+// config.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+
+	mu sync.Mutex
+	// rules are the rules last read, per family in match order.
+	rules      []*rule
+	programmed map[string]*programmedInterface
+}
+
+// programmedInterface is what the dataplane was last given for an
+// interface.
+type programmedInterface struct {
+	swIfIndex uint32
+	found     bool
+	rules     []southbound.FlowSpecRule
+	err       string
+}
+
+// family is a configured FlowSpec address family. Its afi is also the
+// Family of its rules.
+type family struct {
+	afi  string
+	ipv6 bool
+	af   *protocols.BGPFlowSpecAddressFamily
+}
+
+func New(cfg Config) *Component {
+	return &Component{
+		Base:       component.NewBase("flowspec"),
+		logger:     logger.Get("flowspec"),
+		cfg:        cfg,
+		programmed: make(map[string]*programmedInterface),
+	}
+}
+
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting FlowSpec component")
+	c.Go(c.run)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping FlowSpec component")
+	c.StopContext()
+	return nil
+}
+
+func (c *Component) run() {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	c.sync()
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.sync()
+		}
+	}
+}
+
+func (c *Component) running() *config.Config {
+	if c.cfg.ConfigManager == nil {
+		return nil
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+func families(cfg *config.Config) []family {
+	if cfg == nil || cfg.Protocols.BGP == nil {
+		return nil
+	}
+	var out []family
+	if af := cfg.Protocols.BGP.IPv4FlowSpec; af != nil {
+		out = append(out, family{afi: "ipv4", af: af})
+	}
+	if af := cfg.Protocols.BGP.IPv6FlowSpec; af != nil {
+		out = append(out, family{afi: "ipv6", ipv6: true, af: af})
+	}
+	return out
+}
+
+// sync reads the rules of every configured family and programs them. If
+// FRR cannot be read, the rules programmed stay as they are.
+func (c *Component) sync() {
+	cfg := c.running()
+	vrfByRT := func(rt string) (string, bool) { return importingVRF(cfg, rt) }
+
+	var rules []*rule
+	want := map[string][]southbound.FlowSpecRule{}
+	ifaces := map[string][]string{}
+	for _, f := range families(cfg) {
+		data, err := c.cfg.Routing.GetBGPFlowSpec(f.afi)
+		if err != nil {
+			c.logger.Warn("Failed to read FlowSpec rules", "afi", f.afi, "error", err)
+			return
+		}
+		frs, err := parseFRR(data)
+		if err != nil {
+			c.logger.Warn("Failed to read FlowSpec rules", "afi", f.afi, "error", err)
+			return
+		}
+		rs := familyRules(frs, f, vrfByRT)
+
+		var sb []southbound.FlowSpecRule
+		for _, r := range rs {
+			if r.programmable() {
+				sb = append(sb, r.southbound())
+			}
+		}
+		for _, name := range f.af.Interfaces {
+			want[name] = append(want[name], sb...)
+		}
+		ifaces[f.afi] = f.af.Interfaces
+		rules = append(rules, rs...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconcileLocked(want)
+
+	counts := map[string]int{}
+	for _, r := range rules {
+		if r.programmable() {
+			r.Status = models.FlowSpecProgrammed
+			for _, name := range ifaces[r.Family] {
+				if st := c.programmed[name]; st != nil && st.err != "" {
+					r.Status, r.Reason = models.FlowSpecFailed, name+": "+st.err
+					break
+				}
+			}
+		}
+		counts[r.Status]++
+	}
+	if !sameRules(c.rules, rules) {
+		c.logger.Info("FlowSpec rules changed", "rules", len(rules),
+			"programmed", counts[models.FlowSpecProgrammed],
+			"unsupported", counts[models.FlowSpecUnsupported],
+			"over_limit", counts[models.FlowSpecOverLimit],
+			"failed", counts[models.FlowSpecFailed])
+	}
+	c.rules = rules
+}
+
+// familyRules translates and orders the rules of a family and marks the
+// programmable rules past its max-rules as over the limit.
+func familyRules(frs []frrRule, f family, vrfByRT func(string) (string, bool)) []*rule {
+	rs := make([]*rule, 0, len(frs))
+	for _, fr := range frs {
+		rs = append(rs, translate(fr, f.ipv6, vrfByRT))
+	}
+	sortRules(rs)
+
+	limit, n := f.af.GetMaxRules(), 0
+	for _, r := range rs {
+		if !r.programmable() {
+			continue
+		}
+		if n++; n > limit {
+			r.Status = models.FlowSpecOverLimit
+			r.Reason = fmt.Sprintf("max-rules %d reached", limit)
+		}
+	}
+	return rs
+}
+
+// reconcileLocked programs every interface whose rules changed, retries
+// those that failed, and clears interfaces no longer listed. Caller
+// holds mu.
+func (c *Component) reconcileLocked(want map[string][]southbound.FlowSpecRule) {
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rules := want[name]
+		prev := c.programmed[name]
+		sw, ok := c.cfg.IfMgr.GetSwIfIndex(name)
+		if !ok {
+			st := &programmedInterface{err: "interface not found in the dataplane"}
+			if prev == nil || prev.err != st.err {
+				c.logger.Warn("FlowSpec interface not found", "interface", name)
+			}
+			if prev != nil && prev.found {
+				c.clearLocked(name, prev)
+			}
+			c.programmed[name] = st
+			continue
+		}
+		if prev != nil && prev.found && prev.err == "" && prev.swIfIndex == sw && reflect.DeepEqual(prev.rules, rules) {
+			continue
+		}
+		if prev != nil && prev.found && prev.swIfIndex != sw {
+			c.clearLocked(name, prev)
+		}
+
+		st := &programmedInterface{swIfIndex: sw, found: true, rules: rules}
+		if err := c.cfg.Southbound.SetFlowSpecRules(sw, rules); err != nil {
+			st.err = err.Error()
+			if prev == nil || prev.err != st.err {
+				c.logger.Error("Failed to program FlowSpec rules", "interface", name, "error", err)
+			}
+		} else {
+			c.logger.Debug("FlowSpec rules programmed", "interface", name, "rules", len(rules))
+		}
+		c.programmed[name] = st
+	}
+
+	for name, prev := range c.programmed {
+		if _, ok := want[name]; ok {
+			continue
+		}
+		if prev.found && !c.clearLocked(name, prev) {
+			continue
+		}
+		delete(c.programmed, name)
+	}
+}
+
+func (c *Component) clearLocked(name string, prev *programmedInterface) bool {
+	if err := c.cfg.Southbound.SetFlowSpecRules(prev.swIfIndex, nil); err != nil {
+		c.logger.Error("Failed to clear FlowSpec rules", "interface", name, "error", err)
+		return false
+	}
+	c.logger.Debug("FlowSpec rules cleared", "interface", name)
+	return true
+}
+
+// Rules returns the rules last read from FRR, per family in match
+// order, with how each is programmed on each interface.
+func (c *Component) Rules() []models.FlowSpecRule {
+	c.mu.Lock()
+	rules := c.rules
+	names := map[uint32]string{}
+	for name, st := range c.programmed {
+		if st.found {
+			names[st.swIfIndex] = name
+		}
+	}
+	c.mu.Unlock()
+
+	byKey := map[string][]models.FlowSpecProgramming{}
+	if states, err := c.cfg.Southbound.DumpFlowSpec(); err != nil {
+		c.logger.Warn("Failed to dump FlowSpec programming", "error", err)
+	} else {
+		for _, s := range states {
+			name := names[s.SwIfIndex]
+			if name == "" {
+				name = s.InterfaceName
+			}
+			byKey[s.Key] = append(byKey[s.Key], models.FlowSpecProgramming{
+				Interface:   name,
+				Programming: s.Programming,
+				Packets:     s.Packets,
+				Bytes:       s.Bytes,
+				Drops:       s.Drops,
+			})
+		}
+	}
+
+	out := make([]models.FlowSpecRule, 0, len(rules))
+	for _, r := range rules {
+		mr := r.FlowSpecRule
+		if progs := byKey[r.Key]; len(progs) > 0 {
+			sort.Slice(progs, func(i, j int) bool { return progs[i].Interface < progs[j].Interface })
+			mr.Interfaces = progs
+		}
+		out = append(out, mr)
+	}
+	return out
+}
+
+// importingVRF returns the VRF that imports the route target, by name
+// order if several do.
+func importingVRF(cfg *config.Config, rt string) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+	names := make([]string, 0, len(cfg.VRFS))
+	for name := range cfg.VRFS {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := cfg.VRFS[name]
+		if v == nil {
+			continue
+		}
+		for _, t := range v.ImportRouteTargets {
+			if strings.TrimPrefix(t, "RT:") == rt {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func sameRules(a, b []*rule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].Status != b[i].Status || a[i].Action != b[i].Action {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,334 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package flowspec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeRouting struct {
+	rules map[string][]byte
+	err   error
+}
+
+func (f *fakeRouting) GetBGPFlowSpec(afi string) ([]byte, error) {
+	return f.rules[afi], f.err
+}
+
+type fakeIfMgr map[string]uint32
+
+func (f fakeIfMgr) GetSwIfIndex(name string) (uint32, bool) {
+	sw, ok := f[name]
+	return sw, ok
+}
+
+type fakeSB struct {
+	calls []string
+	rules map[uint32][]southbound.FlowSpecRule
+	err   error
+}
+
+func (f *fakeSB) SetFlowSpecRules(swIfIndex uint32, rules []southbound.FlowSpecRule) error {
+	keys := make([]string, 0, len(rules))
+	for _, r := range rules {
+		keys = append(keys, r.Action)
+	}
+	f.calls = append(f.calls, fmt.Sprintf("set %d [%s]", swIfIndex, strings.Join(keys, " ")))
+	if f.err != nil {
+		return f.err
+	}
+	if len(rules) == 0 {
+		delete(f.rules, swIfIndex)
+	} else {
+		f.rules[swIfIndex] = rules
+	}
+	return nil
+}
+
+func (f *fakeSB) DumpFlowSpec() ([]southbound.FlowSpecState, error) {
+	var out []southbound.FlowSpecState
+	for sw, rules := range f.rules {
+		for _, r := range rules {
+			out = append(out, southbound.FlowSpecState{SwIfIndex: sw, Key: r.Key, Action: r.Action, Programming: "acl", Packets: 7})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSB) take() []string {
+	out := f.calls
+	f.calls = nil
+	return out
+}
+
+func loadFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/ipv4.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestComponent(t *testing.T) (*Component, *fakeSB, *fakeRouting, *config.Config) {
+	cfg := &config.Config{
+		Protocols: protocols.ProtocolConfig{BGP: &protocols.BGPConfig{
+			IPv4FlowSpec: &protocols.BGPFlowSpecAddressFamily{Interfaces: []string{"eth1", "eth2"}},
+		}},
+		VRFS: map[string]*ip.VRFSConfig{
+			"scrubbed": {ImportRouteTargets: []string{"64500:100"}},
+		},
+	}
+	routing := &fakeRouting{rules: map[string][]byte{"ipv4": loadFixture(t)}}
+	sb := &fakeSB{rules: map[uint32][]southbound.FlowSpecRule{}}
+	c := New(Config{
+		ConfigManager: &fakeCfg{cfg: cfg},
+		Routing:       routing,
+		IfMgr:         fakeIfMgr{"eth1": 1, "eth2": 2},
+		Southbound:    sb,
+	})
+	return c, sb, routing, cfg
+}
+
+func expectCalls(t *testing.T, sb *fakeSB, want ...string) {
+	t.Helper()
+	got := sb.take()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestParseFRR(t *testing.T) {
+	rules, err := parseFRR(loadFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 7 {
+		t.Fatalf("got %d rules, want 7", len(rules))
+	}
+	if got := rules[5]; got.actions != "FS:rate 0.000000" || got.match["tcp"] != "=2" {
+		t.Errorf("second path leaked into rule: %+v", got)
+	}
+
+	for _, empty := range []string{"", "{}", "{}\n{}"} {
+		rules, err := parseFRR([]byte(empty))
+		if err != nil || len(rules) != 0 {
+			t.Errorf("parseFRR(%q) = %v, %v; want no rules", empty, rules, err)
+		}
+	}
+	if _, err := parseFRR([]byte("[{")); err == nil {
+		t.Error("truncated output parsed")
+	}
+}
+
+func TestFamilyRules(t *testing.T) {
+	frs, err := parseFRR(loadFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vrfByRT := func(rt string) (string, bool) { return "scrubbed", rt == "64500:100" }
+	f := family{afi: "ipv4", af: &protocols.BGPFlowSpecAddressFamily{MaxRules: 3}}
+
+	type want struct{ key, action, status string }
+	var got []want
+	rules := familyRules(frs, f, vrfByRT)
+	for _, r := range rules {
+		got = append(got, want{r.Key, r.Action, r.Status})
+	}
+	expected := []want{
+		{"ipv4 to 192.0.2.10/32 proto =6 dstp =80", "rate-limit", ""},
+		{"ipv4 to 192.0.2.20/32 port >=1024 <=2048", "rate-limit", models.FlowSpecUnsupported},
+		{"ipv4 to 192.0.2.30/32 proto =6 tcp =2", "deny", ""},
+		{"ipv4 to 192.0.2.40/32", "", models.FlowSpecNoAction},
+		{"ipv4 to 192.0.2.0/24 proto =17 dstp =53 =123", "deny", ""},
+		{"ipv4 to 198.51.100.7/32 pktlen >=1000 <=1500", "deny", models.FlowSpecUnsupported},
+		{"ipv4 to 198.51.100.0/24 from 203.0.113.0/24", "redirect", models.FlowSpecOverLimit},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("rules =\n%v\nwant\n%v", got, expected)
+	}
+
+	if r := rules[0]; r.RateBytes != 125000 || len(r.entries) != 1 || r.entries[0].DstFirst != 80 || r.entries[0].Protocol != 6 {
+		t.Errorf("rate limit = %+v", r)
+	}
+	if r := rules[2]; r.entries[0].TCPFlagsValue != 0x02 || r.entries[0].TCPFlagsMask != 0x02 {
+		t.Errorf("tcp flags = %+v", r.entries)
+	}
+	if r := rules[4]; len(r.entries) != 2 || r.entries[0].DstFirst != 53 || r.entries[1].DstFirst != 123 {
+		t.Errorf("udp deny = %+v", r.entries)
+	}
+	if r := rules[6]; r.VRF != "scrubbed" || r.entries[0].Source.String() != "203.0.113.0/24" {
+		t.Errorf("redirect = %+v", r)
+	}
+	if r := rules[1]; !strings.Contains(r.Reason, "port range") {
+		t.Errorf("port range rate limit reason = %q", r.Reason)
+	}
+}
+
+func TestTranslateActions(t *testing.T) {
+	noVRF := func(string) (string, bool) { return "", false }
+	for _, tc := range []struct {
+		actions, status, reason string
+	}{
+		{"FS:rate 10.000000 FS:redirect VRF RT:64500:1", models.FlowSpecUnsupported, "cannot be combined"},
+		{"FS:redirect VRF RT:64500:1", models.FlowSpecUnsupported, "no VRF imports route target 64500:1"},
+		{"FS:redirect IP 192.0.2.1", models.FlowSpecUnsupported, "next hop"},
+		{"FS:marking 46", models.FlowSpecUnsupported, "marking"},
+	} {
+		r := translate(frrRule{match: map[string]string{"to": "192.0.2.1/32"}, actions: tc.actions}, false, noVRF)
+		if r.Status != tc.status || !strings.Contains(r.Reason, tc.reason) {
+			t.Errorf("%q: status %q reason %q, want %q containing %q", tc.actions, r.Status, r.Reason, tc.status, tc.reason)
+		}
+	}
+}
+
+func TestParseNumeric(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []portRange
+		err  bool
+	}{
+		{in: "=17", want: []portRange{{17, 17}}},
+		{in: "=53 =123", want: []portRange{{53, 53}, {123, 123}}},
+		{in: ">=1024 <=2048", want: []portRange{{1024, 2048}}},
+		{in: ">1024&<2048", want: []portRange{{1025, 2047}}},
+		{in: "<=10 >=65530", want: []portRange{{0, 10}, {65530, 65535}}},
+		{in: "=80 or >=8000 and <=8080", want: []portRange{{80, 80}, {8000, 8080}}},
+		{in: "!=80", err: true},
+		{in: ">=10 and <=5", err: true},
+		{in: "=70000", err: true},
+		{in: "true", err: true},
+	} {
+		got, err := parseNumeric(tc.in, 65535)
+		if tc.err {
+			if err == nil {
+				t.Errorf("parseNumeric(%q) = %v, want error", tc.in, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseNumeric(%q) = %v, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestMatchEntries(t *testing.T) {
+	entries, err := matchEntries(map[string]string{"to": "2001:db8::/32", "port": "=443"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tcp and udp, each with 443 as the source and as the destination.
+	if len(entries) != 4 || !entries[0].IPv6 || entries[0].SrcFirst != 443 || entries[1].DstFirst != 443 || entries[2].Protocol != 17 {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	entries, err = matchEntries(map[string]string{"type": "=8", "code": "=0"}, false)
+	if err != nil || len(entries) != 1 || entries[0].Protocol != 1 || entries[0].SrcFirst != 8 || entries[0].DstLast != 0 {
+		t.Errorf("icmp entries = %+v, %v", entries, err)
+	}
+
+	for _, m := range []map[string]string{
+		{"to": "192.0.2.0/24", "dscp": "=46"},
+		{"proto": "=1", "dstp": "=53"},
+		{"port": "=53", "dstp": "=53"},
+		{"to": "2001:db8::/32"},
+		{"tcp": "!=2"},
+		{"tcp": "6"},
+		{"proto": ">=0"},
+	} {
+		if entries, err := matchEntries(m, false); err == nil {
+			t.Errorf("matchEntries(%v) = %+v, want error", m, entries)
+		}
+	}
+}
+
+func TestSyncProgramsInterfaces(t *testing.T) {
+	c, sb, routing, cfg := newTestComponent(t)
+
+	c.sync()
+	expectCalls(t, sb,
+		"set 1 [rate-limit deny deny redirect]",
+		"set 2 [rate-limit deny deny redirect]",
+	)
+
+	c.sync()
+	expectCalls(t, sb)
+
+	rules := c.Rules()
+	if len(rules) != 7 {
+		t.Fatalf("got %d rules, want 7", len(rules))
+	}
+	if r := rules[0]; r.Status != models.FlowSpecProgrammed || len(r.Interfaces) != 2 || r.Interfaces[0].Interface != "eth1" || r.Interfaces[1].Packets != 7 {
+		t.Errorf("rule = %+v", r)
+	}
+	if r := rules[3]; r.Status != models.FlowSpecNoAction || len(r.Interfaces) != 0 {
+		t.Errorf("no-action rule = %+v", r)
+	}
+
+	// A failed read leaves the rules programmed.
+	routing.err = errors.New("vtysh: connection refused")
+	c.sync()
+	expectCalls(t, sb)
+	routing.err = nil
+
+	cfg.Protocols.BGP.IPv4FlowSpec.MaxRules = 1
+	cfg.Protocols.BGP.IPv4FlowSpec.Interfaces = []string{"eth1"}
+	c.sync()
+	expectCalls(t, sb, "set 1 [rate-limit]", "set 2 []")
+
+	cfg.Protocols.BGP.IPv4FlowSpec = nil
+	c.sync()
+	expectCalls(t, sb, "set 1 []")
+	if len(c.Rules()) != 0 {
+		t.Error("rules kept after the family was removed")
+	}
+}
+
+func TestSyncRetriesFailures(t *testing.T) {
+	c, sb, _, _ := newTestComponent(t)
+	sb.err = errors.New("acl_add_replace: -1")
+
+	c.sync()
+	expectCalls(t, sb,
+		"set 1 [rate-limit deny deny redirect]",
+		"set 2 [rate-limit deny deny redirect]",
+	)
+	if r := c.Rules()[0]; r.Status != models.FlowSpecFailed || !strings.Contains(r.Reason, "eth1: acl_add_replace") {
+		t.Errorf("rule = %+v", r)
+	}
+
+	sb.err = nil
+	c.sync()
+	expectCalls(t, sb,
+		"set 1 [rate-limit deny deny redirect]",
+		"set 2 [rate-limit deny deny redirect]",
+	)
+	if r := c.Rules()[0]; r.Status != models.FlowSpecProgrammed {
+		t.Errorf("rule = %+v", r)
+	}
+}
@@ -0,0 +1,669 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package flowspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// matchOrder is the FlowSpec component types in type order (RFC 8955
+// section 4.2.2), by the short names FRR prints them under.
+var matchOrder = []string{
+	"to", "from", "proto", "port", "dstp", "srcp", "type", "code",
+	"tcp", "pktlen", "dscp", "pktfrag", "flwlbl",
+}
+
+// longNames maps the names FRR prints in its long format to the short
+// ones, so either is understood.
+var longNames = map[string]string{
+	"Destination Address": "to",
+	"Source Address":      "from",
+	"IP Protocol":         "proto",
+	"Port":                "port",
+	"Destination Port":    "dstp",
+	"Source Port":         "srcp",
+	"ICMP Type":           "type",
+	"ICMP Code":           "code",
+	"TCP Flags":           "tcp",
+	"Packet Length":       "pktlen",
+	"DSCP":                "dscp",
+	"Packet Fragment":     "pktfrag",
+	"Flow Label":          "flwlbl",
+}
+
+// unsupported are the components the dataplane's ACLs cannot match.
+var unsupported = map[string]string{
+	"pktlen":  "packet length",
+	"dscp":    "DSCP",
+	"pktfrag": "fragment",
+	"flwlbl":  "flow label",
+}
+
+const (
+	// maxEntries bounds the ACL entries one rule may expand to.
+	maxEntries = 64
+	// maxProtocols bounds the protocols one rule may match, since each
+	// is an entry of its own.
+	maxProtocols = 16
+)
+
+var (
+	rateRE        = regexp.MustCompile(`FS:rate\s+([0-9.eE+-]+)`)
+	redirectVRFRE = regexp.MustCompile(`FS:redirect VRF\s+(?:RT:)?(\S+)`)
+)
+
+// frrRule is one rule as FRR prints it: its match components, keyed by
+// short name, and its actions as extended communities.
+type frrRule struct {
+	match   map[string]string
+	actions string
+}
+
+// parseFRR reads the output of `show bgp <afi> flowspec detail json`: a
+// JSON array per rule, holding an object with the match components, one
+// with the actions under "ecomlist" and others ignored here, repeated
+// per path. Only the first path is used; anything that is not an array
+// holds no rules.
+func parseFRR(data []byte) ([]frrRule, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var out []frrRule
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("parse flowspec rules: %w", err)
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 || raw[0] != '[' {
+			continue
+		}
+		var objs []map[string]any
+		if err := json.Unmarshal(raw, &objs); err != nil {
+			return nil, fmt.Errorf("parse flowspec rule: %w", err)
+		}
+
+		var r frrRule
+		for _, o := range objs {
+			if ecom, ok := o["ecomlist"].(string); ok {
+				if r.actions == "" {
+					r.actions = ecom
+				}
+				continue
+			}
+			m := matchOf(o)
+			if len(m) == 0 {
+				continue
+			}
+			if r.match != nil {
+				break
+			}
+			r.match = m
+		}
+		if r.match != nil {
+			out = append(out, r)
+		}
+	}
+}
+
+func matchOf(o map[string]any) map[string]string {
+	m := map[string]string{}
+	for k, v := range o {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if short, ok := longNames[k]; ok {
+			k = short
+		}
+		if k == "flowlabel" {
+			k = "flwlbl"
+		}
+		for _, name := range matchOrder {
+			if k == name {
+				m[k] = strings.TrimSpace(s)
+				break
+			}
+		}
+	}
+	return m
+}
+
+// rule is a FlowSpec rule with its translation for the dataplane.
+type rule struct {
+	models.FlowSpecRule
+	to, from netip.Prefix
+	entries  []aclcfg.Entry
+}
+
+// programmable reports whether the rule is to be programmed, limits and
+// dataplane errors aside.
+func (r *rule) programmable() bool {
+	return r.Action != "" && r.Status == ""
+}
+
+func (r *rule) southbound() southbound.FlowSpecRule {
+	return southbound.FlowSpecRule{
+		Key:       r.Key,
+		Action:    r.Action,
+		Entries:   r.entries,
+		RateBytes: r.RateBytes,
+		VRF:       r.VRF,
+	}
+}
+
+// translate turns an FRR rule into what the dataplane programs. A rule
+// that cannot be programmed has its status and reason set. vrfByRT
+// finds the VRF importing a route target.
+func translate(fr frrRule, ipv6 bool, vrfByRT func(rt string) (string, bool)) *rule {
+	family := aclcfg.FamilyIPv4
+	if ipv6 {
+		family = aclcfg.FamilyIPv6
+	}
+	r := &rule{FlowSpecRule: models.FlowSpecRule{
+		Key:     ruleKey(family, fr.match),
+		Family:  family,
+		Match:   fr.match,
+		Actions: fr.actions,
+	}}
+	r.to, _ = netip.ParsePrefix(fr.match["to"])
+	r.from, _ = netip.ParsePrefix(fr.match["from"])
+
+	if reason := r.setAction(fr.actions, vrfByRT); reason != "" {
+		r.Status, r.Reason = models.FlowSpecUnsupported, reason
+		return r
+	}
+	if r.Action == "" {
+		r.Status = models.FlowSpecNoAction
+		return r
+	}
+
+	entries, err := matchEntries(fr.match, ipv6)
+	if err != nil {
+		r.Status, r.Reason = models.FlowSpecUnsupported, err.Error()
+		return r
+	}
+	if r.Action == southbound.FlowSpecRateLimit {
+		for _, e := range entries {
+			e.Action = aclcfg.ActionPermit
+			if err := qos.ClassifiableEntry(e); err != nil {
+				r.Status, r.Reason = models.FlowSpecUnsupported, "rate limit: "+err.Error()
+				return r
+			}
+		}
+	}
+	r.entries = entries
+	r.Entries = len(entries)
+	return r
+}
+
+// setAction sets the rule's action from its extended communities and
+// returns why it cannot be programmed, if it cannot. A rate of zero
+// drops the traffic.
+func (r *rule) setAction(actions string, vrfByRT func(string) (string, bool)) string {
+	var rate float64
+	hasRate := false
+	if m := rateRE.FindStringSubmatch(actions); m != nil {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return fmt.Sprintf("rate %q is not a number", m[1])
+		}
+		rate, hasRate = v, true
+	}
+	var rt string
+	if m := redirectVRFRE.FindStringSubmatch(actions); m != nil {
+		rt = m[1]
+	}
+
+	switch {
+	case hasRate && rate <= 0:
+		r.Action = southbound.FlowSpecDeny
+	case hasRate && rt != "":
+		return "rate limit and redirect cannot be combined"
+	case hasRate:
+		r.Action = southbound.FlowSpecRateLimit
+		r.RateBytes = uint64(rate)
+	case rt != "":
+		vrf, ok := vrfByRT(rt)
+		if !ok {
+			return fmt.Sprintf("no VRF imports route target %s", rt)
+		}
+		r.Action, r.VRF = southbound.FlowSpecRedirect, vrf
+	case strings.Contains(actions, "FS:redirect IP"):
+		return "redirect to a next hop is not supported"
+	case strings.Contains(actions, "FS:marking"):
+		return "DSCP marking is not supported"
+	}
+	return ""
+}
+
+// ruleKey names a rule by its family and components in type order.
+func ruleKey(family string, match map[string]string) string {
+	parts := []string{family}
+	for _, name := range matchOrder {
+		if v, ok := match[name]; ok {
+			parts = append(parts, name+" "+v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+type portRange [2]uint64
+
+// matchEntries expands a rule's components into ACL entries: one per
+// protocol and per port or ICMP range combination.
+func matchEntries(match map[string]string, ipv6 bool) ([]aclcfg.Entry, error) {
+	for _, name := range matchOrder {
+		if what, ok := unsupported[name]; ok && match[name] != "" {
+			return nil, fmt.Errorf("%s cannot be matched", what)
+		}
+	}
+
+	base := aclcfg.Entry{IPv6: ipv6, SrcLast: 65535, DstLast: 65535}
+	var err error
+	if base.Destination, err = prefixOf(match["to"], ipv6); err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	if base.Source, err = prefixOf(match["from"], ipv6); err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+
+	var protos []uint64
+	if s := match["proto"]; s != "" {
+		ranges, err := parseNumeric(s, 255)
+		if err != nil {
+			return nil, fmt.Errorf("proto: %w", err)
+		}
+		if protos, err = values(ranges, maxProtocols); err != nil {
+			return nil, fmt.Errorf("proto: %w", err)
+		}
+	}
+
+	// Each pair is a source and a destination range; for ICMP the type
+	// and the code.
+	pairs := [][2]portRange{{}}
+	var l4 []uint64
+	switch {
+	case match["type"] != "" || match["code"] != "":
+		if match["port"] != "" || match["srcp"] != "" || match["dstp"] != "" {
+			return nil, fmt.Errorf("ports and ICMP cannot be combined")
+		}
+		icmp := uint64(1)
+		if ipv6 {
+			icmp = 58
+		}
+		l4 = []uint64{icmp}
+		types, err := rangesOr(match["type"], 255)
+		if err != nil {
+			return nil, fmt.Errorf("type: %w", err)
+		}
+		codes, err := rangesOr(match["code"], 255)
+		if err != nil {
+			return nil, fmt.Errorf("code: %w", err)
+		}
+		pairs = product(types, codes)
+	case match["port"] != "":
+		if match["srcp"] != "" || match["dstp"] != "" {
+			return nil, fmt.Errorf("port cannot be combined with srcp or dstp")
+		}
+		ranges, err := parseNumeric(match["port"], 65535)
+		if err != nil {
+			return nil, fmt.Errorf("port: %w", err)
+		}
+		l4 = []uint64{6, 17}
+		pairs = nil
+		for _, pr := range ranges {
+			pairs = append(pairs, [2]portRange{pr, {0, 65535}}, [2]portRange{{0, 65535}, pr})
+		}
+	case match["srcp"] != "" || match["dstp"] != "":
+		l4 = []uint64{6, 17}
+		srcs, err := rangesOr(match["srcp"], 65535)
+		if err != nil {
+			return nil, fmt.Errorf("srcp: %w", err)
+		}
+		dsts, err := rangesOr(match["dstp"], 65535)
+		if err != nil {
+			return nil, fmt.Errorf("dstp: %w", err)
+		}
+		pairs = product(srcs, dsts)
+	}
+
+	var flags, mask uint8
+	if s := match["tcp"]; s != "" {
+		if flags, err = parseTCPFlags(s); err != nil {
+			return nil, fmt.Errorf("tcp: %w", err)
+		}
+		mask = flags
+		if l4 != nil && l4[0] != 6 {
+			return nil, fmt.Errorf("tcp flags and ICMP cannot be combined")
+		}
+		l4 = []uint64{6}
+	}
+
+	switch {
+	case protos == nil:
+		protos = l4
+	case l4 != nil:
+		for _, p := range protos {
+			if !contains(l4, p) {
+				return nil, fmt.Errorf("proto %d cannot match the rule's ports, ICMP or tcp flags", p)
+			}
+		}
+	}
+	if protos == nil {
+		protos = []uint64{0}
+	}
+
+	var out []aclcfg.Entry
+	for _, p := range protos {
+		for _, pair := range pairs {
+			e := base
+			e.Protocol = uint8(p)
+			if l4 != nil {
+				e.SrcFirst, e.SrcLast = uint16(pair[0][0]), uint16(pair[0][1])
+				e.DstFirst, e.DstLast = uint16(pair[1][0]), uint16(pair[1][1])
+			}
+			if p == 6 {
+				e.TCPFlagsValue, e.TCPFlagsMask = flags, mask
+			}
+			out = append(out, e)
+			if len(out) > maxEntries {
+				return nil, fmt.Errorf("expands to more than %d ACL entries", maxEntries)
+			}
+		}
+	}
+	return out, nil
+}
+
+func prefixOf(s string, ipv6 bool) (net.IPNet, error) {
+	bits := 32
+	if ipv6 {
+		bits = 128
+	}
+	if s == "" {
+		if ipv6 {
+			return net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, bits)}, nil
+		}
+		return net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, bits)}, nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return net.IPNet{}, fmt.Errorf("%q is not a prefix", s)
+	}
+	if p.Addr().Is6() != ipv6 {
+		return net.IPNet{}, fmt.Errorf("%q is not of the rule's family", s)
+	}
+	p = p.Masked()
+	return net.IPNet{IP: p.Addr().AsSlice(), Mask: net.CIDRMask(p.Bits(), bits)}, nil
+}
+
+// rangesOr parses a numeric component, or returns the full range if the
+// rule has none.
+func rangesOr(s string, maxVal uint64) ([]portRange, error) {
+	if s == "" {
+		return []portRange{{0, maxVal}}, nil
+	}
+	return parseNumeric(s, maxVal)
+}
+
+func product(a, b []portRange) [][2]portRange {
+	var out [][2]portRange
+	for _, x := range a {
+		for _, y := range b {
+			out = append(out, [2]portRange{x, y})
+		}
+	}
+	return out
+}
+
+func values(ranges []portRange, limit int) ([]uint64, error) {
+	var out []uint64
+	for _, r := range ranges {
+		for v := r[0]; v <= r[1]; v++ {
+			if len(out) == limit {
+				return nil, fmt.Errorf("matches more than %d values", limit)
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func contains(vs []uint64, v uint64) bool {
+	for _, x := range vs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+type numericTerm struct {
+	op     string
+	value  uint64
+	and    bool
+	joined bool
+}
+
+// parseNumeric parses a numeric component as FRR prints it, such as
+// "=17", ">=1024 <=2048" or "=53 =123", into the inclusive ranges it
+// matches. Terms joined by "&" or "and" must all hold; so must a lower
+// bound followed directly by an upper bound, as FRR prints the range
+// of a single operator pair. Other terms are alternatives.
+func parseNumeric(s string, maxVal uint64) ([]portRange, error) {
+	var terms []numericTerm
+	and, joined := false, false
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == ',':
+			i++
+		case c == '&':
+			and, joined = true, true
+			i++
+		case c == '|':
+			joined = true
+			i++
+		case strings.HasPrefix(s[i:], "and"):
+			and, joined = true, true
+			i += 3
+		case strings.HasPrefix(s[i:], "or"):
+			joined = true
+			i += 2
+		case strings.ContainsRune("<>=!", rune(c)) || (c >= '0' && c <= '9'):
+			j := i
+			for j < len(s) && strings.ContainsRune("<>=!", rune(s[j])) {
+				j++
+			}
+			op := s[i:j]
+			if op == "" {
+				op = "="
+			}
+			for j < len(s) && s[j] == ' ' {
+				j++
+			}
+			k := j
+			for k < len(s) && s[k] >= '0' && s[k] <= '9' {
+				k++
+			}
+			if k == j {
+				return nil, fmt.Errorf("%q: operator %q has no value", s, op)
+			}
+			v, err := strconv.ParseUint(s[j:k], 10, 64)
+			if err != nil || v > maxVal {
+				return nil, fmt.Errorf("%q: %s is out of range", s, s[j:k])
+			}
+			terms = append(terms, numericTerm{op: op, value: v, and: and, joined: joined})
+			and, joined = false, false
+			i = k
+		default:
+			return nil, fmt.Errorf("%q cannot be parsed", s)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%q has no values", s)
+	}
+
+	var out []portRange
+	for idx, t := range terms {
+		lo, hi, err := bounds(t.op, t.value, maxVal)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", s, err)
+		}
+		and := t.and
+		if !t.joined && idx > 0 && isLower(terms[idx-1].op) && isUpper(t.op) {
+			and = true
+		}
+		if and && len(out) > 0 {
+			last := &out[len(out)-1]
+			last[0], last[1] = max(last[0], lo), min(last[1], hi)
+			continue
+		}
+		out = append(out, portRange{lo, hi})
+	}
+
+	ranges := out[:0]
+	for _, r := range out {
+		if r[0] <= r[1] {
+			ranges = append(ranges, r)
+		}
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("%q matches no value", s)
+	}
+	return ranges, nil
+}
+
+func bounds(op string, v, maxVal uint64) (uint64, uint64, error) {
+	switch op {
+	case "=", "==":
+		return v, v, nil
+	case ">":
+		return v + 1, maxVal, nil
+	case ">=", "=>":
+		return v, maxVal, nil
+	case "<":
+		if v == 0 {
+			return 1, 0, nil
+		}
+		return 0, v - 1, nil
+	case "<=", "=<":
+		return 0, v, nil
+	case "!=", "<>", "!":
+		return 0, 0, fmt.Errorf("not-equal cannot be matched")
+	}
+	return 0, 0, fmt.Errorf("unknown operator %q", op)
+}
+
+func isLower(op string) bool { return op == ">" || op == ">=" || op == "=>" }
+
+func isUpper(op string) bool { return op == "<" || op == "<=" || op == "=<" }
+
+// parseTCPFlags parses a TCP flags component into the flags that must be
+// set. A "=" term requires all its bits, a bare one a single bit; terms
+// joined by "&" or "and" combine. Flags that must be clear and
+// alternatives cannot be matched.
+func parseTCPFlags(s string) (uint8, error) {
+	if strings.Contains(s, "|") || strings.Contains(s, "or") {
+		return 0, fmt.Errorf("%q: alternatives cannot be matched", s)
+	}
+	joined := strings.Contains(s, "&") || strings.Contains(s, "and")
+	var terms []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == '&' }) {
+		if f != "and" {
+			terms = append(terms, f)
+		}
+	}
+	if len(terms) > 1 && !joined {
+		return 0, fmt.Errorf("%q: alternatives cannot be matched", s)
+	}
+
+	var flags uint8
+	for _, f := range terms {
+		if strings.HasPrefix(f, "!") {
+			return 0, fmt.Errorf("%q: flags that must be clear cannot be matched", s)
+		}
+		exact := strings.HasPrefix(f, "=")
+		v, err := strconv.ParseUint(strings.TrimPrefix(f, "="), 0, 8)
+		if err != nil || v == 0 {
+			return 0, fmt.Errorf("%q cannot be parsed", s)
+		}
+		if !exact && v&(v-1) != 0 {
+			return 0, fmt.Errorf("%q: matching any of several flags is not supported", s)
+		}
+		flags |= uint8(v)
+	}
+	if flags == 0 {
+		return 0, fmt.Errorf("%q has no flags", s)
+	}
+	return flags, nil
+}
+
+// sortRules puts the rules of one family in the order RFC 8955 section
+// 5.1 matches them: component by component in type order, a rule with
+// a component ahead of one without it, overlapping prefixes longest
+// first and other prefixes lowest first. Other components, which the
+// RFC compares by their encoding, are compared as FRR prints them.
+func sortRules(rules []*rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return compareRules(rules[i], rules[j]) < 0
+	})
+}
+
+func compareRules(a, b *rule) int {
+	for _, name := range matchOrder {
+		va, okA := a.Match[name]
+		vb, okB := b.Match[name]
+		switch {
+		case !okA && !okB:
+			continue
+		case !okB:
+			return -1
+		case !okA:
+			return 1
+		}
+		if name == "to" || name == "from" {
+			pa, pb := a.to, b.to
+			if name == "from" {
+				pa, pb = a.from, b.from
+			}
+			if pa.IsValid() && pb.IsValid() {
+				if c := comparePrefixes(pa, pb); c != 0 {
+					return c
+				}
+				continue
+			}
+		}
+		if c := strings.Compare(va, vb); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.Key, b.Key)
+}
+
+func comparePrefixes(a, b netip.Prefix) int {
+	a, b = a.Masked(), b.Masked()
+	if a.Overlaps(b) && a.Bits() != b.Bits() {
+		if a.Bits() > b.Bits() {
+			return -1
+		}
+		return 1
+	}
+	return a.Addr().Compare(b.Addr())
+}
@@ -0,0 +1,363 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	ipamprovider "github.com/veesix-networks/osvbng/pkg/ipam"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+const (
+	// checkInterval matches the pool watermark checks.
+	checkInterval = 10 * time.Second
+	// requestTimeout bounds each call to the IPAM or the HA peer.
+	requestTimeout = 30 * time.Second
+)
+
+// demand is one on-demand block of a profile.
+type demand struct {
+	family  string
+	profile string
+	od      *ip.OnDemandPools
+}
+
+// demands returns the running config's on-demand blocks in a stable
+// order.
+func demands(cfg *config.Config) []demand {
+	var out []demand
+	for name, profile := range cfg.IPv4Profiles {
+		if profile != nil && profile.OnDemand != nil {
+			out = append(out, demand{models.IPAMChunkIPv4, name, profile.OnDemand})
+		}
+	}
+	for name, profile := range cfg.IPv6Profiles {
+		if profile == nil {
+			continue
+		}
+		if profile.IANAOnDemand != nil {
+			out = append(out, demand{models.IPAMChunkIANA, name, profile.IANAOnDemand})
+		}
+		if profile.PDOnDemand != nil {
+			out = append(out, demand{models.IPAMChunkPD, name, profile.PDOnDemand})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].profile != out[j].profile {
+			return out[i].profile < out[j].profile
+		}
+		return out[i].family < out[j].family
+	})
+	return out
+}
+
+func findDemand(cfg *config.Config, family, profile string) (demand, bool) {
+	for _, d := range demands(cfg) {
+		if d.family == family && d.profile == profile {
+			return d, true
+		}
+	}
+	return demand{}, false
+}
+
+func (c *Component) watchProfiles() {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	c.check()
+	for {
+		select {
+		case <-ticker.C:
+			c.check()
+		case <-c.Ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Component) check() {
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil || cfg == nil {
+		return
+	}
+	for _, d := range demands(cfg) {
+		c.grow(cfg, d)
+	}
+	c.shrink(cfg, time.Now())
+}
+
+// grow requests a chunk for d once its profile's pools in the VRF reach
+// the threshold, or straight away if it has none.
+func (c *Component) grow(cfg *config.Config, d demand) {
+	family := allocator.PoolFamily(d.family)
+	size, available := c.cfg.Registry.ProfileUsage(family, d.profile, d.od.VRF)
+	if size > 0 && (size-available)*100 < int(d.od.GetThreshold())*size {
+		return
+	}
+	if c.ownedCount(d.family, d.profile) >= d.od.GetMaxChunks() {
+		return
+	}
+	if !c.mayRequest(cfg, d) {
+		return
+	}
+
+	provider := c.cfg.Providers[d.od.GetProvider()]
+	if provider == nil {
+		c.logger.Warn("No IPAM provider for on-demand pools", "provider", d.od.GetProvider(), "profile", d.profile)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Ctx, requestTimeout)
+	defer cancel()
+
+	req := c.chunkRequest(d.family, d.profile, d.od.VRF, d.od.ChunkLength)
+	got, err := provider.Allocate(ctx, req)
+	if err != nil {
+		c.logger.Error("IPAM chunk request failed", "family", d.family, "profile", d.profile, "error", err)
+		return
+	}
+
+	chunk := &models.IPAMChunk{
+		Family:      d.family,
+		Profile:     d.profile,
+		Pool:        models.IPAMChunkPoolName(got.Prefix),
+		Prefix:      got.Prefix,
+		VRF:         d.od.VRF,
+		Owner:       c.nodeID,
+		IPAMID:      got.ID,
+		AllocatedAt: time.Now(),
+	}
+	err = c.checkHeld(chunk)
+	if err == nil {
+		err = c.install(cfg, chunk)
+	}
+	if err != nil {
+		c.logger.Error("Failed to add IPAM chunk", "prefix", chunk.Prefix, "profile", d.profile, "error", err)
+		if err := provider.Release(ctx, req, got); err != nil {
+			c.logger.Error("Failed to return unusable IPAM chunk", "prefix", chunk.Prefix, "error", err)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	c.chunks[chunk.Key()] = &chunkState{chunk: chunk}
+	c.mu.Unlock()
+	c.persist(ctx, chunk)
+
+	if c.cfg.Replicator != nil {
+		if err := c.cfg.Replicator.ReplicateIPAMChunk(ctx, true, chunk); err != nil {
+			c.logger.Warn("Failed to replicate IPAM chunk to peer", "prefix", chunk.Prefix, "error", err)
+		}
+	}
+
+	c.logger.Info("Added IPAM chunk", "family", d.family, "profile", d.profile, "prefix", chunk.Prefix,
+		"vrf", chunk.VRF, "size", size, "available", available)
+	c.publish(events.IPAMChunkAdded, chunk)
+}
+
+// mayRequest holds back the standby: under HA only a node active for a
+// subscriber group using the profile requests chunks for it.
+func (c *Component) mayRequest(cfg *config.Config, d demand) bool {
+	if c.cfg.SRG == nil || cfg.SubscriberGroups == nil {
+		return true
+	}
+	for name, group := range cfg.SubscriberGroups.Groups {
+		if group == nil {
+			continue
+		}
+		profile := group.IPv6Profile
+		if d.family == models.IPAMChunkIPv4 {
+			profile = group.IPv4Profile
+		}
+		if profile != d.profile {
+			continue
+		}
+		srg := c.cfg.SRG.GetSRGForGroup(name)
+		if srg == "" || c.cfg.SRG.IsActive(srg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHeld refuses a chunk overlapping another chunk held in its VRF,
+// including one already out of the allocator but not yet returned.
+func (c *Component) checkHeld(chunk *models.IPAMChunk) error {
+	prefix, err := netip.ParsePrefix(chunk.Prefix)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, st := range c.chunks {
+		if key == chunk.Key() || st.chunk.VRF != chunk.VRF {
+			continue
+		}
+		other, err := netip.ParsePrefix(st.chunk.Prefix)
+		if err == nil && other.Overlaps(prefix) {
+			return fmt.Errorf("%w: %s overlaps held chunk %s", allocator.ErrPoolOverlap, prefix, other)
+		}
+	}
+	return nil
+}
+
+func (c *Component) ownedCount(family, profile string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int
+	for _, st := range c.chunks {
+		if st.chunk.Owner == c.nodeID && st.chunk.Family == family && st.chunk.Profile == profile {
+			n++
+		}
+	}
+	return n
+}
+
+// shrink returns owned chunks that have been empty for the release
+// grace, keeping any whose removal would put the profile straight back
+// over its threshold. Chunks whose on-demand block has gone from the
+// config are returned as soon as they are empty.
+func (c *Component) shrink(cfg *config.Config, now time.Time) {
+	usage := make(map[string]allocator.PoolUsage)
+	for _, u := range c.cfg.Registry.PoolUsages() {
+		usage[string(u.Family)+"/"+u.Profile+"/"+u.Pool] = u
+	}
+
+	var due []*chunkState
+	c.mu.Lock()
+	for key, st := range c.chunks {
+		if st.chunk.Owner != c.nodeID {
+			continue
+		}
+		if st.returned {
+			due = append(due, st)
+			continue
+		}
+		d, configured := findDemand(cfg, st.chunk.Family, st.chunk.Profile)
+		u, installed := usage[key]
+		if installed && u.Available < u.Size {
+			st.emptySince = time.Time{}
+			continue
+		}
+		if !configured {
+			due = append(due, st)
+			continue
+		}
+		if st.emptySince.IsZero() {
+			st.emptySince = now
+			continue
+		}
+		if now.Sub(st.emptySince) < d.od.GetReleaseGrace() {
+			continue
+		}
+		size, available := c.cfg.Registry.ProfileUsage(allocator.PoolFamily(d.family), d.profile, d.od.VRF)
+		size -= u.Size
+		available -= u.Available
+		if (size-available)*100 >= int(d.od.GetThreshold())*size {
+			continue
+		}
+		due = append(due, st)
+	}
+	c.mu.Unlock()
+
+	for _, st := range due {
+		c.release(cfg, st)
+	}
+}
+
+// release gives an owned chunk back. The peer drops it first, so a
+// chunk still holding leases on either node stays where it is.
+func (c *Component) release(cfg *config.Config, st *chunkState) {
+	chunk := st.chunk
+	ctx, cancel := context.WithTimeout(c.Ctx, requestTimeout)
+	defer cancel()
+
+	if !st.returned {
+		if c.cfg.Replicator != nil {
+			err := c.cfg.Replicator.ReplicateIPAMChunk(ctx, false, chunk)
+			if errors.Is(err, ha.ErrPeerChunkInUse) {
+				c.resetEmpty(st)
+				return
+			}
+			if err != nil {
+				c.logger.Warn("Failed to withdraw IPAM chunk from peer", "prefix", chunk.Prefix, "error", err)
+			}
+		}
+
+		if err := c.uninstall(cfg, chunk); err != nil {
+			if errors.Is(err, allocator.ErrPoolInUse) {
+				c.resetEmpty(st)
+				if c.cfg.Replicator != nil {
+					c.cfg.Replicator.ReplicateIPAMChunk(ctx, true, chunk)
+				}
+				return
+			}
+			c.logger.Error("Failed to remove IPAM chunk", "prefix", chunk.Prefix, "error", err)
+			return
+		}
+		c.mu.Lock()
+		st.returned = true
+		c.mu.Unlock()
+	}
+
+	provider := c.cfg.Providers[chunkProvider(cfg, chunk)]
+	if provider == nil {
+		c.logger.Warn("No IPAM provider to return chunk to", "prefix", chunk.Prefix)
+		return
+	}
+	req := c.chunkRequest(chunk.Family, chunk.Profile, chunk.VRF, chunkLength(chunk))
+	if err := provider.Release(ctx, req, &ipamprovider.Chunk{Prefix: chunk.Prefix, ID: chunk.IPAMID}); err != nil {
+		c.logger.Error("IPAM chunk release failed; will retry", "prefix", chunk.Prefix, "error", err)
+		return
+	}
+
+	c.forget(ctx, chunk)
+	c.logger.Info("Returned IPAM chunk", "family", chunk.Family, "profile", chunk.Profile, "prefix", chunk.Prefix)
+	c.publish(events.IPAMChunkReleased, chunk)
+}
+
+func (c *Component) resetEmpty(st *chunkState) {
+	c.mu.Lock()
+	st.emptySince = time.Time{}
+	c.mu.Unlock()
+}
+
+func (c *Component) chunkRequest(family, profile, vrf string, length uint8) *ipamprovider.ChunkRequest {
+	return &ipamprovider.ChunkRequest{
+		Family:       family,
+		Profile:      profile,
+		VRF:          vrf,
+		PrefixLength: length,
+		DeviceID:     c.nodeID,
+	}
+}
+
+// chunkProvider is the provider a chunk came from: its on-demand
+// block's, or the default once the block has gone.
+func chunkProvider(cfg *config.Config, chunk *models.IPAMChunk) string {
+	d, _ := findDemand(cfg, chunk.Family, chunk.Profile)
+	return d.od.GetProvider()
+}
+
+func chunkLength(chunk *models.IPAMChunk) uint8 {
+	prefix, err := netip.ParsePrefix(chunk.Prefix)
+	if err != nil {
+		return 0
+	}
+	return uint8(prefix.Bits())
+}
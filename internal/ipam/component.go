@@ -0,0 +1,318 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	ipamprovider "github.com/veesix-networks/osvbng/pkg/ipam"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+)
+
+// opdbNamespace holds every chunk this node has installed, owned or
+// adopted from the HA peer, keyed by IPAMChunk.Key.
+const opdbNamespace = "ipam_chunks"
+
+// Registry is the part of the allocator registry chunks are added to.
+type Registry interface {
+	AddIPv4Pool(profileName string, pool ip.IPv4Pool, gateway string) error
+	AddIANAPool(profileName string, pool ip.IANAPool) error
+	AddPDPool(profileName string, pool ip.PDPool) error
+	RemovePool(family allocator.PoolFamily, key string) error
+	ProfileUsage(family allocator.PoolFamily, profileName, vrf string) (size, available int)
+	PoolUsages() []allocator.PoolUsage
+}
+
+// BGPNetworkController originates a chunk and takes it back out.
+type BGPNetworkController interface {
+	AdvertiseBGPNetworkPolicy(asn uint32, vrf string, prefix string, routePolicy string, ipv6 bool) error
+	RemoveBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error
+}
+
+// SRGProvider tells whether this node is active for a subscriber
+// group's SRG. Only the active node requests chunks for a profile.
+type SRGProvider interface {
+	IsActive(srgName string) bool
+	GetSRGForGroup(subscriberGroup string) string
+}
+
+// Replicator sends this node's chunks to the HA peer.
+type Replicator interface {
+	ReplicateIPAMChunk(ctx context.Context, add bool, chunk *models.IPAMChunk) error
+}
+
+// Config wires the component. Routing, SRG and Replicator are
+// optional: without Routing chunks are not advertised, and without SRG
+// and Replicator the node is treated as standalone.
+type Config struct {
+	EventBus      events.Bus
+	ConfigManager component.ConfigManager
+	OpDB          opdb.Store
+	Registry      Registry
+	Routing       BGPNetworkController
+	SRG           SRGProvider
+	Replicator    Replicator
+	Providers     map[string]ipamprovider.Provider
+}
+
+type chunkState struct {
+	chunk *models.IPAMChunk
+	// emptySince is when the chunk was first seen without leases; zero
+	// while it has any.
+	emptySince time.Time
+	// returned is set once the chunk is out of the allocator and BGP
+	// but the IPAM has yet to accept it back.
+	returned bool
+}
+
+// Component grows profiles with on-demand pools from a central IPAM and
+// gives the chunks back once they have been empty for a while.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+	nodeID string
+
+	mu     sync.Mutex
+	chunks map[string]*chunkState
+}
+
+func New(cfg Config) (*Component, error) {
+	if cfg.Registry == nil {
+		return nil, fmt.Errorf("ipam: allocator registry is required")
+	}
+
+	c := &Component{
+		Base:   component.NewBase("ipam"),
+		logger: logger.Get("ipam"),
+		cfg:    cfg,
+		chunks: make(map[string]*chunkState),
+	}
+
+	if running, err := cfg.ConfigManager.GetRunning(); err == nil && running != nil {
+		c.nodeID = running.HA.NodeID
+	}
+	if c.nodeID == "" {
+		c.nodeID, _ = os.Hostname()
+	}
+	return c, nil
+}
+
+// Start puts the chunks held before a restart back into the allocator
+// before any access component restores its sessions, then starts
+// watching the on-demand profiles.
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting IPAM component", "node_id", c.nodeID)
+
+	if err := c.restore(ctx); err != nil {
+		c.logger.Error("Failed to restore IPAM chunks", "error", err)
+	}
+
+	c.Go(c.watchProfiles)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping IPAM component")
+	c.StopContext()
+	return nil
+}
+
+func (c *Component) restore(ctx context.Context) error {
+	if c.cfg.OpDB == nil {
+		return nil
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return fmt.Errorf("get running config: %w", err)
+	}
+
+	var restored int
+	err = c.cfg.OpDB.Load(ctx, opdbNamespace, func(key string, value []byte) error {
+		var chunk models.IPAMChunk
+		if err := json.Unmarshal(value, &chunk); err != nil {
+			c.logger.Warn("Skipping undecodable IPAM chunk", "key", key, "error", err)
+			return nil
+		}
+		if err := c.install(cfg, &chunk); err != nil {
+			// Keep the record: an owned chunk is still ours at the IPAM
+			// and is given back by the release pass.
+			c.logger.Warn("Failed to restore IPAM chunk", "prefix", chunk.Prefix, "profile", chunk.Profile, "error", err)
+		}
+		c.mu.Lock()
+		c.chunks[chunk.Key()] = &chunkState{chunk: &chunk}
+		c.mu.Unlock()
+		restored++
+		return nil
+	})
+	if restored > 0 {
+		c.logger.Info("Restored IPAM chunks", "count", restored)
+	}
+	return err
+}
+
+// install adds a chunk to its profile and advertises it. A chunk that
+// is already in the allocator is only re-advertised.
+func (c *Component) install(cfg *config.Config, chunk *models.IPAMChunk) error {
+	prefix, err := netip.ParsePrefix(chunk.Prefix)
+	if err != nil {
+		return err
+	}
+	if prefix.Addr().Is4() != (chunk.Family == models.IPAMChunkIPv4) {
+		return fmt.Errorf("%s is not a %s prefix", prefix, chunk.Family)
+	}
+
+	switch chunk.Family {
+	case models.IPAMChunkIPv4:
+		var gateway string
+		if profile := cfg.IPv4Profiles[chunk.Profile]; profile != nil {
+			gateway = profile.Gateway
+		}
+		err = c.cfg.Registry.AddIPv4Pool(chunk.Profile, ip.IPv4Pool{Name: chunk.Pool, Network: chunk.Prefix, VRF: chunk.VRF}, gateway)
+	case models.IPAMChunkIANA:
+		err = c.cfg.Registry.AddIANAPool(chunk.Profile, ip.IANAPool{Name: chunk.Pool, Network: chunk.Prefix, VRF: chunk.VRF})
+	case models.IPAMChunkPD:
+		profile := cfg.IPv6Profiles[chunk.Profile]
+		if profile == nil || profile.PDOnDemand == nil {
+			return fmt.Errorf("profile %s has no pd-on-demand", chunk.Profile)
+		}
+		err = c.cfg.Registry.AddPDPool(chunk.Profile, ip.PDPool{
+			Name:         chunk.Pool,
+			Network:      chunk.Prefix,
+			PrefixLength: profile.PDOnDemand.PrefixLength,
+			VRF:          chunk.VRF,
+		})
+	default:
+		return fmt.Errorf("unknown chunk family %q", chunk.Family)
+	}
+	if err != nil && err != allocator.ErrPoolExists {
+		return err
+	}
+
+	c.setAdvertised(cfg, chunk, true)
+	return nil
+}
+
+// uninstall takes an empty chunk out of its profile and BGP. It fails
+// with allocator.ErrPoolInUse while the chunk has leases.
+func (c *Component) uninstall(cfg *config.Config, chunk *models.IPAMChunk) error {
+	if err := c.cfg.Registry.RemovePool(allocator.PoolFamily(chunk.Family), chunk.PoolKey()); err != nil {
+		return err
+	}
+	c.setAdvertised(cfg, chunk, false)
+	return nil
+}
+
+// setAdvertised originates or removes the chunk for every subscriber
+// group that advertises its profile's pools in the chunk's VRF, as the
+// config manager does for configured IPv4 pools. IPv6 chunks are
+// advertised the same way.
+func (c *Component) setAdvertised(cfg *config.Config, chunk *models.IPAMChunk, advertise bool) {
+	if c.cfg.Routing == nil || cfg == nil || cfg.Protocols.BGP == nil {
+		return
+	}
+	asn := cfg.Protocols.BGP.ASN
+	ipv6 := chunk.Family != models.IPAMChunkIPv4
+
+	for _, n := range chunkNetworks(cfg, chunk) {
+		var err error
+		if advertise {
+			err = c.cfg.Routing.AdvertiseBGPNetworkPolicy(asn, n.vrf, chunk.Prefix, n.routePolicy, ipv6)
+		} else {
+			err = c.cfg.Routing.RemoveBGPNetwork(asn, n.vrf, chunk.Prefix, ipv6)
+		}
+		if err != nil {
+			c.logger.Error("Failed to update IPAM chunk BGP network", "prefix", chunk.Prefix, "vrf", n.vrf,
+				"advertise", advertise, "error", err)
+		}
+	}
+}
+
+type chunkNetwork struct {
+	vrf         string
+	routePolicy string
+}
+
+func chunkNetworks(cfg *config.Config, chunk *models.IPAMChunk) []chunkNetwork {
+	if cfg.SubscriberGroups == nil {
+		return nil
+	}
+
+	var networks []chunkNetwork
+	seen := make(map[string]bool)
+	for _, group := range cfg.SubscriberGroups.Groups {
+		if group == nil || group.BGP == nil || !group.BGP.Enabled || !group.BGP.AdvertisePools {
+			continue
+		}
+		profile := group.IPv6Profile
+		if chunk.Family == models.IPAMChunkIPv4 {
+			profile = group.IPv4Profile
+		}
+		if profile != chunk.Profile {
+			continue
+		}
+		vrf := group.BGP.VRF
+		if vrf == "" {
+			vrf = group.VRF
+		}
+		if vrf != chunk.VRF || seen[vrf] {
+			continue
+		}
+		seen[vrf] = true
+		networks = append(networks, chunkNetwork{vrf: vrf, routePolicy: group.BGP.NetworkRoutePolicy})
+	}
+	return networks
+}
+
+func (c *Component) persist(ctx context.Context, chunk *models.IPAMChunk) {
+	if c.cfg.OpDB == nil {
+		return
+	}
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return
+	}
+	if err := c.cfg.OpDB.Put(ctx, opdbNamespace, chunk.Key(), data); err != nil {
+		c.logger.Error("Failed to persist IPAM chunk", "prefix", chunk.Prefix, "error", err)
+	}
+}
+
+func (c *Component) forget(ctx context.Context, chunk *models.IPAMChunk) {
+	c.mu.Lock()
+	delete(c.chunks, chunk.Key())
+	c.mu.Unlock()
+
+	if c.cfg.OpDB == nil {
+		return
+	}
+	if err := c.cfg.OpDB.Delete(ctx, opdbNamespace, chunk.Key()); err != nil {
+		c.logger.Error("Failed to delete IPAM chunk", "prefix", chunk.Prefix, "error", err)
+	}
+}
+
+func (c *Component) publish(action string, chunk *models.IPAMChunk) {
+	if c.cfg.EventBus == nil {
+		return
+	}
+	c.cfg.EventBus.Publish(events.TopicIPAMChunk, events.Event{
+		Source:    c.Name(),
+		Timestamp: time.Now(),
+		Data:      &events.IPAMChunkEvent{Action: action, Chunk: chunk},
+	})
+}
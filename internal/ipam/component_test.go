@@ -0,0 +1,334 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	ipamprovider "github.com/veesix-networks/osvbng/pkg/ipam"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/provider"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeRouting struct{ calls []string }
+
+func (r *fakeRouting) AdvertiseBGPNetworkPolicy(asn uint32, vrf, prefix, routePolicy string, ipv6 bool) error {
+	r.calls = append(r.calls, fmt.Sprintf("advertise %s %s", prefix, routePolicy))
+	return nil
+}
+
+func (r *fakeRouting) RemoveBGPNetwork(asn uint32, vrf, prefix string, ipv6 bool) error {
+	r.calls = append(r.calls, "remove "+prefix)
+	return nil
+}
+
+type fakeProvider struct {
+	prefixes []string
+	released []string
+}
+
+func (p *fakeProvider) Info() provider.Info { return provider.Info{Name: "fake"} }
+
+func (p *fakeProvider) Allocate(ctx context.Context, req *ipamprovider.ChunkRequest) (*ipamprovider.Chunk, error) {
+	if len(p.prefixes) == 0 {
+		return nil, errors.New("exhausted")
+	}
+	prefix := p.prefixes[0]
+	p.prefixes = p.prefixes[1:]
+	return &ipamprovider.Chunk{Prefix: prefix, ID: "id-" + prefix}, nil
+}
+
+func (p *fakeProvider) Release(ctx context.Context, req *ipamprovider.ChunkRequest, chunk *ipamprovider.Chunk) error {
+	p.released = append(p.released, chunk.Prefix)
+	return nil
+}
+
+type fakeReplicator struct {
+	calls []string
+	err   error
+}
+
+func (r *fakeReplicator) ReplicateIPAMChunk(ctx context.Context, add bool, chunk *models.IPAMChunk) error {
+	r.calls = append(r.calls, fmt.Sprintf("%t %s", add, chunk.Prefix))
+	if add {
+		return nil
+	}
+	return r.err
+}
+
+type fakeOpDB struct {
+	opdb.Store
+	data map[string][]byte
+}
+
+func (f *fakeOpDB) Put(ctx context.Context, namespace, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeOpDB) Delete(ctx context.Context, namespace, key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeOpDB) Load(ctx context.Context, namespace string, fn opdb.LoadFunc) error {
+	for k, v := range f.data {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type testEnv struct {
+	c        *Component
+	registry *allocator.Registry
+	routing  *fakeRouting
+	provider *fakeProvider
+	peer     *fakeReplicator
+	store    *fakeOpDB
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	cfg := &config.Config{
+		Protocols: protocols.ProtocolConfig{BGP: &protocols.BGPConfig{ASN: 65000}},
+		IPv4Profiles: map[string]*ip.IPv4Profile{
+			"res": {
+				Gateway: "10.255.255.1",
+				Pools: []ip.IPv4Pool{
+					{Name: "static", Network: "10.0.0.0/24", RangeStart: "10.0.0.1", RangeEnd: "10.0.0.4"},
+				},
+				OnDemand: &ip.OnDemandPools{ChunkLength: 29, Threshold: 75, ReleaseGrace: time.Minute},
+			},
+		},
+		SubscriberGroups: &subscriber.SubscriberGroupsConfig{Groups: map[string]*subscriber.SubscriberGroup{
+			"g1": {IPv4Profile: "res", BGP: &subscriber.SubscriberBGP{Enabled: true, AdvertisePools: true, NetworkRoutePolicy: "POOLS-OUT"}},
+		}},
+	}
+	cfg.HA.NodeID = "bng1"
+
+	registry := allocator.InitGlobalRegistry(cfg.IPv4Profiles, nil)
+	t.Cleanup(allocator.ResetGlobalRegistry)
+
+	env := &testEnv{
+		registry: registry,
+		routing:  &fakeRouting{},
+		provider: &fakeProvider{prefixes: []string{"100.64.0.0/29", "100.64.0.8/29"}},
+		peer:     &fakeReplicator{},
+		store:    &fakeOpDB{data: make(map[string][]byte)},
+	}
+	c, err := New(Config{
+		ConfigManager: &fakeCfg{cfg: cfg},
+		OpDB:          env.store,
+		Registry:      registry,
+		Routing:       env.routing,
+		Replicator:    env.peer,
+		Providers:     map[string]ipamprovider.Provider{"http": env.provider},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c.StartContext(context.Background())
+	t.Cleanup(c.StopContext)
+	env.c = c
+	return env
+}
+
+func (e *testEnv) allocate(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, _, err := e.registry.AllocateFromProfile("res", "", "", fmt.Sprintf("s%d", i)); err != nil {
+			t.Fatalf("allocate %d: %v", i, err)
+		}
+	}
+}
+
+func TestGrowAddsChunkAtThreshold(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.allocate(t, 2)
+	env.c.check()
+	if len(env.c.OwnedChunks()) != 0 {
+		t.Fatal("chunk requested below the threshold")
+	}
+
+	env.allocate(t, 1)
+	env.c.check()
+	owned := env.c.OwnedChunks()
+	if len(owned) != 1 || owned[0].Prefix != "100.64.0.0/29" || owned[0].Owner != "bng1" {
+		t.Fatalf("owned = %+v", owned)
+	}
+	if got := env.registry.GetProfilePools("res"); len(got) != 2 {
+		t.Fatalf("profile pools = %v", got)
+	}
+	if fmt.Sprint(env.routing.calls) != "[advertise 100.64.0.0/29 POOLS-OUT]" {
+		t.Fatalf("routing = %v", env.routing.calls)
+	}
+	if fmt.Sprint(env.peer.calls) != "[true 100.64.0.0/29]" {
+		t.Fatalf("replication = %v", env.peer.calls)
+	}
+	if _, ok := env.store.data[owned[0].Key()]; !ok {
+		t.Fatal("chunk not persisted")
+	}
+
+	// Utilisation is back under the threshold with the chunk added.
+	env.c.check()
+	if len(env.c.OwnedChunks()) != 1 {
+		t.Fatal("second chunk requested under the threshold")
+	}
+}
+
+func TestGrowReleasesOverlappingChunk(t *testing.T) {
+	env := newTestEnv(t)
+	env.provider.prefixes = []string{"10.0.0.0/29", "100.64.0.0/29"}
+	env.allocate(t, 3)
+
+	env.c.check()
+	if len(env.c.OwnedChunks()) != 0 {
+		t.Fatalf("chunk overlapping a configured pool added: %+v", env.c.OwnedChunks())
+	}
+	if fmt.Sprint(env.provider.released) != "[10.0.0.0/29]" {
+		t.Fatalf("released = %v", env.provider.released)
+	}
+	if len(env.routing.calls) != 0 || len(env.peer.calls) != 0 {
+		t.Fatalf("overlapping chunk advertised or replicated: %v %v", env.routing.calls, env.peer.calls)
+	}
+
+	env.c.check()
+	if owned := env.c.OwnedChunks(); len(owned) != 1 || owned[0].Prefix != "100.64.0.0/29" {
+		t.Fatalf("owned = %+v", owned)
+	}
+
+	peer := &models.IPAMChunk{Family: "ipv4", Profile: "res", Pool: "ipam-100.64.0.0_28", Prefix: "100.64.0.0/28", Owner: "bng2"}
+	if err := env.c.ApplySyncedChunk(true, peer); !errors.Is(err, allocator.ErrPoolOverlap) {
+		t.Fatalf("peer chunk overlapping a held one: got %v, want ErrPoolOverlap", err)
+	}
+	if len(env.c.chunks) != 1 {
+		t.Fatalf("overlapping peer chunk held: %d chunks", len(env.c.chunks))
+	}
+}
+
+func TestShrinkReturnsEmptyChunkAfterGrace(t *testing.T) {
+	env := newTestEnv(t)
+	env.allocate(t, 3)
+	env.c.check()
+	chunk := env.c.OwnedChunks()[0]
+
+	// Still over the threshold without the chunk: kept.
+	now := time.Now()
+	env.c.shrink(env.c.mustRunning(t), now)
+	env.c.shrink(env.c.mustRunning(t), now.Add(2*time.Minute))
+	if len(env.c.OwnedChunks()) != 1 {
+		t.Fatal("chunk returned while the profile would be over its threshold")
+	}
+
+	env.registry.ReleaseIP(net.ParseIP("10.0.0.1"))
+	env.registry.ReleaseIP(net.ParseIP("10.0.0.2"))
+	env.registry.AllocateFromProfile("res", chunk.Pool, "", "busy")
+	now = now.Add(2 * time.Minute)
+	env.c.shrink(env.c.mustRunning(t), now)
+	if !env.c.chunks[chunk.Key()].emptySince.IsZero() {
+		t.Fatal("grace period running while the chunk has a lease")
+	}
+
+	env.registry.ReleaseIP(net.ParseIP("100.64.0.1"))
+	env.c.shrink(env.c.mustRunning(t), now)
+	env.c.shrink(env.c.mustRunning(t), now.Add(30*time.Second))
+	if len(env.c.OwnedChunks()) != 1 {
+		t.Fatal("chunk returned before the grace period")
+	}
+	env.c.shrink(env.c.mustRunning(t), now.Add(2*time.Minute))
+	if len(env.c.OwnedChunks()) != 0 {
+		t.Fatal("chunk not returned")
+	}
+	if fmt.Sprint(env.provider.released) != "[100.64.0.0/29]" {
+		t.Fatalf("released = %v", env.provider.released)
+	}
+	if env.routing.calls[len(env.routing.calls)-1] != "remove 100.64.0.0/29" {
+		t.Fatalf("routing = %v", env.routing.calls)
+	}
+	if _, ok := env.store.data[chunk.Key()]; ok {
+		t.Fatal("chunk still persisted")
+	}
+}
+
+func TestShrinkKeepsChunkPeerStillUses(t *testing.T) {
+	env := newTestEnv(t)
+	env.c.cfg.Registry.AddIPv4Pool("res", ip.IPv4Pool{Name: "ipam-100.64.0.0_29", Network: "100.64.0.0/29"}, "")
+	chunk := &models.IPAMChunk{Family: "ipv4", Profile: "res", Pool: "ipam-100.64.0.0_29", Prefix: "100.64.0.0/29", Owner: "bng1"}
+	env.c.chunks[chunk.Key()] = &chunkState{chunk: chunk, emptySince: time.Now().Add(-time.Hour)}
+	env.peer.err = ha.ErrPeerChunkInUse
+
+	env.c.shrink(env.c.mustRunning(t), time.Now())
+	if len(env.c.OwnedChunks()) != 1 || len(env.provider.released) != 0 {
+		t.Fatal("chunk returned while the peer has leases in it")
+	}
+	if !env.c.chunks[chunk.Key()].emptySince.IsZero() {
+		t.Fatal("grace period not restarted")
+	}
+}
+
+func TestRestoreAndPeerChunks(t *testing.T) {
+	env := newTestEnv(t)
+	owned := &models.IPAMChunk{Family: "ipv4", Profile: "res", Pool: "ipam-100.64.0.0_29", Prefix: "100.64.0.0/29", Owner: "bng1"}
+	data, _ := json.Marshal(owned)
+	env.store.data[owned.Key()] = data
+
+	if err := env.c.restore(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := env.registry.ReserveIP(net.ParseIP("100.64.0.3"), "restored"); err != nil {
+		t.Fatalf("reserve in restored chunk: %v", err)
+	}
+
+	peer := &models.IPAMChunk{Family: "ipv4", Profile: "res", Pool: "ipam-100.64.1.0_29", Prefix: "100.64.1.0/29", Owner: "bng2"}
+	env.c.ReconcilePeerChunks([]*models.IPAMChunk{peer})
+	if len(env.c.chunks) != 2 || len(env.c.OwnedChunks()) != 1 {
+		t.Fatalf("chunks after reconcile = %d, owned %d", len(env.c.chunks), len(env.c.OwnedChunks()))
+	}
+
+	if err := env.registry.ReserveIP(net.ParseIP("100.64.1.2"), "synced"); err != nil {
+		t.Fatalf("reserve in peer chunk: %v", err)
+	}
+	if err := env.c.ApplySyncedChunk(false, peer); !errors.Is(err, allocator.ErrPoolInUse) {
+		t.Fatalf("remove with lease: got %v, want ErrPoolInUse", err)
+	}
+	env.registry.ReleaseIP(net.ParseIP("100.64.1.2"))
+	env.c.ReconcilePeerChunks(nil)
+	if len(env.c.chunks) != 1 {
+		t.Fatalf("stale peer chunk kept: %d chunks", len(env.c.chunks))
+	}
+}
+
+func (c *Component) mustRunning(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
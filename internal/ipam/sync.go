@@ -0,0 +1,108 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// ApplySyncedChunk adds or removes a chunk the HA peer owns. The chunk
+// is persisted like an owned one, so a restart keeps serving sessions
+// addressed from it.
+func (c *Component) ApplySyncedChunk(add bool, chunk *models.IPAMChunk) error {
+	if chunk.Owner == c.nodeID {
+		return fmt.Errorf("chunk %s is owned by this node", chunk.Prefix)
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if add {
+		if err := c.checkHeld(chunk); err != nil {
+			return err
+		}
+		if err := c.install(cfg, chunk); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.chunks[chunk.Key()] = &chunkState{chunk: chunk}
+		c.mu.Unlock()
+		c.persist(ctx, chunk)
+		c.logger.Info("Adopted IPAM chunk from peer", "family", chunk.Family, "profile", chunk.Profile,
+			"prefix", chunk.Prefix, "owner", chunk.Owner)
+		c.publish(events.IPAMChunkAdopted, chunk)
+		return nil
+	}
+
+	c.mu.Lock()
+	_, held := c.chunks[chunk.Key()]
+	c.mu.Unlock()
+	if !held {
+		return nil
+	}
+	if err := c.uninstall(cfg, chunk); err != nil {
+		return err
+	}
+	c.forget(ctx, chunk)
+	c.logger.Info("Removed peer IPAM chunk", "family", chunk.Family, "profile", chunk.Profile, "prefix", chunk.Prefix)
+	c.publish(events.IPAMChunkRemoved, chunk)
+	return nil
+}
+
+// OwnedChunks returns the chunks this node took from the IPAM and has
+// not yet given back.
+func (c *Component) OwnedChunks() []*models.IPAMChunk {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []*models.IPAMChunk
+	for _, st := range c.chunks {
+		if st.chunk.Owner == c.nodeID && !st.returned {
+			out = append(out, st.chunk)
+		}
+	}
+	return out
+}
+
+// ReconcilePeerChunks adopts the peer's chunks this node is missing and
+// drops adopted chunks the peer no longer owns, unless they still have
+// leases here.
+func (c *Component) ReconcilePeerChunks(chunks []*models.IPAMChunk) {
+	owned := make(map[string]bool, len(chunks))
+	for _, chunk := range chunks {
+		owned[chunk.Key()] = true
+
+		c.mu.Lock()
+		_, held := c.chunks[chunk.Key()]
+		c.mu.Unlock()
+		if held {
+			continue
+		}
+		if err := c.ApplySyncedChunk(true, chunk); err != nil {
+			c.logger.Warn("Failed to adopt peer IPAM chunk", "prefix", chunk.Prefix, "error", err)
+		}
+	}
+
+	var stale []*models.IPAMChunk
+	c.mu.Lock()
+	for key, st := range c.chunks {
+		if st.chunk.Owner != c.nodeID && !owned[key] {
+			stale = append(stale, st.chunk)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, chunk := range stale {
+		if err := c.ApplySyncedChunk(false, chunk); err != nil {
+			c.logger.Info("Keeping stale peer IPAM chunk", "prefix", chunk.Prefix, "error", err)
+		}
+	}
+}
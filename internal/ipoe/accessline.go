@@ -0,0 +1,85 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipoe
+
+import (
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/accessline"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+// updateAccessLine follows a change in the line rates the access node
+// reports for an approved session, as a renew carries them after the
+// line retrains. The session's access-line attributes are replaced and,
+// where its service group shapes to the line, QoS is moved to the new
+// limits straight away rather than waiting for a CoA. A packet that
+// reports no rates leaves the session as it is.
+func (c *Component) updateAccessLine(sess *SessionState, line accessline.Characteristics) {
+	sess.mu.Lock()
+	if line.IsZero() || line == sess.AccessLine || sess.Closing {
+		sess.mu.Unlock()
+		return
+	}
+	old := sess.AccessLine
+	sess.AccessLine = line
+	sess.Attributes = line.Update(sess.Attributes, old)
+
+	// Only the line rates change: the rest of the resolved group stays
+	// as it was set up, whatever the config has become since.
+	from := sess.ServiceGroup
+	to := from
+	current := accessline.FromAttributes(sess.Attributes)
+	to.LineRateUp, to.LineRateDown = current.ActualRateUp, current.ActualRateDown
+	sess.ServiceGroup = to
+
+	sessID := sess.SessionID
+	swIfIndex := sess.IPoESwIfIndex
+	lineAttrs := make(map[string]string)
+	for _, name := range accessline.AttributeNames() {
+		if v, ok := sess.Attributes[name]; ok {
+			lineAttrs[name] = v
+		}
+	}
+	snapshot := c.buildModelSnapshot(sess)
+	sess.mu.Unlock()
+
+	c.checkpointSession(sess)
+
+	// A session not programmed yet picks the new limits up from its
+	// service group when it is.
+	if swIfIndex != 0 {
+		var qosPolicies map[string]*qos.Policy
+		if cfg, _ := c.cfgMgr.GetRunning(); cfg != nil {
+			qosPolicies = cfg.QoSPolicies
+		}
+		if !svcgroup.SameBindings(from, to, qosPolicies) {
+			if err := svcgroup.ReapplyToSession(c.vpp, swIfIndex, from, to, qosPolicies); err != nil {
+				c.logger.Warn("Failed to move session to new line rates",
+					"session_id", sessID, "sw_if_index", swIfIndex, "error", err)
+			}
+		}
+	}
+
+	upLimit, downLimit := to.LineLimits()
+	c.logger.Info("Access line rates changed",
+		"session_id", sessID,
+		"actual_rate_up", line.ActualRateUp,
+		"actual_rate_down", line.ActualRateDown,
+		"line_limit_up", upLimit,
+		"line_limit_down", downLimit)
+
+	c.eventBus.Publish(events.TopicAccessLine, events.Event{
+		Source:    c.Name(),
+		Timestamp: time.Now(),
+		Data: &events.AccessLineEvent{
+			SessionID:  sessID,
+			Attributes: lineAttrs,
+			Session:    snapshot,
+		},
+	})
+}
@@ -6,6 +6,7 @@ package ipoe
 
 import (
 	"context"
+	"net"
 	"sync"
 
 	"github.com/veesix-networks/osvbng/internal/ra"
@@ -66,6 +67,10 @@ type Component struct {
 	// never terminated here. Set via SetL2GWChannel before Start.
 	l2gwChan chan<- *dataplane.ParsedPacket
 
+	// localSwitching decides whether an NS for another subscriber's
+	// address is answered. Set via SetLocalSwitching before Start.
+	localSwitching LocalSwitching
+
 	aaaRespSub   events.Subscription
 	haStateSub   events.Subscription
 	mutationSub  events.Subscription
@@ -113,6 +118,18 @@ func (c *Component) SetL2GWChannel(ch chan<- *dataplane.ParsedPacket) {
 	c.l2gwChan = ch
 }
 
+// LocalSwitching decides whether the BNG answers a subscriber's NS for
+// another subscriber's address.
+type LocalSwitching interface {
+	ProxyNeighbor(sessionID string, target net.IP) bool
+}
+
+// SetLocalSwitching enables proxy ND between subscribers as their
+// group's local-switching mode allows.
+func (c *Component) SetLocalSwitching(ls LocalSwitching) {
+	c.localSwitching = ls
+}
+
 // forwardToL2GW hands a DHCP packet to the l2gw component when its
 // subscriber group is wholesale-switched. Non-blocking: the l2gw
 // trigger queue is bounded and clients retransmit.
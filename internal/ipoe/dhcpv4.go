@@ -15,6 +15,7 @@ import (
 	"github.com/google/gopacket/layers"
 	"github.com/google/uuid"
 	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/accessline"
 	"github.com/veesix-networks/osvbng/pkg/allocator"
 	aaacfg "github.com/veesix-networks/osvbng/pkg/config/aaa"
 	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
@@ -227,7 +228,9 @@ func (c *Component) handleDiscover(pkt *dataplane.ParsedPacket) error {
 
 	hostname := string(getDHCPOption(pkt.DHCPv4.Options, layers.DHCPOptHostname))
 	clientID := getDHCPOption(pkt.DHCPv4.Options, layers.DHCPOptClientID)
-	circuitID, remoteID := parseOption82(getDHCPOption(pkt.DHCPv4.Options, 82))
+	opt82 := getDHCPOption(pkt.DHCPv4.Options, 82)
+	circuitID, remoteID := parseOption82(opt82)
+	line := accessline.ParseOption82(opt82)
 
 	buf := gopacket.NewSerializeBuffer()
 	opts := gopacket.SerializeOptions{
@@ -248,6 +251,9 @@ func (c *Component) handleDiscover(pkt *dataplane.ParsedPacket) error {
 	sess.ClientID = clientID
 	sess.CircuitID = circuitID
 	sess.RemoteID = remoteID
+	if !sess.AAAApproved {
+		sess.AccessLine = line
+	}
 	sess.LastSeen = time.Now()
 	sess.EncapIfIndex = pkt.SwIfIndex
 	sess.PendingDHCPDiscover = buf.Bytes()
@@ -264,6 +270,7 @@ func (c *Component) handleDiscover(pkt *dataplane.ParsedPacket) error {
 
 	if alreadyApproved && ipoeCreated {
 		c.logger.WithGroup(logger.IPoEDHCP4).Debug("Session already approved, forwarding DISCOVER to provider", "session_id", sess.SessionID)
+		c.updateAccessLine(sess, line)
 		v4Profile := c.resolveIPv4Profile(sess.AllocCtx)
 		var resolved *dhcp.ResolvedDHCPv4
 		if v4Profile == nil || v4Profile.GetMode() == "server" {
@@ -370,6 +377,9 @@ func (c *Component) handleDiscover(pkt *dataplane.ParsedPacket) error {
 	if hostname != "" {
 		aaaAttrs[aaa.AttrHostname] = hostname
 	}
+	for k, v := range line.Attributes() {
+		aaaAttrs[k] = v
+	}
 
 	aaaPayload := &models.AAARequest{
 		RequestID:        requestID,
@@ -459,6 +469,9 @@ func (c *Component) handleRequest(pkt *dataplane.ParsedPacket) error {
 		return fmt.Errorf("serialize DHCP: %w", err)
 	}
 
+	opt82 := getDHCPOption(pkt.DHCPv4.Options, 82)
+	line := accessline.ParseOption82(opt82)
+
 	sess.mu.Lock()
 	if sess.Closing {
 		sess.mu.Unlock()
@@ -467,6 +480,9 @@ func (c *Component) handleRequest(pkt *dataplane.ParsedPacket) error {
 	sess.XID = pkt.DHCPv4.Xid
 	sess.LastSeen = time.Now()
 	sess.PendingDHCPRequest = buf.Bytes()
+	if !sess.AAAApproved && !line.IsZero() {
+		sess.AccessLine = line
+	}
 	alreadyApproved := sess.AAAApproved
 	aaaInFlight := sess.AAAInFlight
 	if !alreadyApproved && !aaaInFlight {
@@ -478,6 +494,8 @@ func (c *Component) handleRequest(pkt *dataplane.ParsedPacket) error {
 	if alreadyApproved {
 		c.logger.WithGroup(logger.IPoEDHCP4).Debug("Session already AAA approved, processing REQUEST with DHCP provider", "session_id", sess.SessionID)
 
+		c.updateAccessLine(sess, line)
+
 		buf := gopacket.NewSerializeBuffer()
 		opts := gopacket.SerializeOptions{
 			ComputeChecksums: true,
@@ -552,7 +570,7 @@ func (c *Component) handleRequest(pkt *dataplane.ParsedPacket) error {
 	}
 
 	hostname := string(getDHCPOption(pkt.DHCPv4.Options, layers.DHCPOptHostname))
-	circuitID, remoteID := parseOption82(getDHCPOption(pkt.DHCPv4.Options, 82))
+	circuitID, remoteID := parseOption82(opt82)
 
 	cfg, _ := c.cfgMgr.GetRunning()
 	username := pkt.MAC.String()
@@ -615,6 +633,9 @@ func (c *Component) handleRequest(pkt *dataplane.ParsedPacket) error {
 	if hostname != "" {
 		aaaAttrs[aaa.AttrHostname] = hostname
 	}
+	for k, v := range line.Attributes() {
+		aaaAttrs[k] = v
+	}
 
 	aaaPayload := &models.AAARequest{
 		RequestID:        requestID,
@@ -986,6 +1007,7 @@ func (c *Component) handleAck(sess *SessionState, pkt *dataplane.ParsedPacket) e
 		AccessInterface: c.accessInterfaceName(sess.EncapIfIndex),
 		VRF:             sess.VRF,
 		ServiceGroup:    sess.ServiceGroup.Name,
+		SteeringPolicy:  sess.ServiceGroup.SteeringPolicy,
 		SRGName:         sess.SRGName,
 		IPv4Address:     sess.IPv4,
 		LeaseTime:       sess.LeaseTime,
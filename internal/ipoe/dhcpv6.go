@@ -704,6 +704,7 @@ func (c *Component) handleDHCPv6Reply(sess *SessionState, msg *dhcp6.Message) er
 		AccessInterface: c.accessInterfaceName(sess.EncapIfIndex),
 		VRF:             sess.VRF,
 		ServiceGroup:    sess.ServiceGroup.Name,
+		SteeringPolicy:  sess.ServiceGroup.SteeringPolicy,
 		SRGName:         sess.SRGName,
 		IPv4Address:     snapshotIPv4,
 		LeaseTime:       snapshotLeaseTime,
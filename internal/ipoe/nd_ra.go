@@ -469,12 +469,40 @@ func (c *Component) processNSPacket(pkt *dataplane.ParsedPacket) error {
 	}
 
 	if !target.Equal(expected) {
-		return nil
+		if !c.proxyNeighbor(pkt, target) {
+			return nil
+		}
+		return c.sendProxyNA(pkt, parentSwIfIndex, localMAC, expected, target)
 	}
 
 	return c.sendNAResponse(pkt, parentSwIfIndex, localMAC, expected)
 }
 
+// proxyNeighbor reports whether the BNG answers the subscriber's NS for
+// another subscriber's address, as the group's local-switching mode
+// allows. Duplicate address detection, sent from the unspecified
+// address, is never answered for another subscriber.
+func (c *Component) proxyNeighbor(pkt *dataplane.ParsedPacket, target net.IP) bool {
+	if c.localSwitching == nil || pkt.IPv6.SrcIP.IsUnspecified() {
+		return false
+	}
+	val, ok := c.sessions.Load(c.makeSessionKeyV6(pkt.MAC, pkt.OuterVLAN, pkt.InnerVLAN))
+	if !ok {
+		return false
+	}
+	return c.localSwitching.ProxyNeighbor(val.(*SessionState).SessionID, target)
+}
+
+// sendProxyNA answers an NS for another subscriber's address with our
+// MAC. RFC 4861 7.2.8: a proxy advertisement leaves Override clear, so
+// it never replaces an entry the subscriber learnt from the owner.
+func (c *Component) sendProxyNA(pkt *dataplane.ParsedPacket, parentSwIfIndex uint32, localMAC net.HardwareAddr, srcIP, target net.IP) error {
+	c.logger.Debug("Proxying NS for subscriber in the same group",
+		"target", target.String(), "svlan", pkt.OuterVLAN, "cvlan", pkt.InnerVLAN)
+	return c.emitNA(pkt.MAC.String(), pkt.IPv6.SrcIP, pkt.OuterVLAN, pkt.InnerVLAN,
+		pkt.SwIfIndex, parentSwIfIndex, localMAC, srcIP, target, 0x80|0x40, true)
+}
+
 func (c *Component) sendNAResponse(pkt *dataplane.ParsedPacket, parentSwIfIndex uint32, localMAC net.HardwareAddr, srcIP net.IP) error {
 	dstIP := pkt.IPv6.SrcIP
 	solicited := !dstIP.IsUnspecified()
@@ -488,7 +516,7 @@ func (c *Component) sendNAResponse(pkt *dataplane.ParsedPacket, parentSwIfIndex
 	}
 
 	return c.emitNA(pkt.MAC.String(), dstIP, pkt.OuterVLAN, pkt.InnerVLAN,
-		pkt.SwIfIndex, parentSwIfIndex, localMAC, srcIP, naFlags, solicited)
+		pkt.SwIfIndex, parentSwIfIndex, localMAC, srcIP, srcIP, naFlags, solicited)
 }
 
 // sendRestoreNA multicasts one unsolicited Neighbor Advertisement for
@@ -527,13 +555,15 @@ func (c *Component) sendRestoreNA(sess *SessionState) {
 	}
 	if err := c.emitNA("33:33:00:00:00:01", net.ParseIP("ff02::1"),
 		sess.OuterVLAN, sess.InnerVLAN, sess.EncapIfIndex, parentSwIfIndex,
-		localMAC, srcIP, 0x80|0x20, false); err != nil {
+		localMAC, srcIP, srcIP, 0x80|0x20, false); err != nil {
 		c.logger.Warn("Failed to send restore NA",
 			"session_id", sess.SessionID, "error", err)
 	}
 }
 
-func (c *Component) emitNA(dstMAC string, dstIP net.IP, outerVLAN, innerVLAN uint16, tpidIfIndex, egressIfIndex uint32, localMAC net.HardwareAddr, srcIP net.IP, naFlags uint8, solicited bool) error {
+// emitNA sends an NA for target from srcIP, the gateway link-local;
+// target is the link-local too unless the NA is proxied.
+func (c *Component) emitNA(dstMAC string, dstIP net.IP, outerVLAN, innerVLAN uint16, tpidIfIndex, egressIfIndex uint32, localMAC net.HardwareAddr, srcIP, target net.IP, naFlags uint8, solicited bool) error {
 	naOptions := layers.ICMPv6Options{
 		{
 			Type: layers.ICMPv6OptTargetAddress,
@@ -543,7 +573,7 @@ func (c *Component) emitNA(dstMAC string, dstIP net.IP, outerVLAN, innerVLAN uin
 
 	naLayer := &layers.ICMPv6NeighborAdvertisement{
 		Flags:         naFlags,
-		TargetAddress: srcIP,
+		TargetAddress: target,
 		Options:       naOptions,
 	}
 
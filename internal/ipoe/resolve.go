@@ -257,6 +257,11 @@ func (c *Component) resolveDHCPv6(ctx *allocator.Context) *dhcp.ResolvedDHCPv6 {
 		c.logger.Error("IPv6 profile not found", "profile", ctx.IPv6ProfileName, "session_id", ctx.SessionID)
 		return nil
 	}
+	ctx.AFTRName = cfg.ServiceGroupAFTRName(ctx.ServiceGroup)
+	ctx.MAP = cfg.ServiceGroupMAP(ctx.ServiceGroup)
+	if len(ctx.DNSv6) == 0 {
+		ctx.DNSv6 = cfg.ServiceGroupDNS64(ctx.ServiceGroup)
+	}
 	return dhcp.ResolveV6(ctx, profile)
 }
 
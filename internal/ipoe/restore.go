@@ -13,6 +13,7 @@ import (
 	"time"
 
 	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/accessline"
 	"github.com/veesix-networks/osvbng/pkg/events"
 	"github.com/veesix-networks/osvbng/pkg/ha"
 	"github.com/veesix-networks/osvbng/pkg/models"
@@ -210,6 +211,7 @@ func (c *Component) restoreSessionToCache(ctx context.Context, sess *SessionStat
 		AccessInterface: c.accessInterfaceName(sess.EncapIfIndex),
 		VRF:             sess.VRF,
 		ServiceGroup:    sess.ServiceGroup.Name,
+		SteeringPolicy:  sess.ServiceGroup.SteeringPolicy,
 		SRGName:         sess.SRGName,
 		IPv4Address:     sess.IPv4,
 		LeaseTime:       sess.LeaseTime,
@@ -449,6 +451,7 @@ func (c *Component) restoreFromHASync(srgName string) {
 					aaaAttrs[k] = v
 				}
 				sess.Attributes = cp.AaaAttributes
+				sess.AccessLine = accessline.FromAttributes(cp.AaaAttributes)
 			}
 			sess.ServiceGroup = c.svcGroupResolver.Resolve(cp.ServiceGroup, cp.ServiceGroup, aaaAttrs)
 		}
@@ -561,6 +564,7 @@ func (c *Component) buildModelSnapshot(sess *SessionState) *models.IPoESession {
 		AccessInterface: c.accessInterfaceName(sess.EncapIfIndex),
 		VRF:             sess.VRF,
 		ServiceGroup:    sess.ServiceGroup.Name,
+		SteeringPolicy:  sess.ServiceGroup.SteeringPolicy,
 		SRGName:         sess.SRGName,
 		IPv4Address:     sess.IPv4,
 		LeaseTime:       sess.LeaseTime,
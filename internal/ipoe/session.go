@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/veesix-networks/osvbng/pkg/accessline"
 	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
 	"github.com/veesix-networks/osvbng/pkg/events"
@@ -37,6 +38,7 @@ type SessionState struct {
 	ClientID            []byte
 	CircuitID           []byte
 	RemoteID            []byte
+	AccessLine          accessline.Characteristics
 	LastSeen            time.Time
 	AAAApproved         bool
 	IPoESessionCreated  bool
@@ -430,6 +432,7 @@ func (c *Component) ForEachSession(fn func(models.SubscriberSession) bool) {
 			AccessInterface: c.accessInterfaceName(sess.EncapIfIndex),
 			VRF:             sess.VRF,
 			ServiceGroup:    sess.ServiceGroup.Name,
+			SteeringPolicy:  sess.ServiceGroup.SteeringPolicy,
 			SRGName:         sess.SRGName,
 			IPv4Address:     sess.IPv4,
 			LeaseTime:       sess.LeaseTime,
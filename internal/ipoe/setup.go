@@ -437,6 +437,7 @@ func (c *Component) handleAAAResponse(event events.Event) {
 	cvlan := sess.InnerVLAN
 	encapIfIndex := sess.EncapIfIndex
 	ipoeCreated := sess.IPoESessionCreated
+	line := sess.AccessLine
 	sess.mu.Unlock()
 
 	if !allowed {
@@ -479,7 +480,10 @@ func (c *Component) handleAAAResponse(event events.Event) {
 		}
 	}
 
-	resolved := c.resolveServiceGroup(svlan, cvlan, data.Response.Attributes)
+	// The line rates the access node reported shape the session like AAA
+	// attributes, and are kept with it so accounting reports them; AAA
+	// returning its own values overrides them.
+	resolved := c.resolveServiceGroup(svlan, cvlan, line.ResolverAttributes(data.Response.Attributes))
 
 	var srgName string
 	if c.srgMgr != nil && subscriberGroup != "" {
@@ -490,6 +494,11 @@ func (c *Component) handleAAAResponse(event events.Event) {
 	for k, v := range data.Response.Attributes {
 		storedAttrs[k] = fmt.Sprintf("%v", v)
 	}
+	for k, v := range line.Attributes() {
+		if _, ok := storedAttrs[k]; !ok {
+			storedAttrs[k] = v
+		}
+	}
 
 	sess.mu.Lock()
 	sess.Attributes = storedAttrs
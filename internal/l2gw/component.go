@@ -51,6 +51,13 @@ type Component struct {
 	armedMu    sync.Mutex
 	armedPorts map[uint32]bool
 
+	// handoffs tracks redundant handoff groups; failoverMu serializes
+	// circuit moves between members.
+	handoffMu  sync.Mutex
+	handoffs   map[string]*handoffState
+	failoverMu sync.Mutex
+	watchIf    func(swIfIndex uint32)
+
 	triggerChan       chan *dataplane.ParsedPacket
 	packetTriggerChan <-chan *dataplane.ParsedPacket
 
@@ -58,6 +65,7 @@ type Component struct {
 	terminateSub events.Subscription
 	haStateSub   events.Subscription
 	evpnSub      events.Subscription
+	ifStateSub   events.Subscription
 }
 
 func New(deps component.Dependencies, srgMgr ha.SRGProvider, ifMgr *ifmgr.Manager) (*Component, error) {
@@ -72,6 +80,7 @@ func New(deps component.Dependencies, srgMgr ha.SRGProvider, ifMgr *ifmgr.Manage
 		srgMgr:      srgMgr,
 		allocators:  make(map[string]*vlanAllocator),
 		armedPorts:  make(map[uint32]bool),
+		handoffs:    make(map[string]*handoffState),
 		triggerChan: make(chan *dataplane.ParsedPacket, 1024),
 	}
 	return c, nil
@@ -97,6 +106,11 @@ func (c *Component) Start(ctx context.Context) error {
 
 	c.SetReadyState(component.StateRestoring)
 
+	if cfg, err := c.cfgMgr.GetRunning(); err == nil && cfg != nil {
+		c.syncHandoffs(cfg.L2GW)
+	}
+	c.ifStateSub = c.eventBus.Subscribe(events.TopicInterfaceState, c.handleInterfaceState)
+
 	if err := c.restoreCircuits(ctx); err != nil {
 		c.logger.Warn("Failed to restore l2gw circuits from OpDB", "error", err)
 	}
@@ -151,6 +165,9 @@ func (c *Component) Stop(ctx context.Context) error {
 	if c.evpnSub != nil {
 		c.evpnSub.Unsubscribe()
 	}
+	if c.ifStateSub != nil {
+		c.ifStateSub.Unsubscribe()
+	}
 
 	c.StopContext()
 	return nil
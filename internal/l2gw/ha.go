@@ -53,7 +53,9 @@ func (c *Component) ForEachSession(fn func(models.SubscriberSession) bool) {
 // ApplySyncedCircuit eagerly installs (or removes) a peer-synced circuit
 // on the standby with forwarding disabled, so promotion is a batch
 // state flip rather than a re-install storm. Interface indexes are
-// re-resolved locally by name, they never match across peers.
+// re-resolved locally by name, they never match across peers, and a
+// redundant group's circuits go to this node's active member rather
+// than the peer's.
 func (c *Component) ApplySyncedCircuit(action hapb.SyncAction, cp *hapb.SessionCheckpoint) {
 	if cp == nil || cp.AccessType != "l2gw" {
 		return
@@ -82,7 +84,7 @@ func (c *Component) ApplySyncedCircuit(action hapb.SyncAction, cp *hapb.SessionC
 		return
 	}
 
-	handoffIdx, ok := c.ifMgr.GetSwIfIndex(cp.HandoffInterface)
+	handoffIf, handoffIdx, ok := c.localHandoff(cp.HandoffGroup, cp.HandoffInterface)
 	if !ok {
 		c.logger.Warn("Synced l2gw circuit references unknown handoff interface",
 			"session_id", cp.SessionId, "interface", cp.HandoffInterface)
@@ -115,7 +117,7 @@ func (c *Component) ApplySyncedCircuit(action hapb.SyncAction, cp *hapb.SessionC
 		AccessCVLAN:      uint16(cp.InnerVlan),
 		AccessTPID:       uint16(cp.AccessTpid),
 		HandoffGroup:     cp.HandoffGroup,
-		HandoffInterface: cp.HandoffInterface,
+		HandoffInterface: handoffIf,
 		HandoffIfIndex:   handoffIdx,
 		HandoffSVLAN:     uint16(cp.HandoffSvlan),
 		HandoffCVLAN:     uint16(cp.HandoffCvlan),
@@ -133,7 +135,7 @@ func (c *Component) ApplySyncedCircuit(action hapb.SyncAction, cp *hapb.SessionC
 	if err := c.armPort(accessIdx, cp.AccessInterface); err != nil {
 		c.logger.Warn("Failed to arm access port for synced circuit", "error", err)
 	}
-	if err := c.armPort(handoffIdx, cp.HandoffInterface); err != nil {
+	if err := c.armPort(handoffIdx, handoffIf); err != nil {
 		c.logger.Warn("Failed to arm handoff port for synced circuit", "error", err)
 	}
 
@@ -0,0 +1,372 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2gw
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	l2gwcfg "github.com/veesix-networks/osvbng/pkg/config/l2gw"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// handoffState tracks member link state for one redundant handoff
+// group and which member currently carries its circuits.
+type handoffState struct {
+	members    []string
+	revertive  bool
+	up         map[string]bool
+	active     string
+	lastChange time.Time
+	failovers  uint64
+}
+
+// selectMember returns the member that should carry the group: the
+// current one while its link holds (unless revertive and a preferred
+// member is back), otherwise the first member with link up. Empty when
+// every member is down.
+func (st *handoffState) selectMember() string {
+	if !st.revertive && st.active != "" && st.up[st.active] {
+		return st.active
+	}
+	for _, m := range st.members {
+		if st.up[m] {
+			return m
+		}
+	}
+	return ""
+}
+
+// SetInterfaceWatch registers the callback that subscribes a handoff
+// member to interface state events. Must be called before Start.
+func (c *Component) SetInterfaceWatch(fn func(swIfIndex uint32)) {
+	c.watchIf = fn
+}
+
+// memberUp reads a member's current admin and link state from the
+// interface manager.
+func (c *Component) memberUp(name string) bool {
+	idx, ok := c.ifMgr.GetSwIfIndex(name)
+	if !ok {
+		return false
+	}
+	iface := c.ifMgr.Get(idx)
+	return iface != nil && iface.AdminUp && iface.LinkUp
+}
+
+// syncHandoffs rebuilds handoff state from config. Link state and the
+// active member carry over for members that remain; new members are
+// read from the interface manager. Returns the redundant groups whose
+// active member changed.
+func (c *Component) syncHandoffs(l2gwCfg *l2gwcfg.L2GWConfig) map[string][2]string {
+	fresh := make(map[string]*handoffState)
+	changed := make(map[string][2]string)
+
+	c.handoffMu.Lock()
+	defer c.handoffMu.Unlock()
+	if l2gwCfg != nil {
+		for name, hg := range l2gwCfg.HandoffGroups {
+			if hg == nil || !hg.Redundant() {
+				continue
+			}
+			prev := c.handoffs[name]
+			st := &handoffState{
+				members:   hg.MemberInterfaces(),
+				revertive: hg.Revertive,
+				up:        make(map[string]bool),
+			}
+			for _, m := range st.members {
+				if prev != nil {
+					if up, ok := prev.up[m]; ok {
+						st.up[m] = up
+						continue
+					}
+				}
+				st.up[m] = c.memberUp(m)
+				if idx, ok := c.ifMgr.GetSwIfIndex(m); ok && c.watchIf != nil {
+					c.watchIf(idx)
+				}
+			}
+			var from string
+			if prev != nil {
+				st.active = prev.active
+				st.lastChange = prev.lastChange
+				st.failovers = prev.failovers
+				from = prev.active
+			}
+			if st.active != "" && !containsMember(st.members, st.active) {
+				st.active = ""
+			}
+			st.active = st.selectMember()
+			if st.active != from {
+				st.lastChange = time.Now()
+				changed[name] = [2]string{from, st.active}
+			}
+			fresh[name] = st
+		}
+	}
+	c.handoffs = fresh
+	return changed
+}
+
+func containsMember(members []string, name string) bool {
+	for _, m := range members {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveHandoff returns the interface that carries a handoff group's
+// circuits right now: the fixed interface, or the active member of a
+// redundant group.
+func (c *Component) resolveHandoff(groupName string, hg *l2gwcfg.HandoffGroup) (string, uint32, error) {
+	name := hg.Interface
+	if hg.Redundant() {
+		c.handoffMu.Lock()
+		st := c.handoffs[groupName]
+		if st != nil {
+			name = st.active
+		}
+		c.handoffMu.Unlock()
+		if name == "" {
+			return "", 0, fmt.Errorf("handoff-group %q: no member with link up", groupName)
+		}
+	}
+	idx, ok := c.ifMgr.GetSwIfIndex(name)
+	if !ok {
+		return "", 0, fmt.Errorf("handoff interface %q not found", name)
+	}
+	return name, idx, nil
+}
+
+// localHandoff maps a handoff interface recorded elsewhere (opdb
+// checkpoint or HA peer) onto this node: a redundant group's circuits
+// go to the local active member, anything else keeps its recorded
+// interface.
+func (c *Component) localHandoff(groupName, recorded string) (string, uint32, bool) {
+	if cfg, _ := c.cfgMgr.GetRunning(); cfg != nil && cfg.L2GW != nil {
+		if hg, ok := cfg.L2GW.HandoffGroups[groupName]; ok && hg.Redundant() {
+			if name, idx, err := c.resolveHandoff(groupName, hg); err == nil {
+				return name, idx, true
+			}
+		}
+	}
+	idx, ok := c.ifMgr.GetSwIfIndex(recorded)
+	return recorded, idx, ok
+}
+
+// handleInterfaceState tracks member link changes and fails the
+// affected groups over.
+func (c *Component) handleInterfaceState(evt events.Event) {
+	data, ok := evt.Data.(events.InterfaceStateEvent)
+	if !ok {
+		return
+	}
+	name := data.Name
+	if name == "" {
+		if iface := c.ifMgr.Get(data.SwIfIndex); iface != nil {
+			name = iface.Name
+		}
+	}
+	if name == "" {
+		return
+	}
+	up := data.AdminUp && data.LinkUp && !data.Deleted
+
+	type move struct{ group, from, to string }
+	var moves []move
+	c.handoffMu.Lock()
+	for group, st := range c.handoffs {
+		prev, member := st.up[name]
+		if !member || prev == up {
+			continue
+		}
+		st.up[name] = up
+		from := st.active
+		st.active = st.selectMember()
+		if st.active != from {
+			st.lastChange = time.Now()
+			moves = append(moves, move{group, from, st.active})
+		}
+	}
+	c.handoffMu.Unlock()
+
+	reason := "link-down " + name
+	if up {
+		reason = "link-up " + name
+	}
+	for _, m := range moves {
+		c.failoverGroup(m.group, m.from, m.to, reason)
+	}
+}
+
+// failoverGroup moves every circuit of a handoff group onto member `to`
+// in one dataplane batch. Standby circuits move too, still disabled, so
+// SRG promotion stays a state flip. With no member left the circuits
+// stay put and the event reports an empty To.
+func (c *Component) failoverGroup(group, from, to, reason string) {
+	c.failoverMu.Lock()
+	defer c.failoverMu.Unlock()
+
+	evt := &events.L2GWHandoffFailoverEvent{Group: group, From: from, To: to, Reason: reason}
+	defer func() {
+		c.eventBus.Publish(events.TopicL2GWHandoffFailover, events.Event{
+			Source: c.Name(),
+			Data:   evt,
+		})
+	}()
+
+	if to == "" {
+		c.logger.Error("Every l2gw handoff member is down; circuits left in place",
+			"handoff_group", group, "last_member", from, "reason", reason)
+		return
+	}
+	toIdx, ok := c.ifMgr.GetSwIfIndex(to)
+	if !ok {
+		c.logger.Error("l2gw handoff member not found", "handoff_group", group, "interface", to)
+		return
+	}
+	if err := c.armPort(toIdx, to); err != nil {
+		c.logger.Error("Failed to arm l2gw handoff member", "interface", to, "error", err)
+		return
+	}
+
+	var cts []*Circuit
+	var batch []southbound.L2GWCircuit
+	c.circuits.Range(func(_, v any) bool {
+		ct := v.(*Circuit)
+		ct.mu.Lock()
+		defer ct.mu.Unlock()
+		if ct.HandoffGroup != group || ct.HandoffIfIndex == toIdx ||
+			ct.State == circuitStateAuthenticating || ct.State == circuitStateRejected {
+			return true
+		}
+		cts = append(cts, ct)
+		batch = append(batch, southbound.L2GWCircuit{
+			AccessIfIndex:  ct.AccessIfIndex,
+			AccessSVLAN:    ct.AccessSVLAN,
+			AccessCVLAN:    ct.AccessCVLAN,
+			AccessTPID:     ct.AccessTPID,
+			HandoffIfIndex: ct.HandoffIfIndex,
+			HandoffSVLAN:   ct.HandoffSVLAN,
+			HandoffCVLAN:   ct.HandoffCVLAN,
+			HandoffTPID:    ct.HandoffTPID,
+			Transparent:    ct.Transparent,
+			Enabled:        !ct.Standby,
+		})
+		return true
+	})
+
+	if len(batch) > 0 {
+		results := c.vpp.MoveL2GWCircuits(batch, toIdx)
+		for i, res := range results {
+			ct := cts[i]
+			if res.Err == nil {
+				ct.mu.Lock()
+				ct.HandoffInterface = to
+				ct.HandoffIfIndex = toIdx
+				ct.CircuitID = res.CircuitID
+				ct.AccessEntryIndex = res.CircuitID
+				ct.HandoffEntryIndex = res.HandoffEntryIndex
+				ct.mu.Unlock()
+				c.checkpointCircuit(ct)
+				evt.Moved++
+				continue
+			}
+			evt.Failed++
+			c.logger.Error("Failed to move l2gw circuit to handoff member",
+				"circuit_id", ct.CircuitID, "handoff_group", group, "interface", to, "error", res.Err)
+			if res.Removed {
+				c.dropMovedCircuit(ct)
+			}
+		}
+	}
+
+	c.handoffMu.Lock()
+	if st := c.handoffs[group]; st != nil {
+		st.failovers++
+	}
+	c.handoffMu.Unlock()
+
+	c.logger.Warn("l2gw handoff group failed over",
+		"handoff_group", group, "from", from, "to", to, "reason", reason,
+		"moved", evt.Moved, "failed", evt.Failed)
+}
+
+// dropMovedCircuit cleans up after a move that deleted the circuit but
+// could not re-add it. A static circuit comes back on the next config
+// reconcile; a dynamic one is torn down and re-triggers.
+func (c *Component) dropMovedCircuit(ct *Circuit) {
+	if ct.Static {
+		c.circuits.Delete(ct.key())
+		return
+	}
+	c.teardownCircuit(ct, "handoff failover")
+}
+
+// HandoffMemberStatus is one member of a redundant handoff group.
+type HandoffMemberStatus struct {
+	Interface string `json:"interface"`
+	Up        bool   `json:"up"`
+	Active    bool   `json:"active"`
+}
+
+// HandoffSummary is the show/API projection of one redundant handoff
+// group, exported through telemetry as l2gw.handoff.*.
+type HandoffSummary struct {
+	Group      string                `json:"group"                 metric:"label"`
+	Active     string                `json:"active,omitempty"`
+	Revertive  bool                  `json:"revertive,omitempty"`
+	Members    []HandoffMemberStatus `json:"members"`
+	LastChange time.Time             `json:"last_change,omitempty"`
+
+	MembersUp int    `json:"members_up" metric:"name=l2gw.handoff.members_up,type=gauge,help=Handoff group members with link up."`
+	Circuits  int    `json:"circuits"   metric:"name=l2gw.handoff.circuits,type=gauge,help=Circuits carried by the handoff group."`
+	Failovers uint64 `json:"failovers"  metric:"name=l2gw.handoff.failovers,type=counter,help=Handoff group member changes."`
+}
+
+// SnapshotHandoffs returns the state of every redundant handoff group,
+// sorted by name.
+func (c *Component) SnapshotHandoffs() []HandoffSummary {
+	counts := make(map[string]int)
+	c.circuits.Range(func(_, v any) bool {
+		ct := v.(*Circuit)
+		ct.mu.Lock()
+		counts[ct.HandoffGroup]++
+		ct.mu.Unlock()
+		return true
+	})
+
+	c.handoffMu.Lock()
+	defer c.handoffMu.Unlock()
+	out := make([]HandoffSummary, 0, len(c.handoffs))
+	for name, st := range c.handoffs {
+		s := HandoffSummary{
+			Group:      name,
+			Active:     st.active,
+			Revertive:  st.revertive,
+			LastChange: st.lastChange,
+			Circuits:   counts[name],
+			Failovers:  st.failovers,
+		}
+		for _, m := range st.members {
+			s.Members = append(s.Members, HandoffMemberStatus{
+				Interface: m,
+				Up:        st.up[m],
+				Active:    m == st.active,
+			})
+			if st.up[m] {
+				s.MembersUp++
+			}
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Group < out[j].Group })
+	return out
+}
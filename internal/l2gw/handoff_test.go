@@ -0,0 +1,135 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2gw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	l2gwcfg "github.com/veesix-networks/osvbng/pkg/config/l2gw"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/ifmgr"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// moveRecorder is the slice of the southbound failover touches.
+type moveRecorder struct {
+	southbound.Southbound
+	moved []southbound.L2GWCircuit
+	to    uint32
+}
+
+func (m *moveRecorder) L2GWEnableInput(string, bool) error { return nil }
+
+func (m *moveRecorder) MoveL2GWCircuits(circuits []southbound.L2GWCircuit, handoffIfIndex uint32) []southbound.L2GWMoveResult {
+	m.moved = append(m.moved, circuits...)
+	m.to = handoffIfIndex
+	out := make([]southbound.L2GWMoveResult, len(circuits))
+	for i := range out {
+		out[i] = southbound.L2GWMoveResult{CircuitID: uint32(100 + i), HandoffEntryIndex: uint32(200 + i)}
+	}
+	return out
+}
+
+func TestSelectMember(t *testing.T) {
+	st := &handoffState{
+		members: []string{"eth3", "eth4"},
+		up:      map[string]bool{"eth3": true, "eth4": true},
+	}
+	if got := st.selectMember(); got != "eth3" {
+		t.Fatalf("initial: got %q, want eth3", got)
+	}
+
+	st.active = "eth3"
+	st.up["eth3"] = false
+	st.active = st.selectMember()
+	if st.active != "eth4" {
+		t.Fatalf("primary down: got %q, want eth4", st.active)
+	}
+
+	st.up["eth3"] = true
+	if got := st.selectMember(); got != "eth4" {
+		t.Fatalf("non-revertive: got %q, want to stay on eth4", got)
+	}
+	st.revertive = true
+	if got := st.selectMember(); got != "eth3" {
+		t.Fatalf("revertive: got %q, want eth3", got)
+	}
+
+	st.up["eth3"], st.up["eth4"] = false, false
+	if got := st.selectMember(); got != "" {
+		t.Fatalf("all down: got %q, want none", got)
+	}
+}
+
+func TestLinkDownMovesGroupCircuits(t *testing.T) {
+	ifMgr := ifmgr.New()
+	ifMgr.Add(&ifmgr.Interface{SwIfIndex: 1, SupSwIfIndex: 1, Name: "eth1", AdminUp: true, LinkUp: true})
+	ifMgr.Add(&ifmgr.Interface{SwIfIndex: 3, SupSwIfIndex: 3, Name: "eth3", AdminUp: true, LinkUp: true})
+	ifMgr.Add(&ifmgr.Interface{SwIfIndex: 4, SupSwIfIndex: 4, Name: "eth4", AdminUp: true, LinkUp: true})
+
+	bus := local.NewBus()
+	failovers := make(chan events.Event, 4)
+	bus.Subscribe(events.TopicL2GWHandoffFailover, func(ev events.Event) { failovers <- ev })
+
+	sb := &moveRecorder{}
+	var watched []uint32
+	c := &Component{
+		Base:       component.NewBase("l2gw"),
+		logger:     logger.Get(logger.L2GW),
+		eventBus:   bus,
+		vpp:        sb,
+		ifMgr:      ifMgr,
+		armedPorts: make(map[uint32]bool),
+		handoffs:   make(map[string]*handoffState),
+		watchIf:    func(idx uint32) { watched = append(watched, idx) },
+	}
+	c.syncHandoffs(&l2gwcfg.L2GWConfig{HandoffGroups: map[string]*l2gwcfg.HandoffGroup{
+		"isp-blue": {Members: []l2gwcfg.HandoffMember{{Interface: "eth3"}, {Interface: "eth4"}}, SVLAN: 400},
+		"isp-red":  {Interface: "eth3", SVLAN: 500},
+	}})
+	if len(watched) != 2 {
+		t.Fatalf("watched %v, want both members", watched)
+	}
+
+	blue := &Circuit{AccessInterface: "eth1", AccessIfIndex: 1, AccessSVLAN: 10, AccessCVLAN: 20,
+		HandoffGroup: "isp-blue", HandoffInterface: "eth3", HandoffIfIndex: 3, HandoffSVLAN: 400, HandoffCVLAN: 7,
+		Static: true, State: circuitStateInstalled, CircuitID: 5}
+	red := &Circuit{AccessInterface: "eth1", AccessIfIndex: 1, AccessSVLAN: 11, AccessCVLAN: 20,
+		HandoffGroup: "isp-red", HandoffInterface: "eth3", HandoffIfIndex: 3, HandoffSVLAN: 500, HandoffCVLAN: 7,
+		Static: true, State: circuitStateInstalled, CircuitID: 6}
+	c.circuits.Store(blue.key(), blue)
+	c.circuits.Store(red.key(), red)
+
+	c.handleInterfaceState(events.Event{Data: events.InterfaceStateEvent{SwIfIndex: 3, Name: "eth3", AdminUp: true}})
+
+	if len(sb.moved) != 1 || sb.to != 4 || sb.moved[0].HandoffIfIndex != 3 {
+		t.Fatalf("moves %+v to %d, want the isp-blue circuit off eth3 onto eth4", sb.moved, sb.to)
+	}
+	if blue.HandoffInterface != "eth4" || blue.HandoffIfIndex != 4 || blue.CircuitID != 100 || blue.HandoffEntryIndex != 200 {
+		t.Fatalf("moved circuit not updated: %+v", blue)
+	}
+	if red.HandoffInterface != "eth3" {
+		t.Fatal("single-interface group must not move")
+	}
+
+	select {
+	case ev := <-failovers:
+		data := ev.Data.(*events.L2GWHandoffFailoverEvent)
+		if data.Group != "isp-blue" || data.From != "eth3" || data.To != "eth4" || data.Moved != 1 {
+			t.Fatalf("failover event: %+v", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no failover event")
+	}
+
+	snap := c.SnapshotHandoffs()
+	if len(snap) != 1 || snap[0].Active != "eth4" || snap[0].MembersUp != 1 || snap[0].Failovers != 1 {
+		t.Fatalf("snapshot: %+v", snap)
+	}
+}
@@ -19,18 +19,26 @@ import (
 // allocators are rebuilt from the new ranges with live circuits' pairs
 // re-marked. Installed dynamic circuits are deliberately left alone,
 // they are session state, torn down via RADIUS Disconnect or operator
-// action, not config edits.
+// action, not config edits. The exception is a redundant handoff group
+// whose active member changes: its circuits move with it.
 func (c *Component) ReconcileConfig(cfg *config.Config) error {
 	if cfg == nil {
 		return nil
 	}
 
+	moved := c.syncHandoffs(cfg.L2GW)
 	c.rebuildAllocators(cfg.L2GW)
 
 	if err := c.reconcileStaticMaps(cfg.L2GW); err != nil {
 		return err
 	}
 
+	// A member list edit can change a redundant group's active member;
+	// its dynamic circuits follow.
+	for group, m := range moved {
+		c.failoverGroup(group, m[0], m[1], "config")
+	}
+
 	if cfg.SubscriberGroups != nil {
 		seen := make(map[string]bool)
 		for _, group := range cfg.SubscriberGroups.Groups {
@@ -150,9 +158,9 @@ func (c *Component) expandStaticMap(l2gwCfg *l2gwcfg.L2GWConfig, sm *l2gwcfg.Sta
 	if !ok {
 		return nil, nil, fmt.Errorf("access interface %q not found", sm.AccessInterface)
 	}
-	handoffIdx, ok := c.ifMgr.GetSwIfIndex(hg.Interface)
-	if !ok {
-		return nil, nil, fmt.Errorf("handoff interface %q not found", hg.Interface)
+	_, handoffIdx, err := c.resolveHandoff(sm.HandoffGroup, hg)
+	if err != nil {
+		return nil, nil, err
 	}
 	svlans, err := sm.GetSVLANs()
 	if err != nil {
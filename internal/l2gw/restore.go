@@ -32,11 +32,13 @@ func (c *Component) restoreCircuits(ctx context.Context) error {
 		}
 
 		// Interface indexes may have drifted across a VPP restart,
-		// re-resolve by name before touching the dataplane.
+		// re-resolve by name before touching the dataplane. A
+		// redundant group's circuits land on its current member.
 		if idx, ok := c.ifMgr.GetSwIfIndex(ct.AccessInterface); ok {
 			ct.AccessIfIndex = idx
 		}
-		if idx, ok := c.ifMgr.GetSwIfIndex(ct.HandoffInterface); ok {
+		if name, idx, ok := c.localHandoff(ct.HandoffGroup, ct.HandoffInterface); ok {
+			ct.HandoffInterface = name
 			ct.HandoffIfIndex = idx
 		}
 
@@ -51,9 +51,9 @@ func (c *Component) applyStaticMap(l2gwCfg *l2gwcfg.L2GWConfig, sm *l2gwcfg.Stat
 	if !ok {
 		return fmt.Errorf("access interface %q not found", sm.AccessInterface)
 	}
-	handoffIdx, ok := c.ifMgr.GetSwIfIndex(hg.Interface)
-	if !ok {
-		return fmt.Errorf("handoff interface %q not found", hg.Interface)
+	handoffIf, handoffIdx, err := c.resolveHandoff(sm.HandoffGroup, hg)
+	if err != nil {
+		return err
 	}
 
 	svlans, err := sm.GetSVLANs()
@@ -78,7 +78,7 @@ func (c *Component) applyStaticMap(l2gwCfg *l2gwcfg.L2GWConfig, sm *l2gwcfg.Stat
 	if err := c.armPort(accessIdx, sm.AccessInterface); err != nil {
 		return fmt.Errorf("arm access port: %w", err)
 	}
-	if err := c.armPort(handoffIdx, hg.Interface); err != nil {
+	if err := c.armPort(handoffIdx, handoffIf); err != nil {
 		return fmt.Errorf("arm handoff port: %w", err)
 	}
 
@@ -99,7 +99,7 @@ func (c *Component) applyStaticMap(l2gwCfg *l2gwcfg.L2GWConfig, sm *l2gwcfg.Stat
 			AccessSVLAN:      svlan,
 			AccessCVLAN:      southbound.L2GWCvlanAny,
 			HandoffGroup:     sm.HandoffGroup,
-			HandoffInterface: hg.Interface,
+			HandoffInterface: handoffIf,
 			HandoffIfIndex:   handoffIdx,
 			HandoffSVLAN:     hs,
 			HandoffCVLAN:     southbound.L2GWCvlanAny,
@@ -337,9 +337,9 @@ func (c *Component) resolveAndInstall(ct *Circuit, attrs map[string]string) erro
 	if !ok {
 		return fmt.Errorf("unknown handoff-group %q", groupName)
 	}
-	handoffIdx, ok := c.ifMgr.GetSwIfIndex(hg.Interface)
-	if !ok {
-		return fmt.Errorf("handoff interface %q not found", hg.Interface)
+	handoffIf, handoffIdx, err := c.resolveHandoff(groupName, hg)
+	if err != nil {
+		return err
 	}
 
 	var svlan, cvlan uint16
@@ -379,13 +379,13 @@ func (c *Component) resolveAndInstall(ct *Circuit, attrs map[string]string) erro
 	if err := c.armPort(ct.AccessIfIndex, ct.AccessInterface); err != nil {
 		return fmt.Errorf("arm access port: %w", err)
 	}
-	if err := c.armPort(handoffIdx, hg.Interface); err != nil {
+	if err := c.armPort(handoffIdx, handoffIf); err != nil {
 		return fmt.Errorf("arm handoff port: %w", err)
 	}
 
 	ct.mu.Lock()
 	ct.HandoffGroup = groupName
-	ct.HandoffInterface = hg.Interface
+	ct.HandoffInterface = handoffIf
 	ct.HandoffIfIndex = handoffIdx
 	ct.HandoffSVLAN = svlan
 	ct.HandoffCVLAN = cvlan
@@ -417,7 +417,7 @@ func (c *Component) resolveAndInstall(ct *Circuit, attrs map[string]string) erro
 	c.logger.Info("l2gw circuit installed",
 		"session_id", ct.SessionID, "mac", ct.MAC,
 		"access", fmt.Sprintf("%s s%d c%d", ct.AccessInterface, ct.AccessSVLAN, ct.AccessCVLAN),
-		"handoff", fmt.Sprintf("%s[%s] s%d c%d", groupName, hg.Interface, svlan, cvlan),
+		"handoff", fmt.Sprintf("%s[%s] s%d c%d", groupName, handoffIf, svlan, cvlan),
 		"circuit_id", ct.CircuitID)
 
 	c.eventBus.Publish(events.TopicSessionLifecycle, events.Event{
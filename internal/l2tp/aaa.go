@@ -39,16 +39,23 @@ type TunnelSpec struct {
 // `<base>:<tag>` (e.g. "tunnel.server-endpoint:1"). Untagged values
 // land in the tag-0 group, which RFC 2868 §3.4 defines as the
 // "default" tag.
+//
+// A tag group whose Tunnel-Type is present and not L2TP is dropped.
+// Tunnel-Medium-Type selects the address family of the endpoints
+// (RFC 2868 §3.2: IPv4 or IPv6); a group whose server endpoint does
+// not parse as an address of that family is dropped. When the medium
+// type is absent the family is taken from the endpoint itself.
 func ParseTunnelSpecs(attrs map[string]string) []TunnelSpec {
 	if len(attrs) == 0 {
 		return nil
 	}
 
 	byTag := map[uint8]*TunnelSpec{}
+	tunnelType := map[uint8]string{}
+	mediumType := map[uint8]string{}
 
 	get := func(k string) *TunnelSpec {
-		base, tag := splitTag(k)
-		_ = base
+		_, tag := splitTag(k)
 		s, ok := byTag[tag]
 		if !ok {
 			s = &TunnelSpec{Tag: tag, PPPHdrSkip: 2}
@@ -58,20 +65,16 @@ func ParseTunnelSpecs(attrs map[string]string) []TunnelSpec {
 	}
 
 	for k, v := range attrs {
-		base, _ := splitTag(k)
+		base, tag := splitTag(k)
 		switch base {
 		case aaa.AttrTunnelType:
-			if !strings.EqualFold(v, "L2TP") {
-				continue
-			}
+			tunnelType[tag] = v
 		case aaa.AttrTunnelMediumType:
-			if !strings.EqualFold(v, "IPv4") {
-				continue
-			}
+			mediumType[tag] = v
 		case aaa.AttrTunnelServerEndpoint:
-			get(k).ServerIP = net.ParseIP(v)
+			get(k).ServerIP = parseTunnelEndpoint(v)
 		case aaa.AttrTunnelClientEndpoint:
-			get(k).ClientIP = net.ParseIP(v)
+			get(k).ClientIP = parseTunnelEndpoint(v)
 		case aaa.AttrTunnelPassword:
 			get(k).Password = v
 		case aaa.AttrTunnelAssignmentID:
@@ -88,18 +91,47 @@ func ParseTunnelSpecs(attrs map[string]string) []TunnelSpec {
 	}
 
 	out := make([]TunnelSpec, 0, len(byTag))
-	for _, s := range byTag {
+	for tag, s := range byTag {
 		if s.ServerIP == nil {
 			continue
 		}
+		if tt, ok := tunnelType[tag]; ok && !strings.EqualFold(tt, "L2TP") {
+			continue
+		}
+		if mt, ok := mediumType[tag]; ok {
+			wantV4 := strings.EqualFold(mt, "IPv4")
+			if !wantV4 && !strings.EqualFold(mt, "IPv6") {
+				continue
+			}
+			if (s.ServerIP.To4() != nil) != wantV4 {
+				continue
+			}
+		}
+		// A client endpoint of the other family cannot source this
+		// tunnel; drop it so the LAC falls back to the pool config.
+		if s.ClientIP != nil && (s.ClientIP.To4() != nil) != (s.ServerIP.To4() != nil) {
+			s.ClientIP = nil
+		}
 		out = append(out, *s)
 	}
 	sort.SliceStable(out, func(i, j int) bool {
-		return out[i].Preference < out[j].Preference
+		if out[i].Preference != out[j].Preference {
+			return out[i].Preference < out[j].Preference
+		}
+		return out[i].Tag < out[j].Tag
 	})
 	return out
 }
 
+// parseTunnelEndpoint parses a Tunnel-*-Endpoint value. RFC 3162 and
+// common RADIUS dictionaries allow bracketed IPv6 literals
+// ("[2001:db8::1]"); both forms are accepted.
+func parseTunnelEndpoint(v string) net.IP {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	return net.ParseIP(v)
+}
+
 // splitTag extracts the base attribute name and tag value from a
 // possibly-tagged key. "tunnel.server-endpoint:5" → ("tunnel.server-endpoint", 5).
 // Unrecognised tag values default to 0.
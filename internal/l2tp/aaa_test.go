@@ -0,0 +1,71 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+)
+
+func TestParseTunnelSpecsMixedFamilies(t *testing.T) {
+	specs := ParseTunnelSpecs(map[string]string{
+		aaa.AttrTunnelType + ":1":           "L2TP",
+		aaa.AttrTunnelMediumType + ":1":     "IPv4",
+		aaa.AttrTunnelServerEndpoint + ":1": "192.0.2.10",
+		aaa.AttrTunnelPreference + ":1":     "20",
+		aaa.AttrTunnelType + ":2":           "L2TP",
+		aaa.AttrTunnelMediumType + ":2":     "IPv6",
+		aaa.AttrTunnelServerEndpoint + ":2": "[2001:db8::10]",
+		aaa.AttrTunnelClientEndpoint + ":2": "2001:db8::1",
+		aaa.AttrTunnelPreference + ":2":     "10",
+	})
+	if len(specs) != 2 {
+		t.Fatalf("got %d specs, want 2", len(specs))
+	}
+	if !specs[0].ServerIP.Equal(net.ParseIP("2001:db8::10")) {
+		t.Fatalf("first spec = %s, want the IPv6 LNS (lower preference)", specs[0].ServerIP)
+	}
+	if !specs[0].ClientIP.Equal(net.ParseIP("2001:db8::1")) {
+		t.Fatalf("IPv6 client endpoint = %v", specs[0].ClientIP)
+	}
+	if !specs[1].ServerIP.Equal(net.ParseIP("192.0.2.10")) {
+		t.Fatalf("second spec = %s, want the IPv4 LNS", specs[1].ServerIP)
+	}
+}
+
+func TestParseTunnelSpecsMediumMismatchDropped(t *testing.T) {
+	specs := ParseTunnelSpecs(map[string]string{
+		aaa.AttrTunnelMediumType:     "IPv4",
+		aaa.AttrTunnelServerEndpoint: "2001:db8::10",
+	})
+	if len(specs) != 0 {
+		t.Fatalf("got %d specs, want 0 for IPv6 endpoint under IPv4 medium", len(specs))
+	}
+}
+
+func TestParseTunnelSpecsNonL2TPDropped(t *testing.T) {
+	specs := ParseTunnelSpecs(map[string]string{
+		aaa.AttrTunnelType:           "PPTP",
+		aaa.AttrTunnelServerEndpoint: "192.0.2.10",
+	})
+	if len(specs) != 0 {
+		t.Fatalf("got %d specs, want 0 for non-L2TP tunnel type", len(specs))
+	}
+}
+
+func TestParseTunnelSpecsClientFamilyMismatchCleared(t *testing.T) {
+	specs := ParseTunnelSpecs(map[string]string{
+		aaa.AttrTunnelServerEndpoint: "2001:db8::10",
+		aaa.AttrTunnelClientEndpoint: "192.0.2.1",
+	})
+	if len(specs) != 1 {
+		t.Fatalf("got %d specs, want 1", len(specs))
+	}
+	if specs[0].ClientIP != nil {
+		t.Fatalf("ClientIP = %s, want nil for cross-family client endpoint", specs[0].ClientIP)
+	}
+}
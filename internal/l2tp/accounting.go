@@ -0,0 +1,167 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+)
+
+// RFC 2867 tunnel accounting. The component only describes the
+// tunnel or link; the AAA component forwards the record to providers
+// implementing auth.TunnelAccounter.
+
+// tunnelAcctSessionID identifies a tunnel across its accounting
+// records. The creation time keeps IDs unique when a tunnel ID is
+// reused for the same peer.
+func tunnelAcctSessionID(t *Tunnel) string {
+	return fmt.Sprintf("l2tp:%s:%d:%x", t.PeerIP.String(), t.LocalID, t.CreatedAt.Unix())
+}
+
+func tunnelMedium(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// tunnelAcctEvent fills the tunnel-level fields shared by every
+// record. RFC 2868 endpoints are role-relative: the client is the LAC.
+func tunnelAcctEvent(t *Tunnel, status auth.TunnelAcctStatus) *events.L2TPTunnelAccountingEvent {
+	ev := &events.L2TPTunnelAccountingEvent{
+		Status:             string(status),
+		AcctSessionID:      tunnelAcctSessionID(t),
+		TunnelConnectionID: fmt.Sprintf("%d/%d", t.LocalID, t.PeerID),
+		Medium:             tunnelMedium(t.PeerIP),
+	}
+	lac, lns := t.LocalIP, t.PeerIP
+	lacName, lnsName := t.LocalHostname, t.PeerHostname
+	if t.Role == l2tppkt.RoleResponder {
+		lac, lns = lns, lac
+		lacName, lnsName = lnsName, lacName
+	}
+	if lac != nil {
+		ev.ClientEndpoint = lac.String()
+	}
+	if lns != nil {
+		ev.ServerEndpoint = lns.String()
+	}
+	ev.ClientAuthID = lacName
+	ev.ServerAuthID = lnsName
+	return ev
+}
+
+// packetsLost reports control messages the tunnel had to retransmit,
+// the closest the LAC / LNS gets to RFC 2867 Acct-Tunnel-Packets-Lost.
+func packetsLost(t *Tunnel) uint32 {
+	if t.Channel == nil {
+		return 0
+	}
+	return uint32(t.Channel.Stats().Retransmits)
+}
+
+func (c *Component) publishTunnelAccounting(ev *events.L2TPTunnelAccountingEvent) {
+	if c.eventBus == nil {
+		return
+	}
+	c.eventBus.Publish(events.TopicL2TPTunnelAccounting, events.Event{
+		Source:    c.Name(),
+		Timestamp: time.Now(),
+		Data:      ev,
+	})
+}
+
+// accountTunnelStart records a tunnel reaching Established.
+func (c *Component) accountTunnelStart(t *Tunnel) {
+	c.publishTunnelAccounting(tunnelAcctEvent(t, auth.TunnelAcctStart))
+}
+
+// accountTunnelStop records the end of an Established tunnel.
+func (c *Component) accountTunnelStop(t *Tunnel, cause string) {
+	if t.FSM == nil || t.FSM.State() != l2tppkt.TunnelEstablished {
+		return
+	}
+	ev := tunnelAcctEvent(t, auth.TunnelAcctStop)
+	ev.SessionDuration = uint32(time.Since(t.CreatedAt).Seconds())
+	ev.PacketsLost = packetsLost(t)
+	ev.TerminateCause = cause
+	c.publishTunnelAccounting(ev)
+}
+
+// accountTunnelReject records a tunnel that failed setup. Tunnels that
+// were already Established are not rejects; their failure surfaces as
+// a Tunnel-Stop.
+func (c *Component) accountTunnelReject(t *Tunnel, reason error) {
+	if t.FSM != nil && t.FSM.State() == l2tppkt.TunnelEstablished {
+		return
+	}
+	ev := tunnelAcctEvent(t, auth.TunnelAcctReject)
+	ev.PacketsLost = packetsLost(t)
+	ev.TerminateCause = auth.TerminateCauseNASError
+	if reason != nil {
+		ev.Attributes = map[string]string{"reason": reason.Error()}
+	}
+	c.publishTunnelAccounting(ev)
+}
+
+// accountPeerReject records an SCCRQ refused before any tunnel state
+// existed: an unknown or unauthorised LAC.
+func (c *Component) accountPeerReject(localIP, peerIP net.IP, peerHostname string, reason error) {
+	ev := &events.L2TPTunnelAccountingEvent{
+		Status:         string(auth.TunnelAcctReject),
+		AcctSessionID:  fmt.Sprintf("l2tp:%s:0:%x", peerIP.String(), time.Now().Unix()),
+		Medium:         tunnelMedium(peerIP),
+		ClientEndpoint: peerIP.String(),
+		ClientAuthID:   peerHostname,
+		ServerAuthID:   c.localHostname,
+		TerminateCause: auth.TerminateCauseNASError,
+	}
+	if localIP != nil {
+		ev.ServerEndpoint = localIP.String()
+	}
+	if reason != nil {
+		ev.Attributes = map[string]string{"reason": reason.Error()}
+	}
+	c.publishTunnelAccounting(ev)
+}
+
+// linkAcctEvent describes a session inside its tunnel. Called with
+// s.mu held.
+func linkAcctEvent(s *Session, status auth.TunnelAcctStatus) *events.L2TPTunnelAccountingEvent {
+	t := s.Tunnel
+	ev := tunnelAcctEvent(t, status)
+	ev.AcctMultiSessionID = ev.AcctSessionID
+	ev.AcctSessionID = s.AcctSessionID
+	if ev.AcctSessionID == "" {
+		ev.AcctSessionID = s.SessionID
+	}
+	ev.TunnelConnectionID = fmt.Sprintf("%d/%d/%d/%d", t.LocalID, t.PeerID, s.LocalID, s.PeerID)
+	ev.SessionID = s.SessionID
+	ev.Username = s.Username
+	return ev
+}
+
+// accountLinkStart records a session coming up. Called with s.mu held.
+func (c *Component) accountLinkStart(s *Session) {
+	c.publishTunnelAccounting(linkAcctEvent(s, auth.TunnelAcctLinkStart))
+}
+
+// accountLinkStop records a session going down. Sessions that never
+// completed ICCN had no Link-Start and get no Link-Stop. Called with
+// s.mu held.
+func (c *Component) accountLinkStop(s *Session, cause string) {
+	if s.ActivatedAt.IsZero() {
+		return
+	}
+	ev := linkAcctEvent(s, auth.TunnelAcctLinkStop)
+	ev.SessionDuration = uint32(time.Since(s.ActivatedAt).Seconds())
+	ev.TerminateCause = cause
+	c.publishTunnelAccounting(ev)
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+var (
+	ErrTunnelNotFound    = errors.New("l2tp: tunnel not found")
+	ErrSessionNotFound   = errors.New("l2tp: session not found")
+	ErrTunnelStandby     = errors.New("l2tp: tunnel is standby; clear it on the active node")
+	ErrTunnelNotRunning  = errors.New("l2tp: tunnel has no control channel")
+	ErrSessionIDRequired = errors.New("l2tp: session id required")
+)
+
+// ClearTunnel sends StopCCN to the peer and tears the tunnel and every
+// session it carries down locally.
+func (c *Component) ClearTunnel(peerIP net.IP, localID uint16) error {
+	t := c.LookupTunnel(peerIP, localID)
+	if t == nil {
+		return ErrTunnelNotFound
+	}
+	if t.isStandby() {
+		return ErrTunnelStandby
+	}
+	if t.Channel != nil {
+		body := l2tppkt.BuildStopCCN(t.LocalID, l2tppkt.ResultStopGeneralRequest,
+			l2tppkt.ErrorNoGeneralError, "administrative clear")
+		if err := t.Channel.Send(body, time.Now()); err != nil {
+			c.log.Warn("Failed to send StopCCN on clear",
+				"peer_ip", peerIP.String(), "local_tunnel_id", localID, "error", err)
+		}
+	}
+	c.teardownTunnel(t, auth.TerminateCauseAdminReset)
+	c.log.Info("Cleared l2tp tunnel", "peer_ip", peerIP.String(), "local_tunnel_id", localID)
+	return nil
+}
+
+// ClearSession sends CDN for one session and tears it down locally.
+// The tunnel stays up even when it carried the last session.
+func (c *Component) ClearSession(sessionID string) error {
+	if sessionID == "" {
+		return ErrSessionIDRequired
+	}
+	s := c.findSessionByID(sessionID)
+	if s == nil {
+		return ErrSessionNotFound
+	}
+	return c.clearSession(s, auth.TerminateCauseAdminReset)
+}
+
+func (c *Component) clearSession(s *Session, cause string) error {
+	t := s.Tunnel
+	if t.isStandby() {
+		return ErrTunnelStandby
+	}
+	if t.Channel != nil {
+		s.mu.Lock()
+		localID, peerID := s.LocalID, s.PeerID
+		s.mu.Unlock()
+		body := l2tppkt.BuildCDN(localID, l2tppkt.ResultCDNAdministrative,
+			l2tppkt.ErrorNoGeneralError, "administrative clear")
+		if err := t.Channel.SendSession(body, peerID, time.Now()); err != nil {
+			c.log.Warn("Failed to send CDN on clear", "session_id", s.SessionID, "error", err)
+		}
+	}
+	c.teardownSession(s, cause)
+	c.log.Info("Cleared l2tp session", "session_id", s.SessionID)
+	return nil
+}
+
+// SendHello queues a Hello on the tunnel's control channel, outside
+// the regular Hello schedule. The peer's ACK shows up in the channel
+// stats; a peer that never answers trips the retransmit limit.
+func (c *Component) SendHello(peerIP net.IP, localID uint16) error {
+	t := c.LookupTunnel(peerIP, localID)
+	if t == nil {
+		return ErrTunnelNotFound
+	}
+	if t.isStandby() {
+		return ErrTunnelStandby
+	}
+	if t.Channel == nil {
+		return ErrTunnelNotRunning
+	}
+	return t.Channel.Send(l2tppkt.BuildHello(), time.Now())
+}
+
+// teardownSession removes a session from the dataplane and the
+// component. LNS sessions have their service-group bindings unwound
+// before the interface goes; those that went Active publish Released
+// on the lifecycle topic (Accounting-Stop, subscriber cleanup, HA) and
+// give their pool addresses back.
+func (c *Component) teardownSession(s *Session, cause string) {
+	t := s.Tunnel
+	if s.FSM != nil {
+		s.FSM.Disconnect()
+	}
+	if c.vpp != nil {
+		s.mu.Lock()
+		swIfIndex, sg := s.SwIfIndex, s.ServiceGroup
+		lns := s.Role == l2tppkt.SessionRoleLNS
+		s.mu.Unlock()
+		if lns && swIfIndex != 0 {
+			svcgroup.ReverseFromSession(c.vpp, swIfIndex, sg)
+		}
+		if err := c.vpp.DeleteL2TPSession(t.LocalIP, t.PeerIP, t.LocalID, s.LocalID); err != nil {
+			c.log.Debug("DeleteL2TPSession on teardown failed",
+				"session_id", s.SessionID, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	c.stopCHAPRetryTimer(s)
+	if s.Role == l2tppkt.SessionRoleLNS && s.lifecyclePublished {
+		c.publishSessionLifecycle(s, models.SessionStateReleased)
+	}
+	if req := s.lacReq; req != nil {
+		// The PPPoE side is still waiting on this bring-up.
+		s.lacReq = nil
+		c.publishLACDecision(req.PPPoESessionID, nil, nil, ErrLACTunnelClosed)
+	}
+	c.releaseSessionAddresses(s)
+	c.accountLinkStop(s, cause)
+	s.SwIfIndex = 0
+	s.programmedInVPP = false
+	s.mu.Unlock()
+
+	t.removeSession(s.LocalID)
+	c.forgetSession(s)
+}
+
+// releaseSessionAddresses returns pool-allocated addresses. Addresses
+// handed out by AAA were never reserved locally. Called with s.mu held.
+func (c *Component) releaseSessionAddresses(s *Session) {
+	if c.registry == nil {
+		return
+	}
+	if s.allocatedPool != "" && s.IPv4Address != nil {
+		c.registry.Release(s.allocatedPool, s.IPv4Address)
+		s.allocatedPool = ""
+	}
+	if s.allocatedIANAPool != "" && s.IPv6Address != nil {
+		c.registry.ReleaseIANA(s.allocatedIANAPool, s.IPv6Address)
+		s.allocatedIANAPool = ""
+	}
+	if s.allocatedPDPool != "" && s.IPv6Prefix != nil {
+		c.registry.ReleasePD(s.allocatedPDPool, s.IPv6Prefix)
+		s.allocatedPDPool = ""
+	}
+}
+
+// teardownTunnel tears down every session on the tunnel and then the
+// tunnel itself. Must not run on the tunnel's own runner goroutine:
+// stopping the runner waits for it to exit. Only the first call on a
+// tunnel does anything.
+func (c *Component) teardownTunnel(t *Tunnel, cause string) {
+	t.mu.Lock()
+	done := t.tornDown
+	t.tornDown = true
+	if t.recoveryHold != nil {
+		t.recoveryHold.Stop()
+		t.recoveryHold = nil
+	}
+	recovers := t.recovers
+	if t.recovered {
+		recovers = nil
+	}
+	t.mu.Unlock()
+	if done {
+		return
+	}
+	t.finishProbe(ErrProbeClosed)
+
+	for _, s := range t.snapshotSessions() {
+		c.teardownSession(s, cause)
+	}
+	c.accountTunnelStop(t, cause)
+	t.FSM.Stop()
+	c.stopTunnelRunner(t.PeerIP, t.LocalID)
+	c.unregisterTunnel(t.PeerIP, t.LocalID)
+	c.releaseTunnelID(t.PeerIP, t.LocalID)
+	c.uninstallTunnelVPP(t)
+	for _, req := range c.clearLACPending(t.PeerIP, t.LocalID) {
+		c.publishLACDecision(req.PPPoESessionID, nil, nil, ErrLACTunnelClosed)
+	}
+	c.forgetTunnel(t)
+
+	// A recovery tunnel that closes before its tunnel resumed takes
+	// that tunnel along: its control channel cannot be recovered.
+	if recovers != nil {
+		c.teardownTunnel(recovers, auth.TerminateCauseLostCarrier)
+	}
+}
+
+// findSessionByID resolves a BNG-wide session ID (see makeSessionID).
+func (c *Component) findSessionByID(sessionID string) *Session {
+	var found *Session
+	c.eachSession(func(s *Session) bool {
+		if s.SessionID == sessionID {
+			found = s
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// eachSession visits every session on every tunnel, standby included,
+// until fn returns false.
+func (c *Component) eachSession(fn func(*Session) bool) {
+	c.mu.RLock()
+	tunnels := make([]*Tunnel, 0, len(c.tunnels))
+	for _, t := range c.tunnels {
+		tunnels = append(tunnels, t)
+	}
+	c.mu.RUnlock()
+
+	for _, t := range tunnels {
+		for _, s := range t.snapshotSessions() {
+			if !fn(s) {
+				return
+			}
+		}
+	}
+}
+
+// handleSubscriberTerminate clears the LNS session an external
+// terminate request (subscriber.session.clear, CoA Disconnect) names.
+// LAC sessions are accounted on their PPPoE session, which the PPPoE
+// component terminates.
+func (c *Component) handleSubscriberTerminate(ev events.Event) {
+	data, ok := ev.Data.(*events.SubscriberTerminateEvent)
+	if !ok {
+		return
+	}
+
+	var target *Session
+	c.eachSession(func(s *Session) bool {
+		if s.Role != l2tppkt.SessionRoleLNS {
+			return true
+		}
+		s.mu.Lock()
+		match := terminateMatches(s, data)
+		s.mu.Unlock()
+		if match {
+			target = s
+			return false
+		}
+		return true
+	})
+	if target == nil || target.Tunnel.isStandby() {
+		return
+	}
+	if err := c.clearSession(target, auth.TerminateCauseAdminReset); err != nil {
+		c.log.Warn("Failed to terminate l2tp session", "session_id", target.SessionID, "error", err)
+		return
+	}
+	c.log.Debug("Session terminated by external request",
+		"session_id", target.SessionID, "reason", data.Reason)
+}
+
+// terminateMatches applies the same key precedence as the PPPoE
+// component: the first identifier set on the request decides. Called
+// with s.mu held.
+func terminateMatches(s *Session, ev *events.SubscriberTerminateEvent) bool {
+	switch {
+	case ev.SessionID != "":
+		return s.SessionID == ev.SessionID
+	case ev.AcctSessionID != "":
+		return s.AcctSessionID == ev.AcctSessionID
+	case ev.Username != "":
+		return s.Username == ev.Username
+	case ev.FramedIPv4 != "":
+		return s.IPv4Address != nil && s.IPv4Address.String() == ev.FramedIPv4
+	case ev.FramedIPv6 != "":
+		return s.IPv6Address != nil && s.IPv6Address.String() == ev.FramedIPv6
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// establishedLNSTunnel registers an Established LNS tunnel carrying
+// one Active session, with its runner started.
+func establishedLNSTunnel(t *testing.T, c *Component) (*Tunnel, *Session) {
+	t.Helper()
+	peer := net.IPv4(10, 0, 0, 2)
+	tun := &Tunnel{
+		LocalIP:       net.IPv4(10, 0, 0, 1),
+		PeerIP:        peer,
+		LocalID:       3,
+		PeerID:        40,
+		LocalPort:     1701,
+		PeerPort:      1701,
+		Role:          l2tppkt.RoleResponder,
+		FSM:           l2tppkt.RestoreTunnelFSM(l2tppkt.RoleResponder, l2tppkt.TunnelEstablished),
+		LocalHostname: "lns1",
+		PeerHostname:  "lac1",
+		CreatedAt:     time.Now().Add(-time.Minute),
+	}
+	if err := c.registerTunnel(tun); err != nil {
+		t.Fatal(err)
+	}
+	c.startTunnelRunner(tun, time.Minute)
+	s := &Session{
+		SessionID:          makeSessionID(peer, 3, 7),
+		AcctSessionID:      "acct-7",
+		Tunnel:             tun,
+		LocalID:            7,
+		PeerID:             70,
+		Role:               l2tppkt.SessionRoleLNS,
+		FSM:                l2tppkt.RestoreSessionFSM(l2tppkt.SessionRoleLNS, l2tppkt.SessionEstablished),
+		Username:           "alice",
+		ActivatedAt:        time.Now().Add(-30 * time.Second),
+		lifecyclePublished: true,
+	}
+	tun.addSession(s)
+	return tun, s
+}
+
+func collect(bus events.Bus, topic string) <-chan events.Event {
+	ch := make(chan events.Event, 16)
+	bus.Subscribe(topic, func(ev events.Event) { ch <- ev })
+	return ch
+}
+
+func nextEvent(t *testing.T, ch <-chan events.Event) events.Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return events.Event{}
+	}
+}
+
+func TestClearTunnelAccountsAndReleases(t *testing.T) {
+	bus := local.NewBus()
+	defer func() { _ = bus.Close() }()
+	acct := collect(bus, events.TopicL2TPTunnelAccounting)
+	lifecycle := collect(bus, events.TopicSessionLifecycle)
+
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	c.SetEventBus(bus)
+	defer c.Stop(context.Background())
+
+	tun, s := establishedLNSTunnel(t, c)
+	if err := c.ClearTunnel(tun.PeerIP, tun.LocalID); err != nil {
+		t.Fatal(err)
+	}
+	if c.LookupTunnel(tun.PeerIP, tun.LocalID) != nil {
+		t.Fatal("tunnel still registered after clear")
+	}
+
+	var stopCCN bool
+	for _, p := range tr.snapshot() {
+		avps, err := l2tppkt.ParseAVPs(p.body)
+		if err == nil && l2tppkt.DecodeMessageType(avps) == l2tppkt.MsgTypeStopCCN {
+			stopCCN = p.header.TunnelID == 40
+		}
+	}
+	if !stopCCN {
+		t.Fatal("StopCCN not sent to the peer tunnel")
+	}
+
+	records := map[string]*events.L2TPTunnelAccountingEvent{}
+	for i := 0; i < 2; i++ {
+		rec := nextEvent(t, acct).Data.(*events.L2TPTunnelAccountingEvent)
+		records[rec.Status] = rec
+	}
+	link, stop := records[string(auth.TunnelAcctLinkStop)], records[string(auth.TunnelAcctStop)]
+	if link == nil || link.AcctSessionID != "acct-7" || link.Username != "alice" ||
+		link.TerminateCause != auth.TerminateCauseAdminReset {
+		t.Fatalf("link stop record: %+v", link)
+	}
+	if stop == nil || stop.AcctSessionID != link.AcctMultiSessionID {
+		t.Fatalf("tunnel stop record: %+v", stop)
+	}
+	// RFC 2868 endpoints: the LAC is the client even on the LNS.
+	if stop.ClientEndpoint != "10.0.0.2" || stop.ServerEndpoint != "10.0.0.1" ||
+		stop.ClientAuthID != "lac1" || stop.ServerAuthID != "lns1" || stop.Medium != "ipv4" {
+		t.Fatalf("tunnel stop endpoints: %+v", stop)
+	}
+
+	rel := nextEvent(t, lifecycle).Data.(*events.SessionLifecycleEvent)
+	if rel.SessionID != s.SessionID || rel.State != models.SessionStateReleased {
+		t.Fatalf("lifecycle: %+v", rel)
+	}
+}
+
+func TestClearSessionKeepsTunnel(t *testing.T) {
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	defer c.Stop(context.Background())
+
+	tun, s := establishedLNSTunnel(t, c)
+	if err := c.ClearSession("nope"); err != ErrSessionNotFound {
+		t.Fatalf("unknown session: %v", err)
+	}
+	if err := c.ClearSession(s.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if c.LookupSession(tun.PeerIP, tun.LocalID, s.LocalID) != nil {
+		t.Fatal("session still registered after clear")
+	}
+	if c.LookupTunnel(tun.PeerIP, tun.LocalID) == nil {
+		t.Fatal("clearing the last session must not drop the tunnel")
+	}
+
+	pkts := tr.snapshot()
+	if len(pkts) == 0 {
+		t.Fatal("no CDN sent")
+	}
+	last := pkts[len(pkts)-1]
+	avps, err := l2tppkt.ParseAVPs(last.body)
+	if err != nil || l2tppkt.DecodeMessageType(avps) != l2tppkt.MsgTypeCDN || last.header.SessionID != 70 {
+		t.Fatalf("want CDN to peer session 70, got type=%d sid=%d", l2tppkt.DecodeMessageType(avps), last.header.SessionID)
+	}
+	if st := tun.Channel.Stats(); st.Sent != 1 {
+		t.Fatalf("control stats: %+v", st)
+	}
+}
@@ -9,14 +9,17 @@ import (
 	"errors"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/component"
 	"github.com/veesix-networks/osvbng/pkg/dataplane"
 	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/ha"
 	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
 	"github.com/veesix-networks/osvbng/pkg/southbound"
 	"github.com/veesix-networks/osvbng/pkg/svcgroup"
 	"github.com/veesix-networks/osvbng/pkg/vrfmgr"
@@ -68,15 +71,29 @@ type Component struct {
 	vrfMgr           *vrfmgr.Manager
 	svcGroupResolver *svcgroup.Resolver
 	localHostname    string
-
-	aaaRespSub events.Subscription
-
-	// LAC-side state. lacPending holds the in-flight bring-up requests
-	// keyed by tunnel identity; SCCRP and ICRP arrival looks up the
-	// originating LACBringUpRequest to forward proxy-auth AVPs into
-	// ICCN and to address the final TopicL2TPLACDecision event.
+	opdb             opdb.Store
+	srgMgr           ha.SRGProvider
+
+	// failover is the RFC 4951 capability advertised on every tunnel;
+	// nil disables failover, and restored tunnels are then closed.
+	failover *l2tppkt.FailoverCapability
+
+	aaaRespSub   events.Subscription
+	haSub        events.Subscription
+	terminateSub events.Subscription
+
+	// LAC-side state. lacPending holds the bring-up requests queued
+	// on a tunnel still waiting for SCCRP, keyed by tunnel identity;
+	// SCCRP opens a session for each. The session then carries its
+	// request to forward proxy-auth AVPs into ICCN and to address the
+	// final TopicL2TPLACDecision event.
 	lacMu      sync.Mutex
-	lacPending map[tunnelKey]*LACBringUpRequest
+	lacPending map[tunnelKey][]*LACBringUpRequest
+
+	// lnsHealth holds the health-check state of configured LNS
+	// endpoints, keyed by address.
+	healthMu  sync.Mutex
+	lnsHealth map[string]*lnsHealth
 
 	// denylist tracks LNS peers the LAC has temporarily given up on.
 	// Allocated lazily so test setups that never exercise the LAC path
@@ -131,10 +148,24 @@ func (c *Component) SetVRFManager(m *vrfmgr.Manager) { c.vrfMgr = m }
 // LNS path.
 func (c *Component) SetServiceGroupResolver(r *svcgroup.Resolver) { c.svcGroupResolver = r }
 
+// SetOpDB installs the store tunnels and sessions are checkpointed to
+// and restored from on Start.
+func (c *Component) SetOpDB(s opdb.Store) { c.opdb = s }
+
+// SetSRGProvider installs the redundancy-group view used to resolve a
+// session's SRG and to gate standby restore.
+func (c *Component) SetSRGProvider(p ha.SRGProvider) { c.srgMgr = p }
+
 // SetLocalHostname records the LNS hostname used as the CHAP challenge
 // name and as the default Host Name in outbound SCCRQ.
 func (c *Component) SetLocalHostname(h string) { c.localHostname = h }
 
+// SetFailover enables RFC 4951 control-channel failover, advertising
+// recoveryTime as the time this node needs to recover a tunnel.
+func (c *Component) SetFailover(recoveryTime time.Duration) {
+	c.failover = &l2tppkt.FailoverCapability{ControlChannel: true, RecoveryTime: recoveryTime}
+}
+
 type tunnelKey struct {
 	peerIP [16]byte
 	id     uint16
@@ -166,12 +197,22 @@ func New(log *logger.Logger) *Component {
 // (operational CLI, HA restore) can inspect or seed entries.
 func (c *Component) Denylist() *PeerDenylist { return c.denylist }
 
-// Start brings the component up. Launches the punt-channel consumer
-// if a channel has been installed via SetPuntChannel and subscribes to
-// AAA response traffic if an event bus is wired.
+// Start brings the component up. Restores checkpointed tunnels and
+// sessions, launches the punt-channel consumer if a channel has been
+// installed via SetPuntChannel, starts LNS health checking when a
+// config manager is wired, and subscribes to AAA response and HA
+// state traffic if an event bus is wired.
 func (c *Component) Start(ctx context.Context) error {
 	runCtx, cancel := context.WithCancel(ctx)
 	c.cancel = cancel
+
+	if err := c.restoreState(ctx); err != nil {
+		c.log.Warn("Failed to restore L2TP state from OpDB", "error", err)
+	}
+	if err := c.restoreSyncedStandby(ctx); err != nil {
+		c.log.Warn("Failed to restore synced L2TP sessions", "error", err)
+	}
+
 	if c.puntCh != nil {
 		c.wg.Add(1)
 		go func() {
@@ -179,8 +220,17 @@ func (c *Component) Start(ctx context.Context) error {
 			c.puntConsumer(runCtx, c.puntCh)
 		}()
 	}
+	if c.cfgMgr != nil && c.send != nil {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.healthLoop(runCtx)
+		}()
+	}
 	if c.eventBus != nil {
 		c.aaaRespSub = c.eventBus.Subscribe(events.TopicAAAResponseL2TP, c.handleAAAResponse)
+		c.haSub = c.eventBus.Subscribe(events.TopicHAStateChange, c.handleHAStateChange)
+		c.terminateSub = c.eventBus.Subscribe(events.TopicSubscriberTerminate, c.handleSubscriberTerminate)
 	}
 	return nil
 }
@@ -197,6 +247,14 @@ func (c *Component) Stop(ctx context.Context) error {
 		c.aaaRespSub.Unsubscribe()
 		c.aaaRespSub = nil
 	}
+	if c.haSub != nil {
+		c.haSub.Unsubscribe()
+		c.haSub = nil
+	}
+	if c.terminateSub != nil {
+		c.terminateSub.Unsubscribe()
+		c.terminateSub = nil
+	}
 
 	c.mu.Lock()
 	runners := c.runners
@@ -229,11 +287,43 @@ func (c *Component) LookupTunnel(peerIP net.IP, localTunnelID uint16) *Tunnel {
 // whatever the L2TP component currently knows rather than a stale
 // checkpointed sw_if_index.
 //
+// pppoeSwIfIndex is the PPPoE session's current sw_if_index. When it
+// differs from the one the L2TP session was installed with (dataplane
+// restart, HA takeover) the L2TP session is re-installed so the
+// LNS→subscriber direction reaches the right PPPoE interface.
+//
 // Returns (sw_if_index, true) on hit. Returns (0, false) when no tunnel
 // or session matches the IDs — caller should leave the PPPoE session in
 // PhaseLACTunnelPending and wait for the L2TP tunnel to come back up
 // before retrying.
-func (c *Component) ResolveLACSessionIndex(localTunnelID, localSessionID uint16) (uint32, bool) {
+func (c *Component) ResolveLACSessionIndex(localTunnelID, localSessionID uint16, pppoeSwIfIndex uint32) (uint32, bool) {
+	s := c.findSessionByLocalIDs(localTunnelID, localSessionID)
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	idx, bound := s.SwIfIndex, s.PPPoESwIfIndex
+	s.mu.Unlock()
+
+	if pppoeSwIfIndex != 0 && (idx == 0 || bound != pppoeSwIfIndex) {
+		if err := c.installLACSessionVPP(s, pppoeSwIfIndex); err != nil {
+			c.log.Warn("Failed to rebind LAC session to PPPoE interface",
+				"session_id", s.SessionID, "pppoe_sw_if_index", pppoeSwIfIndex, "error", err)
+			return 0, false
+		}
+		s.mu.Lock()
+		idx = s.SwIfIndex
+		s.mu.Unlock()
+	}
+	if idx == 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+// findSessionByLocalIDs returns the session with the given local
+// tunnel and session IDs on any peer, or nil.
+func (c *Component) findSessionByLocalIDs(localTunnelID, localSessionID uint16) *Session {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	for _, t := range c.tunnels {
@@ -243,15 +333,11 @@ func (c *Component) ResolveLACSessionIndex(localTunnelID, localSessionID uint16)
 		t.mu.Lock()
 		s := t.Sessions[localSessionID]
 		t.mu.Unlock()
-		if s == nil {
-			continue
-		}
-		if s.SwIfIndex == 0 {
-			return 0, false
+		if s != nil {
+			return s
 		}
-		return s.SwIfIndex, true
 	}
-	return 0, false
+	return nil
 }
 
 // LookupSession returns the session for an incoming data packet, or
@@ -361,6 +447,20 @@ func (c *Component) SnapshotTunnels() []models.L2TPTunnelSummary {
 			State:         state,
 			SessionCount:  len(t.Sessions),
 			CreatedAt:     t.CreatedAt,
+			Standby:       t.standby,
+		}
+		s.Ns, s.Nr = t.sequence()
+		if t.Channel != nil {
+			s.Cwnd = t.Channel.Cwnd()
+			st := t.Channel.Stats()
+			s.Control = models.L2TPControlStats{
+				Sent:        st.Sent,
+				Retransmits: st.Retransmits,
+				Received:    st.Received,
+				Duplicates:  st.Duplicates,
+				ZLBSent:     st.ZLBSent,
+				ZLBReceived: st.ZLBReceived,
+			}
 		}
 		if t.LocalIP != nil {
 			s.LocalIP = t.LocalIP.String()
@@ -374,6 +474,57 @@ func (c *Component) SnapshotTunnels() []models.L2TPTunnelSummary {
 	return out
 }
 
+// SnapshotSessions returns every L2TP session, LAC and LNS, with its
+// tunnel identity. Used by `show l2tp sessions`.
+func (c *Component) SnapshotSessions() []models.L2TPSessionDetail {
+	out := []models.L2TPSessionDetail{}
+	c.eachSession(func(s *Session) bool {
+		t := s.Tunnel
+		standby := t.isStandby()
+		s.mu.Lock()
+		d := models.L2TPSessionDetail{
+			SessionID:      s.SessionID,
+			AcctSessionID:  s.AcctSessionID,
+			Role:           "LAC",
+			Standby:        standby,
+			LocalIP:        t.LocalIP.String(),
+			PeerIP:         t.PeerIP.String(),
+			LocalTunnelID:  t.LocalID,
+			PeerTunnelID:   t.PeerID,
+			LocalSessionID: s.LocalID,
+			PeerSessionID:  s.PeerID,
+			PeerHostname:   t.PeerHostname,
+			Username:       s.Username,
+			VRF:            s.VRF,
+			ServiceGroup:   s.ServiceGroup.Name,
+			SRGName:        s.SRGName,
+			PPPoESessionID: s.PPPoESessionID,
+			IfIndex:        s.SwIfIndex,
+			ActivatedAt:    s.ActivatedAt,
+		}
+		if s.Role == l2tppkt.SessionRoleLNS {
+			d.Role = "LNS"
+			d.PPPPhase = s.Phase.String()
+		}
+		if s.FSM != nil {
+			d.State = s.FSM.State().String()
+		}
+		if s.IPv4Address != nil {
+			d.IPv4Address = s.IPv4Address.String()
+		}
+		if s.IPv6Address != nil {
+			d.IPv6Address = s.IPv6Address.String()
+		}
+		if s.IPv6Prefix != nil {
+			d.IPv6Prefix = s.IPv6Prefix.String()
+		}
+		s.mu.Unlock()
+		out = append(out, d)
+		return true
+	})
+	return out
+}
+
 func (c *Component) releaseTunnelID(peerIP net.IP, id uint16) {
 	c.mu.RLock()
 	alloc := c.tunnelIDs[peerIP.String()]
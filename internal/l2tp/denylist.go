@@ -8,6 +8,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/models"
 )
 
 // PeerDenylist tracks LNS peers the LAC has temporarily given up on.
@@ -120,3 +122,21 @@ func (d *PeerDenylist) Len() int {
 	defer d.mu.Unlock()
 	return len(d.entries)
 }
+
+// Snapshot returns every entry, expired (probe-eligible) ones included.
+func (d *PeerDenylist) Snapshot() []models.L2TPDenylistEntry {
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]models.L2TPDenylistEntry, 0, len(d.entries))
+	for peer, e := range d.entries {
+		out = append(out, models.L2TPDenylistEntry{
+			PeerIP:        peer,
+			Reason:        e.reason,
+			AddedAt:       e.addedAt,
+			ExpiresAt:     e.expires,
+			ProbeEligible: !now.Before(e.expires),
+		})
+	}
+	return out
+}
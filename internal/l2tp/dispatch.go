@@ -7,6 +7,7 @@ package l2tp
 import (
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/dataplane"
@@ -19,10 +20,16 @@ import (
 // and routes to the appropriate handler.
 //
 // The caller (dataplane component) supplies a fully-parsed packet with
-// IPv4 and UDP layers populated; the L2TPv2 payload lives in
-// `pkt.UDP.Payload`.
+// an IPv4 or IPv6 layer and the UDP layer populated; the L2TPv2
+// payload lives in `pkt.UDP.Payload`. Tunnels over both families are
+// served by the same dispatcher — the tunnel lookup key carries the
+// full 16-byte peer address.
 func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
-	if pkt == nil || pkt.UDP == nil || pkt.IPv4 == nil {
+	if pkt == nil || pkt.UDP == nil {
+		return ErrPuntPacketShape
+	}
+	srcIP, dstIP, ok := puntAddrs(pkt)
+	if !ok {
 		return ErrPuntPacketShape
 	}
 	body := pkt.UDP.Payload
@@ -46,7 +53,7 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 		// traffic is intercepted by the VPP dataplane and never
 		// punted. Look up the session and feed the PPP frame to the
 		// session's dispatcher.
-		s := c.LookupSession(pkt.IPv4.SrcIP, h.TunnelID, h.SessionID)
+		s := c.LookupSession(srcIP, h.TunnelID, h.SessionID)
 		if s == nil {
 			return ErrNoSuchSession
 		}
@@ -58,21 +65,32 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 		return fmt.Errorf("l2tp parse avps: %w", err)
 	}
 	msgType := l2tppkt.DecodeMessageType(avps)
-	c.log.Debug("l2tp dispatch", "msg_type", msgType, "tunnel_id", h.TunnelID, "src", pkt.IPv4.SrcIP.String(), "avp_count", len(avps))
+	c.log.Debug("l2tp dispatch", "msg_type", msgType, "tunnel_id", h.TunnelID, "src", srcIP.String(), "avp_count", len(avps))
 
 	if msgType == l2tppkt.MsgTypeSCCRQ {
-		return c.dispatchSCCRQ(pkt, h, avps)
+		return c.dispatchSCCRQ(srcIP, dstIP, h, avps)
 	}
 
-	t := c.LookupTunnel(pkt.IPv4.SrcIP, h.TunnelID)
-	if t == nil {
+	t := c.LookupTunnel(srcIP, h.TunnelID)
+	if t == nil || t.isStandby() {
+		// Standby tunnels belong to the HA peer until promotion.
 		return ErrNoSuchTunnel
 	}
+	if t.isRecovering() {
+		// The channel resumes from the Ns / Nr the peer suggests in
+		// the recovery tunnel's SCCRP; its retransmissions fill the gap.
+		return ErrTunnelRecovering
+	}
 
 	// Advance the control channel's ACK state from the inbound Ns/Nr
 	// before processing the message. Without this the send window stays
 	// closed and outbound replies (SCCCN, ICCN, …) sit in the queue
 	// behind the now-acknowledged previous send.
+	if t.Channel != nil && len(avps) == 0 {
+		// ZLB (no AVPs): a pure ACK, its Ns is not consumed.
+		t.Channel.RecvZLB(h.Ns, h.Nr, time.Now())
+		return nil
+	}
 	if t.Channel != nil {
 		accept, err := t.Channel.Recv(h.Ns, h.Nr, time.Now())
 		if err != nil {
@@ -91,9 +109,24 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 
 	switch msgType {
 	case l2tppkt.MsgTypeSCCRP:
-		return c.handleSCCRP(t, avps)
+		err := c.handleSCCRP(t, avps)
+		if t.recovers != nil {
+			if err != nil {
+				c.failRecovery(t, err)
+			}
+			return err
+		}
+		if t.probe {
+			t.finishProbe(err)
+			return err
+		}
+		if err != nil {
+			c.accountTunnelReject(t, err)
+			return err
+		}
+		return nil
 	case l2tppkt.MsgTypeICRP:
-		s := c.LookupSession(pkt.IPv4.SrcIP, h.TunnelID, h.SessionID)
+		s := c.LookupSession(srcIP, h.TunnelID, h.SessionID)
 		if s == nil {
 			return ErrNoSuchSession
 		}
@@ -102,7 +135,11 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 		t.mu.Lock()
 		oc := t.outstandingChallenge
 		t.mu.Unlock()
-		return c.HandleSCCCN(t, avps, oc)
+		if err := c.HandleSCCCN(t, avps, oc); err != nil {
+			c.accountTunnelReject(t, err)
+			return err
+		}
+		return nil
 	case l2tppkt.MsgTypeHello:
 		// Hello has no body beyond Message Type; the control channel
 		// already extracted Ns/Nr and ACKed it. Nothing further.
@@ -110,6 +147,10 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 	case l2tppkt.MsgTypeStopCCN:
 		c.HandleStopCCN(t)
 		return nil
+	case l2tppkt.MsgTypeFSQ:
+		return c.handleFSQ(t, avps)
+	case l2tppkt.MsgTypeFSR:
+		return c.handleFSR(t, avps)
 	case l2tppkt.MsgTypeICRQ:
 		icrpBody, s, err := c.HandleICRQ(t, avps)
 		if err != nil {
@@ -120,13 +161,13 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 		}
 		return nil
 	case l2tppkt.MsgTypeICCN:
-		s := c.LookupSession(pkt.IPv4.SrcIP, h.TunnelID, h.SessionID)
+		s := c.LookupSession(srcIP, h.TunnelID, h.SessionID)
 		if s == nil {
 			return ErrNoSuchSession
 		}
 		return c.HandleICCN(s, avps)
 	case l2tppkt.MsgTypeCDN:
-		s := c.LookupSession(pkt.IPv4.SrcIP, h.TunnelID, h.SessionID)
+		s := c.LookupSession(srcIP, h.TunnelID, h.SessionID)
 		if s == nil {
 			return ErrNoSuchSession
 		}
@@ -136,23 +177,29 @@ func (c *Component) Dispatch(pkt *dataplane.ParsedPacket) error {
 	return ErrUnsupportedMessageType
 }
 
-func (c *Component) dispatchSCCRQ(pkt *dataplane.ParsedPacket, h *l2tppkt.Header, avps []l2tppkt.AVP) error {
+func (c *Component) dispatchSCCRQ(srcIP, dstIP net.IP, h *l2tppkt.Header, avps []l2tppkt.AVP) error {
+	if tr, ok := l2tppkt.DecodeTunnelRecovery(avps); ok {
+		return c.dispatchRecoverySCCRQ(srcIP, dstIP, h, avps, tr)
+	}
+
 	hostAVP := l2tppkt.FindFirst(avps, 0, l2tppkt.AVPHostName)
 	if hostAVP == nil {
 		return ErrMissingHostName
 	}
-	_ = l2tppkt.DecodeString(hostAVP)
+	hostname := l2tppkt.DecodeString(hostAVP)
 
 	if c.resolveLNSConfig == nil {
 		return ErrLNSConfigUnresolved
 	}
-	cfg, ok := c.resolveLNSConfig(l2tppkt.DecodeString(hostAVP))
+	cfg, ok := c.resolveLNSConfig(hostname)
 	if !ok {
+		c.accountPeerReject(dstIP, srcIP, hostname, ErrLACNotAuthorized)
 		return ErrLACNotAuthorized
 	}
 
-	sccrpBody, t, err := c.HandleSCCRQ(pkt.IPv4.DstIP, pkt.IPv4.SrcIP, avps, cfg)
+	sccrpBody, t, err := c.HandleSCCRQ(dstIP, srcIP, avps, cfg)
 	if err != nil {
+		c.accountPeerReject(dstIP, srcIP, hostname, err)
 		return err
 	}
 	if sccrpBody != nil && t != nil && t.Channel != nil {
@@ -168,6 +215,18 @@ func (c *Component) dispatchSCCRQ(pkt *dataplane.ParsedPacket, h *l2tppkt.Header
 	return nil
 }
 
+// puntAddrs returns the outer source and destination address of a
+// punted L2TP packet, whichever IP family carried it.
+func puntAddrs(pkt *dataplane.ParsedPacket) (src, dst net.IP, ok bool) {
+	switch {
+	case pkt.IPv4 != nil:
+		return pkt.IPv4.SrcIP, pkt.IPv4.DstIP, true
+	case pkt.IPv6 != nil:
+		return pkt.IPv6.SrcIP, pkt.IPv6.DstIP, true
+	}
+	return nil, nil, false
+}
+
 // respondV3Unsupported is a stub for the rare case of a v3 control
 // frame surfacing at this layer. The dispatcher returns the StopCCN
 // bytes; the caller (transmission layer) is responsible for sending.
@@ -177,7 +236,7 @@ func (c *Component) respondV3Unsupported(pkt *dataplane.ParsedPacket) error {
 }
 
 var (
-	ErrPuntPacketShape       = errors.New("l2tp: punt packet missing IPv4/UDP layers")
+	ErrPuntPacketShape       = errors.New("l2tp: punt packet missing IP/UDP layers")
 	ErrUnsupportedVersion    = errors.New("l2tp: unsupported version field")
 	ErrDataAtControlPath     = errors.New("l2tp: data packet at control path")
 	ErrNoSuchTunnel          = errors.New("l2tp: no tunnel for inbound packet")
@@ -0,0 +1,460 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+)
+
+// RFC 4951 control-channel failover. Every tunnel advertises a Failover
+// Capability when failover is enabled, and a tunnel is marked
+// recoverable only when the peer advertised one too.
+//
+// As the failed endpoint (process restart or HA takeover) the node
+// re-installs a recoverable tunnel's dataplane entries so subscriber
+// traffic keeps flowing, then opens a recovery tunnel whose SCCRQ names
+// the old tunnel. The peer's SCCRP carries the Ns / Nr the old tunnel
+// resumes from; the recovery tunnel is then closed and the session
+// state reconciled with FSQ / FSR. Tunnels the peer never agreed to
+// recover are closed locally.
+//
+// As the peer of a failed endpoint the node holds a failover tunnel
+// whose channel died for the peer's recovery time, and answers its
+// recovery SCCRQ and FSQ.
+
+var (
+	ErrRecoveryRejected   = errors.New("l2tp: recovery SCCRQ names no recoverable tunnel")
+	ErrRecoveryRefused    = errors.New("l2tp: peer did not suggest a control sequence for recovery")
+	ErrRecoveryAbandoned  = errors.New("l2tp: recovered tunnel is no longer active")
+	ErrTunnelRecovering   = errors.New("l2tp: tunnel is waiting for recovery")
+	ErrNoFailoverAgreed   = errors.New("l2tp: peer did not agree to failover")
+	errRecoveryTunnelOnly = errors.New("l2tp: message only valid on a recovered tunnel")
+)
+
+// noteFailover records whether the peer's SCCRQ / SCCRP advertised
+// control-channel failover. A tunnel is recoverable only when both
+// ends did.
+func (c *Component) noteFailover(t *Tunnel, avps []l2tppkt.AVP) {
+	if c.failover == nil {
+		return
+	}
+	fc, ok := l2tppkt.DecodeFailoverCapability(avps)
+	if !ok || !fc.ControlChannel {
+		return
+	}
+	t.mu.Lock()
+	t.failover = true
+	t.peerRecoveryTime = fc.RecoveryTime
+	t.mu.Unlock()
+}
+
+// resumeTunnel brings a restored or promoted tunnel back into service.
+// A tunnel without agreed failover is closed: its control channel
+// cannot be recovered and the peer would tear it down anyway. Otherwise
+// dataplane entries left by a previous incarnation are removed and
+// recreated (the plugin has no adopt-existing contract), the sessions
+// are re-installed, and the control channel is recovered through a
+// recovery tunnel. Inbound control messages are dropped until the peer
+// answers it.
+func (c *Component) resumeTunnel(t *Tunnel) error {
+	t.mu.Lock()
+	recoverable := t.failover && c.failover != nil
+	t.standby = false
+	t.mu.Unlock()
+	if !recoverable {
+		c.closeUnrecoverableTunnel(t)
+		return ErrNoFailoverAgreed
+	}
+
+	sessions := t.snapshotSessions()
+	if c.vpp != nil {
+		for _, s := range sessions {
+			_ = c.vpp.DeleteL2TPSession(t.LocalIP, t.PeerIP, t.LocalID, s.LocalID)
+		}
+		_ = c.vpp.DeleteL2TPTunnel(t.LocalIP, t.PeerIP, t.LocalID)
+	}
+
+	t.mu.Lock()
+	t.installedInVPP = false
+	t.recovering = true
+	t.mu.Unlock()
+
+	if err := c.installTunnelVPP(t); err != nil {
+		c.teardownTunnel(t, auth.TerminateCauseNASError)
+		return err
+	}
+	c.checkpointTunnel(t)
+	for _, s := range sessions {
+		c.resumeSessionVPP(s, true)
+	}
+
+	if err := c.openRecoveryTunnel(t); err != nil {
+		c.teardownTunnel(t, auth.TerminateCauseLostCarrier)
+		return err
+	}
+	return nil
+}
+
+// closeUnrecoverableTunnel drops a restored tunnel whose peer never
+// agreed to failover. Its LNS sessions are handed to AAA first so the
+// teardown closes their accounting.
+func (c *Component) closeUnrecoverableTunnel(t *Tunnel) {
+	for _, s := range t.snapshotSessions() {
+		if s.Role != l2tppkt.SessionRoleLNS {
+			continue
+		}
+		s.mu.Lock()
+		c.publishSessionRestored(s)
+		s.mu.Unlock()
+	}
+	c.log.Warn("Closing restored l2tp tunnel; peer did not agree to failover",
+		"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID)
+	c.teardownTunnel(t, auth.TerminateCauseNASError)
+}
+
+// openRecoveryTunnel sends the SCCRQ of a recovery tunnel for `old`
+// (RFC 4951 §5.1). The outcome arrives as an SCCRP on the recovery
+// tunnel (completeRecovery); silence or a StopCCN closes both.
+func (c *Component) openRecoveryTunnel(old *Tunnel) error {
+	if c.send == nil {
+		return ErrSendNotConfigured
+	}
+	localID, err := c.allocateTunnelID(old.PeerIP)
+	if err != nil {
+		return ErrTunnelExhaustion
+	}
+	secret := c.recoverySecret(old)
+	var challenge []byte
+	if len(secret) > 0 {
+		if challenge, err = l2tppkt.NewChallenge(); err != nil {
+			c.releaseTunnelID(old.PeerIP, localID)
+			return err
+		}
+	}
+
+	rt := &Tunnel{
+		LocalIP:              old.LocalIP,
+		PeerIP:               old.PeerIP,
+		LocalID:              localID,
+		LocalPort:            1701,
+		PeerPort:             1701,
+		Role:                 l2tppkt.RoleInitiator,
+		FSM:                  l2tppkt.NewTunnelFSM(l2tppkt.RoleInitiator),
+		LocalHostname:        old.LocalHostname,
+		PeerHostname:         old.PeerHostname,
+		Secret:               secret,
+		Sessions:             make(map[uint16]*Session),
+		CreatedAt:            time.Now(),
+		outstandingChallenge: challenge,
+		recovers:             old,
+	}
+	if err := rt.FSM.SendSCCRQ(); err != nil {
+		c.releaseTunnelID(old.PeerIP, localID)
+		return err
+	}
+	if err := c.registerTunnel(rt); err != nil {
+		c.releaseTunnelID(old.PeerIP, localID)
+		return err
+	}
+	c.startTunnelRunner(rt, 60*time.Second)
+
+	body := l2tppkt.BuildSCCRQ(l2tppkt.SCCRQParams{
+		LocalTunnelID:     localID,
+		ReceiveWindowSize: 16,
+		HostName:          old.LocalHostname,
+		FramingCaps:       l2tppkt.FramingSync,
+		BearerCaps:        l2tppkt.BearerDigital,
+		Challenge:         challenge,
+		Failover:          c.failover,
+		TunnelRecovery: &l2tppkt.TunnelRecovery{
+			TunnelID:       old.LocalID,
+			RemoteTunnelID: old.PeerID,
+		},
+	})
+	if err := rt.Channel.Send(body, time.Now()); err != nil {
+		rt.mu.Lock()
+		rt.recovers = nil
+		rt.mu.Unlock()
+		c.teardownTunnel(rt, "")
+		return err
+	}
+	c.log.Info("Recovering l2tp tunnel",
+		"peer_ip", old.PeerIP.String(), "local_tunnel_id", old.LocalID,
+		"recovery_tunnel_id", localID)
+	return nil
+}
+
+// recoverySecret returns the shared secret to authenticate a recovery
+// tunnel with: the peer policy's on an LNS, the tunnel pool's on a
+// LAC. Secrets handed out by AAA are not checkpointed, so such tunnels
+// recover unauthenticated.
+func (c *Component) recoverySecret(old *Tunnel) []byte {
+	if old.Role == l2tppkt.RoleResponder {
+		if c.resolveLNSConfig == nil {
+			return nil
+		}
+		if cfg, ok := c.resolveLNSConfig(old.PeerHostname); ok {
+			return cfg.Secret
+		}
+		return nil
+	}
+	if c.cfgMgr == nil {
+		return nil
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.L2TP == nil {
+		return nil
+	}
+	if _, _, ref := cfg.L2TP.LookupLNS(old.PeerIP); ref != nil && ref.Secret != "" {
+		return []byte(ref.Secret)
+	}
+	return nil
+}
+
+// completeRecovery handles the SCCRP on a recovery tunnel: the old
+// tunnel's channel restarts from the peer's suggested Ns / Nr, the
+// recovery tunnel is closed and the peer is asked which sessions it
+// still holds. Called after the SCCRP's challenge has been verified;
+// peerResp answers the peer's own challenge, if any.
+func (c *Component) completeRecovery(rt *Tunnel, avps []l2tppkt.AVP, peerResp []byte) error {
+	old := rt.recovers
+	seq, ok := l2tppkt.DecodeSuggestedControlSequence(avps)
+	if !ok {
+		return ErrRecoveryRefused
+	}
+	if old.isStandby() || !old.isRecovering() {
+		return ErrRecoveryAbandoned
+	}
+	now := time.Now()
+	if err := rt.Channel.Send(l2tppkt.BuildSCCCN(peerResp), now); err != nil {
+		return err
+	}
+	stop := l2tppkt.BuildStopCCN(rt.LocalID, l2tppkt.ResultStopGeneralRequest,
+		l2tppkt.ErrorNoGeneralError, "tunnel recovered")
+	if err := rt.Channel.Send(stop, now); err != nil {
+		c.log.Debug("StopCCN on recovery tunnel failed", "error", err)
+	}
+
+	old.mu.Lock()
+	old.resuming = true
+	old.resumeNs, old.resumeNr = seq.Ns, seq.Nr
+	old.recovering = false
+	interval := old.HelloInterval
+	old.mu.Unlock()
+	rt.mu.Lock()
+	rt.recovered = true
+	rt.mu.Unlock()
+
+	c.startTunnelRunner(old, interval)
+	c.checkpointTunnel(old)
+	c.teardownTunnel(rt, "")
+
+	var query []l2tppkt.FailoverSessionState
+	for _, s := range old.snapshotSessions() {
+		s.mu.Lock()
+		query = append(query, l2tppkt.FailoverSessionState{SessionID: s.LocalID, RemoteSessionID: s.PeerID})
+		s.mu.Unlock()
+	}
+	if old.Channel != nil {
+		for _, body := range l2tppkt.BuildFSQ(query) {
+			if err := old.Channel.Send(body, now); err != nil {
+				c.log.Warn("FSQ send failed", "peer_ip", old.PeerIP.String(), "error", err)
+			}
+		}
+	}
+	c.log.Info("Recovered l2tp tunnel",
+		"peer_ip", old.PeerIP.String(), "local_tunnel_id", old.LocalID,
+		"ns", seq.Ns, "nr", seq.Nr, "sessions", len(query))
+	return nil
+}
+
+// failRecovery closes a recovery tunnel whose SCCRP could not be used;
+// teardownTunnel takes the tunnel it was recovering along.
+func (c *Component) failRecovery(rt *Tunnel, reason error) {
+	c.log.Warn("l2tp tunnel recovery failed",
+		"peer_ip", rt.PeerIP.String(), "local_tunnel_id", rt.recovers.LocalID, "error", reason)
+	if rt.Channel != nil {
+		body := l2tppkt.BuildStopCCN(rt.LocalID, l2tppkt.ResultStopGeneralRequest,
+			l2tppkt.ErrorNoGeneralError, "recovery failed")
+		_ = rt.Channel.Send(body, time.Now())
+	}
+	c.teardownTunnel(rt, "")
+}
+
+// dispatchRecoverySCCRQ answers a failed peer's recovery SCCRQ. The
+// tunnel it names must be one this node holds with that peer, with
+// failover agreed; the SCCRP tells the peer where to resume the
+// tunnel's control channel. The recovery tunnel itself carries nothing
+// and is closed by the peer.
+func (c *Component) dispatchRecoverySCCRQ(srcIP, dstIP net.IP, h *l2tppkt.Header, avps []l2tppkt.AVP, tr l2tppkt.TunnelRecovery) error {
+	old := c.LookupTunnel(srcIP, tr.RemoteTunnelID)
+	if old == nil || old.isStandby() || old.Channel == nil {
+		return ErrRecoveryRejected
+	}
+	hostname := ""
+	if a := l2tppkt.FindFirst(avps, 0, l2tppkt.AVPHostName); a != nil {
+		hostname = l2tppkt.DecodeString(a)
+	}
+	old.mu.Lock()
+	ok := old.failover && old.PeerID == tr.TunnelID && old.PeerHostname == hostname && !old.tornDown
+	secret := old.Secret
+	old.mu.Unlock()
+	if !ok {
+		return ErrRecoveryRejected
+	}
+
+	assigned := l2tppkt.FindFirst(avps, 0, l2tppkt.AVPAssignedTunnelID)
+	if assigned == nil || len(assigned.Value) < 2 {
+		return ErrMissingAssignedTunnelID
+	}
+	var challengeResp []byte
+	if ch := l2tppkt.FindFirst(avps, 0, l2tppkt.AVPChallenge); ch != nil {
+		if len(secret) == 0 {
+			return ErrChallengeWithoutSecret
+		}
+		challengeResp = l2tppkt.ComputeChallengeResponse(byte(l2tppkt.MsgTypeSCCRP), secret, ch.Value)
+	}
+
+	localID, err := c.allocateTunnelID(srcIP)
+	if err != nil {
+		return ErrTunnelExhaustion
+	}
+	rt := &Tunnel{
+		LocalIP:       dstIP,
+		PeerIP:        srcIP,
+		LocalID:       localID,
+		PeerID:        l2tppkt.DecodeUint16(assigned),
+		LocalPort:     1701,
+		PeerPort:      1701,
+		Role:          l2tppkt.RoleResponder,
+		FSM:           l2tppkt.NewTunnelFSM(l2tppkt.RoleResponder),
+		LocalHostname: old.LocalHostname,
+		PeerHostname:  hostname,
+		Secret:        secret,
+		Sessions:      make(map[uint16]*Session),
+		CreatedAt:     time.Now(),
+		recovers:      old,
+		recovered:     true,
+	}
+	if err := rt.FSM.RecvSCCRQ(); err != nil {
+		c.releaseTunnelID(srcIP, localID)
+		return err
+	}
+	if err := c.registerTunnel(rt); err != nil {
+		c.releaseTunnelID(srcIP, localID)
+		return err
+	}
+	c.startTunnelRunner(rt, 60*time.Second)
+
+	old.mu.Lock()
+	if old.recoveryHold != nil {
+		old.recoveryHold.Stop()
+		old.recoveryHold = nil
+	}
+	old.mu.Unlock()
+	now := time.Now()
+	ns, nr := old.Channel.RecoveryPoint(now)
+
+	body := l2tppkt.BuildSCCRP(l2tppkt.SCCRPParams{
+		LocalTunnelID:     localID,
+		ReceiveWindowSize: 16,
+		HostName:          old.LocalHostname,
+		FramingCaps:       l2tppkt.FramingSync,
+		BearerCaps:        l2tppkt.BearerDigital,
+		ChallengeResponse: challengeResp,
+		Failover:          c.failover,
+		SuggestedSequence: &l2tppkt.ControlSequence{Ns: ns, Nr: nr},
+	})
+	if _, err := rt.Channel.Recv(h.Ns, h.Nr, now); err != nil {
+		return err
+	}
+	c.log.Info("Peer recovering l2tp tunnel",
+		"peer_ip", srcIP.String(), "local_tunnel_id", old.LocalID, "ns", ns, "nr", nr)
+	return rt.Channel.Send(body, now)
+}
+
+// handleFSQ answers a recovered peer's Failover-Session-Query: each
+// session it lists is confirmed with our session ID, or 0 when this
+// node does not carry it.
+func (c *Component) handleFSQ(t *Tunnel, avps []l2tppkt.AVP) error {
+	if !t.failover {
+		return errRecoveryTunnelOnly
+	}
+	query := l2tppkt.DecodeSessionStates(avps)
+	reply := make([]l2tppkt.FailoverSessionState, 0, len(query))
+	for _, q := range query {
+		ans := l2tppkt.FailoverSessionState{RemoteSessionID: q.SessionID}
+		t.mu.Lock()
+		s := t.Sessions[q.RemoteSessionID]
+		t.mu.Unlock()
+		if s != nil {
+			s.mu.Lock()
+			if s.PeerID == q.SessionID {
+				ans.SessionID = s.LocalID
+			}
+			s.mu.Unlock()
+		}
+		reply = append(reply, ans)
+	}
+	now := time.Now()
+	for _, body := range l2tppkt.BuildFSR(reply) {
+		if err := t.Channel.Send(body, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleFSR clears the sessions the peer no longer holds.
+func (c *Component) handleFSR(t *Tunnel, avps []l2tppkt.AVP) error {
+	if !t.failover {
+		return errRecoveryTunnelOnly
+	}
+	var cleared int
+	for _, r := range l2tppkt.DecodeSessionStates(avps) {
+		if r.SessionID != 0 {
+			continue
+		}
+		t.mu.Lock()
+		s := t.Sessions[r.RemoteSessionID]
+		t.mu.Unlock()
+		if s == nil {
+			continue
+		}
+		c.teardownSession(s, auth.TerminateCauseLostCarrier)
+		cleared++
+	}
+	if cleared > 0 {
+		c.log.Info("Cleared l2tp sessions unknown to the recovered peer",
+			"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID, "sessions", cleared)
+	}
+	return nil
+}
+
+// holdForRecovery keeps a failover tunnel whose channel died for the
+// peer's recovery time instead of tearing it down at once. Reports
+// whether the tunnel is held. Runs on the runner goroutine.
+func (c *Component) holdForRecovery(t *Tunnel) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failover || t.tornDown || t.peerRecoveryTime <= 0 {
+		return false
+	}
+	if t.recoveryHold != nil {
+		return true
+	}
+	c.log.Info("l2tp peer unreachable; holding tunnel for recovery",
+		"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID,
+		"recovery_time", t.peerRecoveryTime)
+	t.recoveryHold = time.AfterFunc(t.peerRecoveryTime, func() {
+		c.teardownTunnel(t, auth.TerminateCauseLostCarrier)
+	})
+	return true
+}
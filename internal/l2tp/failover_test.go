@@ -0,0 +1,232 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"github.com/veesix-networks/osvbng/pkg/dataplane"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// dispatchControl feeds a control message from 10.0.0.2 through
+// Dispatch.
+func dispatchControl(c *Component, tunnelID, ns, nr uint16, body []byte) error {
+	hdr := l2tppkt.NewControl(tunnelID, 0, ns, nr)
+	wire := hdr.AppendTo(make([]byte, 0, 12+len(body)), len(body))
+	wire = append(wire, body...)
+	pkt := &dataplane.ParsedPacket{
+		Protocol: models.ProtocolL2TP,
+		IPv4: &layers.IPv4{
+			SrcIP: net.IPv4(10, 0, 0, 2).To4(),
+			DstIP: net.IPv4(10, 0, 0, 1).To4(),
+		},
+		UDP: &layers.UDP{SrcPort: 1701, DstPort: 1701},
+	}
+	pkt.UDP.Payload = wire
+	return c.Dispatch(pkt)
+}
+
+// restoredLNSTunnel registers a tunnel as adopted from a checkpoint:
+// Established, one session, no control channel yet.
+func restoredLNSTunnel(t *testing.T, c *Component, failover bool) (*Tunnel, *Session) {
+	t.Helper()
+	peer := net.IPv4(10, 0, 0, 2)
+	tun := &Tunnel{
+		LocalIP:       net.IPv4(10, 0, 0, 1),
+		PeerIP:        peer,
+		LocalID:       3,
+		PeerID:        40,
+		LocalPort:     1701,
+		PeerPort:      1701,
+		Role:          l2tppkt.RoleResponder,
+		FSM:           l2tppkt.RestoreTunnelFSM(l2tppkt.RoleResponder, l2tppkt.TunnelEstablished),
+		LocalHostname: "lns1",
+		PeerHostname:  "lac1",
+		HelloInterval: time.Minute,
+		failover:      failover,
+	}
+	if err := c.registerTunnel(tun); err != nil {
+		t.Fatal(err)
+	}
+	s := &Session{
+		SessionID: makeSessionID(peer, 3, 7),
+		Tunnel:    tun,
+		LocalID:   7,
+		PeerID:    70,
+		Role:      l2tppkt.SessionRoleLNS,
+		FSM:       l2tppkt.RestoreSessionFSM(l2tppkt.SessionRoleLNS, l2tppkt.SessionEstablished),
+		Username:  "alice",
+	}
+	tun.addSession(s)
+	return tun, s
+}
+
+func TestResumeTunnelWithoutFailoverCloses(t *testing.T) {
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	c.SetFailover(30 * time.Second)
+	defer c.Stop(context.Background())
+
+	tun, s := restoredLNSTunnel(t, c, false)
+	if err := c.resumeTunnel(tun); err != ErrNoFailoverAgreed {
+		t.Fatalf("resumeTunnel: %v", err)
+	}
+	if c.LookupTunnel(tun.PeerIP, tun.LocalID) != nil || c.LookupSession(tun.PeerIP, 3, s.LocalID) != nil {
+		t.Fatal("tunnel without agreed failover should be closed")
+	}
+	for _, p := range tr.snapshot() {
+		avps, _ := l2tppkt.ParseAVPs(p.body)
+		if _, ok := l2tppkt.DecodeTunnelRecovery(avps); ok {
+			t.Fatal("no recovery tunnel may be opened without agreed failover")
+		}
+	}
+}
+
+func TestRecoveryResumesFromSuggestedSequence(t *testing.T) {
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	c.SetFailover(30 * time.Second)
+	defer c.Stop(context.Background())
+
+	old, s := restoredLNSTunnel(t, c, true)
+	if err := c.resumeTunnel(old); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatchControl(c, old.LocalID, 0, 0, l2tppkt.BuildHello()); err != ErrTunnelRecovering {
+		t.Fatalf("control on a recovering tunnel: %v", err)
+	}
+
+	_, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeSCCRQ)
+	rtID := l2tppkt.DecodeUint16(l2tppkt.FindFirst(avps, 0, l2tppkt.AVPAssignedTunnelID))
+	sccrp := l2tppkt.BuildSCCRP(l2tppkt.SCCRPParams{
+		LocalTunnelID:     90,
+		ReceiveWindowSize: 16,
+		HostName:          "lac1",
+		FramingCaps:       l2tppkt.FramingSync,
+		BearerCaps:        l2tppkt.BearerDigital,
+		Failover:          &l2tppkt.FailoverCapability{ControlChannel: true, RecoveryTime: time.Second},
+		SuggestedSequence: &l2tppkt.ControlSequence{Ns: 9, Nr: 11},
+	})
+	if err := dispatchControl(c, rtID, 0, 1, sccrp); err != nil {
+		t.Fatalf("recovery SCCRP: %v", err)
+	}
+
+	if old.isRecovering() || old.Channel == nil {
+		t.Fatal("old tunnel should resume its control channel")
+	}
+	if c.LookupTunnel(old.PeerIP, rtID) != nil {
+		t.Fatal("recovery tunnel should be closed once the SCCRP is handled")
+	}
+	fsq, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeFSQ)
+	if fsq.header.TunnelID != 40 || fsq.header.Ns != 9 || fsq.header.Nr != 11 {
+		t.Fatalf("FSQ header: tid=%d ns=%d nr=%d", fsq.header.TunnelID, fsq.header.Ns, fsq.header.Nr)
+	}
+	states := l2tppkt.DecodeSessionStates(avps)
+	if len(states) != 1 || states[0] != (l2tppkt.FailoverSessionState{SessionID: 7, RemoteSessionID: 70}) {
+		t.Fatalf("FSQ states: %+v", states)
+	}
+
+	fsr := l2tppkt.BuildFSR([]l2tppkt.FailoverSessionState{{SessionID: 0, RemoteSessionID: 7}})[0]
+	if err := dispatchControl(c, old.LocalID, 11, 10, fsr); err != nil {
+		t.Fatalf("FSR: %v", err)
+	}
+	if c.LookupSession(old.PeerIP, old.LocalID, s.LocalID) != nil {
+		t.Fatal("session the peer no longer holds should be cleared")
+	}
+	if c.LookupTunnel(old.PeerIP, old.LocalID) == nil {
+		t.Fatal("recovered tunnel should stay up")
+	}
+}
+
+func TestRecoverySCCRPWithoutSequenceClosesTunnel(t *testing.T) {
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	c.SetFailover(30 * time.Second)
+	defer c.Stop(context.Background())
+
+	old, _ := restoredLNSTunnel(t, c, true)
+	if err := c.resumeTunnel(old); err != nil {
+		t.Fatal(err)
+	}
+	_, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeSCCRQ)
+	rtID := l2tppkt.DecodeUint16(l2tppkt.FindFirst(avps, 0, l2tppkt.AVPAssignedTunnelID))
+	sccrp := buildSCCRPBody(90, nil, nil)
+	if err := dispatchControl(c, rtID, 0, 1, sccrp); err != ErrRecoveryRefused {
+		t.Fatalf("SCCRP without suggested sequence: %v", err)
+	}
+	if c.LookupTunnel(old.PeerIP, old.LocalID) != nil || c.LookupTunnel(old.PeerIP, rtID) != nil {
+		t.Fatal("failed recovery should close both tunnels")
+	}
+}
+
+func TestPeerAnswersRecoveryAndFSQ(t *testing.T) {
+	tr := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(tr.Send)
+	c.SetFailover(30 * time.Second)
+	defer c.Stop(context.Background())
+
+	old, s := establishedLNSTunnel(t, c)
+	old.mu.Lock()
+	old.failover = true
+	old.mu.Unlock()
+
+	sccrq := l2tppkt.BuildSCCRQ(l2tppkt.SCCRQParams{
+		LocalTunnelID:     41,
+		ReceiveWindowSize: 16,
+		HostName:          "lac1",
+		FramingCaps:       l2tppkt.FramingSync,
+		BearerCaps:        l2tppkt.BearerDigital,
+		Failover:          &l2tppkt.FailoverCapability{ControlChannel: true},
+		TunnelRecovery:    &l2tppkt.TunnelRecovery{TunnelID: 40, RemoteTunnelID: old.LocalID},
+	})
+	if err := dispatchControl(c, 0, 0, 0, sccrq); err != nil {
+		t.Fatalf("recovery SCCRQ: %v", err)
+	}
+	p, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeSCCRP)
+	if p.header.TunnelID != 41 {
+		t.Fatalf("SCCRP sent to tunnel %d, want 41", p.header.TunnelID)
+	}
+	if seq, ok := l2tppkt.DecodeSuggestedControlSequence(avps); !ok || seq != (l2tppkt.ControlSequence{Ns: 0, Nr: 0}) {
+		t.Fatalf("suggested sequence: %+v ok=%v", seq, ok)
+	}
+
+	// A recovery SCCRQ for a tunnel the peer does not own is refused.
+	bogus := l2tppkt.BuildSCCRQ(l2tppkt.SCCRQParams{
+		LocalTunnelID:  42,
+		HostName:       "lac1",
+		TunnelRecovery: &l2tppkt.TunnelRecovery{TunnelID: 99, RemoteTunnelID: old.LocalID},
+	})
+	if err := dispatchControl(c, 0, 0, 0, bogus); err != ErrRecoveryRejected {
+		t.Fatalf("recovery of a foreign tunnel: %v", err)
+	}
+
+	fsq := l2tppkt.BuildFSQ([]l2tppkt.FailoverSessionState{
+		{SessionID: 70, RemoteSessionID: s.LocalID},
+		{SessionID: 71, RemoteSessionID: 8},
+	})[0]
+	if err := dispatchControl(c, old.LocalID, 0, 0, fsq); err != nil {
+		t.Fatalf("FSQ: %v", err)
+	}
+	_, avps = findMessage(t, tr.snapshot(), l2tppkt.MsgTypeFSR)
+	got := l2tppkt.DecodeSessionStates(avps)
+	want := []l2tppkt.FailoverSessionState{
+		{SessionID: s.LocalID, RemoteSessionID: 70},
+		{SessionID: 0, RemoteSessionID: 71},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("FSR states: %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"google.golang.org/protobuf/proto"
+)
+
+// restoreSyncedStandby rebuilds peer-synced tunnels and sessions after
+// a standby restart: the HA synced namespace survives in opdb while the
+// component's own checkpoints only cover tunnels this node owned.
+func (c *Component) restoreSyncedStandby(ctx context.Context) error {
+	if c.opdb == nil || c.srgMgr == nil {
+		return nil
+	}
+	return c.opdb.Load(ctx, opdb.NamespaceHASyncedL2TP, func(key string, value []byte) error {
+		cp := &hapb.SessionCheckpoint{}
+		if err := proto.Unmarshal(value, cp); err != nil {
+			c.log.Warn("Corrupt synced l2tp checkpoint; dropping", "key", key, "error", err)
+			_ = c.opdb.Delete(ctx, opdb.NamespaceHASyncedL2TP, key)
+			return nil
+		}
+		c.ApplySyncedSession(hapb.SyncAction_SYNC_ACTION_UPDATE, cp)
+		return nil
+	})
+}
+
+// ForEachSession feeds the HA BulkSync stream: every session this node
+// owns with an SRG binding, LAC and LNS alike.
+func (c *Component) ForEachSession(fn func(models.SubscriberSession) bool) {
+	c.mu.RLock()
+	tunnels := make([]*Tunnel, 0, len(c.tunnels))
+	for _, t := range c.tunnels {
+		tunnels = append(tunnels, t)
+	}
+	c.mu.RUnlock()
+
+	for _, t := range tunnels {
+		if t.isStandby() {
+			continue
+		}
+		for _, s := range t.snapshotSessions() {
+			s.mu.Lock()
+			var m *models.PPPoL2TPSession
+			if s.SRGName != "" && !s.ActivatedAt.IsZero() {
+				m = c.sessionModel(s, models.SessionStateActive)
+			}
+			s.mu.Unlock()
+			if m != nil && !fn(m) {
+				return
+			}
+		}
+	}
+}
+
+// ApplySyncedSession mirrors a peer's session, and the tunnel carrying
+// it, as standby control state. Nothing is installed in the dataplane:
+// the L2TP plugin has no disabled state and the subscriber routes
+// would shadow the active node. Promotion installs everything in one
+// pass and recovers each control channel with the LAC / LNS peer.
+func (c *Component) ApplySyncedSession(action hapb.SyncAction, cp *hapb.SessionCheckpoint) {
+	if cp == nil || cp.AccessType != "l2tp" {
+		return
+	}
+	if c.srgMgr != nil && cp.SrgName != "" && c.srgMgr.IsActive(cp.SrgName) {
+		return
+	}
+
+	trec, srec := recordsFromCheckpoint(cp)
+	if trec.PeerIP == nil {
+		c.log.Warn("Synced l2tp session has no tunnel endpoint", "session_id", cp.SessionId)
+		return
+	}
+
+	if action == hapb.SyncAction_SYNC_ACTION_DELETE {
+		t := c.LookupTunnel(trec.PeerIP, trec.LocalID)
+		if t == nil || !t.isStandby() {
+			return
+		}
+		t.mu.Lock()
+		s := t.Sessions[srec.LocalID]
+		t.mu.Unlock()
+		if s == nil || s.SessionID != srec.SessionID {
+			return
+		}
+		if t.removeSession(srec.LocalID) {
+			c.unregisterTunnel(t.PeerIP, t.LocalID)
+			c.releaseTunnelID(t.PeerIP, t.LocalID)
+		}
+		return
+	}
+
+	t := c.adoptTunnel(trec, true)
+	if !t.isStandby() {
+		// Same tunnel key owned locally: the peer's copy is stale.
+		return
+	}
+	t.mu.Lock()
+	t.resumeNs, t.resumeNr = trec.Ns, trec.Nr
+	t.failover, t.peerRecoveryTime = trec.Failover, trec.PeerRecoveryTime
+	t.mu.Unlock()
+	c.adoptSession(t, srec)
+
+	c.log.Debug("Applied synced l2tp session (standby)",
+		"session_id", srec.SessionID, "peer_ip", trec.PeerIP.String(),
+		"local_tunnel_id", trec.LocalID, "local_session_id", srec.LocalID)
+}
+
+// recordsFromCheckpoint splits an HA checkpoint into the tunnel and
+// session records the restore path adopts.
+func recordsFromCheckpoint(cp *hapb.SessionCheckpoint) (tunnelRecord, sessionRecord) {
+	trec := tunnelRecord{
+		LocalIP:       net.IP(cp.L2TpLocalIp),
+		PeerIP:        net.IP(cp.L2TpPeerIp),
+		LocalID:       uint16(cp.L2TpLocalTunnelId),
+		PeerID:        uint16(cp.L2TpPeerTunnelId),
+		LocalPort:     uint16(cp.L2TpLocalPort),
+		PeerPort:      uint16(cp.L2TpPeerPort),
+		Role:          cp.L2TpRole,
+		LocalHostname: cp.L2TpLocalHostname,
+		PeerHostname:  cp.L2TpPeerHostname,
+		Ns:            uint16(cp.L2TpNs),
+		Nr:            uint16(cp.L2TpNr),
+		HelloInterval: time.Duration(cp.L2TpHelloInterval) * time.Second,
+		PPPHdrSkip:    uint8(cp.L2TpPppHdrSkip),
+		SRGName:       cp.SrgName,
+
+		Failover:         cp.L2TpFailover,
+		PeerRecoveryTime: time.Duration(cp.L2TpPeerRecoveryTimeMs) * time.Millisecond,
+	}
+	srec := sessionRecord{
+		SessionID:      cp.SessionId,
+		AcctSessionID:  cp.AaaSessionId,
+		PeerIP:         trec.PeerIP,
+		TunnelID:       trec.LocalID,
+		LocalID:        uint16(cp.L2TpLocalSessionId),
+		PeerID:         uint16(cp.L2TpPeerSessionId),
+		Role:           cp.L2TpRole,
+		Username:       cp.Username,
+		VRF:            cp.Vrf,
+		ServiceGroup:   cp.ServiceGroup,
+		SRGName:        cp.SrgName,
+		Attributes:     cp.AaaAttributes,
+		IPv4Pool:       cp.Ipv4Pool,
+		IANAPool:       cp.IanaPool,
+		PDPool:         cp.PdPool,
+		LCPMagic:       cp.LcpMagic,
+		PPPoESessionID: uint16(cp.PppoeSessionId),
+	}
+	if len(cp.Ipv4Address) > 0 {
+		srec.IPv4Address = net.IP(cp.Ipv4Address)
+	}
+	if len(cp.Ipv6Address) > 0 {
+		srec.IPv6Address = net.IP(cp.Ipv6Address)
+	}
+	if len(cp.Ipv6Prefix) > 0 && cp.Ipv6PrefixLen > 0 {
+		pfx := net.IPNet{
+			IP:   net.IP(cp.Ipv6Prefix),
+			Mask: net.CIDRMask(int(cp.Ipv6PrefixLen), 128),
+		}
+		srec.IPv6Prefix = pfx.String()
+	}
+	if cp.BoundAtNs > 0 {
+		srec.ActivatedAt = time.Unix(0, cp.BoundAtNs)
+		trec.CreatedAt = srec.ActivatedAt
+	}
+	return trec, srec
+}
+
+func (c *Component) handleHAStateChange(event events.Event) {
+	data, ok := event.Data.(events.HAStateChangeEvent)
+	if !ok {
+		return
+	}
+
+	wasActive := data.OldState == string(ha.SRGStateActive) || data.OldState == string(ha.SRGStateActiveSolo)
+	isActive := data.NewState == string(ha.SRGStateActive) || data.NewState == string(ha.SRGStateActiveSolo)
+	isStandby := data.NewState == string(ha.SRGStateStandby) || data.NewState == string(ha.SRGStateStandbyAlone)
+
+	switch {
+	case isActive && !wasActive:
+		c.promoteSRGTunnels(data.SRGName)
+	case isStandby && wasActive:
+		c.demoteSRGTunnels(data.SRGName)
+	}
+}
+
+// promoteSRGTunnels takes over the SRG's standby tunnels: each is
+// installed in the dataplane, its control channel recovered with the
+// peer, and its LNS sessions handed to AAA via the Restored topic (no
+// duplicate Accounting-Start). Tunnels whose peer never agreed to
+// failover are closed instead.
+func (c *Component) promoteSRGTunnels(srgName string) {
+	var resumed int
+	for _, t := range c.srgTunnels(srgName, true) {
+		if err := c.resumeTunnel(t); err != nil {
+			if errors.Is(err, ErrNoFailoverAgreed) {
+				continue
+			}
+			c.log.Error("Failed to resume l2tp tunnel on promotion",
+				"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID, "error", err)
+			continue
+		}
+		resumed++
+	}
+	if resumed > 0 {
+		c.log.Info("Resumed l2tp tunnels on SRG promotion", "srg", srgName, "tunnels", resumed)
+	}
+}
+
+// demoteSRGTunnels hands the SRG's tunnels to the peer: the runner
+// stops, dataplane entries are removed and the control state is kept
+// as standby so a re-promotion resumes without renegotiating.
+func (c *Component) demoteSRGTunnels(srgName string) {
+	var demoted int
+	for _, t := range c.srgTunnels(srgName, false) {
+		c.stopTunnelRunner(t.PeerIP, t.LocalID)
+
+		t.mu.Lock()
+		t.resumeNs, t.resumeNr = t.sequence()
+		t.Channel = nil
+		t.standby = true
+		t.mu.Unlock()
+
+		for _, s := range t.snapshotSessions() {
+			if c.vpp != nil {
+				if err := c.vpp.DeleteL2TPSession(t.LocalIP, t.PeerIP, t.LocalID, s.LocalID); err != nil {
+					c.log.Debug("DeleteL2TPSession on demotion failed",
+						"session_id", s.SessionID, "error", err)
+				}
+			}
+			s.mu.Lock()
+			s.SwIfIndex = 0
+			s.programmedInVPP = false
+			s.mu.Unlock()
+		}
+		c.uninstallTunnelVPP(t)
+		demoted++
+	}
+	if demoted > 0 {
+		c.log.Info("Demoted l2tp tunnels on SRG transition", "srg", srgName, "tunnels", demoted)
+	}
+}
+
+func (c *Component) srgTunnels(srgName string, standby bool) []*Tunnel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []*Tunnel
+	for _, t := range c.tunnels {
+		t.mu.Lock()
+		match := t.SRGName == srgName && t.standby == standby
+		t.mu.Unlock()
+		if match {
+			out = append(out, t)
+		}
+	}
+	return out
+}
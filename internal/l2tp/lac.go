@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
 	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
 )
 
@@ -42,6 +43,10 @@ type LACBringUpRequest struct {
 	// plugin pick via FIB lookup on the peer IP.
 	EncapIfIndex uint32
 
+	// SRGName is the partner PPPoE subscriber's redundancy group; the
+	// LAC session is replicated to the standby under it.
+	SRGName string
+
 	// Proxy LCP / proxy auth replay material, copied into ICCN per
 	// RFC 3437.
 	LastSentLCPConfReq     []byte
@@ -58,8 +63,11 @@ type LACBringUpRequest struct {
 // sufficient since LAC restart implies fresh state.
 var lacCallSerial atomic.Uint32
 
-// StartLACSession kicks off LAC bring-up for one PPPoE subscriber. The
-// first non-denylisted TunnelSpec in `req` is tried; the outcome is
+// StartLACSession kicks off LAC bring-up for one PPPoE subscriber.
+// Candidates are tried in preference order; among equal preferences
+// the least loaded LNS relative to its weight goes first (see
+// orderLACCandidates). Denylisted LNSes, LNSes a health check marked
+// down and LNSes at their session cap are skipped. The outcome is
 // reported asynchronously on TopicL2TPLACDecision once the session has
 // either reached SessionEstablished or the candidate list is exhausted.
 //
@@ -75,19 +83,26 @@ func (c *Component) StartLACSession(req LACBringUpRequest) error {
 		return ErrSendNotConfigured
 	}
 
-	skipped := 0
-	for i := range req.TunnelSpecs {
-		spec := req.TunnelSpecs[i]
+	specs := c.orderLACCandidates(req.TunnelSpecs)
+	denied, unavailable := 0, 0
+	for i := range specs {
+		spec := specs[i]
 		if spec.ServerIP == nil {
 			continue
 		}
 		if c.denylist != nil && c.denylist.IsDenied(spec.ServerIP) {
-			skipped++
+			denied++
 			c.log.Debug("LAC candidate skipped (denylisted)",
 				"peer_ip", spec.ServerIP.String(),
 				"reason", c.denylist.Reason(spec.ServerIP))
 			continue
 		}
+		if reason := c.lnsUnavailable(spec.ServerIP); reason != "" {
+			unavailable++
+			c.log.Debug("LAC candidate skipped",
+				"peer_ip", spec.ServerIP.String(), "reason", reason)
+			continue
+		}
 		if err := c.tryLACTunnel(req, spec); err == nil {
 			return nil
 		} else {
@@ -95,19 +110,26 @@ func (c *Component) StartLACSession(req LACBringUpRequest) error {
 				"peer_ip", spec.ServerIP.String(), "error", err)
 		}
 	}
-	if skipped > 0 && skipped == len(req.TunnelSpecs) {
+	if denied > 0 && denied == len(specs) {
 		c.publishLACDecision(req.PPPoESessionID, nil, nil, ErrAllCandidatesDenied)
 		return ErrAllCandidatesDenied
 	}
+	if unavailable > 0 && denied+unavailable == len(specs) {
+		c.publishLACDecision(req.PPPoESessionID, nil, nil, ErrAllCandidatesUnavailable)
+		return ErrAllCandidatesUnavailable
+	}
 	c.publishLACDecision(req.PPPoESessionID, nil, nil, ErrNoTunnelCandidates)
 	return ErrNoTunnelCandidates
 }
 
-// tryLACTunnel attempts to bring up a tunnel + session against one
-// LNS candidate. Returns nil on success once the SCCRQ has been sent
-// (the rest of the bring-up runs asynchronously through Dispatch);
-// returns an error if local state setup failed and the caller should
-// move to the next candidate.
+// tryLACTunnel attempts to bring up a session against one LNS
+// candidate. A tunnel to the same LNS with room under the pool's
+// max-sessions-per-tunnel carries the session; otherwise a new tunnel
+// is opened. Returns nil on success once the ICRQ or SCCRQ has been
+// sent or the request queued behind a pending SCCRQ (the rest of the
+// bring-up runs asynchronously through Dispatch); returns an error if
+// local state setup failed and the caller should move to the next
+// candidate.
 func (c *Component) tryLACTunnel(req LACBringUpRequest, spec TunnelSpec) error {
 	peerIP := spec.ServerIP
 	localIP := req.LocalIP
@@ -115,19 +137,18 @@ func (c *Component) tryLACTunnel(req LACBringUpRequest, spec TunnelSpec) error {
 		localIP = spec.ClientIP
 	}
 	if localIP == nil {
-		// Vendor pattern: pool-config `source-ipv4` per-LNS (Cisco
-		// `source-ip`, RTBrick `client-ipv4`). Look up the running
-		// config and find the LNS entry whose IPv4 matches the peer,
-		// then use its SourceIPv4 as the L2TP local IP.
+		// Vendor pattern: pool-config `source-ipv4` / `source-ipv6`
+		// per-LNS (Cisco `source-ip`, RTBrick `client-ipv4`). Look up
+		// the running config and find the LNS entry whose address
+		// matches the peer, then use its source of the same family as
+		// the L2TP local IP.
 		localIP = c.lookupConfiguredSourceIP(peerIP)
 	}
 	if localIP == nil {
 		return ErrNoLocalIP
 	}
-
-	localTunnelID, err := c.allocateTunnelID(peerIP)
-	if err != nil {
-		return ErrTunnelExhaustion
+	if (localIP.To4() != nil) != (peerIP.To4() != nil) {
+		return ErrAddressFamilyMismatch
 	}
 
 	hostName := req.LocalName
@@ -135,6 +156,15 @@ func (c *Component) tryLACTunnel(req LACBringUpRequest, spec TunnelSpec) error {
 		hostName = c.localHostname
 	}
 
+	if t := c.findLACTunnel(peerIP, localIP, spec, hostName); t != nil {
+		return c.joinLACTunnel(t, &req)
+	}
+
+	localTunnelID, err := c.allocateTunnelID(peerIP)
+	if err != nil {
+		return ErrTunnelExhaustion
+	}
+
 	var ourChallenge []byte
 	if len(spec.Password) > 0 {
 		ourChallenge, err = l2tppkt.NewChallenge()
@@ -170,15 +200,10 @@ func (c *Component) tryLACTunnel(req LACBringUpRequest, spec TunnelSpec) error {
 
 	c.startTunnelRunner(t, 60*time.Second)
 
-	// Stash the bring-up request on the tunnel under the LAC mutex
+	// Queue the bring-up request on the tunnel under the LAC mutex
 	// so the SCCRP handler can find it when the tunnel reaches
 	// Established and the LAC session needs to be opened.
-	c.lacMu.Lock()
-	if c.lacPending == nil {
-		c.lacPending = make(map[tunnelKey]*LACBringUpRequest)
-	}
-	c.lacPending[makeTunnelKey(peerIP, localTunnelID)] = &req
-	c.lacMu.Unlock()
+	c.queueLACRequest(t, &req)
 
 	sccrqBody := l2tppkt.BuildSCCRQ(l2tppkt.SCCRQParams{
 		LocalTunnelID:     localTunnelID,
@@ -187,6 +212,7 @@ func (c *Component) tryLACTunnel(req LACBringUpRequest, spec TunnelSpec) error {
 		FramingCaps:       l2tppkt.FramingSync,
 		BearerCaps:        l2tppkt.BearerDigital,
 		Challenge:         ourChallenge,
+		Failover:          c.failover,
 	})
 	if err := t.Channel.Send(sccrqBody, time.Now()); err != nil {
 		c.stopTunnelRunner(peerIP, localTunnelID)
@@ -253,9 +279,22 @@ func (c *Component) handleSCCRP(t *Tunnel, avps []l2tppkt.AVP) error {
 		)
 	}
 
+	if t.recovers != nil {
+		return c.completeRecovery(t, avps, peerResp)
+	}
+
+	// A health-check probe has seen what it needed: the LNS answers
+	// and, with a secret, authenticates. Close it without SCCCN.
+	if t.probe {
+		body := l2tppkt.BuildStopCCN(t.LocalID, l2tppkt.ResultStopGeneralRequest,
+			l2tppkt.ErrorNoGeneralError, "health check")
+		return t.Channel.Send(body, time.Now())
+	}
+
 	if err := t.FSM.RecvSCCRP(); err != nil {
 		return err
 	}
+	c.noteFailover(t, avps)
 
 	sccccnBody := l2tppkt.BuildSCCCN(peerResp)
 	if err := t.Channel.Send(sccccnBody, time.Now()); err != nil {
@@ -272,14 +311,55 @@ func (c *Component) handleSCCRP(t *Tunnel, avps []l2tppkt.AVP) error {
 			"error", err)
 		return err
 	}
+	c.checkpointTunnel(t)
+	c.accountTunnelStart(t)
 
-	// Open the pending LAC session.
+	// Open a session for every request queued while the tunnel came
+	// up.
+	reqs := c.clearLACPending(t.PeerIP, t.LocalID)
+	if len(reqs) == 0 {
+		return ErrLACRequestMissing
+	}
+	var firstErr error
+	for _, req := range reqs {
+		if err := c.openLACSession(t, req); err != nil {
+			c.publishLACDecision(req.PPPoESessionID, nil, nil, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// queueLACRequest parks a bring-up request on a tunnel waiting for
+// SCCRP.
+func (c *Component) queueLACRequest(t *Tunnel, req *LACBringUpRequest) {
+	k := makeTunnelKey(t.PeerIP, t.LocalID)
 	c.lacMu.Lock()
-	req := c.lacPending[makeTunnelKey(t.PeerIP, t.LocalID)]
+	if c.lacPending == nil {
+		c.lacPending = make(map[tunnelKey][]*LACBringUpRequest)
+	}
+	c.lacPending[k] = append(c.lacPending[k], req)
 	c.lacMu.Unlock()
-	if req == nil {
-		return ErrLACRequestMissing
+}
+
+// joinLACTunnel carries a new session on an existing LAC tunnel. A
+// tunnel still waiting for SCCRP queues the request; handleSCCRP moves
+// the tunnel to Established before it drains the queue, and both sides
+// decide under lacMu, so no request is left behind.
+func (c *Component) joinLACTunnel(t *Tunnel, req *LACBringUpRequest) error {
+	c.lacMu.Lock()
+	if t.FSM.State() != l2tppkt.TunnelEstablished {
+		if c.lacPending == nil {
+			c.lacPending = make(map[tunnelKey][]*LACBringUpRequest)
+		}
+		k := makeTunnelKey(t.PeerIP, t.LocalID)
+		c.lacPending[k] = append(c.lacPending[k], req)
+		c.lacMu.Unlock()
+		return nil
 	}
+	c.lacMu.Unlock()
 	return c.openLACSession(t, req)
 }
 
@@ -312,6 +392,7 @@ func (c *Component) openLACSession(t *Tunnel, req *LACBringUpRequest) error {
 		Username:       req.Username,
 		PPPoESessionID: req.PPPoESessionID,
 		Attributes:     make(map[string]string),
+		lacReq:         req,
 	}
 	if err := s.FSM.SendICRQ(); err != nil {
 		return err
@@ -349,9 +430,9 @@ func (c *Component) handleICRP(s *Session, avps []l2tppkt.AVP) error {
 	}
 
 	t := s.Tunnel
-	c.lacMu.Lock()
-	req := c.lacPending[makeTunnelKey(t.PeerIP, t.LocalID)]
-	c.lacMu.Unlock()
+	s.mu.Lock()
+	req := s.lacReq
+	s.mu.Unlock()
 	if req == nil {
 		return ErrLACRequestMissing
 	}
@@ -372,34 +453,78 @@ func (c *Component) handleICRP(s *Session, avps []l2tppkt.AVP) error {
 		return err
 	}
 
-	if c.vpp != nil {
-		poolIndex, err := c.vpp.AddL2TPSessionRaw(
-			t.LocalIP, t.PeerIP,
-			t.LocalID, s.LocalID, s.PeerID,
-			lacRawNextNode, req.PPPoESwIfIndex, req.EncapIfIndex,
-			t.PPPHdrSkip,
-		)
-		if err != nil {
-			c.log.Error("AddL2TPSessionRaw failed; aborting LAC bring-up",
-				"session_id", s.SessionID, "error", err)
-			c.clearLACPending(t.PeerIP, t.LocalID)
-			c.publishLACDecision(req.PPPoESessionID, nil, nil, err)
-			return err
-		}
+	s.mu.Lock()
+	s.EncapIfIndex = req.EncapIfIndex
+	s.SRGName = req.SRGName
+	s.mu.Unlock()
+	if err := c.installLACSessionVPP(s, req.PPPoESwIfIndex); err != nil {
+		c.log.Error("AddL2TPSessionRaw failed; aborting LAC bring-up",
+			"session_id", s.SessionID, "error", err)
 		s.mu.Lock()
-		s.SwIfIndex = poolIndex
+		s.lacReq = nil
 		s.mu.Unlock()
+		c.publishLACDecision(req.PPPoESessionID, nil, nil, err)
+		return err
 	}
 
 	s.mu.Lock()
 	s.ActivatedAt = time.Now()
 	s.PPPoESwIfIndex = req.PPPoESwIfIndex
+	c.checkpointSession(s)
+	c.publishSessionSync(s, models.SessionStateActive)
+	c.accountLinkStart(s)
+	s.lacReq = nil
 	s.mu.Unlock()
-	c.clearLACPending(t.PeerIP, t.LocalID)
 	c.publishLACDecision(req.PPPoESessionID, t, s, nil)
 	return nil
 }
 
+// installLACSessionVPP installs (or re-installs) the DECAP_RAW entry
+// for a LAC session, bridging LNS→subscriber frames to the PPPoE
+// session at pppoeSwIfIndex. A stale entry from an earlier install is
+// removed first.
+func (c *Component) installLACSessionVPP(s *Session, pppoeSwIfIndex uint32) error {
+	if c.vpp == nil {
+		return nil
+	}
+	t := s.Tunnel
+	t.mu.Lock()
+	installed := t.installedInVPP
+	t.mu.Unlock()
+	if !installed {
+		return ErrTunnelNotInstalled
+	}
+
+	s.mu.Lock()
+	stale := s.SwIfIndex != 0
+	encap := s.EncapIfIndex
+	s.mu.Unlock()
+	if stale {
+		if err := c.vpp.DeleteL2TPSession(t.LocalIP, t.PeerIP, t.LocalID, s.LocalID); err != nil {
+			c.log.Debug("DeleteL2TPSession of stale LAC session failed",
+				"session_id", s.SessionID, "error", err)
+		}
+	}
+
+	poolIndex, err := c.vpp.AddL2TPSessionRaw(
+		t.LocalIP, t.PeerIP,
+		t.LocalID, s.LocalID, s.PeerID,
+		lacRawNextNode, pppoeSwIfIndex, encap,
+		t.PPPHdrSkip,
+	)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.SwIfIndex = poolIndex
+	s.PPPoESwIfIndex = pppoeSwIfIndex
+	if stale {
+		c.checkpointSession(s)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
 // lacRawNextNode is the VPP graph node the L2TPv2 plugin forwards
 // decapsulated PPP frames to in the LNS→subscriber direction. The
 // PPPoE plugin registers this node at init time per
@@ -441,27 +566,37 @@ func (c *Component) publishLACDecision(pppoeSessionID uint16, t *Tunnel, s *Sess
 	})
 }
 
-func (c *Component) clearLACPending(peerIP net.IP, localTunnelID uint16) {
+// clearLACPending removes and returns the requests queued on a
+// tunnel.
+func (c *Component) clearLACPending(peerIP net.IP, localTunnelID uint16) []*LACBringUpRequest {
+	k := makeTunnelKey(peerIP, localTunnelID)
 	c.lacMu.Lock()
-	delete(c.lacPending, makeTunnelKey(peerIP, localTunnelID))
+	reqs := c.lacPending[k]
+	delete(c.lacPending, k)
 	c.lacMu.Unlock()
+	return reqs
 }
 
 var (
 	ErrNoTunnelCandidates  = errors.New("l2tp: no LAC tunnel candidates")
 	ErrAllCandidatesDenied = errors.New("l2tp: all LAC candidates denylisted")
+	ErrAllCandidatesUnavailable = errors.New("l2tp: all LAC candidates down, denylisted or at their session cap")
+	ErrLACTunnelClosed     = errors.New("l2tp: tunnel closed during LAC bring-up")
 	ErrNotSCCRP           = errors.New("l2tp: expected SCCRP")
 	ErrNotICRP            = errors.New("l2tp: expected ICRP")
 	ErrUnexpectedSCCRP    = errors.New("l2tp: SCCRP on non-initiator tunnel")
 	ErrUnexpectedICRP     = errors.New("l2tp: ICRP on non-LAC session")
 	ErrLACRequestMissing  = errors.New("l2tp: LAC bring-up request lost")
-	ErrNoLocalIP          = errors.New("l2tp: no local IP for tunnel; set tunnel-pool lns.source-ipv4 or AAA Tunnel-Client-Endpoint")
+	ErrNoLocalIP          = errors.New("l2tp: no local IP for tunnel; set tunnel-pool lns.source-ipv4/source-ipv6 or AAA Tunnel-Client-Endpoint")
+	ErrAddressFamilyMismatch = errors.New("l2tp: tunnel local and peer addresses are of different families")
+	ErrTunnelNotInstalled    = errors.New("l2tp: tunnel not installed in the dataplane")
 )
 
 // lookupConfiguredSourceIP scans the running L2TPConfig for an LNS
-// entry whose `ipv4` matches `peerIP` and returns its `source-ipv4`.
-// Returns nil if no match. The scan is bounded by the number of LNS
-// entries configured (typically <100), so a linear walk is fine.
+// entry whose `ipv4` or `ipv6` matches `peerIP` and returns its source
+// address of the same family (`source-ipv4` / `source-ipv6`). Returns
+// nil if no match. The scan is bounded by the number of LNS entries
+// configured (typically <100), so a linear walk is fine.
 func (c *Component) lookupConfiguredSourceIP(peerIP net.IP) net.IP {
 	if c.cfgMgr == nil {
 		return nil
@@ -475,14 +610,10 @@ func (c *Component) lookupConfiguredSourceIP(peerIP net.IP) net.IP {
 			continue
 		}
 		for i := range pool.LNS {
-			lnsIP := net.ParseIP(pool.LNS[i].IPv4)
-			if lnsIP == nil || !lnsIP.Equal(peerIP) {
-				continue
-			}
-			if pool.LNS[i].SourceIPv4 == "" {
+			if !pool.LNS[i].Matches(peerIP) {
 				continue
 			}
-			if src := net.ParseIP(pool.LNS[i].SourceIPv4); src != nil {
+			if src := pool.LNS[i].SourceAddress(peerIP); src != nil {
 				return src
 			}
 		}
@@ -208,3 +208,62 @@ func TestLACSCCRPTriggersSCCCNAndICRQ(t *testing.T) {
 	// Allow the bus a moment in case the test runner is loaded.
 	time.Sleep(10 * time.Millisecond)
 }
+
+func TestLACSCCRPOverIPv6(t *testing.T) {
+	cap := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(cap.Send)
+	c.SetLocalHostname("bng1")
+
+	local6 := net.ParseIP("2001:db8::1")
+	peer6 := net.ParseIP("2001:db8::2")
+	if err := c.StartLACSession(LACBringUpRequest{
+		PPPoESessionID: 8,
+		LocalIP:        local6,
+		TunnelSpecs:    []TunnelSpec{{ServerIP: peer6, Password: "shared"}},
+	}); err != nil {
+		t.Fatalf("StartLACSession: %v", err)
+	}
+	tnl := c.LookupTunnel(peer6, 1)
+	if tnl == nil {
+		t.Fatal("IPv6 LAC tunnel not registered with LocalID=1")
+	}
+
+	sccrpBody := buildSCCRPBody(42, tnl.Secret, tnl.outstandingChallenge)
+	hdr := l2tppkt.NewControl(tnl.LocalID, 0, 0, 1)
+	wire := hdr.AppendTo(make([]byte, 0, 12+len(sccrpBody)), len(sccrpBody))
+	wire = append(wire, sccrpBody...)
+
+	pkt := &dataplane.ParsedPacket{
+		Protocol: models.ProtocolL2TP,
+		IPv6:     &layers.IPv6{SrcIP: peer6, DstIP: local6},
+		UDP:      &layers.UDP{SrcPort: 1701, DstPort: 1701},
+	}
+	pkt.UDP.Payload = wire
+	if err := c.Dispatch(pkt); err != nil {
+		t.Fatalf("Dispatch sccrp: %v", err)
+	}
+	if tnl.PeerID != 42 {
+		t.Fatalf("PeerID = %d, want 42 (from SCCRP)", tnl.PeerID)
+	}
+	if got := len(cap.snapshot()); got < 3 {
+		t.Fatalf("expected SCCRQ, SCCCN, ICRQ; got %d packets", got)
+	}
+}
+
+func TestStartLACSessionFamilyMismatch(t *testing.T) {
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(func(_, _ net.IP, _, _ uint16, _ l2tppkt.Header, _ []byte) error { return nil })
+
+	err := c.StartLACSession(LACBringUpRequest{
+		PPPoESessionID: 9,
+		LocalIP:        net.IPv4(10, 0, 0, 1),
+		TunnelSpecs:    []TunnelSpec{{ServerIP: net.ParseIP("2001:db8::2")}},
+	})
+	if err != ErrNoTunnelCandidates {
+		t.Fatalf("want ErrNoTunnelCandidates, got %v", err)
+	}
+	if c.LookupTunnel(net.ParseIP("2001:db8::2"), 1) != nil {
+		t.Fatal("tunnel registered despite address family mismatch")
+	}
+}
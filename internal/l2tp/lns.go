@@ -9,6 +9,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/veesix-networks/osvbng/pkg/auth"
 	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
 )
 
@@ -113,6 +114,11 @@ func (c *Component) HandleSCCRQ(localIP, peerIP net.IP, avps []l2tppkt.AVP, cfg
 		c.releaseTunnelID(peerIP, localID)
 		return nil, nil, err
 	}
+	c.noteFailover(t, avps)
+	var failover *l2tppkt.FailoverCapability
+	if t.failover {
+		failover = c.failover
+	}
 	if err := c.registerTunnel(t); err != nil {
 		c.releaseTunnelID(peerIP, localID)
 		return nil, nil, err
@@ -129,6 +135,7 @@ func (c *Component) HandleSCCRQ(localIP, peerIP net.IP, avps []l2tppkt.AVP, cfg
 		BearerCaps:        l2tppkt.BearerDigital,
 		Challenge:         ourChallenge,
 		ChallengeResponse: ourChallengeResp,
+		Failover:          failover,
 	})
 	return sccrpBody, t, nil
 }
@@ -156,6 +163,10 @@ func (c *Component) HandleSCCCN(t *Tunnel, avps []l2tppkt.AVP, outstandingChalle
 		t.outstandingChallenge = nil
 		t.mu.Unlock()
 	}
+	if t.recovers != nil {
+		// A recovery tunnel carries nothing; the peer closes it.
+		return nil
+	}
 	if err := t.FSM.RecvSCCCN(); err != nil {
 		return err
 	}
@@ -169,6 +180,8 @@ func (c *Component) HandleSCCCN(t *Tunnel, avps []l2tppkt.AVP, outstandingChalle
 			"error", err)
 		return err
 	}
+	c.checkpointTunnel(t)
+	c.accountTunnelStart(t)
 	return nil
 }
 
@@ -245,25 +258,21 @@ func (c *Component) HandleICCN(s *Session, avps []l2tppkt.AVP) error {
 	if err := s.FSM.RecvICCN(); err != nil {
 		return err
 	}
+	s.mu.Lock()
+	c.accountLinkStart(s)
+	s.mu.Unlock()
 	c.initSessionPPP(s)
 	return nil
 }
 
-// HandleCDN tears the session down.
+// HandleCDN tears the session down at the peer's request.
 func (c *Component) HandleCDN(s *Session) {
-	s.FSM.Disconnect()
-	if s.Tunnel != nil {
-		s.Tunnel.removeSession(s.LocalID)
-	}
+	c.teardownSession(s, auth.TerminateCauseUserRequest)
 }
 
-// HandleStopCCN tears the tunnel down.
+// HandleStopCCN tears the tunnel down at the peer's request.
 func (c *Component) HandleStopCCN(t *Tunnel) {
-	t.FSM.Stop()
-	c.stopTunnelRunner(t.PeerIP, t.LocalID)
-	c.unregisterTunnel(t.PeerIP, t.LocalID)
-	c.releaseTunnelID(t.PeerIP, t.LocalID)
-	c.uninstallTunnelVPP(t)
+	c.teardownTunnel(t, auth.TerminateCauseNASRequest)
 }
 
 var (
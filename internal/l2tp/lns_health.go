@@ -0,0 +1,379 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"time"
+
+	l2tpcfg "github.com/veesix-networks/osvbng/pkg/config/l2tp"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// LNS health states. An LNS starts unknown and is selectable until a
+// health check marks it down.
+const (
+	lnsStateUnknown = "unknown"
+	lnsStateUp      = "up"
+	lnsStateDown    = "down"
+)
+
+// healthTick is how often the health loop looks for due probes. Probe
+// intervals are configured per pool and are much longer.
+const healthTick = time.Second
+
+var (
+	ErrProbeTimeout = errors.New("l2tp: LNS health probe timed out")
+	ErrProbeClosed  = errors.New("l2tp: LNS health probe tunnel closed")
+)
+
+// lnsHealth is the health-check state of one LNS endpoint.
+type lnsHealth struct {
+	state      string
+	probing    bool
+	nextProbe  time.Time
+	lastProbe  time.Time
+	lastChange time.Time
+	lastError  string
+
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	probes               uint64
+	probeFailures        uint64
+}
+
+// healthLoop probes the LNS entries of every tunnel pool with a
+// health-check block until ctx is cancelled.
+func (c *Component) healthLoop(ctx context.Context) {
+	ticker := time.NewTicker(healthTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.runHealthChecks(ctx, now)
+		}
+	}
+}
+
+// runHealthChecks starts the probes that are due and forgets LNS
+// entries no longer under a health-checked pool.
+func (c *Component) runHealthChecks(ctx context.Context, now time.Time) {
+	cfg := c.runningL2TPConfig()
+	seen := make(map[string]bool)
+
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	if c.lnsHealth == nil {
+		c.lnsHealth = make(map[string]*lnsHealth)
+	}
+	if cfg != nil {
+		for _, pool := range cfg.TunnelPools {
+			if pool == nil || pool.HealthCheck == nil {
+				continue
+			}
+			hc := pool.HealthCheck.WithDefaults()
+			for i := range pool.LNS {
+				ref := pool.LNS[i]
+				addr := ref.Address()
+				if addr == nil {
+					continue
+				}
+				key := addr.String()
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				h := c.lnsHealth[key]
+				if h == nil {
+					h = &lnsHealth{state: lnsStateUnknown}
+					c.lnsHealth[key] = h
+				}
+				if h.probing || now.Before(h.nextProbe) {
+					continue
+				}
+				h.probing = true
+				h.nextProbe = now.Add(hc.Interval)
+				hostName := pool.LocalName
+				go func() {
+					err := c.probeLNS(ctx, addr, &ref, hostName, hc)
+					c.recordProbe(key, err, hc)
+				}()
+			}
+		}
+	}
+	for key, h := range c.lnsHealth {
+		if !seen[key] && !h.probing {
+			delete(c.lnsHealth, key)
+		}
+	}
+}
+
+// probeLNS runs one health check against an LNS. The Hello method
+// uses an established LAC tunnel when there is one.
+func (c *Component) probeLNS(ctx context.Context, addr net.IP, ref *l2tpcfg.LNSRef, hostName string, hc l2tpcfg.HealthCheck) error {
+	if hc.Method == l2tpcfg.HealthCheckHello {
+		if t := c.establishedLACTunnel(addr); t != nil {
+			return c.probeHello(ctx, t, hc.Timeout)
+		}
+	}
+	return c.probeSCCRQ(ctx, addr, ref, hostName, hc.Timeout)
+}
+
+func (c *Component) establishedLACTunnel(addr net.IP) *Tunnel {
+	for _, t := range c.lacTunnels() {
+		if t.PeerIP.Equal(addr) && t.Channel != nil && !t.isStandby() &&
+			t.FSM.State() == l2tppkt.TunnelEstablished {
+			return t
+		}
+	}
+	return nil
+}
+
+// probeHello sends a Hello on an established tunnel and succeeds once
+// the peer sends anything back, the ACK included.
+func (c *Component) probeHello(ctx context.Context, t *Tunnel, timeout time.Duration) error {
+	inbound := func() uint64 {
+		st := t.Channel.Stats()
+		return st.Received + st.ZLBReceived
+	}
+	before := inbound()
+	if err := t.Channel.Send(l2tppkt.BuildHello(), time.Now()); err != nil {
+		return err
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	poll := time.NewTicker(100 * time.Millisecond)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return ErrProbeTimeout
+		case <-poll.C:
+			if inbound() != before {
+				return nil
+			}
+		}
+	}
+}
+
+// probeSCCRQ opens a throwaway control connection to the LNS. SCCRP
+// (with a valid Challenge-Response when a secret is configured) is a
+// pass; handleSCCRP answers it with StopCCN. Silence, StopCCN or a bad
+// response is a failure.
+func (c *Component) probeSCCRQ(ctx context.Context, addr net.IP, ref *l2tpcfg.LNSRef, hostName string, timeout time.Duration) error {
+	t, err := c.openProbeTunnel(addr, ref, hostName)
+	if err != nil {
+		return err
+	}
+	defer c.teardownTunnel(t, "")
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	select {
+	case err := <-t.probeResult:
+		return err
+	case <-deadline.C:
+		return ErrProbeTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// openProbeTunnel registers a probe tunnel and sends its SCCRQ. The
+// outcome arrives on t.probeResult; the caller owns the teardown.
+func (c *Component) openProbeTunnel(addr net.IP, ref *l2tpcfg.LNSRef, hostName string) (*Tunnel, error) {
+	if c.send == nil {
+		return nil, ErrSendNotConfigured
+	}
+	localIP := ref.SourceAddress(addr)
+	if localIP == nil {
+		return nil, ErrNoLocalIP
+	}
+	if hostName == "" {
+		hostName = c.localHostname
+	}
+
+	localID, err := c.allocateTunnelID(addr)
+	if err != nil {
+		return nil, ErrTunnelExhaustion
+	}
+	var challenge []byte
+	if ref.Secret != "" {
+		if challenge, err = l2tppkt.NewChallenge(); err != nil {
+			c.releaseTunnelID(addr, localID)
+			return nil, err
+		}
+	}
+
+	t := &Tunnel{
+		LocalIP:              localIP,
+		PeerIP:               addr,
+		LocalID:              localID,
+		LocalPort:            1701,
+		PeerPort:             1701,
+		Role:                 l2tppkt.RoleInitiator,
+		FSM:                  l2tppkt.NewTunnelFSM(l2tppkt.RoleInitiator),
+		LocalHostname:        hostName,
+		Secret:               []byte(ref.Secret),
+		Sessions:             make(map[uint16]*Session),
+		CreatedAt:            time.Now(),
+		outstandingChallenge: challenge,
+		probe:                true,
+		probeResult:          make(chan error, 1),
+	}
+	if err := t.FSM.SendSCCRQ(); err != nil {
+		c.releaseTunnelID(addr, localID)
+		return nil, err
+	}
+	if err := c.registerTunnel(t); err != nil {
+		c.releaseTunnelID(addr, localID)
+		return nil, err
+	}
+	c.startTunnelRunner(t, 60*time.Second)
+
+	body := l2tppkt.BuildSCCRQ(l2tppkt.SCCRQParams{
+		LocalTunnelID:     localID,
+		ReceiveWindowSize: 16,
+		HostName:          hostName,
+		FramingCaps:       l2tppkt.FramingSync,
+		BearerCaps:        l2tppkt.BearerDigital,
+		Challenge:         challenge,
+	})
+	if err := t.Channel.Send(body, time.Now()); err != nil {
+		c.teardownTunnel(t, "")
+		return nil, err
+	}
+	return t, nil
+}
+
+// recordProbe folds a probe outcome into the LNS state. A missing
+// source address is a configuration gap, not a verdict on the LNS.
+func (c *Component) recordProbe(key string, err error, hc l2tpcfg.HealthCheck) {
+	now := time.Now()
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	h := c.lnsHealth[key]
+	if h == nil {
+		return
+	}
+	h.probing = false
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if errors.Is(err, ErrNoLocalIP) {
+		h.lastError = err.Error()
+		return
+	}
+
+	h.probes++
+	h.lastProbe = now
+	if err != nil {
+		h.probeFailures++
+		h.consecutiveFailures++
+		h.consecutiveSuccesses = 0
+		h.lastError = err.Error()
+		if h.state != lnsStateDown && h.consecutiveFailures >= hc.DownAfter {
+			h.state = lnsStateDown
+			h.lastChange = now
+			c.log.Warn("LNS failed health check, marked down",
+				"peer_ip", key, "failures", h.consecutiveFailures, "error", err)
+		}
+		return
+	}
+
+	h.consecutiveFailures = 0
+	h.consecutiveSuccesses++
+	h.lastError = ""
+	switch {
+	case h.state == lnsStateUnknown:
+		h.state = lnsStateUp
+		h.lastChange = now
+	case h.state == lnsStateDown && h.consecutiveSuccesses >= hc.UpAfter:
+		h.state = lnsStateUp
+		h.lastChange = now
+		c.log.Info("LNS passed health check, marked up", "peer_ip", key)
+	}
+}
+
+// lnsDown reports whether a health check has marked the LNS down.
+func (c *Component) lnsDown(ip net.IP) bool {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	h := c.lnsHealth[ip.String()]
+	return h != nil && h.state == lnsStateDown
+}
+
+// SnapshotLNS returns the state of every tunnel-pool LNS entry: health,
+// load and whether the LAC would select it. Used by `show l2tp lns`
+// and its telemetry.
+func (c *Component) SnapshotLNS() []models.L2TPLNSStatus {
+	out := []models.L2TPLNSStatus{}
+	cfg := c.runningL2TPConfig()
+	if cfg == nil {
+		return out
+	}
+	load := c.lacLoad()
+
+	poolNames := make([]string, 0, len(cfg.TunnelPools))
+	for name := range cfg.TunnelPools {
+		poolNames = append(poolNames, name)
+	}
+	sort.Strings(poolNames)
+
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	for _, poolName := range poolNames {
+		pool := cfg.TunnelPools[poolName]
+		if pool == nil {
+			continue
+		}
+		for i := range pool.LNS {
+			ref := &pool.LNS[i]
+			addr := ref.Address()
+			if addr == nil {
+				continue
+			}
+			key := addr.String()
+			l := load[key]
+			st := models.L2TPLNSStatus{
+				Pool:        poolName,
+				Name:        ref.Name,
+				Address:     key,
+				State:       lnsStateUnknown,
+				Preference:  ref.Preference,
+				Weight:      ref.EffectiveWeight(),
+				MaxSessions: ref.MaxSessions,
+				Sessions:    l.sessions,
+				Tunnels:     l.tunnels,
+			}
+			if c.denylist != nil {
+				st.Denylisted = c.denylist.IsDenied(addr)
+			}
+			if h := c.lnsHealth[key]; h != nil {
+				st.State = h.state
+				st.Probes = h.probes
+				st.ProbeFailures = h.probeFailures
+				st.ConsecutiveFailures = h.consecutiveFailures
+				st.LastProbe = h.lastProbe
+				st.LastChange = h.lastChange
+				st.LastError = h.lastError
+			}
+			st.Up = st.State == lnsStateUp
+			st.Available = st.State != lnsStateDown && !st.Denylisted &&
+				(ref.MaxSessions == 0 || l.sessions < ref.MaxSessions)
+			out = append(out, st)
+		}
+	}
+	return out
+}
@@ -13,10 +13,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/veesix-networks/osvbng/pkg/aaa"
 	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
 	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
 	"github.com/veesix-networks/osvbng/pkg/events"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
 	"github.com/veesix-networks/osvbng/pkg/models"
 	"github.com/veesix-networks/osvbng/pkg/ppp"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
 )
 
 const (
@@ -234,6 +237,7 @@ func (c *Component) onAuthResult(s *Session, allowed bool, attributes map[string
 		}
 		c.extractIPFromAttributes(s)
 		c.resolveServiceGroup(s, attributes)
+		s.SRGName = c.resolveLNSSRG()
 		s.AllocCtx = c.buildAllocContext(s, attributes)
 
 		switch s.pendingAuthType {
@@ -367,6 +371,24 @@ func (c *Component) resolveLNSSubscriberGroup() *subscriber.SubscriberGroup {
 	return nil
 }
 
+// resolveLNSSRG returns the redundancy group owning the LNS
+// subscriber-group, or "" when HA is not configured.
+func (c *Component) resolveLNSSRG() string {
+	if c.srgMgr == nil || c.cfgMgr == nil {
+		return ""
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil || cfg.SubscriberGroups == nil {
+		return ""
+	}
+	for name, g := range cfg.SubscriberGroups.Groups {
+		if g != nil && g.HasAccessType(subscriber.AccessTypeLNS) {
+			return c.srgMgr.GetSRGForGroup(name)
+		}
+	}
+	return ""
+}
+
 // startNCP allocates IPv4 / IPv6 / PD from the configured pools, plumbs
 // the values into the IPCP / IPv6CP FSMs, and drives both layers Open.
 // Called with s.mu held.
@@ -540,6 +562,7 @@ func (c *Component) checkSessionOpen(s *Session) {
 
 	c.programSessionVPP(s)
 	c.publishSessionLifecycle(s, models.SessionStateActive)
+	c.checkpointSession(s)
 }
 
 // installLNSSessionVPP creates the per-session DECAP_IP entry in the
@@ -590,7 +613,10 @@ func (c *Component) installLNSSessionVPP(s *Session) error {
 //      effect of vnet_sw_interface_update_unnumbered, and gives the
 //      iface a source IP (the loopback's) for L3 forwarding;
 //   2. install the subscriber's /32 v4, /128 v6 IANA, and PD routes
-//      via the per-session iface so FIB lookups forward both directions.
+//      via the per-session iface so FIB lookups forward both directions;
+//   3. apply the resolved service group's QoS / ACL / uRPF bindings
+//      through svcgroup.ApplyToSession, so AAA-returned rates shape the
+//      session as they do IPoE and PPPoE.
 func (c *Component) programSessionVPP(s *Session) {
 	if c.vpp == nil || s.SwIfIndex == 0 {
 		return
@@ -630,6 +656,25 @@ func (c *Component) programSessionVPP(s *Session) {
 				"session_id", s.SessionID, "prefix", s.IPv6Prefix.String(), "error", err)
 		}
 	}
+
+	if err := c.applyServiceGroupBindings(s); err != nil {
+		c.log.Error("L2TP service-group bindings failed",
+			"session_id", s.SessionID, "service_group", s.ServiceGroup.Name, "error", err)
+	}
+}
+
+// applyServiceGroupBindings programs the session's resolved service
+// group onto its vnet interface, resolving QoS policy references
+// against the running config. Shared by NCP-up and restore; the
+// southbound calls are idempotent. Called with s.mu held.
+func (c *Component) applyServiceGroupBindings(s *Session) error {
+	var qosPolicies map[string]*qos.Policy
+	if c.cfgMgr != nil {
+		if cfg, _ := c.cfgMgr.GetRunning(); cfg != nil {
+			qosPolicies = cfg.QoSPolicies
+		}
+	}
+	return svcgroup.ApplyToSession(c.vpp, s.SwIfIndex, s.ServiceGroup, qosPolicies)
 }
 
 // publishSessionLifecycle emits a SessionLifecycleEvent carrying a
@@ -639,12 +684,48 @@ func (c *Component) publishSessionLifecycle(s *Session, state models.SessionStat
 	if c.eventBus == nil {
 		return
 	}
+	c.eventBus.Publish(events.TopicSessionLifecycle, events.Event{
+		Source: c.Name(),
+		Data: &events.SessionLifecycleEvent{
+			AccessType: models.AccessTypeL2TP,
+			Protocol:   models.ProtocolL2TP,
+			SessionID:  s.SessionID,
+			State:      state,
+			Session:    c.sessionModel(s, state),
+		},
+	})
+}
+
+// publishSessionSync hands session state to HA replication only, for
+// transitions the lifecycle topic does not carry (LAC sessions, LNS
+// teardown). Called with s.mu held.
+func (c *Component) publishSessionSync(s *Session, state models.SessionState) {
+	if c.eventBus == nil || s.SRGName == "" {
+		return
+	}
+	c.eventBus.Publish(events.TopicL2TPSessionSync, events.Event{
+		Source: c.Name(),
+		Data: &events.SessionLifecycleEvent{
+			AccessType: models.AccessTypeL2TP,
+			Protocol:   models.ProtocolL2TP,
+			SessionID:  s.SessionID,
+			State:      state,
+			Session:    c.sessionModel(s, state),
+		},
+	})
+}
+
+// sessionModel snapshots a session and its tunnel into the event
+// model, including the control-channel sequence state HA needs to
+// take the tunnel over. Called with s.mu held.
+func (c *Component) sessionModel(s *Session, state models.SessionState) *models.PPPoL2TPSession {
 	var v6Prefix string
 	if s.IPv6Prefix != nil {
 		v6Prefix = s.IPv6Prefix.String()
 	}
 	t := s.Tunnel
-	payload := &models.PPPoL2TPSession{
+	ns, nr := t.sequence()
+	m := &models.PPPoL2TPSession{
 		SessionID:      s.SessionID,
 		State:          state,
 		AccessType:     string(models.AccessTypeL2TP),
@@ -656,7 +737,16 @@ func (c *Component) publishSessionLifecycle(s *Session, state models.SessionStat
 		PeerTunnelID:   t.PeerID,
 		LocalSessionID: s.LocalID,
 		PeerSessionID:  s.PeerID,
-		LACHostname:    t.PeerHostname,
+		Role:           sessionRoleName(s.Role),
+		LocalPort:      t.LocalPort,
+		PeerPort:       t.PeerPort,
+		LocalHostname:  t.LocalHostname,
+		PeerHostname:   t.PeerHostname,
+		Ns:             ns,
+		Nr:             nr,
+		HelloInterval:  t.HelloInterval,
+		PPPHdrSkip:     t.PPPHdrSkip,
+		PPPoESessionID: s.PPPoESessionID,
 		IfIndex:        s.SwIfIndex,
 		VRF:            s.VRF,
 		ServiceGroup:   s.ServiceGroup.Name,
@@ -666,18 +756,20 @@ func (c *Component) publishSessionLifecycle(s *Session, state models.SessionStat
 		IPv6Prefix:     v6Prefix,
 		IPv4Pool:       s.allocatedPool,
 		IANAPool:       s.allocatedIANAPool,
+		PDPool:         s.allocatedPDPool,
 		LCPMagic:       s.LCPMagic,
 		Username:       s.Username,
 		ActivatedAt:    s.ActivatedAt,
 	}
-	c.eventBus.Publish(events.TopicSessionLifecycle, events.Event{
-		Source: c.Name(),
-		Data: &events.SessionLifecycleEvent{
-			AccessType: models.AccessTypeL2TP,
-			Protocol:   models.ProtocolL2TP,
-			SessionID:  s.SessionID,
-			State:      state,
-			Session:    payload,
-		},
-	})
+	if len(s.Attributes) > 0 {
+		m.Attributes = make(map[string]string, len(s.Attributes))
+		for k, v := range s.Attributes {
+			m.Attributes[k] = v
+		}
+	}
+	if s.Role == l2tppkt.SessionRoleLNS {
+		m.LACHostname = t.PeerHostname
+	}
+	m.Failover, m.PeerRecoveryTime = t.failover, t.peerRecoveryTime
+	return m
 }
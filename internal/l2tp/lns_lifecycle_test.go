@@ -0,0 +1,199 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/ifmgr"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+// bindingRecorder records the session binding calls an LNS session makes,
+// in order.
+type bindingRecorder struct {
+	southbound.Southbound
+	ifMgr *ifmgr.Manager
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *bindingRecorder) record(format string, args ...any) error {
+	r.mu.Lock()
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *bindingRecorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func (r *bindingRecorder) GetIfMgr() *ifmgr.Manager { return r.ifMgr }
+
+func (r *bindingRecorder) EnableSourceVerify(sw uint32, strict bool) error {
+	return r.record("EnableSourceVerify %d %t", sw, strict)
+}
+func (r *bindingRecorder) DisableSourceVerify(sw uint32) error {
+	return r.record("DisableSourceVerify %d", sw)
+}
+func (r *bindingRecorder) ApplyIngressACL(sw uint32, name string) error {
+	return r.record("ApplyIngressACL %d %s", sw, name)
+}
+func (r *bindingRecorder) ApplyEgressACL(sw uint32, name string) error {
+	return r.record("ApplyEgressACL %d %s", sw, name)
+}
+func (r *bindingRecorder) RemoveIngressACL(sw uint32) error {
+	return r.record("RemoveIngressACL %d", sw)
+}
+func (r *bindingRecorder) RemoveEgressACL(sw uint32) error {
+	return r.record("RemoveEgressACL %d", sw)
+}
+func (r *bindingRecorder) ApplyQoS(sw uint32, ingress, egress *qos.Policy) error {
+	return r.record("ApplyQoS %d", sw)
+}
+func (r *bindingRecorder) RemoveQoS(sw uint32) error { return r.record("RemoveQoS %d", sw) }
+func (r *bindingRecorder) ApplyScheduler(sw, rateKbps uint32, _ *qos.SchedulerConfig) error {
+	return r.record("ApplyScheduler %d %d", sw, rateKbps)
+}
+func (r *bindingRecorder) RemoveScheduler(sw uint32) error {
+	return r.record("RemoveScheduler %d", sw)
+}
+func (r *bindingRecorder) RemoveQoSClasses(sw uint32) error {
+	return r.record("RemoveQoSClasses %d", sw)
+}
+func (r *bindingRecorder) RemoveMarking(sw uint32) error { return r.record("RemoveMarking %d", sw) }
+func (r *bindingRecorder) DeleteL2TPSession(_, _ net.IP, tunnelID, sessionID uint16) error {
+	return r.record("DeleteL2TPSession %d %d", tunnelID, sessionID)
+}
+
+func TestApplyServiceGroupBindings(t *testing.T) {
+	cases := []struct {
+		name   string
+		egress string
+		want   []string
+	}{
+		{
+			name:   "scheduler",
+			egress: "shaped",
+			want: []string{
+				"ApplyIngressACL 42 subs-in",
+				"ApplyScheduler 42 50000",
+			},
+		},
+		{
+			name:   "policer",
+			egress: "policed",
+			want: []string{
+				"ApplyIngressACL 42 subs-in",
+				"ApplyQoS 42",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &bindingRecorder{ifMgr: ifmgr.New()}
+			c := New(logger.Get("l2tp"))
+			c.SetSouthbound(rec)
+			c.SetConfigManager(&fakeConfigManager{cfg: &config.Config{
+				QoSPolicies: map[string]*qos.Policy{
+					"shaped":  {CIR: 50000, Scheduler: &qos.SchedulerConfig{TinMode: "diffserv4"}},
+					"policed": {CIR: 50000},
+				},
+			}})
+
+			s := &Session{
+				SessionID: "lns-42",
+				SwIfIndex: 42,
+				ServiceGroup: svcgroup.ServiceGroup{
+					Name:       "sg",
+					ACLIngress: "subs-in",
+					QoSEgress:  tc.egress,
+				},
+			}
+			s.mu.Lock()
+			err := c.applyServiceGroupBindings(s)
+			s.mu.Unlock()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rec.snapshot(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("calls = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTeardownSessionReversesBindings(t *testing.T) {
+	bus := local.NewBus()
+	defer func() { _ = bus.Close() }()
+	lifecycle := collect(bus, events.TopicSessionLifecycle)
+
+	rec := &bindingRecorder{ifMgr: ifmgr.New()}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn((&captureTransport{}).Send)
+	c.SetEventBus(bus)
+	c.SetSouthbound(rec)
+	defer c.Stop(context.Background())
+
+	tun, s := establishedLNSTunnel(t, c)
+	s.mu.Lock()
+	s.SwIfIndex = 42
+	s.ServiceGroup = svcgroup.ServiceGroup{
+		Name:       "sg",
+		URPF:       "strict",
+		ACLIngress: "subs-in",
+		ACLEgress:  "subs-out",
+		QoSEgress:  "shaped",
+	}
+	s.mu.Unlock()
+
+	if err := c.clearSession(s, auth.TerminateCauseAdminReset); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bindings come off before the interface they hang on.
+	want := []string{
+		"RemoveQoSClasses 42",
+		"RemoveScheduler 42",
+		"RemoveQoS 42",
+		"RemoveMarking 42",
+		"RemoveIngressACL 42",
+		"RemoveEgressACL 42",
+		"DisableSourceVerify 42",
+		fmt.Sprintf("DeleteL2TPSession %d %d", tun.LocalID, s.LocalID),
+	}
+	if got := rec.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+	tun.mu.Lock()
+	remaining := tun.Sessions[s.LocalID]
+	tun.mu.Unlock()
+	if remaining != nil {
+		t.Fatal("session still on its tunnel after teardown")
+	}
+	if s.SwIfIndex != 0 {
+		t.Fatalf("sw_if_index = %d after teardown, want 0", s.SwIfIndex)
+	}
+	ev := nextEvent(t, lifecycle).Data.(*events.SessionLifecycleEvent)
+	if ev.SessionID != s.SessionID {
+		t.Fatalf("released session %q, want %q", ev.SessionID, s.SessionID)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"net"
+	"sort"
+
+	l2tpcfg "github.com/veesix-networks/osvbng/pkg/config/l2tp"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+)
+
+// lnsPolicy is the tunnel-pool configuration that applies to one LNS
+// endpoint. An LNS no pool lists gets weight 1 and no caps.
+type lnsPolicy struct {
+	pool        string
+	name        string
+	weight      uint16
+	maxSessions int
+	perTunnel   int
+}
+
+func lnsPolicyFor(cfg *l2tpcfg.L2TPConfig, ip net.IP) lnsPolicy {
+	p := lnsPolicy{weight: 1}
+	poolName, pool, ref := cfg.LookupLNS(ip)
+	if ref == nil {
+		return p
+	}
+	p.pool = poolName
+	p.name = ref.Name
+	p.weight = ref.EffectiveWeight()
+	p.maxSessions = ref.MaxSessions
+	p.perTunnel = pool.MaxSessionsPerTunnel
+	return p
+}
+
+// runningL2TPConfig returns the l2tp block of the running config, or
+// nil when there is none.
+func (c *Component) runningL2TPConfig() *l2tpcfg.L2TPConfig {
+	if c.cfgMgr == nil {
+		return nil
+	}
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.L2TP
+}
+
+// lnsLoad is the LAC's current use of one LNS: sessions carried or
+// queued for bring-up, and the tunnels carrying them.
+type lnsLoad struct {
+	sessions int
+	tunnels  int
+}
+
+// lacLoad sums LAC sessions and tunnels per LNS address. Standby
+// tunnels belong to the HA peer and probe tunnels carry nothing.
+func (c *Component) lacLoad() map[string]lnsLoad {
+	out := make(map[string]lnsLoad)
+	for _, t := range c.lacTunnels() {
+		n := c.tunnelLoad(t)
+		if n < 0 {
+			continue
+		}
+		l := out[t.PeerIP.String()]
+		l.tunnels++
+		l.sessions += n
+		out[t.PeerIP.String()] = l
+	}
+	return out
+}
+
+// lacTunnels returns the LAC tunnels other than health-check probes.
+func (c *Component) lacTunnels() []*Tunnel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []*Tunnel
+	for _, t := range c.tunnels {
+		if t.Role == l2tppkt.RoleInitiator && !t.probe {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// tunnelLoad returns the sessions a LAC tunnel carries plus the
+// requests queued on it, or -1 for a standby or torn-down tunnel.
+func (c *Component) tunnelLoad(t *Tunnel) int {
+	t.mu.Lock()
+	gone := t.standby || t.tornDown
+	n := len(t.Sessions)
+	t.mu.Unlock()
+	if gone {
+		return -1
+	}
+	c.lacMu.Lock()
+	n += len(c.lacPending[makeTunnelKey(t.PeerIP, t.LocalID)])
+	c.lacMu.Unlock()
+	return n
+}
+
+// orderLACCandidates sorts AAA tunnel candidates for selection. Lower
+// Tunnel-Preference still wins; among equal preferences the LNS with
+// the fewest sessions per unit of configured weight goes first, and
+// AAA order breaks remaining ties.
+func (c *Component) orderLACCandidates(specs []TunnelSpec) []TunnelSpec {
+	if len(specs) < 2 {
+		return specs
+	}
+	cfg := c.runningL2TPConfig()
+	load := c.lacLoad()
+
+	type candidate struct {
+		spec     TunnelSpec
+		sessions int
+		weight   int
+	}
+	cands := make([]candidate, len(specs))
+	for i, spec := range specs {
+		cands[i] = candidate{spec: spec, weight: 1}
+		if spec.ServerIP == nil {
+			continue
+		}
+		cands[i].sessions = load[spec.ServerIP.String()].sessions
+		cands[i].weight = int(lnsPolicyFor(cfg, spec.ServerIP).weight)
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if a.spec.Preference != b.spec.Preference {
+			return a.spec.Preference < b.spec.Preference
+		}
+		return a.sessions*b.weight < b.sessions*a.weight
+	})
+
+	out := make([]TunnelSpec, len(cands))
+	for i := range cands {
+		out[i] = cands[i].spec
+	}
+	return out
+}
+
+// lnsUnavailable returns why the LAC should not select an LNS right
+// now, or "" if it may.
+func (c *Component) lnsUnavailable(ip net.IP) string {
+	if c.lnsDown(ip) {
+		return "health-check down"
+	}
+	p := lnsPolicyFor(c.runningL2TPConfig(), ip)
+	if p.maxSessions > 0 && c.lacLoad()[ip.String()].sessions >= p.maxSessions {
+		return "at max-sessions"
+	}
+	return ""
+}
+
+// findLACTunnel returns the least loaded LAC tunnel that can carry
+// another session for `spec`: same endpoints, secret, hostname and
+// framing, established or still waiting for SCCRP, and below the
+// pool's max-sessions-per-tunnel. Nil means open a new tunnel.
+func (c *Component) findLACTunnel(peerIP, localIP net.IP, spec TunnelSpec, hostName string) *Tunnel {
+	perTunnel := lnsPolicyFor(c.runningL2TPConfig(), peerIP).perTunnel
+
+	var best *Tunnel
+	bestLoad := 0
+	for _, t := range c.lacTunnels() {
+		if !t.PeerIP.Equal(peerIP) || !t.LocalIP.Equal(localIP) ||
+			string(t.Secret) != spec.Password || t.LocalHostname != hostName ||
+			t.PPPHdrSkip != spec.PPPHdrSkip {
+			continue
+		}
+		switch t.FSM.State() {
+		case l2tppkt.TunnelWaitCtlReply, l2tppkt.TunnelEstablished:
+		default:
+			continue
+		}
+		n := c.tunnelLoad(t)
+		if n < 0 || (perTunnel > 0 && n >= perTunnel) {
+			continue
+		}
+		if best == nil || n < bestLoad {
+			best, bestLoad = t, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	l2tpcfg "github.com/veesix-networks/osvbng/pkg/config/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/dataplane"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+type fakeConfigManager struct {
+	cfg *config.Config
+}
+
+func (f *fakeConfigManager) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeConfigManager) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeConfigManager) LookupSubscriberGroup(uint16, uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+func lacPoolComponent(pool *l2tpcfg.TunnelPool) *Component {
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(func(_, _ net.IP, _, _ uint16, _ l2tppkt.Header, _ []byte) error { return nil })
+	c.SetLocalHostname("bng1")
+	c.SetConfigManager(&fakeConfigManager{cfg: &config.Config{
+		L2TP: &l2tpcfg.L2TPConfig{TunnelPools: map[string]*l2tpcfg.TunnelPool{"wholesale": pool}},
+	}})
+	return c
+}
+
+// lacTunnelWithSessions registers an Established LAC tunnel carrying n
+// sessions.
+func lacTunnelWithSessions(t *testing.T, c *Component, peer net.IP, localID uint16, n int) {
+	t.Helper()
+	tun := &Tunnel{
+		LocalIP:   net.IPv4(10, 0, 0, 1),
+		PeerIP:    peer,
+		LocalID:   localID,
+		LocalPort: 1701,
+		PeerPort:  1701,
+		Role:      l2tppkt.RoleInitiator,
+		FSM:       l2tppkt.RestoreTunnelFSM(l2tppkt.RoleInitiator, l2tppkt.TunnelEstablished),
+		Sessions:  make(map[uint16]*Session),
+	}
+	if err := c.registerTunnel(tun); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= n; i++ {
+		tun.addSession(&Session{Tunnel: tun, LocalID: uint16(i), Role: l2tppkt.SessionRoleLAC})
+	}
+}
+
+func TestOrderLACCandidatesWeightedLoad(t *testing.T) {
+	lnsA, lnsB, lnsC := net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3), net.IPv4(10, 0, 0, 4)
+	c := lacPoolComponent(&l2tpcfg.TunnelPool{LNS: []l2tpcfg.LNSRef{
+		{Name: "a", IPv4: "10.0.0.2", Weight: 1},
+		{Name: "b", IPv4: "10.0.0.3", Weight: 2},
+		{Name: "c", IPv4: "10.0.0.4", Weight: 1},
+	}})
+	lacTunnelWithSessions(t, c, lnsA, 1, 2)
+	lacTunnelWithSessions(t, c, lnsB, 1, 3)
+
+	got := c.orderLACCandidates([]TunnelSpec{
+		{ServerIP: lnsA, Preference: 10},
+		{ServerIP: lnsB, Preference: 10},
+		{ServerIP: lnsC, Preference: 20},
+	})
+	// B carries 3 sessions at weight 2 (1.5 per unit) and goes ahead
+	// of A (2 per unit); the idle C still loses on preference.
+	want := []net.IP{lnsB, lnsA, lnsC}
+	for i := range want {
+		if !got[i].ServerIP.Equal(want[i]) {
+			t.Fatalf("order[%d] = %s, want %s", i, got[i].ServerIP, want[i])
+		}
+	}
+}
+
+func TestStartLACSessionSharesTunnelUpToCap(t *testing.T) {
+	peer := net.IPv4(10, 0, 0, 2)
+	c := lacPoolComponent(&l2tpcfg.TunnelPool{
+		MaxSessionsPerTunnel: 2,
+		LNS:                  []l2tpcfg.LNSRef{{Name: "a", IPv4: "10.0.0.2", SourceIPv4: "10.0.0.1"}},
+	})
+	defer func() { _ = c.Stop(context.Background()) }()
+
+	for id := uint16(1); id <= 3; id++ {
+		if err := c.StartLACSession(LACBringUpRequest{
+			PPPoESessionID: id,
+			TunnelSpecs:    []TunnelSpec{{ServerIP: peer, Password: "shared", PPPHdrSkip: 2}},
+		}); err != nil {
+			t.Fatalf("StartLACSession %d: %v", id, err)
+		}
+	}
+
+	if c.LookupTunnel(peer, 1) == nil || c.LookupTunnel(peer, 2) == nil {
+		t.Fatal("expected a second tunnel once the first reached max-sessions-per-tunnel")
+	}
+	if c.LookupTunnel(peer, 3) != nil {
+		t.Fatal("unexpected third tunnel")
+	}
+	if n := len(c.clearLACPending(peer, 1)); n != 2 {
+		t.Fatalf("first tunnel queued %d requests, want 2", n)
+	}
+}
+
+func TestStartLACSessionSkipsUnavailableLNS(t *testing.T) {
+	lnsA, lnsB := net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3)
+	c := lacPoolComponent(&l2tpcfg.TunnelPool{LNS: []l2tpcfg.LNSRef{
+		{Name: "a", IPv4: "10.0.0.2"},
+		{Name: "b", IPv4: "10.0.0.3", MaxSessions: 1},
+	}})
+	c.lnsHealth = map[string]*lnsHealth{lnsA.String(): {state: lnsStateDown}}
+	lacTunnelWithSessions(t, c, lnsB, 1, 1)
+
+	err := c.StartLACSession(LACBringUpRequest{
+		PPPoESessionID: 1,
+		LocalIP:        net.IPv4(10, 0, 0, 1),
+		TunnelSpecs:    []TunnelSpec{{ServerIP: lnsA}, {ServerIP: lnsB}},
+	})
+	if err != ErrAllCandidatesUnavailable {
+		t.Fatalf("want ErrAllCandidatesUnavailable, got %v", err)
+	}
+
+	status := c.SnapshotLNS()
+	if len(status) != 2 {
+		t.Fatalf("want 2 LNS rows, got %d", len(status))
+	}
+	for _, st := range status {
+		if st.Available {
+			t.Fatalf("%s reported available: %+v", st.Name, st)
+		}
+	}
+}
+
+func TestRecordProbeThresholds(t *testing.T) {
+	c := New(logger.Get("l2tp"))
+	hc := l2tpcfg.HealthCheck{DownAfter: 2, UpAfter: 2}.WithDefaults()
+	key := "10.0.0.2"
+	c.lnsHealth = map[string]*lnsHealth{key: {state: lnsStateUnknown}}
+	peer := net.ParseIP(key)
+	fail := errors.New("no answer")
+
+	steps := []struct {
+		err  error
+		down bool
+	}{
+		{nil, false},  // unknown → up on first success
+		{fail, false}, // one failure is under down-after
+		{fail, true},
+		{nil, true}, // one success is under up-after
+		{nil, false},
+	}
+	for i, step := range steps {
+		c.recordProbe(key, step.err, hc)
+		if got := c.lnsDown(peer); got != step.down {
+			t.Fatalf("step %d: down = %v, want %v", i, got, step.down)
+		}
+	}
+	if h := c.lnsHealth[key]; h.probes != 5 || h.probeFailures != 2 {
+		t.Fatalf("counters = %d/%d, want 5/2", h.probes, h.probeFailures)
+	}
+}
+
+func TestProbeSCCRQPassesOnSCCRP(t *testing.T) {
+	cap := &captureTransport{}
+	c := New(logger.Get("l2tp"))
+	c.SetSendControlFn(cap.Send)
+	c.SetLocalHostname("bng1")
+
+	peer := net.IPv4(10, 0, 0, 2)
+	ref := &l2tpcfg.LNSRef{Name: "a", IPv4: "10.0.0.2", SourceIPv4: "10.0.0.1", Secret: "shared"}
+	tnl, err := c.openProbeTunnel(peer, ref, "")
+	if err != nil {
+		t.Fatalf("openProbeTunnel: %v", err)
+	}
+
+	body := buildSCCRPBody(99, tnl.Secret, tnl.outstandingChallenge)
+	hdr := l2tppkt.NewControl(tnl.LocalID, 0, 0, 1)
+	wire := hdr.AppendTo(make([]byte, 0, 12+len(body)), len(body))
+	wire = append(wire, body...)
+	pkt := &dataplane.ParsedPacket{
+		Protocol: models.ProtocolL2TP,
+		IPv4:     &layers.IPv4{SrcIP: peer.To4(), DstIP: net.IPv4(10, 0, 0, 1).To4()},
+		UDP:      &layers.UDP{SrcPort: 1701, DstPort: 1701},
+	}
+	pkt.UDP.Payload = wire
+	if err := c.Dispatch(pkt); err != nil {
+		t.Fatalf("Dispatch sccrp: %v", err)
+	}
+
+	select {
+	case err := <-tnl.probeResult:
+		if err != nil {
+			t.Fatalf("probe failed: %v", err)
+		}
+	default:
+		t.Fatal("probe has no result after SCCRP")
+	}
+
+	pkts := cap.snapshot()
+	last, err := l2tppkt.ParseAVPs(pkts[len(pkts)-1].body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mt := l2tppkt.DecodeMessageType(last); mt != l2tppkt.MsgTypeStopCCN {
+		t.Fatalf("probe closed with message type %d, want StopCCN", mt)
+	}
+
+	c.teardownTunnel(tnl, "")
+	if c.LookupTunnel(peer, tnl.LocalID) != nil {
+		t.Fatal("probe tunnel still registered")
+	}
+}
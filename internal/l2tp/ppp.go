@@ -37,6 +37,41 @@ var (
 // once the LNS knows the session is up and PPP termination should
 // begin.
 func (c *Component) initSessionPPP(s *Session) {
+	c.newSessionPPP(s)
+
+	s.mu.Lock()
+	s.Phase = ppp.PhaseEstablish
+	s.LCP.FSM().Up()
+	s.LCP.FSM().Open()
+	s.mu.Unlock()
+}
+
+// restoreSessionPPP rebuilds the PPP stack of a checkpointed LNS
+// session directly in Opened: no Configure exchange is started, so
+// the subscriber's view of the link (state and magic) is preserved.
+// Only NCPs that carried an address are restored.
+func (c *Component) restoreSessionPPP(s *Session) {
+	c.newSessionPPP(s)
+	if s.LCPMagic != 0 {
+		s.LCP.SetMagic(s.LCPMagic)
+	}
+	s.LCP.FSM().Restore()
+	if s.IPv4Address != nil {
+		s.IPCP.SetPeerAddress(s.IPv4Address)
+		s.IPCP.FSM().Restore()
+		s.ipcpOpen = true
+	}
+	if s.IPv6Address != nil {
+		s.IPv6CP.FSM().Restore()
+		s.ipv6cpOpen = true
+	}
+	s.Phase = ppp.PhaseOpen
+	s.lifecyclePublished = true
+}
+
+// newSessionPPP allocates the session's LCP / NCP / auth handlers and
+// the dispatcher without driving any FSM.
+func (c *Component) newSessionPPP(s *Session) {
 	sendCb := func(proto uint16) func(code, id uint8, data []byte) {
 		return func(code, id uint8, data []byte) {
 			pkt := buildPPPPacket(code, id, data)
@@ -99,12 +134,6 @@ func (c *Component) initSessionPPP(s *Session) {
 			}
 		},
 	}
-
-	s.mu.Lock()
-	s.Phase = ppp.PhaseEstablish
-	s.LCP.FSM().Up()
-	s.LCP.FSM().Open()
-	s.mu.Unlock()
 }
 
 // dispatchPPPFrame routes a single inbound PPP frame to the session's
@@ -0,0 +1,575 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/events"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+const (
+	opdbTunnelNamespace  = "l2tp:tunnels"
+	opdbSessionNamespace = "l2tp:sessions"
+)
+
+const (
+	roleLAC = "lac"
+	roleLNS = "lns"
+)
+
+// tunnelRecord is the opdb checkpoint of one Established control
+// connection. Ns / Nr are a snapshot; a restored channel resumes from
+// the sequence the peer suggests during recovery. Failover records
+// whether both ends agreed to RFC 4951 failover, without which the
+// tunnel cannot be recovered.
+type tunnelRecord struct {
+	LocalIP       net.IP        `json:"local_ip"`
+	PeerIP        net.IP        `json:"peer_ip"`
+	LocalID       uint16        `json:"local_id"`
+	PeerID        uint16        `json:"peer_id"`
+	LocalPort     uint16        `json:"local_port"`
+	PeerPort      uint16        `json:"peer_port"`
+	Role          string        `json:"role"`
+	LocalHostname string        `json:"local_hostname,omitempty"`
+	PeerHostname  string        `json:"peer_hostname,omitempty"`
+	Ns            uint16        `json:"ns"`
+	Nr            uint16        `json:"nr"`
+	HelloInterval time.Duration `json:"hello_interval,omitempty"`
+	PPPHdrSkip    uint8         `json:"ppp_hdr_skip"`
+	SRGName       string        `json:"srg_name,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+
+	Failover         bool          `json:"failover,omitempty"`
+	PeerRecoveryTime time.Duration `json:"peer_recovery_time,omitempty"`
+}
+
+// sessionRecord is the opdb checkpoint of one bound session. LNS
+// records carry the negotiated PPP state so the session resumes in
+// Opened without renegotiating with the subscriber; LAC records carry
+// the partner PPPoE session.
+type sessionRecord struct {
+	SessionID     string `json:"session_id"`
+	AcctSessionID string `json:"acct_session_id,omitempty"`
+	PeerIP        net.IP `json:"peer_ip"`
+	TunnelID      uint16 `json:"tunnel_id"`
+	LocalID       uint16 `json:"local_id"`
+	PeerID        uint16 `json:"peer_id"`
+	Role          string `json:"role"`
+
+	Username     string            `json:"username,omitempty"`
+	VRF          string            `json:"vrf,omitempty"`
+	ServiceGroup string            `json:"service_group,omitempty"`
+	SRGName      string            `json:"srg_name,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+
+	IPv4Address net.IP `json:"ipv4_address,omitempty"`
+	IPv6Address net.IP `json:"ipv6_address,omitempty"`
+	IPv6Prefix  string `json:"ipv6_prefix,omitempty"`
+	IPv4Pool    string `json:"ipv4_pool,omitempty"`
+	IANAPool    string `json:"iana_pool,omitempty"`
+	PDPool      string `json:"pd_pool,omitempty"`
+	LCPMagic    uint32 `json:"lcp_magic,omitempty"`
+
+	SwIfIndex      uint32 `json:"sw_if_index,omitempty"`
+	EncapIfIndex   uint32 `json:"encap_if_index,omitempty"`
+	PPPoESessionID uint16 `json:"pppoe_session_id,omitempty"`
+	PPPoESwIfIndex uint32 `json:"pppoe_sw_if_index,omitempty"`
+
+	ActivatedAt time.Time `json:"activated_at"`
+}
+
+func tunnelRecordKey(peerIP net.IP, localID uint16) string {
+	return fmt.Sprintf("%s/%d", peerIP, localID)
+}
+
+func tunnelRoleName(r l2tppkt.TunnelRole) string {
+	if r == l2tppkt.RoleInitiator {
+		return roleLAC
+	}
+	return roleLNS
+}
+
+func sessionRoleName(r l2tppkt.SessionRole) string {
+	if r == l2tppkt.SessionRoleLAC {
+		return roleLAC
+	}
+	return roleLNS
+}
+
+func (t *Tunnel) record() tunnelRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ns, nr := t.sequence()
+	return tunnelRecord{
+		LocalIP:       t.LocalIP,
+		PeerIP:        t.PeerIP,
+		LocalID:       t.LocalID,
+		PeerID:        t.PeerID,
+		LocalPort:     t.LocalPort,
+		PeerPort:      t.PeerPort,
+		Role:          tunnelRoleName(t.Role),
+		LocalHostname: t.LocalHostname,
+		PeerHostname:  t.PeerHostname,
+		Ns:            ns,
+		Nr:            nr,
+		HelloInterval: t.HelloInterval,
+		PPPHdrSkip:    t.PPPHdrSkip,
+		SRGName:       t.SRGName,
+		CreatedAt:     t.CreatedAt,
+
+		Failover:         t.failover,
+		PeerRecoveryTime: t.peerRecoveryTime,
+	}
+}
+
+// record snapshots the session. Called with s.mu held.
+func (s *Session) record() sessionRecord {
+	rec := sessionRecord{
+		SessionID:      s.SessionID,
+		AcctSessionID:  s.AcctSessionID,
+		PeerIP:         s.Tunnel.PeerIP,
+		TunnelID:       s.Tunnel.LocalID,
+		LocalID:        s.LocalID,
+		PeerID:         s.PeerID,
+		Role:           sessionRoleName(s.Role),
+		Username:       s.Username,
+		VRF:            s.VRF,
+		ServiceGroup:   s.ServiceGroup.Name,
+		SRGName:        s.SRGName,
+		Attributes:     s.Attributes,
+		IPv4Address:    s.IPv4Address,
+		IPv6Address:    s.IPv6Address,
+		IPv4Pool:       s.allocatedPool,
+		IANAPool:       s.allocatedIANAPool,
+		PDPool:         s.allocatedPDPool,
+		LCPMagic:       s.LCPMagic,
+		SwIfIndex:      s.SwIfIndex,
+		EncapIfIndex:   s.EncapIfIndex,
+		PPPoESessionID: s.PPPoESessionID,
+		PPPoESwIfIndex: s.PPPoESwIfIndex,
+		ActivatedAt:    s.ActivatedAt,
+	}
+	if s.IPv6Prefix != nil {
+		rec.IPv6Prefix = s.IPv6Prefix.String()
+	}
+	return rec
+}
+
+// checkpointTunnel persists a tunnel once it is Established. Standby
+// tunnels live in the HA synced namespace and are not checkpointed.
+func (c *Component) checkpointTunnel(t *Tunnel) {
+	if c.opdb == nil {
+		return
+	}
+	if t.isStandby() {
+		return
+	}
+	rec := t.record()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		c.log.Error("Failed to marshal l2tp tunnel", "error", err)
+		return
+	}
+	key := tunnelRecordKey(rec.PeerIP, rec.LocalID)
+	if err := c.opdb.Put(context.Background(), opdbTunnelNamespace, key, data); err != nil {
+		c.log.Warn("Failed to checkpoint l2tp tunnel", "key", key, "error", err)
+	}
+}
+
+// checkpointSession persists a bound session and refreshes its
+// tunnel's record, which picks up the current Ns / Nr. Called with
+// s.mu held.
+func (c *Component) checkpointSession(s *Session) {
+	if c.opdb == nil || s.Tunnel == nil {
+		return
+	}
+	t := s.Tunnel
+	t.mu.Lock()
+	if t.SRGName == "" {
+		t.SRGName = s.SRGName
+	}
+	t.mu.Unlock()
+	c.checkpointTunnel(t)
+	if t.isStandby() {
+		return
+	}
+	data, err := json.Marshal(s.record())
+	if err != nil {
+		c.log.Error("Failed to marshal l2tp session", "error", err)
+		return
+	}
+	if err := c.opdb.Put(context.Background(), opdbSessionNamespace, s.SessionID, data); err != nil {
+		c.log.Warn("Failed to checkpoint l2tp session", "session_id", s.SessionID, "error", err)
+	}
+}
+
+// forgetSession drops a torn-down session's checkpoint and tells the
+// standby to remove its copy.
+func (c *Component) forgetSession(s *Session) {
+	if c.opdb != nil {
+		if err := c.opdb.Delete(context.Background(), opdbSessionNamespace, s.SessionID); err != nil {
+			c.log.Warn("Failed to delete l2tp session checkpoint", "session_id", s.SessionID, "error", err)
+		}
+	}
+	s.mu.Lock()
+	// LNS sessions that went Active already published Released on the
+	// lifecycle topic, which HA replicates.
+	if s.Role != l2tppkt.SessionRoleLNS || !s.lifecyclePublished {
+		c.publishSessionSync(s, models.SessionStateReleased)
+	}
+	s.mu.Unlock()
+}
+
+// forgetTunnel drops a torn-down tunnel's checkpoint along with those
+// of every session it still carried.
+func (c *Component) forgetTunnel(t *Tunnel) {
+	for _, s := range t.snapshotSessions() {
+		c.forgetSession(s)
+	}
+	if c.opdb == nil {
+		return
+	}
+	key := tunnelRecordKey(t.PeerIP, t.LocalID)
+	if err := c.opdb.Delete(context.Background(), opdbTunnelNamespace, key); err != nil {
+		c.log.Warn("Failed to delete l2tp tunnel checkpoint", "key", key, "error", err)
+	}
+}
+
+// restoreState replays checkpointed tunnels and sessions on startup.
+// Tunnels this node owns are resumed: re-installed in the dataplane and
+// their control channel recovered with the peer (see resumeTunnel).
+// Tunnels whose SRG is standby here are kept as control state only;
+// promotion resumes them.
+// Sessions never re-authenticate and publish the Restored topic, not
+// Lifecycle, so AAA does not emit a duplicate Accounting-Start.
+func (c *Component) restoreState(ctx context.Context) error {
+	if c.opdb == nil {
+		return nil
+	}
+
+	var tunnels []*Tunnel
+	err := c.opdb.Load(ctx, opdbTunnelNamespace, func(key string, value []byte) error {
+		var rec tunnelRecord
+		if err := json.Unmarshal(value, &rec); err != nil || rec.PeerIP == nil {
+			c.log.Warn("Corrupt l2tp tunnel checkpoint; dropping", "key", key, "error", err)
+			_ = c.opdb.Delete(ctx, opdbTunnelNamespace, key)
+			return nil
+		}
+		standby := c.srgMgr != nil && rec.SRGName != "" && !c.srgMgr.IsActive(rec.SRGName)
+		tunnels = append(tunnels, c.adoptTunnel(rec, standby))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var restored, orphaned int
+	err = c.opdb.Load(ctx, opdbSessionNamespace, func(key string, value []byte) error {
+		var rec sessionRecord
+		if err := json.Unmarshal(value, &rec); err != nil || rec.PeerIP == nil {
+			c.log.Warn("Corrupt l2tp session checkpoint; dropping", "key", key, "error", err)
+			_ = c.opdb.Delete(ctx, opdbSessionNamespace, key)
+			return nil
+		}
+		t := c.LookupTunnel(rec.PeerIP, rec.TunnelID)
+		if t == nil {
+			orphaned++
+			_ = c.opdb.Delete(ctx, opdbSessionNamespace, key)
+			return nil
+		}
+		s := c.adoptSession(t, rec)
+		c.reserveSessionAddresses(s)
+		restored++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tunnels {
+		if t.isStandby() {
+			continue
+		}
+		if err := c.resumeTunnel(t); err != nil && !errors.Is(err, ErrNoFailoverAgreed) {
+			c.log.Error("Failed to resume restored l2tp tunnel",
+				"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID, "error", err)
+		}
+	}
+
+	if len(tunnels) > 0 || restored > 0 || orphaned > 0 {
+		c.log.Info("l2tp restore complete",
+			"tunnels", len(tunnels), "sessions", restored, "orphaned", orphaned)
+	}
+	return nil
+}
+
+// RecoverSessions re-programs every active tunnel and session into a
+// dataplane that lost its state (VPP restart). Control state is kept;
+// only the plugin entries are rebuilt.
+func (c *Component) RecoverSessions(ctx context.Context) error {
+	c.mu.RLock()
+	tunnels := make([]*Tunnel, 0, len(c.tunnels))
+	for _, t := range c.tunnels {
+		tunnels = append(tunnels, t)
+	}
+	c.mu.RUnlock()
+
+	var recovered int
+	for _, t := range tunnels {
+		t.mu.Lock()
+		skip := t.standby || t.FSM == nil || t.FSM.State() != l2tppkt.TunnelEstablished
+		t.installedInVPP = false
+		t.mu.Unlock()
+		if skip {
+			continue
+		}
+		if err := c.installTunnelVPP(t); err != nil {
+			c.log.Error("Failed to recover l2tp tunnel",
+				"peer_ip", t.PeerIP.String(), "local_tunnel_id", t.LocalID, "error", err)
+			continue
+		}
+		for _, s := range t.snapshotSessions() {
+			c.resumeSessionVPP(s, false)
+		}
+		recovered++
+	}
+	c.log.Info("l2tp dataplane recovery complete", "tunnels", recovered)
+	return nil
+}
+
+// adoptTunnel registers a tunnel rebuilt from a record, or returns the
+// one already registered under the same key. Control state only; the
+// dataplane and the runner are brought up by resumeTunnel.
+func (c *Component) adoptTunnel(rec tunnelRecord, standby bool) *Tunnel {
+	if t := c.LookupTunnel(rec.PeerIP, rec.LocalID); t != nil {
+		return t
+	}
+	role := l2tppkt.RoleResponder
+	if rec.Role == roleLAC {
+		role = l2tppkt.RoleInitiator
+	}
+	t := &Tunnel{
+		LocalIP:       rec.LocalIP,
+		PeerIP:        rec.PeerIP,
+		LocalID:       rec.LocalID,
+		PeerID:        rec.PeerID,
+		LocalPort:     rec.LocalPort,
+		PeerPort:      rec.PeerPort,
+		Role:          role,
+		FSM:           l2tppkt.RestoreTunnelFSM(role, l2tppkt.TunnelEstablished),
+		LocalHostname: rec.LocalHostname,
+		PeerHostname:  rec.PeerHostname,
+		Sessions:      make(map[uint16]*Session),
+		HelloInterval: rec.HelloInterval,
+		PPPHdrSkip:    rec.PPPHdrSkip,
+		CreatedAt:     rec.CreatedAt,
+		SRGName:       rec.SRGName,
+		standby:       standby,
+		resumeNs:      rec.Ns,
+		resumeNr:      rec.Nr,
+
+		failover:         rec.Failover,
+		peerRecoveryTime: rec.PeerRecoveryTime,
+	}
+	c.reserveTunnelID(rec.PeerIP, rec.LocalID)
+	if err := c.registerTunnel(t); err != nil {
+		if existing := c.LookupTunnel(rec.PeerIP, rec.LocalID); existing != nil {
+			return existing
+		}
+	}
+	return t
+}
+
+// adoptSession attaches a session rebuilt from a record to its tunnel.
+// LNS sessions get their PPP stack restored in Opened.
+func (c *Component) adoptSession(t *Tunnel, rec sessionRecord) *Session {
+	t.mu.Lock()
+	existing := t.Sessions[rec.LocalID]
+	t.mu.Unlock()
+	if existing != nil && existing.SessionID == rec.SessionID {
+		return existing
+	}
+	if existing != nil {
+		t.removeSession(rec.LocalID)
+	}
+
+	role := l2tppkt.SessionRoleLNS
+	if rec.Role == roleLAC {
+		role = l2tppkt.SessionRoleLAC
+	}
+	s := &Session{
+		SessionID:         rec.SessionID,
+		AcctSessionID:     rec.AcctSessionID,
+		Tunnel:            t,
+		LocalID:           rec.LocalID,
+		PeerID:            rec.PeerID,
+		Role:              role,
+		FSM:               l2tppkt.RestoreSessionFSM(role, l2tppkt.SessionEstablished),
+		Attributes:        rec.Attributes,
+		VRF:               rec.VRF,
+		ServiceGroup:      c.restoredServiceGroup(rec.ServiceGroup, rec.VRF, rec.Attributes),
+		SRGName:           rec.SRGName,
+		Username:          rec.Username,
+		IPv4Address:       rec.IPv4Address,
+		IPv6Address:       rec.IPv6Address,
+		allocatedPool:     rec.IPv4Pool,
+		allocatedIANAPool: rec.IANAPool,
+		allocatedPDPool:   rec.PDPool,
+		LCPMagic:          rec.LCPMagic,
+		SwIfIndex:         rec.SwIfIndex,
+		EncapIfIndex:      rec.EncapIfIndex,
+		PPPoESessionID:    rec.PPPoESessionID,
+		PPPoESwIfIndex:    rec.PPPoESwIfIndex,
+		ActivatedAt:       rec.ActivatedAt,
+		BoundAt:           rec.ActivatedAt,
+	}
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	if rec.IPv6Prefix != "" {
+		if _, pfx, err := net.ParseCIDR(rec.IPv6Prefix); err == nil {
+			s.IPv6Prefix = pfx
+		}
+	}
+	if role == l2tppkt.SessionRoleLNS {
+		c.restoreSessionPPP(s)
+	}
+	t.addSession(s)
+
+	t.mu.Lock()
+	if t.SRGName == "" {
+		t.SRGName = rec.SRGName
+	}
+	t.mu.Unlock()
+	return s
+}
+
+// restoredServiceGroup re-resolves a session's service group by name
+// so restored sessions pick up the running config's unnumbered
+// loopback and pools. The stored AAA attributes are merged back in so
+// per-subscriber overrides such as rates survive the restart.
+func (c *Component) restoredServiceGroup(name, vrf string, attrs map[string]string) svcgroup.ServiceGroup {
+	if c.svcGroupResolver != nil && name != "" {
+		var aaaAttrs map[string]interface{}
+		if len(attrs) > 0 {
+			aaaAttrs = make(map[string]interface{}, len(attrs))
+			for k, v := range attrs {
+				aaaAttrs[k] = v
+			}
+		}
+		sg := c.svcGroupResolver.Resolve(name, "", aaaAttrs)
+		if sg.VRF == "" {
+			sg.VRF = vrf
+		}
+		return sg
+	}
+	return svcgroup.ServiceGroup{Name: name, VRF: vrf}
+}
+
+func (c *Component) reserveTunnelID(peerIP net.IP, id uint16) {
+	key := peerIP.String()
+	c.mu.Lock()
+	alloc, ok := c.tunnelIDs[key]
+	if !ok {
+		alloc = NewIDAllocator()
+		c.tunnelIDs[key] = alloc
+	}
+	c.mu.Unlock()
+	alloc.Reserve(id)
+}
+
+// reserveSessionAddresses locks a restored LNS session's addresses in
+// the allocator so they are not handed to a new subscriber.
+func (c *Component) reserveSessionAddresses(s *Session) {
+	if c.registry == nil || s.Role != l2tppkt.SessionRoleLNS {
+		return
+	}
+	if s.IPv4Address != nil {
+		if err := c.registry.ReserveIP(s.IPv4Address, s.SessionID); err != nil {
+			c.log.Warn("IPv4 reservation conflict on restore",
+				"session_id", s.SessionID, "address", s.IPv4Address, "error", err)
+		}
+	}
+	if s.IPv6Address != nil {
+		if err := c.registry.ReserveIANA(s.IPv6Address, s.SessionID); err != nil {
+			c.log.Warn("IPv6 IANA reservation conflict on restore",
+				"session_id", s.SessionID, "address", s.IPv6Address, "error", err)
+		}
+	}
+	if s.IPv6Prefix != nil {
+		if err := c.registry.ReservePD(s.IPv6Prefix, s.SessionID); err != nil {
+			c.log.Warn("PD reservation conflict on restore",
+				"session_id", s.SessionID, "prefix", s.IPv6Prefix, "error", err)
+		}
+	}
+}
+
+// resumeSessionVPP re-installs a restored session in the dataplane.
+// LNS sessions are re-created and their subscriber bindings replayed;
+// LAC sessions are re-bound to their PPPoE session, which the PPPoE
+// restore path refreshes through ResolveLACSessionIndex once it knows
+// the PPPoE interface's current index. With `announce` set the session
+// is handed to AAA through the Restored topic.
+func (c *Component) resumeSessionVPP(s *Session, announce bool) {
+	switch s.Role {
+	case l2tppkt.SessionRoleLNS:
+		s.mu.Lock()
+		s.programmedInVPP = false
+		s.mu.Unlock()
+		if err := c.installLNSSessionVPP(s); err != nil {
+			return
+		}
+		s.mu.Lock()
+		c.programSessionVPP(s)
+		c.checkpointSession(s)
+		if announce {
+			c.publishSessionRestored(s)
+		}
+		s.mu.Unlock()
+	case l2tppkt.SessionRoleLAC:
+		s.mu.Lock()
+		pppoeIdx := s.PPPoESwIfIndex
+		s.SwIfIndex = 0
+		s.mu.Unlock()
+		if pppoeIdx == 0 {
+			return
+		}
+		if err := c.installLACSessionVPP(s, pppoeIdx); err != nil {
+			c.log.Warn("Failed to re-install LAC session",
+				"session_id", s.SessionID, "error", err)
+			return
+		}
+		s.mu.Lock()
+		c.checkpointSession(s)
+		s.mu.Unlock()
+	}
+}
+
+// publishSessionRestored hands a restored LNS session to AAA and the
+// subscriber view without a new Accounting-Start. Called with s.mu
+// held.
+func (c *Component) publishSessionRestored(s *Session) {
+	if c.eventBus == nil {
+		return
+	}
+	c.eventBus.Publish(events.TopicSessionRestored, events.Event{
+		Source: c.Name(),
+		Data: &events.SessionRestoredEvent{
+			AccessType: models.AccessTypeL2TP,
+			Protocol:   models.ProtocolL2TP,
+			SessionID:  s.SessionID,
+			Session:    c.sessionModel(s, models.SessionStateActive),
+		},
+	})
+}
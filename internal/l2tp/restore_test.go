@@ -0,0 +1,324 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/ppp"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+type fakeOpDB struct {
+	mu sync.Mutex
+	ns map[string]map[string][]byte
+}
+
+func newFakeOpDB() *fakeOpDB { return &fakeOpDB{ns: map[string]map[string][]byte{}} }
+
+func (f *fakeOpDB) Put(ctx context.Context, namespace, key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ns[namespace] == nil {
+		f.ns[namespace] = map[string][]byte{}
+	}
+	f.ns[namespace][key] = append([]byte(nil), value...)
+	return nil
+}
+func (f *fakeOpDB) Delete(ctx context.Context, namespace, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ns[namespace] != nil {
+		delete(f.ns[namespace], key)
+	}
+	return nil
+}
+func (f *fakeOpDB) Load(ctx context.Context, namespace string, fn opdb.LoadFunc) error {
+	f.mu.Lock()
+	keys := make([]string, 0, len(f.ns[namespace]))
+	vals := make([][]byte, 0, len(f.ns[namespace]))
+	for k, v := range f.ns[namespace] {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	f.mu.Unlock()
+	for i, k := range keys {
+		if err := fn(k, vals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (f *fakeOpDB) Count(ctx context.Context, namespace string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.ns[namespace]), nil
+}
+func (f *fakeOpDB) Clear(ctx context.Context, namespace string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.ns, namespace)
+	return nil
+}
+func (f *fakeOpDB) Stats() opdb.Stats { return opdb.Stats{} }
+func (f *fakeOpDB) Close() error      { return nil }
+
+type fakeSRGProvider struct {
+	mu     sync.Mutex
+	active bool
+}
+
+func (f *fakeSRGProvider) GetVirtualMAC(string) net.HardwareAddr { return nil }
+func (f *fakeSRGProvider) IsActive(string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+func (f *fakeSRGProvider) GetSRGForGroup(string) string { return "" }
+func (f *fakeSRGProvider) RequestGARP(string)           {}
+
+// findMessage returns the first sent packet of the given message type.
+func findMessage(t *testing.T, pkts []capturedPacket, msgType uint16) (capturedPacket, []l2tppkt.AVP) {
+	t.Helper()
+	for _, p := range pkts {
+		avps, err := l2tppkt.ParseAVPs(p.body)
+		if err != nil {
+			continue
+		}
+		if l2tppkt.DecodeMessageType(avps) == msgType {
+			return p, avps
+		}
+	}
+	t.Fatalf("no %s among %d sent packets", l2tppkt.MessageTypeName(msgType), len(pkts))
+	return capturedPacket{}, nil
+}
+
+func TestRestoreStateResumesLNSTunnel(t *testing.T) {
+	db := newFakeOpDB()
+	local := net.IPv4(10, 0, 0, 1)
+	peer := net.IPv4(10, 0, 0, 2)
+
+	c1 := New(logger.Get("l2tp"))
+	c1.SetOpDB(db)
+	c1.SetFailover(30 * time.Second)
+	tun := &Tunnel{
+		LocalIP:       local,
+		PeerIP:        peer,
+		LocalID:       3,
+		PeerID:        40,
+		LocalPort:     1701,
+		PeerPort:      1701,
+		Role:          l2tppkt.RoleResponder,
+		FSM:           l2tppkt.RestoreTunnelFSM(l2tppkt.RoleResponder, l2tppkt.TunnelEstablished),
+		PeerHostname:  "lac1",
+		HelloInterval: time.Minute,
+		PPPHdrSkip:    2,
+		resumeNs:      4,
+		resumeNr:      6,
+
+		failover:         true,
+		peerRecoveryTime: 20 * time.Second,
+	}
+	if err := c1.registerTunnel(tun); err != nil {
+		t.Fatal(err)
+	}
+	s := &Session{
+		SessionID:   makeSessionID(peer, 3, 7),
+		Tunnel:      tun,
+		LocalID:     7,
+		PeerID:      70,
+		Role:        l2tppkt.SessionRoleLNS,
+		Username:    "alice",
+		IPv4Address: net.IPv4(100, 64, 0, 9),
+		LCPMagic:    0xdeadbeef,
+		ActivatedAt: time.Unix(1700000000, 0),
+	}
+	tun.addSession(s)
+	s.mu.Lock()
+	c1.checkpointSession(s)
+	s.mu.Unlock()
+
+	tr := &captureTransport{}
+	c2 := New(logger.Get("l2tp"))
+	c2.SetOpDB(db)
+	c2.SetSendControlFn(tr.Send)
+	c2.SetFailover(30 * time.Second)
+	if err := c2.restoreState(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Stop(context.Background())
+
+	got := c2.LookupTunnel(peer, 3)
+	if got == nil {
+		t.Fatal("tunnel not restored")
+	}
+	if got.isStandby() || got.PeerID != 40 || got.FSM.State() != l2tppkt.TunnelEstablished {
+		t.Fatalf("restored tunnel: standby=%v peer_id=%d state=%v", got.isStandby(), got.PeerID, got.FSM.State())
+	}
+	rs := c2.LookupSession(peer, 3, 7)
+	if rs == nil {
+		t.Fatal("session not restored")
+	}
+	if rs.LCP == nil || rs.LCP.FSM().State() != ppp.Opened {
+		t.Fatal("LNS session should resume with LCP Opened")
+	}
+	if rs.IPCP.FSM().State() != ppp.Opened || !rs.IPv4Address.Equal(net.IPv4(100, 64, 0, 9)) {
+		t.Fatal("IPCP should resume Opened with the checkpointed address")
+	}
+
+	if !got.failover || got.peerRecoveryTime != 20*time.Second {
+		t.Fatalf("failover agreement not restored: %v %v", got.failover, got.peerRecoveryTime)
+	}
+	if !got.isRecovering() || got.Channel != nil {
+		t.Fatal("restored tunnel should wait for recovery before resuming its channel")
+	}
+	_, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeSCCRQ)
+	rec, ok := l2tppkt.DecodeTunnelRecovery(avps)
+	if !ok || rec.TunnelID != 3 || rec.RemoteTunnelID != 40 {
+		t.Fatalf("recovery SCCRQ should name tunnel 3/40, got %+v ok=%v", rec, ok)
+	}
+	if fc, ok := l2tppkt.DecodeFailoverCapability(avps); !ok || !fc.ControlChannel {
+		t.Fatal("recovery SCCRQ should advertise failover")
+	}
+
+	// A second ID allocation must not collide with the restored one.
+	id, err := c2.allocateTunnelID(peer)
+	if err != nil || id == 3 {
+		t.Fatalf("allocateTunnelID after restore: id=%d err=%v", id, err)
+	}
+}
+
+func TestApplySyncedSessionStandbyThenPromote(t *testing.T) {
+	db := newFakeOpDB()
+	srg := &fakeSRGProvider{}
+	tr := &captureTransport{}
+
+	c := New(logger.Get("l2tp"))
+	c.SetOpDB(db)
+	c.SetSRGProvider(srg)
+	c.SetSendControlFn(tr.Send)
+	c.SetFailover(30 * time.Second)
+	defer c.Stop(context.Background())
+
+	peer := net.IPv4(10, 0, 0, 2)
+	cp := &hapb.SessionCheckpoint{
+		SessionId:          makeSessionID(peer, 5, 9),
+		SrgName:            "srg1",
+		AccessType:         "l2tp",
+		Username:           "bob",
+		Ipv4Address:        net.IPv4(100, 64, 0, 10).To4(),
+		L2TpLocalIp:        net.IPv4(10, 0, 0, 1).To4(),
+		L2TpPeerIp:         peer.To4(),
+		L2TpLocalTunnelId:  5,
+		L2TpPeerTunnelId:   50,
+		L2TpLocalSessionId: 9,
+		L2TpPeerSessionId:  90,
+		L2TpLocalPort:      1701,
+		L2TpPeerPort:       1701,
+		L2TpRole:           roleLNS,
+		L2TpNs:             10,
+		L2TpNr:             20,
+		L2TpHelloInterval:  60,
+		L2TpPppHdrSkip:     2,
+		L2TpFailover:       true,
+		BoundAtNs:          time.Unix(1700000000, 0).UnixNano(),
+	}
+	c.ApplySyncedSession(hapb.SyncAction_SYNC_ACTION_UPDATE, cp)
+
+	tun := c.LookupTunnel(peer, 5)
+	if tun == nil || !tun.isStandby() {
+		t.Fatal("synced tunnel should be held as standby")
+	}
+	if len(tr.snapshot()) != 0 {
+		t.Fatal("standby tunnel must not send control traffic")
+	}
+	var n int
+	c.ForEachSession(func(models.SubscriberSession) bool { n++; return true })
+	if n != 0 {
+		t.Fatalf("standby sessions must not be bulk-synced back, got %d", n)
+	}
+	if cnt, _ := db.Count(context.Background(), opdbSessionNamespace); cnt != 0 {
+		t.Fatalf("standby sessions must not be checkpointed locally, got %d", cnt)
+	}
+
+	srg.mu.Lock()
+	srg.active = true
+	srg.mu.Unlock()
+	c.handleHAStateChange(events.Event{Data: events.HAStateChangeEvent{
+		SRGName:  "srg1",
+		OldState: "STANDBY",
+		NewState: "ACTIVE",
+	}})
+
+	if tun.isStandby() {
+		t.Fatal("tunnel should be active after promotion")
+	}
+	_, avps := findMessage(t, tr.snapshot(), l2tppkt.MsgTypeSCCRQ)
+	if rec, ok := l2tppkt.DecodeTunnelRecovery(avps); !ok || rec.TunnelID != 5 || rec.RemoteTunnelID != 50 {
+		t.Fatalf("promotion should open a recovery tunnel for 5/50, got %+v ok=%v", rec, ok)
+	}
+	if cnt, _ := db.Count(context.Background(), opdbSessionNamespace); cnt != 1 {
+		t.Fatalf("promoted session should be checkpointed, got %d", cnt)
+	}
+	n = 0
+	c.ForEachSession(func(models.SubscriberSession) bool { n++; return true })
+	if n != 1 {
+		t.Fatalf("promoted session should be bulk-syncable, got %d", n)
+	}
+}
+
+func TestApplySyncedSessionDeleteDropsEmptyTunnel(t *testing.T) {
+	c := New(logger.Get("l2tp"))
+	c.SetSRGProvider(&fakeSRGProvider{})
+
+	peer := net.IPv4(10, 0, 0, 2)
+	cp := &hapb.SessionCheckpoint{
+		SessionId:          makeSessionID(peer, 5, 9),
+		SrgName:            "srg1",
+		AccessType:         "l2tp",
+		L2TpLocalIp:        net.IPv4(10, 0, 0, 1).To4(),
+		L2TpPeerIp:         peer.To4(),
+		L2TpLocalTunnelId:  5,
+		L2TpLocalSessionId: 9,
+		L2TpRole:           roleLAC,
+	}
+	c.ApplySyncedSession(hapb.SyncAction_SYNC_ACTION_UPDATE, cp)
+	if c.LookupSession(peer, 5, 9) == nil {
+		t.Fatal("synced LAC session not adopted")
+	}
+
+	c.ApplySyncedSession(hapb.SyncAction_SYNC_ACTION_DELETE, cp)
+	if c.LookupTunnel(peer, 5) != nil {
+		t.Fatal("tunnel should be dropped with its last standby session")
+	}
+}
+
+func TestRestoredServiceGroupKeepsAAARates(t *testing.T) {
+	resolver := svcgroup.New()
+	resolver.Set("lns", &servicegroup.Config{QoS: &servicegroup.QoSConfig{EgressPolicy: "shaped", DownloadRate: 10_000_000}})
+
+	c := New(logger.Get("l2tp"))
+	c.SetServiceGroupResolver(resolver)
+
+	sg := c.restoredServiceGroup("lns", "", map[string]string{aaa.AttrQoSDownloadRate: "50000000"})
+	if sg.DownloadRate != 50_000_000 || sg.QoSEgress != "shaped" {
+		t.Fatalf("restored with AAA rate: %+v", sg)
+	}
+	if sg := c.restoredServiceGroup("lns", "", nil); sg.DownloadRate != 10_000_000 {
+		t.Fatalf("restored without attributes: download rate %d", sg.DownloadRate)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/veesix-networks/osvbng/pkg/auth"
 	"github.com/veesix-networks/osvbng/pkg/dataplane"
 	l2tppkt "github.com/veesix-networks/osvbng/pkg/l2tp"
 )
@@ -132,12 +133,23 @@ func (c *Component) startTunnelRunner(t *Tunnel, helloInterval time.Duration) {
 	t.Channel = l2tppkt.NewControlChannel(cfg, func(body []byte, sessionID, ns, nr uint16) error {
 		return r.sendBody(body, sessionID, ns, nr)
 	}, func() {
-		// Channel declared dead — drive the tunnel to Cleanup.
-		t.FSM.Stop()
-		c.unregisterTunnel(t.PeerIP, t.LocalID)
-		c.uninstallTunnelVPP(t)
+		// Channel declared dead — drive the tunnel to Cleanup, unless
+		// the peer may still recover it. The callback runs inside Tick
+		// on the runner goroutine, which teardown waits on, so it
+		// continues off-goroutine.
+		if c.holdForRecovery(t) {
+			return
+		}
+		go c.teardownTunnel(t, auth.TerminateCauseLostCarrier)
 	})
 
+	t.mu.Lock()
+	if t.resuming {
+		t.Channel.Restore(t.resumeNs, t.resumeNr)
+		t.resuming = false
+	}
+	t.mu.Unlock()
+
 	c.mu.Lock()
 	if c.runners == nil {
 		c.runners = make(map[tunnelKey]*tunnelRunner)
@@ -46,11 +46,26 @@ type KernelUDPTransport struct {
 // netns. The returned socket retains its netns; reads and writes from
 // any goroutine route through the correct netns regardless of the
 // current OS thread.
+//
+// A nil `listenIP` opens a dual-stack socket so IPv4 and IPv6 tunnels
+// share one transport; if the netns has IPv6 disabled the socket falls
+// back to IPv4-only. A non-nil `listenIP` binds that family only.
 func NewKernelUDPTransport(listenIP net.IP, nsName string) (*KernelUDPTransport, error) {
 	addr := &net.UDPAddr{IP: listenIP, Port: 1701}
 
 	open := func() (*net.UDPConn, error) {
-		return net.ListenUDP("udp4", addr)
+		switch {
+		case listenIP == nil:
+			conn, err := net.ListenUDP("udp", addr)
+			if err == nil {
+				return conn, nil
+			}
+			return net.ListenUDP("udp4", addr)
+		case listenIP.To4() != nil:
+			return net.ListenUDP("udp4", addr)
+		default:
+			return net.ListenUDP("udp6", addr)
+		}
 	}
 
 	if nsName == "" {
@@ -151,11 +166,8 @@ func (t *KernelUDPTransport) Feed(ctx context.Context, localIP net.IP, ch chan<-
 		// Build a minimal ParsedPacket carrying just enough for the
 		// L2TP dispatcher: the UDP layer (for src/dst port + payload)
 		// and the IP layer (for the src/dst IP that keys our session
-		// lookup).
-		ip := &layers.IPv4{
-			SrcIP: peer.IP.To4(),
-			DstIP: localIP.To4(),
-		}
+		// lookup). A dual-stack socket reports IPv4 peers as
+		// v4-mapped addresses, so the family is taken from To4.
 		udp := &layers.UDP{
 			SrcPort: layers.UDPPort(peer.Port),
 			DstPort: 1701,
@@ -163,9 +175,17 @@ func (t *KernelUDPTransport) Feed(ctx context.Context, localIP net.IP, ch chan<-
 		udp.BaseLayer.Payload = payload
 		pkt := &dataplane.ParsedPacket{
 			Protocol: models.ProtocolL2TP,
-			IPv4:     ip,
 			UDP:      udp,
 		}
+		if v4 := peer.IP.To4(); v4 != nil {
+			pkt.IPv4 = &layers.IPv4{SrcIP: v4, DstIP: localIP.To4()}
+		} else {
+			var dst net.IP
+			if localIP != nil && localIP.To4() == nil {
+				dst = localIP
+			}
+			pkt.IPv6 = &layers.IPv6{SrcIP: peer.IP, DstIP: dst}
+		}
 
 		select {
 		case ch <- pkt:
@@ -93,6 +93,10 @@ type Session struct {
 	PPPoESessionID uint16
 	PPPoESwIfIndex uint32
 
+	// LAC-only: the bring-up request, held until ICRP completes the
+	// session or it fails.
+	lacReq *LACBringUpRequest
+
 	ActivatedAt time.Time
 	BoundAt     time.Time
 }
@@ -67,6 +67,65 @@ type Tunnel struct {
 	// this tunnel in its bihash. Gates AddL2TPSession calls; cleared by
 	// uninstallTunnelVPP on teardown.
 	installedInVPP bool
+
+	// SRGName is the redundancy group of the sessions the tunnel
+	// carries; the tunnel fails over with them. Set by the first
+	// session with an SRG binding.
+	SRGName string
+
+	// standby marks a tunnel rebuilt from a peer's HA sync (or
+	// demoted): control state only, no runner and no dataplane
+	// entries until promotion.
+	standby bool
+
+	// resumeNs / resumeNr seed the control channel of a restored
+	// tunnel when its runner starts: the checkpointed values until the
+	// peer suggests its own during recovery (see completeRecovery).
+	resuming bool
+	resumeNs uint16
+	resumeNr uint16
+
+	// failover is set when both ends advertised RFC 4951 control
+	// channel failover; only such tunnels survive a restart or an HA
+	// takeover. peerRecoveryTime is how long the peer said it needs to
+	// recover, and how long a dead channel is held for it.
+	failover         bool
+	peerRecoveryTime time.Duration
+
+	// recovering marks a restored tunnel waiting for its recovery
+	// tunnel's SCCRP; inbound control messages are dropped until then.
+	// recoveryHold is armed when the peer's channel dies and tears the
+	// tunnel down unless the peer recovers it first.
+	recovering   bool
+	recoveryHold *time.Timer
+
+	// recovers links a recovery tunnel to the tunnel it recovers.
+	// recovered is set once that tunnel resumed, so closing the
+	// recovery tunnel no longer takes it along.
+	recovers  *Tunnel
+	recovered bool
+
+	// probe marks a throwaway LAC tunnel opened by an SCCRQ health
+	// check. It carries no sessions, is never accounted, and reports
+	// the outcome on probeResult.
+	probe       bool
+	probeResult chan error
+
+	// tornDown is set by the first teardownTunnel; later calls (a
+	// dead channel racing a StopCCN or a clear) are no-ops.
+	tornDown bool
+}
+
+// finishProbe reports the outcome of an SCCRQ health check. The first
+// report wins.
+func (t *Tunnel) finishProbe(err error) {
+	if !t.probe {
+		return
+	}
+	select {
+	case t.probeResult <- err:
+	default:
+	}
 }
 
 func (t *Tunnel) addSession(s *Session) {
@@ -88,6 +147,28 @@ func (t *Tunnel) removeSession(localID uint16) (empty bool) {
 	return empty
 }
 
+// sequence returns the control channel's next-send and next-expected
+// sequence numbers, or the checkpointed values of a tunnel whose
+// runner has not started.
+func (t *Tunnel) sequence() (ns, nr uint16) {
+	if t.Channel != nil {
+		return t.Channel.Ns(), t.Channel.Nr()
+	}
+	return t.resumeNs, t.resumeNr
+}
+
+func (t *Tunnel) isRecovering() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.recovering
+}
+
+func (t *Tunnel) isStandby() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.standby
+}
+
 func (t *Tunnel) snapshotSessions() []*Session {
 	t.mu.Lock()
 	defer t.mu.Unlock()
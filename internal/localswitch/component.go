@@ -0,0 +1,545 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package localswitch applies the local-switching mode of each
+// subscriber group to its IPoE sessions. The mode decides whether
+// subscribers of the group sharing a subnet reach each other through
+// the BNG: it is enforced with a deny-only ACL on each session's
+// inbound traffic and tells the ARP and ND proxies whether to answer
+// for a neighbour.
+package localswitch
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/netip"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// tick is how often the ACLs are brought in line with the sessions and
+// the running config.
+const tick = time.Second
+
+// aclPrefix starts the name of every ACL the component programs.
+const aclPrefix = aclcfg.ReservedPrefix + "local-switching:"
+
+// Southbound is what the component needs from the dataplane.
+type Southbound interface {
+	AddReplaceACL(name string, entries []aclcfg.Entry) (uint32, error)
+	DeleteACL(name string) error
+	ApplyLocalSwitchingACL(swIfIndex uint32, aclName string) error
+	RemoveLocalSwitchingACL(swIfIndex uint32) error
+}
+
+// Config wires the component. Without an EventBus no session is known
+// and nothing is programmed.
+type Config struct {
+	EventBus      events.Bus
+	ConfigManager component.ConfigManager
+	Southbound    Southbound
+}
+
+// Component tracks the IPoE sessions and the ACLs bound to them.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+
+	// mu guards what the events teach about the sessions. It is never
+	// held across a dataplane call, so the neighbour proxies are not
+	// held up behind one.
+	mu       sync.Mutex
+	sessions map[string]*sessionInfo
+	// byAddr maps the IPv4 and IANA addresses of the sessions to their
+	// IDs, for the neighbour proxies.
+	byAddr map[netip.Addr]string
+
+	// applyMu serializes the dataplane programming and guards the
+	// state it records. It is taken before mu.
+	applyMu sync.Mutex
+	// acls are the entries last programmed for each ACL.
+	acls map[string][]aclcfg.Entry
+	// bound is the ACL bound to each session's interface.
+	bound map[string]binding
+	// errs holds the last failure of each ACL and session, so a
+	// failure is logged once rather than on every reconcile.
+	errs map[string]string
+
+	subs []events.Subscription
+}
+
+// sessionInfo is what the component has learnt about a session. The
+// subscriber group is looked up from the VLANs when needed, so a
+// config change moves the session to its new group.
+type sessionInfo struct {
+	swIfIndex    uint32
+	outerVLAN    uint16
+	innerVLAN    uint16
+	serviceGroup string
+	ipv4         netip.Addr
+	ipv6         netip.Addr
+	prefix       netip.Prefix
+}
+
+// binding is the ACL a session's interface is bound to.
+type binding struct {
+	swIfIndex uint32
+	acl       string
+}
+
+func New(cfg Config) *Component {
+	return &Component{
+		Base:     component.NewBase("localswitch"),
+		logger:   logger.Get("localswitch"),
+		cfg:      cfg,
+		sessions: make(map[string]*sessionInfo),
+		byAddr:   make(map[netip.Addr]string),
+		acls:     make(map[string][]aclcfg.Entry),
+		bound:    make(map[string]binding),
+		errs:     make(map[string]string),
+	}
+}
+
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting local switching component")
+	if c.cfg.EventBus == nil {
+		return nil
+	}
+	c.subs = append(c.subs,
+		c.cfg.EventBus.Subscribe(events.TopicSessionLifecycle, c.handleSessionLifecycle),
+		c.cfg.EventBus.Subscribe(events.TopicSessionProgrammed, c.handleSessionProgrammed),
+		c.cfg.EventBus.Subscribe(events.TopicSessionRestored, c.handleSessionRestored),
+	)
+	c.Go(c.run)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping local switching component")
+	for _, sub := range c.subs {
+		sub.Unsubscribe()
+	}
+	c.StopContext()
+	return nil
+}
+
+func (c *Component) run() {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.reconcile()
+		}
+	}
+}
+
+// ProxyNeighbor reports whether the ARP or ND proxy should answer the
+// session's request for target with the BNG's MAC, drawing the traffic
+// through the BNG. It does when target is the address of another
+// session of the same subscriber group and the group's mode lets the
+// two reach each other: always for hairpin, and for service-group when
+// both sessions are in the same service group.
+func (c *Component) ProxyNeighbor(sessionID string, target net.IP) bool {
+	addr, ok := netip.AddrFromSlice(target)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	peerID, ok := c.byAddr[addr]
+	if !ok || peerID == sessionID {
+		return false
+	}
+	self, peer := c.sessions[sessionID], c.sessions[peerID]
+	if self == nil || peer == nil {
+		return false
+	}
+	match, ok := c.group(self)
+	if !ok {
+		return false
+	}
+	if peerMatch, ok := c.group(peer); !ok || peerMatch.Name != match.Name {
+		return false
+	}
+	switch match.Group.LocalSwitching {
+	case subscriber.LocalSwitchingHairpin:
+		return true
+	case subscriber.LocalSwitchingServiceGroup:
+		return self.serviceGroup != "" && self.serviceGroup == peer.serviceGroup
+	}
+	return false
+}
+
+func (c *Component) handleSessionLifecycle(event events.Event) {
+	data, ok := event.Data.(*events.SessionLifecycleEvent)
+	if !ok || data.AccessType != models.AccessTypeIPoE {
+		return
+	}
+	switch data.State {
+	case models.SessionStateActive:
+		if sess, ok := data.Session.(models.SubscriberSession); ok {
+			c.update(data.SessionID, sess)
+		}
+	case models.SessionStateReleased:
+		c.release(data.SessionID)
+	}
+}
+
+func (c *Component) handleSessionProgrammed(event events.Event) {
+	data, ok := event.Data.(*events.SessionLifecycleEvent)
+	if !ok || data.AccessType != models.AccessTypeIPoE {
+		return
+	}
+	if sess, ok := data.Session.(models.SubscriberSession); ok {
+		c.update(data.SessionID, sess)
+	}
+}
+
+func (c *Component) handleSessionRestored(event events.Event) {
+	data, ok := event.Data.(*events.SessionRestoredEvent)
+	if !ok || data.Session == nil || data.Session.GetAccessType() != models.AccessTypeIPoE {
+		return
+	}
+	c.update(data.SessionID, data.Session)
+}
+
+// update merges what an event says about a session. The ACLs follow on
+// the next reconcile.
+func (c *Component) update(sessionID string, sess models.SubscriberSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, ok := c.sessions[sessionID]
+	if !ok {
+		info = &sessionInfo{}
+		c.sessions[sessionID] = info
+	}
+	if idx := sess.GetIfIndex(); idx != 0 {
+		info.swIfIndex = idx
+	}
+	info.outerVLAN, info.innerVLAN = sess.GetOuterVLAN(), sess.GetInnerVLAN()
+	if sg := sess.GetServiceGroup(); sg != "" {
+		info.serviceGroup = sg
+	}
+	if a, ok := netip.AddrFromSlice(sess.GetIPv4Address().To4()); ok && a.IsValid() && !a.IsUnspecified() {
+		c.setAddrLocked(sessionID, &info.ipv4, a)
+	}
+	if a, ok := netip.AddrFromSlice(sess.GetIPv6Address().To16()); ok && sess.GetIPv6Address().To4() == nil && !a.IsUnspecified() {
+		c.setAddrLocked(sessionID, &info.ipv6, a)
+	}
+	if p, err := netip.ParsePrefix(sess.GetIPv6Prefix()); err == nil {
+		info.prefix = p.Masked()
+	}
+}
+
+func (c *Component) setAddrLocked(sessionID string, field *netip.Addr, a netip.Addr) {
+	if *field == a {
+		return
+	}
+	if field.IsValid() && c.byAddr[*field] == sessionID {
+		delete(c.byAddr, *field)
+	}
+	*field = a
+	c.byAddr[a] = sessionID
+}
+
+// release forgets the session and unbinds its ACL.
+func (c *Component) release(sessionID string) {
+	c.applyMu.Lock()
+	defer c.applyMu.Unlock()
+
+	c.mu.Lock()
+	info := c.sessions[sessionID]
+	if info != nil {
+		delete(c.sessions, sessionID)
+		for _, a := range []netip.Addr{info.ipv4, info.ipv6} {
+			if a.IsValid() && c.byAddr[a] == sessionID {
+				delete(c.byAddr, a)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	delete(c.errs, "session:"+sessionID)
+	if b, ok := c.bound[sessionID]; ok {
+		delete(c.bound, sessionID)
+		if err := c.cfg.Southbound.RemoveLocalSwitchingACL(b.swIfIndex); err != nil {
+			// The session interface is usually deleted alongside.
+			c.logger.Debug("Failed to unbind local switching ACL", "session_id", sessionID, "error", err)
+		}
+	}
+}
+
+func (c *Component) group(info *sessionInfo) (subscriber.GroupMatch, bool) {
+	if c.cfg.ConfigManager == nil {
+		return subscriber.GroupMatch{}, false
+	}
+	match, ok := c.cfg.ConfigManager.LookupSubscriberGroup(info.outerVLAN, info.innerVLAN)
+	if !ok || match.Group == nil {
+		return subscriber.GroupMatch{}, false
+	}
+	return match, true
+}
+
+// reconcile programs the ACL each session needs, binds it, and removes
+// the ACLs no session uses any more. A session whose group has no mode,
+// or hairpin, has none bound. What the sessions need is worked out
+// under mu; the dataplane is programmed after it is released.
+func (c *Component) reconcile() {
+	var cfg *config.Config
+	if c.cfg.ConfigManager != nil {
+		cfg, _ = c.cfg.ConfigManager.GetRunning()
+	}
+
+	c.applyMu.Lock()
+	defer c.applyMu.Unlock()
+
+	c.mu.Lock()
+	want, acls := c.desiredLocked(cfg)
+	c.mu.Unlock()
+
+	c.apply(want, acls)
+}
+
+// desiredLocked returns the ACL each session should be bound to and
+// the entries of each of those ACLs. A session whose ACL would have no
+// entries, because its service group covers all of the group's pools,
+// needs none. Caller holds mu.
+func (c *Component) desiredLocked(cfg *config.Config) (map[string]binding, map[string][]aclcfg.Entry) {
+	want := make(map[string]binding, len(c.sessions))
+	acls := make(map[string][]aclcfg.Entry)
+	if cfg == nil {
+		return want, acls
+	}
+	for id, info := range c.sessions {
+		if info.swIfIndex == 0 {
+			continue
+		}
+		match, ok := c.group(info)
+		if !ok {
+			continue
+		}
+		var sg string
+		switch match.Group.LocalSwitching {
+		case subscriber.LocalSwitchingIsolate:
+		case subscriber.LocalSwitchingServiceGroup:
+			// Without a service group the session is isolated.
+			sg = info.serviceGroup
+		default:
+			continue
+		}
+		name := aclName(match.Name, sg)
+		entries, ok := acls[name]
+		if !ok {
+			entries = c.entries(cfg, match.Group, sg)
+			acls[name] = entries
+		}
+		if len(entries) > 0 {
+			want[id] = binding{swIfIndex: info.swIfIndex, acl: name}
+		}
+	}
+	for name, entries := range acls {
+		if len(entries) == 0 {
+			delete(acls, name)
+		}
+	}
+	return want, acls
+}
+
+// entries builds an ACL that denies the group's pools, less the
+// gateways and, for a service group, the pools its members are
+// addressed from. It only denies: traffic it does not match goes on to
+// the session's ingress ACL, which decides what is permitted.
+func (c *Component) entries(cfg *config.Config, group *subscriber.SubscriberGroup, serviceGroup string) []aclcfg.Entry {
+	var holes []netip.Prefix
+	for _, gw := range cfg.LocalSwitchingGateways(group) {
+		holes = append(holes, netip.PrefixFrom(gw, gw.BitLen()))
+	}
+	if serviceGroup != "" {
+		holes = append(holes, cfg.LocalSwitchingServiceGroupPrefixes(group, serviceGroup)...)
+	}
+	deny := subtractPrefixes(cfg.LocalSwitchingPrefixes(group), holes)
+	if len(deny) == 0 {
+		return nil
+	}
+	rules := make([]aclcfg.Rule, 0, len(deny))
+	for _, p := range deny {
+		rules = append(rules, aclcfg.Rule{Action: aclcfg.ActionDeny, Destination: p.String()})
+	}
+	entries, _, err := (&aclcfg.AccessList{Rules: rules}).Expand()
+	if err != nil {
+		c.logger.Warn("Local switching ACL left empty", "error", err)
+		return nil
+	}
+	return entries
+}
+
+// apply programs the ACLs whose entries changed, moves the sessions
+// whose ACL changed, and deletes the ACLs left unused. What fails is
+// retried on the next reconcile and logged once until it succeeds.
+// Caller holds applyMu, not mu.
+func (c *Component) apply(want map[string]binding, acls map[string][]aclcfg.Entry) {
+	for _, name := range sortedKeys(acls) {
+		entries := acls[name]
+		if prev, ok := c.acls[name]; ok && reflect.DeepEqual(prev, entries) {
+			continue
+		}
+		if _, err := c.cfg.Southbound.AddReplaceACL(name, entries); err != nil {
+			c.fail("acl:"+name, err, "acl", name)
+			if _, ok := c.acls[name]; !ok {
+				delete(acls, name)
+			}
+			continue
+		}
+		c.acls[name] = entries
+		c.succeed("acl:" + name)
+		c.logger.Debug("Local switching ACL programmed", "acl", name, "entries", len(entries))
+	}
+
+	for _, id := range sortedKeys(want) {
+		b := want[id]
+		if c.bound[id] == b {
+			continue
+		}
+		if _, ok := c.acls[b.acl]; !ok {
+			continue
+		}
+		if err := c.cfg.Southbound.ApplyLocalSwitchingACL(b.swIfIndex, b.acl); err != nil {
+			c.fail("session:"+id, err, "session_id", id, "acl", b.acl)
+			continue
+		}
+		c.bound[id] = b
+		c.succeed("session:" + id)
+		c.logger.Debug("Local switching ACL bound", "session_id", id, "sw_if_index", b.swIfIndex, "acl", b.acl)
+	}
+	for _, id := range sortedKeys(c.bound) {
+		if _, ok := want[id]; ok {
+			continue
+		}
+		b := c.bound[id]
+		if err := c.cfg.Southbound.RemoveLocalSwitchingACL(b.swIfIndex); err != nil {
+			c.fail("session:"+id, err, "session_id", id)
+			continue
+		}
+		delete(c.bound, id)
+		c.succeed("session:" + id)
+		c.logger.Debug("Local switching ACL unbound", "session_id", id, "sw_if_index", b.swIfIndex)
+	}
+
+	inUse := make(map[string]bool, len(c.bound))
+	for _, b := range c.bound {
+		inUse[b.acl] = true
+	}
+	for _, name := range sortedKeys(c.acls) {
+		if _, ok := acls[name]; ok || inUse[name] {
+			continue
+		}
+		if err := c.cfg.Southbound.DeleteACL(name); err != nil {
+			c.fail("acl:"+name, err, "acl", name)
+			continue
+		}
+		delete(c.acls, name)
+		c.succeed("acl:" + name)
+		c.logger.Debug("Local switching ACL deleted", "acl", name)
+	}
+}
+
+func (c *Component) fail(key string, err error, args ...any) {
+	msg := err.Error()
+	if c.errs[key] == msg {
+		return
+	}
+	c.errs[key] = msg
+	c.logger.Warn("Failed to program local switching", append(args, "error", err)...)
+}
+
+func (c *Component) succeed(key string) {
+	delete(c.errs, key)
+}
+
+// aclName names the ACL of a subscriber group, or of one service group
+// within it. Names too long for the dataplane are hashed.
+func aclName(group, serviceGroup string) string {
+	name := aclPrefix + group
+	if serviceGroup != "" {
+		name += ":" + serviceGroup
+	}
+	if len(name) <= aclcfg.MaxNameLength {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%s%08x", aclPrefix, h.Sum32())
+}
+
+// subtractPrefixes returns the address space of from not covered by
+// any of holes, as the fewest prefixes a split along the prefix tree
+// gives, in address order.
+func subtractPrefixes(from, holes []netip.Prefix) []netip.Prefix {
+	var out []netip.Prefix
+	var walk func(p netip.Prefix)
+	walk = func(p netip.Prefix) {
+		split := false
+		for _, h := range holes {
+			if h.Addr().BitLen() != p.Addr().BitLen() {
+				continue
+			}
+			if h.Bits() <= p.Bits() && h.Contains(p.Addr()) {
+				return
+			}
+			if h.Bits() > p.Bits() && p.Contains(h.Addr()) {
+				split = true
+			}
+		}
+		if !split {
+			out = append(out, p)
+			return
+		}
+		lo, hi := halves(p)
+		walk(lo)
+		walk(hi)
+	}
+	for _, p := range from {
+		walk(p.Masked())
+	}
+	return out
+}
+
+// halves splits p into its two prefixes one bit longer.
+func halves(p netip.Prefix) (netip.Prefix, netip.Prefix) {
+	bits := p.Bits() + 1
+	b := p.Addr().AsSlice()
+	lo := netip.PrefixFrom(p.Addr(), bits)
+	b[p.Bits()/8] |= 0x80 >> (p.Bits() % 8)
+	hiAddr, _ := netip.AddrFromSlice(b)
+	return lo, netip.PrefixFrom(hiAddr, bits)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package localswitch
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// fakeCfg maps the S-VLAN to the group: 100 is biz, 200 is res.
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	name := map[uint16]string{100: "biz", 200: "res"}[svlan]
+	g := f.cfg.SubscriberGroups.Groups[name]
+	return subscriber.GroupMatch{Name: name, Group: g}, g != nil
+}
+
+type fakeSB struct {
+	acls  map[string][]string
+	bound map[uint32]string
+	calls []string
+	// during, when set, runs inside every call that programs an ACL.
+	during func()
+}
+
+func (f *fakeSB) AddReplaceACL(name string, entries []aclcfg.Entry) (uint32, error) {
+	if f.during != nil {
+		f.during()
+	}
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Action+" "+e.Destination.String())
+	}
+	f.acls[name] = out
+	f.calls = append(f.calls, "acl "+name)
+	return 0, nil
+}
+
+func (f *fakeSB) DeleteACL(name string) error {
+	delete(f.acls, name)
+	f.calls = append(f.calls, "delete "+name)
+	return nil
+}
+
+func (f *fakeSB) ApplyLocalSwitchingACL(swIfIndex uint32, aclName string) error {
+	f.bound[swIfIndex] = aclName
+	f.calls = append(f.calls, fmt.Sprintf("bind %d %s", swIfIndex, aclName))
+	return nil
+}
+
+func (f *fakeSB) RemoveLocalSwitchingACL(swIfIndex uint32) error {
+	delete(f.bound, swIfIndex)
+	f.calls = append(f.calls, fmt.Sprintf("unbind %d", swIfIndex))
+	return nil
+}
+
+func (f *fakeSB) reset() []string {
+	calls := f.calls
+	f.calls = nil
+	sort.Strings(calls)
+	return calls
+}
+
+func newTestComponent(t *testing.T) (*Component, *fakeSB, *config.Config) {
+	t.Helper()
+	cfg := &config.Config{
+		SubscriberGroups: &subscriber.SubscriberGroupsConfig{Groups: map[string]*subscriber.SubscriberGroup{
+			"biz": {IPv4Profile: "biz", LocalSwitching: subscriber.LocalSwitchingServiceGroup},
+			"res": {IPv4Profile: "res", LocalSwitching: subscriber.LocalSwitchingIsolate},
+		}},
+		IPv4Profiles: map[string]*ip.IPv4Profile{
+			"biz": {Gateway: "100.64.0.1", Pools: []ip.IPv4Pool{
+				{Name: "shared", Network: "100.64.0.0/25"},
+				{Name: "gold", Network: "100.64.0.128/25"},
+			}},
+			"res": {Pools: []ip.IPv4Pool{{Network: "10.0.0.0/24", Gateway: "10.0.0.1"}}},
+		},
+		ServiceGroups: map[string]*servicegroup.Config{
+			"gold": {Pool: "gold"},
+		},
+	}
+	sb := &fakeSB{acls: map[string][]string{}, bound: map[uint32]string{}}
+	c := New(Config{ConfigManager: &fakeCfg{cfg: cfg}, Southbound: sb})
+	return c, sb, cfg
+}
+
+func active(id string, ifIndex uint32, svlan uint16, sg, addr string) events.Event {
+	return events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		SessionID:  id,
+		State:      models.SessionStateActive,
+		Session: &models.IPoESession{
+			SessionID:    id,
+			AccessType:   string(models.AccessTypeIPoE),
+			IfIndex:      ifIndex,
+			OuterVLAN:    svlan,
+			ServiceGroup: sg,
+			IPv4Address:  net.ParseIP(addr),
+		},
+	}}
+}
+
+// denies reports whether the entries of an ACL programmed on sb drop
+// traffic toward addr.
+func denies(t *testing.T, sb *fakeSB, acl, addr string) bool {
+	t.Helper()
+	a := netip.MustParseAddr(addr)
+	for _, e := range sb.acls[acl] {
+		action, dst, _ := strings.Cut(e, " ")
+		if action != aclcfg.ActionDeny {
+			t.Fatalf("%s: %q is not a deny", acl, e)
+		}
+		if netip.MustParsePrefix(dst).Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func released(id string) events.Event {
+	return events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: models.AccessTypeIPoE,
+		SessionID:  id,
+		State:      models.SessionStateReleased,
+	}}
+}
+
+func TestServiceGroupACLs(t *testing.T) {
+	c, sb, _ := newTestComponent(t)
+	c.handleSessionLifecycle(active("a", 1, 100, "gold", "100.64.0.130"))
+	c.handleSessionLifecycle(active("b", 2, 100, "gold", "100.64.0.131"))
+	c.handleSessionLifecycle(active("d", 3, 100, "", "100.64.0.12"))
+	c.handleSessionLifecycle(active("r", 4, 200, "", "10.0.0.5"))
+	c.reconcile()
+
+	wantBound := map[uint32]string{
+		1: "osvbng-local-switching:biz:gold",
+		2: "osvbng-local-switching:biz:gold",
+		3: "osvbng-local-switching:biz",
+		4: "osvbng-local-switching:res",
+	}
+	if !reflect.DeepEqual(sb.bound, wantBound) {
+		t.Fatalf("bound = %v", sb.bound)
+	}
+	cases := []struct {
+		acl, addr string
+		want      bool
+	}{
+		{"osvbng-local-switching:biz:gold", "100.64.0.1", false},
+		{"osvbng-local-switching:biz:gold", "100.64.0.131", false},
+		{"osvbng-local-switching:biz:gold", "100.64.0.200", false},
+		{"osvbng-local-switching:biz:gold", "100.64.0.12", true},
+		{"osvbng-local-switching:biz:gold", "100.64.0.0", true},
+		{"osvbng-local-switching:biz:gold", "8.8.8.8", false},
+		{"osvbng-local-switching:biz", "100.64.0.1", false},
+		{"osvbng-local-switching:biz", "100.64.0.12", true},
+		{"osvbng-local-switching:biz", "100.64.0.131", true},
+		{"osvbng-local-switching:res", "10.0.0.1", false},
+		{"osvbng-local-switching:res", "10.0.0.6", true},
+	}
+	for _, tc := range cases {
+		if got := denies(t, sb, tc.acl, tc.addr); got != tc.want {
+			t.Errorf("%s denies %s = %v, want %v", tc.acl, tc.addr, got, tc.want)
+		}
+	}
+	sb.reset()
+
+	// Nothing changed: nothing is reprogrammed.
+	c.reconcile()
+	if calls := sb.reset(); len(calls) != 0 {
+		t.Fatalf("calls = %v", calls)
+	}
+
+	// A member leaving leaves the ACL alone: it is keyed on the
+	// service group's pools, not on its members.
+	c.handleSessionLifecycle(released("b"))
+	c.reconcile()
+	if got := strings.Join(sb.reset(), ","); got != "unbind 2" {
+		t.Fatalf("calls = %s", got)
+	}
+
+	// The last member leaving deletes the ACL.
+	c.handleSessionLifecycle(released("a"))
+	c.reconcile()
+	if got := strings.Join(sb.reset(), ","); got != "delete osvbng-local-switching:biz:gold,unbind 1" {
+		t.Fatalf("calls = %s", got)
+	}
+}
+
+// TestProxyNeighborDuringApply checks the neighbour proxies are
+// answered while the dataplane is being programmed.
+func TestProxyNeighborDuringApply(t *testing.T) {
+	c, sb, cfg := newTestComponent(t)
+	cfg.SubscriberGroups.Groups["res"].LocalSwitching = subscriber.LocalSwitchingHairpin
+	c.handleSessionLifecycle(active("a", 1, 100, "gold", "100.64.0.130"))
+	c.handleSessionLifecycle(active("r", 4, 200, "", "10.0.0.5"))
+	c.handleSessionLifecycle(active("s", 5, 200, "", "10.0.0.6"))
+
+	var proxied bool
+	sb.during = func() { proxied = c.ProxyNeighbor("r", net.ParseIP("10.0.0.6")) }
+	c.reconcile()
+	if !proxied {
+		t.Fatal("r -> 10.0.0.6 not proxied during reconcile")
+	}
+}
+
+func TestSubtractPrefixes(t *testing.T) {
+	from := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24"), netip.MustParsePrefix("2001:db8::/64")}
+	holes := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.1/32"),
+		netip.MustParsePrefix("10.0.0.128/25"),
+		netip.MustParsePrefix("2001:db8::/65"),
+		netip.MustParsePrefix("192.0.2.0/24"),
+	}
+	got := fmt.Sprint(subtractPrefixes(from, holes))
+	want := "[10.0.0.0/32 10.0.0.2/31 10.0.0.4/30 10.0.0.8/29 10.0.0.16/28 10.0.0.32/27 10.0.0.64/26 2001:db8:0:0:8000::/65]"
+	if got != want {
+		t.Errorf("subtract = %s, want %s", got, want)
+	}
+	if got := subtractPrefixes(from[:1], []netip.Prefix{netip.MustParsePrefix("10.0.0.0/16")}); len(got) != 0 {
+		t.Errorf("covered prefix left %v", got)
+	}
+}
+
+func TestModeChange(t *testing.T) {
+	c, sb, cfg := newTestComponent(t)
+	c.handleSessionLifecycle(active("r", 4, 200, "", "10.0.0.5"))
+	c.reconcile()
+	sb.reset()
+
+	cfg.SubscriberGroups.Groups["res"].LocalSwitching = subscriber.LocalSwitchingHairpin
+	c.reconcile()
+	if got := strings.Join(sb.reset(), ","); got != "delete osvbng-local-switching:res,unbind 4" {
+		t.Fatalf("calls = %s", got)
+	}
+}
+
+func TestProxyNeighbor(t *testing.T) {
+	c, _, cfg := newTestComponent(t)
+	c.handleSessionLifecycle(active("a", 1, 100, "gold", "100.64.0.130"))
+	c.handleSessionLifecycle(active("b", 2, 100, "gold", "100.64.0.131"))
+	c.handleSessionLifecycle(active("d", 3, 100, "", "100.64.0.12"))
+	c.handleSessionLifecycle(active("r", 4, 200, "", "10.0.0.5"))
+	c.handleSessionLifecycle(active("s", 5, 200, "", "10.0.0.6"))
+
+	cases := []struct {
+		from, target string
+		want         bool
+	}{
+		{"a", "100.64.0.131", true},
+		{"a", "100.64.0.12", false},
+		{"d", "100.64.0.130", false},
+		{"a", "100.64.0.130", false},
+		{"a", "10.0.0.5", false},
+		{"a", "100.64.0.99", false},
+		{"r", "10.0.0.6", false},
+	}
+	for _, tc := range cases {
+		if got := c.ProxyNeighbor(tc.from, net.ParseIP(tc.target)); got != tc.want {
+			t.Errorf("%s -> %s = %v, want %v", tc.from, tc.target, got, tc.want)
+		}
+	}
+
+	cfg.SubscriberGroups.Groups["res"].LocalSwitching = subscriber.LocalSwitchingHairpin
+	if !c.ProxyNeighbor("r", net.ParseIP("10.0.0.6")) {
+		t.Error("hairpin: r -> 10.0.0.6 not proxied")
+	}
+	c.handleSessionLifecycle(released("s"))
+	if c.ProxyNeighbor("r", net.ParseIP("10.0.0.6")) {
+		t.Error("released session still proxied")
+	}
+}
+
+func TestACLNameLength(t *testing.T) {
+	name := aclName(strings.Repeat("g", 40), strings.Repeat("s", 40))
+	if len(name) > aclcfg.MaxNameLength || !strings.HasPrefix(name, aclPrefix) {
+		t.Fatalf("name = %q", name)
+	}
+}
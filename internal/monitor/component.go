@@ -8,28 +8,42 @@ import (
 	"context"
 
 	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/events"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 )
 
 // Component is the monitoring lifecycle owner. Metric emission flows
-// through pkg/telemetry.RegisterMetric in the show handlers themselves.
+// through pkg/telemetry.RegisterMetric in the show handlers themselves;
+// the component itself watches address pool watermarks.
 type Component struct {
 	*component.Base
 	logger *logger.Logger
+	cfg    Config
 }
 
-type Config struct{}
+// Config wires the pool watermark checks. Every field is optional:
+// without an EventBus no events are published, without Routing no
+// network is withdrawn, and without CGNAT only allocator pools are
+// checked.
+type Config struct {
+	EventBus      events.Bus
+	ConfigManager component.ConfigManager
+	Routing       BGPNetworkController
+	CGNAT         CGNATPoolChecker
+}
 
 func New(cfg Config) *Component {
 	return &Component{
 		Base:   component.NewBase("monitor"),
 		logger: logger.Get("monitor"),
+		cfg:    cfg,
 	}
 }
 
 func (c *Component) Start(ctx context.Context) error {
 	c.StartContext(ctx)
 	c.logger.Info("Starting monitoring component")
+	c.Go(c.watchPools)
 	return nil
 }
 
@@ -0,0 +1,188 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package monitor
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/events"
+)
+
+// thresholdCheckInterval matches the telemetry poll, so the level
+// gauge and the events never disagree for long.
+const thresholdCheckInterval = 10 * time.Second
+
+// BGPNetworkController withdraws and restores the network statement a
+// pool is advertised with.
+type BGPNetworkController interface {
+	AdvertiseBGPNetworkPolicy(asn uint32, vrf string, prefix string, routePolicy string, ipv6 bool) error
+	WithdrawBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error
+}
+
+// CGNATPoolChecker re-evaluates the CGNAT pools' watermarks.
+type CGNATPoolChecker interface {
+	CheckPoolThresholds() []allocator.PoolLevelChange
+}
+
+func (c *Component) watchPools() {
+	ticker := time.NewTicker(thresholdCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkPools()
+		case <-c.Ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Component) checkPools() {
+	changes := allocator.GetGlobalRegistry().CheckThresholds()
+	if c.cfg.CGNAT != nil {
+		changes = append(changes, c.cfg.CGNAT.CheckPoolThresholds()...)
+	}
+	for _, change := range changes {
+		c.poolLevelChanged(change)
+	}
+}
+
+func (c *Component) poolLevelChanged(change allocator.PoolLevelChange) {
+	u := change.Usage
+	if u.Level != change.Previous {
+		c.publishPoolLevel(change)
+	}
+
+	if change.Thresholds == nil || !change.Thresholds.WithdrawRoute {
+		return
+	}
+	if change.Drained() {
+		c.setPoolAdvertised(u, false)
+	} else if change.Refilled() {
+		c.setPoolAdvertised(u, true)
+	}
+}
+
+func (c *Component) publishPoolLevel(change allocator.PoolLevelChange) {
+	u := change.Usage
+	var utilization float64
+	if u.Size > 0 {
+		utilization = float64(u.Size-u.Available) / float64(u.Size)
+	}
+
+	log := c.logger.Info
+	if u.Level != allocator.PoolLevelNormal {
+		log = c.logger.Warn
+	}
+	log("Pool crossed utilisation threshold", "family", u.Family, "profile", u.Profile, "pool", u.Pool,
+		"level", u.Level, "previous", change.Previous, "size", u.Size, "available", u.Available)
+
+	if c.cfg.EventBus != nil {
+		c.cfg.EventBus.Publish(events.TopicPoolThreshold, events.Event{
+			Source: c.Name(),
+			Data: &events.PoolThresholdEvent{
+				Family:        string(u.Family),
+				Profile:       u.Profile,
+				Pool:          u.Pool,
+				Level:         string(u.Level),
+				PreviousLevel: string(change.Previous),
+				Size:          uint64(u.Size),
+				Available:     uint64(u.Available),
+				Utilization:   utilization,
+			},
+		})
+	}
+}
+
+// setPoolAdvertised withdraws or restores every network the running
+// config originates for the pool. The blackhole route stays installed
+// either way, so only the BGP origination changes.
+func (c *Component) setPoolAdvertised(u allocator.PoolUsage, advertise bool) {
+	if c.cfg.Routing == nil || c.cfg.ConfigManager == nil {
+		return
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil || cfg == nil || cfg.Protocols.BGP == nil {
+		return
+	}
+	asn := cfg.Protocols.BGP.ASN
+
+	for _, n := range poolNetworks(cfg, u) {
+		ipv6 := strings.Contains(n.prefix, ":")
+		if advertise {
+			err = c.cfg.Routing.AdvertiseBGPNetworkPolicy(asn, n.vrf, n.prefix, n.routePolicy, ipv6)
+		} else {
+			err = c.cfg.Routing.WithdrawBGPNetwork(asn, n.vrf, n.prefix, ipv6)
+		}
+		if err != nil {
+			c.logger.Error("Failed to update pool BGP network", "pool", u.Pool, "prefix", n.prefix, "vrf", n.vrf,
+				"advertise", advertise, "error", err)
+			continue
+		}
+		c.logger.Info("Updated pool BGP network", "pool", u.Pool, "prefix", n.prefix, "vrf", n.vrf, "advertise", advertise)
+	}
+}
+
+type poolNetwork struct {
+	vrf         string
+	prefix      string
+	routePolicy string
+}
+
+// poolNetworks returns the BGP networks the config manager originates
+// for a pool: an IPv4 pool's network for each subscriber group that
+// advertises its profile's pools, and a CGNAT pool's outside prefixes
+// unless HA owns their advertisement.
+func poolNetworks(cfg *config.Config, u allocator.PoolUsage) []poolNetwork {
+	var networks []poolNetwork
+	switch u.Family {
+	case allocator.PoolFamilyIPv4:
+		profile := cfg.IPv4Profiles[u.Profile]
+		if profile == nil || cfg.SubscriberGroups == nil {
+			return nil
+		}
+		for _, pool := range profile.Pools {
+			if pool.Name != u.Pool {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, group := range cfg.SubscriberGroups.Groups {
+				if group == nil || group.IPv4Profile != u.Profile || group.BGP == nil ||
+					!group.BGP.Enabled || !group.BGP.AdvertisePools {
+					continue
+				}
+				vrf := group.BGP.VRF
+				if vrf == "" {
+					vrf = group.VRF
+				}
+				if pool.VRF != vrf || seen[vrf] {
+					continue
+				}
+				seen[vrf] = true
+				networks = append(networks, poolNetwork{vrf: pool.VRF, prefix: pool.Network, routePolicy: group.BGP.NetworkRoutePolicy})
+			}
+		}
+	case allocator.PoolFamilyCGNAT:
+		if cfg.CGNAT == nil || cfg.HA.Enabled {
+			return nil
+		}
+		pool := cfg.CGNAT.Pools[u.Pool]
+		if pool == nil {
+			return nil
+		}
+		for _, addr := range pool.OutsideAddresses {
+			if _, _, err := net.ParseCIDR(addr); err != nil {
+				continue
+			}
+			networks = append(networks, poolNetwork{prefix: addr, routePolicy: pool.NetworkRoutePolicy})
+		}
+	}
+	return networks
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeRouting struct{ calls []string }
+
+func (r *fakeRouting) AdvertiseBGPNetworkPolicy(asn uint32, vrf, prefix, routePolicy string, ipv6 bool) error {
+	r.calls = append(r.calls, fmt.Sprintf("advertise %d %s %s %s", asn, vrf, prefix, routePolicy))
+	return nil
+}
+
+func (r *fakeRouting) WithdrawBGPNetwork(asn uint32, vrf, prefix string, ipv6 bool) error {
+	r.calls = append(r.calls, fmt.Sprintf("withdraw %d %s %s", asn, vrf, prefix))
+	return nil
+}
+
+func poolTestConfig() *config.Config {
+	return &config.Config{
+		Protocols: protocols.ProtocolConfig{BGP: &protocols.BGPConfig{ASN: 65000}},
+		IPv4Profiles: map[string]*ip.IPv4Profile{
+			"res": {Pools: []ip.IPv4Pool{
+				{Name: "a", Network: "10.0.0.0/22", Thresholds: &ip.PoolThresholds{WithdrawRoute: true}},
+				{Name: "b", Network: "10.0.4.0/22", VRF: "blue"},
+			}},
+		},
+		SubscriberGroups: &subscriber.SubscriberGroupsConfig{Groups: map[string]*subscriber.SubscriberGroup{
+			"g1": {IPv4Profile: "res", BGP: &subscriber.SubscriberBGP{Enabled: true, AdvertisePools: true, NetworkRoutePolicy: "POOLS-OUT"}},
+			"g2": {IPv4Profile: "res", VRF: "blue", BGP: &subscriber.SubscriberBGP{Enabled: true, AdvertisePools: true}},
+		}},
+		CGNAT: &cgnat.Config{Pools: map[string]*cgnat.Pool{
+			"cg": {OutsideAddresses: []string{"198.51.100.0/28", "203.0.113.7"}, NetworkRoutePolicy: "CGN-OUT"},
+		}},
+	}
+}
+
+func TestPoolNetworks(t *testing.T) {
+	cfg := poolTestConfig()
+
+	got := poolNetworks(cfg, allocator.PoolUsage{Family: allocator.PoolFamilyIPv4, Profile: "res", Pool: "a"})
+	if len(got) != 1 || got[0] != (poolNetwork{prefix: "10.0.0.0/22", routePolicy: "POOLS-OUT"}) {
+		t.Fatalf("ipv4 pool a = %+v", got)
+	}
+	got = poolNetworks(cfg, allocator.PoolUsage{Family: allocator.PoolFamilyIPv4, Profile: "res", Pool: "b"})
+	if len(got) != 1 || got[0].vrf != "blue" {
+		t.Fatalf("ipv4 pool b = %+v", got)
+	}
+
+	cg := allocator.PoolUsage{Family: allocator.PoolFamilyCGNAT, Pool: "cg"}
+	got = poolNetworks(cfg, cg)
+	if len(got) != 1 || got[0] != (poolNetwork{prefix: "198.51.100.0/28", routePolicy: "CGN-OUT"}) {
+		t.Fatalf("cgnat pool = %+v", got)
+	}
+	cfg.HA.Enabled = true
+	if got := poolNetworks(cfg, cg); len(got) != 0 {
+		t.Fatalf("cgnat pool under HA = %+v", got)
+	}
+}
+
+func TestPoolLevelChanged_PublishesAndWithdraws(t *testing.T) {
+	bus := local.NewBus()
+	ch := make(chan *events.PoolThresholdEvent, 4)
+	bus.Subscribe(events.TopicPoolThreshold, func(ev events.Event) {
+		ch <- ev.Data.(*events.PoolThresholdEvent)
+	})
+	routing := &fakeRouting{}
+	c := New(Config{EventBus: bus, ConfigManager: &fakeCfg{cfg: poolTestConfig()}, Routing: routing})
+	th := &ip.PoolThresholds{WithdrawRoute: true}
+	usage := allocator.PoolUsage{Family: allocator.PoolFamilyIPv4, Profile: "res", Pool: "a", Size: 4, Available: 1}
+
+	usage.Level = allocator.PoolLevelHigh
+	c.poolLevelChanged(allocator.PoolLevelChange{Usage: usage, Previous: allocator.PoolLevelNormal, Thresholds: th})
+	select {
+	case ev := <-ch:
+		if ev.Level != "high" || ev.PreviousLevel != "normal" || ev.Pool != "a" || ev.Utilization != 0.75 {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no threshold event")
+	}
+	if len(routing.calls) != 0 {
+		t.Fatalf("routing touched at high: %v", routing.calls)
+	}
+
+	// Exhaustion leaves the route alone: subscribers still hold
+	// addresses from the pool.
+	usage.Level, usage.Available = allocator.PoolLevelExhausted, 0
+	c.poolLevelChanged(allocator.PoolLevelChange{Usage: usage, Previous: allocator.PoolLevelHigh, Thresholds: th})
+	<-ch
+	if len(routing.calls) != 0 {
+		t.Fatalf("routing touched when exhausted: %v", routing.calls)
+	}
+
+	usage.Level, usage.Available = allocator.PoolLevelNormal, 4
+	c.poolLevelChanged(allocator.PoolLevelChange{Usage: usage, Previous: allocator.PoolLevelExhausted, Thresholds: th})
+	<-ch
+	usage.Available = 3
+	c.poolLevelChanged(allocator.PoolLevelChange{Usage: usage, Previous: allocator.PoolLevelNormal, WasEmpty: true, Thresholds: th})
+	want := []string{"withdraw 65000  10.0.0.0/22", "advertise 65000  10.0.0.0/22 POOLS-OUT"}
+	if fmt.Sprint(routing.calls) != fmt.Sprint(want) {
+		t.Fatalf("routing calls = %q, want %q", routing.calls, want)
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("threshold event without a level change: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	routing.calls = nil
+	usage.Available = 4
+	c.poolLevelChanged(allocator.PoolLevelChange{Usage: usage, Previous: allocator.PoolLevelNormal})
+	if len(routing.calls) != 0 {
+		t.Fatalf("routing touched without withdraw-route: %v", routing.calls)
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package nptv6 programs per-session stateless IPv6 prefix translation
+// (NPTv6, RFC 6296). A subscriber's internal prefix, from the service
+// group or an AAA attribute, is translated to its delegated prefix for
+// as long as the session holds the prefix. The dataplane translates on
+// the uplink of the subscriber's VRF, rewriting the source of packets
+// leaving it and the destination of packets arriving on it, so the
+// internal prefix is also routed to the session interface. An uplink
+// carries one translation.
+package nptv6
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// Component owns the NPTv6 bindings of the running sessions. Bindings
+// are not persisted: restored sessions are replayed on
+// TopicSessionRestored and re-bound, which the dataplane accepts when
+// the binding survived.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+
+	mu       sync.Mutex
+	sessions map[string]*sessionInfo
+	bindings map[string]*models.NPTv6Binding
+	// uplinks maps a bound uplink to the session translated on it.
+	uplinks map[uint32]string
+
+	subs []events.Subscription
+}
+
+// sessionInfo is what the component has learnt about a session. No
+// single event carries all of it: PPPoE announces the session interface
+// only once programmed, and the AAA attributes arrive on the response.
+type sessionInfo struct {
+	swIfIndex    uint32
+	prefix       string
+	nextHop      net.IP
+	vrf          string
+	serviceGroup string
+	username     string
+	srgName      string
+	// override is the AAA internal prefix, if one was returned.
+	override string
+}
+
+// InterfaceResolver resolves interface names to dataplane indexes.
+type InterfaceResolver interface {
+	GetSwIfIndex(name string) (uint32, bool)
+}
+
+// Config wires the component. Without an EventBus nothing is bound.
+type Config struct {
+	EventBus      events.Bus
+	ConfigManager component.ConfigManager
+	IfMgr         InterfaceResolver
+	Southbound    southbound.NPTv6
+}
+
+func New(cfg Config) *Component {
+	return &Component{
+		Base:     component.NewBase("nptv6"),
+		logger:   logger.Get("nptv6"),
+		cfg:      cfg,
+		sessions: make(map[string]*sessionInfo),
+		bindings: make(map[string]*models.NPTv6Binding),
+		uplinks:  make(map[uint32]string),
+	}
+}
+
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting NPTv6 component")
+	if c.cfg.EventBus == nil {
+		return nil
+	}
+	for _, topic := range []string{events.TopicAAAResponseIPoE, events.TopicAAAResponsePPPoE, events.TopicAAAResponseL2TP} {
+		c.subs = append(c.subs, c.cfg.EventBus.Subscribe(topic, c.handleAAAResponse))
+	}
+	c.subs = append(c.subs,
+		c.cfg.EventBus.Subscribe(events.TopicSessionLifecycle, c.handleSessionLifecycle),
+		c.cfg.EventBus.Subscribe(events.TopicSessionProgrammed, c.handleSessionProgrammed),
+		c.cfg.EventBus.Subscribe(events.TopicSessionRestored, c.handleSessionRestored),
+	)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping NPTv6 component")
+	for _, sub := range c.subs {
+		sub.Unsubscribe()
+	}
+	c.StopContext()
+	return nil
+}
+
+// Bindings returns the programmed translations sorted by session ID.
+func (c *Component) Bindings() []models.NPTv6Binding {
+	c.mu.Lock()
+	out := make([]models.NPTv6Binding, 0, len(c.bindings))
+	for _, b := range c.bindings {
+		out = append(out, *b)
+	}
+	c.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].SessionID < out[j].SessionID })
+	return out
+}
+
+func (c *Component) handleAAAResponse(event events.Event) {
+	data, ok := event.Data.(*events.AAAResponseEvent)
+	if !ok || !data.Response.Allowed {
+		return
+	}
+	prefix, _ := data.Response.Attributes[aaa.AttrNPTv6InternalPrefix].(string)
+	if prefix == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.infoLocked(data.SessionID).override = prefix
+	c.bindLocked(data.SessionID)
+}
+
+func (c *Component) handleSessionLifecycle(event events.Event) {
+	data, ok := event.Data.(*events.SessionLifecycleEvent)
+	if !ok {
+		return
+	}
+	sess, ok := data.Session.(models.SubscriberSession)
+	if !ok {
+		return
+	}
+
+	switch data.State {
+	case models.SessionStateActive:
+		// A PPPoE session goes active before its interface exists; the
+		// index it carries is the punt interface.
+		c.update(data.SessionID, sess, data.AccessType != models.AccessTypePPPoE)
+	case models.SessionStateReleased:
+		c.release(data.SessionID)
+	}
+}
+
+func (c *Component) handleSessionProgrammed(event events.Event) {
+	data, ok := event.Data.(*events.SessionLifecycleEvent)
+	if !ok {
+		return
+	}
+	if sess, ok := data.Session.(models.SubscriberSession); ok {
+		c.update(data.SessionID, sess, true)
+	}
+}
+
+func (c *Component) handleSessionRestored(event events.Event) {
+	data, ok := event.Data.(*events.SessionRestoredEvent)
+	if !ok || data.Session == nil {
+		return
+	}
+	c.update(data.SessionID, data.Session, true)
+}
+
+// update merges what an event says about a session, then binds it if
+// it now has everything a translation needs.
+func (c *Component) update(sessionID string, sess models.SubscriberSession, withIfIndex bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info := c.infoLocked(sessionID)
+	if idx := sess.GetIfIndex(); withIfIndex && idx != 0 {
+		info.swIfIndex = idx
+	}
+	if p := sess.GetIPv6Prefix(); p != "" {
+		info.prefix = p
+	}
+	if a := sess.GetIPv6Address(); a != nil {
+		info.nextHop = a
+	}
+	if vrf := sessionVRF(sess); vrf != "" {
+		info.vrf = vrf
+	}
+	if sg := sess.GetServiceGroup(); sg != "" {
+		info.serviceGroup = sg
+	}
+	if u := sess.GetUsername(); u != "" {
+		info.username = u
+	}
+	if srg := sess.GetSRGName(); srg != "" {
+		info.srgName = srg
+	}
+	if p := sessionAttributes(sess)[aaa.AttrNPTv6InternalPrefix]; p != "" {
+		info.override = p
+	}
+	c.bindLocked(sessionID)
+}
+
+func (c *Component) infoLocked(sessionID string) *sessionInfo {
+	info, ok := c.sessions[sessionID]
+	if !ok {
+		info = &sessionInfo{}
+		c.sessions[sessionID] = info
+	}
+	return info
+}
+
+// bindLocked binds the session's internal prefix to its delegated
+// prefix on its uplink and routes the internal prefix to the session. A
+// session already bound the same way is left alone; one whose prefixes
+// or interfaces changed is unbound first. Caller holds mu.
+func (c *Component) bindLocked(sessionID string) {
+	info := c.sessions[sessionID]
+	internalStr, source := c.internalPrefix(info)
+	if internalStr == "" || info.prefix == "" || info.swIfIndex == 0 {
+		return
+	}
+	internal, external, err := parsePrefixes(internalStr, info.prefix)
+	if err != nil {
+		c.logger.Warn("Session left without NPTv6 translation", "session_id", sessionID, "error", err)
+		return
+	}
+	vrf := c.sessionVRF(info)
+	uplink, uplinkIdx, err := c.uplink(info, vrf)
+	if err != nil {
+		c.logger.Warn("Session left without NPTv6 translation", "session_id", sessionID, "error", err)
+		return
+	}
+
+	if old, ok := c.bindings[sessionID]; ok {
+		if old.SwIfIndex == uplinkIdx && old.SessionSwIfIndex == info.swIfIndex &&
+			old.Internal == internal.String() && old.External == external.String() {
+			return
+		}
+		c.unbindLocked(old, info.srgName)
+	}
+	if owner, ok := c.uplinks[uplinkIdx]; ok && owner != sessionID {
+		c.logger.Warn("Session left without NPTv6 translation", "session_id", sessionID,
+			"error", fmt.Sprintf("uplink %s already translates session %s", uplink, owner))
+		return
+	}
+	if owner := c.internalOwnerLocked(vrf, internal.String()); owner != "" && owner != sessionID {
+		c.logger.Warn("Session left without NPTv6 translation", "session_id", sessionID,
+			"error", fmt.Sprintf("internal prefix %s is already routed to session %s in vrf %q", internal, owner, vrf))
+		return
+	}
+
+	binding := &models.NPTv6Binding{
+		SessionID:        sessionID,
+		Username:         info.username,
+		ServiceGroup:     info.serviceGroup,
+		VRF:              vrf,
+		Uplink:           uplink,
+		SwIfIndex:        uplinkIdx,
+		SessionSwIfIndex: info.swIfIndex,
+		Internal:         internal.String(),
+		External:         external.String(),
+		Source:           source,
+		CreatedAt:        time.Now(),
+	}
+	if err := c.cfg.Southbound.NPTv6RouteAddDel(*internal, vrf, info.swIfIndex, info.nextHop, true); err != nil {
+		c.logger.Error("Failed to route NPTv6 internal prefix", "session_id", sessionID,
+			"internal", binding.Internal, "vrf", vrf, "error", err)
+		return
+	}
+	if err := c.cfg.Southbound.NPTv6BindingAddDel(uplinkIdx, *internal, *external, true); err != nil {
+		c.logger.Error("Failed to program NPTv6 binding", "session_id", sessionID,
+			"internal", binding.Internal, "external", binding.External, "uplink", uplink, "error", err)
+		c.cfg.Southbound.NPTv6RouteAddDel(*internal, vrf, info.swIfIndex, nil, false)
+		return
+	}
+	c.bindings[sessionID] = binding
+	c.uplinks[uplinkIdx] = sessionID
+	c.publish(info.srgName, binding, true)
+	c.logger.Debug("NPTv6 binding programmed", "session_id", sessionID, "uplink", uplink,
+		"sw_if_index", binding.SwIfIndex, "internal", binding.Internal, "external", binding.External, "source", source)
+}
+
+// internalOwnerLocked returns the session the internal prefix is routed
+// to in the VRF, if any. Caller holds mu.
+func (c *Component) internalOwnerLocked(vrf, internal string) string {
+	for _, b := range c.bindings {
+		if b.VRF == vrf && b.Internal == internal {
+			return b.SessionID
+		}
+	}
+	return ""
+}
+
+// release forgets the session and removes its translation, if it has
+// one.
+func (c *Component) release(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info := c.sessions[sessionID]
+	delete(c.sessions, sessionID)
+	if b, ok := c.bindings[sessionID]; ok {
+		var srgName string
+		if info != nil {
+			srgName = info.srgName
+		}
+		c.unbindLocked(b, srgName)
+	}
+}
+
+// unbindLocked removes a binding from the dataplane and the table.
+// Caller holds mu.
+func (c *Component) unbindLocked(b *models.NPTv6Binding, srgName string) {
+	delete(c.bindings, b.SessionID)
+	if c.uplinks[b.SwIfIndex] == b.SessionID {
+		delete(c.uplinks, b.SwIfIndex)
+	}
+	_, internal, _ := net.ParseCIDR(b.Internal)
+	_, external, _ := net.ParseCIDR(b.External)
+	if err := c.cfg.Southbound.NPTv6BindingAddDel(b.SwIfIndex, *internal, *external, false); err != nil {
+		c.logger.Warn("Failed to remove NPTv6 binding", "session_id", b.SessionID, "uplink", b.Uplink, "error", err)
+	}
+	if err := c.cfg.Southbound.NPTv6RouteAddDel(*internal, b.VRF, b.SessionSwIfIndex, nil, false); err != nil {
+		// The session interface is usually deleted alongside, taking
+		// the route with it.
+		c.logger.Debug("Failed to remove NPTv6 internal route", "session_id", b.SessionID, "error", err)
+	}
+	c.publish(srgName, b, false)
+	c.logger.Debug("NPTv6 binding removed", "session_id", b.SessionID, "external", b.External)
+}
+
+// internalPrefix returns the internal prefix for a session and where it
+// came from: the AAA attribute wins over the service group.
+func (c *Component) internalPrefix(info *sessionInfo) (string, string) {
+	if info.override != "" {
+		return info.override, models.NPTv6SourceAAA
+	}
+	sg := c.serviceGroup(info)
+	if sg == nil || sg.NPTv6 == nil {
+		return "", ""
+	}
+	return sg.NPTv6.InternalPrefix, models.NPTv6SourceServiceGroup
+}
+
+// sessionVRF is the VRF the session routes in: the session's own, or
+// its service group's.
+func (c *Component) sessionVRF(info *sessionInfo) string {
+	if info.vrf != "" {
+		return info.vrf
+	}
+	if sg := c.serviceGroup(info); sg != nil {
+		return sg.VRF
+	}
+	return ""
+}
+
+// uplink returns the interface the session's translation is bound on:
+// the service group's nptv6 uplink, else the only uplink of the VRF.
+func (c *Component) uplink(info *sessionInfo, vrf string) (string, uint32, error) {
+	var name string
+	if sg := c.serviceGroup(info); sg != nil && sg.NPTv6 != nil && sg.NPTv6.Uplink != "" {
+		name = sg.NPTv6.Uplink
+	} else {
+		if c.cfg.ConfigManager == nil {
+			return "", 0, fmt.Errorf("no config to find the uplink of vrf %q", vrf)
+		}
+		cfg, err := c.cfg.ConfigManager.GetRunning()
+		if err != nil || cfg == nil {
+			return "", 0, fmt.Errorf("no running config to find the uplink of vrf %q", vrf)
+		}
+		uplinks := cfg.NPTv6Uplinks(vrf)
+		if len(uplinks) != 1 {
+			return "", 0, fmt.Errorf("vrf %q has %d candidate uplinks %v; set nptv6.uplink on the service group", vrf, len(uplinks), uplinks)
+		}
+		name = uplinks[0]
+	}
+	if c.cfg.IfMgr == nil {
+		return "", 0, fmt.Errorf("no interface resolver for uplink %s", name)
+	}
+	idx, ok := c.cfg.IfMgr.GetSwIfIndex(name)
+	if !ok {
+		return "", 0, fmt.Errorf("uplink %s is not in the dataplane", name)
+	}
+	return name, idx, nil
+}
+
+func (c *Component) serviceGroup(info *sessionInfo) *servicegroup.Config {
+	if c.cfg.ConfigManager == nil || info.serviceGroup == "" {
+		return nil
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.ServiceGroups[info.serviceGroup]
+}
+
+func (c *Component) publish(srgName string, b *models.NPTv6Binding, isAdd bool) {
+	ev := *b
+	c.cfg.EventBus.Publish(events.TopicNPTv6Binding, events.Event{
+		Source: c.Name(),
+		Data: &events.NPTv6BindingEvent{
+			SRGName:   srgName,
+			SessionID: b.SessionID,
+			Binding:   &ev,
+			IsAdd:     isAdd,
+		},
+	})
+}
+
+// sessionVRF returns the VRF a session payload carries, if any.
+func sessionVRF(sess models.SubscriberSession) string {
+	switch s := sess.(type) {
+	case *models.IPoESession:
+		return s.VRF
+	case *models.PPPSession:
+		return s.VRF
+	}
+	return ""
+}
+
+// sessionAttributes returns the AAA attributes a session payload
+// carries. Only restored sessions are replayed with them.
+func sessionAttributes(sess models.SubscriberSession) map[string]string {
+	switch s := sess.(type) {
+	case *models.IPoESession:
+		return s.Attributes
+	case *models.PPPSession:
+		return s.Attributes
+	case *models.PPPoL2TPSession:
+		return s.Attributes
+	}
+	return nil
+}
+
+// parsePrefixes parses both sides of a translation. RFC 6296 needs
+// them the same length.
+func parsePrefixes(internal, external string) (*net.IPNet, *net.IPNet, error) {
+	ip, in, err := net.ParseCIDR(internal)
+	if err != nil || ip.To4() != nil {
+		return nil, nil, fmt.Errorf("internal prefix %q is not an IPv6 prefix", internal)
+	}
+	ip, ex, err := net.ParseCIDR(external)
+	if err != nil || ip.To4() != nil {
+		return nil, nil, fmt.Errorf("delegated prefix %q is not an IPv6 prefix", external)
+	}
+	inLen, _ := in.Mask.Size()
+	exLen, _ := ex.Mask.Size()
+	if inLen != exLen {
+		return nil, nil, fmt.Errorf("internal prefix %s and delegated prefix %s differ in length", in, ex)
+	}
+	return in, ex, nil
+}
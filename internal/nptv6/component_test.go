@@ -0,0 +1,280 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package nptv6
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeSB struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeSB) NPTv6BindingAddDel(swIfIndex uint32, internal, external net.IPNet, isAdd bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op := "del"
+	if isAdd {
+		op = "add"
+	}
+	f.calls = append(f.calls, fmt.Sprintf("%s %d %s %s", op, swIfIndex, internal.String(), external.String()))
+	return nil
+}
+
+func (f *fakeSB) NPTv6RouteAddDel(internal net.IPNet, vrf string, swIfIndex uint32, nextHop net.IP, isAdd bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op := "del"
+	if isAdd {
+		op = "add"
+	}
+	f.calls = append(f.calls, fmt.Sprintf("route %s %s vrf=%s via %d", op, internal.String(), vrf, swIfIndex))
+	return nil
+}
+
+func (f *fakeSB) got() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+type fakeIfMgr map[string]uint32
+
+func (f fakeIfMgr) GetSwIfIndex(name string) (uint32, bool) {
+	idx, ok := f[name]
+	return idx, ok
+}
+
+func v6Address(prefix string) *interfaces.AddressConfig {
+	return &interfaces.AddressConfig{IPv6: []string{prefix}}
+}
+
+func newTestComponent(t *testing.T) (*Component, *fakeSB, chan *events.NPTv6BindingEvent) {
+	t.Helper()
+	bus := local.NewBus()
+	evCh := make(chan *events.NPTv6BindingEvent, 8)
+	bus.Subscribe(events.TopicNPTv6Binding, func(ev events.Event) {
+		evCh <- ev.Data.(*events.NPTv6BindingEvent)
+	})
+	sb := &fakeSB{}
+	cfg := &config.Config{
+		Interfaces: map[string]*interfaces.InterfaceConfig{
+			"loop0": {Name: "loop0", Address: v6Address("2001:db8:ffff::1/128")},
+			"eth1":  {Name: "eth1", Address: v6Address("2001:db8:0:1::1/64")},
+			"eth2": {Name: "eth2", Subinterfaces: interfaces.SubinterfaceMap{
+				"100": {ID: 100, VLAN: 100, VRF: "cust", Address: v6Address("2001:db8:0:100::1/64")},
+				"200": {ID: 200, VLAN: 200, VRF: "cust", Address: v6Address("2001:db8:0:200::1/64")},
+				"300": {ID: 300, VLAN: 300, SubscriberAccess: true, Address: v6Address("2001:db8:0:300::1/64")},
+			}},
+		},
+		ServiceGroups: map[string]*servicegroup.Config{
+			"biz":    {NPTv6: &servicegroup.NPTv6Config{InternalPrefix: "fd00:42::/56"}},
+			"pinned": {VRF: "cust", NPTv6: &servicegroup.NPTv6Config{InternalPrefix: "fd00:43::/56", Uplink: "eth2.200"}},
+			"cust":   {VRF: "cust", NPTv6: &servicegroup.NPTv6Config{InternalPrefix: "fd00:44::/56"}},
+			"alt":    {VRF: "cust", NPTv6: &servicegroup.NPTv6Config{InternalPrefix: "fd00:43::/56", Uplink: "eth2.100"}},
+		},
+	}
+	ifMgr := fakeIfMgr{"loop0": 1, "eth1": 2, "eth2.100": 3, "eth2.200": 4, "eth2.300": 5}
+	c := New(Config{EventBus: bus, ConfigManager: &fakeCfg{cfg: cfg}, IfMgr: ifMgr, Southbound: sb})
+	return c, sb, evCh
+}
+
+func lifecycle(state models.SessionState, sess models.SubscriberSession) events.Event {
+	return events.Event{Data: &events.SessionLifecycleEvent{
+		AccessType: sess.GetAccessType(),
+		Protocol:   sess.GetProtocol(),
+		SessionID:  sess.GetSessionID(),
+		State:      state,
+		Session:    sess,
+	}}
+}
+
+func nextEvent(t *testing.T, ch chan *events.NPTv6BindingEvent) *events.NPTv6BindingEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no NPTv6 binding event")
+		return nil
+	}
+}
+
+func TestServiceGroupBinding(t *testing.T) {
+	c, sb, evCh := newTestComponent(t)
+
+	sess := &models.IPoESession{
+		SessionID:    "s1",
+		AccessType:   string(models.AccessTypeIPoE),
+		Protocol:     string(models.ProtocolDHCPv6),
+		IfIndex:      7,
+		ServiceGroup: "biz",
+		IPv6Prefix:   "2001:db8:100:4200::/56",
+	}
+	c.handleSessionLifecycle(lifecycle(models.SessionStateActive, sess))
+	// A repeated announcement of the same prefix changes nothing.
+	c.handleSessionLifecycle(lifecycle(models.SessionStateActive, sess))
+
+	want := []string{"route add fd00:42::/56 vrf= via 7", "add 2 fd00:42::/56 2001:db8:100:4200::/56"}
+	if got := sb.got(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("dataplane calls = %v, want %v", got, want)
+	}
+	ev := nextEvent(t, evCh)
+	if !ev.IsAdd || ev.Binding.Source != models.NPTv6SourceServiceGroup {
+		t.Fatalf("event = %+v", ev)
+	}
+	if b := c.Bindings(); len(b) != 1 || b[0].External != "2001:db8:100:4200::/56" || b[0].Uplink != "eth1" || b[0].SessionSwIfIndex != 7 {
+		t.Fatalf("bindings = %+v", b)
+	}
+
+	c.handleSessionLifecycle(lifecycle(models.SessionStateReleased, &models.IPoESession{
+		SessionID:  "s1",
+		AccessType: string(models.AccessTypeIPoE),
+		Protocol:   string(models.ProtocolDHCPv6),
+	}))
+	want = append(want, "del 2 fd00:42::/56 2001:db8:100:4200::/56", "route del fd00:42::/56 vrf= via 7")
+	if got := sb.got(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("dataplane calls = %v, want %v", got, want)
+	}
+	if ev := nextEvent(t, evCh); ev.IsAdd {
+		t.Fatalf("release event = %+v", ev)
+	}
+	if len(c.Bindings()) != 0 || len(c.sessions) != 0 {
+		t.Fatal("session not forgotten")
+	}
+}
+
+func TestPPPoEWaitsForProgrammedInterface(t *testing.T) {
+	c, sb, _ := newTestComponent(t)
+
+	// The AAA override applies to a session whose group has no nptv6.
+	c.handleAAAResponse(events.Event{Data: &events.AAAResponseEvent{
+		AccessType: models.AccessTypePPPoE,
+		SessionID:  "p1",
+		Response: models.AAAResponse{
+			Allowed:    true,
+			Attributes: map[string]interface{}{aaa.AttrNPTv6InternalPrefix: "2001:db8:ffff::/48"},
+		},
+	}})
+
+	sess := &models.PPPSession{
+		SessionID:  "p1",
+		AccessType: string(models.AccessTypePPPoE),
+		Protocol:   string(models.ProtocolPPPoESession),
+		IfIndex:    1, // the punt interface
+		IPv6Prefix: "2001:db8:200::/48",
+	}
+	c.handleSessionLifecycle(lifecycle(models.SessionStateActive, sess))
+	if got := sb.got(); len(got) != 0 {
+		t.Fatalf("bound before the session interface exists: %v", got)
+	}
+
+	programmed := *sess
+	programmed.IfIndex = 12
+	c.handleSessionProgrammed(lifecycle(models.SessionStateActive, &programmed))
+	want := []string{"route add 2001:db8:ffff::/48 vrf= via 12", "add 2 2001:db8:ffff::/48 2001:db8:200::/48"}
+	if got := sb.got(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("dataplane calls = %v, want %v", got, want)
+	}
+	if b := c.Bindings(); b[0].Source != models.NPTv6SourceAAA {
+		t.Fatalf("source = %q", b[0].Source)
+	}
+
+	// A new delegated prefix moves the translation.
+	programmed.IPv6Prefix = "2001:db8:300::/48"
+	c.handleSessionProgrammed(lifecycle(models.SessionStateActive, &programmed))
+	want = append(want,
+		"del 2 2001:db8:ffff::/48 2001:db8:200::/48", "route del 2001:db8:ffff::/48 vrf= via 12",
+		"route add 2001:db8:ffff::/48 vrf= via 12", "add 2 2001:db8:ffff::/48 2001:db8:300::/48")
+	if got := sb.got(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("dataplane calls = %v, want %v", got, want)
+	}
+}
+
+func TestPrefixLengthMismatch(t *testing.T) {
+	c, sb, _ := newTestComponent(t)
+
+	c.handleSessionRestored(events.Event{Data: &events.SessionRestoredEvent{
+		SessionID: "s1",
+		Session: &models.IPoESession{
+			SessionID:    "s1",
+			IfIndex:      7,
+			ServiceGroup: "biz",
+			IPv6Prefix:   "2001:db8:100::/48",
+		},
+	}})
+	if got := sb.got(); len(got) != 0 {
+		t.Fatalf("bound a /56 to a /48: %v", got)
+	}
+}
+
+func TestBindsOnUplinkOfSessionVRF(t *testing.T) {
+	c, sb, _ := newTestComponent(t)
+	active := func(id, group, prefix string, ifIndex uint32) {
+		c.handleSessionLifecycle(lifecycle(models.SessionStateActive, &models.IPoESession{
+			SessionID:    id,
+			AccessType:   string(models.AccessTypeIPoE),
+			Protocol:     string(models.ProtocolDHCPv6),
+			IfIndex:      ifIndex,
+			ServiceGroup: group,
+			IPv6Prefix:   prefix,
+		}))
+	}
+
+	// The group names its uplink; the session interface only gets the
+	// route to the internal prefix.
+	active("s1", "pinned", "2001:db8:100:4300::/56", 7)
+	want := []string{"route add fd00:43::/56 vrf=cust via 7", "add 4 fd00:43::/56 2001:db8:100:4300::/56"}
+	if got := sb.got(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("dataplane calls = %v, want %v", got, want)
+	}
+	if b := c.Bindings(); len(b) != 1 || b[0].Uplink != "eth2.200" || b[0].SwIfIndex != 4 || b[0].VRF != "cust" {
+		t.Fatalf("bindings = %+v", b)
+	}
+
+	// An uplink carries one translation.
+	active("s2", "pinned", "2001:db8:100:4400::/56", 8)
+	// The cust VRF has two uplinks, so a group without one is refused.
+	active("s3", "cust", "2001:db8:100:4500::/56", 9)
+	if got := sb.got(); len(got) != len(want) {
+		t.Fatalf("dataplane calls = %v, want only %v", got, want)
+	}
+
+	// Another group of the VRF on the free uplink with the same internal
+	// prefix would route it twice.
+	active("s4", "alt", "2001:db8:100:4600::/56", 10)
+	if got := sb.got(); len(got) != len(want) {
+		t.Fatalf("dataplane calls = %v, want only %v", got, want)
+	}
+
+	// The uplink frees up with its session.
+	c.handleSessionLifecycle(lifecycle(models.SessionStateReleased, &models.IPoESession{SessionID: "s1"}))
+	active("s2", "pinned", "2001:db8:100:4400::/56", 8)
+	if got := sb.got(); got[len(got)-1] != "add 4 fd00:43::/56 2001:db8:100:4400::/56" {
+		t.Fatalf("dataplane calls = %v", got)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
 	pppdisp "github.com/veesix-networks/osvbng/internal/ppp"
 	"github.com/veesix-networks/osvbng/internal/ra"
+	"github.com/veesix-networks/osvbng/pkg/accessline"
 	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/cache"
 	"github.com/veesix-networks/osvbng/pkg/component"
@@ -148,6 +149,7 @@ type SessionState struct {
 	HostUniq       []byte
 	AgentCircuitID string
 	AgentRemoteID  string
+	AccessLine     accessline.Characteristics
 
 	IPv4Address net.IP
 	IPv6Address net.IP
@@ -638,6 +640,7 @@ func (c *Component) handlePADR(pkt *dataplane.ParsedPacket) error {
 		HostUniq:       tags.HostUniq,
 		AgentCircuitID: tags.AgentCircuitID,
 		AgentRemoteID:  tags.AgentRemoteID,
+		AccessLine:     tags.AccessLine,
 		Attributes:     make(map[string]string),
 		CreatedAt:      time.Now(),
 		LastSeen:       time.Now(),
@@ -1319,6 +1322,7 @@ func (c *Component) restoreSessionToCache(ctx context.Context, sess *SessionStat
 		AccessInterface:  c.accessInterfaceName(sess.EncapIfIndex),
 		VRF:              sess.VRF,
 		ServiceGroup:     sess.ServiceGroup.Name,
+		SteeringPolicy:   sess.ServiceGroup.SteeringPolicy,
 		SRGName:          sess.SRGName,
 		IPv4Address:      sess.IPv4Address,
 		IPv6Address:      sess.IPv6Address,
@@ -1673,6 +1677,7 @@ func (c *Component) ForEachSession(fn func(models.SubscriberSession) bool) {
 			AccessInterface:  c.accessInterfaceName(sess.EncapIfIndex),
 			VRF:              sess.VRF,
 			ServiceGroup:     sess.ServiceGroup.Name,
+			SteeringPolicy:   sess.ServiceGroup.SteeringPolicy,
 			SRGName:          sess.SRGName,
 			IPv4Address:      sess.IPv4Address,
 			IPv6Address:      sess.IPv6Address,
@@ -1771,6 +1776,7 @@ func (c *Component) buildModelSnapshot(sess *SessionState) *models.PPPSession {
 		IfIndex:          sess.SwIfIndex,
 		VRF:              sess.VRF,
 		ServiceGroup:     sess.ServiceGroup.Name,
+		SteeringPolicy:   sess.ServiceGroup.SteeringPolicy,
 		SRGName:          sess.SRGName,
 		IPv4Address:      sess.IPv4Address,
 		IPv6Address:      sess.IPv6Address,
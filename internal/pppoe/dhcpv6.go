@@ -209,6 +209,17 @@ func (c *Component) bindDHCPv6(s *SessionState, swIdx uint32, msg *dhcp6.Message
 
 	c.checkpointSession(s)
 	c.logger.Debug("PPPoE DHCPv6 bound", "session_id", s.SessionID, "ipv6", iana, "prefix", pd)
+
+	// A new IA-NA or delegated prefix re-announces the programmed session
+	// so consumers keyed on them (the DS-Lite B4, NPTv6) pick it up.
+	newAddr := iana != nil && !iana.Equal(oldAddr)
+	newPrefix := pd != nil && (oldPrefix == nil || pd.String() != oldPrefix.String())
+	if swIdx != 0 && (newAddr || newPrefix) {
+		s.mu.Lock()
+		snapshot := c.buildModelSnapshot(s)
+		s.mu.Unlock()
+		c.publishSessionProgrammed(snapshot)
+	}
 }
 
 // unbindDHCPv6 clears the IA-NA / IA-PD on a Release/Decline, frees the allocator
@@ -275,6 +286,13 @@ func (c *Component) resolveDHCPv6(ctx *allocator.Context) *dhcp.ResolvedDHCPv6 {
 	if profile == nil {
 		return nil
 	}
+	if cfg, err := c.cfgMgr.GetRunning(); err == nil {
+		ctx.AFTRName = cfg.ServiceGroupAFTRName(ctx.ServiceGroup)
+		ctx.MAP = cfg.ServiceGroupMAP(ctx.ServiceGroup)
+		if len(ctx.DNSv6) == 0 {
+			ctx.DNSv6 = cfg.ServiceGroupDNS64(ctx.ServiceGroup)
+		}
+	}
 	return dhcp.ResolveV6(ctx, profile)
 }
 
@@ -35,6 +35,10 @@ type LACBringUpAttrs struct {
 	// ~0 lets VPP resolve via FIB on the LNS peer IP.
 	EncapIfIndex uint32
 
+	// SRGName is the subscriber's redundancy group. The L2TP side
+	// replicates the LAC session to the standby under the same SRG.
+	SRGName string
+
 	// AAAAttrs is the subset of the AAA reply attribute bag the LAC
 	// needs to parse out tunnel candidates (tunnel.type,
 	// tunnel.server-endpoint, tunnel.password, tunnel.preference,
@@ -66,7 +70,9 @@ func (c *Component) SetLACTrigger(fn LACTrigger) { c.lacTrigger = fn }
 // the L2TP session interface. Used by setupSessionRestore to replay
 // SetPPPoESessionLACTunneled across L2TP component re-init without
 // persisting the volatile sw_if_index in the PPPoE checkpoint.
-type LACSessionIndexResolver func(localTunnelID, localSessionID uint16) (uint32, bool)
+// pppoeSwIfIndex is the restored PPPoE session's current sw_if_index,
+// which the L2TP side rebinds its LNS→subscriber direction to.
+type LACSessionIndexResolver func(localTunnelID, localSessionID uint16, pppoeSwIfIndex uint32) (uint32, bool)
 
 // SetLACResolver installs the L2TP-side sw_if_index resolver. Called
 // once from cmd-level wiring after the L2TP component is constructed.
@@ -348,6 +354,7 @@ func buildLACBringUpAttrs(s *SessionState) LACBringUpAttrs {
 		Username:       s.Username,
 		PPPoESwIfIndex: s.SwIfIndex,
 		EncapIfIndex:   s.EncapIfIndex,
+		SRGName:        s.SRGName,
 		AAAAttrs:       make(map[string]string, len(s.Attributes)),
 	}
 	for k, v := range s.Attributes {
@@ -210,6 +210,9 @@ func (s *SessionState) publishAAARequest(attrs map[string]string) {
 	if s.AgentRemoteID != "" {
 		attrs[aaa.AttrRemoteID] = s.AgentRemoteID
 	}
+	for k, v := range s.AccessLine.Attributes() {
+		attrs[k] = v
+	}
 
 	aaaPayload := &models.AAARequest{
 		RequestID:        requestID,
@@ -330,8 +333,17 @@ func (s *SessionState) onAuthResult(allowed bool, attributes map[string]interfac
 			}
 		}
 
+		// The line rates the access node reported shape the session like
+		// AAA attributes, and are kept with it so accounting reports
+		// them; AAA returning its own values overrides them.
+		for k, v := range s.AccessLine.Attributes() {
+			if _, ok := attributes[k]; !ok {
+				s.Attributes[k] = v
+			}
+		}
+
 		s.extractIPFromAttributes()
-		resolved := s.resolveServiceGroup(attributes)
+		resolved := s.resolveServiceGroup(s.AccessLine.ResolverAttributes(attributes))
 		s.VRF = resolved.VRF
 		s.ServiceGroup = resolved
 		s.SRGName = s.component.resolveSRGName(s.OuterVLAN, s.InnerVLAN)
@@ -730,6 +742,7 @@ func (s *SessionState) checkOpen() {
 				AccessIfIndex:    s.EncapIfIndex,
 				VRF:              s.VRF,
 				ServiceGroup:     s.ServiceGroup.Name,
+				SteeringPolicy:   s.ServiceGroup.SteeringPolicy,
 				SRGName:          s.SRGName,
 				IPv4Address:      s.IPv4Address,
 				IPv6Address:      s.IPv6Address,
@@ -826,6 +839,7 @@ func (s *SessionState) onVPPSessionCreated(swIfIndex uint32, err error) {
 		AccessIfIndex:    s.EncapIfIndex,
 		VRF:              s.VRF,
 		ServiceGroup:     s.ServiceGroup.Name,
+		SteeringPolicy:   s.ServiceGroup.SteeringPolicy,
 		SRGName:          s.SRGName,
 		IPv4Address:      s.IPv4Address,
 		IPv6Address:      s.IPv6Address,
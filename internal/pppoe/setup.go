@@ -250,6 +250,7 @@ func (c *Component) setupSessionRestore(ctx context.Context, sess *SessionState)
 		AccessIfIndex:    sess.EncapIfIndex,
 		VRF:              sess.VRF,
 		ServiceGroup:     sess.ServiceGroup.Name,
+		SteeringPolicy:   sess.ServiceGroup.SteeringPolicy,
 		SRGName:          sess.SRGName,
 		IPv4Address:      sess.IPv4Address,
 		IPv6Address:      sess.IPv6Address,
@@ -475,7 +476,7 @@ func (c *Component) setupSessionRestoreLAC(ctx context.Context, sess *SessionSta
 		return nil
 	}
 
-	lacIdx, ok := c.lacResolver(binding.LocalTunnelID, binding.LocalSessionID)
+	lacIdx, ok := c.lacResolver(binding.LocalTunnelID, binding.LocalSessionID, swIfIndex)
 	if !ok {
 		c.logger.Error("LAC session sw_if_index not resolvable; tunnel likely not restored yet",
 			"session_id", sess.SessionID,
@@ -24,6 +24,10 @@ import (
 	"github.com/veesix-networks/osvbng/pkg/southbound"
 )
 
+// icmpv6OptRDNSS is the Recursive DNS Server option type (RFC 8106), which
+// gopacket has no constant for.
+const icmpv6OptRDNSS layers.ICMPv6Opt = 25
+
 // PrefixInfo is one Prefix Information Option to advertise.
 type PrefixInfo struct {
 	Network       string
@@ -88,6 +92,10 @@ func ResolveGroupRA(cfg *config.Config, group *subscriber.SubscriberGroup) (sout
 		}
 	}
 
+	if group != nil {
+		raConfig.RDNSS = cfg.ServiceGroupDNS64(group.DefaultServiceGroup)
+	}
+
 	onLink := cfg.DHCPv6.RA.GetOnLink()
 	if group != nil && group.IPv6 != nil && group.IPv6.RA != nil && group.IPv6.RA.OnLink != nil {
 		onLink = *group.IPv6.RA.OnLink
@@ -212,6 +220,23 @@ func BuildRARawData(raConfig southbound.IPv6RAConfig, prefixes []PrefixInfo, src
 		})
 	}
 
+	if len(raConfig.RDNSS) > 0 {
+		// RFC 8106 §5.1: lifetime of at least 3 * MaxRtrAdvInterval.
+		lifetime := 3 * raConfig.MaxInterval
+		if lifetime == 0 {
+			lifetime = 1800
+		}
+		rdnssData := make([]byte, 6, 6+16*len(raConfig.RDNSS))
+		binary.BigEndian.PutUint32(rdnssData[2:6], lifetime)
+		for _, ip := range raConfig.RDNSS {
+			rdnssData = append(rdnssData, ip.To16()...)
+		}
+		raOptions = append(raOptions, layers.ICMPv6Option{
+			Type: icmpv6OptRDNSS,
+			Data: rdnssData,
+		})
+	}
+
 	routerLifetime := raConfig.RouterLifetime
 	if routerLifetime > 9000 {
 		routerLifetime = 9000 // RFC 4861 §4.2 maximum router lifetime
@@ -6,6 +6,7 @@ package ra
 
 import (
 	"bytes"
+	"encoding/binary"
 	"net"
 	"testing"
 
@@ -71,3 +72,25 @@ func TestRefreshIntervalClamps(t *testing.T) {
 		t.Fatalf("refresh = %vs, want 300 (lifetime/3)", got)
 	}
 }
+
+func TestBuildRAIncludesRDNSS(t *testing.T) {
+	dns := net.ParseIP("2001:db8:53::1")
+	raConfig := southbound.IPv6RAConfig{RouterLifetime: 1800, MaxInterval: 600, RDNSS: []net.IP{dns}}
+	srcMAC := net.HardwareAddr{0xaa, 0xc1, 0xab, 0x1f, 0xe2, 0xfa}
+
+	raw, err := BuildRARawData(raConfig, nil, srcMAC, LinkLocalFromMAC(srcMAC), net.ParseIP("ff02::1"), false, nil)
+	if err != nil {
+		t.Fatalf("BuildRARawData: %v", err)
+	}
+	// IPv6 header (40) + RA fixed part (16), then the RDNSS option.
+	opt := raw[56:]
+	if len(opt) != 24 || opt[0] != 25 || opt[1] != 3 {
+		t.Fatalf("RDNSS option = %x", opt)
+	}
+	if got := binary.BigEndian.Uint32(opt[4:8]); got != 1800 {
+		t.Fatalf("RDNSS lifetime = %d, want 1800", got)
+	}
+	if !net.IP(opt[8:24]).Equal(dns) {
+		t.Fatalf("RDNSS address = %s, want %s", net.IP(opt[8:24]), dns)
+	}
+}
@@ -50,6 +50,13 @@ func (c *Component) Stop(ctx context.Context) error {
 }
 
 func (c *Component) AdvertiseBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error {
+	return c.AdvertiseBGPNetworkPolicy(asn, vrf, prefix, "", ipv6)
+}
+
+// AdvertiseBGPNetworkPolicy is AdvertiseBGPNetwork with a route-map on
+// the network statement, for restoring a network the config originated
+// with a route policy.
+func (c *Component) AdvertiseBGPNetworkPolicy(asn uint32, vrf string, prefix string, routePolicy string, ipv6 bool) error {
 	af := "ipv4"
 	routeCmd := fmt.Sprintf("ip route %s Null0", prefix)
 	if ipv6 {
@@ -72,10 +79,14 @@ func (c *Component) AdvertiseBGPNetwork(asn uint32, vrf string, prefix string, i
 	if vrf != "" {
 		router = fmt.Sprintf("router bgp %d vrf %s", asn, vrf)
 	}
+	network := fmt.Sprintf("network %s", prefix)
+	if routePolicy != "" {
+		network += " route-map " + routePolicy
+	}
 	_, err := c.execVtysh("-c", "configure terminal",
 		"-c", router,
 		"-c", fmt.Sprintf("address-family %s unicast", af),
-		"-c", fmt.Sprintf("network %s", prefix))
+		"-c", network)
 	return err
 }
 
@@ -95,6 +106,26 @@ func (c *Component) WithdrawBGPNetwork(asn uint32, vrf string, prefix string, ip
 	return err
 }
 
+// RemoveBGPNetwork undoes AdvertiseBGPNetworkPolicy: it withdraws the
+// network and removes its blackhole route.
+func (c *Component) RemoveBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error {
+	if err := c.WithdrawBGPNetwork(asn, vrf, prefix, ipv6); err != nil {
+		return err
+	}
+
+	routeCmd := fmt.Sprintf("no ip route %s Null0", prefix)
+	if ipv6 {
+		routeCmd = fmt.Sprintf("no ipv6 route %s Null0", prefix)
+	}
+	if vrf != "" {
+		routeCmd += " vrf " + vrf
+	}
+	if _, err := c.execVtysh("-c", "configure terminal", "-c", routeCmd); err != nil {
+		return fmt.Errorf("remove blackhole route: %w", err)
+	}
+	return nil
+}
+
 func (c *Component) AdvertiseSRGNetworks(ctx context.Context, networks []config.SRGNetwork) error {
 	cfg, err := c.configMgr.GetRunning()
 	if err != nil {
@@ -672,6 +703,16 @@ func (c *Component) GetBGPRIB(vrf, afi, filter string) (json.RawMessage, error)
 	return json.RawMessage(output), nil
 }
 
+// GetBGPFlowSpec returns the FlowSpec rules of the AFI as the routing
+// daemon prints them in detail: a JSON array per rule, one after the
+// other, holding the rule's match and then its actions.
+func (c *Component) GetBGPFlowSpec(afi string) ([]byte, error) {
+	if afi != "ipv4" && afi != "ipv6" {
+		return nil, fmt.Errorf("invalid BGP AFI %q", afi)
+	}
+	return c.execVtysh("-c", "show bgp "+afi+" flowspec detail json")
+}
+
 func (c *Component) fetchVPNSummary(cmd, af string) (*bgp.VPNSummary, error) {
 	output, err := c.execVtysh("-c", cmd)
 	if err != nil {
@@ -0,0 +1,209 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// pendingTTL is how long one half of a blackhole mutation waits for the
+// other.
+const pendingTTL = time.Minute
+
+// A CoA or API mutation carrying aaa.AttrBlackhole blackholes the
+// session it targets. The mutation names the session only by its
+// identifiers; the addresses come with the access component's result.
+// The bus delivers the two events concurrently, so whichever arrives
+// first waits here for the other.
+type pendingMutation struct {
+	value    string
+	hasValue bool
+	result   *events.SubscriberMutationResultEvent
+	at       time.Time
+}
+
+func (c *Component) handleMutation(ev events.Event) {
+	data, ok := ev.Data.(*events.SubscriberMutationEvent)
+	if !ok {
+		return
+	}
+	value, ok := data.AttributeDelta[aaa.AttrBlackhole]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	p := c.pending[data.RequestID]
+	if p == nil || p.result == nil {
+		c.pending[data.RequestID] = &pendingMutation{value: value, hasValue: true, at: c.now()}
+		c.mu.Unlock()
+		return
+	}
+	delete(c.pending, data.RequestID)
+	c.mu.Unlock()
+
+	c.applyMutation(value, p.result)
+}
+
+func (c *Component) handleMutationResult(ev events.Event) {
+	data, ok := ev.Data.(*events.SubscriberMutationResultEvent)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	p := c.pending[data.RequestID]
+	if !data.Ok || data.Session == nil {
+		delete(c.pending, data.RequestID)
+		c.mu.Unlock()
+		return
+	}
+	if p == nil || !p.hasValue {
+		// Only wait for mutations that can be blackhole ones.
+		if _, ok := sessionAttributes(data.Session)[aaa.AttrBlackhole]; ok {
+			c.pending[data.RequestID] = &pendingMutation{result: data, at: c.now()}
+		}
+		c.mu.Unlock()
+		return
+	}
+	delete(c.pending, data.RequestID)
+	c.mu.Unlock()
+
+	c.applyMutation(p.value, data)
+}
+
+// prunePendingLocked drops halves whose other half never came. Caller
+// holds c.mu.
+func (c *Component) prunePendingLocked(now time.Time) {
+	for id, p := range c.pending {
+		if now.Sub(p.at) > pendingTTL {
+			delete(c.pending, id)
+		}
+	}
+}
+
+// applyMutation blackholes the session's addresses and delegated prefix
+// for the duration in value, or lifts their blackholes when it is 0.
+func (c *Component) applyMutation(value string, result *events.SubscriberMutationResultEvent) {
+	ctx := context.Background()
+	sess := result.Session
+
+	d, err := parseBlackholeDuration(value)
+	if err != nil {
+		c.logger.Warn("Ignoring blackhole mutation", "session_id", result.SessionID, "error", err)
+		return
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil || cfg == nil {
+		c.logger.Error("Failed to get running config", "error", err)
+		return
+	}
+
+	prefixes := sessionPrefixes(sess)
+	vrf := sessionVRF(sess)
+
+	if d == 0 {
+		for _, prefix := range prefixes {
+			key := (&models.Blackhole{Prefix: prefix.String(), VRF: vrf}).Key()
+			c.mu.Lock()
+			st, ok := c.blackholes[key]
+			c.mu.Unlock()
+			if !ok {
+				continue
+			}
+			c.remove(ctx, cfg, st.blackhole, events.BlackholeRemoved)
+			c.replicate(ctx, false, st.blackhole)
+		}
+		return
+	}
+
+	if cfg.RTBH == nil {
+		c.logger.Warn("Ignoring blackhole mutation: rtbh is not configured", "session_id", result.SessionID)
+		return
+	}
+	if d, err = cfg.RTBH.Duration(d); err != nil {
+		c.logger.Warn("Ignoring blackhole mutation", "session_id", result.SessionID, "error", err)
+		return
+	}
+
+	now := c.now()
+	for _, prefix := range prefixes {
+		if baseNetwork(cfg, prefix, vrf) {
+			c.logger.Warn("Not blackholing a network the config advertises", "session_id", result.SessionID,
+				"prefix", prefix, "vrf", vrf)
+			continue
+		}
+		b := &models.Blackhole{
+			Prefix:    prefix.String(),
+			VRF:       vrf,
+			Source:    models.BlackholeSourceAAA,
+			SessionID: result.SessionID,
+			Owner:     c.nodeID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(d),
+		}
+		c.add(ctx, cfg, b)
+		c.replicate(ctx, true, b)
+	}
+}
+
+// parseBlackholeDuration parses the attribute value: a Go duration or
+// whole seconds.
+func parseBlackholeDuration(value string) (time.Duration, error) {
+	if secs, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", aaa.AttrBlackhole, value)
+	}
+	return d, nil
+}
+
+// sessionPrefixes returns the session's IPv4 address as a /32, its
+// IPv6 address as a /128 and its delegated prefix.
+func sessionPrefixes(sess models.SubscriberSession) []netip.Prefix {
+	var out []netip.Prefix
+	if addr, ok := netip.AddrFromSlice(sess.GetIPv4Address().To4()); ok {
+		out = append(out, netip.PrefixFrom(addr, 32))
+	}
+	if ip := sess.GetIPv6Address(); ip != nil {
+		if addr, ok := netip.AddrFromSlice(ip.To16()); ok {
+			out = append(out, netip.PrefixFrom(addr, 128))
+		}
+	}
+	if p, err := netip.ParsePrefix(sess.GetIPv6Prefix()); err == nil {
+		out = append(out, p.Masked())
+	}
+	return out
+}
+
+func sessionVRF(sess models.SubscriberSession) string {
+	switch s := sess.(type) {
+	case *models.IPoESession:
+		return s.VRF
+	case *models.PPPSession:
+		return s.VRF
+	}
+	return ""
+}
+
+func sessionAttributes(sess models.SubscriberSession) map[string]string {
+	switch s := sess.(type) {
+	case *models.IPoESession:
+		return s.Attributes
+	case *models.PPPSession:
+		return s.Attributes
+	}
+	return nil
+}
@@ -0,0 +1,464 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package rtbh runs remote-triggered blackholes: traffic toward a
+// blackholed address or prefix is dropped in the dataplane and, with
+// BGP, advertised with the blackhole communities so the upstream
+// networks drop it before it reaches the BNG. Blackholes are requested
+// through the API or a CoA, expire on their own, and are kept across
+// restarts and on both HA peers.
+package rtbh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	rtbhcfg "github.com/veesix-networks/osvbng/pkg/config/rtbh"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// opdbNamespace holds every blackhole this node has installed, its own
+// or the HA peer's, keyed by Blackhole.Key.
+const opdbNamespace = "rtbh_blackholes"
+
+// tick is how often expired blackholes are lifted.
+const tick = 10 * time.Second
+
+// BGPNetworkController originates a blackhole and takes it back out.
+type BGPNetworkController interface {
+	AdvertiseBGPNetworkPolicy(asn uint32, vrf string, prefix string, routePolicy string, ipv6 bool) error
+	RemoveBGPNetwork(asn uint32, vrf string, prefix string, ipv6 bool) error
+}
+
+// Replicator sends blackholes requested on this node to the HA peer.
+type Replicator interface {
+	ReplicateBlackhole(ctx context.Context, add bool, b *models.Blackhole) error
+}
+
+// Config wires the component. Routing, Replicator and Sessions are
+// optional: without Routing blackholes are only dropped locally, without
+// Replicator the node is treated as standalone, and without Sessions
+// only pool addresses can be blackholed through the API.
+type Config struct {
+	EventBus      events.Bus
+	ConfigManager component.ConfigManager
+	OpDB          opdb.Store
+	Southbound    southbound.Blackhole
+	Routing       BGPNetworkController
+	Replicator    Replicator
+	Sessions      SessionProvider
+}
+
+type blackholeState struct {
+	blackhole  *models.Blackhole
+	installed  bool
+	advertised bool
+	err        string
+}
+
+// Component holds the blackholes of this node and its HA peer.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+	nodeID string
+	now    func() time.Time
+
+	mu         sync.Mutex
+	blackholes map[string]*blackholeState
+	// pending pairs a blackhole mutation with its result; see aaa.go.
+	pending map[string]*pendingMutation
+
+	mutationSub events.Subscription
+	resultSub   events.Subscription
+}
+
+func New(cfg Config) (*Component, error) {
+	if cfg.Southbound == nil {
+		return nil, fmt.Errorf("rtbh: southbound is required")
+	}
+
+	c := &Component{
+		Base:       component.NewBase("rtbh"),
+		logger:     logger.Get("rtbh"),
+		cfg:        cfg,
+		now:        time.Now,
+		blackholes: make(map[string]*blackholeState),
+		pending:    make(map[string]*pendingMutation),
+	}
+
+	if running, err := cfg.ConfigManager.GetRunning(); err == nil && running != nil {
+		c.nodeID = running.HA.NodeID
+	}
+	if c.nodeID == "" {
+		c.nodeID, _ = os.Hostname()
+	}
+	return c, nil
+}
+
+// Start reinstalls the blackholes held before a restart, lifting those
+// that expired meanwhile, then follows blackhole mutations and expiry.
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting RTBH component", "node_id", c.nodeID)
+
+	if err := c.restore(ctx); err != nil {
+		c.logger.Error("Failed to restore blackholes", "error", err)
+	}
+
+	if c.cfg.EventBus != nil {
+		c.mutationSub = c.cfg.EventBus.Subscribe(events.TopicSubscriberMutation, c.handleMutation)
+		c.resultSub = c.cfg.EventBus.Subscribe(events.TopicSubscriberMutationResult, c.handleMutationResult)
+	}
+
+	c.Go(c.expireLoop)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping RTBH component")
+	if c.mutationSub != nil {
+		c.mutationSub.Unsubscribe()
+	}
+	if c.resultSub != nil {
+		c.resultSub.Unsubscribe()
+	}
+	c.StopContext()
+	return nil
+}
+
+func (c *Component) restore(ctx context.Context) error {
+	if c.cfg.OpDB == nil {
+		return nil
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return fmt.Errorf("get running config: %w", err)
+	}
+
+	var restored []*models.Blackhole
+	err = c.cfg.OpDB.Load(ctx, opdbNamespace, func(key string, value []byte) error {
+		var b models.Blackhole
+		if err := json.Unmarshal(value, &b); err != nil {
+			c.logger.Warn("Skipping undecodable blackhole", "key", key, "error", err)
+			return nil
+		}
+		restored = append(restored, &b)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	now := c.now()
+	var count int
+	for _, b := range restored {
+		if !b.ExpiresAt.After(now) {
+			// The dataplane may have kept the route across the restart.
+			if prefix, err := netip.ParsePrefix(b.Prefix); err == nil {
+				_ = c.cfg.Southbound.DeleteBlackholeRoute(prefix, b.VRF)
+			}
+			c.forget(ctx, b)
+			c.logger.Info("Blackhole expired while stopped", "prefix", b.Prefix, "vrf", b.VRF)
+			continue
+		}
+		c.install(cfg, b)
+		count++
+	}
+	if count > 0 {
+		c.logger.Info("Restored blackholes", "count", count)
+	}
+	return nil
+}
+
+// Add blackholes the requested prefix, or replaces the expiry of the
+// blackhole already there, and replicates it to the HA peer. The prefix
+// must be a subscriber's; see checkRequested.
+func (c *Component) Add(ctx context.Context, req *models.BlackholeRequest) (*models.BlackholeStatus, error) {
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return nil, fmt.Errorf("get running config: %w", err)
+	}
+	if cfg == nil || cfg.RTBH == nil {
+		return nil, fmt.Errorf("rtbh is not configured")
+	}
+
+	prefix, err := rtbhcfg.ParsePrefix(req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkRequested(ctx, cfg, prefix, req.VRF); err != nil {
+		return nil, err
+	}
+	var requested time.Duration
+	if req.Duration != "" {
+		if requested, err = time.ParseDuration(req.Duration); err != nil {
+			return nil, fmt.Errorf("invalid duration %q", req.Duration)
+		}
+	}
+	d, err := cfg.RTBH.Duration(requested)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	b := &models.Blackhole{
+		Prefix:    prefix.String(),
+		VRF:       req.VRF,
+		Source:    models.BlackholeSourceAPI,
+		Reason:    req.Reason,
+		Owner:     c.nodeID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(d),
+	}
+	st := c.add(ctx, cfg, b)
+	c.replicate(ctx, true, b)
+	if st.err != "" {
+		return c.status(st), fmt.Errorf("blackhole %s: %s", b.Prefix, st.err)
+	}
+	return c.status(st), nil
+}
+
+// Remove lifts the blackhole of prefix in vrf and takes it off the HA
+// peer too.
+func (c *Component) Remove(ctx context.Context, prefix, vrf string) error {
+	p, err := rtbhcfg.ParsePrefix(prefix)
+	if err != nil {
+		return err
+	}
+	key := (&models.Blackhole{Prefix: p.String(), VRF: vrf}).Key()
+
+	c.mu.Lock()
+	st, ok := c.blackholes[key]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no blackhole for %s", key)
+	}
+
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return fmt.Errorf("get running config: %w", err)
+	}
+	c.remove(ctx, cfg, st.blackhole, events.BlackholeRemoved)
+	c.replicate(ctx, false, st.blackhole)
+	return nil
+}
+
+// Blackholes returns every blackhole held, ordered by VRF and prefix.
+func (c *Component) Blackholes() []models.BlackholeStatus {
+	c.mu.Lock()
+	states := make([]*blackholeState, 0, len(c.blackholes))
+	for _, st := range c.blackholes {
+		states = append(states, st)
+	}
+	c.mu.Unlock()
+
+	out := make([]models.BlackholeStatus, 0, len(states))
+	for _, st := range states {
+		out = append(out, *c.status(st))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].VRF != out[j].VRF {
+			return out[i].VRF < out[j].VRF
+		}
+		return out[i].Prefix < out[j].Prefix
+	})
+	return out
+}
+
+func (c *Component) status(st *blackholeState) *models.BlackholeStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := st.blackhole.ExpiresAt.Sub(c.now())
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &models.BlackholeStatus{
+		Blackhole:  *st.blackhole,
+		Remaining:  int64(remaining / time.Second),
+		Installed:  st.installed,
+		Advertised: st.advertised,
+		Error:      st.err,
+	}
+}
+
+// add installs and persists b, replacing a blackhole of the same key
+// but keeping when it was first created.
+func (c *Component) add(ctx context.Context, cfg *config.Config, b *models.Blackhole) *blackholeState {
+	c.mu.Lock()
+	if cur, ok := c.blackholes[b.Key()]; ok && cur.blackhole.CreatedAt.Before(b.CreatedAt) {
+		b.CreatedAt = cur.blackhole.CreatedAt
+	}
+	c.mu.Unlock()
+
+	st := c.install(cfg, b)
+	c.persist(ctx, b)
+	c.logger.Info("Blackhole added", "prefix", b.Prefix, "vrf", b.VRF, "source", b.Source,
+		"session_id", b.SessionID, "expires_at", b.ExpiresAt, "owner", b.Owner)
+	c.publish(events.BlackholeAdded, b)
+	return st
+}
+
+// install drops traffic toward b and advertises it. A failure is kept
+// with the blackhole for show; adding it again retries.
+func (c *Component) install(cfg *config.Config, b *models.Blackhole) *blackholeState {
+	st := &blackholeState{blackhole: b}
+
+	prefix, err := netip.ParsePrefix(b.Prefix)
+	if err != nil {
+		st.err = err.Error()
+	} else {
+		if err := c.cfg.Southbound.AddBlackholeRoute(prefix, b.VRF); err != nil {
+			st.err = err.Error()
+			c.logger.Error("Failed to install blackhole route", "prefix", b.Prefix, "vrf", b.VRF, "error", err)
+		} else {
+			st.installed = true
+		}
+		// Upstream still drops the traffic when the local route failed.
+		st.advertised = c.setAdvertised(cfg, b, prefix, true)
+	}
+
+	c.mu.Lock()
+	c.blackholes[b.Key()] = st
+	c.mu.Unlock()
+	return st
+}
+
+// remove withdraws and uninstalls b and forgets it.
+func (c *Component) remove(ctx context.Context, cfg *config.Config, b *models.Blackhole, action string) {
+	if prefix, err := netip.ParsePrefix(b.Prefix); err == nil {
+		c.setAdvertised(cfg, b, prefix, false)
+		if err := c.cfg.Southbound.DeleteBlackholeRoute(prefix, b.VRF); err != nil {
+			c.logger.Error("Failed to remove blackhole route", "prefix", b.Prefix, "vrf", b.VRF, "error", err)
+		}
+	}
+	c.forget(ctx, b)
+	c.logger.Info("Blackhole "+action, "prefix", b.Prefix, "vrf", b.VRF, "source", b.Source)
+	c.publish(action, b)
+}
+
+// setAdvertised originates or removes b through the rtbh route-policy,
+// which sets the blackhole communities. It reports whether b is now
+// advertised.
+func (c *Component) setAdvertised(cfg *config.Config, b *models.Blackhole, prefix netip.Prefix, advertise bool) bool {
+	if c.cfg.Routing == nil || cfg == nil || cfg.Protocols.BGP == nil {
+		return false
+	}
+	asn := cfg.Protocols.BGP.ASN
+	ipv6 := prefix.Addr().Is6()
+
+	var err error
+	if advertise {
+		err = c.cfg.Routing.AdvertiseBGPNetworkPolicy(asn, b.VRF, b.Prefix, cfg.RTBH.GetRoutePolicy(), ipv6)
+	} else {
+		err = c.cfg.Routing.RemoveBGPNetwork(asn, b.VRF, b.Prefix, ipv6)
+	}
+	if err != nil {
+		c.logger.Error("Failed to update blackhole BGP network", "prefix", b.Prefix, "vrf", b.VRF,
+			"advertise", advertise, "error", err)
+		return false
+	}
+	return advertise
+}
+
+func (c *Component) expireLoop() {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.expire(c.Ctx)
+		}
+	}
+}
+
+// expire lifts the blackholes that ran out. Each HA peer expires its
+// own copy, so nothing is replicated.
+func (c *Component) expire(ctx context.Context) {
+	now := c.now()
+
+	var expired []*models.Blackhole
+	c.mu.Lock()
+	for _, st := range c.blackholes {
+		if !st.blackhole.ExpiresAt.After(now) {
+			expired = append(expired, st.blackhole)
+		}
+	}
+	c.prunePendingLocked(now)
+	c.mu.Unlock()
+
+	if len(expired) == 0 {
+		return
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		c.logger.Error("Failed to get running config", "error", err)
+		return
+	}
+	for _, b := range expired {
+		c.remove(ctx, cfg, b, events.BlackholeExpired)
+	}
+}
+
+func (c *Component) replicate(ctx context.Context, add bool, b *models.Blackhole) {
+	if c.cfg.Replicator == nil {
+		return
+	}
+	if err := c.cfg.Replicator.ReplicateBlackhole(ctx, add, b); err != nil {
+		c.logger.Warn("Failed to replicate blackhole to peer", "prefix", b.Prefix, "add", add, "error", err)
+	}
+}
+
+func (c *Component) persist(ctx context.Context, b *models.Blackhole) {
+	if c.cfg.OpDB == nil {
+		return
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return
+	}
+	if err := c.cfg.OpDB.Put(ctx, opdbNamespace, b.Key(), data); err != nil {
+		c.logger.Error("Failed to persist blackhole", "prefix", b.Prefix, "error", err)
+	}
+}
+
+func (c *Component) forget(ctx context.Context, b *models.Blackhole) {
+	c.mu.Lock()
+	delete(c.blackholes, b.Key())
+	c.mu.Unlock()
+
+	if c.cfg.OpDB == nil {
+		return
+	}
+	if err := c.cfg.OpDB.Delete(ctx, opdbNamespace, b.Key()); err != nil {
+		c.logger.Error("Failed to delete blackhole", "prefix", b.Prefix, "error", err)
+	}
+}
+
+func (c *Component) publish(action string, b *models.Blackhole) {
+	if c.cfg.EventBus == nil {
+		return
+	}
+	c.cfg.EventBus.Publish(events.TopicBlackhole, events.Event{
+		Source:    c.Name(),
+		Timestamp: time.Now(),
+		Data:      &events.BlackholeEvent{Action: action, Blackhole: b},
+	})
+}
@@ -0,0 +1,328 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	rtbhcfg "github.com/veesix-networks/osvbng/pkg/config/rtbh"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/opdb"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeSB struct{ routes map[string]bool }
+
+func (s *fakeSB) AddBlackholeRoute(prefix netip.Prefix, vrf string) error {
+	s.routes[vrf+"/"+prefix.String()] = true
+	return nil
+}
+
+func (s *fakeSB) DeleteBlackholeRoute(prefix netip.Prefix, vrf string) error {
+	delete(s.routes, vrf+"/"+prefix.String())
+	return nil
+}
+
+type fakeRouting struct{ calls []string }
+
+func (r *fakeRouting) AdvertiseBGPNetworkPolicy(asn uint32, vrf, prefix, routePolicy string, ipv6 bool) error {
+	r.calls = append(r.calls, fmt.Sprintf("advertise %s %s", prefix, routePolicy))
+	return nil
+}
+
+func (r *fakeRouting) RemoveBGPNetwork(asn uint32, vrf, prefix string, ipv6 bool) error {
+	r.calls = append(r.calls, "remove "+prefix)
+	return nil
+}
+
+type fakeReplicator struct{ calls []string }
+
+func (r *fakeReplicator) ReplicateBlackhole(ctx context.Context, add bool, b *models.Blackhole) error {
+	r.calls = append(r.calls, fmt.Sprintf("%t %s", add, b.Prefix))
+	return nil
+}
+
+type fakeOpDB struct {
+	opdb.Store
+	data map[string][]byte
+}
+
+func (f *fakeOpDB) Put(ctx context.Context, namespace, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeOpDB) Delete(ctx context.Context, namespace, key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeOpDB) Load(ctx context.Context, namespace string, fn opdb.LoadFunc) error {
+	for k, v := range f.data {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeSessions struct{ sessions []models.SubscriberSession }
+
+func (f *fakeSessions) GetSessions(ctx context.Context, accessType, protocol string, svlan uint32) ([]models.SubscriberSession, error) {
+	return f.sessions, nil
+}
+
+type testEnv struct {
+	c       *Component
+	cfg     *config.Config
+	sb      *fakeSB
+	routing *fakeRouting
+	peer    *fakeReplicator
+	store   *fakeOpDB
+	now     time.Time
+}
+
+func newTestEnv(t *testing.T, nodeID string, store *fakeOpDB) *testEnv {
+	t.Helper()
+	cfg := &config.Config{
+		Protocols: protocols.ProtocolConfig{BGP: &protocols.BGPConfig{ASN: 65000}},
+		RTBH:      &rtbhcfg.Config{},
+		IPv4Profiles: map[string]*ip.IPv4Profile{
+			"default": {Pools: []ip.IPv4Pool{{Name: "cgnat", Network: "100.64.0.0/16"}}},
+		},
+	}
+	cfg.HA.NodeID = nodeID
+
+	env := &testEnv{
+		cfg:     cfg,
+		sb:      &fakeSB{routes: make(map[string]bool)},
+		routing: &fakeRouting{},
+		peer:    &fakeReplicator{},
+		store:   store,
+		now:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+	c, err := New(Config{
+		ConfigManager: &fakeCfg{cfg: cfg},
+		OpDB:          store,
+		Southbound:    env.sb,
+		Routing:       env.routing,
+		Replicator:    env.peer,
+		Sessions: &fakeSessions{sessions: []models.SubscriberSession{&models.IPoESession{
+			SessionID:  "s1",
+			State:      models.SessionStateActive,
+			IPv6Prefix: "2001:db8::/56",
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return env.now }
+	env.c = c
+	return env
+}
+
+func TestAddExpireAndRestore(t *testing.T) {
+	store := &fakeOpDB{data: make(map[string][]byte)}
+	env := newTestEnv(t, "bng1", store)
+	ctx := context.Background()
+
+	st, err := env.c.Add(ctx, &models.BlackholeRequest{Prefix: "100.64.1.7", Duration: "30m", Reason: "ddos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Prefix != "100.64.1.7/32" || !st.Installed || !st.Advertised || st.Remaining != 1800 {
+		t.Fatalf("status = %+v", st)
+	}
+	if _, err := env.c.Add(ctx, &models.BlackholeRequest{Prefix: "2001:db8::/64"}); err != nil {
+		t.Fatal(err)
+	}
+	if !env.sb.routes["/100.64.1.7/32"] || !env.sb.routes["/2001:db8::/64"] {
+		t.Fatalf("routes = %v", env.sb.routes)
+	}
+	want := []string{"advertise 100.64.1.7/32 RTBH-BLACKHOLE", "advertise 2001:db8::/64 RTBH-BLACKHOLE"}
+	if !reflect.DeepEqual(env.routing.calls, want) {
+		t.Fatalf("routing = %v", env.routing.calls)
+	}
+	if len(env.peer.calls) != 2 || len(store.data) != 2 {
+		t.Fatalf("replicated %v, persisted %d", env.peer.calls, len(store.data))
+	}
+
+	for _, req := range []*models.BlackholeRequest{
+		{Prefix: "100.64.0.0/16"},
+		{Prefix: "100.64.1.8", Duration: "48h"},
+		{Prefix: "100.64.1.8", Duration: "soon"},
+	} {
+		if _, err := env.c.Add(ctx, req); err == nil {
+			t.Errorf("Add(%+v) succeeded", req)
+		}
+	}
+
+	// A restart after the /32 expired lifts it and keeps the /64,
+	// which lasts the default hour.
+	restarted := newTestEnv(t, "bng1", store)
+	restarted.now = env.now.Add(45 * time.Minute)
+	restarted.sb.routes["/100.64.1.7/32"] = true
+	if err := restarted.c.restore(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := restarted.c.Blackholes(); len(got) != 1 || got[0].Prefix != "2001:db8::/64" || got[0].Remaining != 900 {
+		t.Fatalf("restored = %+v", got)
+	}
+	if restarted.sb.routes["/100.64.1.7/32"] || !restarted.sb.routes["/2001:db8::/64"] {
+		t.Fatalf("routes after restore = %v", restarted.sb.routes)
+	}
+
+	restarted.now = restarted.now.Add(15 * time.Minute)
+	restarted.c.expire(ctx)
+	if len(restarted.c.Blackholes()) != 0 || len(restarted.sb.routes) != 0 || len(store.data) != 0 {
+		t.Fatalf("expire left %v, %v", restarted.c.Blackholes(), restarted.sb.routes)
+	}
+	if len(restarted.peer.calls) != 0 {
+		t.Fatalf("expiry replicated: %v", restarted.peer.calls)
+	}
+}
+
+func TestBlackholeMutation(t *testing.T) {
+	env := newTestEnv(t, "bng1", &fakeOpDB{data: make(map[string][]byte)})
+	sess := &models.IPoESession{
+		SessionID:   "s1",
+		VRF:         "cust",
+		IPv4Address: net.ParseIP("100.64.1.7"),
+		IPv6Prefix:  "2001:db8:0:100::/56",
+		Attributes:  map[string]string{aaa.AttrBlackhole: "600"},
+	}
+	mutate := func(id, value string, resultFirst bool) {
+		mutation := events.Event{Data: &events.SubscriberMutationEvent{
+			RequestID:      id,
+			SessionID:      "s1",
+			AttributeDelta: map[string]string{aaa.AttrBlackhole: value},
+		}}
+		result := events.Event{Data: &events.SubscriberMutationResultEvent{RequestID: id, SessionID: "s1", Ok: true, Session: sess}}
+		if resultFirst {
+			env.c.handleMutationResult(result)
+			env.c.handleMutation(mutation)
+		} else {
+			env.c.handleMutation(mutation)
+			env.c.handleMutationResult(result)
+		}
+	}
+
+	mutate("r1", "600", true)
+	got := env.c.Blackholes()
+	if len(got) != 2 || got[0].Prefix != "100.64.1.7/32" || got[1].Prefix != "2001:db8:0:100::/56" {
+		t.Fatalf("blackholes = %+v", got)
+	}
+	if got[0].VRF != "cust" || got[0].Source != models.BlackholeSourceAAA || got[0].SessionID != "s1" || got[0].Remaining != 600 {
+		t.Fatalf("blackhole = %+v", got[0])
+	}
+
+	// A later mutation of something else does not blackhole again.
+	env.c.handleMutationResult(events.Event{Data: &events.SubscriberMutationResultEvent{RequestID: "r2", Ok: true, Session: sess}})
+	env.now = env.now.Add(2 * pendingTTL)
+	env.c.expire(context.Background())
+	if len(env.c.pending) != 0 {
+		t.Fatalf("pending not pruned: %v", env.c.pending)
+	}
+
+	mutate("r3", "0", false)
+	if got := env.c.Blackholes(); len(got) != 0 || len(env.sb.routes) != 0 {
+		t.Fatalf("lift left %+v", got)
+	}
+}
+
+func TestPeerBlackholes(t *testing.T) {
+	env := newTestEnv(t, "bng2", &fakeOpDB{data: make(map[string][]byte)})
+	peer := func(prefix string) *models.Blackhole {
+		return &models.Blackhole{Prefix: prefix, Source: models.BlackholeSourceAPI, Owner: "bng1",
+			CreatedAt: env.now, ExpiresAt: env.now.Add(time.Hour)}
+	}
+
+	if err := env.c.ApplySyncedBlackhole(true, peer("100.64.1.7/32")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.c.Add(context.Background(), &models.BlackholeRequest{Prefix: "100.64.2.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if owned := env.c.OwnedBlackholes(); len(owned) != 1 || owned[0].Prefix != "100.64.2.1/32" {
+		t.Fatalf("owned = %+v", owned)
+	}
+
+	// The peer lifted the /32 and added another while disconnected.
+	env.c.ReconcilePeerBlackholes([]*models.Blackhole{peer("100.64.3.0/24")})
+	var prefixes []string
+	for _, b := range env.c.Blackholes() {
+		prefixes = append(prefixes, b.Prefix)
+	}
+	if want := []string{"100.64.2.1/32", "100.64.3.0/24"}; !reflect.DeepEqual(prefixes, want) {
+		t.Fatalf("blackholes = %v, want %v", prefixes, want)
+	}
+	if len(env.peer.calls) != 1 {
+		t.Fatalf("synced blackholes replicated back: %v", env.peer.calls)
+	}
+}
+
+func TestAddRefusesPrefixesNotSubscribers(t *testing.T) {
+	env := newTestEnv(t, "bng1", &fakeOpDB{data: make(map[string][]byte)})
+	env.cfg.IPv4Profiles["default"].Pools = append(env.cfg.IPv4Profiles["default"].Pools,
+		ip.IPv4Pool{Name: "small", Network: "198.51.100.0/24"})
+	env.cfg.Protocols.BGP.IPv4Unicast = &protocols.BGPAddressFamily{
+		Networks: map[string]*protocols.BGPNetwork{"100.64.5.0/24": {}},
+	}
+	ctx := context.Background()
+
+	for _, req := range []*models.BlackholeRequest{
+		// Nobody's address.
+		{Prefix: "192.0.2.1"},
+		// The session's prefix, but in another VRF.
+		{Prefix: "2001:db8::/64", VRF: "cust"},
+		// A pool network and a network statement: lifting the blackhole
+		// would withdraw them.
+		{Prefix: "198.51.100.0/24"},
+		{Prefix: "100.64.5.0/24"},
+	} {
+		if _, err := env.c.Add(ctx, req); err == nil {
+			t.Errorf("Add(%+v) succeeded", req)
+		}
+	}
+	if len(env.c.Blackholes()) != 0 || len(env.routing.calls) != 0 {
+		t.Fatalf("refused requests left %+v, routing %v", env.c.Blackholes(), env.routing.calls)
+	}
+
+	// Within the pool and the session's delegated prefix.
+	for _, prefix := range []string{"198.51.100.9", "100.64.5.1", "2001:db8:0:10::/60"} {
+		if _, err := env.c.Add(ctx, &models.BlackholeRequest{Prefix: prefix}); err != nil {
+			t.Errorf("Add(%s): %v", prefix, err)
+		}
+	}
+
+	// A CoA does not blackhole a session prefix the config advertises.
+	env.cfg.Protocols.BGP.IPv6Unicast = &protocols.BGPAddressFamily{
+		Networks: map[string]*protocols.BGPNetwork{"2001:db8:0:100::/56": {}},
+	}
+	sess := &models.IPoESession{SessionID: "s2", IPv6Prefix: "2001:db8:0:100::/56"}
+	env.c.applyMutation("600", &events.SubscriberMutationResultEvent{SessionID: "s2", Ok: true, Session: sess})
+	if got := env.c.Blackholes(); len(got) != 3 {
+		t.Fatalf("blackholes = %+v", got)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// SessionProvider lists the subscriber sessions, whose addresses and
+// delegated prefixes can be blackholed.
+type SessionProvider interface {
+	GetSessions(ctx context.Context, accessType, protocol string, svlan uint32) ([]models.SubscriberSession, error)
+}
+
+// checkRequested refuses a requested prefix that is not a subscriber's:
+// it must lie within the address or delegated prefix of an active
+// session, or within a pool subscribers are addressed from, in vrf. A
+// prefix the config originates a BGP network for is refused too, since
+// lifting the blackhole would withdraw that network.
+func (c *Component) checkRequested(ctx context.Context, cfg *config.Config, prefix netip.Prefix, vrf string) error {
+	if baseNetwork(cfg, prefix, vrf) {
+		return fmt.Errorf("prefix %s is a network the config advertises", prefix)
+	}
+	for _, p := range poolPrefixes(cfg, vrf) {
+		if covers(p, prefix) {
+			return nil
+		}
+	}
+	if c.cfg.Sessions != nil {
+		sessions, err := c.cfg.Sessions.GetSessions(ctx, "", "", 0)
+		if err != nil {
+			return fmt.Errorf("get sessions: %w", err)
+		}
+		for _, sess := range sessions {
+			if sess.GetState() != models.SessionStateActive || sessionVRF(sess) != vrf {
+				continue
+			}
+			for _, p := range sessionPrefixes(sess) {
+				if covers(p, prefix) {
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("prefix %s is not held by a subscriber session or pool", prefix)
+}
+
+// covers reports whether prefix lies within p.
+func covers(p, prefix netip.Prefix) bool {
+	return p.Bits() <= prefix.Bits() && p.Contains(prefix.Addr())
+}
+
+// poolPrefixes returns the networks of the IPv4, IANA and PD pools in
+// vrf, and the CGNAT outside prefixes, which are in the default table.
+func poolPrefixes(cfg *config.Config, vrf string) []netip.Prefix {
+	var out []netip.Prefix
+	add := func(network string) {
+		if p, err := netip.ParsePrefix(network); err == nil {
+			out = append(out, p.Masked())
+		}
+	}
+	for _, profile := range cfg.IPv4Profiles {
+		if profile == nil {
+			continue
+		}
+		for _, pool := range profile.Pools {
+			if poolVRF(pool.VRF, profile.VRF) == vrf {
+				add(pool.Network)
+			}
+		}
+	}
+	for _, profile := range cfg.IPv6Profiles {
+		if profile == nil {
+			continue
+		}
+		for _, pool := range profile.IANAPools {
+			if poolVRF(pool.VRF, profile.VRF) == vrf {
+				add(pool.Network)
+			}
+		}
+		for _, pool := range profile.PDPools {
+			if poolVRF(pool.VRF, profile.VRF) == vrf {
+				add(pool.Network)
+			}
+		}
+	}
+	if cfg.CGNAT != nil && vrf == "" {
+		for _, pool := range cfg.CGNAT.Pools {
+			if pool == nil {
+				continue
+			}
+			for _, addr := range pool.OutsideAddresses {
+				add(addr)
+			}
+		}
+	}
+	return out
+}
+
+// poolVRF is the VRF of a pool, which defaults to its profile's.
+func poolVRF(pool, profile string) string {
+	if pool != "" {
+		return pool
+	}
+	return profile
+}
+
+// baseNetwork reports whether the config originates prefix in vrf: a
+// BGP network statement, or a pool network, which subscriber groups and
+// the pool monitor advertise. Blackholing it would replace that network
+// statement, and lifting the blackhole would remove it.
+func baseNetwork(cfg *config.Config, prefix netip.Prefix, vrf string) bool {
+	for _, p := range poolPrefixes(cfg, vrf) {
+		if p == prefix {
+			return true
+		}
+	}
+	bgp := cfg.Protocols.BGP
+	if bgp == nil {
+		return false
+	}
+	var networks []string
+	if vrf == "" {
+		if bgp.IPv4Unicast != nil {
+			networks = appendKeys(networks, bgp.IPv4Unicast.Networks)
+		}
+		if bgp.IPv6Unicast != nil {
+			networks = appendKeys(networks, bgp.IPv6Unicast.Networks)
+		}
+	} else if v := bgp.VRF[vrf]; v != nil {
+		if v.IPv4Unicast != nil {
+			networks = appendKeys(networks, v.IPv4Unicast.Networks)
+		}
+		if v.IPv6Unicast != nil {
+			networks = appendKeys(networks, v.IPv6Unicast.Networks)
+		}
+	}
+	for _, network := range networks {
+		if p, err := netip.ParsePrefix(network); err == nil && p.Masked() == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+func appendKeys[V any](out []string, m map[string]V) []string {
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
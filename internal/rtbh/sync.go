@@ -0,0 +1,94 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// ApplySyncedBlackhole adds or lifts a blackhole requested or lifted on
+// the HA peer. The blackhole is installed, advertised and persisted like
+// one requested here, so traffic is still dropped after a switchover.
+// Either node can lift any blackhole; re-requesting one makes it the
+// requesting node's.
+func (c *Component) ApplySyncedBlackhole(add bool, b *models.Blackhole) error {
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if add {
+		if !b.ExpiresAt.After(c.now()) {
+			return nil
+		}
+		if st := c.add(ctx, cfg, b); st.err != "" {
+			return fmt.Errorf("blackhole %s: %s", b.Prefix, st.err)
+		}
+		return nil
+	}
+
+	c.mu.Lock()
+	_, held := c.blackholes[b.Key()]
+	c.mu.Unlock()
+	if !held {
+		return nil
+	}
+	c.remove(ctx, cfg, b, events.BlackholeRemoved)
+	return nil
+}
+
+// OwnedBlackholes returns the blackholes requested on this node.
+func (c *Component) OwnedBlackholes() []*models.Blackhole {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []*models.Blackhole
+	for _, st := range c.blackholes {
+		if st.blackhole.Owner == c.nodeID {
+			out = append(out, st.blackhole)
+		}
+	}
+	return out
+}
+
+// ReconcilePeerBlackholes adopts the peer's blackholes this node is
+// missing, or holds with another expiry, and lifts adopted blackholes
+// the peer no longer has.
+func (c *Component) ReconcilePeerBlackholes(blackholes []*models.Blackhole) {
+	owned := make(map[string]bool, len(blackholes))
+	for _, b := range blackholes {
+		owned[b.Key()] = true
+
+		c.mu.Lock()
+		st, held := c.blackholes[b.Key()]
+		c.mu.Unlock()
+		if held && st.blackhole.ExpiresAt.Equal(b.ExpiresAt) {
+			continue
+		}
+		if err := c.ApplySyncedBlackhole(true, b); err != nil {
+			c.logger.Warn("Failed to adopt peer blackhole", "prefix", b.Prefix, "error", err)
+		}
+	}
+
+	var stale []*models.Blackhole
+	c.mu.Lock()
+	for key, st := range c.blackholes {
+		if st.blackhole.Owner != c.nodeID && !owned[key] {
+			stale = append(stale, st.blackhole)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, b := range stale {
+		if err := c.ApplySyncedBlackhole(false, b); err != nil {
+			c.logger.Warn("Failed to lift stale peer blackhole", "prefix", b.Prefix, "error", err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package steering runs the steering policies of the running config:
+// it programs each policy with the next hops that pass their health
+// check and lets the policy fail open, withdrawing it so its sessions
+// forward normally, while none does. Sessions are bound to policies by
+// pkg/svcgroup as their service group is applied.
+package steering
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/component"
+	steeringcfg "github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+// tick is how often the component looks for due probes and config
+// changes. Probe intervals are configured per policy and are longer.
+const tick = time.Second
+
+// Component follows the steering-policies block of the running config.
+type Component struct {
+	*component.Base
+	logger *logger.Logger
+	cfg    Config
+
+	// probe sends one health check to a next hop.
+	probe func(ctx context.Context, hop steeringcfg.NextHop, timeout time.Duration) error
+
+	mu         sync.Mutex
+	health     map[string]*hopHealth
+	programmed map[string]*programmedPolicy
+}
+
+// programmedPolicy is what the dataplane was last given for a policy.
+type programmedPolicy struct {
+	accessList string
+	// hops are the keys of the next hops in use, in config order.
+	hops []string
+	err  string
+}
+
+// Config wires the component.
+type Config struct {
+	ConfigManager component.ConfigManager
+	Southbound    southbound.Steering
+}
+
+func New(cfg Config) *Component {
+	return &Component{
+		Base:       component.NewBase("steering"),
+		logger:     logger.Get("steering"),
+		cfg:        cfg,
+		probe:      echoProbe,
+		health:     make(map[string]*hopHealth),
+		programmed: make(map[string]*programmedPolicy),
+	}
+}
+
+func (c *Component) Start(ctx context.Context) error {
+	c.StartContext(ctx)
+	c.logger.Info("Starting steering component")
+	c.Go(c.run)
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	c.logger.Info("Stopping steering component")
+	c.StopContext()
+	return nil
+}
+
+func (c *Component) run() {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	c.sync(c.Ctx, time.Now())
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.sync(c.Ctx, now)
+		}
+	}
+}
+
+// sync starts the probes that are due and brings the dataplane in line
+// with the configured policies and the health of their next hops.
+func (c *Component) sync(ctx context.Context, now time.Time) {
+	policies := c.runningPolicies()
+	c.runHealthChecks(ctx, now, policies)
+	c.reconcile(policies)
+}
+
+func (c *Component) runningPolicies() map[string]*steeringcfg.Policy {
+	if c.cfg.ConfigManager == nil {
+		return nil
+	}
+	cfg, err := c.cfg.ConfigManager.GetRunning()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.SteeringPolicies
+}
+
+// reconcile programs every policy whose access list or usable next hops
+// changed, retries those that failed, and withdraws removed ones.
+func (c *Component) reconcile(policies map[string]*steeringcfg.Policy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range sortedNames(policies) {
+		p := policies[name]
+		var hops []southbound.SteeringNextHop
+		var keys []string
+		for _, hop := range p.NextHops {
+			if !c.usableLocked(p, hop) {
+				continue
+			}
+			hops = append(hops, southbound.SteeringNextHop{Address: hop.IP(), VRF: hop.VRF})
+			keys = append(keys, hop.Key())
+		}
+
+		prev := c.programmed[name]
+		if prev != nil && prev.err == "" && prev.accessList == p.AccessList && equalKeys(prev.hops, keys) {
+			continue
+		}
+
+		st := &programmedPolicy{accessList: p.AccessList, hops: keys}
+		if err := c.cfg.Southbound.SetSteeringPolicy(name, p.AccessList, hops); err != nil {
+			st.err = err.Error()
+			if prev == nil || prev.err != st.err {
+				c.logger.Error("Failed to program steering policy", "policy", name, "error", err)
+			}
+			c.programmed[name] = st
+			continue
+		}
+		c.programmed[name] = st
+
+		switch {
+		case len(keys) == 0 && (prev == nil || len(prev.hops) > 0 || prev.err != ""):
+			c.logger.Warn("No steering next hop is up, policy failing open", "policy", name)
+		case len(keys) > 0 && prev != nil && len(prev.hops) == 0 && prev.err == "":
+			c.logger.Info("Steering next hop back up, policy steering again", "policy", name, "next_hops", keys)
+		default:
+			c.logger.Debug("Steering policy programmed", "policy", name, "access_list", p.AccessList, "next_hops", keys)
+		}
+	}
+
+	for name := range c.programmed {
+		if policies[name] != nil {
+			continue
+		}
+		if err := c.cfg.Southbound.DeleteSteeringPolicy(name); err != nil {
+			c.logger.Error("Failed to remove steering policy", "policy", name, "error", err)
+			continue
+		}
+		delete(c.programmed, name)
+		c.logger.Debug("Steering policy removed", "policy", name)
+	}
+}
+
+// usableLocked reports whether the policy may forward to the next hop:
+// it has no health check, or the health check has not marked it down.
+// Caller holds mu.
+func (c *Component) usableLocked(p *steeringcfg.Policy, hop steeringcfg.NextHop) bool {
+	if p.HealthCheck == nil {
+		return true
+	}
+	h := c.health[hop.Key()]
+	return h == nil || h.state != models.SteeringHopDown
+}
+
+// Policies returns the configured steering policies with the state of
+// their next hops, sorted by name.
+func (c *Component) Policies() []models.SteeringPolicy {
+	policies := c.runningPolicies()
+	out := make([]models.SteeringPolicy, 0, len(policies))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range sortedNames(policies) {
+		p := policies[name]
+		mp := models.SteeringPolicy{Name: name, AccessList: p.AccessList, NextHops: []models.SteeringNextHop{}}
+		prog := c.programmed[name]
+		if prog != nil {
+			mp.Error = prog.err
+			mp.FailOpen = prog.err == "" && len(prog.hops) == 0
+		}
+		for _, hop := range p.NextHops {
+			mh := models.SteeringNextHop{Address: hop.Address, VRF: hop.VRF, State: models.SteeringHopUnchecked}
+			if p.HealthCheck != nil {
+				mh.State = models.SteeringHopUnknown
+				if h := c.health[hop.Key()]; h != nil {
+					mh.State = h.state
+					mh.LastChange = h.lastChange
+					mh.LastError = h.lastError
+				}
+			}
+			if prog != nil && prog.err == "" {
+				for _, k := range prog.hops {
+					if k == hop.Key() {
+						mh.Active = true
+						break
+					}
+				}
+			}
+			mp.NextHops = append(mp.NextHops, mh)
+		}
+		out = append(out, mp)
+	}
+	return out
+}
+
+func sortedNames(policies map[string]*steeringcfg.Policy) []string {
+	names := make([]string, 0, len(policies))
+	for name, p := range policies {
+		if p != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	steeringcfg "github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeSB struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+}
+
+func (f *fakeSB) SetSteeringPolicy(name, accessList string, nextHops []southbound.SteeringNextHop) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hops := make([]string, 0, len(nextHops))
+	for _, h := range nextHops {
+		hops = append(hops, h.Address.String())
+	}
+	f.calls = append(f.calls, fmt.Sprintf("set %s %s [%s]", name, accessList, strings.Join(hops, " ")))
+	return f.err
+}
+
+func (f *fakeSB) DeleteSteeringPolicy(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "delete "+name)
+	return nil
+}
+
+func (f *fakeSB) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := f.calls
+	f.calls = nil
+	return out
+}
+
+func newTestComponent() (*Component, *fakeSB, *config.Config) {
+	cfg := &config.Config{SteeringPolicies: map[string]*steeringcfg.Policy{
+		"dpi": {
+			AccessList: "web",
+			NextHops: []steeringcfg.NextHop{
+				{Address: "192.0.2.1"},
+				{Address: "192.0.2.2"},
+			},
+			HealthCheck: &steeringcfg.HealthCheck{DownAfter: 1, UpAfter: 1},
+		},
+	}}
+	sb := &fakeSB{}
+	c := New(Config{ConfigManager: &fakeCfg{cfg: cfg}, Southbound: sb})
+	return c, sb, cfg
+}
+
+func expectCalls(t *testing.T, sb *fakeSB, want ...string) {
+	t.Helper()
+	got := sb.take()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestReconcileFailsOpenAndRecovers(t *testing.T) {
+	c, sb, cfg := newTestComponent()
+	hc := cfg.SteeringPolicies["dpi"].HealthCheck.WithDefaults()
+	c.health["192.0.2.1"] = &hopHealth{state: models.SteeringHopUnknown}
+	c.health["192.0.2.2"] = &hopHealth{state: models.SteeringHopUnknown}
+
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web [192.0.2.1 192.0.2.2]")
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb)
+
+	probeErr := errors.New("unreachable")
+	c.recordProbe("192.0.2.1", probeErr, hc)
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web [192.0.2.2]")
+
+	c.recordProbe("192.0.2.2", probeErr, hc)
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web []")
+	if p := c.Policies(); len(p) != 1 || !p[0].FailOpen || p[0].NextHops[0].State != models.SteeringHopDown {
+		t.Fatalf("expected policy failing open, got %+v", p)
+	}
+
+	c.recordProbe("192.0.2.1", nil, hc)
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web [192.0.2.1]")
+	if p := c.Policies(); p[0].FailOpen || !p[0].NextHops[0].Active || p[0].NextHops[1].Active {
+		t.Fatalf("expected 192.0.2.1 active, got %+v", p)
+	}
+
+	delete(cfg.SteeringPolicies, "dpi")
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "delete dpi")
+}
+
+func TestReconcileRetriesFailedPolicy(t *testing.T) {
+	c, sb, cfg := newTestComponent()
+	cfg.SteeringPolicies["dpi"].HealthCheck = nil
+	sb.err = errors.New("unknown access list")
+
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web [192.0.2.1 192.0.2.2]")
+	if p := c.Policies(); p[0].Error == "" || p[0].FailOpen {
+		t.Fatalf("expected programming error, got %+v", p)
+	}
+
+	sb.err = nil
+	c.reconcile(cfg.SteeringPolicies)
+	expectCalls(t, sb, "set dpi web [192.0.2.1 192.0.2.2]")
+	if p := c.Policies(); p[0].Error != "" || p[0].NextHops[0].State != models.SteeringHopUnchecked {
+		t.Fatalf("expected policy programmed, got %+v", p)
+	}
+}
+
+func TestRunHealthChecksMarksNextHopDown(t *testing.T) {
+	c, _, cfg := newTestComponent()
+	c.probe = func(_ context.Context, hop steeringcfg.NextHop, _ time.Duration) error {
+		if hop.Address == "192.0.2.1" {
+			return ErrProbeTimeout
+		}
+		return nil
+	}
+
+	c.runHealthChecks(context.Background(), time.Now(), cfg.SteeringPolicies)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		a, b := c.health["192.0.2.1"].state, c.health["192.0.2.2"].state
+		c.mu.Unlock()
+		if a == models.SteeringHopDown && b == models.SteeringHopUp {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("states = %s, %s", a, b)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	steeringcfg "github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+)
+
+var ErrProbeTimeout = errors.New("steering: next-hop health probe timed out")
+
+// hopHealth is the health-check state of one next hop, shared by the
+// policies that list it.
+type hopHealth struct {
+	state      string
+	probing    bool
+	nextProbe  time.Time
+	lastChange time.Time
+	lastError  string
+
+	consecutiveFailures  int
+	consecutiveSuccesses int
+}
+
+// runHealthChecks starts the probes that are due and forgets next hops
+// no longer under a health-checked policy. A next hop listed by several
+// policies is probed at the interval of the first, by name.
+func (c *Component) runHealthChecks(ctx context.Context, now time.Time, policies map[string]*steeringcfg.Policy) {
+	seen := make(map[string]bool)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range sortedNames(policies) {
+		p := policies[name]
+		if p.HealthCheck == nil {
+			continue
+		}
+		hc := p.HealthCheck.WithDefaults()
+		for _, hop := range p.NextHops {
+			key := hop.Key()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			h := c.health[key]
+			if h == nil {
+				h = &hopHealth{state: models.SteeringHopUnknown}
+				c.health[key] = h
+			}
+			if h.probing || now.Before(h.nextProbe) {
+				continue
+			}
+			h.probing = true
+			h.nextProbe = now.Add(hc.Interval)
+			go func() {
+				err := c.probe(ctx, hop, hc.Timeout)
+				c.recordProbe(key, err, hc)
+			}()
+		}
+	}
+	for key, h := range c.health {
+		if !seen[key] && !h.probing {
+			delete(c.health, key)
+		}
+	}
+}
+
+// recordProbe folds a probe outcome into the next hop's state.
+func (c *Component) recordProbe(key string, err error, hc steeringcfg.HealthCheck) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.health[key]
+	if h == nil {
+		return
+	}
+	h.probing = false
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err != nil {
+		h.consecutiveFailures++
+		h.consecutiveSuccesses = 0
+		h.lastError = err.Error()
+		if h.state != models.SteeringHopDown && h.consecutiveFailures >= hc.DownAfter {
+			h.state = models.SteeringHopDown
+			h.lastChange = now
+			c.logger.Warn("Steering next hop failed health check, marked down",
+				"next_hop", key, "failures", h.consecutiveFailures, "error", err)
+		}
+		return
+	}
+
+	h.consecutiveFailures = 0
+	h.consecutiveSuccesses++
+	h.lastError = ""
+	switch {
+	case h.state == models.SteeringHopUnknown:
+		h.state = models.SteeringHopUp
+		h.lastChange = now
+	case h.state == models.SteeringHopDown && h.consecutiveSuccesses >= hc.UpAfter:
+		h.state = models.SteeringHopUp
+		h.lastChange = now
+		c.logger.Info("Steering next hop passed health check, marked up", "next_hop", key)
+	}
+}
+
+// echoSeq numbers echo requests so concurrent probes, which share the
+// process's ICMP identifier, only accept their own reply.
+var echoSeq atomic.Uint32
+
+// echoProbe sends an ICMP echo request to the next hop from its VRF and
+// waits for the reply.
+func echoProbe(ctx context.Context, hop steeringcfg.NextHop, timeout time.Duration) error {
+	ip := hop.IP()
+	if ip == nil {
+		return fmt.Errorf("invalid next hop %q", hop.Address)
+	}
+	family, proto := netbind.FamilyV4, 1
+	var request, reply icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.To4() == nil {
+		family, proto = netbind.FamilyV6, 58
+		request, reply = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+
+	conn, err := netbind.ListenICMP(ctx, family, netbind.Binding{VRF: hop.VRF})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	id := os.Getpid() & 0xffff
+	seq := int(echoSeq.Add(1) & 0xffff)
+	msg := icmp.Message{Type: request, Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("osvbng")}}
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	if _, err := conn.WriteTo(b, &net.IPAddr{IP: ip}); err != nil {
+		return fmt.Errorf("send echo request: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				return ErrProbeTimeout
+			}
+			return err
+		}
+		if addr, ok := peer.(*net.IPAddr); !ok || !addr.IP.Equal(ip) {
+			continue
+		}
+		m, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || m.Type != reply {
+			continue
+		}
+		if echo, ok := m.Body.(*icmp.Echo); ok && echo.ID == id && echo.Seq == seq {
+			return nil
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/veesix-networks/osvbng/pkg/models"
 	"github.com/veesix-networks/osvbng/pkg/session"
 	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
 )
 
 type Component struct {
@@ -33,10 +34,15 @@ type Component struct {
 	cfgMgr    component.ConfigManager
 	cache     cache.Cache
 
+	// svcGroupResolver re-resolves sessions at schedule boundaries (see
+	// schedule.go).
+	svcGroupResolver *svcgroup.Resolver
+
 	lifecycleSub    events.Subscription
 	restoredSub     events.Subscription
 	programmedSub   events.Subscription
 	mutationResSub  events.Subscription
+	accessLineSub   events.Subscription
 	mutationWaiters sync.Map
 
 	// sw_if_index <-> session-id, maintained by persistSession and read by
@@ -57,6 +63,7 @@ func New(deps component.Dependencies, srgMgr ha.SRGProvider) (*Component, error)
 		vpp:              deps.Southbound,
 		cfgMgr:           deps.ConfigManager,
 		cache:            deps.Cache,
+		svcGroupResolver: deps.SvcGroupResolver,
 		sessionByIfIndex: make(map[uint32]string),
 		ifIndexBySession: make(map[string]uint32),
 	}
@@ -76,11 +83,16 @@ func (c *Component) Start(ctx context.Context) error {
 	c.restoredSub = c.eventBus.Subscribe(events.TopicSessionRestored, c.handleSessionRestored)
 	c.programmedSub = c.eventBus.Subscribe(events.TopicSessionProgrammed, c.handleSessionProgrammed)
 	c.mutationResSub = c.eventBus.Subscribe(events.TopicSubscriberMutationResult, c.handleMutationResult)
+	c.accessLineSub = c.eventBus.Subscribe(events.TopicAccessLine, c.handleAccessLine)
 
 	// Off the start path: the scan races nothing (lifecycle events index
 	// themselves) and only fills in sessions from before this process.
 	go c.warmSessionIfIndex(ctx)
 
+	if c.svcGroupResolver != nil {
+		c.Go(func() { c.scheduleLoop(c.Ctx) })
+	}
+
 	return nil
 }
 
@@ -97,6 +109,9 @@ func (c *Component) Stop(ctx context.Context) error {
 		c.programmedSub.Unsubscribe()
 	}
 	c.mutationResSub.Unsubscribe()
+	if c.accessLineSub != nil {
+		c.accessLineSub.Unsubscribe()
+	}
 
 	c.StopContext()
 
@@ -473,12 +488,11 @@ func (c *Component) handleSessionProgrammed(event events.Event) {
 
 // handleSessionRestored exists for completeness so the subscriber
 // component is still subscribed to TopicSessionRestored, but for the
-// IPoE / PPPoE access types it is a no-op: the publishing component's
-// setupSessionRestore already invoked pkg/svcgroup.ApplyToSession to
-// program QoS / ACL / uRPF, so re-running activateSession here would
-// double-apply. Other access types (currently only L2TP LNS) fall
-// through to activateSession because they don't yet adopt the
-// svcgroup.ApplyToSession SDK.
+// IPoE / PPPoE / L2TP LNS access types it is a no-op: the publishing
+// component's restore path already invoked pkg/svcgroup.ApplyToSession
+// to program QoS / ACL / uRPF, so re-running activateSession here would
+// double-apply. Access types that don't adopt the svcgroup.ApplyToSession
+// SDK fall through to activateSession.
 func (c *Component) handleSessionRestored(event events.Event) {
 	data, ok := event.Data.(*events.SessionRestoredEvent)
 	if !ok {
@@ -506,18 +520,17 @@ func (c *Component) handleSessionRestored(event events.Event) {
 // component's legacy activateSession path.
 //
 // Returns true for IPoE and PPPoE (non-LAC) — both invoke
-// svcgroup.ApplyToSession directly from setupSession. PPPoE-LAC
-// sessions take a different dataplane path (the L2TP side owns policy
-// on the tunnel interface, not the PPPoE session interface) and are
-// filtered out by the existing handleSessionLifecycle skip on
-// PhaseLACTunneled (no IfIndex set on the PPPoE side for those).
-//
-// Returns false for L2TP LNS, which has not adopted setupSession yet —
-// activateSession remains the QoS-apply path for those sessions until
-// it does.
+// svcgroup.ApplyToSession directly from setupSession — and for L2TP
+// LNS, which invokes it from programSessionVPP once NCP converges and
+// again on restore. PPPoE-LAC sessions take a different dataplane path
+// (the L2TP side owns policy on the tunnel interface, not the PPPoE
+// session interface) and are filtered out by the existing
+// handleSessionLifecycle skip on PhaseLACTunneled (no IfIndex set on
+// the PPPoE side for those); LAC-role L2TP sessions never publish on
+// the lifecycle topic.
 func isUnifiedSetupAccessType(t models.AccessType) bool {
 	switch t {
-	case models.AccessTypeIPoE, models.AccessTypePPPoE:
+	case models.AccessTypeIPoE, models.AccessTypePPPoE, models.AccessTypeL2TP:
 		return true
 	}
 	return false
@@ -529,13 +542,12 @@ func (c *Component) activateSession(sess models.SubscriberSession) error {
 		return nil
 	}
 
-	// HA-restored sessions and L2TP-LNS sessions still flow through
-	// activateSession because their bring-up paths don't yet invoke
-	// pkg/svcgroup.ApplyToSession. For IPoE / PPPoE (non-LAC) on the
-	// fresh + opdb-restore paths, setupSession already programmed the
-	// service-group bindings via svcgroup.ApplyToSession; running this
-	// path again would double-apply. Skip and let the publisher's
-	// direct apply stand.
+	// Only access types whose bring-up paths don't invoke
+	// pkg/svcgroup.ApplyToSession flow through activateSession. For
+	// IPoE / PPPoE (non-LAC) and L2TP LNS on the fresh + restore paths,
+	// the publisher already programmed the service-group bindings via
+	// svcgroup.ApplyToSession; running this path again would
+	// double-apply. Skip and let the publisher's direct apply stand.
 	if accessType := sess.GetAccessType(); isUnifiedSetupAccessType(accessType) {
 		c.logger.Debug("Skipping subscriber.activateSession; setupSession already applied svcgroup bindings",
 			"session_id", sess.GetSessionID(),
@@ -67,6 +67,7 @@ var allowedMutationAttrs = map[string]struct{}{
 	aaa.AttrQoSDownloadRate:     {},
 	aaa.AttrRateLimitUp:         {},
 	aaa.AttrRateLimitDown:       {},
+	aaa.AttrBlackhole:           {},
 }
 
 func validateAttributes(attrs map[string]string) (int, error) {
@@ -298,6 +299,19 @@ func (c *Component) handleMutationResult(ev events.Event) {
 	}
 }
 
+// handleAccessLine stores a session whose line rates an access component
+// updated on renew, so a schedule boundary re-resolving it shapes to the
+// rates now in force.
+func (c *Component) handleAccessLine(ev events.Event) {
+	data, ok := ev.Data.(*events.AccessLineEvent)
+	if !ok || data.Session == nil {
+		return
+	}
+	if err := c.persistSession(data.Session); err != nil {
+		c.logger.Warn("Failed to persist session with new line rates", "session_id", data.SessionID, "error", err)
+	}
+}
+
 func makeFailedResults(targets []Target, errCause int, errMsg string) []TargetResult {
 	results := make([]TargetResult, len(targets))
 	for i, t := range targets {
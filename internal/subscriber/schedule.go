@@ -0,0 +1,222 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package subscriber
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+// scheduleRecheck bounds how long the schedule loop sleeps between
+// evaluations, so a schedule added or edited by a commit takes effect
+// within it rather than at its next boundary.
+const scheduleRecheck = time.Minute
+
+// scheduleLoop moves sessions between their scheduled bindings. It
+// wakes at each schedule boundary, works out which schedules opened or
+// closed, and re-resolves every session's service group with the old
+// and the new active sets; sessions whose bindings differ are moved over
+// in batches, the same way mutation events are paced, so a boundary that
+// touches every subscriber does not stall the dataplane API.
+//
+// Sessions that come up during a window need nothing from here: the
+// access components resolve their service group with the schedules
+// active at the time.
+func (c *Component) scheduleLoop(ctx context.Context) {
+	active := c.svcGroupResolver.ActiveSchedules(time.Now())
+
+	for {
+		wait := scheduleRecheck
+		if next := c.svcGroupResolver.NextTransition(time.Now()); !next.IsZero() {
+			if d := time.Until(next); d < wait {
+				wait = d
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		current := c.svcGroupResolver.ActiveSchedules(time.Now())
+		opened, closed := diffSchedules(active, current)
+		if len(opened) == 0 && len(closed) == 0 {
+			continue
+		}
+		c.applyScheduleTransition(ctx, active, current, opened, closed)
+		active = current
+	}
+}
+
+// applyScheduleTransition moves every live session whose bindings differ
+// between the before and after active sets.
+func (c *Component) applyScheduleTransition(ctx context.Context, before, after, opened, closed []string) {
+	cfg, err := c.cfgMgr.GetRunning()
+	if err != nil || cfg == nil {
+		c.logger.Warn("Schedule transition skipped: no running config",
+			"opened", opened, "closed", closed, "error", err)
+		return
+	}
+
+	sessions, err := c.GetSessions(ctx, "", "", 0)
+	if err != nil {
+		c.logger.Warn("Schedule transition skipped: cannot list sessions",
+			"opened", opened, "closed", closed, "error", err)
+		return
+	}
+
+	var moved, failed, batch int
+	for _, sess := range sessions {
+		if ctx.Err() != nil {
+			return
+		}
+		if !c.scheduledSession(sess) {
+			continue
+		}
+
+		name := sess.GetServiceGroup()
+		attrs := resolverAttributes(sess)
+		from := c.svcGroupResolver.ResolveWith(name, name, attrs, before)
+		to := c.svcGroupResolver.ResolveWith(name, name, attrs, after)
+		if svcgroup.SameBindings(from, to, cfg.QoSPolicies) {
+			continue
+		}
+
+		// Pace the dataplane calls like bucketed mutation events.
+		if batch == defaultBucketSize {
+			batch = 0
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(defaultBucketInterval):
+			}
+		}
+		batch++
+
+		swIfIndex := sess.GetIfIndex()
+		if err := svcgroup.ReapplyToSession(c.vpp, swIfIndex, from, to, cfg.QoSPolicies); err != nil {
+			failed++
+			c.logger.Warn("Failed to move session to scheduled bindings",
+				"session_id", sess.GetSessionID(), "sw_if_index", swIfIndex,
+				"service_group", name, "error", err)
+			continue
+		}
+		moved++
+
+		schedules := sessionSchedules(cfg, to)
+		c.logger.Debug("Moved session to scheduled bindings",
+			"session_id", sess.GetSessionID(), "sw_if_index", swIfIndex,
+			"service_group", name, "schedules", schedules)
+		c.eventBus.Publish(events.TopicServiceSchedule, events.Event{
+			Source:    c.Name(),
+			Timestamp: time.Now(),
+			Data: &events.ServiceScheduleEvent{
+				SessionID:    sess.GetSessionID(),
+				ServiceGroup: name,
+				Schedules:    schedules,
+			},
+		})
+	}
+
+	c.logger.Info("Schedule transition",
+		"opened", opened, "closed", closed, "active", after,
+		"sessions_moved", moved, "sessions_failed", failed)
+}
+
+// scheduledSession reports whether a session's bindings follow its
+// service group's schedules: live IPoE, PPPoE and L2TP LNS sessions on
+// this node. Their bindings come from svcgroup.ApplyToSession; other
+// access types are programmed by paths that do not resolve schedules.
+func (c *Component) scheduledSession(sess models.SubscriberSession) bool {
+	if !isUnifiedSetupAccessType(sess.GetAccessType()) {
+		return false
+	}
+	if sess.GetState() != models.SessionStateActive || sess.GetIfIndex() == 0 || sess.GetServiceGroup() == "" {
+		return false
+	}
+	if c.srgMgr != nil && !c.srgMgr.IsActive(sess.GetSRGName()) {
+		return false
+	}
+	return true
+}
+
+// resolverAttributes returns a session's AAA attributes in the form the
+// service group resolver takes, as the access components' restore paths
+// pass them.
+func resolverAttributes(sess models.SubscriberSession) map[string]interface{} {
+	var attrs map[string]string
+	switch s := sess.(type) {
+	case *models.IPoESession:
+		attrs = s.Attributes
+	case *models.PPPSession:
+		attrs = s.Attributes
+	case *models.PPPoL2TPSession:
+		attrs = s.Attributes
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
+
+// sessionSchedules returns the active schedules that sg's bindings
+// follow: those its service group overrides on and those its QoS
+// policies switch on.
+func sessionSchedules(cfg *config.Config, sg svcgroup.ServiceGroup) []string {
+	follows := map[string]bool{}
+	if g := cfg.ServiceGroups[sg.Name]; g != nil {
+		for _, s := range g.Schedules {
+			follows[s.Schedule] = true
+		}
+	}
+	for _, name := range []string{sg.QoSIngress, sg.QoSEgress} {
+		if p := cfg.QoSPolicies[name]; p != nil {
+			for _, s := range p.Schedules {
+				follows[s.Schedule] = true
+			}
+		}
+	}
+
+	var out []string
+	for _, name := range sg.Schedules {
+		if follows[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// diffSchedules returns the schedules in after but not before, and the
+// reverse. Both inputs are sorted.
+func diffSchedules(before, after []string) (opened, closed []string) {
+	in := func(list []string, name string) bool {
+		i := sort.SearchStrings(list, name)
+		return i < len(list) && list[i] == name
+	}
+	for _, name := range after {
+		if !in(before, name) {
+			opened = append(opened, name)
+		}
+	}
+	for _, name := range before {
+		if !in(after, name) {
+			closed = append(closed, name)
+		}
+	}
+	return opened, closed
+}
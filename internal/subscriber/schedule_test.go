@@ -0,0 +1,138 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package subscriber
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/cache/memory"
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+	"github.com/veesix-networks/osvbng/pkg/events"
+	"github.com/veesix-networks/osvbng/pkg/events/local"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+type fakeCfg struct{ cfg *config.Config }
+
+func (f *fakeCfg) GetRunning() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) GetStartup() (*config.Config, error) { return f.cfg, nil }
+func (f *fakeCfg) LookupSubscriberGroup(svlan, cvlan uint16) (subscriber.GroupMatch, bool) {
+	return subscriber.GroupMatch{}, false
+}
+
+type fakeScheduleSB struct {
+	southbound.Southbound
+	mu     sync.Mutex
+	egress map[uint32]uint32
+}
+
+func (f *fakeScheduleSB) ApplyQoS(swIfIndex uint32, ingress, egress *qos.Policy) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if egress != nil {
+		f.egress[swIfIndex] = egress.CIR
+	}
+	return nil
+}
+
+func (f *fakeScheduleSB) RemoveQoS(swIfIndex uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.egress, swIfIndex)
+	return nil
+}
+
+func (f *fakeScheduleSB) RemoveQoSClasses(uint32) error { return nil }
+func (f *fakeScheduleSB) RemoveScheduler(uint32) error  { return nil }
+func (f *fakeScheduleSB) RemoveMarking(uint32) error    { return nil }
+
+func TestScheduleTransitionMovesFollowingSessions(t *testing.T) {
+	policies := map[string]*qos.Policy{
+		"plan-100m": {CIR: 100_000, Schedules: []qos.PolicySchedule{{Schedule: "night", Policy: "plan-1g"}}},
+		"plan-1g":   {CIR: 1_000_000},
+		"flat":      {CIR: 50_000},
+	}
+	cfg := &config.Config{
+		QoSPolicies: policies,
+		ServiceGroups: map[string]*servicegroup.Config{
+			"boost": {QoS: &servicegroup.QoSConfig{EgressPolicy: "plan-100m"}},
+			"flat":  {QoS: &servicegroup.QoSConfig{EgressPolicy: "flat"}},
+		},
+	}
+
+	resolver := svcgroup.New()
+	resolver.SetSchedule("night", &schedule.Schedule{Timezone: "UTC", Windows: []schedule.Window{{Start: "00:00", End: "06:00"}}})
+	for name, sg := range cfg.ServiceGroups {
+		resolver.Set(name, sg)
+	}
+
+	bus := local.NewBus()
+	evCh := make(chan *events.ServiceScheduleEvent, 8)
+	bus.Subscribe(events.TopicServiceSchedule, func(ev events.Event) {
+		evCh <- ev.Data.(*events.ServiceScheduleEvent)
+	})
+
+	sb := &fakeScheduleSB{egress: map[uint32]uint32{}}
+	c := newProgrammedTestComponent()
+	c.vpp = sb
+	c.eventBus = bus
+	c.cfgMgr = &fakeCfg{cfg: cfg}
+	c.svcGroupResolver = resolver
+	c.cache = memory.New()
+
+	for i, sg := range []string{"boost", "flat"} {
+		sess := &models.IPoESession{
+			SessionID:    fmt.Sprintf("s-%s", sg),
+			State:        models.SessionStateActive,
+			AccessType:   string(models.AccessTypeIPoE),
+			IfIndex:      uint32(10 + i),
+			OuterVLAN:    100,
+			ServiceGroup: sg,
+		}
+		if err := c.persistSession(sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.applyScheduleTransition(context.Background(), nil, []string{"night"}, []string{"night"}, nil)
+
+	if got := sb.egress[10]; got != 1_000_000 {
+		t.Fatalf("boost session egress CIR = %d, want the night policy", got)
+	}
+	if _, touched := sb.egress[11]; touched {
+		t.Fatal("session whose bindings do not follow the schedule was reprogrammed")
+	}
+
+	select {
+	case ev := <-evCh:
+		if ev.SessionID != "s-boost" || len(ev.Schedules) != 1 || ev.Schedules[0] != "night" {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no schedule event for the moved session")
+	}
+	select {
+	case ev := <-evCh:
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+}
+
+func TestDiffSchedules(t *testing.T) {
+	opened, closed := diffSchedules([]string{"night", "weekend"}, []string{"peak", "weekend"})
+	if len(opened) != 1 || opened[0] != "peak" || len(closed) != 1 || closed[0] != "night" {
+		t.Fatalf("opened %v, closed %v", opened, closed)
+	}
+}
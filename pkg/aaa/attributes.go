@@ -33,6 +33,10 @@ const (
 	AttrUsername            = "username"
 	AttrIPv4Profile         = "ipv4-profile"
 	AttrIPv6Profile         = "ipv6-profile"
+	AttrSteeringPolicy      = "steering-policy"
+	AttrMarkingIngress      = "marking.ingress"
+	AttrMarkingIngressMap   = "marking.ingress-map"
+	AttrMarkingEgressPCPMap = "marking.egress-pcp-map"
 )
 
 const (
@@ -75,3 +79,42 @@ const (
 	AttrL2GWSVLAN        = "l2gw.svlan"
 	AttrL2GWCVLAN        = "l2gw.cvlan"
 )
+
+// NPTv6 attributes. The internal prefix, when an auth provider returns
+// it, overrides the service group's nptv6 internal-prefix. Accounting
+// reports the programmed translation under both names.
+const (
+	AttrNPTv6InternalPrefix = "nptv6.internal-prefix"
+	AttrNPTv6ExternalPrefix = "nptv6.external-prefix"
+)
+
+// Schedule attributes. Accounting reports the schedules a session's
+// bindings follow that are active, comma-separated, from the
+// Interim-Update sent at each schedule boundary on.
+const (
+	AttrServiceSchedules = "service.schedules"
+)
+
+// Access-loop characteristics (Broadband Forum TR-101) as the access
+// node reports them in DHCP option 82 or the PPPoE vendor-specific tag,
+// decimal kbps. The access components add them to the AAA request and
+// keep them with the session, so accounting reports the current rates;
+// an auth provider that returns them overrides what was reported.
+const (
+	AttrAccessLineActualRateUp       = "access-line.actual-rate-up"
+	AttrAccessLineActualRateDown     = "access-line.actual-rate-down"
+	AttrAccessLineMinRateUp          = "access-line.min-rate-up"
+	AttrAccessLineMinRateDown        = "access-line.min-rate-down"
+	AttrAccessLineAttainableRateUp   = "access-line.attainable-rate-up"
+	AttrAccessLineAttainableRateDown = "access-line.attainable-rate-down"
+	AttrAccessLineMaxRateUp          = "access-line.max-rate-up"
+	AttrAccessLineMaxRateDown        = "access-line.max-rate-down"
+)
+
+// Blackhole attribute. In a CoA or API mutation it blackholes the
+// session's addresses and delegated prefix for the given duration, a Go
+// duration or seconds, capped at the rtbh max-duration. 0 lifts the
+// blackholes.
+const (
+	AttrBlackhole = "rtbh.duration"
+)
@@ -0,0 +1,228 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package accessline parses the access-loop characteristics a DSL or
+// GPON access node inserts into the subscriber's DHCP and PPPoE
+// discovery traffic (Broadband Forum TR-101, RFC 4679 section 3): the
+// data rates the line is trained at. The same sub-options are carried
+// in the PPPoE vendor-specific tag under the BBF enterprise number, and
+// in DHCP option 82 inside the vendor-specific information sub-option.
+package accessline
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+)
+
+// VendorIDBBF is the Broadband Forum (formerly ADSL Forum) enterprise
+// number the access-loop sub-options are carried under.
+const VendorIDBBF uint32 = 3561
+
+// Option82SubOptVendor is the DHCP relay agent vendor-specific
+// information sub-option (RFC 4243).
+const Option82SubOptVendor uint8 = 9
+
+// Access-loop characteristic sub-option types (RFC 4679 section 3).
+// Each rate is a 4-byte value in kbps.
+const (
+	SubOptActualRateUp       uint8 = 0x81
+	SubOptActualRateDown     uint8 = 0x82
+	SubOptMinRateUp          uint8 = 0x83
+	SubOptMinRateDown        uint8 = 0x84
+	SubOptAttainableRateUp   uint8 = 0x85
+	SubOptAttainableRateDown uint8 = 0x86
+	SubOptMaxRateUp          uint8 = 0x87
+	SubOptMaxRateDown        uint8 = 0x88
+)
+
+// Characteristics are the rates an access node reports for a line, in
+// kbps. Zero means not reported.
+type Characteristics struct {
+	ActualRateUp       uint32
+	ActualRateDown     uint32
+	MinRateUp          uint32
+	MinRateDown        uint32
+	AttainableRateUp   uint32
+	AttainableRateDown uint32
+	MaxRateUp          uint32
+	MaxRateDown        uint32
+}
+
+type field struct {
+	subOpt uint8
+	attr   string
+	value  *uint32
+}
+
+// fields pairs each characteristic with its sub-option and attribute.
+func (c *Characteristics) fields() []field {
+	return []field{
+		{SubOptActualRateUp, aaa.AttrAccessLineActualRateUp, &c.ActualRateUp},
+		{SubOptActualRateDown, aaa.AttrAccessLineActualRateDown, &c.ActualRateDown},
+		{SubOptMinRateUp, aaa.AttrAccessLineMinRateUp, &c.MinRateUp},
+		{SubOptMinRateDown, aaa.AttrAccessLineMinRateDown, &c.MinRateDown},
+		{SubOptAttainableRateUp, aaa.AttrAccessLineAttainableRateUp, &c.AttainableRateUp},
+		{SubOptAttainableRateDown, aaa.AttrAccessLineAttainableRateDown, &c.AttainableRateDown},
+		{SubOptMaxRateUp, aaa.AttrAccessLineMaxRateUp, &c.MaxRateUp},
+		{SubOptMaxRateDown, aaa.AttrAccessLineMaxRateDown, &c.MaxRateDown},
+	}
+}
+
+// Parse reads the access-loop sub-options out of a run of one-byte
+// type, one-byte length sub-options, as they follow the enterprise
+// number in either carrier. Other sub-options, such as the circuit and
+// remote IDs, are skipped, as is a rate of the wrong length.
+func Parse(data []byte) Characteristics {
+	var c Characteristics
+	fields := c.fields()
+
+	for offset := 0; offset+2 <= len(data); {
+		subType := data[offset]
+		subLen := int(data[offset+1])
+		offset += 2
+		if offset+subLen > len(data) {
+			break
+		}
+		value := data[offset : offset+subLen]
+		offset += subLen
+
+		if subLen != 4 {
+			continue
+		}
+		for _, f := range fields {
+			if f.subOpt == subType {
+				*f.value = binary.BigEndian.Uint32(value)
+				break
+			}
+		}
+	}
+	return c
+}
+
+// ParseOption82 reads the access-loop sub-options out of the value of a
+// DHCPv4 option 82. They are carried under the BBF enterprise number in
+// the vendor-specific information sub-option, which holds a run of
+// enterprise number, one-byte length and data (RFC 4243).
+func ParseOption82(opt82 []byte) Characteristics {
+	for i := 0; i+2 <= len(opt82); {
+		subType := opt82[i]
+		subLen := int(opt82[i+1])
+		i += 2
+		if i+subLen > len(opt82) {
+			break
+		}
+		value := opt82[i : i+subLen]
+		i += subLen
+
+		if subType != Option82SubOptVendor {
+			continue
+		}
+		for j := 0; j+5 <= len(value); {
+			enterprise := binary.BigEndian.Uint32(value[j : j+4])
+			dataLen := int(value[j+4])
+			j += 5
+			if j+dataLen > len(value) {
+				break
+			}
+			if enterprise == VendorIDBBF {
+				return Parse(value[j : j+dataLen])
+			}
+			j += dataLen
+		}
+	}
+	return Characteristics{}
+}
+
+// IsZero reports whether no characteristic was reported.
+func (c Characteristics) IsZero() bool {
+	return c == Characteristics{}
+}
+
+// Attributes returns the reported characteristics as AAA attributes,
+// decimal kbps, or nil if none were reported.
+func (c Characteristics) Attributes() map[string]string {
+	if c.IsZero() {
+		return nil
+	}
+	attrs := make(map[string]string)
+	for _, f := range c.fields() {
+		if *f.value != 0 {
+			attrs[f.attr] = strconv.FormatUint(uint64(*f.value), 10)
+		}
+	}
+	return attrs
+}
+
+// FromAttributes reads characteristics back from AAA attributes, as a
+// restored session stores them.
+func FromAttributes(attrs map[string]string) Characteristics {
+	var c Characteristics
+	for _, f := range c.fields() {
+		if v, ok := attrs[f.attr]; ok {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err == nil {
+				*f.value = uint32(n)
+			}
+		}
+	}
+	return c
+}
+
+// ResolverAttributes returns attrs with the reported characteristics
+// added where attrs has none, in the form the service group resolver
+// takes. AAA can so override what the access node reported. attrs is
+// not modified.
+func (c Characteristics) ResolverAttributes(attrs map[string]interface{}) map[string]interface{} {
+	line := c.Attributes()
+	if len(line) == 0 {
+		return attrs
+	}
+	out := make(map[string]interface{}, len(attrs)+len(line))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	for k, v := range line {
+		if _, ok := out[k]; !ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Update returns attrs with the attributes reported as old replaced by
+// those of c, as a renew reporting new rates changes them. An attribute
+// whose value is not the one old reported was returned by AAA and is
+// kept. attrs is not modified.
+func (c Characteristics) Update(attrs map[string]string, old Characteristics) map[string]string {
+	reported := old.Attributes()
+	out := make(map[string]string, len(attrs)+len(reported))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	for _, f := range c.fields() {
+		if v, ok := out[f.attr]; ok && v != reported[f.attr] {
+			continue
+		}
+		if *f.value == 0 {
+			delete(out, f.attr)
+			continue
+		}
+		out[f.attr] = strconv.FormatUint(uint64(*f.value), 10)
+	}
+	return out
+}
+
+// AttributeNames returns the names of the AAA attributes
+// characteristics are reported under.
+func AttributeNames() []string {
+	var c Characteristics
+	fields := c.fields()
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.attr
+	}
+	return names
+}
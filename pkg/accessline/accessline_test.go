@@ -0,0 +1,93 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package accessline
+
+import (
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+)
+
+func TestParseOption82(t *testing.T) {
+	opt82 := []byte{
+		0x01, 0x02, 'c', '1', // circuit-id
+		0x09, 0x1A, // vendor-specific information
+		0x00, 0x00, 0x00, 0x09, 0x01, 0xFF, // another enterprise first
+		0x00, 0x00, 0x0D, 0xE9, 0x0F,
+		0x81, 0x04, 0x00, 0x00, 0x04, 0x00,
+		0x82, 0x04, 0x00, 0x00, 0x40, 0x00,
+		0x90, 0x01, 0x00, // not a rate
+	}
+
+	c := ParseOption82(opt82)
+	if c.ActualRateUp != 1024 || c.ActualRateDown != 16384 {
+		t.Fatalf("got %+v", c)
+	}
+	if c.MaxRateDown != 0 {
+		t.Fatalf("unreported rate set: %+v", c)
+	}
+}
+
+func TestParseOption82WithoutBBF(t *testing.T) {
+	if c := ParseOption82([]byte{0x01, 0x02, 'c', '1', 0x02, 0x01, 'r'}); !c.IsZero() {
+		t.Fatalf("got %+v", c)
+	}
+}
+
+func TestParseSkipsMalformedRate(t *testing.T) {
+	c := Parse([]byte{0x81, 0x02, 0x04, 0x00, 0x82, 0x04, 0x00, 0x00, 0x40})
+	if !c.IsZero() {
+		t.Fatalf("got %+v", c)
+	}
+}
+
+func TestAttributesRoundTrip(t *testing.T) {
+	c := Characteristics{ActualRateUp: 1024, ActualRateDown: 16384, AttainableRateDown: 20000}
+	attrs := c.Attributes()
+	if attrs[aaa.AttrAccessLineActualRateDown] != "16384" || len(attrs) != 3 {
+		t.Fatalf("attrs = %v", attrs)
+	}
+	if got := FromAttributes(attrs); got != c {
+		t.Fatalf("round trip = %+v", got)
+	}
+	if (Characteristics{}).Attributes() != nil {
+		t.Fatal("empty characteristics produced attributes")
+	}
+}
+
+func TestResolverAttributesKeepsAAA(t *testing.T) {
+	c := Characteristics{ActualRateUp: 1024, ActualRateDown: 16384}
+	in := map[string]interface{}{aaa.AttrAccessLineActualRateDown: "8000"}
+
+	out := c.ResolverAttributes(in)
+	if out[aaa.AttrAccessLineActualRateDown] != "8000" || out[aaa.AttrAccessLineActualRateUp] != "1024" {
+		t.Fatalf("out = %v", out)
+	}
+	if len(in) != 1 {
+		t.Fatal("input modified")
+	}
+}
+
+func TestUpdateKeepsAAAValues(t *testing.T) {
+	old := Characteristics{ActualRateUp: 1024, ActualRateDown: 16384, MaxRateDown: 24000}
+	attrs := old.Attributes()
+	attrs[aaa.AttrAccessLineActualRateUp] = "512" // returned by AAA
+	attrs["username"] = "sub1"
+
+	c := Characteristics{ActualRateUp: 2048, ActualRateDown: 12000}
+	out := c.Update(attrs, old)
+	if out[aaa.AttrAccessLineActualRateUp] != "512" {
+		t.Errorf("AAA value replaced: %v", out)
+	}
+	if out[aaa.AttrAccessLineActualRateDown] != "12000" {
+		t.Errorf("reported value not updated: %v", out)
+	}
+	if _, ok := out[aaa.AttrAccessLineMaxRateDown]; ok {
+		t.Errorf("rate no longer reported kept: %v", out)
+	}
+	if out["username"] != "sub1" || attrs[aaa.AttrAccessLineActualRateDown] != "16384" {
+		t.Errorf("unrelated attribute lost or input modified: %v / %v", out, attrs)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net"
 
 	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
 )
 
 type Context struct {
@@ -27,6 +28,13 @@ type Context struct {
 	DNSv4 []net.IP
 	DNSv6 []net.IP
 
+	// AFTRName is the DS-Lite AFTR-Name (DHCPv6 option 64) of the
+	// service group's CGNAT policy, set when the policy is a DS-Lite pool.
+	AFTRName string
+	// MAP is the MAP-E/MAP-T softwire configuration (DHCPv6 option 94
+	// or 95) of the service group's MAP domain.
+	MAP *cgnat.MAPParams
+
 	PoolOverride     string
 	IANAPoolOverride string
 	PDPoolOverride   string
@@ -0,0 +1,253 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+var (
+	ErrPoolExists  = errors.New("pool already exists")
+	ErrPoolInUse   = errors.New("pool has active leases")
+	ErrPoolOverlap = errors.New("pool overlaps an existing pool")
+)
+
+// AddIPv4Pool adds a pool to a profile at runtime, behind the pools it
+// already has. The pool's network and broadcast addresses are never
+// allocated, nor is gateway when it falls inside the pool. A pool whose
+// network overlaps another pool of the same address family in its VRF,
+// of any profile, is refused with ErrPoolOverlap.
+func (r *Registry) AddIPv4Pool(profileName string, pool ip.IPv4Pool, gateway string) error {
+	alloc, err := newRangeAllocator(pool.Network, gateway)
+	if err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := profileName + "/" + pool.Name
+	if _, exists := r.allocators[key]; exists {
+		return ErrPoolExists
+	}
+	if err := r.checkOverlapLocked(pool.Network, pool.VRF); err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+	alloc.SetDirection(!r.descending)
+	r.allocators[key] = alloc
+	r.setNetwork(PoolFamilyIPv4, key, pool.Network)
+	r.profilePools[profileName] = append(r.profilePools[profileName], key)
+	if pool.VRF != "" {
+		r.poolVRFs[key] = pool.VRF
+	}
+	return nil
+}
+
+// AddIANAPool is AddIPv4Pool for an IANA pool.
+func (r *Registry) AddIANAPool(profileName string, pool ip.IANAPool) error {
+	alloc, err := newRangeAllocator(pool.Network, pool.Gateway)
+	if err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := profileName + "/" + pool.Name
+	if _, exists := r.ianaAllocators[key]; exists {
+		return ErrPoolExists
+	}
+	if err := r.checkOverlapLocked(pool.Network, pool.VRF); err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+	alloc.SetDirection(!r.descending)
+	r.ianaAllocators[key] = alloc
+	r.setNetwork(PoolFamilyIANA, key, pool.Network)
+	r.profileIANAPools[profileName] = append(r.profileIANAPools[profileName], key)
+	if pool.VRF != "" {
+		r.poolVRFs[key] = pool.VRF
+	}
+	return nil
+}
+
+// AddPDPool is AddIPv4Pool for a PD pool.
+func (r *Registry) AddPDPool(profileName string, pool ip.PDPool) error {
+	prefix, err := netip.ParsePrefix(pool.Network)
+	if err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+	alloc := NewPrefixAllocator(prefix, int(pool.PrefixLength))
+	if alloc == nil {
+		return fmt.Errorf("pool %s: cannot delegate /%d from %s", pool.Name, pool.PrefixLength, prefix)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := profileName + "/" + pool.Name
+	if _, exists := r.pdAllocators[key]; exists {
+		return ErrPoolExists
+	}
+	if err := r.checkOverlapLocked(pool.Network, pool.VRF); err != nil {
+		return fmt.Errorf("pool %s: %w", pool.Name, err)
+	}
+	alloc.SetDirection(!r.descending)
+	r.pdAllocators[key] = alloc
+	r.setNetwork(PoolFamilyPD, key, pool.Network)
+	r.profilePDPools[profileName] = append(r.profilePDPools[profileName], key)
+	if pool.VRF != "" {
+		r.poolVRFs[key] = pool.VRF
+	}
+	return nil
+}
+
+// RemovePool removes a pool added at runtime. It refuses, with
+// ErrPoolInUse, while anything is still allocated from the pool.
+func (r *Registry) RemovePool(family PoolFamily, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profileName, _, _ := strings.Cut(key, "/")
+	switch family {
+	case PoolFamilyIPv4:
+		alloc, ok := r.allocators[key]
+		if !ok {
+			return nil
+		}
+		if alloc.Available() < alloc.Size() {
+			return ErrPoolInUse
+		}
+		delete(r.allocators, key)
+		r.profilePools[profileName] = removeKey(r.profilePools[profileName], key)
+	case PoolFamilyIANA:
+		alloc, ok := r.ianaAllocators[key]
+		if !ok {
+			return nil
+		}
+		if alloc.Available() < alloc.Size() {
+			return ErrPoolInUse
+		}
+		delete(r.ianaAllocators, key)
+		r.profileIANAPools[profileName] = removeKey(r.profileIANAPools[profileName], key)
+	case PoolFamilyPD:
+		alloc, ok := r.pdAllocators[key]
+		if !ok {
+			return nil
+		}
+		if alloc.Available() < alloc.Size() {
+			return ErrPoolInUse
+		}
+		delete(r.pdAllocators, key)
+		r.profilePDPools[profileName] = removeKey(r.profilePDPools[profileName], key)
+	default:
+		return fmt.Errorf("unknown pool family %q", family)
+	}
+
+	delete(r.poolVRFs, key)
+	delete(r.networks, poolRef{family, key})
+	delete(r.thresholds, poolRef{family, key})
+	delete(r.levels, poolRef{family, key})
+	delete(r.empty, poolRef{family, key})
+	return nil
+}
+
+// ProfileUsage sums the size and free count of a profile's pools of
+// family in vrf.
+func (r *Registry) ProfileUsage(family PoolFamily, profileName, vrf string) (size, available int) {
+	if r == nil {
+		return 0, 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []string
+	switch family {
+	case PoolFamilyIPv4:
+		keys = r.profilePools[profileName]
+	case PoolFamilyIANA:
+		keys = r.profileIANAPools[profileName]
+	case PoolFamilyPD:
+		keys = r.profilePDPools[profileName]
+	}
+	for _, key := range keys {
+		if r.poolVRFs[key] != vrf {
+			continue
+		}
+		if u, ok := r.usageLocked(poolRef{family, key}); ok {
+			size += u.Size
+			available += u.Available
+		}
+	}
+	return size, available
+}
+
+// setNetwork records the network of a pool for overlap checks.
+func (r *Registry) setNetwork(family PoolFamily, key, network string) {
+	if prefix, err := netip.ParsePrefix(network); err == nil {
+		r.networks[poolRef{family, key}] = prefix.Masked()
+	}
+}
+
+// checkOverlapLocked fails with ErrPoolOverlap when network overlaps
+// the network of any pool of the same address family in vrf. IANA and
+// PD pools share IPv6 space, so they are checked against each other.
+func (r *Registry) checkOverlapLocked(network, vrf string) error {
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return err
+	}
+	for ref, other := range r.networks {
+		if other.Addr().Is4() != prefix.Addr().Is4() || r.poolVRFs[ref.key] != vrf {
+			continue
+		}
+		if other.Overlaps(prefix) {
+			return fmt.Errorf("%w: %s overlaps %s (%s)", ErrPoolOverlap, prefix, ref.key, other)
+		}
+	}
+	return nil
+}
+
+// newRangeAllocator allocates every host address of network, less the
+// first and last, and gateway.
+func newRangeAllocator(network, gateway string) (*PoolAllocator, error) {
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return nil, err
+	}
+	prefix = prefix.Masked()
+
+	var exclude []netip.Addr
+	if gw, err := netip.ParseAddr(gateway); err == nil && prefix.Contains(gw) {
+		exclude = append(exclude, gw)
+	}
+
+	first := prefix.Addr().Next()
+	last := prevAddr(lastAddr(prefix))
+	if !first.IsValid() || !last.IsValid() || first.Compare(last) > 0 {
+		return nil, fmt.Errorf("%s has no host addresses", prefix)
+	}
+	return NewPoolAllocator(first, last, exclude), nil
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 0x80 >> (bit % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+func removeKey(keys []string, key string) []string {
+	out := keys[:0]
+	for _, k := range keys {
+		if k != key {
+			out = append(out, k)
+		}
+	}
+	return out
+}
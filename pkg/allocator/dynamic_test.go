@@ -0,0 +1,127 @@
+package allocator
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+func TestRegistryAddIPv4Pool(t *testing.T) {
+	profiles := map[string]*ip.IPv4Profile{
+		"prof1": makeV4Profile("10.0.0.1", ip.IPv4Pool{
+			Name:       "static",
+			Network:    "10.0.0.0/24",
+			RangeStart: "10.0.0.2",
+			RangeEnd:   "10.0.0.2",
+		}),
+	}
+	r := newRegistry(profiles, nil)
+
+	if err := r.AddIPv4Pool("prof1", ip.IPv4Pool{Name: "chunk", Network: "100.64.0.0/29"}, "10.0.0.1"); err != nil {
+		t.Fatalf("AddIPv4Pool: %v", err)
+	}
+	if err := r.AddIPv4Pool("prof1", ip.IPv4Pool{Name: "chunk", Network: "100.64.0.0/29"}, ""); !errors.Is(err, ErrPoolExists) {
+		t.Fatalf("second add: got %v, want ErrPoolExists", err)
+	}
+
+	size, available := r.ProfileUsage(PoolFamilyIPv4, "prof1", "")
+	if size != 7 || available != 7 {
+		t.Fatalf("usage = %d/%d, want 7/7", size, available)
+	}
+
+	if _, key, _ := r.AllocateFromProfile("prof1", "", "", "s1"); key != "prof1/static" {
+		t.Fatalf("first allocation from %s, want the configured pool", key)
+	}
+	addr, key, err := r.AllocateFromProfile("prof1", "", "", "s2")
+	if err != nil || key != "prof1/chunk" {
+		t.Fatalf("second allocation: %v from %s, want the chunk", err, key)
+	}
+	if !addr.Equal(net.ParseIP("100.64.0.1")) {
+		t.Fatalf("got %v, want 100.64.0.1", addr)
+	}
+
+	if err := r.RemovePool(PoolFamilyIPv4, "prof1/chunk"); !errors.Is(err, ErrPoolInUse) {
+		t.Fatalf("remove with lease: got %v, want ErrPoolInUse", err)
+	}
+	r.Release("prof1/chunk", addr)
+	if err := r.RemovePool(PoolFamilyIPv4, "prof1/chunk"); err != nil {
+		t.Fatalf("RemovePool: %v", err)
+	}
+	if pools := r.GetProfilePools("prof1"); len(pools) != 1 {
+		t.Fatalf("profile pools = %v, want only the configured pool", pools)
+	}
+}
+
+func TestRegistryAddIPv4PoolExcludesGateway(t *testing.T) {
+	r := newRegistry(nil, nil)
+	if err := r.AddIPv4Pool("prof1", ip.IPv4Pool{Name: "chunk", Network: "100.64.0.0/30"}, "100.64.0.1"); err != nil {
+		t.Fatalf("AddIPv4Pool: %v", err)
+	}
+	addr, _, err := r.AllocateFromProfile("prof1", "", "", "s1")
+	if err != nil || !addr.Equal(net.ParseIP("100.64.0.2")) {
+		t.Fatalf("got %v, %v; want 100.64.0.2", addr, err)
+	}
+	if _, _, err := r.AllocateFromProfile("prof1", "", "", "s2"); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("got %v, want ErrPoolExhausted", err)
+	}
+}
+
+func TestRegistryAddPoolRejectsOverlap(t *testing.T) {
+	profiles := map[string]*ip.IPv4Profile{
+		"prof1": makeV4Profile("10.0.0.1", ip.IPv4Pool{Name: "static", Network: "10.0.0.0/24"}),
+	}
+	r := newRegistry(profiles, nil)
+
+	if err := r.AddIPv4Pool("prof2", ip.IPv4Pool{Name: "chunk", Network: "10.0.0.64/29"}, ""); !errors.Is(err, ErrPoolOverlap) {
+		t.Fatalf("chunk inside a configured pool: got %v, want ErrPoolOverlap", err)
+	}
+	if err := r.AddIPv4Pool("prof1", ip.IPv4Pool{Name: "other-vrf", Network: "10.0.0.64/29", VRF: "subs"}, ""); err != nil {
+		t.Fatalf("same range in another VRF: %v", err)
+	}
+	if err := r.AddIPv4Pool("prof1", ip.IPv4Pool{Name: "wide", Network: "10.0.0.0/16", VRF: "subs"}, ""); !errors.Is(err, ErrPoolOverlap) {
+		t.Fatalf("chunk covering a held one: got %v, want ErrPoolOverlap", err)
+	}
+	if pools := r.GetProfilePools("prof2"); len(pools) != 0 {
+		t.Fatalf("refused pool kept: %v", pools)
+	}
+
+	if err := r.AddPDPool("prof6", ip.PDPool{Name: "pd", Network: "2001:db8::/48", PrefixLength: 56}); err != nil {
+		t.Fatalf("AddPDPool: %v", err)
+	}
+	if err := r.AddIANAPool("prof6", ip.IANAPool{Name: "iana", Network: "2001:db8:0:1::/120"}); !errors.Is(err, ErrPoolOverlap) {
+		t.Fatalf("IANA pool inside a PD pool: got %v, want ErrPoolOverlap", err)
+	}
+	if err := r.RemovePool(PoolFamilyPD, "prof6/pd"); err != nil {
+		t.Fatalf("RemovePool: %v", err)
+	}
+	if err := r.AddIANAPool("prof6", ip.IANAPool{Name: "iana", Network: "2001:db8:0:1::/120"}); err != nil {
+		t.Fatalf("IANA pool after the PD pool went: %v", err)
+	}
+}
+
+func TestRegistryAddPDPool(t *testing.T) {
+	r := newRegistry(nil, nil)
+	pool := ip.PDPool{Name: "chunk", Network: "2001:db8::/48", PrefixLength: 56, VRF: "subs"}
+	if err := r.AddPDPool("prof6", pool); err != nil {
+		t.Fatalf("AddPDPool: %v", err)
+	}
+	if size, _ := r.ProfileUsage(PoolFamilyPD, "prof6", "subs"); size != 256 {
+		t.Fatalf("size = %d, want 256", size)
+	}
+	if size, _ := r.ProfileUsage(PoolFamilyPD, "prof6", ""); size != 0 {
+		t.Fatalf("size outside the VRF = %d, want 0", size)
+	}
+	prefix, _, err := r.AllocatePDFromProfile("prof6", "", "subs", "s1")
+	if err != nil {
+		t.Fatalf("AllocatePDFromProfile: %v", err)
+	}
+	if err := r.RemovePool(PoolFamilyPD, "prof6/chunk"); !errors.Is(err, ErrPoolInUse) {
+		t.Fatalf("got %v, want ErrPoolInUse", err)
+	}
+	r.ReleasePD("prof6/chunk", prefix)
+	if err := r.RemovePool(PoolFamilyPD, "prof6/chunk"); err != nil {
+		t.Fatalf("RemovePool: %v", err)
+	}
+}
@@ -131,6 +131,13 @@ func (a *PoolAllocator) Available() int {
 	return len(a.free)
 }
 
+// Size returns the number of allocatable addresses, free or leased.
+func (a *PoolAllocator) Size() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.free) + len(a.leases)
+}
+
 func prevAddr(a netip.Addr) netip.Addr {
 	if a.Is4() {
 		b := a.As4()
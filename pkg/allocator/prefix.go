@@ -113,6 +113,19 @@ func (a *PrefixAllocator) Reserve(prefix *net.IPNet, sessionID string) error {
 	return nil
 }
 
+func (a *PrefixAllocator) Available() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.free)
+}
+
+// Size returns the number of delegatable prefixes, free or leased.
+func (a *PrefixAllocator) Size() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.free) + len(a.leases)
+}
+
 func (a *PrefixAllocator) Contains(prefix *net.IPNet) bool {
 	_, ok := a.prefixToIndex(prefix)
 	return ok
@@ -19,6 +19,11 @@ type Registry struct {
 	profileIANAPools map[string][]string
 	pdAllocators     map[string]*PrefixAllocator
 	profilePDPools   map[string][]string
+	networks         map[poolRef]netip.Prefix
+	thresholds       map[poolRef]*ip.PoolThresholds
+	levels           map[poolRef]PoolLevel
+	empty            map[poolRef]bool
+	descending       bool
 	mu               sync.RWMutex
 }
 
@@ -55,6 +60,10 @@ func newRegistry(v4Profiles map[string]*ip.IPv4Profile, v6Profiles map[string]*i
 		profileIANAPools: make(map[string][]string),
 		pdAllocators:     make(map[string]*PrefixAllocator),
 		profilePDPools:   make(map[string][]string),
+		networks:         make(map[poolRef]netip.Prefix),
+		thresholds:       make(map[poolRef]*ip.PoolThresholds),
+		levels:           make(map[poolRef]PoolLevel),
+		empty:            make(map[poolRef]bool),
 	}
 
 	r.initV4Pools(v4Profiles)
@@ -92,6 +101,8 @@ func (r *Registry) initV4Pools(profiles map[string]*ip.IPv4Profile) {
 			if pool.VRF != "" {
 				r.poolVRFs[key] = pool.VRF
 			}
+			r.setThresholds(PoolFamilyIPv4, key, pool.Thresholds)
+			r.setNetwork(PoolFamilyIPv4, key, pool.Network)
 			if _, exists := r.allocators[key]; exists {
 				continue
 			}
@@ -151,6 +162,8 @@ func (r *Registry) initV6Pools(profiles map[string]*ip.IPv6Profile) {
 			if pool.VRF != "" {
 				r.poolVRFs[key] = pool.VRF
 			}
+			r.setThresholds(PoolFamilyIANA, key, pool.Thresholds)
+			r.setNetwork(PoolFamilyIANA, key, pool.Network)
 
 			if _, exists := r.ianaAllocators[key]; exists {
 				continue
@@ -195,6 +208,8 @@ func (r *Registry) initV6Pools(profiles map[string]*ip.IPv6Profile) {
 			if pool.VRF != "" {
 				r.poolVRFs[key] = pool.VRF
 			}
+			r.setThresholds(PoolFamilyPD, key, pool.Thresholds)
+			r.setNetwork(PoolFamilyPD, key, pool.Network)
 
 			if _, exists := r.pdAllocators[key]; exists {
 				continue
@@ -219,6 +234,7 @@ func (r *Registry) initV6Pools(profiles map[string]*ip.IPv6Profile) {
 func (r *Registry) SetAllocDirection(ascending bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.descending = !ascending
 	for _, alloc := range r.allocators {
 		alloc.SetDirection(ascending)
 	}
@@ -239,16 +255,17 @@ func (r *Registry) AllocateFromProfile(profileName, poolOverride, subscriberVRF,
 	defer r.mu.RUnlock()
 
 	if poolOverride != "" {
-		key := profileName + "/" + poolOverride
-		if alloc, ok := r.allocators[key]; ok {
-			allocated, err := alloc.Allocate(sessionID)
-			if err == nil {
-				return allocated, key, nil
+		for _, key := range r.overrideOrder(PoolFamilyIPv4, profileName, poolOverride) {
+			if alloc, ok := r.allocators[key]; ok {
+				allocated, err := alloc.Allocate(sessionID)
+				if err == nil {
+					return allocated, key, nil
+				}
 			}
 		}
 	}
 
-	for _, poolName := range r.profilePools[profileName] {
+	for _, poolName := range r.allocationOrder(PoolFamilyIPv4, profileName, r.profilePools[profileName]) {
 		poolVRF := r.poolVRFs[poolName]
 		if poolVRF != subscriberVRF {
 			continue
@@ -275,16 +292,17 @@ func (r *Registry) AllocateIANAFromProfile(profileName, poolOverride, subscriber
 	defer r.mu.RUnlock()
 
 	if poolOverride != "" {
-		key := profileName + "/" + poolOverride
-		if alloc, ok := r.ianaAllocators[key]; ok {
-			allocated, err := alloc.Allocate(sessionID)
-			if err == nil {
-				return allocated, key, nil
+		for _, key := range r.overrideOrder(PoolFamilyIANA, profileName, poolOverride) {
+			if alloc, ok := r.ianaAllocators[key]; ok {
+				allocated, err := alloc.Allocate(sessionID)
+				if err == nil {
+					return allocated, key, nil
+				}
 			}
 		}
 	}
 
-	for _, poolName := range r.profileIANAPools[profileName] {
+	for _, poolName := range r.allocationOrder(PoolFamilyIANA, profileName, r.profileIANAPools[profileName]) {
 		poolVRF := r.poolVRFs[poolName]
 		if poolVRF != subscriberVRF {
 			continue
@@ -311,16 +329,17 @@ func (r *Registry) AllocatePDFromProfile(profileName, poolOverride, subscriberVR
 	defer r.mu.RUnlock()
 
 	if poolOverride != "" {
-		key := profileName + "/" + poolOverride
-		if alloc, ok := r.pdAllocators[key]; ok {
-			allocated, err := alloc.Allocate(sessionID)
-			if err == nil {
-				return allocated, key, nil
+		for _, key := range r.overrideOrder(PoolFamilyPD, profileName, poolOverride) {
+			if alloc, ok := r.pdAllocators[key]; ok {
+				allocated, err := alloc.Allocate(sessionID)
+				if err == nil {
+					return allocated, key, nil
+				}
 			}
 		}
 	}
 
-	for _, poolName := range r.profilePDPools[profileName] {
+	for _, poolName := range r.allocationOrder(PoolFamilyPD, profileName, r.profilePDPools[profileName]) {
 		poolVRF := r.poolVRFs[poolName]
 		if poolVRF != subscriberVRF {
 			continue
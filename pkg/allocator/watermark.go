@@ -0,0 +1,276 @@
+package allocator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+type PoolFamily string
+
+const (
+	PoolFamilyIPv4 PoolFamily = "ipv4"
+	PoolFamilyIANA PoolFamily = "iana"
+	PoolFamilyPD   PoolFamily = "pd"
+	// PoolFamilyCGNAT pools live in the CGNAT component; they share the
+	// levels so threshold events look the same for every pool.
+	PoolFamilyCGNAT PoolFamily = "cgnat"
+)
+
+// PoolLevel is where a pool's utilisation sits against its watermarks.
+type PoolLevel string
+
+const (
+	PoolLevelNormal    PoolLevel = "normal"
+	PoolLevelHigh      PoolLevel = "high"
+	PoolLevelExhausted PoolLevel = "exhausted"
+)
+
+// Code is the level as a gauge value: 0 normal, 1 high, 2 exhausted.
+func (l PoolLevel) Code() uint8 {
+	switch l {
+	case PoolLevelHigh:
+		return 1
+	case PoolLevelExhausted:
+		return 2
+	}
+	return 0
+}
+
+// NextLevel returns a pool's level given its current one. A pool with
+// nothing free is exhausted; otherwise it is high from the high
+// watermark up, and stays high until utilisation falls to the low
+// watermark. Without thresholds a pool is only ever normal or exhausted.
+func NextLevel(t *ip.PoolThresholds, current PoolLevel, size, available uint64) PoolLevel {
+	if size > 0 && available == 0 {
+		return PoolLevelExhausted
+	}
+	if t == nil || size == 0 {
+		return PoolLevelNormal
+	}
+	used := (size - available) * 100
+	if used >= uint64(t.GetHigh())*size {
+		return PoolLevelHigh
+	}
+	if current != "" && current != PoolLevelNormal && used > uint64(t.GetLow())*size {
+		return PoolLevelHigh
+	}
+	return PoolLevelNormal
+}
+
+type poolRef struct {
+	family PoolFamily
+	key    string
+}
+
+// PoolUsage is one pool's size, free count and level.
+type PoolUsage struct {
+	Family    PoolFamily
+	Profile   string
+	Pool      string
+	Size      int
+	Available int
+	Level     PoolLevel
+}
+
+// Empty reports whether nothing is allocated from the pool.
+func (u PoolUsage) Empty() bool {
+	return u.Size > 0 && u.Available == u.Size
+}
+
+// PoolLevelChange reports a pool moving from Previous to Usage.Level,
+// or, for a pool with WithdrawRoute, from WasEmpty to Usage.Empty with
+// its level unchanged.
+type PoolLevelChange struct {
+	Usage      PoolUsage
+	Previous   PoolLevel
+	WasEmpty   bool
+	Thresholds *ip.PoolThresholds
+}
+
+// Drained reports whether the pool's last allocation was released.
+func (c PoolLevelChange) Drained() bool {
+	return !c.WasEmpty && c.Usage.Empty()
+}
+
+// Refilled reports whether the pool took its first allocation.
+func (c PoolLevelChange) Refilled() bool {
+	return c.WasEmpty && !c.Usage.Empty()
+}
+
+func (r *Registry) setThresholds(family PoolFamily, key string, t *ip.PoolThresholds) {
+	if t == nil {
+		return
+	}
+	ref := poolRef{family, key}
+	r.thresholds[ref] = t
+	r.levels[ref] = PoolLevelNormal
+}
+
+func (r *Registry) usageLocked(ref poolRef) (PoolUsage, bool) {
+	var size, available int
+	switch ref.family {
+	case PoolFamilyIPv4:
+		a, ok := r.allocators[ref.key]
+		if !ok {
+			return PoolUsage{}, false
+		}
+		size, available = a.Size(), a.Available()
+	case PoolFamilyIANA:
+		a, ok := r.ianaAllocators[ref.key]
+		if !ok {
+			return PoolUsage{}, false
+		}
+		size, available = a.Size(), a.Available()
+	case PoolFamilyPD:
+		a, ok := r.pdAllocators[ref.key]
+		if !ok {
+			return PoolUsage{}, false
+		}
+		size, available = a.Size(), a.Available()
+	default:
+		return PoolUsage{}, false
+	}
+
+	profile, pool, _ := strings.Cut(ref.key, "/")
+	level, tracked := r.levels[ref]
+	if !tracked {
+		level = NextLevel(nil, PoolLevelNormal, uint64(size), uint64(available))
+	}
+	return PoolUsage{
+		Family:    ref.family,
+		Profile:   profile,
+		Pool:      pool,
+		Size:      size,
+		Available: available,
+		Level:     level,
+	}, true
+}
+
+// PoolUsages returns every pool's usage, ordered by family, profile and
+// pool. Levels are those of the last CheckThresholds.
+func (r *Registry) PoolUsages() []PoolUsage {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var refs []poolRef
+	for key := range r.allocators {
+		refs = append(refs, poolRef{PoolFamilyIPv4, key})
+	}
+	for key := range r.ianaAllocators {
+		refs = append(refs, poolRef{PoolFamilyIANA, key})
+	}
+	for key := range r.pdAllocators {
+		refs = append(refs, poolRef{PoolFamilyPD, key})
+	}
+	sortRefs(refs)
+
+	usages := make([]PoolUsage, 0, len(refs))
+	for _, ref := range refs {
+		if u, ok := r.usageLocked(ref); ok {
+			usages = append(usages, u)
+		}
+	}
+	return usages
+}
+
+// CheckThresholds re-evaluates every pool with thresholds and returns
+// the ones whose level changed, and those with WithdrawRoute that were
+// drained or refilled. A pool counts as in use until its first check.
+// The new levels take effect on allocation order at once.
+func (r *Registry) CheckThresholds() []PoolLevelChange {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refs := make([]poolRef, 0, len(r.thresholds))
+	for ref := range r.thresholds {
+		refs = append(refs, ref)
+	}
+	sortRefs(refs)
+
+	var changes []PoolLevelChange
+	for _, ref := range refs {
+		usage, ok := r.usageLocked(ref)
+		if !ok {
+			continue
+		}
+		t := r.thresholds[ref]
+		next := NextLevel(t, usage.Level, uint64(usage.Size), uint64(usage.Available))
+		wasEmpty := r.empty[ref]
+		if next == usage.Level && (!t.WithdrawRoute || wasEmpty == usage.Empty()) {
+			continue
+		}
+		r.levels[ref] = next
+		r.empty[ref] = usage.Empty()
+		previous := usage.Level
+		usage.Level = next
+		changes = append(changes, PoolLevelChange{Usage: usage, Previous: previous, WasEmpty: wasEmpty, Thresholds: t})
+	}
+	return changes
+}
+
+// allocationOrder returns the order to try a profile's pools in. A pool
+// above normal is preceded by its overflow pool, and with lower-priority
+// moves, with its overflow pool, behind the pools that are not demoted.
+func (r *Registry) allocationOrder(family PoolFamily, profileName string, keys []string) []string {
+	if len(r.thresholds) == 0 {
+		return keys
+	}
+
+	order := make([]string, 0, len(keys))
+	var demoted []string
+	placed := make(map[string]bool, len(keys))
+	add := func(list *[]string, key string) {
+		if !placed[key] {
+			placed[key] = true
+			*list = append(*list, key)
+		}
+	}
+	for _, key := range keys {
+		ref := poolRef{family, key}
+		t := r.thresholds[ref]
+		if t == nil || r.levels[ref] == PoolLevelNormal {
+			add(&order, key)
+			continue
+		}
+		list := &order
+		if t.LowerPriority {
+			list = &demoted
+		}
+		if t.OverflowPool != "" {
+			add(list, profileName+"/"+t.OverflowPool)
+		}
+		add(list, key)
+	}
+	return append(order, demoted...)
+}
+
+// overrideOrder is allocationOrder for a pool named explicitly by the
+// subscriber's attributes: its overflow pool, if it is above normal,
+// then the pool itself.
+func (r *Registry) overrideOrder(family PoolFamily, profileName, poolName string) []string {
+	key := profileName + "/" + poolName
+	ref := poolRef{family, key}
+	if t := r.thresholds[ref]; t != nil && t.OverflowPool != "" && r.levels[ref] != PoolLevelNormal {
+		return []string{profileName + "/" + t.OverflowPool, key}
+	}
+	return []string{key}
+}
+
+func sortRefs(refs []poolRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].family != refs[j].family {
+			return refs[i].family < refs[j].family
+		}
+		return refs[i].key < refs[j].key
+	})
+}
@@ -0,0 +1,228 @@
+package allocator
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+func TestNextLevelHysteresis(t *testing.T) {
+	th := &ip.PoolThresholds{High: 80, Low: 60}
+	tests := []struct {
+		name      string
+		current   PoolLevel
+		available uint64
+		want      PoolLevel
+	}{
+		{"below high", PoolLevelNormal, 21, PoolLevelNormal},
+		{"at high", PoolLevelNormal, 20, PoolLevelHigh},
+		{"empty", PoolLevelHigh, 0, PoolLevelExhausted},
+		{"recovering above low", PoolLevelExhausted, 30, PoolLevelHigh},
+		{"between watermarks stays high", PoolLevelHigh, 39, PoolLevelHigh},
+		{"at low", PoolLevelHigh, 40, PoolLevelNormal},
+		{"between watermarks from normal", PoolLevelNormal, 39, PoolLevelNormal},
+	}
+	for _, tt := range tests {
+		if got := NextLevel(th, tt.current, 100, tt.available); got != tt.want {
+			t.Errorf("%s: level = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := NextLevel(nil, PoolLevelNormal, 100, 1); got != PoolLevelNormal {
+		t.Fatalf("no thresholds, one free = %q", got)
+	}
+	if got := NextLevel(nil, PoolLevelNormal, 100, 0); got != PoolLevelExhausted {
+		t.Fatalf("no thresholds, none free = %q", got)
+	}
+}
+
+func TestCheckThresholdsReportsChanges(t *testing.T) {
+	profiles := map[string]*ip.IPv4Profile{
+		"prof1": makeV4Profile("", ip.IPv4Pool{
+			Name:       "small",
+			Network:    "10.0.0.0/24",
+			RangeStart: "10.0.0.1",
+			RangeEnd:   "10.0.0.4",
+			Thresholds: &ip.PoolThresholds{High: 75, Low: 25},
+		}, ip.IPv4Pool{
+			Name:       "plain",
+			Network:    "10.0.1.0/24",
+			RangeStart: "10.0.1.1",
+			RangeEnd:   "10.0.1.1",
+		}),
+	}
+	r := newRegistry(profiles, nil)
+
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("changes on an empty pool = %+v", changes)
+	}
+
+	var ips []net.IP
+	for i := 0; i < 3; i++ {
+		a, _, err := r.AllocateFromProfile("prof1", "small", "", "s")
+		if err != nil {
+			t.Fatalf("allocate: %v", err)
+		}
+		ips = append(ips, a)
+	}
+	changes := r.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != PoolLevelHigh || changes[0].Previous != PoolLevelNormal {
+		t.Fatalf("changes at 75%% = %+v", changes)
+	}
+	if u := changes[0].Usage; u.Family != PoolFamilyIPv4 || u.Profile != "prof1" || u.Pool != "small" || u.Size != 4 || u.Available != 1 {
+		t.Fatalf("usage = %+v", u)
+	}
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("repeated check = %+v", changes)
+	}
+
+	r.Release("prof1/small", ips[0])
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("between watermarks = %+v", changes)
+	}
+	r.Release("prof1/small", ips[1])
+	changes = r.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != PoolLevelNormal {
+		t.Fatalf("at low watermark = %+v", changes)
+	}
+
+	// Pools without thresholds never report, but show their level.
+	// Without withdraw-route, draining the pool is not reported either.
+	r.Release("prof1/small", ips[2])
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("drained without withdraw-route = %+v", changes)
+	}
+
+	if _, _, err := r.AllocateFromProfile("prof1", "plain", "", "s"); err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("pool without thresholds reported %+v", changes)
+	}
+	for _, u := range r.PoolUsages() {
+		if u.Pool == "plain" && u.Level != PoolLevelExhausted {
+			t.Fatalf("plain level = %q", u.Level)
+		}
+	}
+}
+
+func TestCheckThresholdsReportsDrainedPools(t *testing.T) {
+	profiles := map[string]*ip.IPv4Profile{
+		"prof1": makeV4Profile("", ip.IPv4Pool{
+			Name:       "small",
+			Network:    "10.0.0.0/24",
+			RangeStart: "10.0.0.1",
+			RangeEnd:   "10.0.0.4",
+			Thresholds: &ip.PoolThresholds{WithdrawRoute: true},
+		}),
+	}
+	r := newRegistry(profiles, nil)
+
+	changes := r.CheckThresholds()
+	if len(changes) != 1 || !changes[0].Drained() || changes[0].Usage.Level != PoolLevelNormal || changes[0].Previous != PoolLevelNormal {
+		t.Fatalf("first check of an empty pool = %+v", changes)
+	}
+	if changes := r.CheckThresholds(); len(changes) != 0 {
+		t.Fatalf("repeated check = %+v", changes)
+	}
+
+	a, _, err := r.AllocateFromProfile("prof1", "small", "", "s")
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	changes = r.CheckThresholds()
+	if len(changes) != 1 || !changes[0].Refilled() {
+		t.Fatalf("first allocation = %+v", changes)
+	}
+
+	var held []net.IP
+	for i := 0; i < 3; i++ {
+		b, _, err := r.AllocateFromProfile("prof1", "small", "", "s")
+		if err != nil {
+			t.Fatalf("allocate: %v", err)
+		}
+		held = append(held, b)
+	}
+	changes = r.CheckThresholds()
+	if len(changes) != 1 || changes[0].Usage.Level != PoolLevelExhausted || changes[0].Drained() || changes[0].Refilled() {
+		t.Fatalf("exhausted = %+v", changes)
+	}
+
+	r.Release("prof1/small", a)
+	for _, b := range held {
+		r.Release("prof1/small", b)
+	}
+	changes = r.CheckThresholds()
+	if len(changes) != 1 || !changes[0].Drained() || changes[0].Previous != PoolLevelExhausted {
+		t.Fatalf("drained = %+v", changes)
+	}
+}
+
+func TestAllocationOrderActions(t *testing.T) {
+	profiles := map[string]*ip.IPv4Profile{
+		"prof1": makeV4Profile("",
+			ip.IPv4Pool{
+				Name:       "primary",
+				Network:    "10.0.0.0/24",
+				RangeStart: "10.0.0.1",
+				RangeEnd:   "10.0.0.2",
+				Priority:   1,
+				Thresholds: &ip.PoolThresholds{High: 50, Low: 10, OverflowPool: "overflow"},
+			},
+			ip.IPv4Pool{
+				Name:       "secondary",
+				Network:    "10.0.1.0/24",
+				RangeStart: "10.0.1.1",
+				RangeEnd:   "10.0.1.10",
+				Priority:   2,
+			},
+			ip.IPv4Pool{
+				Name:       "overflow",
+				Network:    "10.0.2.0/24",
+				RangeStart: "10.0.2.1",
+				RangeEnd:   "10.0.2.10",
+				Priority:   3,
+			},
+		),
+	}
+	r := newRegistry(profiles, nil)
+
+	if _, key, _ := r.AllocateFromProfile("prof1", "", "", "s1"); key != "prof1/primary" {
+		t.Fatalf("first allocation from %q", key)
+	}
+	r.CheckThresholds()
+	if _, key, _ := r.AllocateFromProfile("prof1", "", "", "s2"); key != "prof1/overflow" {
+		t.Fatalf("allocation above high from %q, want overflow", key)
+	}
+	if _, key, _ := r.AllocateFromProfile("prof1", "primary", "", "s3"); key != "prof1/overflow" {
+		t.Fatalf("override above high from %q, want overflow", key)
+	}
+
+	r.thresholds[poolRef{PoolFamilyIPv4, "prof1/primary"}].OverflowPool = ""
+	r.thresholds[poolRef{PoolFamilyIPv4, "prof1/primary"}].LowerPriority = true
+	got := r.allocationOrder(PoolFamilyIPv4, "prof1", r.profilePools["prof1"])
+	want := []string{"prof1/secondary", "prof1/overflow", "prof1/primary"}
+	if len(got) != len(want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPrefixAllocatorSize(t *testing.T) {
+	a := NewPrefixAllocator(netip.MustParsePrefix("2001:db8::/60"), 62)
+	if a.Size() != 4 || a.Available() != 4 {
+		t.Fatalf("size %d available %d", a.Size(), a.Available())
+	}
+	if _, err := a.Allocate("s1"); err != nil {
+		t.Fatal(err)
+	}
+	if a.Size() != 4 || a.Available() != 3 {
+		t.Fatalf("after allocate: size %d available %d", a.Size(), a.Available())
+	}
+}
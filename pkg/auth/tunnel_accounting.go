@@ -0,0 +1,71 @@
+package auth
+
+import "context"
+
+// TunnelAccounter is implemented by providers that report RFC 2867
+// tunnel accounting. It is optional: the AAA component checks for it
+// on the configured AuthProvider and drops tunnel records otherwise.
+type TunnelAccounter interface {
+	TunnelAccounting(ctx context.Context, rec *TunnelAccountingRecord) error
+}
+
+// TunnelAcctStatus is the RFC 2867 record kind.
+type TunnelAcctStatus string
+
+const (
+	TunnelAcctStart     TunnelAcctStatus = "tunnel-start"
+	TunnelAcctStop      TunnelAcctStatus = "tunnel-stop"
+	TunnelAcctReject    TunnelAcctStatus = "tunnel-reject"
+	TunnelAcctLinkStart TunnelAcctStatus = "tunnel-link-start"
+	TunnelAcctLinkStop  TunnelAcctStatus = "tunnel-link-stop"
+)
+
+// IsLink reports whether the record describes a session inside a
+// tunnel rather than the tunnel itself.
+func (s TunnelAcctStatus) IsLink() bool {
+	return s == TunnelAcctLinkStart || s == TunnelAcctLinkStop
+}
+
+// Terminate causes carried on Stop / Reject records, named after the
+// RFC 2866 Acct-Terminate-Cause values they map to.
+const (
+	TerminateCauseUserRequest = "user-request"
+	TerminateCauseLostCarrier = "lost-carrier"
+	TerminateCauseAdminReset  = "admin-reset"
+	TerminateCauseNASRequest  = "nas-request"
+	TerminateCauseNASError    = "nas-error"
+)
+
+// TunnelAccountingRecord is one RFC 2867 accounting record. Endpoints
+// follow RFC 2868: the client is the LAC and the server the LNS,
+// whichever role this node plays.
+type TunnelAccountingRecord struct {
+	Status TunnelAcctStatus
+
+	// AcctSessionID identifies the tunnel on tunnel records and the
+	// session on link records; AcctMultiSessionID links a session's
+	// records to its tunnel.
+	AcctSessionID      string
+	AcctMultiSessionID string
+
+	// TunnelConnectionID is Acct-Tunnel-Connection: the tunnel IDs
+	// and, on link records, the call's session IDs.
+	TunnelConnectionID string
+
+	Medium         string
+	ClientEndpoint string
+	ServerEndpoint string
+	ClientAuthID   string
+	ServerAuthID   string
+
+	// Link records only.
+	SessionID string
+	Username  string
+
+	// Stop / Reject records only.
+	SessionDuration uint32
+	PacketsLost     uint32
+	TerminateCause  string
+
+	Attributes map[string]string
+}
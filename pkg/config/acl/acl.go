@@ -0,0 +1,335 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package acl holds the access-lists config block: named, ordered rule
+// lists that service groups and AAA attach to subscriber sessions.
+package acl
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	ActionPermit        = "permit"
+	ActionDeny          = "deny"
+	ActionPermitReflect = "permit-reflect"
+)
+
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// MaxNameLength is the longest name the dataplane stores as an ACL's tag,
+// which is how an ACL is found again after a restart.
+const MaxNameLength = 63
+
+// ReservedPrefix starts the names of the ACLs osvbng programs for its
+// own use, such as local switching and FlowSpec. Access lists may not
+// use it.
+const ReservedPrefix = "osvbng-"
+
+var protocolNumbers = map[string]uint8{
+	"icmp":   1,
+	"tcp":    6,
+	"udp":    17,
+	"icmpv6": 58,
+}
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+)
+
+var tcpFlagBits = map[string]uint8{
+	"fin": 0x01,
+	"syn": 0x02,
+	"rst": 0x04,
+	"psh": 0x08,
+	"ack": 0x10,
+	"urg": 0x20,
+	"ece": 0x40,
+	"cwr": 0x80,
+}
+
+// AccessList is an ordered list of rules. The first matching rule
+// decides; traffic that matches none is denied.
+type AccessList struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Rules       []Rule `json:"rules"                 yaml:"rules"`
+}
+
+// Rule matches on the 5-tuple. Empty fields match anything. A rule with
+// no prefixes, no family and no ICMP protocol applies to both families
+// and becomes one dataplane entry per family.
+type Rule struct {
+	Action          string    `json:"action"                     yaml:"action"`
+	Family          string    `json:"family,omitempty"           yaml:"family,omitempty"`
+	Protocol        string    `json:"protocol,omitempty"         yaml:"protocol,omitempty"`
+	Source          string    `json:"source,omitempty"           yaml:"source,omitempty"`
+	Destination     string    `json:"destination,omitempty"      yaml:"destination,omitempty"`
+	SourcePort      string    `json:"source-port,omitempty"      yaml:"source-port,omitempty"`
+	DestinationPort string    `json:"destination-port,omitempty" yaml:"destination-port,omitempty"`
+	TCPFlags        *TCPFlags `json:"tcp-flags,omitempty"        yaml:"tcp-flags,omitempty"`
+	ICMPType        *uint8    `json:"icmp-type,omitempty"        yaml:"icmp-type,omitempty"`
+	ICMPCode        *uint8    `json:"icmp-code,omitempty"        yaml:"icmp-code,omitempty"`
+}
+
+// TCPFlags matches flags that must be set and flags that must be clear;
+// flags in neither list are ignored.
+type TCPFlags struct {
+	Set   []string `json:"set,omitempty"   yaml:"set,omitempty"`
+	Unset []string `json:"unset,omitempty" yaml:"unset,omitempty"`
+}
+
+// Entry is one rule as the dataplane matches it: a single family, with
+// every wildcard spelled out. For ICMP the source range holds the types
+// and the destination range the codes.
+type Entry struct {
+	Action        string
+	IPv6          bool
+	Source        net.IPNet
+	Destination   net.IPNet
+	Protocol      uint8
+	SrcFirst      uint16
+	SrcLast       uint16
+	DstFirst      uint16
+	DstLast       uint16
+	TCPFlagsValue uint8
+	TCPFlagsMask  uint8
+}
+
+// Validate checks the list without expanding it.
+func (a *AccessList) Validate(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return fmt.Errorf("access-lists: name %q must be 1-%d characters", name, MaxNameLength)
+	}
+	if strings.HasPrefix(name, ReservedPrefix) {
+		return fmt.Errorf("access-lists: name %q uses the reserved prefix %q", name, ReservedPrefix)
+	}
+	if len(a.Rules) == 0 {
+		return fmt.Errorf("access-lists.%s: at least one rule is required", name)
+	}
+	_, _, err := a.Expand()
+	if err != nil {
+		return fmt.Errorf("access-lists.%s.%w", name, err)
+	}
+	return nil
+}
+
+// Expand turns the rules into dataplane entries, in order. ruleOf[i] is
+// the index of the rule entry i came from, so per-entry counters can be
+// reported against the configured rule.
+func (a *AccessList) Expand() (entries []Entry, ruleOf []int, err error) {
+	for i := range a.Rules {
+		es, err := a.Rules[i].entries()
+		if err != nil {
+			return nil, nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		for range es {
+			ruleOf = append(ruleOf, i)
+		}
+		entries = append(entries, es...)
+	}
+	return entries, ruleOf, nil
+}
+
+func (r *Rule) entries() ([]Entry, error) {
+	switch r.Action {
+	case ActionPermit, ActionDeny, ActionPermitReflect:
+	default:
+		return nil, fmt.Errorf("action: %q is not permit, deny or permit-reflect", r.Action)
+	}
+
+	proto, err := parseProtocol(r.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	families := map[string]bool{}
+	if r.Family != "" {
+		if r.Family != FamilyIPv4 && r.Family != FamilyIPv6 {
+			return nil, fmt.Errorf("family: %q is not ipv4 or ipv6", r.Family)
+		}
+		families[r.Family] = true
+	}
+	switch proto {
+	case protoICMP:
+		families[FamilyIPv4] = true
+	case protoICMPv6:
+		families[FamilyIPv6] = true
+	}
+
+	src, err := parsePrefix("source", r.Source, families)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := parsePrefix("destination", r.Destination, families)
+	if err != nil {
+		return nil, err
+	}
+	if len(families) > 1 {
+		return nil, fmt.Errorf("mixes IPv4 and IPv6 matches")
+	}
+
+	e := Entry{
+		Action:   r.Action,
+		Protocol: proto,
+		SrcFirst: 0, SrcLast: 65535,
+		DstFirst: 0, DstLast: 65535,
+	}
+
+	isPorts := proto == protoTCP || proto == protoUDP
+	isICMP := proto == protoICMP || proto == protoICMPv6
+	if (r.SourcePort != "" || r.DestinationPort != "") && !isPorts {
+		return nil, fmt.Errorf("ports need protocol tcp or udp")
+	}
+	if r.TCPFlags != nil && proto != protoTCP {
+		return nil, fmt.Errorf("tcp-flags needs protocol tcp")
+	}
+	if (r.ICMPType != nil || r.ICMPCode != nil) && !isICMP {
+		return nil, fmt.Errorf("icmp-type and icmp-code need protocol icmp or icmpv6")
+	}
+
+	if r.SourcePort != "" {
+		if e.SrcFirst, e.SrcLast, err = parsePortRange(r.SourcePort); err != nil {
+			return nil, fmt.Errorf("source-port: %w", err)
+		}
+	}
+	if r.DestinationPort != "" {
+		if e.DstFirst, e.DstLast, err = parsePortRange(r.DestinationPort); err != nil {
+			return nil, fmt.Errorf("destination-port: %w", err)
+		}
+	}
+	if isICMP {
+		e.SrcLast, e.DstLast = 255, 255
+		if r.ICMPType != nil {
+			e.SrcFirst, e.SrcLast = uint16(*r.ICMPType), uint16(*r.ICMPType)
+		}
+		if r.ICMPCode != nil {
+			e.DstFirst, e.DstLast = uint16(*r.ICMPCode), uint16(*r.ICMPCode)
+		}
+	}
+	if r.TCPFlags != nil {
+		if e.TCPFlagsValue, e.TCPFlagsMask, err = r.TCPFlags.bits(); err != nil {
+			return nil, err
+		}
+	}
+
+	var out []Entry
+	for _, family := range []string{FamilyIPv4, FamilyIPv6} {
+		if len(families) > 0 && !families[family] {
+			continue
+		}
+		fe := e
+		fe.IPv6 = family == FamilyIPv6
+		fe.Source = anyIfUnset(src, fe.IPv6)
+		fe.Destination = anyIfUnset(dst, fe.IPv6)
+		out = append(out, fe)
+	}
+	return out, nil
+}
+
+func (f *TCPFlags) bits() (value, mask uint8, err error) {
+	for _, name := range f.Set {
+		bit, ok := tcpFlagBits[strings.ToLower(name)]
+		if !ok {
+			return 0, 0, fmt.Errorf("tcp-flags.set: unknown flag %q", name)
+		}
+		value |= bit
+		mask |= bit
+	}
+	for _, name := range f.Unset {
+		bit, ok := tcpFlagBits[strings.ToLower(name)]
+		if !ok {
+			return 0, 0, fmt.Errorf("tcp-flags.unset: unknown flag %q", name)
+		}
+		if value&bit != 0 {
+			return 0, 0, fmt.Errorf("tcp-flags: %q is both set and unset", name)
+		}
+		mask |= bit
+	}
+	if mask == 0 {
+		return 0, 0, fmt.Errorf("tcp-flags: no flags given")
+	}
+	return value, mask, nil
+}
+
+// parseProtocol accepts a name or a protocol number; empty is any.
+func parseProtocol(s string) (uint8, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, ok := protocolNumbers[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("protocol: %q is not tcp, udp, icmp, icmpv6 or 0-255", s)
+	}
+	return uint8(n), nil
+}
+
+// parsePrefix parses a prefix or a bare address and records its family.
+func parsePrefix(field, s string, families map[string]bool) (*net.IPNet, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("%s: %q is not an address or prefix", field, s)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		s = fmt.Sprintf("%s/%d", ip, bits)
+	}
+	ip, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not an address or prefix", field, s)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return nil, fmt.Errorf("%s: %q has host bits set", field, s)
+	}
+	if ip.To4() != nil {
+		families[FamilyIPv4] = true
+	} else {
+		families[FamilyIPv6] = true
+	}
+	return ipNet, nil
+}
+
+// parsePortRange accepts a single port or an inclusive "first-last".
+func parsePortRange(s string) (uint16, uint16, error) {
+	firstStr, lastStr, isRange := strings.Cut(s, "-")
+	first, err := strconv.ParseUint(strings.TrimSpace(firstStr), 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a port or port range", s)
+	}
+	if !isRange {
+		return uint16(first), uint16(first), nil
+	}
+	last, err := strconv.ParseUint(strings.TrimSpace(lastStr), 10, 16)
+	if err != nil || last < first {
+		return 0, 0, fmt.Errorf("%q is not a port or port range", s)
+	}
+	return uint16(first), uint16(last), nil
+}
+
+func anyIfUnset(p *net.IPNet, ipv6 bool) net.IPNet {
+	if p != nil {
+		return *p
+	}
+	if ipv6 {
+		return net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+	}
+	return net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package acl
+
+import (
+	"strings"
+	"testing"
+)
+
+func u8(v uint8) *uint8 { return &v }
+
+func TestExpand(t *testing.T) {
+	list := &AccessList{Rules: []Rule{
+		{Action: ActionPermit, Protocol: "tcp", Destination: "192.0.2.0/24", DestinationPort: "80-443",
+			TCPFlags: &TCPFlags{Set: []string{"syn"}, Unset: []string{"ack"}}},
+		{Action: ActionPermit, Protocol: "icmpv6", ICMPType: u8(128)},
+		{Action: ActionDeny},
+	}}
+
+	entries, ruleOf, err := list.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+	if want := []int{0, 1, 2, 2}; len(ruleOf) != 4 || ruleOf[0] != want[0] || ruleOf[1] != want[1] || ruleOf[3] != want[3] {
+		t.Fatalf("ruleOf = %v, want %v", ruleOf, want)
+	}
+
+	e := entries[0]
+	if e.IPv6 || e.Protocol != 6 || e.Destination.String() != "192.0.2.0/24" || e.Source.String() != "0.0.0.0/0" {
+		t.Fatalf("tcp entry = %+v", e)
+	}
+	if e.SrcFirst != 0 || e.SrcLast != 65535 || e.DstFirst != 80 || e.DstLast != 443 {
+		t.Fatalf("tcp ports = %d-%d -> %d-%d", e.SrcFirst, e.SrcLast, e.DstFirst, e.DstLast)
+	}
+	if e.TCPFlagsValue != 0x02 || e.TCPFlagsMask != 0x12 {
+		t.Fatalf("tcp flags = %#x/%#x", e.TCPFlagsValue, e.TCPFlagsMask)
+	}
+
+	e = entries[1]
+	if !e.IPv6 || e.SrcFirst != 128 || e.SrcLast != 128 || e.DstFirst != 0 || e.DstLast != 255 {
+		t.Fatalf("icmpv6 entry = %+v", e)
+	}
+
+	if entries[2].IPv6 || !entries[3].IPv6 || entries[3].Source.String() != "::/0" {
+		t.Fatalf("catch-all entries = %+v, %+v", entries[2], entries[3])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"bad action", Rule{Action: "allow"}, "action"},
+		{"bad protocol", Rule{Action: ActionPermit, Protocol: "sctp"}, "protocol"},
+		{"mixed families", Rule{Action: ActionPermit, Source: "10.0.0.0/8", Destination: "2001:db8::/32"}, "mixes"},
+		{"family mismatch", Rule{Action: ActionPermit, Family: FamilyIPv6, Source: "10.0.0.0/8"}, "mixes"},
+		{"icmp over ipv6", Rule{Action: ActionPermit, Protocol: "icmp", Family: FamilyIPv6}, "mixes"},
+		{"host bits", Rule{Action: ActionPermit, Source: "10.0.0.1/8"}, "host bits"},
+		{"ports without l4", Rule{Action: ActionPermit, DestinationPort: "53"}, "ports"},
+		{"reversed range", Rule{Action: ActionPermit, Protocol: "udp", DestinationPort: "90-80"}, "port range"},
+		{"flags without tcp", Rule{Action: ActionPermit, Protocol: "udp", TCPFlags: &TCPFlags{Set: []string{"syn"}}}, "tcp-flags"},
+		{"unknown flag", Rule{Action: ActionPermit, Protocol: "tcp", TCPFlags: &TCPFlags{Set: []string{"xyz"}}}, "unknown flag"},
+		{"icmp type without icmp", Rule{Action: ActionPermit, Protocol: "tcp", ICMPType: u8(8)}, "icmp-type"},
+		{"ok", Rule{Action: ActionPermitReflect, Protocol: "17", Source: "2001:db8::1"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&AccessList{Rules: []Rule{tt.rule}}).Validate("test")
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := (&AccessList{}).Validate("empty"); err == nil {
+		t.Fatal("empty list accepted")
+	}
+	if err := (&AccessList{Rules: []Rule{{Action: ActionDeny}}}).Validate(strings.Repeat("a", 64)); err == nil {
+		t.Fatal("over-long name accepted")
+	}
+	if err := (&AccessList{Rules: []Rule{{Action: ActionDeny}}}).Validate(ReservedPrefix + "mine"); err == nil {
+		t.Fatal("reserved name accepted")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	DefaultArchiveDirectory     = "/var/lib/osvbng/cgnat-archive"
+	DefaultArchiveRetentionDays = 180
+)
+
+// ArchiveConfig enables the on-box mapping archive: every port block
+// allocation and release is appended to a compressed file per UTC day,
+// so an outside address, port and time can be traced back to the
+// subscriber without an external collector. Files older than
+// RetentionDays are deleted; with MaxSizeMB set, the oldest days are
+// also deleted once the archive outgrows it.
+type ArchiveConfig struct {
+	Directory     string `json:"directory,omitempty" yaml:"directory,omitempty"`
+	RetentionDays uint32 `json:"retention-days,omitempty" yaml:"retention-days,omitempty"`
+	MaxSizeMB     uint32 `json:"max-size-mb,omitempty" yaml:"max-size-mb,omitempty"`
+}
+
+func (a *ArchiveConfig) GetDirectory() string {
+	if a == nil || a.Directory == "" {
+		return DefaultArchiveDirectory
+	}
+	return a.Directory
+}
+
+func (a *ArchiveConfig) GetRetentionDays() uint32 {
+	if a == nil || a.RetentionDays == 0 {
+		return DefaultArchiveRetentionDays
+	}
+	return a.RetentionDays
+}
+
+func (a *ArchiveConfig) validate() error {
+	if a == nil {
+		return nil
+	}
+	if a.Directory != "" && !filepath.IsAbs(a.Directory) {
+		return fmt.Errorf("cgnat: archive.directory %q must be an absolute path", a.Directory)
+	}
+	return nil
+}
@@ -4,7 +4,31 @@
 
 package cgnat
 
-import "fmt"
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+// DS-Lite defaults (RFC 6333 §5.7): the AFTR takes 192.0.0.1 on the
+// softwire, B4s use the rest of 192.0.0.0/29.
+const (
+	ModeDSLite        = "dslite"
+	DefaultAFTRIPv4   = "192.0.0.1"
+	maxDomainNameLen  = 253
+	maxDomainLabelLen = 63
+)
+
+// NAT64 (RFC 6146) translates towards the RFC 6052 well-known prefix
+// unless the pool configures a network-specific one.
+const (
+	ModeNAT64          = "nat64"
+	DefaultNAT64Prefix = "64:ff9b::/96"
+	DefaultDNS64Port   = 53
+)
 
 type Config struct {
 	Standalone                bool             `json:"standalone,omitempty" yaml:"standalone,omitempty"`
@@ -13,6 +37,10 @@ type Config struct {
 	Pools                     map[string]*Pool `json:"pools,omitempty" yaml:"pools,omitempty"`
 	Logging                   *LoggingConfig   `json:"logging,omitempty" yaml:"logging,omitempty"`
 	Reconcile                 *ReconcileConfig `json:"reconcile,omitempty" yaml:"reconcile,omitempty"`
+	MAP                       *MAPConfig       `json:"map,omitempty" yaml:"map,omitempty"`
+	PortForwards              []PortForward    `json:"port-forwards,omitempty" yaml:"port-forwards,omitempty"`
+	PCP                       *PCPConfig       `json:"pcp,omitempty" yaml:"pcp,omitempty"`
+	Archive                   *ArchiveConfig   `json:"archive,omitempty" yaml:"archive,omitempty"`
 }
 
 type ReconcileConfig struct {
@@ -57,6 +85,7 @@ func (c *Config) Validate() error {
 		c.Reconcile.OnDivergence != "fail" {
 		return fmt.Errorf("cgnat: reconcile.on_divergence must be \"reconcile\" or \"fail\", got %q", c.Reconcile.OnDivergence)
 	}
+	var dsLite, nat64 string
 	for name, pool := range c.Pools {
 		if pool == nil {
 			continue
@@ -74,31 +103,264 @@ func (c *Config) Validate() error {
 			}
 			seen[entry] = struct{}{}
 		}
+		if err := pool.validateAFTR(name); err != nil {
+			return err
+		}
+		if pool.GetMode() == ModeDSLite {
+			if dsLite != "" {
+				return fmt.Errorf("cgnat: pools %q and %q are both mode dslite; the dataplane has a single AFTR", dsLite, name)
+			}
+			dsLite = name
+		}
+		if err := pool.validateNAT64(name); err != nil {
+			return err
+		}
+		if err := pool.validateThresholds(name); err != nil {
+			return err
+		}
+		if err := pool.validateSubscriberLimits(name); err != nil {
+			return err
+		}
+		if pool.GetMode() == ModeNAT64 {
+			if nat64 != "" {
+				return fmt.Errorf("cgnat: pools %q and %q are both mode nat64; the dataplane has a single NAT64 address pool", nat64, name)
+			}
+			nat64 = name
+		}
+	}
+	if err := c.validatePortForwards(); err != nil {
+		return err
+	}
+	if err := c.PCP.validate(); err != nil {
+		return err
+	}
+	if err := c.Archive.validate(); err != nil {
+		return err
+	}
+	return c.MAP.validate()
+}
+
+// validateThresholds checks the pool's watermarks. Pool selection is
+// by inside prefix or service-group policy, so there is no pool order
+// for overflow-pool or lower-priority to change.
+func (p *Pool) validateThresholds(name string) error {
+	if p.Thresholds == nil {
+		return nil
+	}
+	if err := p.Thresholds.Validate(); err != nil {
+		return fmt.Errorf("cgnat: pool %q: thresholds: %w", name, err)
+	}
+	if p.Thresholds.OverflowPool != "" || p.Thresholds.LowerPriority {
+		return fmt.Errorf("cgnat: pool %q: thresholds: overflow-pool and lower-priority do not apply to CGNAT pools", name)
+	}
+	return nil
+}
+
+func (p *Pool) validateNAT64(name string) error {
+	if p.GetMode() != ModeNAT64 {
+		if p.NAT64 != nil {
+			return fmt.Errorf("cgnat: pool %q: nat64 is only valid with mode nat64", name)
+		}
+		return nil
+	}
+	if len(p.InsidePrefixes) > 0 {
+		return fmt.Errorf("cgnat: pool %q: mode nat64 selects subscribers by service-group policy; inside-prefixes is not used", name)
+	}
+	prefix := p.NAT64.GetPrefix()
+	_, ipNet, err := net.ParseCIDR(prefix)
+	if err != nil || ipNet.IP.To4() != nil {
+		return fmt.Errorf("cgnat: pool %q: nat64.prefix %q is not an IPv6 prefix", name, prefix)
+	}
+	switch ones, _ := ipNet.Mask.Size(); ones {
+	case 32, 40, 48, 56, 64, 96:
+	default:
+		return fmt.Errorf("cgnat: pool %q: nat64.prefix %q: length must be 32, 40, 48, 56, 64 or 96 (RFC 6052)", name, prefix)
+	}
+	if p.NAT64 == nil || p.NAT64.DNS64 == nil {
+		return nil
+	}
+	dns := p.NAT64.DNS64
+	if ip := net.ParseIP(dns.Address); ip == nil || ip.To4() != nil {
+		return fmt.Errorf("cgnat: pool %q: nat64.dns64.address %q is not an IPv6 address", name, dns.Address)
+	}
+	if len(dns.Upstreams) == 0 {
+		return fmt.Errorf("cgnat: pool %q: nat64.dns64.upstreams is required", name)
+	}
+	for i, up := range dns.Upstreams {
+		if _, err := ParseDNS64Upstream(up); err != nil {
+			return fmt.Errorf("cgnat: pool %q: nat64.dns64.upstreams[%d]: %w", name, i, err)
+		}
+	}
+	for i, pfx := range dns.AllowedClients {
+		if _, n, err := net.ParseCIDR(pfx); err != nil || n.IP.To4() != nil {
+			return fmt.Errorf("cgnat: pool %q: nat64.dns64.allowed-clients[%d] %q is not an IPv6 prefix", name, i, pfx)
+		}
+	}
+	return nil
+}
+
+// ParseDNS64Upstream parses an upstream resolver given as an address or
+// address:port (IPv6 with port in brackets); the port defaults to 53.
+func ParseDNS64Upstream(s string) (*net.UDPAddr, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		return &net.UDPAddr{IP: ip, Port: DefaultDNS64Port}, nil
+	}
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an address or address:port", s)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("%q: %q is not an IP address", s, host)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || n == 0 {
+		return nil, fmt.Errorf("%q: invalid port %q", s, port)
+	}
+	return &net.UDPAddr{IP: ip, Port: int(n)}, nil
+}
+
+func (p *Pool) validateAFTR(name string) error {
+	if p.GetMode() != ModeDSLite {
+		if p.AFTR != nil {
+			return fmt.Errorf("cgnat: pool %q: aftr is only valid with mode dslite", name)
+		}
+		return nil
+	}
+	if p.AFTR == nil || p.AFTR.Address == "" {
+		return fmt.Errorf("cgnat: pool %q: mode dslite requires aftr.address", name)
+	}
+	if ip := net.ParseIP(p.AFTR.Address); ip == nil || ip.To4() != nil {
+		return fmt.Errorf("cgnat: pool %q: aftr.address %q is not an IPv6 address", name, p.AFTR.Address)
+	}
+	if p.AFTR.IPv4Address != "" {
+		if ip := net.ParseIP(p.AFTR.IPv4Address); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("cgnat: pool %q: aftr.ipv4-address %q is not an IPv4 address", name, p.AFTR.IPv4Address)
+		}
+	}
+	if p.AFTR.Name != "" {
+		if err := validateDomainName(p.AFTR.Name); err != nil {
+			return fmt.Errorf("cgnat: pool %q: aftr.name: %w", name, err)
+		}
+	}
+	if len(p.InsidePrefixes) > 0 {
+		return fmt.Errorf("cgnat: pool %q: mode dslite selects subscribers by service-group policy; inside-prefixes is not used", name)
+	}
+	return nil
+}
+
+func validateDomainName(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > maxDomainNameLen {
+		return fmt.Errorf("%q is not a valid domain name", name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > maxDomainLabelLen {
+			return fmt.Errorf("%q has an empty or over-long label", name)
+		}
+	}
+	return nil
+}
+
+// AFTRName returns the AFTR-Name (DHCPv6 option 64) for subscribers
+// whose service group selects policy, or "" when policy is not a
+// DS-Lite pool.
+func (c *Config) AFTRName(policy string) string {
+	if c == nil || policy == "" {
+		return ""
+	}
+	p := c.Pools[policy]
+	if p == nil || p.GetMode() != ModeDSLite || p.AFTR == nil {
+		return ""
+	}
+	return p.AFTR.Name
+}
+
+// DNS64Servers returns the DNS64 address to hand subscribers whose
+// service group selects policy, or nil when policy is not a NAT64 pool
+// with DNS64.
+func (c *Config) DNS64Servers(policy string) []net.IP {
+	if c == nil || policy == "" {
+		return nil
+	}
+	p := c.Pools[policy]
+	if p == nil || p.GetMode() != ModeNAT64 || p.NAT64 == nil || p.NAT64.DNS64 == nil {
+		return nil
+	}
+	if ip := net.ParseIP(p.NAT64.DNS64.Address); ip != nil {
+		return []net.IP{ip}
 	}
 	return nil
 }
 
 type Pool struct {
-	OutsideInterfaces        []string       `json:"outside_interfaces,omitempty" yaml:"outside_interfaces,omitempty"`
-	Mode                     string         `json:"mode,omitempty" yaml:"mode,omitempty"`
-	AutoConfigure            bool           `json:"auto-configure,omitempty" yaml:"auto-configure,omitempty"`
-	InsidePrefixes           []InsidePrefix `json:"inside-prefixes,omitempty" yaml:"inside-prefixes,omitempty"`
-	OutsideAddresses         []string       `json:"outside-addresses,omitempty" yaml:"outside-addresses,omitempty"`
-	BlockSize                uint16         `json:"block-size,omitempty" yaml:"block-size,omitempty"`
-	MaxBlocksPerSubscriber   uint8          `json:"max-blocks-per-subscriber,omitempty" yaml:"max-blocks-per-subscriber,omitempty"`
-	MaxSessionsPerSubscriber uint32         `json:"max-sessions-per-subscriber,omitempty" yaml:"max-sessions-per-subscriber,omitempty"`
-	ExhaustionBehavior       string         `json:"exhaustion-behavior,omitempty" yaml:"exhaustion-behavior,omitempty"`
-	PortReuseTimeout         uint16         `json:"port-reuse-timeout,omitempty" yaml:"port-reuse-timeout,omitempty"`
-	SubscriberRatio          uint16         `json:"subscriber-ratio,omitempty" yaml:"subscriber-ratio,omitempty"`
-	PortsPerSubscriber       uint16         `json:"ports-per-subscriber,omitempty" yaml:"ports-per-subscriber,omitempty"`
-	PortRange                string         `json:"port-range,omitempty" yaml:"port-range,omitempty"`
-	AddressPooling           string         `json:"address-pooling,omitempty" yaml:"address-pooling,omitempty"`
-	Filtering                string         `json:"filtering,omitempty" yaml:"filtering,omitempty"`
-	ExcludedAddresses        []string       `json:"excluded-addresses,omitempty" yaml:"excluded-addresses,omitempty"`
-	BlacklistMode            string         `json:"blacklist-mode,omitempty" yaml:"blacklist-mode,omitempty"`
-	NetworkRoutePolicy       string         `json:"network-route-policy,omitempty" yaml:"network-route-policy,omitempty"`
-	ALG                      *ALGConfig     `json:"alg,omitempty" yaml:"alg,omitempty"`
-	Timeouts                 *TimeoutConfig `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	OutsideInterfaces        []string                `json:"outside_interfaces,omitempty" yaml:"outside_interfaces,omitempty"`
+	Mode                     string                  `json:"mode,omitempty" yaml:"mode,omitempty"`
+	AutoConfigure            bool                    `json:"auto-configure,omitempty" yaml:"auto-configure,omitempty"`
+	InsidePrefixes           []InsidePrefix          `json:"inside-prefixes,omitempty" yaml:"inside-prefixes,omitempty"`
+	OutsideAddresses         []string                `json:"outside-addresses,omitempty" yaml:"outside-addresses,omitempty"`
+	BlockSize                uint16                  `json:"block-size,omitempty" yaml:"block-size,omitempty"`
+	MaxBlocksPerSubscriber   uint8                   `json:"max-blocks-per-subscriber,omitempty" yaml:"max-blocks-per-subscriber,omitempty"`
+	MaxSessionsPerSubscriber uint32                  `json:"max-sessions-per-subscriber,omitempty" yaml:"max-sessions-per-subscriber,omitempty"`
+	ExhaustionBehavior       string                  `json:"exhaustion-behavior,omitempty" yaml:"exhaustion-behavior,omitempty"`
+	PortReuseTimeout         uint16                  `json:"port-reuse-timeout,omitempty" yaml:"port-reuse-timeout,omitempty"`
+	SubscriberRatio          uint16                  `json:"subscriber-ratio,omitempty" yaml:"subscriber-ratio,omitempty"`
+	PortsPerSubscriber       uint16                  `json:"ports-per-subscriber,omitempty" yaml:"ports-per-subscriber,omitempty"`
+	PortRange                string                  `json:"port-range,omitempty" yaml:"port-range,omitempty"`
+	AddressPooling           string                  `json:"address-pooling,omitempty" yaml:"address-pooling,omitempty"`
+	Filtering                string                  `json:"filtering,omitempty" yaml:"filtering,omitempty"`
+	ExcludedAddresses        []string                `json:"excluded-addresses,omitempty" yaml:"excluded-addresses,omitempty"`
+	BlacklistMode            string                  `json:"blacklist-mode,omitempty" yaml:"blacklist-mode,omitempty"`
+	NetworkRoutePolicy       string                  `json:"network-route-policy,omitempty" yaml:"network-route-policy,omitempty"`
+	ALG                      *ALGConfig              `json:"alg,omitempty" yaml:"alg,omitempty"`
+	Timeouts                 *TimeoutConfig          `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	AFTR                     *AFTRConfig             `json:"aftr,omitempty" yaml:"aftr,omitempty"`
+	NAT64                    *NAT64Config            `json:"nat64,omitempty" yaml:"nat64,omitempty"`
+	Thresholds               *ip.PoolThresholds      `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	SubscriberLimits         *SubscriberLimitsConfig `json:"subscriber-limits,omitempty" yaml:"subscriber-limits,omitempty"`
+}
+
+// AFTRConfig is the DS-Lite (RFC 6333) tunnel concentrator of a mode
+// dslite pool. Address is the IPv6 softwire endpoint B4s tunnel to;
+// Name is handed to subscribers as DHCPv6 option 64 (RFC 6334) and
+// must resolve to Address.
+type AFTRConfig struct {
+	Address     string `json:"address" yaml:"address"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	IPv4Address string `json:"ipv4-address,omitempty" yaml:"ipv4-address,omitempty"`
+}
+
+func (a *AFTRConfig) GetIPv4Address() string {
+	if a == nil || a.IPv4Address == "" {
+		return DefaultAFTRIPv4
+	}
+	return a.IPv4Address
+}
+
+// NAT64Config is the translation prefix of a mode nat64 pool and its
+// optional DNS64 service.
+type NAT64Config struct {
+	Prefix string       `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	DNS64  *DNS64Config `json:"dns64,omitempty" yaml:"dns64,omitempty"`
+}
+
+func (n *NAT64Config) GetPrefix() string {
+	if n == nil || n.Prefix == "" {
+		return DefaultNAT64Prefix
+	}
+	return n.Prefix
+}
+
+// DNS64Config is the built-in DNS64 (RFC 6147) forwarder. It listens on
+// Address, relays queries to Upstreams and synthesizes AAAA answers from
+// A records under the NAT64 prefix. Address is what subscribers get as
+// their DNS server, in RA RDNSS and DHCPv6. Only the pool's subscribers
+// and the AllowedClients prefixes are answered.
+type DNS64Config struct {
+	Address        string   `json:"address" yaml:"address"`
+	Upstreams      []string `json:"upstreams" yaml:"upstreams"`
+	VRF            string   `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	AllowedClients []string `json:"allowed-clients,omitempty" yaml:"allowed-clients,omitempty"`
 }
 
 type InsidePrefix struct {
@@ -159,6 +421,15 @@ func (p *Pool) GetBlockSize() uint16 {
 	return 512
 }
 
+// GetPortsPerSubscriber is the size of each subscriber's port range in
+// a deterministic pool.
+func (p *Pool) GetPortsPerSubscriber() uint16 {
+	if p.PortsPerSubscriber > 0 {
+		return p.PortsPerSubscriber
+	}
+	return p.GetBlockSize()
+}
+
 func (p *Pool) GetPortRangeStart() uint16 {
 	if p.PortRange != "" {
 		start, _ := parsePortRange(p.PortRange)
@@ -5,8 +5,12 @@
 package cgnat
 
 import (
+	"net"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
 )
 
 func TestConfigValidate_NilOrEmpty(t *testing.T) {
@@ -135,3 +139,278 @@ func TestConfigValidate_WholesaleTwoPoolsTwoVRFs(t *testing.T) {
 		t.Fatalf("expected wholesale config to pass schema validation, got %v", err)
 	}
 }
+
+func TestConfigValidate_DSLite(t *testing.T) {
+	dslite := func(aftr *AFTRConfig) *Pool {
+		return &Pool{Mode: ModeDSLite, OutsideInterfaces: []string{"eth2"}, AFTR: aftr}
+	}
+	cases := []struct {
+		name  string
+		pools map[string]*Pool
+		want  string
+	}{
+		{"valid", map[string]*Pool{"ds": dslite(&AFTRConfig{Address: "2001:db8::1", Name: "aftr.example.net"})}, ""},
+		{"missing aftr", map[string]*Pool{"ds": dslite(nil)}, "requires aftr.address"},
+		{"ipv4 endpoint", map[string]*Pool{"ds": dslite(&AFTRConfig{Address: "192.0.2.1"})}, "not an IPv6 address"},
+		{"bad name", map[string]*Pool{"ds": dslite(&AFTRConfig{Address: "2001:db8::1", Name: "aftr..example"})}, "aftr.name"},
+		{"aftr on pba", map[string]*Pool{"res": {OutsideInterfaces: []string{"eth2"}, AFTR: &AFTRConfig{Address: "2001:db8::1"}}}, "only valid with mode dslite"},
+		{"two aftrs", map[string]*Pool{
+			"a": dslite(&AFTRConfig{Address: "2001:db8::1"}),
+			"b": dslite(&AFTRConfig{Address: "2001:db8::2"}),
+		}, "single AFTR"},
+	}
+	for _, tc := range cases {
+		err := (&Config{Pools: tc.pools}).Validate()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestConfigAFTRName(t *testing.T) {
+	cfg := &Config{Pools: map[string]*Pool{
+		"ds":  {Mode: ModeDSLite, AFTR: &AFTRConfig{Address: "2001:db8::1", Name: "aftr.example.net"}},
+		"res": {Mode: "pba"},
+	}}
+	if got := cfg.AFTRName("ds"); got != "aftr.example.net" {
+		t.Fatalf("AFTRName(ds) = %q", got)
+	}
+	if got := cfg.AFTRName("res"); got != "" {
+		t.Fatalf("AFTRName(res) = %q, want empty", got)
+	}
+}
+
+func TestConfigValidate_NAT64(t *testing.T) {
+	nat64 := func(n *NAT64Config) *Pool {
+		return &Pool{Mode: ModeNAT64, OutsideInterfaces: []string{"eth2"}, NAT64: n}
+	}
+	cases := []struct {
+		name  string
+		pools map[string]*Pool
+		want  string
+	}{
+		{"well-known prefix", map[string]*Pool{"v6": nat64(nil)}, ""},
+		{"network-specific prefix", map[string]*Pool{"v6": nat64(&NAT64Config{Prefix: "2001:db8:64::/96"})}, ""},
+		{"dns64", map[string]*Pool{"v6": nat64(&NAT64Config{DNS64: &DNS64Config{
+			Address: "2001:db8:53::1", Upstreams: []string{"192.0.2.53", "[2001:db8::53]:5353"},
+		}})}, ""},
+		{"bad prefix length", map[string]*Pool{"v6": nat64(&NAT64Config{Prefix: "2001:db8::/80"})}, "length must be"},
+		{"ipv4 prefix", map[string]*Pool{"v6": nat64(&NAT64Config{Prefix: "192.0.2.0/24"})}, "IPv6"},
+		{"no upstreams", map[string]*Pool{"v6": nat64(&NAT64Config{DNS64: &DNS64Config{Address: "2001:db8:53::1"}})}, "upstreams is required"},
+		{"bad upstream", map[string]*Pool{"v6": nat64(&NAT64Config{DNS64: &DNS64Config{
+			Address: "2001:db8:53::1", Upstreams: []string{"resolver"},
+		}})}, "upstreams[0]"},
+		{"allowed clients", map[string]*Pool{"v6": nat64(&NAT64Config{DNS64: &DNS64Config{
+			Address: "2001:db8:53::1", Upstreams: []string{"192.0.2.53"}, AllowedClients: []string{"2001:db8:100::/40"},
+		}})}, ""},
+		{"ipv4 allowed client", map[string]*Pool{"v6": nat64(&NAT64Config{DNS64: &DNS64Config{
+			Address: "2001:db8:53::1", Upstreams: []string{"192.0.2.53"}, AllowedClients: []string{"198.51.100.0/24"},
+		}})}, "allowed-clients[0]"},
+		{"nat64 on pba", map[string]*Pool{"res": {OutsideInterfaces: []string{"eth2"}, NAT64: &NAT64Config{}}}, "only valid with mode nat64"},
+		{"two nat64", map[string]*Pool{"a": nat64(nil), "b": nat64(nil)}, "single NAT64"},
+	}
+	for _, tc := range cases {
+		err := (&Config{Pools: tc.pools}).Validate()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestConfigDNS64Servers(t *testing.T) {
+	cfg := &Config{Pools: map[string]*Pool{
+		"v6":  {Mode: ModeNAT64, NAT64: &NAT64Config{DNS64: &DNS64Config{Address: "2001:db8:53::1"}}},
+		"res": {Mode: "pba"},
+	}}
+	if got := cfg.DNS64Servers("v6"); len(got) != 1 || !got[0].Equal(net.ParseIP("2001:db8:53::1")) {
+		t.Fatalf("DNS64Servers(v6) = %v", got)
+	}
+	if got := cfg.DNS64Servers("res"); got != nil {
+		t.Fatalf("DNS64Servers(res) = %v, want nil", got)
+	}
+}
+
+func TestConfigValidate_MAP(t *testing.T) {
+	u8 := func(v uint8) *uint8 { return &v }
+	mapT := func(domains map[string]*MAPDomain) *MAPConfig {
+		return &MAPConfig{Mode: MAPModeT, DMR: "2001:db8:ffff::/64", OutsideInterfaces: []string{"eth2"}, Domains: domains}
+	}
+	east := &MAPDomain{IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16}
+	cases := []struct {
+		name string
+		m    *MAPConfig
+		want string
+	}{
+		{"map-t", mapT(map[string]*MAPDomain{"east": east}), ""},
+		{"map-e", &MAPConfig{Mode: MAPModeE, BRAddress: "2001:db8:ffff::1", OutsideInterfaces: []string{"eth2"},
+			Domains: map[string]*MAPDomain{"east": east}}, ""},
+		{"unknown mode", &MAPConfig{Mode: "lw4o6"}, "mode must be"},
+		{"map-e without br", &MAPConfig{Mode: MAPModeE, OutsideInterfaces: []string{"eth2"}}, "requires br-address"},
+		{"bad dmr length", &MAPConfig{Mode: MAPModeT, DMR: "2001:db8::/80"}, "length must be"},
+		{"no domains", mapT(nil), "at least one domain"},
+		{"ea bits past /64", mapT(map[string]*MAPDomain{"d": {IPv6Prefix: "2001:db8::/56", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16}}), "exceeds the /64"},
+		{"ea bits short of suffix", mapT(map[string]*MAPDomain{"d": {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 6}}), "cannot carry"},
+		{"psid length mismatch", mapT(map[string]*MAPDomain{"d": {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16, PSIDLength: u8(6)}}), "does not match"},
+		{"port bits overflow", mapT(map[string]*MAPDomain{"d": {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16, PSIDOffset: u8(10)}}), "exceeds 16"},
+		{"overlapping ipv4", mapT(map[string]*MAPDomain{"a": east, "b": {IPv6Prefix: "2001:db8:100::/40", IPv4Prefix: "192.0.2.128/25", EABitsLength: 15}}), "overlapping"},
+	}
+	for _, tc := range cases {
+		err := (&Config{MAP: tc.m}).Validate()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestConfigMAPParams(t *testing.T) {
+	cfg := &Config{MAP: &MAPConfig{
+		Mode:      MAPModeE,
+		BRAddress: "2001:db8:ffff::1",
+		Domains: map[string]*MAPDomain{
+			"west":  {IPv6Prefix: "2001:db8:200::/40", IPv4Prefix: "198.51.100.0/24", EABitsLength: 16, Forwarding: true},
+			"east":  {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16},
+			"north": {IPv6Prefix: "2001:db8:100::/40", IPv4Prefix: "203.0.113.0/24", EABitsLength: 16, Forwarding: true},
+		},
+	}}
+	p := cfg.MAPParams("east")
+	if p == nil || len(p.Rules) != 3 {
+		t.Fatalf("MAPParams(east) = %+v, want BMR and two FMRs", p)
+	}
+	if p.Rules[0].IPv4Prefix.String() != "192.0.2.0/24" || p.Rules[0].PSIDLength != 8 || p.Rules[0].PSIDOffset != DefaultPSIDOffset {
+		t.Fatalf("BMR = %+v", p.Rules[0])
+	}
+	if p.Rules[1].IPv4Prefix.String() != "203.0.113.0/24" || p.Rules[2].IPv4Prefix.String() != "198.51.100.0/24" {
+		t.Fatalf("FMRs out of order: %s, %s", p.Rules[1].IPv4Prefix, p.Rules[2].IPv4Prefix)
+	}
+	if !p.BRAddress.Equal(net.ParseIP("2001:db8:ffff::1")) {
+		t.Fatalf("BRAddress = %v", p.BRAddress)
+	}
+	if got := cfg.MAPParams("missing"); got != nil {
+		t.Fatalf("MAPParams(missing) = %+v, want nil", got)
+	}
+}
+
+func TestConfigValidate_PortForwards(t *testing.T) {
+	pools := map[string]*Pool{
+		"p1": {OutsideInterfaces: []string{"eth2"}, OutsideAddresses: []string{"198.51.100.0/30"}, PortRange: "1024-65535"},
+	}
+	fwd := func(proto, inside string, insidePort uint16, outside string, outsidePort uint16) PortForward {
+		return PortForward{Protocol: proto, InsideIP: inside, InsidePort: insidePort, OutsideIP: outside, OutsidePort: outsidePort}
+	}
+	web := fwd("TCP", "100.64.0.10", 80, "198.51.100.1", 8080)
+	cases := []struct {
+		name     string
+		forwards []PortForward
+		pcp      *PCPConfig
+		want     string
+	}{
+		{"valid", []PortForward{web, fwd("udp", "100.64.0.10", 80, "198.51.100.1", 8080)}, &PCPConfig{Address: "100.64.0.1"}, ""},
+		{"bad protocol", []PortForward{fwd("sctp", "100.64.0.10", 80, "198.51.100.1", 8080)}, nil, "protocol must be"},
+		{"ipv6 inside", []PortForward{fwd("tcp", "2001:db8::1", 80, "198.51.100.1", 8080)}, nil, "inside-ip"},
+		{"missing port", []PortForward{fwd("tcp", "100.64.0.10", 0, "198.51.100.1", 8080)}, nil, "are required"},
+		{"outside not in pool", []PortForward{fwd("tcp", "100.64.0.10", 80, "203.0.113.1", 8080)}, nil, "not in the outside addresses"},
+		{"below port range", []PortForward{fwd("tcp", "100.64.0.10", 80, "198.51.100.1", 80)}, nil, "not in the outside addresses"},
+		{"duplicate inside", []PortForward{web, fwd("tcp", "100.64.0.10", 80, "198.51.100.1", 8081)}, nil, "inside tcp/100.64.0.10/80 duplicates"},
+		{"duplicate outside", []PortForward{web, fwd("tcp", "100.64.0.11", 80, "198.51.100.1", 8080)}, nil, "outside tcp/198.51.100.1/8080 duplicates"},
+		{"pcp address", nil, &PCPConfig{Address: "fe80::1"}, "pcp.address"},
+		{"pcp lifetimes", nil, &PCPConfig{Address: "100.64.0.1", MinLifetime: 600, MaxLifetime: 300}, "exceeds max-lifetime"},
+	}
+	for _, tc := range cases {
+		err := (&Config{Pools: pools, PortForwards: tc.forwards, PCP: tc.pcp}).Validate()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestConfigValidate_Archive(t *testing.T) {
+	if err := (&Config{Archive: &ArchiveConfig{}}).Validate(); err != nil {
+		t.Fatalf("default archive: %v", err)
+	}
+	err := (&Config{Archive: &ArchiveConfig{Directory: "archive"}}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "absolute path") {
+		t.Fatalf("relative directory: %v", err)
+	}
+	a := &ArchiveConfig{}
+	if a.GetDirectory() != DefaultArchiveDirectory || a.GetRetentionDays() != DefaultArchiveRetentionDays {
+		t.Fatalf("defaults = %q, %d", a.GetDirectory(), a.GetRetentionDays())
+	}
+}
+
+func TestConfigValidate_Thresholds(t *testing.T) {
+	pool := func(th *ip.PoolThresholds) *Config {
+		return &Config{Pools: map[string]*Pool{"p": {OutsideInterfaces: []string{"eth1"}, Thresholds: th}}}
+	}
+	if err := pool(&ip.PoolThresholds{High: 90, WithdrawRoute: true}).Validate(); err != nil {
+		t.Fatalf("valid thresholds: %v", err)
+	}
+	err := pool(&ip.PoolThresholds{OverflowPool: "q"}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "do not apply to CGNAT pools") {
+		t.Fatalf("overflow-pool: %v", err)
+	}
+	err = pool(&ip.PoolThresholds{High: 40, Low: 50}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "must be below high") {
+		t.Fatalf("low above high: %v", err)
+	}
+}
+
+func TestConfigValidate_SubscriberLimits(t *testing.T) {
+	pool := func(mode string, maxBlocks uint8, l *SubscriberLimitsConfig) *Config {
+		return &Config{Pools: map[string]*Pool{"p": {
+			OutsideInterfaces:      []string{"eth1"},
+			Mode:                   mode,
+			MaxBlocksPerSubscriber: maxBlocks,
+			SubscriberLimits:       l,
+		}}}
+	}
+	cases := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{"notify only", pool("", 0, &SubscriberLimitsConfig{NotifyInterval: time.Minute}), ""},
+		{"additional block", pool("pba", 4, &SubscriberLimitsConfig{Escalate: EscalateAdditionalBlock}), ""},
+		{"bypass", pool("pba", 0, &SubscriberLimitsConfig{Escalate: EscalateBypass, BypassServiceGroup: "heavy"}), ""},
+		{"deterministic", pool("deterministic", 0, &SubscriberLimitsConfig{}), "only valid with mode pba"},
+		{"single block", pool("pba", 1, &SubscriberLimitsConfig{Escalate: EscalateAdditionalBlock}), "max-blocks-per-subscriber above 1"},
+		{"bypass without group", pool("pba", 0, &SubscriberLimitsConfig{Escalate: EscalateBypass}), "requires bypass-service-group"},
+		{"group without bypass", pool("pba", 0, &SubscriberLimitsConfig{BypassServiceGroup: "heavy"}), "only valid with escalate bypass"},
+		{"unknown escalation", pool("pba", 0, &SubscriberLimitsConfig{Escalate: "upgrade"}), "must be additional-block or bypass"},
+	}
+	for _, tc := range cases {
+		err := tc.cfg.Validate()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// PCP (RFC 6887) defaults. The lifetime bounds clamp what clients ask
+// for; RFC 6887 §15 suggests two minutes as a floor and a day as a
+// ceiling for MAP requests.
+const (
+	DefaultPCPPort                     = 5351
+	DefaultPCPMinLifetime              = 120
+	DefaultPCPMaxLifetime              = 86400
+	DefaultPCPMaxMappingsPerSubscriber = 16
+)
+
+// PortForward is a static inbound mapping from one outside address and
+// port to a subscriber's inside address and port. The outside port must
+// fall inside the block the subscriber is allocated; the forward stays
+// pending until the subscriber holds that block.
+type PortForward struct {
+	Protocol    string `json:"protocol" yaml:"protocol"`
+	InsideIP    string `json:"inside-ip" yaml:"inside-ip"`
+	InsidePort  uint16 `json:"inside-port" yaml:"inside-port"`
+	OutsideIP   string `json:"outside-ip" yaml:"outside-ip"`
+	OutsidePort uint16 `json:"outside-port" yaml:"outside-port"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// PCPConfig is the subscriber-facing Port Control Protocol server. CPEs
+// send MAP requests to Address and get forwards inside their own port
+// block.
+type PCPConfig struct {
+	Address                  string `json:"address" yaml:"address"`
+	VRF                      string `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	MinLifetime              uint32 `json:"min-lifetime,omitempty" yaml:"min-lifetime,omitempty"`
+	MaxLifetime              uint32 `json:"max-lifetime,omitempty" yaml:"max-lifetime,omitempty"`
+	MaxMappingsPerSubscriber uint16 `json:"max-mappings-per-subscriber,omitempty" yaml:"max-mappings-per-subscriber,omitempty"`
+}
+
+func (p *PCPConfig) GetMinLifetime() uint32 {
+	if p == nil || p.MinLifetime == 0 {
+		return DefaultPCPMinLifetime
+	}
+	return p.MinLifetime
+}
+
+func (p *PCPConfig) GetMaxLifetime() uint32 {
+	if p == nil || p.MaxLifetime == 0 {
+		return DefaultPCPMaxLifetime
+	}
+	return p.MaxLifetime
+}
+
+func (p *PCPConfig) GetMaxMappingsPerSubscriber() uint16 {
+	if p == nil || p.MaxMappingsPerSubscriber == 0 {
+		return DefaultPCPMaxMappingsPerSubscriber
+	}
+	return p.MaxMappingsPerSubscriber
+}
+
+func (p *PCPConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	if ip := net.ParseIP(p.Address); ip == nil || ip.To4() == nil {
+		return fmt.Errorf("cgnat: pcp.address %q is not an IPv4 address", p.Address)
+	}
+	if p.GetMinLifetime() > p.GetMaxLifetime() {
+		return fmt.Errorf("cgnat: pcp.min-lifetime %d exceeds max-lifetime %d", p.GetMinLifetime(), p.GetMaxLifetime())
+	}
+	return nil
+}
+
+// NormalizeForwardProtocol lower-cases a forward protocol and reports
+// whether it is one the dataplane can forward.
+func NormalizeForwardProtocol(proto string) (string, bool) {
+	proto = strings.ToLower(proto)
+	return proto, proto == "tcp" || proto == "udp"
+}
+
+// ForwardPool returns the name of the port-block pool whose outside
+// addresses and port range hold ip:port, or "" when none does.
+func (c *Config) ForwardPool(ip net.IP, port uint16) string {
+	if c == nil || ip.To4() == nil {
+		return ""
+	}
+	for name, pool := range c.Pools {
+		if pool == nil || pool.GetMode() != "pba" {
+			continue
+		}
+		if port < pool.GetPortRangeStart() || port > pool.GetPortRangeEnd() {
+			continue
+		}
+		for _, addr := range pool.OutsideAddresses {
+			if outsideAddressContains(addr, ip) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func outsideAddressContains(addr string, ip net.IP) bool {
+	if _, ipNet, err := net.ParseCIDR(addr); err == nil {
+		return ipNet.Contains(ip)
+	}
+	a := net.ParseIP(addr)
+	return a != nil && a.Equal(ip)
+}
+
+func (c *Config) validatePortForwards() error {
+	inside := make(map[string]int, len(c.PortForwards))
+	outside := make(map[string]int, len(c.PortForwards))
+	for i, f := range c.PortForwards {
+		proto, ok := NormalizeForwardProtocol(f.Protocol)
+		if !ok {
+			return fmt.Errorf("cgnat: port-forwards[%d]: protocol must be tcp or udp, got %q", i, f.Protocol)
+		}
+		insideIP := net.ParseIP(f.InsideIP)
+		if insideIP == nil || insideIP.To4() == nil {
+			return fmt.Errorf("cgnat: port-forwards[%d]: inside-ip %q is not an IPv4 address", i, f.InsideIP)
+		}
+		outsideIP := net.ParseIP(f.OutsideIP)
+		if outsideIP == nil || outsideIP.To4() == nil {
+			return fmt.Errorf("cgnat: port-forwards[%d]: outside-ip %q is not an IPv4 address", i, f.OutsideIP)
+		}
+		if f.InsidePort == 0 || f.OutsidePort == 0 {
+			return fmt.Errorf("cgnat: port-forwards[%d]: inside-port and outside-port are required", i)
+		}
+		if c.ForwardPool(outsideIP, f.OutsidePort) == "" {
+			return fmt.Errorf("cgnat: port-forwards[%d]: %s:%d is not in the outside addresses and port range of any pba pool", i, f.OutsideIP, f.OutsidePort)
+		}
+		in := fmt.Sprintf("%s/%s/%d", proto, insideIP, f.InsidePort)
+		if j, dup := inside[in]; dup {
+			return fmt.Errorf("cgnat: port-forwards[%d]: inside %s duplicates port-forwards[%d]", i, in, j)
+		}
+		inside[in] = i
+		out := fmt.Sprintf("%s/%s/%d", proto, outsideIP, f.OutsidePort)
+		if j, dup := outside[out]; dup {
+			return fmt.Errorf("cgnat: port-forwards[%d]: outside %s duplicates port-forwards[%d]", i, out, j)
+		}
+		outside[out] = i
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultLimitNotifyInterval = 5 * time.Minute
+
+	EscalateAdditionalBlock = "additional-block"
+	EscalateBypass          = "bypass"
+)
+
+// SubscriberLimitsConfig controls what happens when a PBA subscriber
+// hits max-sessions-per-subscriber or runs out of ports in its blocks.
+// Each subscriber gets at most one event per limit per NotifyInterval;
+// drops in between are summed into the next one.
+//
+// Escalate optionally lifts the subscriber: additional-block gives a
+// subscriber that ran out of ports another block, up to
+// max-blocks-per-subscriber, and bypass takes a subscriber that hit
+// either limit out of CGNAT as if it were in BypassServiceGroup, which
+// must be a service group with cgnat.bypass set.
+type SubscriberLimitsConfig struct {
+	NotifyInterval     time.Duration `json:"notify-interval,omitempty" yaml:"notify-interval,omitempty"`
+	Escalate           string        `json:"escalate,omitempty" yaml:"escalate,omitempty"`
+	BypassServiceGroup string        `json:"bypass-service-group,omitempty" yaml:"bypass-service-group,omitempty"`
+}
+
+func (l *SubscriberLimitsConfig) GetNotifyInterval() time.Duration {
+	if l == nil || l.NotifyInterval <= 0 {
+		return DefaultLimitNotifyInterval
+	}
+	return l.NotifyInterval
+}
+
+func (l *SubscriberLimitsConfig) GetEscalate() string {
+	if l == nil {
+		return ""
+	}
+	return l.Escalate
+}
+
+func (p *Pool) validateSubscriberLimits(name string) error {
+	l := p.SubscriberLimits
+	if l == nil {
+		return nil
+	}
+	if p.GetMode() != "pba" {
+		return fmt.Errorf("cgnat: pool %q: subscriber-limits is only valid with mode pba", name)
+	}
+	if l.NotifyInterval < 0 {
+		return fmt.Errorf("cgnat: pool %q: subscriber-limits.notify-interval must not be negative", name)
+	}
+	switch l.Escalate {
+	case "":
+	case EscalateAdditionalBlock:
+		if p.GetMaxBlocksPerSubscriber() < 2 {
+			return fmt.Errorf("cgnat: pool %q: subscriber-limits: escalate %s needs max-blocks-per-subscriber above 1", name, l.Escalate)
+		}
+	case EscalateBypass:
+		if l.BypassServiceGroup == "" {
+			return fmt.Errorf("cgnat: pool %q: subscriber-limits: escalate bypass requires bypass-service-group", name)
+		}
+	default:
+		return fmt.Errorf("cgnat: pool %q: subscriber-limits.escalate %q: must be %s or %s", name, l.Escalate, EscalateAdditionalBlock, EscalateBypass)
+	}
+	if l.BypassServiceGroup != "" && l.Escalate != EscalateBypass {
+		return fmt.Errorf("cgnat: pool %q: subscriber-limits: bypass-service-group is only valid with escalate bypass", name)
+	}
+	return nil
+}
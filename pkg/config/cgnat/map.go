@@ -0,0 +1,242 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// MAP border relay modes: MAP-E (RFC 7597) encapsulates IPv4 in IPv6,
+// MAP-T (RFC 7599) translates it.
+const (
+	MAPModeE          = "map-e"
+	MAPModeT          = "map-t"
+	DefaultPSIDOffset = 6
+	DefaultMAPMTU     = 1280
+)
+
+// MAPConfig is the MAP border relay. The domains are stateless: each
+// CE derives its IPv4 address and port set from its delegated prefix,
+// so the BR holds no per-subscriber state.
+type MAPConfig struct {
+	Mode              string                `json:"mode" yaml:"mode"`
+	BRAddress         string                `json:"br-address,omitempty" yaml:"br-address,omitempty"`
+	DMR               string                `json:"dmr,omitempty" yaml:"dmr,omitempty"`
+	OutsideInterfaces []string              `json:"outside_interfaces,omitempty" yaml:"outside_interfaces,omitempty"`
+	MTU               uint16                `json:"mtu,omitempty" yaml:"mtu,omitempty"`
+	Domains           map[string]*MAPDomain `json:"domains,omitempty" yaml:"domains,omitempty"`
+}
+
+// MAPDomain is one MAP domain and its Basic Mapping Rule. Forwarding
+// also advertises the rule as a Forwarding Mapping Rule to every CE, so
+// CEs of other domains reach this one directly rather than via the BR.
+type MAPDomain struct {
+	IPv6Prefix   string `json:"ipv6-prefix" yaml:"ipv6-prefix"`
+	IPv4Prefix   string `json:"ipv4-prefix" yaml:"ipv4-prefix"`
+	EABitsLength uint8  `json:"ea-bits-length" yaml:"ea-bits-length"`
+	PSIDOffset   *uint8 `json:"psid-offset,omitempty" yaml:"psid-offset,omitempty"`
+	PSIDLength   *uint8 `json:"psid-length,omitempty" yaml:"psid-length,omitempty"`
+	Forwarding   bool   `json:"forwarding,omitempty" yaml:"forwarding,omitempty"`
+}
+
+func (m *MAPConfig) GetMTU() uint16 {
+	if m == nil || m.MTU == 0 {
+		return DefaultMAPMTU
+	}
+	return m.MTU
+}
+
+func (d *MAPDomain) GetPSIDOffset() uint8 {
+	if d == nil || d.PSIDOffset == nil {
+		return DefaultPSIDOffset
+	}
+	return *d.PSIDOffset
+}
+
+// GetPSIDLength returns the configured PSID length, or the one the EA
+// bits imply: whatever they carry beyond the IPv4 suffix (RFC 7597 5.2).
+func (d *MAPDomain) GetPSIDLength() uint8 {
+	if d == nil {
+		return 0
+	}
+	if d.PSIDLength != nil {
+		return *d.PSIDLength
+	}
+	_, v4, err := net.ParseCIDR(d.IPv4Prefix)
+	if err != nil {
+		return 0
+	}
+	ones, _ := v4.Mask.Size()
+	if suffix := 32 - ones; int(d.EABitsLength) > suffix {
+		return d.EABitsLength - uint8(suffix)
+	}
+	return 0
+}
+
+func (m *MAPConfig) validate() error {
+	if m == nil {
+		return nil
+	}
+	switch m.Mode {
+	case MAPModeE:
+		if ip := net.ParseIP(m.BRAddress); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("cgnat: map: mode map-e requires br-address to be an IPv6 address, got %q", m.BRAddress)
+		}
+		if m.DMR != "" {
+			return fmt.Errorf("cgnat: map: dmr is only valid with mode map-t")
+		}
+	case MAPModeT:
+		_, dmr, err := net.ParseCIDR(m.DMR)
+		if err != nil || dmr.IP.To4() != nil {
+			return fmt.Errorf("cgnat: map: mode map-t requires dmr to be an IPv6 prefix, got %q", m.DMR)
+		}
+		switch ones, _ := dmr.Mask.Size(); ones {
+		case 32, 40, 48, 56, 64, 96:
+		default:
+			return fmt.Errorf("cgnat: map: dmr %q: length must be 32, 40, 48, 56, 64 or 96 (RFC 6052)", m.DMR)
+		}
+		if m.BRAddress != "" {
+			return fmt.Errorf("cgnat: map: br-address is only valid with mode map-e")
+		}
+	default:
+		return fmt.Errorf("cgnat: map: mode must be %q or %q, got %q", MAPModeE, MAPModeT, m.Mode)
+	}
+	if len(m.OutsideInterfaces) == 0 {
+		return fmt.Errorf("cgnat: map: outside_interfaces is required")
+	}
+	if len(m.Domains) == 0 {
+		return fmt.Errorf("cgnat: map: at least one domain is required")
+	}
+	if m.MTU != 0 && m.MTU < 1280 {
+		return fmt.Errorf("cgnat: map: mtu %d is below the IPv6 minimum of 1280", m.MTU)
+	}
+
+	v4s := make(map[string]*net.IPNet, len(m.Domains))
+	for name, d := range m.Domains {
+		if d == nil {
+			return fmt.Errorf("cgnat: map: domain %q is empty", name)
+		}
+		v4, err := d.validate(name)
+		if err != nil {
+			return err
+		}
+		for other, n := range v4s {
+			if n.Contains(v4.IP) || v4.Contains(n.IP) {
+				return fmt.Errorf("cgnat: map: domains %q and %q have overlapping ipv4-prefix", other, name)
+			}
+		}
+		v4s[name] = v4
+	}
+	return nil
+}
+
+func (d *MAPDomain) validate(name string) (*net.IPNet, error) {
+	_, v6, err := net.ParseCIDR(d.IPv6Prefix)
+	if err != nil || v6.IP.To4() != nil {
+		return nil, fmt.Errorf("cgnat: map: domain %q: ipv6-prefix %q is not an IPv6 prefix", name, d.IPv6Prefix)
+	}
+	_, v4, err := net.ParseCIDR(d.IPv4Prefix)
+	if err != nil || v4.IP.To4() == nil {
+		return nil, fmt.Errorf("cgnat: map: domain %q: ipv4-prefix %q is not an IPv4 prefix", name, d.IPv4Prefix)
+	}
+	v6Len, _ := v6.Mask.Size()
+	v4Len, _ := v4.Mask.Size()
+	if d.EABitsLength > 48 {
+		return nil, fmt.Errorf("cgnat: map: domain %q: ea-bits-length %d exceeds 48", name, d.EABitsLength)
+	}
+	if v6Len+int(d.EABitsLength) > 64 {
+		return nil, fmt.Errorf("cgnat: map: domain %q: ipv6-prefix length %d plus ea-bits-length %d exceeds the /64 end-user prefix", name, v6Len, d.EABitsLength)
+	}
+	suffix := 32 - v4Len
+	if int(d.EABitsLength) < suffix {
+		return nil, fmt.Errorf("cgnat: map: domain %q: ea-bits-length %d cannot carry the %d-bit IPv4 suffix of %s", name, d.EABitsLength, suffix, d.IPv4Prefix)
+	}
+	psidLen := d.GetPSIDLength()
+	if int(psidLen) != int(d.EABitsLength)-suffix {
+		return nil, fmt.Errorf("cgnat: map: domain %q: psid-length %d does not match ea-bits-length %d less the %d-bit IPv4 suffix", name, psidLen, d.EABitsLength, suffix)
+	}
+	if int(d.GetPSIDOffset())+int(psidLen) > 16 {
+		return nil, fmt.Errorf("cgnat: map: domain %q: psid-offset %d plus psid-length %d exceeds 16 port bits", name, d.GetPSIDOffset(), psidLen)
+	}
+	return v4, nil
+}
+
+// MAPRule is one mapping rule as a CE receives it (RFC 7598 4.1).
+type MAPRule struct {
+	IPv6Prefix   *net.IPNet
+	IPv4Prefix   *net.IPNet
+	EABitsLength uint8
+	PSIDOffset   uint8
+	PSIDLength   uint8
+	Forwarding   bool
+}
+
+// MAPParams is the softwire configuration a CE in a MAP domain needs:
+// its Basic Mapping Rule first, then the Forwarding Mapping Rules of the
+// other domains, and the BR address (MAP-E) or DMR (MAP-T).
+type MAPParams struct {
+	Mode      string
+	Rules     []MAPRule
+	BRAddress net.IP
+	DMR       *net.IPNet
+}
+
+// MAPParams returns the softwire configuration for subscribers of a MAP
+// domain, or nil when domain is not configured.
+func (c *Config) MAPParams(domain string) *MAPParams {
+	if c == nil || c.MAP == nil || domain == "" {
+		return nil
+	}
+	bmr := c.MAP.Domains[domain]
+	if bmr == nil {
+		return nil
+	}
+	rule, ok := bmr.rule()
+	if !ok {
+		return nil
+	}
+	p := &MAPParams{Mode: c.MAP.Mode, Rules: []MAPRule{rule}}
+
+	names := make([]string, 0, len(c.MAP.Domains))
+	for name := range c.MAP.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := c.MAP.Domains[name]
+		if name == domain || d == nil || !d.Forwarding {
+			continue
+		}
+		if r, ok := d.rule(); ok {
+			p.Rules = append(p.Rules, r)
+		}
+	}
+
+	switch c.MAP.Mode {
+	case MAPModeE:
+		p.BRAddress = net.ParseIP(c.MAP.BRAddress)
+	case MAPModeT:
+		_, p.DMR, _ = net.ParseCIDR(c.MAP.DMR)
+	}
+	return p
+}
+
+func (d *MAPDomain) rule() (MAPRule, bool) {
+	_, v6, err6 := net.ParseCIDR(d.IPv6Prefix)
+	_, v4, err4 := net.ParseCIDR(d.IPv4Prefix)
+	if err6 != nil || err4 != nil {
+		return MAPRule{}, false
+	}
+	return MAPRule{
+		IPv6Prefix:   v6,
+		IPv4Prefix:   v4,
+		EABitsLength: d.EABitsLength,
+		PSIDOffset:   d.GetPSIDOffset(),
+		PSIDLength:   d.GetPSIDLength(),
+		Forwarding:   d.Forwarding,
+	}, true
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ip
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultOnDemandProvider     = "http"
+	DefaultOnDemandMaxChunks    = 16
+	DefaultOnDemandReleaseGrace = time.Hour
+
+	// maxChunkEntryBits caps a chunk at 65536 addresses or delegated
+	// prefixes, as the allocator keeps a free list entry for each.
+	maxChunkEntryBits = 16
+)
+
+// OnDemandPools grows a profile with address space from a central IPAM
+// instead of pre-carving it per BNG. Once utilisation of the profile's
+// pools in VRF reaches Threshold percent, a chunk of ChunkLength is
+// requested from Provider and added to the profile as a pool, behind
+// its configured pools. A chunk left without leases for ReleaseGrace is
+// returned, unless returning it would put the profile straight back
+// over Threshold. PrefixLength is the length delegated from PD chunks.
+type OnDemandPools struct {
+	Provider     string        `json:"provider,omitempty" yaml:"provider,omitempty"`
+	ChunkLength  uint8         `json:"chunk_length" yaml:"chunk-length"`
+	PrefixLength uint8         `json:"prefix_length,omitempty" yaml:"prefix-length,omitempty"`
+	VRF          string        `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	Threshold    uint8         `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	MaxChunks    int           `json:"max_chunks,omitempty" yaml:"max-chunks,omitempty"`
+	ReleaseGrace time.Duration `json:"release_grace,omitempty" yaml:"release-grace,omitempty"`
+}
+
+func (o *OnDemandPools) GetProvider() string {
+	if o == nil || o.Provider == "" {
+		return DefaultOnDemandProvider
+	}
+	return o.Provider
+}
+
+func (o *OnDemandPools) GetThreshold() uint8 {
+	if o == nil || o.Threshold == 0 {
+		return DefaultPoolHighWatermark
+	}
+	return o.Threshold
+}
+
+func (o *OnDemandPools) GetMaxChunks() int {
+	if o == nil || o.MaxChunks == 0 {
+		return DefaultOnDemandMaxChunks
+	}
+	return o.MaxChunks
+}
+
+func (o *OnDemandPools) GetReleaseGrace() time.Duration {
+	if o == nil || o.ReleaseGrace == 0 {
+		return DefaultOnDemandReleaseGrace
+	}
+	return o.ReleaseGrace
+}
+
+// Validate checks the chunk and delegated lengths against the address
+// family: bits is 32 for IPv4 and 128 for IPv6, and delegating is set
+// for PD, which needs a PrefixLength longer than the chunk.
+func (o *OnDemandPools) Validate(bits uint8, delegating bool) error {
+	if o == nil {
+		return nil
+	}
+	if o.ChunkLength == 0 || o.ChunkLength >= bits-1 {
+		return fmt.Errorf("chunk-length %d: must be between 1 and %d", o.ChunkLength, bits-2)
+	}
+	entryLength := bits
+	if delegating {
+		if o.PrefixLength <= o.ChunkLength || o.PrefixLength > 64 {
+			return fmt.Errorf("prefix-length %d: must be longer than chunk-length %d and at most 64", o.PrefixLength, o.ChunkLength)
+		}
+		entryLength = o.PrefixLength
+	} else if o.PrefixLength != 0 {
+		return fmt.Errorf("prefix-length only applies to PD chunks")
+	}
+	if entryLength-o.ChunkLength > maxChunkEntryBits {
+		return fmt.Errorf("chunk-length %d: must be /%d or longer", o.ChunkLength, entryLength-maxChunkEntryBits)
+	}
+	if o.Threshold > 100 {
+		return fmt.Errorf("threshold %d: must be a percentage (1-100)", o.Threshold)
+	}
+	if o.MaxChunks < 0 {
+		return fmt.Errorf("max-chunks must not be negative")
+	}
+	if o.ReleaseGrace < 0 {
+		return fmt.Errorf("release-grace must not be negative")
+	}
+	return nil
+}
@@ -7,38 +7,41 @@ import (
 )
 
 type IPv4Pool struct {
-	Name       string       `json:"name,omitempty" yaml:"name,omitempty"`
-	Network    string       `json:"network,omitempty" yaml:"network,omitempty"`
-	RangeStart string       `json:"range_start,omitempty" yaml:"range-start,omitempty"`
-	RangeEnd   string       `json:"range_end,omitempty" yaml:"range-end,omitempty"`
-	Gateway    string       `json:"gateway,omitempty" yaml:"gateway,omitempty"`
-	VRF        string       `json:"vrf,omitempty" yaml:"vrf,omitempty"`
-	DNSServers []string     `json:"dns_servers,omitempty" yaml:"dns,omitempty"`
-	LeaseTime  uint32       `json:"lease_time,omitempty" yaml:"lease-time,omitempty"`
-	Priority   int          `json:"priority,omitempty" yaml:"priority,omitempty"`
-	Exclude    []string     `json:"exclude,omitempty" yaml:"exclude,omitempty"`
-	Options    []DHCPOption `json:"dhcp_options,omitempty" yaml:"dhcp-options,omitempty"`
+	Name       string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Network    string          `json:"network,omitempty" yaml:"network,omitempty"`
+	RangeStart string          `json:"range_start,omitempty" yaml:"range-start,omitempty"`
+	RangeEnd   string          `json:"range_end,omitempty" yaml:"range-end,omitempty"`
+	Gateway    string          `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+	VRF        string          `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	DNSServers []string        `json:"dns_servers,omitempty" yaml:"dns,omitempty"`
+	LeaseTime  uint32          `json:"lease_time,omitempty" yaml:"lease-time,omitempty"`
+	Priority   int             `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Exclude    []string        `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	Options    []DHCPOption    `json:"dhcp_options,omitempty" yaml:"dhcp-options,omitempty"`
+	Thresholds *PoolThresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
 }
 
 type IANAPool struct {
-	Name          string         `json:"name,omitempty" yaml:"name,omitempty"`
-	Network       string         `json:"network,omitempty" yaml:"network,omitempty"`
-	RangeStart    string         `json:"range_start,omitempty" yaml:"range_start,omitempty"`
-	RangeEnd      string         `json:"range_end,omitempty" yaml:"range_end,omitempty"`
-	Gateway       string         `json:"gateway,omitempty" yaml:"gateway,omitempty"`
-	VRF           string         `json:"vrf,omitempty" yaml:"vrf,omitempty"`
-	PreferredTime uint32         `json:"preferred_time,omitempty" yaml:"preferred_time,omitempty"`
-	ValidTime     uint32         `json:"valid_time,omitempty" yaml:"valid_time,omitempty"`
-	Options       []DHCPv6Option `json:"dhcpv6_options,omitempty" yaml:"dhcpv6-options,omitempty"`
+	Name          string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Network       string          `json:"network,omitempty" yaml:"network,omitempty"`
+	RangeStart    string          `json:"range_start,omitempty" yaml:"range_start,omitempty"`
+	RangeEnd      string          `json:"range_end,omitempty" yaml:"range_end,omitempty"`
+	Gateway       string          `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+	VRF           string          `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	PreferredTime uint32          `json:"preferred_time,omitempty" yaml:"preferred_time,omitempty"`
+	ValidTime     uint32          `json:"valid_time,omitempty" yaml:"valid_time,omitempty"`
+	Options       []DHCPv6Option  `json:"dhcpv6_options,omitempty" yaml:"dhcpv6-options,omitempty"`
+	Thresholds    *PoolThresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
 }
 
 type PDPool struct {
-	Name          string `json:"name,omitempty" yaml:"name,omitempty"`
-	Network       string `json:"network,omitempty" yaml:"network,omitempty"`
-	PrefixLength  uint8  `json:"prefix_length,omitempty" yaml:"prefix_length,omitempty"`
-	VRF           string `json:"vrf,omitempty" yaml:"vrf,omitempty"`
-	PreferredTime uint32 `json:"preferred_time,omitempty" yaml:"preferred_time,omitempty"`
-	ValidTime     uint32 `json:"valid_time,omitempty" yaml:"valid_time,omitempty"`
+	Name          string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Network       string          `json:"network,omitempty" yaml:"network,omitempty"`
+	PrefixLength  uint8           `json:"prefix_length,omitempty" yaml:"prefix_length,omitempty"`
+	VRF           string          `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	PreferredTime uint32          `json:"preferred_time,omitempty" yaml:"preferred_time,omitempty"`
+	ValidTime     uint32          `json:"valid_time,omitempty" yaml:"valid_time,omitempty"`
+	Thresholds    *PoolThresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
 }
 
 type IPv6RAConfig struct {
@@ -171,6 +174,8 @@ type IPv4Profile struct {
 	DNS     []string         `json:"dns,omitempty" yaml:"dns,omitempty"`
 	DHCP    *IPv4DHCPOptions `json:"dhcp,omitempty" yaml:"dhcp,omitempty"`
 	IPCP    *IPv4ICPPOptions `json:"ipcp,omitempty" yaml:"ipcp,omitempty"`
+	// OnDemand adds pools from a central IPAM as the profile fills.
+	OnDemand *OnDemandPools `json:"on_demand,omitempty" yaml:"on-demand,omitempty"`
 }
 
 func (p *IPv4Profile) GetMode() string {
@@ -272,6 +277,10 @@ type IPv6Profile struct {
 	RA        *IPv6RAConfig      `json:"ra,omitempty" yaml:"ra,omitempty"`
 	DHCPv6    *IPv6DHCPv6Options `json:"dhcpv6,omitempty" yaml:"dhcpv6,omitempty"`
 	IPv6CP    *IPv6CPOptions     `json:"ipv6cp,omitempty" yaml:"ipv6cp,omitempty"`
+	// IANAOnDemand and PDOnDemand add IANA and PD pools from a central
+	// IPAM as the profile fills.
+	IANAOnDemand *OnDemandPools `json:"iana_on_demand,omitempty" yaml:"iana-on-demand,omitempty"`
+	PDOnDemand   *OnDemandPools `json:"pd_on_demand,omitempty" yaml:"pd-on-demand,omitempty"`
 }
 
 func (p *IPv6Profile) GetMode() string {
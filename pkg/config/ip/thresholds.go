@@ -0,0 +1,63 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ip
+
+import "fmt"
+
+const (
+	DefaultPoolHighWatermark = 90
+	defaultPoolHysteresis    = 10
+)
+
+// PoolThresholds sets a pool's utilisation watermarks, in percent of
+// its addresses (or delegated prefixes) in use. Reaching High raises the
+// pool to the high level; it returns to normal only once utilisation
+// falls to Low, so a pool hovering around High does not flap. A pool
+// with nothing left to allocate is exhausted whatever its watermarks.
+//
+// OverflowPool and LowerPriority apply while the pool is above normal:
+// the first names a pool of the same profile that new sessions try
+// first, the second moves the pool behind every other pool of its
+// profile. WithdrawRoute
+// is independent of the level: it withdraws the pool's BGP network while
+// nothing is allocated from it, so upstream traffic shifts to the HA
+// peer, and restores it once the pool is in use again.
+type PoolThresholds struct {
+	High          uint8  `json:"high,omitempty" yaml:"high,omitempty"`
+	Low           uint8  `json:"low,omitempty" yaml:"low,omitempty"`
+	OverflowPool  string `json:"overflow_pool,omitempty" yaml:"overflow-pool,omitempty"`
+	LowerPriority bool   `json:"lower_priority,omitempty" yaml:"lower-priority,omitempty"`
+	WithdrawRoute bool   `json:"withdraw_route,omitempty" yaml:"withdraw-route,omitempty"`
+}
+
+func (t *PoolThresholds) GetHigh() uint8 {
+	if t == nil || t.High == 0 {
+		return DefaultPoolHighWatermark
+	}
+	return t.High
+}
+
+func (t *PoolThresholds) GetLow() uint8 {
+	if t != nil && t.Low != 0 {
+		return t.Low
+	}
+	if high := t.GetHigh(); high > defaultPoolHysteresis {
+		return high - defaultPoolHysteresis
+	}
+	return 0
+}
+
+func (t *PoolThresholds) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.High > 100 {
+		return fmt.Errorf("high %d: must be a percentage (1-100)", t.High)
+	}
+	if t.GetLow() >= t.GetHigh() {
+		return fmt.Errorf("low %d must be below high %d", t.GetLow(), t.GetHigh())
+	}
+	return nil
+}
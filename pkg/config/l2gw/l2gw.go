@@ -24,9 +24,20 @@ type L2GWConfig struct {
 // egress VLANs from the configured ranges unless RADIUS supplies
 // explicit values.
 type HandoffGroup struct {
-	Interface string `json:"interface" yaml:"interface"`
+	Interface string `json:"interface,omitempty" yaml:"interface,omitempty"`
 	VlanTpid  string `json:"vlan-tpid,omitempty" yaml:"vlan-tpid,omitempty"`
 
+	// Members replaces Interface with an ordered list of redundant
+	// handoff ports: the first member with link up carries every
+	// circuit of the group, the rest stand by. Mutually exclusive with
+	// Interface.
+	Members []HandoffMember `json:"members,omitempty" yaml:"members,omitempty"`
+
+	// Revertive moves circuits back to a more preferred member as soon
+	// as its link returns. Off by default: circuits stay on the member
+	// that took over until it fails.
+	Revertive bool `json:"revertive,omitempty" yaml:"revertive,omitempty"`
+
 	// SVLAN pins every circuit of this group to one outer VLAN
 	// (VLAN-per-ISP model). Mutually exclusive with SVLANRange.
 	SVLAN uint16 `json:"svlan,omitempty" yaml:"svlan,omitempty"`
@@ -37,6 +48,12 @@ type HandoffGroup struct {
 	CVLANRange string `json:"cvlan-range,omitempty" yaml:"cvlan-range,omitempty"`
 }
 
+// HandoffMember is one port of a redundant handoff group. Members are
+// tried in list order.
+type HandoffMember struct {
+	Interface string `json:"interface" yaml:"interface"`
+}
+
 // StaticMap cross-connects an entire access S-VLAN (all C-VLANs,
 // wildcard) to a handoff group with no DHCP trigger and no RADIUS.
 type StaticMap struct {
@@ -61,6 +78,27 @@ func (hg *HandoffGroup) GetOuterTPID() uint16 {
 	return 0x88A8
 }
 
+// MemberInterfaces returns the group's handoff ports in preference
+// order: the members, or the single interface.
+func (hg *HandoffGroup) MemberInterfaces() []string {
+	if len(hg.Members) == 0 {
+		if hg.Interface == "" {
+			return nil
+		}
+		return []string{hg.Interface}
+	}
+	out := make([]string, 0, len(hg.Members))
+	for _, m := range hg.Members {
+		out = append(out, m.Interface)
+	}
+	return out
+}
+
+// Redundant reports whether the group fails over between members.
+func (hg *HandoffGroup) Redundant() bool {
+	return len(hg.Members) > 0
+}
+
 func (sm *StaticMap) GetSVLANs() ([]uint16, error) {
 	return vlan.ParseVLANRange(sm.SVLAN)
 }
@@ -71,8 +109,24 @@ func (c *L2GWConfig) Validate() error {
 		return nil
 	}
 	for name, hg := range c.HandoffGroups {
-		if hg == nil || hg.Interface == "" {
-			return fmt.Errorf("l2gw handoff-group %q: interface is required", name)
+		if hg == nil || (hg.Interface == "" && len(hg.Members) == 0) {
+			return fmt.Errorf("l2gw handoff-group %q: interface or members is required", name)
+		}
+		if hg.Interface != "" && len(hg.Members) > 0 {
+			return fmt.Errorf("l2gw handoff-group %q: interface and members are mutually exclusive", name)
+		}
+		seen := make(map[string]bool, len(hg.Members))
+		for i, m := range hg.Members {
+			if m.Interface == "" {
+				return fmt.Errorf("l2gw handoff-group %q: members[%d]: interface is required", name, i)
+			}
+			if seen[m.Interface] {
+				return fmt.Errorf("l2gw handoff-group %q: member %q listed twice", name, m.Interface)
+			}
+			seen[m.Interface] = true
+		}
+		if hg.Revertive && len(hg.Members) == 0 {
+			return fmt.Errorf("l2gw handoff-group %q: revertive requires members", name)
 		}
 		if hg.SVLAN != 0 && hg.SVLANRange != "" {
 			return fmt.Errorf("l2gw handoff-group %q: svlan and svlan-range are mutually exclusive", name)
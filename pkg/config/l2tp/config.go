@@ -5,6 +5,9 @@
 package l2tp
 
 import (
+	"fmt"
+	"math"
+	"net"
 	"strings"
 	"time"
 )
@@ -54,6 +57,34 @@ type L2TPConfig struct {
 	TunnelPools  map[string]*TunnelPool `json:"tunnel-pools,omitempty"  yaml:"tunnel-pools,omitempty"`
 	Profiles     map[string]*Profile    `json:"profiles,omitempty"      yaml:"profiles,omitempty"`
 	PeerPolicies map[string]*PeerPolicy `json:"peer-policies,omitempty" yaml:"peer-policies,omitempty"`
+	Failover     *Failover              `json:"failover,omitempty"      yaml:"failover,omitempty"`
+}
+
+// DefaultRecoveryTime is the RFC 4951 Recovery Time advertised when
+// failover is not configured: how long the peer should hold a tunnel
+// after this node fails, for it to restart or for the standby to take
+// over.
+const DefaultRecoveryTime = 30 * time.Second
+
+// Failover configures RFC 4951 control-channel failover. It is on by
+// default; a tunnel survives a restart or an HA takeover only when its
+// peer advertised failover as well.
+type Failover struct {
+	Disabled     bool          `json:"disabled,omitempty"      yaml:"disabled,omitempty"`
+	RecoveryTime time.Duration `json:"recovery-time,omitempty" yaml:"recovery-time,omitempty"`
+}
+
+// FailoverSettings returns the effective failover settings, defaults
+// filled in.
+func (c *L2TPConfig) FailoverSettings() Failover {
+	var f Failover
+	if c != nil && c.Failover != nil {
+		f = *c.Failover
+	}
+	if f.RecoveryTime <= 0 {
+		f.RecoveryTime = DefaultRecoveryTime
+	}
+	return f
 }
 
 // TunnelPool is a named catalog of LNS endpoints used by the LAC as a
@@ -63,19 +94,125 @@ type L2TPConfig struct {
 type TunnelPool struct {
 	LocalName string   `json:"local-name,omitempty" yaml:"local-name,omitempty"`
 	LNS       []LNSRef `json:"lns,omitempty"        yaml:"lns,omitempty"`
+
+	// MaxSessionsPerTunnel caps the sessions the LAC carries in one
+	// tunnel to an LNS of this pool; further sessions open another
+	// tunnel to the same LNS. 0 puts every session in one tunnel.
+	MaxSessionsPerTunnel int `json:"max-sessions-per-tunnel,omitempty" yaml:"max-sessions-per-tunnel,omitempty"`
+
+	// HealthCheck enables active probing of the pool's LNS entries.
+	HealthCheck *HealthCheck `json:"health-check,omitempty" yaml:"health-check,omitempty"`
+}
+
+// Health-check probe methods. A Hello probe rides an established
+// tunnel to the LNS and falls back to an SCCRQ probe when there is
+// none; an SCCRQ probe always opens a throwaway control connection.
+const (
+	HealthCheckHello = "hello"
+	HealthCheckSCCRQ = "sccrq"
+)
+
+// HealthCheck configures active LNS probing. An LNS is marked down
+// after DownAfter consecutive failed probes and back up after UpAfter
+// consecutive successful ones; the LAC does not select a down LNS.
+type HealthCheck struct {
+	Method    string        `json:"method,omitempty"     yaml:"method,omitempty"`
+	Interval  time.Duration `json:"interval,omitempty"   yaml:"interval,omitempty"`
+	Timeout   time.Duration `json:"timeout,omitempty"    yaml:"timeout,omitempty"`
+	DownAfter int           `json:"down-after,omitempty" yaml:"down-after,omitempty"`
+	UpAfter   int           `json:"up-after,omitempty"   yaml:"up-after,omitempty"`
+}
+
+// WithDefaults returns a copy with unset fields filled in.
+func (h HealthCheck) WithDefaults() HealthCheck {
+	if h.Method == "" {
+		h.Method = HealthCheckHello
+	}
+	if h.Interval <= 0 {
+		h.Interval = 30 * time.Second
+	}
+	if h.Timeout <= 0 {
+		h.Timeout = 5 * time.Second
+	}
+	if h.DownAfter <= 0 {
+		h.DownAfter = 3
+	}
+	if h.UpAfter <= 0 {
+		h.UpAfter = 1
+	}
+	return h
 }
 
 // LNSRef is one LNS endpoint inside a tunnel-pool. Per-server VRF,
-// source-ipv4, and PPP framing override let one pool span multiple
-// upstream LNSes with mixed behavior.
+// source address, and PPP framing override let one pool span multiple
+// upstream LNSes with mixed behavior. An entry carries either an IPv4
+// or an IPv6 endpoint; a pool may mix both families. Weight and
+// MaxSessions steer load-aware selection among entries of equal
+// preference; MaxSessions 0 is unlimited.
 type LNSRef struct {
-	Name       string `json:"name"                  yaml:"name"`
-	IPv4       string `json:"ipv4"                  yaml:"ipv4"`
-	Secret     string `json:"secret,omitempty"      yaml:"secret,omitempty"`
-	Preference uint16 `json:"preference,omitempty"  yaml:"preference,omitempty"`
-	VRF        string `json:"vrf,omitempty"         yaml:"vrf,omitempty"`
-	SourceIPv4 string `json:"source-ipv4,omitempty" yaml:"source-ipv4,omitempty"`
-	PPPFraming `yaml:",inline"`
+	Name        string `json:"name"                   yaml:"name"`
+	IPv4        string `json:"ipv4,omitempty"         yaml:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"         yaml:"ipv6,omitempty"`
+	Secret      string `json:"secret,omitempty"       yaml:"secret,omitempty"`
+	Preference  uint16 `json:"preference,omitempty"   yaml:"preference,omitempty"`
+	Weight      uint16 `json:"weight,omitempty"       yaml:"weight,omitempty"`
+	MaxSessions int    `json:"max-sessions,omitempty" yaml:"max-sessions,omitempty"`
+	VRF         string `json:"vrf,omitempty"          yaml:"vrf,omitempty"`
+	SourceIPv4  string `json:"source-ipv4,omitempty"  yaml:"source-ipv4,omitempty"`
+	SourceIPv6  string `json:"source-ipv6,omitempty"  yaml:"source-ipv6,omitempty"`
+	PPPFraming  `yaml:",inline"`
+}
+
+// Address returns the LNS endpoint address, preferring IPv4 when both
+// families are set. Returns nil if neither parses.
+func (r *LNSRef) Address() net.IP {
+	if ip := net.ParseIP(r.IPv4); ip != nil && ip.To4() != nil {
+		return ip
+	}
+	if ip := net.ParseIP(r.IPv6); ip != nil && ip.To4() == nil {
+		return ip
+	}
+	return nil
+}
+
+// EffectiveWeight returns the configured weight, 1 when unset.
+func (r *LNSRef) EffectiveWeight() uint16 {
+	if r.Weight == 0 {
+		return 1
+	}
+	return r.Weight
+}
+
+// SourceAddress returns the configured tunnel source address of the
+// same family as `peer`, or nil if none is configured for that family.
+func (r *LNSRef) SourceAddress(peer net.IP) net.IP {
+	if peer == nil {
+		return nil
+	}
+	if peer.To4() != nil {
+		if ip := net.ParseIP(r.SourceIPv4); ip != nil && ip.To4() != nil {
+			return ip
+		}
+		return nil
+	}
+	if ip := net.ParseIP(r.SourceIPv6); ip != nil && ip.To4() == nil {
+		return ip
+	}
+	return nil
+}
+
+// Matches reports whether `ip` is one of this entry's endpoint
+// addresses.
+func (r *LNSRef) Matches(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, s := range []string{r.IPv4, r.IPv6} {
+		if a := net.ParseIP(s); a != nil && a.Equal(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 // Profile bundles timers, limits and policy. Referenced from a
@@ -121,6 +258,64 @@ type PeerPolicy struct {
 	PPPFraming `yaml:",inline"`
 }
 
+// Validate checks that every tunnel-pool LNS entry carries a usable
+// endpoint and that per-family addresses are of the family their field
+// name promises.
+func (c *L2TPConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if f := c.Failover; f != nil && (f.RecoveryTime < 0 || f.RecoveryTime > time.Duration(math.MaxUint32)*time.Millisecond) {
+		return fmt.Errorf("l2tp: failover.recovery-time: out of range")
+	}
+	for poolName, pool := range c.TunnelPools {
+		if pool == nil {
+			continue
+		}
+		if pool.MaxSessionsPerTunnel < 0 {
+			return fmt.Errorf("l2tp: tunnel-pools.%s.max-sessions-per-tunnel: must not be negative", poolName)
+		}
+		if hc := pool.HealthCheck; hc != nil {
+			switch hc.Method {
+			case "", HealthCheckHello, HealthCheckSCCRQ:
+			default:
+				return fmt.Errorf("l2tp: tunnel-pools.%s.health-check.method: unknown method %q", poolName, hc.Method)
+			}
+			if hc.Interval < 0 || hc.Timeout < 0 || hc.DownAfter < 0 || hc.UpAfter < 0 {
+				return fmt.Errorf("l2tp: tunnel-pools.%s.health-check: values must not be negative", poolName)
+			}
+		}
+		for i := range pool.LNS {
+			ref := &pool.LNS[i]
+			if ref.MaxSessions < 0 {
+				return fmt.Errorf("l2tp: tunnel-pools.%s.lns[%d].max-sessions: must not be negative", poolName, i)
+			}
+			if ref.IPv4 == "" && ref.IPv6 == "" {
+				return fmt.Errorf("l2tp: tunnel-pools.%s.lns[%d]: one of ipv4 or ipv6 is required", poolName, i)
+			}
+			for _, f := range []struct {
+				field string
+				value string
+				v4    bool
+			}{
+				{"ipv4", ref.IPv4, true},
+				{"source-ipv4", ref.SourceIPv4, true},
+				{"ipv6", ref.IPv6, false},
+				{"source-ipv6", ref.SourceIPv6, false},
+			} {
+				if f.value == "" {
+					continue
+				}
+				ip := net.ParseIP(f.value)
+				if ip == nil || (ip.To4() != nil) != f.v4 {
+					return fmt.Errorf("l2tp: tunnel-pools.%s.lns[%d].%s: invalid address %q", poolName, i, f.field, f.value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (c *L2TPConfig) GetProfile(name string) *Profile {
 	if c == nil {
 		return nil
@@ -135,6 +330,26 @@ func (c *L2TPConfig) GetTunnelPool(name string) *TunnelPool {
 	return c.TunnelPools[name]
 }
 
+// LookupLNS returns the tunnel-pool LNS entry whose endpoint is `ip`,
+// and the name of its pool. The first match wins when several pools
+// list the same LNS.
+func (c *L2TPConfig) LookupLNS(ip net.IP) (string, *TunnelPool, *LNSRef) {
+	if c == nil || ip == nil {
+		return "", nil, nil
+	}
+	for name, pool := range c.TunnelPools {
+		if pool == nil {
+			continue
+		}
+		for i := range pool.LNS {
+			if pool.LNS[i].Matches(ip) {
+				return name, pool, &pool.LNS[i]
+			}
+		}
+	}
+	return "", nil, nil
+}
+
 // GetPeerPolicyByHostname returns the peer policy whose Hostname
 // matches the LAC Host Name AVP value, or nil if none match.
 func (c *L2TPConfig) GetPeerPolicyByHostname(hostname string) *PeerPolicy {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"slices"
 
 	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
 	"github.com/veesix-networks/osvbng/pkg/config/system"
@@ -113,7 +114,7 @@ func (c *Config) validatePseudowires() error {
 
 		if c.L2GW != nil && c.L2GW.HandoffGroups != nil {
 			for hgName, hg := range c.L2GW.HandoffGroups {
-				if hg != nil && hg.Interface == transport {
+				if hg != nil && slices.Contains(hg.MemberInterfaces(), transport) {
 					return fmt.Errorf("interfaces.%s.pseudowire: transport %q is an l2gw handoff interface (handoff-group %s)", ifName, transport, hgName)
 				}
 			}
@@ -151,6 +152,50 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.validateServiceGroupCGNAT(); err != nil {
+		return err
+	}
+
+	if err := c.validateCGNATBypassEscalation(); err != nil {
+		return err
+	}
+
+	if err := c.validateServiceGroupNPTv6(); err != nil {
+		return err
+	}
+
+	if err := c.validateAccessLists(); err != nil {
+		return err
+	}
+
+	if err := c.validateSchedules(); err != nil {
+		return err
+	}
+
+	if err := c.validateSteeringPolicies(); err != nil {
+		return err
+	}
+
+	if err := c.validateQoSPolicies(); err != nil {
+		return err
+	}
+
+	if err := c.validateMarking(); err != nil {
+		return err
+	}
+
+	if err := c.validateRTBH(); err != nil {
+		return err
+	}
+
+	if err := c.validateFlowSpec(); err != nil {
+		return err
+	}
+
+	if err := c.validateLocalSwitching(); err != nil {
+		return err
+	}
+
 	if c.NeedsAccessInterface() {
 		if _, err := c.GetAccessInterface(); err != nil {
 			return fmt.Errorf("access interface validation: %w", err)
@@ -247,6 +292,14 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.validatePoolThresholds(); err != nil {
+		return err
+	}
+
+	if err := c.validateOnDemandPools(); err != nil {
+		return err
+	}
+
 	if err := c.validateOSPFVRFInterfaces(); err != nil {
 		return err
 	}
@@ -255,6 +308,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.L2TP.Validate(); err != nil {
+		return err
+	}
+
 	if c.HA.Enabled && c.SubscriberGroups != nil {
 		for srgName, srg := range c.HA.SRGs {
 			for _, sg := range srg.SubscriberGroups {
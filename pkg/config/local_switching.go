@@ -0,0 +1,175 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+)
+
+// LocalSwitchingPrefixes returns the networks of the pools the group's
+// IPv4 and IPv6 profiles address subscribers from, sorted and without
+// duplicates: the destinations local switching isolates subscribers
+// of the group from. Pools added on demand are not included.
+func (c *Config) LocalSwitchingPrefixes(group *subscriber.SubscriberGroup) []netip.Prefix {
+	if group == nil {
+		return nil
+	}
+	var networks []string
+	if p := c.IPv4Profiles[group.IPv4Profile]; p != nil {
+		for _, pool := range p.Pools {
+			networks = append(networks, pool.Network)
+		}
+	}
+	if p := c.IPv6Profiles[group.IPv6Profile]; p != nil {
+		for _, pool := range p.IANAPools {
+			networks = append(networks, pool.Network)
+		}
+		for _, pool := range p.PDPools {
+			networks = append(networks, pool.Network)
+		}
+	}
+	return uniquePrefixes(networks)
+}
+
+// LocalSwitchingServiceGroupPrefixes returns the networks of the pools
+// the named service group addresses its subscribers from, sorted and
+// without duplicates: the destinations service-group local switching
+// lets the group's members reach. A pool the service group names is
+// looked up in its own profile, or else in the subscriber group's; a
+// service group with a profile of its own but no pool of a kind
+// contributes every pool of that kind in the profile. A service group
+// naming neither has no prefixes, and its members are isolated.
+func (c *Config) LocalSwitchingServiceGroupPrefixes(group *subscriber.SubscriberGroup, serviceGroup string) []netip.Prefix {
+	sg := c.ServiceGroups[serviceGroup]
+	if group == nil || sg == nil {
+		return nil
+	}
+	v4Profile, v6Profile := group.IPv4Profile, group.IPv6Profile
+	if sg.IPv4Profile != "" {
+		v4Profile = sg.IPv4Profile
+	}
+	if sg.IPv6Profile != "" {
+		v6Profile = sg.IPv6Profile
+	}
+	named := func(pool, want string, whole bool) bool {
+		if want != "" {
+			return pool == want
+		}
+		return whole
+	}
+
+	var networks []string
+	if p := c.IPv4Profiles[v4Profile]; p != nil {
+		for _, pool := range p.Pools {
+			if named(pool.Name, sg.Pool, sg.IPv4Profile != "") {
+				networks = append(networks, pool.Network)
+			}
+		}
+	}
+	if p := c.IPv6Profiles[v6Profile]; p != nil {
+		for _, pool := range p.IANAPools {
+			if named(pool.Name, sg.IANAPool, sg.IPv6Profile != "") {
+				networks = append(networks, pool.Network)
+			}
+		}
+		for _, pool := range p.PDPools {
+			if named(pool.Name, sg.PDPool, sg.IPv6Profile != "") {
+				networks = append(networks, pool.Network)
+			}
+		}
+	}
+	return uniquePrefixes(networks)
+}
+
+// uniquePrefixes parses networks into masked prefixes, sorted and
+// without duplicates. Networks that do not parse are skipped.
+func uniquePrefixes(networks []string) []netip.Prefix {
+	seen := make(map[netip.Prefix]bool, len(networks))
+	var out []netip.Prefix
+	for _, n := range networks {
+		p, err := netip.ParsePrefix(n)
+		if err != nil {
+			continue
+		}
+		p = p.Masked()
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if c := out[i].Addr().Compare(out[j].Addr()); c != 0 {
+			return c < 0
+		}
+		return out[i].Bits() < out[j].Bits()
+	})
+	return out
+}
+
+// LocalSwitchingGateways returns the gateway addresses of the group's
+// IPv4 and IPv6 profiles and their pools, sorted and without duplicates.
+// They sit inside the pools, so local switching has to keep them
+// reachable while it isolates subscribers.
+func (c *Config) LocalSwitchingGateways(group *subscriber.SubscriberGroup) []netip.Addr {
+	if group == nil {
+		return nil
+	}
+	var gateways []string
+	if p := c.IPv4Profiles[group.IPv4Profile]; p != nil {
+		gateways = append(gateways, p.Gateway)
+		for _, pool := range p.Pools {
+			gateways = append(gateways, pool.Gateway)
+		}
+	}
+	if p := c.IPv6Profiles[group.IPv6Profile]; p != nil {
+		for _, pool := range p.IANAPools {
+			gateways = append(gateways, pool.Gateway)
+		}
+	}
+
+	seen := make(map[netip.Addr]bool, len(gateways))
+	var out []netip.Addr
+	for _, g := range gateways {
+		a, err := netip.ParseAddr(g)
+		if err != nil || seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Less(out[j]) })
+	return out
+}
+
+// validateLocalSwitching checks each subscriber group's local-switching
+// mode: a known mode, on a group with IPoE subscribers, and with pools
+// to isolate them from where the mode drops traffic.
+func (c *Config) validateLocalSwitching() error {
+	if c.SubscriberGroups == nil {
+		return nil
+	}
+	for name, group := range c.SubscriberGroups.Groups {
+		if group == nil || group.LocalSwitching == "" {
+			continue
+		}
+		path := "subscriber-groups." + name + ".local-switching"
+		switch group.LocalSwitching {
+		case subscriber.LocalSwitchingIsolate, subscriber.LocalSwitchingHairpin, subscriber.LocalSwitchingServiceGroup:
+		default:
+			return fmt.Errorf("%s: %q is not isolate, hairpin or service-group", path, group.LocalSwitching)
+		}
+		if !group.HasAccessType(subscriber.AccessTypeIPoE) {
+			return fmt.Errorf("%s: the group has no ipoe access type", path)
+		}
+		if group.LocalSwitching != subscriber.LocalSwitchingHairpin && len(c.LocalSwitchingPrefixes(group)) == 0 {
+			return fmt.Errorf("%s: %s needs pools in the group's ipv4-profile or ipv6-profile to isolate subscribers from", path, group.LocalSwitching)
+		}
+	}
+	return nil
+}
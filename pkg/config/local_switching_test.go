@@ -0,0 +1,120 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
+)
+
+func localSwitchingConfig(mode subscriber.LocalSwitchingMode, access subscriber.AccessType, v4Profile string) *Config {
+	return &Config{
+		SubscriberGroups: &subscriber.SubscriberGroupsConfig{Groups: map[string]*subscriber.SubscriberGroup{
+			"business": {
+				AccessTypes:    []subscriber.AccessType{access},
+				IPv4Profile:    v4Profile,
+				IPv6Profile:    "v6",
+				LocalSwitching: mode,
+			},
+		}},
+		IPv4Profiles: map[string]*ip.IPv4Profile{
+			"v4": {Gateway: "100.64.0.1", Pools: []ip.IPv4Pool{
+				{Network: "100.64.0.0/16"},
+				{Network: "10.0.0.0/24", Gateway: "10.0.0.1"},
+				{Network: "100.64.0.0/16", Gateway: "100.64.0.1"},
+			}},
+		},
+		IPv6Profiles: map[string]*ip.IPv6Profile{
+			"v6": {
+				IANAPools: []ip.IANAPool{{Network: "2001:db8:1::/64", Gateway: "2001:db8:1::1"}},
+				PDPools:   []ip.PDPool{{Network: "2001:db8:100::/40", PrefixLength: 56}},
+			},
+		},
+	}
+}
+
+func TestLocalSwitchingPrefixes(t *testing.T) {
+	cfg := localSwitchingConfig(subscriber.LocalSwitchingIsolate, subscriber.AccessTypeIPoE, "v4")
+	got := fmt.Sprint(cfg.LocalSwitchingPrefixes(cfg.SubscriberGroups.Groups["business"]))
+	want := "[10.0.0.0/24 100.64.0.0/16 2001:db8:1::/64 2001:db8:100::/40]"
+	if got != want {
+		t.Errorf("prefixes = %s, want %s", got, want)
+	}
+
+	got = fmt.Sprint(cfg.LocalSwitchingGateways(cfg.SubscriberGroups.Groups["business"]))
+	want = "[10.0.0.1 100.64.0.1 2001:db8:1::1]"
+	if got != want {
+		t.Errorf("gateways = %s, want %s", got, want)
+	}
+}
+
+func TestLocalSwitchingServiceGroupPrefixes(t *testing.T) {
+	cfg := localSwitchingConfig(subscriber.LocalSwitchingServiceGroup, subscriber.AccessTypeIPoE, "v4")
+	cfg.IPv4Profiles["v4"].Pools[1].Name = "gold"
+	cfg.IPv4Profiles["gold"] = &ip.IPv4Profile{Pools: []ip.IPv4Pool{
+		{Name: "a", Network: "100.65.0.0/24"},
+		{Name: "b", Network: "100.65.1.0/24"},
+	}}
+	cfg.IPv6Profiles["v6"].PDPools[0].Name = "gold-pd"
+	cfg.ServiceGroups = map[string]*servicegroup.Config{
+		"gold":    {Pool: "gold", PDPool: "gold-pd"},
+		"silver":  {IPv4Profile: "gold"},
+		"bronze":  {IPv4Profile: "gold", Pool: "b"},
+		"default": {},
+	}
+	group := cfg.SubscriberGroups.Groups["business"]
+
+	cases := map[string]string{
+		"gold":    "[10.0.0.0/24 2001:db8:100::/40]",
+		"silver":  "[100.65.0.0/24 100.65.1.0/24]",
+		"bronze":  "[100.65.1.0/24]",
+		"default": "[]",
+		"missing": "[]",
+	}
+	for sg, want := range cases {
+		if got := fmt.Sprint(cfg.LocalSwitchingServiceGroupPrefixes(group, sg)); got != want {
+			t.Errorf("%s: prefixes = %s, want %s", sg, got, want)
+		}
+	}
+}
+
+func TestValidateLocalSwitching(t *testing.T) {
+	cases := []struct {
+		name    string
+		mode    subscriber.LocalSwitchingMode
+		access  subscriber.AccessType
+		profile string
+		noV6    bool
+		want    string
+	}{
+		{name: "isolate", mode: subscriber.LocalSwitchingIsolate, access: subscriber.AccessTypeIPoE, profile: "v4"},
+		{name: "service group", mode: subscriber.LocalSwitchingServiceGroup, access: subscriber.AccessTypeIPoE, profile: "v4"},
+		{name: "hairpin without pools", mode: subscriber.LocalSwitchingHairpin, access: subscriber.AccessTypeIPoE, noV6: true},
+		{name: "unknown mode", mode: "bridge", access: subscriber.AccessTypeIPoE, profile: "v4", want: "is not isolate, hairpin or service-group"},
+		{name: "pppoe", mode: subscriber.LocalSwitchingIsolate, access: subscriber.AccessTypePPPoE, profile: "v4", want: "no ipoe access type"},
+		{name: "isolate without pools", mode: subscriber.LocalSwitchingIsolate, access: subscriber.AccessTypeIPoE, noV6: true, want: "needs pools"},
+	}
+	for _, tc := range cases {
+		cfg := localSwitchingConfig(tc.mode, tc.access, tc.profile)
+		if tc.noV6 {
+			cfg.IPv6Profiles = nil
+		}
+		err := cfg.validateLocalSwitching()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -11,16 +11,18 @@ const (
 )
 
 type BGPConfig struct {
-	ASN         uint32                   `json:"asn" yaml:"asn"`
-	RouterID    string                   `json:"router-id,omitempty" yaml:"router-id,omitempty"`
-	PeerGroups  map[string]*BGPPeerGroup `json:"peer-groups,omitempty" yaml:"peer-groups,omitempty"`
-	Neighbors   map[string]*BGPNeighbor  `json:"neighbors,omitempty" yaml:"neighbors,omitempty"`
-	IPv4Unicast *BGPAddressFamily        `json:"ipv4-unicast,omitempty" yaml:"ipv4-unicast,omitempty"`
-	IPv6Unicast *BGPAddressFamily        `json:"ipv6-unicast,omitempty" yaml:"ipv6-unicast,omitempty"`
-	IPv4VPN     *BGPVPNAddressFamily     `json:"ipv4-vpn,omitempty" yaml:"ipv4-vpn,omitempty"`
-	IPv6VPN     *BGPVPNAddressFamily     `json:"ipv6-vpn,omitempty" yaml:"ipv6-vpn,omitempty"`
-	L2VPNEVPN   *BGPEVPNAddressFamily    `json:"l2vpn-evpn,omitempty" yaml:"l2vpn-evpn,omitempty"`
-	VRF         map[string]*BGPVRFConfig `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+	ASN          uint32                    `json:"asn" yaml:"asn"`
+	RouterID     string                    `json:"router-id,omitempty" yaml:"router-id,omitempty"`
+	PeerGroups   map[string]*BGPPeerGroup  `json:"peer-groups,omitempty" yaml:"peer-groups,omitempty"`
+	Neighbors    map[string]*BGPNeighbor   `json:"neighbors,omitempty" yaml:"neighbors,omitempty"`
+	IPv4Unicast  *BGPAddressFamily         `json:"ipv4-unicast,omitempty" yaml:"ipv4-unicast,omitempty"`
+	IPv6Unicast  *BGPAddressFamily         `json:"ipv6-unicast,omitempty" yaml:"ipv6-unicast,omitempty"`
+	IPv4VPN      *BGPVPNAddressFamily      `json:"ipv4-vpn,omitempty" yaml:"ipv4-vpn,omitempty"`
+	IPv6VPN      *BGPVPNAddressFamily      `json:"ipv6-vpn,omitempty" yaml:"ipv6-vpn,omitempty"`
+	L2VPNEVPN    *BGPEVPNAddressFamily     `json:"l2vpn-evpn,omitempty" yaml:"l2vpn-evpn,omitempty"`
+	IPv4FlowSpec *BGPFlowSpecAddressFamily `json:"ipv4-flowspec,omitempty" yaml:"ipv4-flowspec,omitempty"`
+	IPv6FlowSpec *BGPFlowSpecAddressFamily `json:"ipv6-flowspec,omitempty" yaml:"ipv6-flowspec,omitempty"`
+	VRF          map[string]*BGPVRFConfig  `json:"vrf,omitempty" yaml:"vrf,omitempty"`
 }
 
 type BGPPeerGroup struct {
@@ -63,6 +65,31 @@ type BGPEVPNAddressFamily struct {
 	AdvertiseAllVNI bool                             `json:"advertise-all-vni,omitempty" yaml:"advertise-all-vni,omitempty"`
 }
 
+// BGPFlowSpecAddressFamily receives FlowSpec rules (RFC 8955) from its
+// neighbors. osvbng programs the rules FRR installs on Interfaces, the
+// core-facing interfaces the traffic they match arrives on.
+type BGPFlowSpecAddressFamily struct {
+	Neighbors  map[string]*BGPNeighborAFIConfig `json:"neighbors,omitempty" yaml:"neighbors,omitempty"`
+	Interfaces []string                         `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	MaxRules   int                              `json:"max-rules,omitempty" yaml:"max-rules,omitempty"`
+}
+
+const (
+	// DefaultFlowSpecMaxRules is how many rules of a family are
+	// programmed when max-rules is not set.
+	DefaultFlowSpecMaxRules = 256
+	// MaxFlowSpecRules bounds max-rules: every rule costs ACL entries,
+	// classify sessions or ABF policies on each interface.
+	MaxFlowSpecRules = 4096
+)
+
+func (a *BGPFlowSpecAddressFamily) GetMaxRules() int {
+	if a.MaxRules == 0 {
+		return DefaultFlowSpecMaxRules
+	}
+	return a.MaxRules
+}
+
 type BGPVRFAFConfig struct {
 	Networks     map[string]*BGPNetwork `json:"networks,omitempty" yaml:"networks,omitempty"`
 	Redistribute *BGPRedistribute       `json:"redistribute,omitempty" yaml:"redistribute,omitempty"`
@@ -0,0 +1,328 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
+)
+
+// Directions a policy is attached in, as a service group names them.
+const (
+	DirectionIngress = "ingress"
+	DirectionEgress  = "egress"
+)
+
+// MaxClasses bounds the classes of one policy. Every ingress class is a
+// policer and at least one classify table per subscriber, so the bound
+// is on dataplane memory as much as on configuration sanity.
+const MaxClasses = 8
+
+// Class is one traffic class of a policy. Classes are matched in order
+// and the first match wins. The policy's own rate still bounds the
+// subscriber as a whole; a class limits or marks its share within it.
+//
+// In the ingress direction a class is a classifier feeding its own
+// policer, so it can match on anything in Match and police or remark
+// what it matches. It has no priority: a policer does not queue. In the
+// egress direction the subscriber's CAKE scheduler already sorts traffic
+// into tins by DSCP, so a class can only match DSCP and names the tin
+// those code points land in. It has no rate, priority or marking of its
+// own: the scheduler exposes none per tin, so the tin mode decides them
+// and the class names and counts the tin.
+type Class struct {
+	Name   string     `json:"name"             yaml:"name"`
+	Match  ClassMatch `json:"match"            yaml:"match"`
+	Police *ClassRate `json:"police,omitempty" yaml:"police,omitempty"`
+	Mark   *ClassMark `json:"mark,omitempty"   yaml:"mark,omitempty"`
+}
+
+// ClassMatch fields are ANDed; the values within a list are ORed.
+type ClassMatch struct {
+	DSCP       []string `json:"dscp,omitempty"        yaml:"dscp,omitempty"`
+	PCP        []uint8  `json:"pcp,omitempty"         yaml:"pcp,omitempty"`
+	Protocol   string   `json:"protocol,omitempty"    yaml:"protocol,omitempty"`
+	AccessList string   `json:"access-list,omitempty" yaml:"access-list,omitempty"`
+}
+
+// ClassRate is a class's own two-rate three-colour policer.
+type ClassRate struct {
+	CIR     uint32       `json:"cir"               yaml:"cir"`
+	EIR     uint32       `json:"eir,omitempty"     yaml:"eir,omitempty"`
+	CBS     uint64       `json:"cbs,omitempty"     yaml:"cbs,omitempty"`
+	EBS     uint64       `json:"ebs,omitempty"     yaml:"ebs,omitempty"`
+	Exceed  ActionConfig `json:"exceed"            yaml:"exceed"`
+	Violate ActionConfig `json:"violate"           yaml:"violate"`
+}
+
+// ClassMark rewrites the DSCP of the class's conforming traffic.
+type ClassMark struct {
+	DSCP string `json:"dscp" yaml:"dscp"`
+}
+
+// markOnlyCIR is the rate of the policer a class that only marks is
+// given: high enough that everything conforms.
+const markOnlyCIR = 100_000_000
+
+var dscpNames = map[string]uint8{
+	"be": 0, "default": 0, "le": 1,
+	"cs1": 8, "cs2": 16, "cs3": 24, "cs4": 32, "cs5": 40, "cs6": 48, "cs7": 56,
+	"af11": 10, "af12": 12, "af13": 14,
+	"af21": 18, "af22": 20, "af23": 22,
+	"af31": 26, "af32": 28, "af33": 30,
+	"af41": 34, "af42": 36, "af43": 38,
+	"va": 44, "ef": 46,
+}
+
+// ParseDSCP accepts a PHB name (ef, af41, cs1, ...) or a code point.
+func ParseDSCP(s string) (uint8, error) {
+	if v, ok := dscpNames[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || v > 63 {
+		return 0, fmt.Errorf("%q is not a DSCP name or 0-63", s)
+	}
+	return uint8(v), nil
+}
+
+// DSCPValues parses the class's DSCP list.
+func (m *ClassMatch) DSCPValues() ([]uint8, error) {
+	out := make([]uint8, 0, len(m.DSCP))
+	for _, s := range m.DSCP {
+		v, err := ParseDSCP(s)
+		if err != nil {
+			return nil, fmt.Errorf("dscp: %w", err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// ProtocolNumber returns the IP protocol the class matches, 0 for any.
+func (m *ClassMatch) ProtocolNumber() (uint8, error) {
+	if m.Protocol == "" {
+		return 0, nil
+	}
+	probe := acl.Rule{Action: acl.ActionPermit, Protocol: m.Protocol}
+	entries, _, err := (&acl.AccessList{Rules: []acl.Rule{probe}}).Expand()
+	if err != nil {
+		return 0, err
+	}
+	return entries[0].Protocol, nil
+}
+
+// ValidateClasses checks the policy's classes for the direction it is attached
+// in. accessLists resolves match.access-list references.
+func (p *Policy) ValidateClasses(direction string, accessLists map[string]*acl.AccessList) error {
+	if len(p.Classes) == 0 {
+		return nil
+	}
+	if len(p.Classes) > MaxClasses {
+		return fmt.Errorf("at most %d classes are supported, got %d", MaxClasses, len(p.Classes))
+	}
+	if direction == DirectionEgress && p.Scheduler == nil {
+		return fmt.Errorf("egress classes need a scheduler: they are the scheduler's tins")
+	}
+
+	seen := map[string]bool{}
+	tins := map[uint8]string{}
+	for i := range p.Classes {
+		c := &p.Classes[i]
+		if c.Name == "" {
+			return fmt.Errorf("classes[%d]: name is required", i)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("class %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+
+		if err := c.validate(direction, accessLists); err != nil {
+			return fmt.Errorf("class %q: %w", c.Name, err)
+		}
+
+		if direction == DirectionEgress {
+			tin, err := c.Tin(p.Scheduler.TinMode)
+			if err != nil {
+				return fmt.Errorf("class %q: %w", c.Name, err)
+			}
+			if other, ok := tins[tin]; ok {
+				return fmt.Errorf("class %q: shares tin %d with class %q", c.Name, tin, other)
+			}
+			tins[tin] = c.Name
+		}
+	}
+	return nil
+}
+
+func (c *Class) validate(direction string, accessLists map[string]*acl.AccessList) error {
+	m := &c.Match
+	if len(m.DSCP) == 0 && len(m.PCP) == 0 && m.Protocol == "" && m.AccessList == "" {
+		return fmt.Errorf("match is empty")
+	}
+	if _, err := m.DSCPValues(); err != nil {
+		return err
+	}
+	for _, pcp := range m.PCP {
+		if pcp > 7 {
+			return fmt.Errorf("pcp: %d is not 0-7", pcp)
+		}
+	}
+	if _, err := m.ProtocolNumber(); err != nil {
+		return err
+	}
+	if c.Mark != nil {
+		if _, err := ParseDSCP(c.Mark.DSCP); err != nil {
+			return fmt.Errorf("mark.dscp: %w", err)
+		}
+	}
+
+	if direction == DirectionEgress {
+		if len(m.PCP) > 0 || m.Protocol != "" || m.AccessList != "" {
+			return fmt.Errorf("egress classes match on dscp only")
+		}
+		if len(m.DSCP) == 0 {
+			return fmt.Errorf("egress classes need match.dscp")
+		}
+		if c.Police != nil || c.Mark != nil {
+			return fmt.Errorf("egress classes have no rate or marking of their own, only their tin's; police and mark are ingress only")
+		}
+		return nil
+	}
+
+	if c.Police == nil && c.Mark == nil {
+		return fmt.Errorf("an ingress class needs police or mark")
+	}
+	if c.Police != nil && c.Police.CIR == 0 {
+		return fmt.Errorf("police.cir is required")
+	}
+	if m.AccessList != "" {
+		list := accessLists[m.AccessList]
+		if list == nil {
+			return fmt.Errorf("match.access-list: access-list %q is not defined", m.AccessList)
+		}
+		entries, _, err := list.Expand()
+		if err != nil {
+			return fmt.Errorf("match.access-list %q: %w", m.AccessList, err)
+		}
+		for i, e := range entries {
+			if err := ClassifiableEntry(e); err != nil {
+				return fmt.Errorf("match.access-list %q: entry %d: %w", m.AccessList, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ClassifiableEntry reports whether an access-list entry can be matched
+// by an exact-match classifier: a permit with prefixes, single ports or
+// any port, and no TCP flags.
+func ClassifiableEntry(e acl.Entry) error {
+	if e.Action == acl.ActionDeny {
+		return fmt.Errorf("deny rules cannot select a class")
+	}
+	if e.TCPFlagsMask != 0 {
+		return fmt.Errorf("tcp-flags cannot be classified")
+	}
+	for _, r := range [][2]uint16{{e.SrcFirst, e.SrcLast}, {e.DstFirst, e.DstLast}} {
+		if r[0] != r[1] && !AnyPort(e, r[0], r[1]) {
+			return fmt.Errorf("port range %d-%d cannot be classified; use single ports", r[0], r[1])
+		}
+	}
+	return nil
+}
+
+// AnyPort reports whether first-last is the whole range for the entry's
+// protocol: every port, or for ICMP every type or code.
+func AnyPort(e acl.Entry, first, last uint16) bool {
+	if first != 0 {
+		return false
+	}
+	if e.Protocol == 1 || e.Protocol == 58 {
+		return last == 255
+	}
+	return last == 65535
+}
+
+// Tin returns the scheduler tin an egress class's DSCP values land in
+// under tinMode. Every value must land in the same tin.
+func (c *Class) Tin(tinMode string) (uint8, error) {
+	values, err := c.Match.DSCPValues()
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("egress classes need match.dscp")
+	}
+	tin := TinForDSCP(tinMode, values[0])
+	for _, v := range values[1:] {
+		if t := TinForDSCP(tinMode, v); t != tin {
+			return 0, fmt.Errorf("dscp %d is in tin %d, not tin %d with the rest of the class", v, t, tin)
+		}
+	}
+	return tin, nil
+}
+
+// TinForDSCP maps a code point to a tin the way the scheduler's tin
+// modes do, with tins numbered lowest priority first as the scheduler
+// reports them: diffserv3 is Bulk, Best Effort, Voice; diffserv4 adds
+// Video below Voice; diffserv8 is one tin per precedence.
+func TinForDSCP(tinMode string, dscp uint8) uint8 {
+	bulk := dscp == 8 || dscp == 1
+	voice := dscp == 56 || dscp == 48 || dscp == 46 || dscp == 44 || dscp == 40 || dscp == 32
+	video := dscp == 16 || dscp == 24 || (dscp >= 18 && dscp <= 38 && dscp%2 == 0 && dscp%8 != 0)
+
+	switch tinMode {
+	case "diffserv3":
+		switch {
+		case bulk:
+			return 0
+		case voice:
+			return 2
+		}
+		return 1
+	case "diffserv4":
+		switch {
+		case bulk:
+			return 0
+		case voice:
+			return 3
+		case video:
+			return 2
+		}
+		return 1
+	case "diffserv8":
+		return dscp >> 3
+	}
+	return 0
+}
+
+// ToPolicerConfig returns the class policer, marking conforming traffic
+// when the class marks. A class that only marks gets a policer nothing
+// can exceed.
+func (c *Class) ToPolicerConfig() (Policy, error) {
+	p := Policy{
+		CIR:     markOnlyCIR,
+		Conform: ActionConfig{Action: ActionTransmit},
+		Exceed:  ActionConfig{Action: ActionTransmit},
+		Violate: ActionConfig{Action: ActionTransmit},
+	}
+	if c.Police != nil {
+		p.CIR, p.EIR, p.CBS, p.EBS = c.Police.CIR, c.Police.EIR, c.Police.CBS, c.Police.EBS
+		p.Exceed, p.Violate = c.Police.Exceed, c.Police.Violate
+	}
+	if c.Mark != nil {
+		dscp, err := ParseDSCP(c.Mark.DSCP)
+		if err != nil {
+			return Policy{}, err
+		}
+		p.Conform = ActionConfig{Action: ActionMarkAndTransmit, DSCP: dscp}
+	}
+	p.Defaults()
+	return p, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
+)
+
+func TestParseDSCP(t *testing.T) {
+	for in, want := range map[string]uint8{"ef": 46, "AF41": 34, "cs1": 8, "be": 0, "va": 44, "26": 26} {
+		got, err := ParseDSCP(in)
+		if err != nil || got != want {
+			t.Errorf("ParseDSCP(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"64", "af44", ""} {
+		if _, err := ParseDSCP(in); err == nil {
+			t.Errorf("ParseDSCP(%q) accepted", in)
+		}
+	}
+}
+
+func TestTinForDSCP(t *testing.T) {
+	cases := []struct {
+		mode string
+		dscp uint8
+		want uint8
+	}{
+		{"diffserv4", 46, 3},
+		{"diffserv4", 34, 2},
+		{"diffserv4", 24, 2},
+		{"diffserv4", 0, 1},
+		{"diffserv4", 8, 0},
+		{"diffserv3", 46, 2},
+		{"diffserv3", 34, 1},
+		{"diffserv8", 46, 5},
+		{"besteffort", 46, 0},
+	}
+	for _, tc := range cases {
+		if got := TinForDSCP(tc.mode, tc.dscp); got != tc.want {
+			t.Errorf("TinForDSCP(%s, %d) = %d, want %d", tc.mode, tc.dscp, got, tc.want)
+		}
+	}
+}
+
+func TestValidateClasses(t *testing.T) {
+	lists := map[string]*acl.AccessList{
+		"sip":   {Rules: []acl.Rule{{Action: acl.ActionPermit, Protocol: "udp", DestinationPort: "5060"}}},
+		"range": {Rules: []acl.Rule{{Action: acl.ActionPermit, Protocol: "udp", DestinationPort: "5000-6000"}}},
+		"deny":  {Rules: []acl.Rule{{Action: acl.ActionDeny}}},
+	}
+	voice := Class{Name: "voice", Match: ClassMatch{DSCP: []string{"ef"}}, Police: &ClassRate{CIR: 512}}
+	sched := &SchedulerConfig{TinMode: "diffserv4"}
+
+	cases := []struct {
+		name      string
+		policy    Policy
+		direction string
+		want      string
+	}{
+		{"ingress police", Policy{Classes: []Class{voice}}, DirectionIngress, ""},
+		{"ingress acl", Policy{Classes: []Class{{Name: "sip", Match: ClassMatch{AccessList: "sip", PCP: []uint8{5}},
+			Mark: &ClassMark{DSCP: "cs5"}}}}, DirectionIngress, ""},
+		{"no action", Policy{Classes: []Class{{Name: "x", Match: ClassMatch{DSCP: []string{"ef"}}}}}, DirectionIngress, "police or mark"},
+		{"empty match", Policy{Classes: []Class{{Name: "x", Police: &ClassRate{CIR: 1}}}}, DirectionIngress, "match is empty"},
+		{"duplicate", Policy{Classes: []Class{voice, voice}}, DirectionIngress, "duplicate"},
+		{"bad pcp", Policy{Classes: []Class{{Name: "x", Match: ClassMatch{PCP: []uint8{8}}, Police: &ClassRate{CIR: 1}}}}, DirectionIngress, "pcp"},
+		{"unknown acl", Policy{Classes: []Class{{Name: "x", Match: ClassMatch{AccessList: "nope"}, Police: &ClassRate{CIR: 1}}}}, DirectionIngress, "not defined"},
+		{"port range", Policy{Classes: []Class{{Name: "x", Match: ClassMatch{AccessList: "range"}, Police: &ClassRate{CIR: 1}}}}, DirectionIngress, "port range"},
+		{"deny acl", Policy{Classes: []Class{{Name: "x", Match: ClassMatch{AccessList: "deny"}, Police: &ClassRate{CIR: 1}}}}, DirectionIngress, "deny"},
+		{"egress tins", Policy{Scheduler: sched, Classes: []Class{
+			{Name: "voice", Match: ClassMatch{DSCP: []string{"ef", "cs5"}}},
+			{Name: "video", Match: ClassMatch{DSCP: []string{"af41"}}},
+		}}, DirectionEgress, ""},
+		{"egress without scheduler", Policy{Classes: []Class{{Name: "v", Match: ClassMatch{DSCP: []string{"ef"}}}}}, DirectionEgress, "scheduler"},
+		{"egress police", Policy{Scheduler: sched, Classes: []Class{voice}}, DirectionEgress, "ingress only"},
+		{"egress pcp", Policy{Scheduler: sched, Classes: []Class{{Name: "v", Match: ClassMatch{PCP: []uint8{5}}}}}, DirectionEgress, "dscp only"},
+		{"egress split tin", Policy{Scheduler: sched, Classes: []Class{{Name: "v", Match: ClassMatch{DSCP: []string{"ef", "af41"}}}}}, DirectionEgress, "not tin"},
+		{"egress shared tin", Policy{Scheduler: sched, Classes: []Class{
+			{Name: "a", Match: ClassMatch{DSCP: []string{"ef"}}},
+			{Name: "b", Match: ClassMatch{DSCP: []string{"cs5"}}},
+		}}, DirectionEgress, "shares tin"},
+	}
+	for _, tc := range cases {
+		err := tc.policy.ValidateClasses(tc.direction, lists)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestClassPolicerConfig(t *testing.T) {
+	mark := Class{Name: "m", Mark: &ClassMark{DSCP: "af41"}}
+	p, err := mark.ToPolicerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.CIR != markOnlyCIR || p.Conform.Action != ActionMarkAndTransmit || p.Conform.DSCP != 34 || p.Violate.Action != ActionTransmit {
+		t.Fatalf("mark-only policer = %+v", p)
+	}
+
+	police := Class{Name: "p", Police: &ClassRate{CIR: 1000, Violate: ActionConfig{Action: ActionDrop}}}
+	p, err = police.ToPolicerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.CIR != 1000 || p.EIR != 1000 || p.CBS != 125000 || p.Conform.Action != ActionTransmit || p.Violate.Action != ActionDrop {
+		t.Fatalf("police policer = %+v", p)
+	}
+}
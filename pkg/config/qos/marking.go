@@ -0,0 +1,259 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Ingress marking modes a service group can select.
+const (
+	MarkingTrust  = "trust"
+	MarkingBleach = "bleach"
+	MarkingRemark = "remark"
+)
+
+// Marking is the DSCP and 802.1p marking of a subscriber session.
+// Ingress is trust (the default: the CPE's DSCP is kept), bleach (every
+// packet to best effort) or remark through IngressMap. EgressPCPMap
+// sets the 802.1p priority of frames toward the access network from
+// their DSCP.
+type Marking struct {
+	Ingress      string `json:"ingress,omitempty"        yaml:"ingress,omitempty"`
+	IngressMap   string `json:"ingress-map,omitempty"    yaml:"ingress-map,omitempty"`
+	EgressPCPMap string `json:"egress-pcp-map,omitempty" yaml:"egress-pcp-map,omitempty"`
+}
+
+// IsZero reports whether the session is left as it is: DSCP trusted and
+// no 802.1p marking.
+func (m Marking) IsZero() bool {
+	return !m.Remarks() && m.EgressPCPMap == ""
+}
+
+// Remarks reports whether ingress DSCP is rewritten.
+func (m Marking) Remarks() bool {
+	return m.Ingress == MarkingBleach || m.Ingress == MarkingRemark
+}
+
+// Validate checks the mode and that remark has a map. Whether the maps
+// exist, and are of the right kind, is the config's to check.
+func (m *Marking) Validate() error {
+	switch m.Ingress {
+	case "", MarkingTrust, MarkingBleach:
+		if m.IngressMap != "" {
+			return fmt.Errorf("ingress-map is only used with ingress remark")
+		}
+	case MarkingRemark:
+		if m.IngressMap == "" {
+			return fmt.Errorf("ingress remark needs an ingress-map")
+		}
+	default:
+		return fmt.Errorf("unknown ingress %q: must be trust, bleach or remark", m.Ingress)
+	}
+	return nil
+}
+
+// MarkingMap translates the DSCP of a packet, either to a new DSCP (a
+// remark map, keyed dscp) or to an 802.1p priority (a PCP map, keyed
+// pcp). Keys and DSCP values are PHB names or code points.
+//
+// Code points a remark map does not list keep their DSCP, and those a
+// PCP map does not list take their precedence, the top three bits,
+// unless Default is set: a DSCP for remark maps, 0-7 for PCP maps.
+type MarkingMap struct {
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	DSCP        map[string]string `json:"dscp,omitempty"        yaml:"dscp,omitempty"`
+	PCP         map[string]uint8  `json:"pcp,omitempty"         yaml:"pcp,omitempty"`
+	Default     string            `json:"default,omitempty"     yaml:"default,omitempty"`
+}
+
+// IsPCP reports whether the map sets the 802.1p priority.
+func (m *MarkingMap) IsPCP() bool {
+	return len(m.PCP) > 0
+}
+
+// Validate checks the map is one kind or the other and that every code
+// point parses. A remark map may send code points to at most MaxClasses
+// new values: each is a class of the session's ingress classifier.
+func (m *MarkingMap) Validate(name string) error {
+	if (len(m.DSCP) == 0) == (len(m.PCP) == 0) {
+		return fmt.Errorf("marking-map %q: exactly one of dscp or pcp is required", name)
+	}
+	table, err := m.Table()
+	if err != nil {
+		return fmt.Errorf("marking-map %q: %w", name, err)
+	}
+	if !m.IsPCP() {
+		if n := len(RemarkTargets(table)); n > MaxClasses {
+			return fmt.Errorf("marking-map %q: at most %d distinct new DSCP values are supported, got %d", name, MaxClasses, n)
+		}
+	}
+	return nil
+}
+
+// Table returns the map as a 64-entry table indexed by DSCP: the new
+// DSCP of a remark map, the priority of a PCP map.
+func (m *MarkingMap) Table() ([]uint8, error) {
+	table := make([]uint8, 64)
+	for d := range table {
+		table[d] = uint8(d)
+		if m.IsPCP() {
+			table[d] = uint8(d) >> 3
+		}
+	}
+
+	if m.Default != "" {
+		v, err := m.parseValue(m.Default)
+		if err != nil {
+			return nil, fmt.Errorf("default: %w", err)
+		}
+		for d := range table {
+			table[d] = v
+		}
+	}
+
+	if m.IsPCP() {
+		for k, pcp := range m.PCP {
+			d, err := ParseDSCP(k)
+			if err != nil {
+				return nil, fmt.Errorf("pcp: %w", err)
+			}
+			if pcp > 7 {
+				return nil, fmt.Errorf("pcp: %s: %d is not 0-7", k, pcp)
+			}
+			table[d] = pcp
+		}
+		return table, nil
+	}
+	for k, v := range m.DSCP {
+		d, err := ParseDSCP(k)
+		if err != nil {
+			return nil, fmt.Errorf("dscp: %w", err)
+		}
+		to, err := ParseDSCP(v)
+		if err != nil {
+			return nil, fmt.Errorf("dscp: %s: %w", k, err)
+		}
+		table[d] = to
+	}
+	return table, nil
+}
+
+func (m *MarkingMap) parseValue(s string) (uint8, error) {
+	if !m.IsPCP() {
+		return ParseDSCP(s)
+	}
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || v > 7 {
+		return 0, fmt.Errorf("%q is not 0-7", s)
+	}
+	return uint8(v), nil
+}
+
+// BleachTable is the remark table of bleach mode: every code point to
+// best effort.
+func BleachTable() []uint8 {
+	return make([]uint8, 64)
+}
+
+// RemarkTargets returns, by new value, the code points a remark table
+// rewrites. Code points it leaves as they are are not listed.
+func RemarkTargets(table []uint8) map[uint8][]uint8 {
+	out := map[uint8][]uint8{}
+	for d, to := range table {
+		if uint8(d) != to {
+			out[to] = append(out[to], uint8(d))
+		}
+	}
+	return out
+}
+
+// RemarkClasses returns ingress classes that apply a remark table ahead
+// of classes: each class matches the code points the table sends to
+// the ones it lists, and is given the mark the table implies when it
+// has none of its own, so a CPE cannot reach a class, or a scheduler
+// tin further on, with a marking the table takes away. A class no code
+// point can reach any more is left out. Classes that only mark follow,
+// one per new value, for the traffic no class matches.
+//
+// A class that matches code points the table sends to different values
+// needs its own mark: its single policer can only mark one.
+func RemarkClasses(classes []Class, table []uint8) ([]Class, error) {
+	out := make([]Class, 0, len(classes))
+	for i := range classes {
+		c := classes[i]
+
+		var sources []uint8
+		if len(c.Match.DSCP) > 0 {
+			values, err := c.Match.DSCPValues()
+			if err != nil {
+				return nil, fmt.Errorf("class %q: %w", c.Name, err)
+			}
+			listed := map[uint8]bool{}
+			for _, v := range values {
+				listed[v] = true
+			}
+			for d, to := range table {
+				if listed[to] {
+					sources = append(sources, uint8(d))
+				}
+			}
+			if len(sources) == 0 {
+				continue
+			}
+			c.Match.DSCP = nil
+			if len(sources) < len(table) {
+				c.Match.DSCP = dscpStrings(sources)
+			}
+		} else {
+			for d := range table {
+				sources = append(sources, uint8(d))
+			}
+		}
+
+		if c.Mark == nil {
+			targets := map[uint8]bool{}
+			rewrites := false
+			for _, d := range sources {
+				targets[table[d]] = true
+				rewrites = rewrites || table[d] != d
+			}
+			if rewrites {
+				if len(targets) > 1 {
+					return nil, fmt.Errorf("class %q matches code points the marking map sends to different values; give it a mark", c.Name)
+				}
+				for to := range targets {
+					c.Mark = &ClassMark{DSCP: strconv.Itoa(int(to))}
+				}
+			}
+		}
+		out = append(out, c)
+	}
+
+	targets := RemarkTargets(table)
+	values := make([]int, 0, len(targets))
+	for to := range targets {
+		values = append(values, int(to))
+	}
+	sort.Ints(values)
+	for _, to := range values {
+		out = append(out, Class{
+			Name:  fmt.Sprintf("remark-%d", to),
+			Match: ClassMatch{DSCP: dscpStrings(targets[uint8(to)])},
+			Mark:  &ClassMark{DSCP: strconv.Itoa(to)},
+		})
+	}
+	return out, nil
+}
+
+func dscpStrings(values []uint8) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strconv.Itoa(int(v))
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkingMapTable(t *testing.T) {
+	remark := &MarkingMap{DSCP: map[string]string{"ef": "af21", "cs6": "be"}}
+	table, err := remark.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table[46] != 18 || table[48] != 0 || table[34] != 34 {
+		t.Errorf("remark table: ef=%d cs6=%d af41=%d", table[46], table[48], table[34])
+	}
+
+	pcp := &MarkingMap{PCP: map[string]uint8{"ef": 5}}
+	table, err = pcp.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table[46] != 5 || table[34] != 4 || table[0] != 0 {
+		t.Errorf("pcp table: ef=%d af41=%d be=%d", table[46], table[34], table[0])
+	}
+
+	pcp.Default = "1"
+	if table, _ = pcp.Table(); table[34] != 1 || table[46] != 5 {
+		t.Errorf("pcp table with default: af41=%d ef=%d", table[34], table[46])
+	}
+}
+
+func TestMarkingMapValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		m    MarkingMap
+		err  string
+	}{
+		{"remark", MarkingMap{DSCP: map[string]string{"ef": "be"}}, ""},
+		{"pcp", MarkingMap{PCP: map[string]uint8{"ef": 5}, Default: "0"}, ""},
+		{"empty", MarkingMap{}, "exactly one"},
+		{"both", MarkingMap{DSCP: map[string]string{"ef": "be"}, PCP: map[string]uint8{"ef": 5}}, "exactly one"},
+		{"bad key", MarkingMap{DSCP: map[string]string{"af44": "be"}}, "not a DSCP"},
+		{"bad pcp", MarkingMap{PCP: map[string]uint8{"ef": 8}}, "not 0-7"},
+		{"bad default", MarkingMap{PCP: map[string]uint8{"ef": 5}, Default: "ef"}, "not 0-7"},
+		{"too many values", MarkingMap{DSCP: map[string]string{
+			"1": "2", "3": "4", "5": "6", "7": "9", "11": "13", "15": "17", "19": "21", "23": "25", "27": "29",
+		}}, "at most"},
+	}
+	for _, tc := range cases {
+		err := tc.m.Validate(tc.name)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: error %v, want %q", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestRemarkClasses(t *testing.T) {
+	m := &MarkingMap{DSCP: map[string]string{"ef": "be", "cs5": "be", "af41": "af21"}}
+	table, _ := m.Table()
+	classes := []Class{
+		{Name: "voice", Match: ClassMatch{DSCP: []string{"ef"}}, Police: &ClassRate{CIR: 512}},
+		{Name: "video", Match: ClassMatch{DSCP: []string{"af21"}}, Police: &ClassRate{CIR: 4096}},
+		{Name: "sip", Match: ClassMatch{Protocol: "udp"}, Mark: &ClassMark{DSCP: "cs3"}},
+	}
+
+	got, err := RemarkClasses(classes, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range got {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, " ") != "video sip remark-0 remark-18" {
+		t.Fatalf("classes = %v", names)
+	}
+
+	video := got[0]
+	if strings.Join(video.Match.DSCP, " ") != "18 34" || video.Mark == nil || video.Mark.DSCP != "18" {
+		t.Errorf("video = %+v mark %+v", video.Match, video.Mark)
+	}
+	if got[1].Mark.DSCP != "cs3" {
+		t.Errorf("sip mark = %q", got[1].Mark.DSCP)
+	}
+	if strings.Join(got[2].Match.DSCP, " ") != "40 46" || got[2].Mark.DSCP != "0" {
+		t.Errorf("remark-0 = %+v", got[2])
+	}
+	if classes[1].Mark != nil || len(classes[1].Match.DSCP) != 1 {
+		t.Errorf("input classes changed: %+v", classes[1])
+	}
+
+	if _, err := RemarkClasses([]Class{{Name: "bulk", Match: ClassMatch{Protocol: "tcp"}, Police: &ClassRate{CIR: 1}}}, table); err == nil {
+		t.Error("unmarked class matching every code point accepted")
+	}
+
+	got, err = RemarkClasses([]Class{
+		{Name: "bulk", Match: ClassMatch{Protocol: "tcp"}, Police: &ClassRate{CIR: 1}},
+		{Name: "voice", Match: ClassMatch{DSCP: []string{"ef"}}, Police: &ClassRate{CIR: 1}},
+		{Name: "be", Match: ClassMatch{DSCP: []string{"be"}}, Police: &ClassRate{CIR: 1}},
+	}, BleachTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Mark.DSCP != "0" || got[1].Name != "be" || got[1].Match.DSCP != nil || got[2].Name != "remark-0" {
+		t.Errorf("bleached classes = %+v", got)
+	}
+}
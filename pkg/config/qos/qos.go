@@ -69,6 +69,16 @@ type Policy struct {
 	Exceed    ActionConfig     `yaml:"exceed"`
 	Violate   ActionConfig     `yaml:"violate"`
 	Scheduler *SchedulerConfig `json:"scheduler,omitempty" yaml:"scheduler,omitempty"`
+	Classes   []Class          `json:"classes,omitempty" yaml:"classes,omitempty"`
+	Schedules []PolicySchedule `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+}
+
+// PolicySchedule swaps the policy for another while a schedule is active,
+// for every session bound to it. The first active entry wins; the
+// alternate policy's own schedules are not followed.
+type PolicySchedule struct {
+	Schedule string `json:"schedule" yaml:"schedule"`
+	Policy   string `json:"policy"   yaml:"policy"`
 }
 
 func (p *Policy) Defaults() {
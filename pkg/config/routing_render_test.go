@@ -10,6 +10,7 @@ import (
 
 	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
 	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+	"github.com/veesix-networks/osvbng/pkg/config/rtbh"
 )
 
 func newRoutingConfForTest() *RoutingConf {
@@ -351,6 +352,64 @@ func TestRoutingRender_BGPNoPasswordBackwardCompat(t *testing.T) {
 	}
 }
 
+func TestRoutingRender_RTBHRouteMap(t *testing.T) {
+	cfg := &Config{RTBH: &rtbh.Config{}}
+
+	out, err := newRoutingConfForTest().GenerateConfig(cfg)
+	if err != nil {
+		t.Fatalf("GenerateConfig: %v", err)
+	}
+	if !strings.Contains(out, "route-map RTBH-BLACKHOLE permit 10\n set community blackhole no-export") {
+		t.Errorf("missing default RTBH route map\n%s", out)
+	}
+
+	cfg.RTBH.RoutePolicy = "TAG-BLACKHOLE"
+	if out, _ = newRoutingConfForTest().GenerateConfig(cfg); strings.Contains(out, "RTBH-BLACKHOLE") {
+		t.Errorf("default RTBH route map rendered alongside route-policy\n%s", out)
+	}
+}
+
+func TestRoutingRender_BGPFlowSpec(t *testing.T) {
+	cfg := &Config{
+		Protocols: protocols.ProtocolConfig{
+			BGP: &protocols.BGPConfig{
+				ASN: 65000,
+				Neighbors: map[string]*protocols.BGPNeighbor{
+					"10.0.0.2": {RemoteAS: 65010},
+					"10.0.0.3": {RemoteAS: 65000},
+				},
+				IPv4FlowSpec: &protocols.BGPFlowSpecAddressFamily{
+					Neighbors: map[string]*protocols.BGPNeighborAFIConfig{
+						"10.0.0.2": {RoutePolicyIn: "SCRUBBER-IN"},
+					},
+					Interfaces: []string{"eth1"},
+				},
+			},
+		},
+	}
+
+	out, err := newRoutingConfForTest().GenerateConfig(cfg)
+	if err != nil {
+		t.Fatalf("GenerateConfig: %v", err)
+	}
+
+	start := strings.Index(out, "address-family ipv4 flowspec")
+	if start < 0 {
+		t.Fatalf("missing ipv4 flowspec address family\n%s", out)
+	}
+	block := out[start:]
+	block = block[:strings.Index(block, "exit-address-family")]
+	if !strings.Contains(block, "neighbor 10.0.0.2 activate") || !strings.Contains(block, "neighbor 10.0.0.2 route-map SCRUBBER-IN in") {
+		t.Errorf("flowspec neighbor not activated\n%s", block)
+	}
+	if strings.Contains(block, "10.0.0.3") {
+		t.Errorf("neighbor outside the flowspec address family activated\n%s", block)
+	}
+	if strings.Contains(out, "ipv6 flowspec") {
+		t.Errorf("unconfigured ipv6 flowspec rendered\n%s", out)
+	}
+}
+
 func TestRoutingRender_OSPFVRFInstance(t *testing.T) {
 	cfg := &Config{
 		Protocols: protocols.ProtocolConfig{
@@ -0,0 +1,157 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package rtbh holds the configuration of remote-triggered blackholing:
+// how long a blackhole lasts and how it is advertised over BGP.
+package rtbh
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDuration    = time.Hour
+	DefaultMaxDuration = 24 * time.Hour
+
+	// DefaultRoutePolicy is the route map rendered into the routing
+	// config, setting Communities, when no route-policy is configured.
+	DefaultRoutePolicy = "RTBH-BLACKHOLE"
+
+	// The shortest prefixes that can be blackholed. Upstream networks
+	// commonly accept nothing shorter.
+	MinIPv4Length = 24
+	MinIPv6Length = 48
+)
+
+// DefaultCommunities are BLACKHOLE (RFC 7999, 65535:666) and NO_EXPORT,
+// which keeps the blackhole inside the neighbouring AS.
+var DefaultCommunities = []string{"blackhole", "no-export"}
+
+var (
+	communityRE          = regexp.MustCompile(`^\d+:\d+$`)
+	wellKnownCommunities = map[string]bool{
+		"no-export":    true,
+		"no-advertise": true,
+		"no-peer":      true,
+		"blackhole":    true,
+		"local-AS":     true,
+	}
+)
+
+// Config enables remote-triggered blackholes. A blackhole drops traffic
+// toward a subscriber's address or prefix in the dataplane and, when
+// BGP is configured, advertises it through RoutePolicy so the upstream
+// networks drop it too. Every blackhole expires: after the duration it
+// was requested with, or DefaultDuration, capped at MaxDuration.
+type Config struct {
+	DefaultDuration time.Duration `json:"default-duration,omitempty" yaml:"default-duration,omitempty"`
+	MaxDuration     time.Duration `json:"max-duration,omitempty" yaml:"max-duration,omitempty"`
+	Communities     []string      `json:"communities,omitempty" yaml:"communities,omitempty"`
+	RoutePolicy     string        `json:"route-policy,omitempty" yaml:"route-policy,omitempty"`
+}
+
+func (c *Config) GetDefaultDuration() time.Duration {
+	if c == nil || c.DefaultDuration <= 0 {
+		return DefaultDuration
+	}
+	return c.DefaultDuration
+}
+
+func (c *Config) GetMaxDuration() time.Duration {
+	if c == nil || c.MaxDuration <= 0 {
+		return DefaultMaxDuration
+	}
+	return c.MaxDuration
+}
+
+func (c *Config) GetCommunities() []string {
+	if c == nil || len(c.Communities) == 0 {
+		return DefaultCommunities
+	}
+	return c.Communities
+}
+
+// CommunityString is GetCommunities as the routing config sets them.
+func (c *Config) CommunityString() string {
+	return strings.Join(c.GetCommunities(), " ")
+}
+
+// GetRoutePolicy returns the route map blackholes are advertised with.
+func (c *Config) GetRoutePolicy() string {
+	if c == nil || c.RoutePolicy == "" {
+		return DefaultRoutePolicy
+	}
+	return c.RoutePolicy
+}
+
+// Validate checks the durations and communities. Whether route-policy
+// exists is the config's to check.
+func (c *Config) Validate() error {
+	if c.DefaultDuration < 0 || c.MaxDuration < 0 {
+		return fmt.Errorf("rtbh: durations must not be negative")
+	}
+	if c.GetDefaultDuration() > c.GetMaxDuration() {
+		return fmt.Errorf("rtbh: default-duration %s is longer than max-duration %s", c.GetDefaultDuration(), c.GetMaxDuration())
+	}
+	if c.RoutePolicy != "" && len(c.Communities) > 0 {
+		return fmt.Errorf("rtbh: communities are set by route-policy %q; configure one or the other", c.RoutePolicy)
+	}
+	for i, community := range c.Communities {
+		if !communityRE.MatchString(community) && !wellKnownCommunities[community] {
+			return fmt.Errorf("rtbh: communities[%d]: invalid community %q (expected AA:NN or well-known name)", i, community)
+		}
+	}
+	return nil
+}
+
+// Duration returns how long a blackhole requested for d lasts: the
+// default when d is zero, at most the maximum.
+func (c *Config) Duration(d time.Duration) (time.Duration, error) {
+	if d < 0 {
+		return 0, fmt.Errorf("duration must not be negative")
+	}
+	if d == 0 {
+		d = c.GetDefaultDuration()
+	}
+	if max := c.GetMaxDuration(); d > max {
+		return 0, fmt.Errorf("duration %s is longer than max-duration %s", d, max)
+	}
+	return d, nil
+}
+
+// ParsePrefix parses the address or prefix to blackhole. A bare address
+// is a /32 or /128. The prefix is masked and must be no shorter than
+// MinIPv4Length or MinIPv6Length.
+func ParsePrefix(s string) (netip.Prefix, error) {
+	var p netip.Prefix
+	if strings.Contains(s, "/") {
+		var err error
+		if p, err = netip.ParsePrefix(s); err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid prefix %q", s)
+		}
+	} else {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid address %q", s)
+		}
+		p = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	if p.Addr().Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("invalid address %q", s)
+	}
+	p = p.Masked()
+
+	min := MinIPv4Length
+	if p.Addr().Is6() {
+		min = MinIPv6Length
+	}
+	if p.Bits() < min {
+		return netip.Prefix{}, fmt.Errorf("prefix %s is shorter than /%d", p, min)
+	}
+	return p, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePrefix(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{"198.51.100.7", "198.51.100.7/32", ""},
+		{"2001:db8::1", "2001:db8::1/128", ""},
+		{"198.51.100.7/24", "198.51.100.0/24", ""},
+		{"2001:db8:1:2::/56", "2001:db8:1::/56", ""},
+		{"198.51.0.0/16", "", "shorter than /24"},
+		{"2001:db8::/32", "", "shorter than /48"},
+		{"subscriber", "", "invalid address"},
+		{"198.51.100.7/33", "", "invalid prefix"},
+	}
+	for _, tc := range cases {
+		got, err := ParsePrefix(tc.in)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: error %v, want %q", tc.in, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tc.want {
+			t.Errorf("%s: got %s, %v, want %s", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	var cfg *Config
+	if d, err := cfg.Duration(0); err != nil || d != DefaultDuration {
+		t.Errorf("default = %s, %v", d, err)
+	}
+
+	cfg = &Config{DefaultDuration: 10 * time.Minute, MaxDuration: time.Hour}
+	if d, _ := cfg.Duration(0); d != 10*time.Minute {
+		t.Errorf("configured default = %s", d)
+	}
+	if d, _ := cfg.Duration(30 * time.Minute); d != 30*time.Minute {
+		t.Errorf("requested = %s", d)
+	}
+	if _, err := cfg.Duration(2 * time.Hour); err == nil {
+		t.Error("duration over max-duration accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{"empty", Config{}, ""},
+		{"communities", Config{Communities: []string{"blackhole", "64500:666"}}, ""},
+		{"bad community", Config{Communities: []string{"666"}}, "invalid community"},
+		{"default over max", Config{DefaultDuration: 2 * time.Hour, MaxDuration: time.Hour}, "longer than max-duration"},
+		{"policy and communities", Config{RoutePolicy: "TAG", Communities: []string{"blackhole"}}, "one or the other"},
+	}
+	for _, tc := range cases {
+		err := tc.cfg.Validate()
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: error %v, want %q", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package schedule holds the schedules config block: named sets of
+// weekly time windows that switch service groups and QoS policies to
+// their alternate bindings, for off-peak boosts and night-time plans.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule is active while the time in its timezone falls in any of its
+// windows.
+type Schedule struct {
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"    yaml:"timezone,omitempty"`
+	Windows     []Window `json:"windows"               yaml:"windows"`
+}
+
+// Window opens at Start on each of Days and closes at End. An End at or
+// before Start closes on the following day, so 22:00-06:00 on fri runs
+// through Friday night. Days takes names (mon, tue, ...), cron-style
+// ranges (mon-fri) and "*"; empty means every day.
+type Window struct {
+	Days  []string `json:"days,omitempty" yaml:"days,omitempty"`
+	Start string   `json:"start"          yaml:"start"`
+	End   string   `json:"end"            yaml:"end"`
+}
+
+var dayNumbers = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Validate checks the schedule, name being its config key.
+func (s *Schedule) Validate(name string) error {
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("schedule %q: %w", name, err)
+	}
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("schedule %q: at least one window is required", name)
+	}
+	for i := range s.Windows {
+		if _, err := s.Windows[i].compile(); err != nil {
+			return fmt.Errorf("schedule %q: windows[%d]: %w", name, i, err)
+		}
+	}
+	return nil
+}
+
+// Active reports whether t falls in one of the schedule's windows.
+func (s *Schedule) Active(t time.Time) bool {
+	loc, windows, err := s.compile()
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	for _, w := range windows {
+		// A window that wraps midnight may have opened yesterday.
+		for _, back := range []int{0, -1} {
+			opens, closes := w.on(t, back, loc)
+			if opens.IsZero() {
+				continue
+			}
+			if !t.Before(opens) && t.Before(closes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Next returns the first window opening or closing after t, or the zero
+// time if the schedule never changes.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc, windows, err := s.compile()
+	if err != nil {
+		return time.Time{}
+	}
+	t = t.In(loc)
+	var next time.Time
+	for _, w := range windows {
+		for day := -1; day <= 7; day++ {
+			opens, closes := w.on(t, day, loc)
+			if opens.IsZero() {
+				continue
+			}
+			for _, b := range []time.Time{opens, closes} {
+				if b.After(t) && (next.IsZero() || b.Before(next)) {
+					next = b
+				}
+			}
+		}
+	}
+	return next
+}
+
+// locations caches loaded timezones: LoadLocation reads the zone file
+// on every call, and a boundary evaluates schedules once per session.
+var locations sync.Map
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	if loc, ok := locations.Load(s.Timezone); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	locations.Store(s.Timezone, loc)
+	return loc, nil
+}
+
+func (s *Schedule) compile() (*time.Location, []window, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]window, 0, len(s.Windows))
+	for i := range s.Windows {
+		w, err := s.Windows[i].compile()
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, w)
+	}
+	return loc, out, nil
+}
+
+// window is a Window parsed: the days it opens on and minutes since
+// midnight.
+type window struct {
+	days       [7]bool
+	start, end int
+}
+
+// on returns the window's opening and closing on the day offset days
+// from t's, or zero times if it does not open that day. Building the
+// times with time.Date keeps a window on wall-clock time across DST.
+func (w window) on(t time.Time, offset int, loc *time.Location) (time.Time, time.Time) {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+	if !w.days[day.Weekday()] {
+		return time.Time{}, time.Time{}
+	}
+	opens := time.Date(y, m, d+offset, w.start/60, w.start%60, 0, 0, loc)
+	endDay := d + offset
+	if w.end <= w.start {
+		endDay++
+	}
+	closes := time.Date(y, m, endDay, w.end/60, w.end%60, 0, 0, loc)
+	return opens, closes
+}
+
+func (w *Window) compile() (window, error) {
+	var out window
+	var err error
+	if out.start, err = parseClock(w.Start); err != nil {
+		return window{}, fmt.Errorf("start: %w", err)
+	}
+	if out.end, err = parseClock(w.End); err != nil {
+		return window{}, fmt.Errorf("end: %w", err)
+	}
+	if out.start == 24*60 {
+		return window{}, fmt.Errorf("start: 24:00 is only valid as an end")
+	}
+	if len(w.Days) == 0 {
+		out.days = [7]bool{true, true, true, true, true, true, true}
+		return out, nil
+	}
+	for _, d := range w.Days {
+		if err := addDays(&out.days, d); err != nil {
+			return window{}, fmt.Errorf("days: %w", err)
+		}
+	}
+	return out, nil
+}
+
+func addDays(days *[7]bool, spec string) error {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "*" {
+		*days = [7]bool{true, true, true, true, true, true, true}
+		return nil
+	}
+	first, last, isRange := strings.Cut(spec, "-")
+	from, ok := dayNumbers[first]
+	if !ok {
+		return fmt.Errorf("%q is not a day", first)
+	}
+	to := from
+	if isRange {
+		if to, ok = dayNumbers[last]; !ok {
+			return fmt.Errorf("%q is not a day", last)
+		}
+	}
+	// A range runs forward through the week, so sat-sun and fri-mon wrap.
+	for d := from; ; d = (d + 1) % 7 {
+		days[d] = true
+		if d == to {
+			break
+		}
+	}
+	return nil
+}
+
+// parseClock parses HH:MM into minutes since midnight. 24:00 is allowed
+// so a window can close at the end of the day.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || len(mm) != 2 || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return h*60 + m, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func at(t *testing.T, loc *time.Location, s string) time.Time {
+	t.Helper()
+	v, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestActiveWrapsMidnight(t *testing.T) {
+	s := &Schedule{Timezone: "UTC", Windows: []Window{
+		{Days: []string{"fri"}, Start: "22:00", End: "06:00"},
+	}}
+	// 2026-10-16 is a Friday.
+	cases := map[string]bool{
+		"2026-10-16 21:59": false,
+		"2026-10-16 22:00": true,
+		"2026-10-17 05:59": true,
+		"2026-10-17 06:00": false,
+		"2026-10-17 22:30": false,
+	}
+	for when, want := range cases {
+		if got := s.Active(at(t, time.UTC, when)); got != want {
+			t.Errorf("Active(%s) = %v, want %v", when, got, want)
+		}
+	}
+}
+
+func TestActiveDayRanges(t *testing.T) {
+	s := &Schedule{Timezone: "UTC", Windows: []Window{
+		{Days: []string{"mon-fri"}, Start: "00:00", End: "07:00"},
+		{Days: []string{"sat-sun"}, Start: "00:00", End: "24:00"},
+	}}
+	cases := map[string]bool{
+		"2026-10-12 03:00": true,  // Monday
+		"2026-10-12 07:00": false, // Monday
+		"2026-10-17 15:00": true,  // Saturday
+		"2026-10-18 23:59": true,  // Sunday
+	}
+	for when, want := range cases {
+		if got := s.Active(at(t, time.UTC, when)); got != want {
+			t.Errorf("Active(%s) = %v, want %v", when, got, want)
+		}
+	}
+}
+
+func TestActiveTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	s := &Schedule{Timezone: "Asia/Tokyo", Windows: []Window{{Start: "01:00", End: "05:00"}}}
+	// 02:00 in Tokyo is 17:00 UTC the day before.
+	if !s.Active(at(t, tokyo, "2026-10-16 02:00").UTC()) {
+		t.Fatal("window not evaluated in the schedule's timezone")
+	}
+	if s.Active(at(t, time.UTC, "2026-10-16 02:00")) {
+		t.Fatal("window evaluated in UTC")
+	}
+}
+
+func TestNext(t *testing.T) {
+	s := &Schedule{Timezone: "UTC", Windows: []Window{
+		{Days: []string{"fri"}, Start: "22:00", End: "06:00"},
+	}}
+	cases := map[string]string{
+		"2026-10-16 12:00": "2026-10-16 22:00",
+		"2026-10-16 22:00": "2026-10-17 06:00",
+		"2026-10-17 06:00": "2026-10-23 22:00",
+	}
+	for from, want := range cases {
+		if got := s.Next(at(t, time.UTC, from)); !got.Equal(at(t, time.UTC, want)) {
+			t.Errorf("Next(%s) = %s, want %s", from, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		s    Schedule
+		want string
+	}{
+		{Schedule{}, "at least one window"},
+		{Schedule{Timezone: "Nowhere/City", Windows: []Window{{Start: "00:00", End: "01:00"}}}, "timezone"},
+		{Schedule{Windows: []Window{{Start: "7:0", End: "08:00"}}}, "start"},
+		{Schedule{Windows: []Window{{Start: "24:00", End: "08:00"}}}, "only valid as an end"},
+		{Schedule{Windows: []Window{{Start: "00:00", End: "25:00"}}}, "end"},
+		{Schedule{Windows: []Window{{Days: []string{"mon-xyz"}, Start: "00:00", End: "01:00"}}}, "days"},
+	}
+	for _, c := range cases {
+		err := c.s.Validate("s")
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", c.s, err, c.want)
+		}
+	}
+
+	ok := Schedule{Windows: []Window{{Days: []string{"fri-mon", "*"}, Start: "22:00", End: "24:00"}}}
+	if err := ok.Validate("s"); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+}
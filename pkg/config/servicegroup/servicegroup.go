@@ -1,22 +1,69 @@
 package servicegroup
 
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+)
+
 type Config struct {
 	VRF         string           `json:"vrf,omitempty" yaml:"vrf,omitempty"`
 	Unnumbered  string           `json:"unnumbered,omitempty" yaml:"unnumbered,omitempty"`
 	URPF        string           `json:"urpf,omitempty" yaml:"urpf,omitempty"`
 	ACL         *ACLConfig       `json:"acl,omitempty" yaml:"acl,omitempty"`
 	QoS         *QoSConfig       `json:"qos,omitempty" yaml:"qos,omitempty"`
+	Marking     *qos.Marking     `json:"marking,omitempty" yaml:"marking,omitempty"`
 	Pool        string           `json:"pool,omitempty" yaml:"pool,omitempty"`
 	IANAPool    string           `json:"iana-pool,omitempty" yaml:"iana-pool,omitempty"`
 	PDPool      string           `json:"pd-pool,omitempty" yaml:"pd-pool,omitempty"`
 	IPv4Profile string           `json:"ipv4-profile,omitempty" yaml:"ipv4-profile,omitempty"`
 	IPv6Profile string           `json:"ipv6-profile,omitempty" yaml:"ipv6-profile,omitempty"`
 	CGNAT       *CGNATConfig     `json:"cgnat,omitempty" yaml:"cgnat,omitempty"`
+	NPTv6       *NPTv6Config     `json:"nptv6,omitempty" yaml:"nptv6,omitempty"`
+	Steering    string           `json:"steering-policy,omitempty" yaml:"steering-policy,omitempty"`
+	Schedules   []ScheduleConfig `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+}
+
+// ScheduleConfig overrides the group's ACL and QoS bindings while the
+// named schedule is active. Entries are applied in order, so a later
+// active entry overrides an earlier one field by field; per-subscriber
+// AAA attributes still override both.
+type ScheduleConfig struct {
+	Schedule string     `json:"schedule" yaml:"schedule"`
+	ACL      *ACLConfig `json:"acl,omitempty" yaml:"acl,omitempty"`
+	QoS      *QoSConfig `json:"qos,omitempty" yaml:"qos,omitempty"`
 }
 
+// CGNAT translation modes a service group can select.
+const (
+	CGNATModeNAT44  = "nat44"
+	CGNATModeNAT64  = "nat64"
+	CGNATModeDSLite = "dslite"
+	CGNATModeMAP    = "map"
+)
+
+// CGNATConfig selects the translation for the group's subscribers.
+// Mode is nat44 (IPv4 subscribers), nat64 (IPv6-only subscribers reach
+// IPv4 through the NAT64 prefix) or dslite (IPv6-only subscribers with
+// a B4); Policy names a cgnat pool of that kind. An empty Mode follows
+// the policy pool. Mode map instead places the subscribers in the MAP
+// domain named by MAPDomain, which needs no pool.
 type CGNATConfig struct {
-	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
-	Bypass bool   `json:"bypass,omitempty" yaml:"bypass,omitempty"`
+	Policy    string `json:"policy,omitempty" yaml:"policy,omitempty"`
+	Bypass    bool   `json:"bypass,omitempty" yaml:"bypass,omitempty"`
+	Mode      string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	MAPDomain string `json:"map-domain,omitempty" yaml:"map-domain,omitempty"`
+}
+
+// NPTv6Config translates the group's delegated prefixes statelessly
+// (RFC 6296). InternalPrefix is the prefix the customer numbers the
+// LAN from, a ULA or a provider-independent prefix; the subscriber's
+// delegated prefix is the external side and must have the same length.
+// Uplink is the core-facing interface the translation is bound on; empty
+// picks the only uplink of the subscriber's VRF.
+type NPTv6Config struct {
+	InternalPrefix string `json:"internal-prefix,omitempty" yaml:"internal-prefix,omitempty"`
+	Uplink         string `json:"uplink,omitempty" yaml:"uplink,omitempty"`
 }
 
 type ACLConfig struct {
@@ -25,8 +72,42 @@ type ACLConfig struct {
 }
 
 type QoSConfig struct {
-	IngressPolicy string `json:"ingress-policy,omitempty" yaml:"ingress-policy,omitempty"`
-	EgressPolicy  string `json:"egress-policy,omitempty" yaml:"egress-policy,omitempty"`
-	UploadRate    uint64 `json:"upload-rate,omitempty" yaml:"upload-rate,omitempty"`
-	DownloadRate  uint64 `json:"download-rate,omitempty" yaml:"download-rate,omitempty"`
+	IngressPolicy string          `json:"ingress-policy,omitempty" yaml:"ingress-policy,omitempty"`
+	EgressPolicy  string          `json:"egress-policy,omitempty" yaml:"egress-policy,omitempty"`
+	UploadRate    uint64          `json:"upload-rate,omitempty" yaml:"upload-rate,omitempty"`
+	DownloadRate  uint64          `json:"download-rate,omitempty" yaml:"download-rate,omitempty"`
+	LineRate      *LineRateConfig `json:"line-rate,omitempty" yaml:"line-rate,omitempty"`
+}
+
+// LineRateConfig caps the group's QoS at the rate the subscriber's line
+// is trained at, as the access node reports it: each direction is held
+// to min(plan rate, line rate x OverheadFactor). The factor discounts
+// the access encapsulation the line rate includes, such as ATM cell tax
+// on ADSL; zero means 1.
+type LineRateConfig struct {
+	Enabled        bool    `json:"enabled" yaml:"enabled"`
+	OverheadFactor float64 `json:"overhead-factor,omitempty" yaml:"overhead-factor,omitempty"`
+}
+
+// Factor returns the overhead factor to apply, or 0 if line-rate
+// shaping is off.
+func (l *LineRateConfig) Factor() float64 {
+	if l == nil || !l.Enabled {
+		return 0
+	}
+	if l.OverheadFactor == 0 {
+		return 1
+	}
+	return l.OverheadFactor
+}
+
+// Validate checks the overhead factor is a fraction of the line rate.
+func (l *LineRateConfig) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.OverheadFactor < 0 || l.OverheadFactor > 1 {
+		return fmt.Errorf("overhead-factor %g must be between 0 and 1", l.OverheadFactor)
+	}
+	return nil
 }
@@ -0,0 +1,105 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package steering holds the steering-policies config block: traffic
+// steering by policy-based forwarding. A session bound to a policy has
+// the traffic its access list permits forwarded to the policy's next
+// hops, such as a DPI or parental-control appliance, instead of by its
+// VRF's routing table. Everything else forwards as usual.
+package steering
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Policy steers what AccessList permits to NextHops, shared between
+// them when there are several of the same family. A policy with a
+// health check only uses the next hops that answer it and fails open,
+// leaving its sessions to forward normally, when none do.
+type Policy struct {
+	AccessList  string       `json:"access-list"            yaml:"access-list"`
+	NextHops    []NextHop    `json:"next-hops"              yaml:"next-hops"`
+	HealthCheck *HealthCheck `json:"health-check,omitempty" yaml:"health-check,omitempty"`
+}
+
+// NextHop is one next hop, resolved in VRF or in the default table if
+// VRF is empty. Health probes are sent from the same VRF.
+type NextHop struct {
+	Address string `json:"address"       yaml:"address"`
+	VRF     string `json:"vrf,omitempty" yaml:"vrf,omitempty"`
+}
+
+// IP returns the next hop's address, or nil if it does not parse.
+func (n NextHop) IP() net.IP {
+	return net.ParseIP(n.Address)
+}
+
+// Key identifies the next hop across policies.
+func (n NextHop) Key() string {
+	if n.VRF == "" {
+		return n.Address
+	}
+	return n.VRF + "/" + n.Address
+}
+
+// HealthCheck configures ICMP echo probing of a policy's next hops. A
+// next hop is marked down after DownAfter consecutive failed probes and
+// back up after UpAfter consecutive answered ones; until its first
+// probes complete it is used.
+type HealthCheck struct {
+	Interval  time.Duration `json:"interval,omitempty"   yaml:"interval,omitempty"`
+	Timeout   time.Duration `json:"timeout,omitempty"    yaml:"timeout,omitempty"`
+	DownAfter int           `json:"down-after,omitempty" yaml:"down-after,omitempty"`
+	UpAfter   int           `json:"up-after,omitempty"   yaml:"up-after,omitempty"`
+}
+
+// WithDefaults returns a copy with unset fields filled in.
+func (h HealthCheck) WithDefaults() HealthCheck {
+	if h.Interval <= 0 {
+		h.Interval = 5 * time.Second
+	}
+	if h.Timeout <= 0 {
+		h.Timeout = time.Second
+	}
+	if h.DownAfter <= 0 {
+		h.DownAfter = 3
+	}
+	if h.UpAfter <= 0 {
+		h.UpAfter = 1
+	}
+	return h
+}
+
+// Validate checks the policy, name being its config key. References to
+// access lists and VRFs are checked against the rest of the config by
+// the config package.
+func (p *Policy) Validate(name string) error {
+	if p.AccessList == "" {
+		return fmt.Errorf("steering-policy %q: access-list is required", name)
+	}
+	if len(p.NextHops) == 0 {
+		return fmt.Errorf("steering-policy %q: at least one next hop is required", name)
+	}
+	seen := make(map[string]bool, len(p.NextHops))
+	for i, hop := range p.NextHops {
+		if hop.IP() == nil {
+			return fmt.Errorf("steering-policy %q: next-hops[%d]: invalid address %q", name, i, hop.Address)
+		}
+		if seen[hop.Key()] {
+			return fmt.Errorf("steering-policy %q: next-hops[%d]: %s is listed twice", name, i, hop.Address)
+		}
+		seen[hop.Key()] = true
+	}
+	if hc := p.HealthCheck; hc != nil {
+		if hc.Interval < 0 || hc.Timeout < 0 || hc.DownAfter < 0 || hc.UpAfter < 0 {
+			return fmt.Errorf("steering-policy %q: health-check: values must not be negative", name)
+		}
+		if hc := hc.WithDefaults(); hc.Timeout > hc.Interval {
+			return fmt.Errorf("steering-policy %q: health-check: timeout %s is longer than the interval %s", name, hc.Timeout, hc.Interval)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy Policy
+		err    string
+	}{
+		{"ok", Policy{AccessList: "web", NextHops: []NextHop{{Address: "192.0.2.1"}, {Address: "2001:db8::1"}}}, ""},
+		{"same address in two vrfs", Policy{AccessList: "web", NextHops: []NextHop{{Address: "192.0.2.1"}, {Address: "192.0.2.1", VRF: "dpi"}}}, ""},
+		{"no access list", Policy{NextHops: []NextHop{{Address: "192.0.2.1"}}}, "access-list is required"},
+		{"no next hops", Policy{AccessList: "web"}, "at least one next hop"},
+		{"bad address", Policy{AccessList: "web", NextHops: []NextHop{{Address: "dpi-1"}}}, "invalid address"},
+		{"duplicate", Policy{AccessList: "web", NextHops: []NextHop{{Address: "192.0.2.1"}, {Address: "192.0.2.1"}}}, "listed twice"},
+		{"timeout over interval", Policy{AccessList: "web", NextHops: []NextHop{{Address: "192.0.2.1"}},
+			HealthCheck: &HealthCheck{Interval: time.Second, Timeout: 2 * time.Second}}, "longer than the interval"},
+	}
+	for _, tc := range cases {
+		err := tc.policy.Validate("dpi")
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.err)
+		}
+	}
+}
@@ -43,6 +43,28 @@ const (
 	SessionModeIndependent SessionMode = "independent"
 )
 
+// LocalSwitchingMode decides the traffic IPoE subscribers of a group
+// send each other. Unset, the BNG neither answers ARP or ND for other
+// subscribers nor filters what subscribers route to each other through
+// the gateway.
+type LocalSwitchingMode string
+
+const (
+	// LocalSwitchingIsolate drops the traffic subscribers of the group
+	// send to the group's pools.
+	LocalSwitchingIsolate LocalSwitchingMode = "isolate"
+
+	// LocalSwitchingHairpin answers ARP and ND for other subscribers of
+	// the group with the gateway's address, so their traffic to each
+	// other is routed through the BNG.
+	LocalSwitchingHairpin LocalSwitchingMode = "hairpin"
+
+	// LocalSwitchingServiceGroup hairpins the traffic between
+	// subscribers of the group in the same service group and drops the
+	// rest as LocalSwitchingIsolate does.
+	LocalSwitchingServiceGroup LocalSwitchingMode = "service-group"
+)
+
 type AccessType string
 
 const (
@@ -71,6 +93,7 @@ type SubscriberGroup struct {
 	DHCPv6              *SubscriberDHCPv6      `json:"dhcpv6,omitempty" yaml:"dhcpv6,omitempty"`
 	L2TP                *SubscriberL2TPConfig  `json:"l2tp,omitempty" yaml:"l2tp,omitempty"`
 	L2GW                *SubscriberL2GWConfig  `json:"l2gw,omitempty" yaml:"l2gw,omitempty"`
+	LocalSwitching      LocalSwitchingMode     `json:"local-switching,omitempty" yaml:"local-switching,omitempty"`
 }
 
 // SubscriberL2GWConfig binds an l2gw access-type group to a default
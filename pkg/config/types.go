@@ -6,8 +6,10 @@ package config
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/veesix-networks/osvbng/pkg/config/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
 	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
 	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
 	"github.com/veesix-networks/osvbng/pkg/config/ip"
@@ -16,7 +18,10 @@ import (
 	"github.com/veesix-networks/osvbng/pkg/config/protocols"
 	"github.com/veesix-networks/osvbng/pkg/config/qos"
 	routing_policy "github.com/veesix-networks/osvbng/pkg/config/routing_policy"
+	"github.com/veesix-networks/osvbng/pkg/config/rtbh"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
 	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/steering"
 	"github.com/veesix-networks/osvbng/pkg/config/subscriber"
 	"github.com/veesix-networks/osvbng/pkg/config/system"
 )
@@ -37,17 +42,22 @@ type Config struct {
 	HA               HAConfig                           `json:"ha,omitempty" yaml:"ha,omitempty"`
 	L2TP             *l2tp.L2TPConfig                   `json:"l2tp,omitempty" yaml:"l2tp,omitempty"`
 	L2GW             *l2gwcfg.L2GWConfig                `json:"l2gw,omitempty" yaml:"l2gw,omitempty"`
+	RTBH             *rtbh.Config                       `json:"rtbh,omitempty" yaml:"rtbh,omitempty"`
 
 	// Walked in struct order, dependency order matters
-	System          *SystemConfig                          `json:"system,omitempty" yaml:"system,omitempty"`
-	RoutingPolicies *routing_policy.RoutingPolicyConfig    `json:"routing-policies,omitempty" yaml:"routing-policies,omitempty"`
-	VRFS            map[string]*ip.VRFSConfig              `json:"vrfs,omitempty" yaml:"vrfs,omitempty"`
-	QoSPolicies     map[string]*qos.Policy                 `json:"qos-policies,omitempty" yaml:"qos-policies,omitempty"`
-	ServiceGroups   map[string]*servicegroup.Config        `json:"service-groups,omitempty" yaml:"service-groups,omitempty"`
-	Interfaces      map[string]*interfaces.InterfaceConfig `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
-	QoSAggregates   map[string]*qos.Aggregate              `json:"qos-aggregates,omitempty" yaml:"qos-aggregates,omitempty"`
-	Protocols       protocols.ProtocolConfig               `json:"protocols,omitempty" yaml:"protocols,omitempty"`
-	AAA             aaa.AAAConfig                          `json:"aaa,omitempty" yaml:"aaa,omitempty"`
+	System           *SystemConfig                          `json:"system,omitempty" yaml:"system,omitempty"`
+	RoutingPolicies  *routing_policy.RoutingPolicyConfig    `json:"routing-policies,omitempty" yaml:"routing-policies,omitempty"`
+	VRFS             map[string]*ip.VRFSConfig              `json:"vrfs,omitempty" yaml:"vrfs,omitempty"`
+	QoSPolicies      map[string]*qos.Policy                 `json:"qos-policies,omitempty" yaml:"qos-policies,omitempty"`
+	MarkingMaps      map[string]*qos.MarkingMap             `json:"marking-maps,omitempty" yaml:"marking-maps,omitempty"`
+	AccessLists      map[string]*acl.AccessList             `json:"access-lists,omitempty" yaml:"access-lists,omitempty"`
+	Schedules        map[string]*schedule.Schedule          `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	SteeringPolicies map[string]*steering.Policy            `json:"steering-policies,omitempty" yaml:"steering-policies,omitempty"`
+	ServiceGroups    map[string]*servicegroup.Config        `json:"service-groups,omitempty" yaml:"service-groups,omitempty"`
+	Interfaces       map[string]*interfaces.InterfaceConfig `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	QoSAggregates    map[string]*qos.Aggregate              `json:"qos-aggregates,omitempty" yaml:"qos-aggregates,omitempty"`
+	Protocols        protocols.ProtocolConfig               `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+	AAA              aaa.AAAConfig                          `json:"aaa,omitempty" yaml:"aaa,omitempty"`
 
 	// Plugin configs (handled separately)
 	Plugins map[string]interface{} `json:"plugins,omitempty" yaml:"plugins,omitempty"`
@@ -123,3 +133,43 @@ func (c *Config) GetAccessInterface() (string, error) {
 	}
 	return "", fmt.Errorf("multiple parent-interfaces configured across subscriber groups (only 1 allowed): %v", names)
 }
+
+// ServiceGroupAFTRName returns the DS-Lite AFTR-Name to hand subscribers
+// of a service group, or "" when its CGNAT policy is not a DS-Lite pool.
+func (c *Config) ServiceGroupAFTRName(serviceGroup string) string {
+	if c == nil || c.CGNAT == nil || serviceGroup == "" {
+		return ""
+	}
+	sg, ok := c.ServiceGroups[serviceGroup]
+	if !ok || sg.CGNAT == nil || sg.CGNAT.Bypass {
+		return ""
+	}
+	return c.CGNAT.AFTRName(sg.CGNAT.Policy)
+}
+
+// ServiceGroupDNS64 returns the DNS64 server to hand subscribers of a
+// service group, or nil when its CGNAT policy is not a NAT64 pool with
+// DNS64.
+func (c *Config) ServiceGroupDNS64(serviceGroup string) []net.IP {
+	if c == nil || c.CGNAT == nil || serviceGroup == "" {
+		return nil
+	}
+	sg, ok := c.ServiceGroups[serviceGroup]
+	if !ok || sg.CGNAT == nil || sg.CGNAT.Bypass {
+		return nil
+	}
+	return c.CGNAT.DNS64Servers(sg.CGNAT.Policy)
+}
+
+// ServiceGroupMAP returns the MAP softwire configuration to hand
+// subscribers of a service group, or nil when it selects no MAP domain.
+func (c *Config) ServiceGroupMAP(serviceGroup string) *cgnat.MAPParams {
+	if c == nil || c.CGNAT == nil || serviceGroup == "" {
+		return nil
+	}
+	sg, ok := c.ServiceGroups[serviceGroup]
+	if !ok || sg.CGNAT == nil || sg.CGNAT.Bypass {
+		return nil
+	}
+	return c.CGNAT.MAPParams(sg.CGNAT.MAPDomain)
+}
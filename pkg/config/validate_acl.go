@@ -0,0 +1,81 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import "fmt"
+
+// validateAccessLists checks every access list and that the ACLs a
+// service group attaches are defined. Names returned by AAA are only
+// resolved when a session comes up.
+func (c *Config) validateAccessLists() error {
+	for name, list := range c.AccessLists {
+		if list == nil {
+			continue
+		}
+		if err := list.Validate(name); err != nil {
+			return err
+		}
+	}
+	for name, sg := range c.ServiceGroups {
+		if sg == nil || sg.ACL == nil {
+			continue
+		}
+		if err := c.CheckACLReference(sg.ACL.Ingress); err != nil {
+			return fmt.Errorf("service-groups.%s.acl.ingress: %w", name, err)
+		}
+		if err := c.CheckACLReference(sg.ACL.Egress); err != nil {
+			return fmt.Errorf("service-groups.%s.acl.egress: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CheckACLReference reports whether an ACL name a service group uses
+// is defined under access-lists. An empty name attaches nothing.
+func (c *Config) CheckACLReference(name string) error {
+	if name == "" {
+		return nil
+	}
+	if c.AccessLists[name] == nil {
+		return fmt.Errorf("access-list %q is not defined", name)
+	}
+	return nil
+}
+
+// ACLReferences returns what uses the named ACL: the service groups
+// that attach it, directly or from a schedule, and the QoS classes and
+// steering policies that match on it.
+func (c *Config) ACLReferences(name string) []string {
+	var out []string
+	for sgName, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		if sg.ACL != nil && (sg.ACL.Ingress == name || sg.ACL.Egress == name) {
+			out = append(out, "service-groups."+sgName)
+		}
+		for _, s := range sg.Schedules {
+			if s.ACL != nil && (s.ACL.Ingress == name || s.ACL.Egress == name) {
+				out = append(out, "service-groups."+sgName+".schedules."+s.Schedule)
+			}
+		}
+	}
+	for policyName, policy := range c.QoSPolicies {
+		if policy == nil {
+			continue
+		}
+		for _, class := range policy.Classes {
+			if class.Match.AccessList == name {
+				out = append(out, "qos-policies."+policyName+".classes."+class.Name)
+			}
+		}
+	}
+	for policyName, policy := range c.SteeringPolicies {
+		if policy != nil && policy.AccessList == name {
+			out = append(out, "steering-policies."+policyName)
+		}
+	}
+	return out
+}
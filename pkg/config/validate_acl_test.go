@@ -0,0 +1,50 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateAccessLists(t *testing.T) {
+	lists := map[string]*acl.AccessList{
+		"web": {Rules: []acl.Rule{{Action: acl.ActionPermit, Protocol: "tcp", DestinationPort: "443"}}},
+	}
+	cases := []struct {
+		name    string
+		ingress string
+		egress  string
+		lists   map[string]*acl.AccessList
+		want    string
+	}{
+		{"defined", "web", "", lists, ""},
+		{"none attached", "", "", lists, ""},
+		{"undefined ingress", "nope", "", lists, "acl.ingress: access-list \"nope\" is not defined"},
+		{"undefined egress", "web", "nope", lists, "acl.egress"},
+		{"bad rule", "", "", map[string]*acl.AccessList{"x": {Rules: []acl.Rule{{Action: "drop"}}}}, "access-lists.x.rules[0]"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			AccessLists: tc.lists,
+			ServiceGroups: map[string]*servicegroup.Config{
+				"sg": {ACL: &servicegroup.ACLConfig{Ingress: tc.ingress, Egress: tc.egress}},
+			},
+		}
+		err := cfg.validateAccessLists()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
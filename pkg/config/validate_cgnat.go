@@ -0,0 +1,102 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+// cgnatPoolFamily maps a pool mode onto the service-group mode that
+// selects it.
+func cgnatPoolFamily(mode string) string {
+	switch mode {
+	case cgnat.ModeNAT64:
+		return servicegroup.CGNATModeNAT64
+	case cgnat.ModeDSLite:
+		return servicegroup.CGNATModeDSLite
+	default:
+		return servicegroup.CGNATModeNAT44
+	}
+}
+
+// validateServiceGroupCGNAT checks that a service group's cgnat.mode
+// names a known translation and agrees with the pool its policy selects.
+func (c *Config) validateServiceGroupCGNAT() error {
+	for name, sg := range c.ServiceGroups {
+		if sg == nil || sg.CGNAT == nil {
+			continue
+		}
+		if err := c.validateServiceGroupMAP(name, sg.CGNAT); err != nil {
+			return err
+		}
+		mode := sg.CGNAT.Mode
+		if mode == "" || mode == servicegroup.CGNATModeMAP {
+			continue
+		}
+		switch mode {
+		case servicegroup.CGNATModeNAT44, servicegroup.CGNATModeNAT64, servicegroup.CGNATModeDSLite:
+		default:
+			return fmt.Errorf("service-groups.%s.cgnat.mode: %q is not nat44, nat64, dslite or map", name, mode)
+		}
+		if sg.CGNAT.Bypass || sg.CGNAT.Policy == "" || c.CGNAT == nil {
+			continue
+		}
+		pool := c.CGNAT.Pools[sg.CGNAT.Policy]
+		if pool == nil {
+			continue
+		}
+		if family := cgnatPoolFamily(pool.GetMode()); family != mode {
+			return fmt.Errorf("service-groups.%s.cgnat: mode %s but policy %q is a %s pool", name, mode, sg.CGNAT.Policy, pool.GetMode())
+		}
+	}
+	return nil
+}
+
+// validateServiceGroupMAP checks that map-domain and mode map come
+// together and name a configured MAP domain.
+func (c *Config) validateServiceGroupMAP(name string, sg *servicegroup.CGNATConfig) error {
+	isMAP := sg.Mode == servicegroup.CGNATModeMAP
+	if sg.MAPDomain == "" {
+		if isMAP {
+			return fmt.Errorf("service-groups.%s.cgnat: mode map requires map-domain", name)
+		}
+		return nil
+	}
+	if sg.Mode != "" && !isMAP {
+		return fmt.Errorf("service-groups.%s.cgnat: map-domain is only valid with mode map", name)
+	}
+	if sg.Policy != "" {
+		return fmt.Errorf("service-groups.%s.cgnat: map-domain and policy are mutually exclusive", name)
+	}
+	if c.CGNAT == nil || c.CGNAT.MAP == nil || c.CGNAT.MAP.Domains[sg.MAPDomain] == nil {
+		return fmt.Errorf("service-groups.%s.cgnat.map-domain: %q is not a configured cgnat.map domain", name, sg.MAPDomain)
+	}
+	return nil
+}
+
+// validateCGNATBypassEscalation checks that each pool escalating
+// subscribers to bypass names a service group that bypasses CGNAT.
+func (c *Config) validateCGNATBypassEscalation() error {
+	if c.CGNAT == nil {
+		return nil
+	}
+	for name, pool := range c.CGNAT.Pools {
+		if pool == nil || pool.SubscriberLimits.GetEscalate() != cgnat.EscalateBypass {
+			continue
+		}
+		sgName := pool.SubscriberLimits.BypassServiceGroup
+		sg := c.ServiceGroups[sgName]
+		if sg == nil {
+			return fmt.Errorf("cgnat.pools.%s.subscriber-limits.bypass-service-group: %q is not a configured service group", name, sgName)
+		}
+		if sg.CGNAT == nil || !sg.CGNAT.Bypass {
+			return fmt.Errorf("cgnat.pools.%s.subscriber-limits.bypass-service-group: service group %q does not set cgnat.bypass", name, sgName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateServiceGroupCGNAT_Mode(t *testing.T) {
+	pools := map[string]*cgnat.Pool{
+		"res":  {Mode: "pba"},
+		"v6":   {Mode: cgnat.ModeNAT64},
+		"soft": {Mode: cgnat.ModeDSLite},
+	}
+	cases := []struct {
+		name   string
+		mode   string
+		policy string
+		want   string
+	}{
+		{"unset", "", "v6", ""},
+		{"nat64 matches", servicegroup.CGNATModeNAT64, "v6", ""},
+		{"nat44 matches", servicegroup.CGNATModeNAT44, "res", ""},
+		{"dslite matches", servicegroup.CGNATModeDSLite, "soft", ""},
+		{"unknown mode", "nat66", "v6", "is not nat44, nat64, dslite or map"},
+		{"nat64 on nat44 pool", servicegroup.CGNATModeNAT64, "res", "mode nat64 but policy"},
+		{"nat44 on nat64 pool", servicegroup.CGNATModeNAT44, "v6", "mode nat44 but policy"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			CGNAT: &cgnat.Config{Pools: pools},
+			ServiceGroups: map[string]*servicegroup.Config{
+				"sg": {CGNAT: &servicegroup.CGNATConfig{Policy: tc.policy, Mode: tc.mode}},
+			},
+		}
+		err := cfg.validateServiceGroupCGNAT()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateServiceGroupCGNAT_MAPDomain(t *testing.T) {
+	cgn := &cgnat.Config{
+		Pools: map[string]*cgnat.Pool{"res": {Mode: "pba"}},
+		MAP: &cgnat.MAPConfig{Mode: cgnat.MAPModeT, Domains: map[string]*cgnat.MAPDomain{
+			"east": {IPv6Prefix: "2001:db8::/40", IPv4Prefix: "192.0.2.0/24", EABitsLength: 16},
+		}},
+	}
+	cases := []struct {
+		name string
+		sg   *servicegroup.CGNATConfig
+		want string
+	}{
+		{"map domain", &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeMAP, MAPDomain: "east"}, ""},
+		{"map without domain", &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeMAP}, "requires map-domain"},
+		{"domain on nat44", &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeNAT44, MAPDomain: "east"}, "only valid with mode map"},
+		{"domain with policy", &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeMAP, MAPDomain: "east", Policy: "res"}, "mutually exclusive"},
+		{"unknown domain", &servicegroup.CGNATConfig{Mode: servicegroup.CGNATModeMAP, MAPDomain: "west"}, "not a configured cgnat.map domain"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{CGNAT: cgn, ServiceGroups: map[string]*servicegroup.Config{"sg": {CGNAT: tc.sg}}}
+		err := cfg.validateServiceGroupCGNAT()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateCGNATBypassEscalation(t *testing.T) {
+	limits := &cgnat.SubscriberLimitsConfig{Escalate: cgnat.EscalateBypass, BypassServiceGroup: "heavy"}
+	cases := []struct {
+		name  string
+		group *servicegroup.Config
+		want  string
+	}{
+		{"bypass group", &servicegroup.Config{CGNAT: &servicegroup.CGNATConfig{Bypass: true}}, ""},
+		{"missing group", nil, "is not a configured service group"},
+		{"group without bypass", &servicegroup.Config{CGNAT: &servicegroup.CGNATConfig{Policy: "res"}}, "does not set cgnat.bypass"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			CGNAT:         &cgnat.Config{Pools: map[string]*cgnat.Pool{"res": {Mode: "pba", SubscriberLimits: limits}}},
+			ServiceGroups: map[string]*servicegroup.Config{},
+		}
+		if tc.group != nil {
+			cfg.ServiceGroups["heavy"] = tc.group
+		}
+		err := cfg.validateCGNATBypassEscalation()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
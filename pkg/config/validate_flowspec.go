@@ -0,0 +1,59 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+)
+
+// validateFlowSpec checks the FlowSpec address families: the rule limit,
+// the interfaces the rules are programmed on and the neighbors they are
+// received from.
+func (c *Config) validateFlowSpec() error {
+	bgp := c.Protocols.BGP
+	if bgp == nil {
+		return nil
+	}
+	for _, f := range []struct {
+		name string
+		af   *protocols.BGPFlowSpecAddressFamily
+	}{
+		{"ipv4-flowspec", bgp.IPv4FlowSpec},
+		{"ipv6-flowspec", bgp.IPv6FlowSpec},
+	} {
+		if f.af == nil {
+			continue
+		}
+		path := "protocols.bgp." + f.name
+		if f.af.MaxRules < 0 || f.af.MaxRules > protocols.MaxFlowSpecRules {
+			return fmt.Errorf("%s.max-rules: %d is not between 0 and %d", path, f.af.MaxRules, protocols.MaxFlowSpecRules)
+		}
+		if len(f.af.Interfaces) == 0 {
+			return fmt.Errorf("%s.interfaces: at least one interface is required", path)
+		}
+		seen := make(map[string]bool, len(f.af.Interfaces))
+		for _, name := range f.af.Interfaces {
+			if seen[name] {
+				return fmt.Errorf("%s.interfaces: interface %q is listed twice", path, name)
+			}
+			seen[name] = true
+			if _, ok := c.lookupInterfaceVRF(name); !ok {
+				return fmt.Errorf("%s.interfaces: interface %q is not declared in interfaces:", path, name)
+			}
+		}
+		for name := range f.af.Neighbors {
+			if _, ok := bgp.Neighbors[name]; ok {
+				continue
+			}
+			if _, ok := bgp.PeerGroups[name]; ok {
+				continue
+			}
+			return fmt.Errorf("%s.neighbors: %q is not a neighbor or peer-group", path, name)
+		}
+	}
+	return nil
+}
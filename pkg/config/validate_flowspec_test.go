@@ -0,0 +1,70 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
+	"github.com/veesix-networks/osvbng/pkg/config/protocols"
+)
+
+func TestValidateFlowSpec(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			Interfaces: map[string]*interfaces.InterfaceConfig{
+				"eth1": {Name: "eth1", Subinterfaces: interfaces.SubinterfaceMap{"100": {ID: 100, VLAN: 100}}},
+			},
+			Protocols: protocols.ProtocolConfig{BGP: &protocols.BGPConfig{
+				ASN:        65000,
+				Neighbors:  map[string]*protocols.BGPNeighbor{"10.0.0.2": {RemoteAS: 65010}},
+				PeerGroups: map[string]*protocols.BGPPeerGroup{"SCRUBBERS": {RemoteAS: 65010}},
+				IPv4FlowSpec: &protocols.BGPFlowSpecAddressFamily{
+					Neighbors:  map[string]*protocols.BGPNeighborAFIConfig{"10.0.0.2": {}, "SCRUBBERS": {}},
+					Interfaces: []string{"eth1", "eth1.100"},
+					MaxRules:   1000,
+				},
+			}},
+		}
+	}
+
+	if err := base().validateFlowSpec(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"too many rules", func(c *Config) {
+			c.Protocols.BGP.IPv4FlowSpec.MaxRules = protocols.MaxFlowSpecRules + 1
+		}, "ipv4-flowspec.max-rules"},
+		{"no interfaces", func(c *Config) {
+			c.Protocols.BGP.IPv4FlowSpec.Interfaces = nil
+		}, "at least one interface is required"},
+		{"unknown interface", func(c *Config) {
+			c.Protocols.BGP.IPv4FlowSpec.Interfaces = []string{"eth2"}
+		}, `interface "eth2" is not declared`},
+		{"duplicate interface", func(c *Config) {
+			c.Protocols.BGP.IPv4FlowSpec.Interfaces = []string{"eth1", "eth1"}
+		}, `interface "eth1" is listed twice`},
+		{"unknown neighbor", func(c *Config) {
+			c.Protocols.BGP.IPv6FlowSpec = &protocols.BGPFlowSpecAddressFamily{
+				Neighbors:  map[string]*protocols.BGPNeighborAFIConfig{"2001:db8::2": {}},
+				Interfaces: []string{"eth1"},
+			}
+		}, `ipv6-flowspec.neighbors: "2001:db8::2" is not a neighbor or peer-group`},
+	}
+	for _, tc := range cases {
+		cfg := base()
+		tc.mutate(cfg)
+		err := cfg.validateFlowSpec()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+// validateMarking checks every marking map and the marking of every
+// service group. Maps named by AAA are only resolved when a session
+// comes up.
+func (c *Config) validateMarking() error {
+	for name, m := range c.MarkingMaps {
+		if m == nil {
+			continue
+		}
+		if err := m.Validate(name); err != nil {
+			return err
+		}
+	}
+	for name, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		if err := c.CheckMarking(sg); err != nil {
+			return fmt.Errorf("service-groups.%s.marking: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CheckMarking validates a service group's marking against the rest of
+// the config: the maps must be defined and of the right kind, and a
+// remark must leave every ingress class the group attaches able to
+// mark what it matches.
+func (c *Config) CheckMarking(sg *servicegroup.Config) error {
+	m := sg.Marking
+	if m == nil {
+		return nil
+	}
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if m.EgressPCPMap != "" {
+		mm := c.MarkingMaps[m.EgressPCPMap]
+		if mm == nil {
+			return fmt.Errorf("egress-pcp-map: marking-map %q is not defined", m.EgressPCPMap)
+		}
+		if !mm.IsPCP() {
+			return fmt.Errorf("egress-pcp-map: marking-map %q maps dscp, not pcp", m.EgressPCPMap)
+		}
+	}
+
+	var table []uint8
+	switch m.Ingress {
+	case qos.MarkingBleach:
+		table = qos.BleachTable()
+	case qos.MarkingRemark:
+		mm := c.MarkingMaps[m.IngressMap]
+		if mm == nil {
+			return fmt.Errorf("ingress-map: marking-map %q is not defined", m.IngressMap)
+		}
+		if mm.IsPCP() {
+			return fmt.Errorf("ingress-map: marking-map %q maps pcp, not dscp", m.IngressMap)
+		}
+		var err error
+		if table, err = mm.Table(); err != nil {
+			return fmt.Errorf("ingress-map: marking-map %q: %w", m.IngressMap, err)
+		}
+	default:
+		return nil
+	}
+
+	policies := map[string]bool{}
+	if sg.QoS != nil && sg.QoS.IngressPolicy != "" {
+		policies[sg.QoS.IngressPolicy] = true
+	}
+	for _, s := range sg.Schedules {
+		if s.QoS != nil && s.QoS.IngressPolicy != "" {
+			policies[s.QoS.IngressPolicy] = true
+		}
+	}
+	for name := range policies {
+		p := c.QoSPolicies[name]
+		if p == nil {
+			continue
+		}
+		if _, err := qos.RemarkClasses(p.Classes, table); err != nil {
+			return fmt.Errorf("qos-policies.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// MarkingReferences returns the service groups that use the named
+// marking map.
+func (c *Config) MarkingReferences(name string) []string {
+	var out []string
+	for sgName, sg := range c.ServiceGroups {
+		if sg == nil || sg.Marking == nil {
+			continue
+		}
+		if sg.Marking.IngressMap == name || sg.Marking.EgressPCPMap == name {
+			out = append(out, "service-groups."+sgName)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateMarking(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			MarkingMaps: map[string]*qos.MarkingMap{
+				"untrusted": {DSCP: map[string]string{"ef": "be", "cs5": "be"}},
+				"access":    {PCP: map[string]uint8{"ef": 5}},
+			},
+			QoSPolicies: map[string]*qos.Policy{"up": {
+				CIR: 10000,
+				Classes: []qos.Class{
+					{Name: "voice", Match: qos.ClassMatch{DSCP: []string{"ef"}}, Police: &qos.ClassRate{CIR: 512}},
+				},
+			}},
+			ServiceGroups: map[string]*servicegroup.Config{"res": {
+				QoS:     &servicegroup.QoSConfig{IngressPolicy: "up"},
+				Marking: &qos.Marking{Ingress: qos.MarkingRemark, IngressMap: "untrusted", EgressPCPMap: "access"},
+			}},
+		}
+	}
+
+	if err := base().validateMarking(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"undefined ingress map", func(c *Config) {
+			c.ServiceGroups["res"].Marking.IngressMap = "nope"
+		}, `service-groups.res.marking: ingress-map: marking-map "nope" is not defined`},
+		{"pcp map for ingress", func(c *Config) {
+			c.ServiceGroups["res"].Marking.IngressMap = "access"
+		}, "maps pcp, not dscp"},
+		{"dscp map for egress", func(c *Config) {
+			c.ServiceGroups["res"].Marking.EgressPCPMap = "untrusted"
+		}, "maps dscp, not pcp"},
+		{"remark without map", func(c *Config) {
+			c.ServiceGroups["res"].Marking.IngressMap = ""
+		}, "needs an ingress-map"},
+		{"unknown mode", func(c *Config) {
+			c.ServiceGroups["res"].Marking.Ingress = "rewrite"
+		}, "unknown ingress"},
+		{"bad map", func(c *Config) {
+			c.MarkingMaps["untrusted"].DSCP["ef"] = "af44"
+		}, `marking-map "untrusted"`},
+		{"unmarkable class", func(c *Config) {
+			c.QoSPolicies["up"].Classes = append(c.QoSPolicies["up"].Classes,
+				qos.Class{Name: "tcp", Match: qos.ClassMatch{Protocol: "tcp"}, Police: &qos.ClassRate{CIR: 1}})
+		}, `qos-policies.up: class "tcp" matches code points`},
+	}
+	for _, tc := range cases {
+		cfg := base()
+		tc.mutate(cfg)
+		err := cfg.validateMarking()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	if refs := base().MarkingReferences("access"); len(refs) != 1 || refs[0] != "service-groups.res" {
+		t.Errorf("MarkingReferences = %v", refs)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// validateServiceGroupNPTv6 checks that a service group's nptv6
+// internal prefix is an IPv6 network VPP can translate, and that its
+// uplink is an interface of the group's VRF.
+func (c *Config) validateServiceGroupNPTv6() error {
+	for name, sg := range c.ServiceGroups {
+		if sg == nil || sg.NPTv6 == nil {
+			continue
+		}
+		if sg.NPTv6.InternalPrefix == "" {
+			return fmt.Errorf("service-groups.%s.nptv6: internal-prefix is required", name)
+		}
+		ip, ipNet, err := net.ParseCIDR(sg.NPTv6.InternalPrefix)
+		if err != nil || ip.To4() != nil {
+			return fmt.Errorf("service-groups.%s.nptv6.internal-prefix: %q is not an IPv6 prefix", name, sg.NPTv6.InternalPrefix)
+		}
+		if !ip.Equal(ipNet.IP) {
+			return fmt.Errorf("service-groups.%s.nptv6.internal-prefix: %q has host bits set", name, sg.NPTv6.InternalPrefix)
+		}
+		if ones, _ := ipNet.Mask.Size(); ones > 64 {
+			return fmt.Errorf("service-groups.%s.nptv6.internal-prefix: /%d is longer than /64", name, ones)
+		}
+		if sg.NPTv6.Uplink == "" {
+			continue
+		}
+		vrf, ok := c.lookupInterfaceVRF(sg.NPTv6.Uplink)
+		if !ok {
+			return fmt.Errorf("service-groups.%s.nptv6.uplink: interface %q is not configured", name, sg.NPTv6.Uplink)
+		}
+		if vrf != sg.VRF {
+			return fmt.Errorf("service-groups.%s.nptv6.uplink: interface %q is not in vrf %q", name, sg.NPTv6.Uplink, sg.VRF)
+		}
+	}
+	return nil
+}
+
+// NPTv6Uplinks returns the interfaces of a VRF that can carry NPTv6
+// translations when a service group names none: those with an IPv6
+// address that are neither loopbacks nor subscriber access interfaces.
+func (c *Config) NPTv6Uplinks(vrf string) []string {
+	var names []string
+	for parentName, iface := range c.Interfaces {
+		if iface == nil {
+			continue
+		}
+		if iface.VRF == vrf && !strings.HasPrefix(parentName, "loop") && iface.Address != nil && len(iface.Address.IPv6) > 0 {
+			names = append(names, parentName)
+		}
+		for subID, sub := range iface.Subinterfaces {
+			if sub == nil || sub.SubscriberAccess || sub.VRF != vrf || sub.Address == nil || len(sub.Address.IPv6) == 0 {
+				continue
+			}
+			names = append(names, fmt.Sprintf("%s.%s", parentName, subID))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/interfaces"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateServiceGroupNPTv6(t *testing.T) {
+	cases := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"ula", "fd00:1::/48", ""},
+		{"pi", "2001:db8:100::/56", ""},
+		{"missing", "", "internal-prefix is required"},
+		{"ipv4", "10.0.0.0/24", "is not an IPv6 prefix"},
+		{"garbage", "fd00::", "is not an IPv6 prefix"},
+		{"host bits", "fd00:1::1/48", "has host bits set"},
+		{"too long", "fd00:1::/96", "longer than /64"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			ServiceGroups: map[string]*servicegroup.Config{
+				"sg": {NPTv6: &servicegroup.NPTv6Config{InternalPrefix: tc.prefix}},
+			},
+		}
+		err := cfg.validateServiceGroupNPTv6()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateServiceGroupNPTv6Uplink(t *testing.T) {
+	cases := []struct {
+		name   string
+		vrf    string
+		uplink string
+		want   string
+	}{
+		{"auto", "cust", "", ""},
+		{"in vrf", "cust", "eth2.100", ""},
+		{"unknown", "cust", "eth9", "is not configured"},
+		{"other vrf", "cust", "eth1", `not in vrf "cust"`},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			Interfaces: map[string]*interfaces.InterfaceConfig{
+				"eth1": {Name: "eth1"},
+				"eth2": {Name: "eth2", Subinterfaces: interfaces.SubinterfaceMap{"100": {ID: 100, VLAN: 100, VRF: "cust"}}},
+			},
+			ServiceGroups: map[string]*servicegroup.Config{
+				"sg": {VRF: tc.vrf, NPTv6: &servicegroup.NPTv6Config{InternalPrefix: "fd00:1::/48", Uplink: tc.uplink}},
+			},
+		}
+		err := cfg.validateServiceGroupNPTv6()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestNPTv6Uplinks(t *testing.T) {
+	v6 := &interfaces.AddressConfig{IPv6: []string{"2001:db8::1/64"}}
+	cfg := &Config{Interfaces: map[string]*interfaces.InterfaceConfig{
+		"loop0": {Name: "loop0", Address: v6},
+		"eth1":  {Name: "eth1", Address: v6},
+		"eth3":  {Name: "eth3", Address: &interfaces.AddressConfig{IPv4: []string{"192.0.2.1/24"}}},
+		"eth2": {Name: "eth2", Subinterfaces: interfaces.SubinterfaceMap{
+			"100": {ID: 100, VLAN: 100, VRF: "cust", Address: v6},
+			"200": {ID: 200, VLAN: 200, SubscriberAccess: true, Address: v6},
+		}},
+	}}
+	if got := cfg.NPTv6Uplinks(""); len(got) != 1 || got[0] != "eth1" {
+		t.Fatalf("default vrf uplinks = %v", got)
+	}
+	if got := cfg.NPTv6Uplinks("cust"); len(got) != 1 || got[0] != "eth2.100" {
+		t.Fatalf("cust uplinks = %v", got)
+	}
+}
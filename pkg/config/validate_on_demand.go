@@ -0,0 +1,82 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// validateOnDemandPools checks the on-demand blocks of each profile.
+// Chunks only ever reach subscribers through the local allocator, so
+// relay and proxy modes are rejected, and an IPv4 chunk has no gateway
+// of its own, so DHCP must hand out addresses unnumbered.
+func (c *Config) validateOnDemandPools() error {
+	for profileName, profile := range c.IPv4Profiles {
+		if profile == nil || profile.OnDemand == nil {
+			continue
+		}
+		if err := profile.OnDemand.Validate(32, false); err != nil {
+			return fmt.Errorf("ipv4-profiles.%s.on-demand: %w", profileName, err)
+		}
+		if profile.DHCP == nil {
+			continue
+		}
+		if mode := profile.GetMode(); mode != "server" {
+			return fmt.Errorf("ipv4-profiles.%s.on-demand: not supported with dhcp mode %q", profileName, mode)
+		}
+		if model := profile.GetAddressModel(); model != "unnumbered-ptp" {
+			return fmt.Errorf("ipv4-profiles.%s.on-demand: requires dhcp address-model unnumbered-ptp, not %q", profileName, model)
+		}
+	}
+
+	for profileName, profile := range c.IPv6Profiles {
+		if profile == nil || (profile.IANAOnDemand == nil && profile.PDOnDemand == nil) {
+			continue
+		}
+		if err := profile.IANAOnDemand.Validate(128, false); err != nil {
+			return fmt.Errorf("ipv6-profiles.%s.iana-on-demand: %w", profileName, err)
+		}
+		if err := profile.PDOnDemand.Validate(128, true); err != nil {
+			return fmt.Errorf("ipv6-profiles.%s.pd-on-demand: %w", profileName, err)
+		}
+		if profile.DHCPv6 != nil {
+			if mode := profile.GetMode(); mode != "server" {
+				return fmt.Errorf("ipv6-profiles.%s: on-demand pools are not supported with dhcpv6 mode %q", profileName, mode)
+			}
+		}
+	}
+
+	return nil
+}
+
+// OnDemandProviders returns the IPAM providers the on-demand blocks
+// use, sorted, or nil when no profile has one.
+func (c *Config) OnDemandProviders() []string {
+	seen := make(map[string]bool)
+	for _, profile := range c.IPv4Profiles {
+		if profile != nil && profile.OnDemand != nil {
+			seen[profile.OnDemand.GetProvider()] = true
+		}
+	}
+	for _, profile := range c.IPv6Profiles {
+		if profile == nil {
+			continue
+		}
+		if profile.IANAOnDemand != nil {
+			seen[profile.IANAOnDemand.GetProvider()] = true
+		}
+		if profile.PDOnDemand != nil {
+			seen[profile.PDOnDemand.GetProvider()] = true
+		}
+	}
+
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+func TestValidateOnDemandPools(t *testing.T) {
+	v4 := []struct {
+		name    string
+		profile *ip.IPv4Profile
+		want    string
+	}{
+		{"pppoe only", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{ChunkLength: 24}}, ""},
+		{"unnumbered", &ip.IPv4Profile{
+			DHCP:     &ip.IPv4DHCPOptions{AddressModel: "unnumbered-ptp"},
+			OnDemand: &ip.OnDemandPools{ChunkLength: 24},
+		}, ""},
+		{"connected subnet", &ip.IPv4Profile{
+			DHCP:     &ip.IPv4DHCPOptions{},
+			OnDemand: &ip.OnDemandPools{ChunkLength: 24},
+		}, "requires dhcp address-model unnumbered-ptp"},
+		{"relay", &ip.IPv4Profile{
+			DHCP:     &ip.IPv4DHCPOptions{Mode: "relay", AddressModel: "unnumbered-ptp"},
+			OnDemand: &ip.OnDemandPools{ChunkLength: 24},
+		}, "not supported with dhcp mode"},
+		{"no chunk length", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{}}, "chunk-length 0"},
+		{"chunk too large", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{ChunkLength: 12}}, "must be /16 or longer"},
+		{"chunk too long", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{ChunkLength: 31}}, "chunk-length 31"},
+		{"prefix length", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{ChunkLength: 24, PrefixLength: 28}}, "only applies to PD"},
+		{"threshold", &ip.IPv4Profile{OnDemand: &ip.OnDemandPools{ChunkLength: 24, Threshold: 101}}, "must be a percentage"},
+	}
+	for _, tc := range v4 {
+		cfg := &Config{IPv4Profiles: map[string]*ip.IPv4Profile{"p": tc.profile}}
+		err := cfg.validateOnDemandPools()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	v6 := []struct {
+		name string
+		pd   *ip.OnDemandPools
+		want string
+	}{
+		{"pd", &ip.OnDemandPools{ChunkLength: 48, PrefixLength: 56}, ""},
+		{"pd without prefix length", &ip.OnDemandPools{ChunkLength: 48}, "prefix-length 0"},
+		{"pd prefix length too long", &ip.OnDemandPools{ChunkLength: 48, PrefixLength: 72}, "at most 64"},
+		{"pd chunk too large", &ip.OnDemandPools{ChunkLength: 32, PrefixLength: 56}, "must be /40 or longer"},
+	}
+	for _, tc := range v6 {
+		cfg := &Config{IPv6Profiles: map[string]*ip.IPv6Profile{"p": {PDOnDemand: tc.pd}}}
+		err := cfg.validateOnDemandPools()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	od := &ip.OnDemandPools{}
+	if od.GetProvider() != "http" || od.GetThreshold() != ip.DefaultPoolHighWatermark ||
+		od.GetMaxChunks() != ip.DefaultOnDemandMaxChunks || od.GetReleaseGrace() != ip.DefaultOnDemandReleaseGrace {
+		t.Fatalf("defaults = %q %d %d %s", od.GetProvider(), od.GetThreshold(), od.GetMaxChunks(), od.GetReleaseGrace())
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+func (c *Config) validatePoolThresholds() error {
+	for profileName, profile := range c.IPv4Profiles {
+		if profile == nil {
+			continue
+		}
+		vrfs := make(map[string]string, len(profile.Pools))
+		for _, pool := range profile.Pools {
+			vrfs[pool.Name] = pool.VRF
+		}
+		for i, pool := range profile.Pools {
+			if err := validateThresholds(pool.Name, pool.VRF, pool.Thresholds, vrfs, true); err != nil {
+				return fmt.Errorf("ipv4-profiles.%s.pools[%d].thresholds: %w", profileName, i, err)
+			}
+		}
+	}
+
+	for profileName, profile := range c.IPv6Profiles {
+		if profile == nil {
+			continue
+		}
+		iana := make(map[string]string, len(profile.IANAPools))
+		for _, pool := range profile.IANAPools {
+			iana[pool.Name] = pool.VRF
+		}
+		for i, pool := range profile.IANAPools {
+			if err := validateThresholds(pool.Name, pool.VRF, pool.Thresholds, iana, false); err != nil {
+				return fmt.Errorf("ipv6-profiles.%s.iana-pools[%d].thresholds: %w", profileName, i, err)
+			}
+		}
+		pd := make(map[string]string, len(profile.PDPools))
+		for _, pool := range profile.PDPools {
+			pd[pool.Name] = pool.VRF
+		}
+		for i, pool := range profile.PDPools {
+			if err := validateThresholds(pool.Name, pool.VRF, pool.Thresholds, pd, false); err != nil {
+				return fmt.Errorf("ipv6-profiles.%s.pd-pools[%d].thresholds: %w", profileName, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateThresholds checks one pool's watermarks. The overflow pool
+// must be another pool of the same profile, family and VRF; only IPv4
+// pools are advertised into BGP, so only they can withdraw their route.
+func validateThresholds(name, vrf string, t *ip.PoolThresholds, siblings map[string]string, advertised bool) error {
+	if t == nil {
+		return nil
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if t.OverflowPool != "" {
+		if t.OverflowPool == name {
+			return fmt.Errorf("overflow-pool %q is the pool itself", t.OverflowPool)
+		}
+		overflowVRF, ok := siblings[t.OverflowPool]
+		if !ok {
+			return fmt.Errorf("overflow-pool %q is not a pool of this profile", t.OverflowPool)
+		}
+		if overflowVRF != vrf {
+			return fmt.Errorf("overflow-pool %q is in VRF %q, not %q", t.OverflowPool, overflowVRF, vrf)
+		}
+	}
+	if t.WithdrawRoute && !advertised {
+		return fmt.Errorf("withdraw-route is only supported on IPv4 pools")
+	}
+	return nil
+}
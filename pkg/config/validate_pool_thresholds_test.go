@@ -0,0 +1,59 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+)
+
+func TestValidatePoolThresholds(t *testing.T) {
+	cases := []struct {
+		name string
+		th   *ip.PoolThresholds
+		vrf  string
+		want string
+	}{
+		{"defaults", &ip.PoolThresholds{}, "", ""},
+		{"overflow and withdraw", &ip.PoolThresholds{High: 95, OverflowPool: "b", WithdrawRoute: true}, "", ""},
+		{"over 100", &ip.PoolThresholds{High: 120}, "", "must be a percentage"},
+		{"low above high", &ip.PoolThresholds{High: 50, Low: 60}, "", "must be below high"},
+		{"overflow self", &ip.PoolThresholds{OverflowPool: "a"}, "", "is the pool itself"},
+		{"overflow unknown", &ip.PoolThresholds{OverflowPool: "z"}, "", "is not a pool of this profile"},
+		{"overflow other vrf", &ip.PoolThresholds{OverflowPool: "b"}, "blue", "is in VRF"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{IPv4Profiles: map[string]*ip.IPv4Profile{
+			"p": {Pools: []ip.IPv4Pool{
+				{Name: "a", VRF: tc.vrf, Thresholds: tc.th},
+				{Name: "b"},
+			}},
+		}}
+		err := cfg.validatePoolThresholds()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	cfg := &Config{IPv6Profiles: map[string]*ip.IPv6Profile{
+		"p": {PDPools: []ip.PDPool{{Name: "pd", Thresholds: &ip.PoolThresholds{WithdrawRoute: true}}}},
+	}}
+	if err := cfg.validatePoolThresholds(); err == nil || !strings.Contains(err.Error(), "only supported on IPv4 pools") {
+		t.Fatalf("PD withdraw-route: %v", err)
+	}
+
+	th := &ip.PoolThresholds{}
+	if th.GetHigh() != ip.DefaultPoolHighWatermark || th.GetLow() != ip.DefaultPoolHighWatermark-10 {
+		t.Fatalf("defaults = %d/%d", th.GetHigh(), th.GetLow())
+	}
+}
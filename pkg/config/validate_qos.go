@@ -0,0 +1,70 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+)
+
+// validateQoSPolicies checks the classes of every policy a service group
+// attaches, including the policies its schedules switch to. What a class
+// may match on depends on the direction it is attached in, so classes
+// are checked per binding rather than per policy. The group's line-rate
+// settings are checked alongside.
+func (c *Config) validateQoSPolicies() error {
+	for name, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		type binding struct {
+			policy, direction, field string
+		}
+		var bindings []binding
+		if sg.QoS != nil {
+			if err := sg.QoS.LineRate.Validate(); err != nil {
+				return fmt.Errorf("service-groups.%s.qos.line-rate: %w", name, err)
+			}
+			bindings = append(bindings,
+				binding{sg.QoS.IngressPolicy, qos.DirectionIngress, "qos.ingress-policy"},
+				binding{sg.QoS.EgressPolicy, qos.DirectionEgress, "qos.egress-policy"})
+		}
+		for i, s := range sg.Schedules {
+			if s.QoS == nil {
+				continue
+			}
+			if err := s.QoS.LineRate.Validate(); err != nil {
+				return fmt.Errorf("service-groups.%s.schedules[%d].qos.line-rate: %w", name, i, err)
+			}
+			bindings = append(bindings,
+				binding{s.QoS.IngressPolicy, qos.DirectionIngress, fmt.Sprintf("schedules[%d].qos.ingress-policy", i)},
+				binding{s.QoS.EgressPolicy, qos.DirectionEgress, fmt.Sprintf("schedules[%d].qos.egress-policy", i)})
+		}
+		for _, b := range bindings {
+			if b.policy == "" {
+				continue
+			}
+			policy := c.QoSPolicies[b.policy]
+			if policy == nil {
+				continue
+			}
+			if err := policy.ValidateClasses(b.direction, c.AccessLists); err != nil {
+				return fmt.Errorf("service-groups.%s.%s: qos-policies.%s: %w", name, b.field, b.policy, err)
+			}
+			for _, s := range policy.Schedules {
+				alt := c.QoSPolicies[s.Policy]
+				if alt == nil {
+					continue
+				}
+				if err := alt.ValidateClasses(b.direction, c.AccessLists); err != nil {
+					return fmt.Errorf("service-groups.%s.%s: qos-policies.%s: schedule %q: qos-policies.%s: %w",
+						name, b.field, b.policy, s.Schedule, s.Policy, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateQoSPolicies(t *testing.T) {
+	// The same policy is valid upstream and not downstream: egress
+	// classes are scheduler tins and cannot police.
+	policies := map[string]*qos.Policy{
+		"voice": {CIR: 10000, Classes: []qos.Class{
+			{Name: "voice", Match: qos.ClassMatch{DSCP: []string{"ef"}}, Police: &qos.ClassRate{CIR: 256}},
+		}},
+	}
+	cases := []struct {
+		name    string
+		ingress string
+		egress  string
+		want    string
+	}{
+		{"ingress", "voice", "", ""},
+		{"undefined policy", "nope", "", ""},
+		{"egress", "", "voice", "service-groups.sg.qos.egress-policy: qos-policies.voice"},
+	}
+	for _, tc := range cases {
+		cfg := &Config{
+			QoSPolicies: policies,
+			ServiceGroups: map[string]*servicegroup.Config{
+				"sg": {QoS: &servicegroup.QoSConfig{IngressPolicy: tc.ingress, EgressPolicy: tc.egress}},
+			},
+		}
+		err := cfg.validateQoSPolicies()
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateQoSLineRate(t *testing.T) {
+	for _, tc := range []struct {
+		factor float64
+		ok     bool
+	}{{0, true}, {0.9, true}, {1, true}, {1.2, false}, {-0.5, false}} {
+		cfg := &Config{ServiceGroups: map[string]*servicegroup.Config{
+			"sg": {QoS: &servicegroup.QoSConfig{LineRate: &servicegroup.LineRateConfig{Enabled: true, OverheadFactor: tc.factor}}},
+		}}
+		err := cfg.validateQoSPolicies()
+		if tc.ok != (err == nil) {
+			t.Errorf("factor %g: err = %v", tc.factor, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "service-groups.sg.qos.line-rate") {
+			t.Errorf("factor %g: err = %v", tc.factor, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import "fmt"
+
+// validateRTBH checks the rtbh block and that a configured route-policy
+// is defined.
+func (c *Config) validateRTBH() error {
+	if c.RTBH == nil {
+		return nil
+	}
+	if err := c.RTBH.Validate(); err != nil {
+		return err
+	}
+	if name := c.RTBH.RoutePolicy; name != "" {
+		if c.RoutingPolicies == nil {
+			return fmt.Errorf("rtbh: route-policy %q is not defined", name)
+		}
+		if _, ok := c.RoutingPolicies.RoutePolicies[name]; !ok {
+			return fmt.Errorf("rtbh: route-policy %q is not defined", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+)
+
+// validateSchedules checks every schedule and everything that switches
+// on one. An override naming a policy or schedule that does not exist
+// would only show up at the boundary, as sessions silently keeping
+// their daytime bindings, so references are checked up front.
+func (c *Config) validateSchedules() error {
+	for name, s := range c.Schedules {
+		if s == nil {
+			continue
+		}
+		if err := s.Validate(name); err != nil {
+			return err
+		}
+	}
+
+	for name, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		for i, s := range sg.Schedules {
+			path := fmt.Sprintf("service-groups.%s.schedules[%d]", name, i)
+			if err := c.CheckScheduleReference(s.Schedule); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if s.ACL == nil && s.QoS == nil {
+				return fmt.Errorf("%s: an acl or qos override is required", path)
+			}
+			if s.ACL != nil {
+				if err := c.CheckACLReference(s.ACL.Ingress); err != nil {
+					return fmt.Errorf("%s.acl.ingress: %w", path, err)
+				}
+				if err := c.CheckACLReference(s.ACL.Egress); err != nil {
+					return fmt.Errorf("%s.acl.egress: %w", path, err)
+				}
+			}
+			if s.QoS != nil {
+				for _, policy := range []string{s.QoS.IngressPolicy, s.QoS.EgressPolicy} {
+					if policy != "" && c.QoSPolicies[policy] == nil {
+						return fmt.Errorf("%s.qos: qos-policy %q is not defined", path, policy)
+					}
+				}
+			}
+		}
+	}
+
+	for name, policy := range c.QoSPolicies {
+		if policy == nil {
+			continue
+		}
+		for i, s := range policy.Schedules {
+			path := fmt.Sprintf("qos-policies.%s.schedules[%d]", name, i)
+			if err := c.CheckScheduleReference(s.Schedule); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if s.Policy == "" {
+				return fmt.Errorf("%s: policy is required", path)
+			}
+			if s.Policy == name {
+				return fmt.Errorf("%s: policy cannot be the policy itself", path)
+			}
+			if c.QoSPolicies[s.Policy] == nil {
+				return fmt.Errorf("%s: qos-policy %q is not defined", path, s.Policy)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckScheduleReference reports whether a schedule name is defined
+// under schedules.
+func (c *Config) CheckScheduleReference(name string) error {
+	if name == "" {
+		return fmt.Errorf("schedule is required")
+	}
+	if c.Schedules[name] == nil {
+		return fmt.Errorf("schedule %q is not defined", name)
+	}
+	return nil
+}
+
+// ScheduleReferences returns the service groups and QoS policies that
+// switch on the named schedule.
+func (c *Config) ScheduleReferences(name string) []string {
+	var out []string
+	for sgName, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		for _, s := range sg.Schedules {
+			if s.Schedule == name {
+				out = append(out, "service-groups."+sgName)
+				break
+			}
+		}
+	}
+	for policyName, policy := range c.QoSPolicies {
+		if policy == nil {
+			continue
+		}
+		for _, s := range policy.Schedules {
+			if s.Schedule == name {
+				out = append(out, "qos-policies."+policyName)
+				break
+			}
+		}
+	}
+	return out
+}
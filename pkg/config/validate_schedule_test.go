@@ -0,0 +1,79 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+)
+
+func TestValidateSchedules(t *testing.T) {
+	night := &schedule.Schedule{Windows: []schedule.Window{{Start: "00:00", End: "06:00"}}}
+	base := func() *Config {
+		return &Config{
+			Schedules:   map[string]*schedule.Schedule{"night": night},
+			AccessLists: map[string]*acl.AccessList{"open": {Rules: []acl.Rule{{Action: acl.ActionPermit}}}},
+			QoSPolicies: map[string]*qos.Policy{
+				"plan-100m": {CIR: 100_000, Schedules: []qos.PolicySchedule{{Schedule: "night", Policy: "plan-1g"}}},
+				"plan-1g":   {CIR: 1_000_000},
+			},
+			ServiceGroups: map[string]*servicegroup.Config{
+				"res": {Schedules: []servicegroup.ScheduleConfig{{
+					Schedule: "night",
+					ACL:      &servicegroup.ACLConfig{Ingress: "open"},
+					QoS:      &servicegroup.QoSConfig{EgressPolicy: "plan-1g"},
+				}}},
+			},
+		}
+	}
+
+	if err := base().validateSchedules(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"undefined schedule", func(c *Config) {
+			c.ServiceGroups["res"].Schedules[0].Schedule = "weekend"
+		}, `service-groups.res.schedules[0]: schedule "weekend" is not defined`},
+		{"empty override", func(c *Config) {
+			c.ServiceGroups["res"].Schedules[0].ACL = nil
+			c.ServiceGroups["res"].Schedules[0].QoS = nil
+		}, "an acl or qos override is required"},
+		{"undefined acl", func(c *Config) {
+			c.ServiceGroups["res"].Schedules[0].ACL.Egress = "nope"
+		}, "schedules[0].acl.egress"},
+		{"undefined service group policy", func(c *Config) {
+			c.ServiceGroups["res"].Schedules[0].QoS.EgressPolicy = "nope"
+		}, `qos-policy "nope" is not defined`},
+		{"policy schedules itself", func(c *Config) {
+			c.QoSPolicies["plan-100m"].Schedules[0].Policy = "plan-100m"
+		}, "cannot be the policy itself"},
+		{"bad window", func(c *Config) {
+			c.Schedules["night"] = &schedule.Schedule{Windows: []schedule.Window{{Start: "noon", End: "06:00"}}}
+		}, `schedule "night": windows[0]: start`},
+	}
+	for _, tc := range cases {
+		cfg := base()
+		tc.mutate(cfg)
+		err := cfg.validateSchedules()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	refs := base().ScheduleReferences("night")
+	if len(refs) != 2 {
+		t.Fatalf("ScheduleReferences = %v, want the service group and the policy", refs)
+	}
+}
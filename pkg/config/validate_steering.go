@@ -0,0 +1,81 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+)
+
+// validateSteeringPolicies checks every steering policy and that the
+// policies service groups select are defined. Names returned by AAA are
+// only resolved when a session comes up.
+func (c *Config) validateSteeringPolicies() error {
+	for name, p := range c.SteeringPolicies {
+		if p == nil {
+			continue
+		}
+		if err := c.CheckSteeringPolicy(name, p); err != nil {
+			return err
+		}
+	}
+	for name, sg := range c.ServiceGroups {
+		if sg == nil {
+			continue
+		}
+		if err := c.CheckSteeringReference(sg.Steering); err != nil {
+			return fmt.Errorf("service-groups.%s.steering-policy: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CheckSteeringPolicy validates the named policy against the rest of
+// the config: its access list must be defined, and the VRF of each next
+// hop must exist and carry the next hop's address family.
+func (c *Config) CheckSteeringPolicy(name string, p *steering.Policy) error {
+	if err := p.Validate(name); err != nil {
+		return err
+	}
+	if err := c.CheckACLReference(p.AccessList); err != nil {
+		return fmt.Errorf("steering-policy %q: %w", name, err)
+	}
+	for i, hop := range p.NextHops {
+		family := netbind.FamilyV4
+		if hop.IP().To4() == nil {
+			family = netbind.FamilyV6
+		}
+		if err := (netbind.Binding{VRF: hop.VRF}).Validate(family, c.VRFLookup()); err != nil {
+			return fmt.Errorf("steering-policy %q: next-hops[%d]: %w", name, i, err)
+		}
+	}
+	return nil
+}
+
+// CheckSteeringReference reports whether a steering policy a service
+// group selects is defined. An empty name selects none.
+func (c *Config) CheckSteeringReference(name string) error {
+	if name == "" {
+		return nil
+	}
+	if c.SteeringPolicies[name] == nil {
+		return fmt.Errorf("steering-policy %q is not defined", name)
+	}
+	return nil
+}
+
+// SteeringReferences returns the service groups that select the named
+// steering policy.
+func (c *Config) SteeringReferences(name string) []string {
+	var out []string
+	for sgName, sg := range c.ServiceGroups {
+		if sg != nil && sg.Steering == name {
+			out = append(out, "service-groups."+sgName)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/ip"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/config/steering"
+)
+
+func TestValidateSteeringPolicies(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			VRFS: map[string]*ip.VRFSConfig{"services": {AddressFamilies: ip.VRFAddressFamilyConfig{
+				IPv4Unicast: &ip.VRFAFConfig{Enabled: true},
+			}}},
+			AccessLists: map[string]*acl.AccessList{"web": {Rules: []acl.Rule{{Action: acl.ActionPermit}}}},
+			SteeringPolicies: map[string]*steering.Policy{"dpi": {
+				AccessList: "web",
+				NextHops:   []steering.NextHop{{Address: "192.0.2.1", VRF: "services"}},
+			}},
+			ServiceGroups: map[string]*servicegroup.Config{"res": {Steering: "dpi"}},
+		}
+	}
+
+	if err := base().validateSteeringPolicies(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"undefined policy", func(c *Config) {
+			c.ServiceGroups["res"].Steering = "nope"
+		}, `service-groups.res.steering-policy: steering-policy "nope" is not defined`},
+		{"undefined acl", func(c *Config) {
+			c.SteeringPolicies["dpi"].AccessList = "nope"
+		}, `access-list "nope" is not defined`},
+		{"undefined vrf", func(c *Config) {
+			c.SteeringPolicies["dpi"].NextHops[0].VRF = "nope"
+		}, `next-hops[0]: netbind: vrf "nope" not declared`},
+		{"vrf without family", func(c *Config) {
+			c.SteeringPolicies["dpi"].NextHops[0].Address = "2001:db8::1"
+		}, "does not have ipv6-unicast enabled"},
+	}
+	for _, tc := range cases {
+		cfg := base()
+		tc.mutate(cfg)
+		err := cfg.validateSteeringPolicies()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	if refs := base().SteeringReferences("dpi"); len(refs) != 1 || refs[0] != "service-groups.res" {
+		t.Errorf("SteeringReferences = %v", refs)
+	}
+	if refs := base().ACLReferences("web"); len(refs) != 1 || refs[0] != "steering-policies.dpi" {
+		t.Errorf("ACLReferences = %v", refs)
+	}
+}
@@ -177,6 +177,8 @@ func (i *Ingress) parsePacket(pkt *PuntPacket) (*dataplane.ParsedPacket, error)
 		parsed.Protocol = models.ProtocolL2TP
 		if ipv4Layer := packet.Layer(layers.LayerTypeIPv4); ipv4Layer != nil {
 			parsed.IPv4 = ipv4Layer.(*layers.IPv4)
+		} else if ipv6Layer := packet.Layer(layers.LayerTypeIPv6); ipv6Layer != nil {
+			parsed.IPv6 = ipv6Layer.(*layers.IPv6)
 		}
 		if udpLayer := packet.Layer(layers.LayerTypeUDP); udpLayer != nil {
 			parsed.UDP = udpLayer.(*layers.UDP)
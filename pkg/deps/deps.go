@@ -7,9 +7,13 @@ package deps
 import (
 	aaacomp "github.com/veesix-networks/osvbng/internal/aaa"
 	cgnatcomp "github.com/veesix-networks/osvbng/internal/cgnat"
+	flowspeccomp "github.com/veesix-networks/osvbng/internal/flowspec"
 	l2gwcomp "github.com/veesix-networks/osvbng/internal/l2gw"
 	l2tpcomp "github.com/veesix-networks/osvbng/internal/l2tp"
+	nptv6comp "github.com/veesix-networks/osvbng/internal/nptv6"
 	routingcomp "github.com/veesix-networks/osvbng/internal/routing"
+	rtbhcomp "github.com/veesix-networks/osvbng/internal/rtbh"
+	steeringcomp "github.com/veesix-networks/osvbng/internal/steering"
 	"github.com/veesix-networks/osvbng/internal/subscriber"
 	"github.com/veesix-networks/osvbng/internal/watchdog"
 	"github.com/veesix-networks/osvbng/pkg/cache"
@@ -50,6 +54,10 @@ type ShowDeps struct {
 	CGNAT            *cgnatcomp.Component
 	L2TP             *l2tpcomp.Component
 	L2GW             *l2gwcomp.Component
+	NPTv6            *nptv6comp.Component
+	Steering         *steeringcomp.Component
+	RTBH             *rtbhcomp.Component
+	FlowSpec         *flowspeccomp.Component
 	RunningConfig    RunningConfigReader
 	Orchestrator     *component.Orchestrator
 }
@@ -67,6 +75,8 @@ type OperDeps struct {
 	HAManager        *ha.Manager
 	PluginComponents map[string]component.Component
 	CGNAT            *cgnatcomp.Component
+	L2TP             *l2tpcomp.Component
+	RTBH             *rtbhcomp.Component
 	ConfigReloader   OperConfigReloader
 }
 
@@ -196,9 +196,34 @@ func ResolveV6(ctx *allocator.Context, profile *ip.IPv6Profile) *ResolvedDHCPv6
 		}
 	}
 
+	if ctx.AFTRName != "" && !hasDHCPv6Option(resolved.Options, OptionAFTRName) {
+		if payload, err := EncodeDomainName(ctx.AFTRName); err == nil {
+			resolved.Options = append(resolved.Options, EncodedDHCPv6Option{
+				Code:    OptionAFTRName,
+				Payload: payload,
+			})
+		}
+	}
+
+	if code, payload := EncodeS46Container(ctx.MAP); code != 0 && !hasDHCPv6Option(resolved.Options, code) {
+		resolved.Options = append(resolved.Options, EncodedDHCPv6Option{
+			Code:    code,
+			Payload: payload,
+		})
+	}
+
 	return resolved
 }
 
+func hasDHCPv6Option(opts []EncodedDHCPv6Option, code uint16) bool {
+	for _, o := range opts {
+		if o.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
 func findIANAPoolForAddr(addr net.IP, profile *ip.IPv6Profile) *ip.IANAPool {
 	for i := range profile.IANAPools {
 		_, poolNet, err := net.ParseCIDR(profile.IANAPools[i].Network)
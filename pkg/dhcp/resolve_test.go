@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
 	"github.com/veesix-networks/osvbng/pkg/config/ip"
 )
 
@@ -434,6 +435,100 @@ func TestResolveV6DNSPrecedence(t *testing.T) {
 	}
 }
 
+func TestResolveV6AFTRName(t *testing.T) {
+	v6 := map[string]*ip.IPv6Profile{
+		"prof1": {
+			IANAPools: []ip.IANAPool{{
+				Name:       "iana1",
+				Network:    "2001:db8::/64",
+				RangeStart: "2001:db8::1",
+				RangeEnd:   "2001:db8::10",
+			}},
+		},
+	}
+	initRegistry(t, nil, v6)
+
+	ctx := &allocator.Context{SessionID: "s1", IPv6ProfileName: "prof1", AFTRName: "aftr.example.net."}
+	res := ResolveV6(ctx, v6["prof1"])
+	if len(res.Options) != 1 || res.Options[0].Code != OptionAFTRName {
+		t.Fatalf("Options = %+v, want a single option 64", res.Options)
+	}
+	want := []byte("\x04aftr\x07example\x03net\x00")
+	if string(res.Options[0].Payload) != string(want) {
+		t.Fatalf("AFTR-Name payload = %q, want %q", res.Options[0].Payload, want)
+	}
+}
+
+func TestResolveV6MAPEContainer(t *testing.T) {
+	v6 := map[string]*ip.IPv6Profile{
+		"prof1": {
+			IANAPools: []ip.IANAPool{{
+				Name:       "iana1",
+				Network:    "2001:db8::/64",
+				RangeStart: "2001:db8::1",
+				RangeEnd:   "2001:db8::10",
+			}},
+		},
+	}
+	initRegistry(t, nil, v6)
+
+	_, rule6, _ := net.ParseCIDR("2001:db8::/40")
+	_, rule4, _ := net.ParseCIDR("192.0.2.0/24")
+	ctx := &allocator.Context{SessionID: "s1", IPv6ProfileName: "prof1", MAP: &cgnat.MAPParams{
+		Mode: cgnat.MAPModeE,
+		Rules: []cgnat.MAPRule{{
+			IPv6Prefix: rule6, IPv4Prefix: rule4, EABitsLength: 16, PSIDOffset: 6, PSIDLength: 8,
+		}},
+		BRAddress: net.ParseIP("2001:db8:ffff::1"),
+	}}
+	res := ResolveV6(ctx, v6["prof1"])
+	if len(res.Options) != 1 || res.Options[0].Code != OptionS46ContMAPE {
+		t.Fatalf("Options = %+v, want a single option 94", res.Options)
+	}
+	want := []byte{
+		0, 89, 0, 21, 0x00, 16, 24, 192, 0, 2, 0, 40, 0x20, 0x01, 0x0d, 0xb8, 0x00,
+		0, 93, 0, 4, 6, 8, 0, 0,
+		0, 90, 0, 16, 0x20, 0x01, 0x0d, 0xb8, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	if string(res.Options[0].Payload) != string(want) {
+		t.Fatalf("MAP-E container = % x, want % x", res.Options[0].Payload, want)
+	}
+}
+
+func TestEncodeS46ContainerMAPTDMR(t *testing.T) {
+	_, rule6, _ := net.ParseCIDR("2001:db8::/40")
+	_, rule4, _ := net.ParseCIDR("192.0.2.0/24")
+	_, dmr, _ := net.ParseCIDR("2001:db8:ffff::/64")
+	code, payload := EncodeS46Container(&cgnat.MAPParams{
+		Mode:  cgnat.MAPModeT,
+		Rules: []cgnat.MAPRule{{IPv6Prefix: rule6, IPv4Prefix: rule4, EABitsLength: 16, Forwarding: true}},
+		DMR:   dmr,
+	})
+	if code != OptionS46ContMAPT {
+		t.Fatalf("code = %d, want 95", code)
+	}
+	if payload[4] != 0x01 {
+		t.Fatalf("rule flags = %#x, want the F flag", payload[4])
+	}
+	dmrOpt := payload[len(payload)-13:]
+	want := []byte{0, 91, 0, 9, 64, 0x20, 0x01, 0x0d, 0xb8, 0xff, 0xff, 0, 0}
+	if string(dmrOpt) != string(want) {
+		t.Fatalf("DMR option = % x, want % x", dmrOpt, want)
+	}
+
+	if code, _ := EncodeS46Container(nil); code != 0 {
+		t.Fatalf("nil params encoded option %d", code)
+	}
+}
+
+func TestEncodeDomainNameRejectsBadLabels(t *testing.T) {
+	for _, name := range []string{"", ".", "a..b", string(make([]byte, 64)) + ".net"} {
+		if _, err := EncodeDomainName(name); err == nil {
+			t.Fatalf("EncodeDomainName(%q): want error", name)
+		}
+	}
+}
+
 func TestResolveV6PoolTimingOverrides(t *testing.T) {
 	v6 := map[string]*ip.IPv6Profile{
 		"prof1": {
@@ -1,10 +1,16 @@
 package dhcp
 
 import (
+	"errors"
 	"net"
+	"strings"
 	"time"
 )
 
+// OptionAFTRName is OPTION_AFTR_NAME (RFC 6334): the DS-Lite AFTR's
+// FQDN in DNS wire format.
+const OptionAFTRName uint16 = 64
+
 type ResolvedDHCPv4 struct {
 	YourIP    net.IP
 	Netmask   net.IPMask
@@ -48,3 +54,21 @@ type EncodedDHCPv6Option struct {
 	Code    uint16
 	Payload []byte
 }
+
+// EncodeDomainName renders a domain name in uncompressed DNS wire
+// format (RFC 1035 §3.1), as DHCPv6 domain-name options carry it.
+func EncodeDomainName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return nil, errors.New("dhcp: invalid domain name length")
+	}
+	out := make([]byte, 0, len(name)+2)
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return nil, errors.New("dhcp: invalid domain name label")
+		}
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+	return append(out, 0), nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dhcp
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/config/cgnat"
+)
+
+// Softwire46 options (RFC 7598). The container options carry the rest
+// as encapsulated options.
+const (
+	OptionS46Rule       uint16 = 89
+	OptionS46BR         uint16 = 90
+	OptionS46DMR        uint16 = 91
+	OptionS46PortParams uint16 = 93
+	OptionS46ContMAPE   uint16 = 94
+	OptionS46ContMAPT   uint16 = 95
+)
+
+// s46RuleFMR is the F flag of OPTION_S46_RULE: the rule is also a
+// Forwarding Mapping Rule.
+const s46RuleFMR = 0x01
+
+// EncodeS46Container renders the MAP-E (94) or MAP-T (95) container for
+// a CE: one OPTION_S46_RULE per mapping rule, each with its port
+// parameters, then the BR address (MAP-E) or the DMR (MAP-T). Returns
+// code 0 when p carries no rules.
+func EncodeS46Container(p *cgnat.MAPParams) (uint16, []byte) {
+	if p == nil || len(p.Rules) == 0 {
+		return 0, nil
+	}
+	var out []byte
+	for _, r := range p.Rules {
+		out = appendOption(out, OptionS46Rule, encodeS46Rule(r))
+	}
+	switch p.Mode {
+	case cgnat.MAPModeE:
+		if p.BRAddress == nil {
+			return 0, nil
+		}
+		out = appendOption(out, OptionS46BR, p.BRAddress.To16())
+		return OptionS46ContMAPE, out
+	case cgnat.MAPModeT:
+		if p.DMR == nil {
+			return 0, nil
+		}
+		ones, _ := p.DMR.Mask.Size()
+		dmr := append([]byte{byte(ones)}, prefixBytes(p.DMR.IP.To16(), ones)...)
+		out = appendOption(out, OptionS46DMR, dmr)
+		return OptionS46ContMAPT, out
+	}
+	return 0, nil
+}
+
+// encodeS46Rule renders OPTION_S46_RULE (RFC 7598 4.1) with its
+// OPTION_S46_PORTPARAMS; the PSID field of a rule's port parameters is
+// zero, the CE derives its own from the EA bits.
+func encodeS46Rule(r cgnat.MAPRule) []byte {
+	var flags byte
+	if r.Forwarding {
+		flags |= s46RuleFMR
+	}
+	v4Len, _ := r.IPv4Prefix.Mask.Size()
+	v6Len, _ := r.IPv6Prefix.Mask.Size()
+
+	b := []byte{flags, r.EABitsLength, byte(v4Len)}
+	b = append(b, r.IPv4Prefix.IP.To4()...)
+	b = append(b, byte(v6Len))
+	b = append(b, prefixBytes(r.IPv6Prefix.IP.To16(), v6Len)...)
+
+	ports := make([]byte, 4)
+	ports[0] = r.PSIDOffset
+	ports[1] = r.PSIDLength
+	return appendOption(b, OptionS46PortParams, ports)
+}
+
+// prefixBytes trims a prefix to the bytes that carry its length, as the
+// S46 options encode it.
+func prefixBytes(ip net.IP, ones int) []byte {
+	return ip[:(ones+7)/8]
+}
+
+func appendOption(b []byte, code uint16, payload []byte) []byte {
+	var hdr [4]byte
+	binary.BigEndian.PutUint16(hdr[0:2], code)
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(len(payload)))
+	b = append(b, hdr[:]...)
+	return append(b, payload...)
+}
@@ -28,6 +28,31 @@ const (
 	// EVPNTunnelProgrammedEvent.
 	TopicEVPNTunnelProgrammed = "osvbng:events:evpn:tunnel:programmed"
 	TopicCGNATMapping             = "osvbng:events:cgnat:mapping"
+	// TopicCGNATSubscriberLimit fires when a PBA subscriber's packets are
+	// dropped at its session limit or for want of a free port, at most
+	// once per subscriber and limit per the pool's notify interval.
+	// Carries CGNATSubscriberLimitEvent.
+	TopicCGNATSubscriberLimit = "osvbng:events:cgnat:subscriber-limit"
+	// TopicPoolThreshold fires when an address pool (IPv4, IA_NA, PD or
+	// CGNAT) crosses one of its utilisation watermarks. Carries
+	// PoolThresholdEvent.
+	TopicPoolThreshold = "osvbng:events:pool:threshold"
+	// TopicIPAMChunk fires when an on-demand pool chunk from the central
+	// IPAM is added to, or removed from, a profile. Carries
+	// IPAMChunkEvent.
+	TopicIPAMChunk = "osvbng:events:ipam:chunk"
+	// TopicNPTv6Binding fires when a session's NPTv6 prefix translation
+	// is programmed or removed. Carries NPTv6BindingEvent.
+	TopicNPTv6Binding = "osvbng:events:nptv6:binding"
+	// TopicServiceSchedule fires when a schedule boundary moves a
+	// session to different bindings. Carries ServiceScheduleEvent.
+	TopicServiceSchedule = "osvbng:events:service:schedule"
+	// TopicAccessLine fires when a renew reports new access line rates
+	// for a live session. Carries AccessLineEvent.
+	TopicAccessLine = "osvbng:events:access:line"
+	// TopicBlackhole fires when a remote-triggered blackhole is added,
+	// lifted or expires. Carries BlackholeEvent.
+	TopicBlackhole = "osvbng:events:rtbh:blackhole"
 	TopicSubscriberMutation       = "osvbng:events:subscriber:mutation"
 	TopicSubscriberMutationResult = "osvbng:events:subscriber:mutation:result"
 	TopicSubscriberTerminate      = "osvbng:events:subscriber:terminate"
@@ -36,9 +61,22 @@ const (
 	// §"Shared-core performance considerations" (spec-finalize C4).
 	TopicAAAResponseL2TP = "osvbng:events:aaa:response:l2tp"
 	TopicL2TPLACDecision = "osvbng:events:l2tp:lac:decision"
+	// TopicL2TPSessionSync carries SessionLifecycleEvents for L2TP
+	// session state that only HA replicates: LAC-role sessions (the
+	// subscriber is accounted on its PPPoE session) and LNS sessions
+	// torn down before they went Active.
+	// Consumers other than the HA sync sender must not subscribe.
+	TopicL2TPSessionSync = "osvbng:events:l2tp:session:sync"
+	// TopicL2TPTunnelAccounting carries L2TPTunnelAccountingEvents
+	// (RFC 2867 tunnel and link records) for the AAA component.
+	TopicL2TPTunnelAccounting = "osvbng:events:l2tp:tunnel:accounting"
 
 	// Layer 2 wholesale gateway
 	TopicAAAResponseL2GW = "osvbng:events:aaa:response:l2gw"
+	// TopicL2GWHandoffFailover fires after a redundant handoff group
+	// moves its circuits to another member interface. Carries
+	// L2GWHandoffFailoverEvent.
+	TopicL2GWHandoffFailover = "osvbng:events:l2gw:handoff:failover"
 
 	// TopicComponentReady fires when a component transitions out of its
 	// recovery window into StateReady. Carries a ComponentReadyEvent.
@@ -5,6 +5,8 @@
 package events
 
 import (
+	"net"
+
 	"github.com/veesix-networks/osvbng/pkg/models"
 	"github.com/veesix-networks/osvbng/pkg/session"
 )
@@ -56,6 +58,103 @@ type CGNATMappingEvent struct {
 	IsAdd     bool
 }
 
+// CGNAT subscriber limits: the dataplane dropped packets because the
+// subscriber was at max-sessions-per-subscriber, or because its port
+// blocks had no free port.
+const (
+	CGNATLimitSessions = "session-limit"
+	CGNATLimitPorts    = "port-exhaustion"
+)
+
+// CGNATSubscriberLimitEvent reports a subscriber hitting one of its
+// CGNAT limits. Drops counts the packets dropped since the previous
+// event for the subscriber and limit; the dataplane counts them per
+// node, so drops while several subscribers were at the limit are
+// shared among them. Escalation is the action taken,
+// additional-block or bypass, or empty; ServiceGroup is the bypass
+// service group for a bypass escalation.
+type CGNATSubscriberLimitEvent struct {
+	Pool         string
+	SessionID    string
+	InsideIP     net.IP
+	InsideVRFID  uint32
+	Limit        string
+	Drops        uint64
+	Blocks       int
+	MaxBlocks    int
+	Escalation   string
+	ServiceGroup string
+}
+
+// PoolThresholdEvent reports a pool moving between the normal, high
+// and exhausted levels. Family is ipv4, iana, pd or cgnat; Profile is
+// empty for CGNAT pools. Size and Available count addresses, delegated
+// prefixes or, for CGNAT, port blocks.
+type PoolThresholdEvent struct {
+	Family        string
+	Profile       string
+	Pool          string
+	Level         string
+	PreviousLevel string
+	Size          uint64
+	Available     uint64
+	Utilization   float64
+}
+
+// IPAM chunk actions: added and released chunks were requested or
+// returned by this node; adopted and removed chunks were replicated
+// from, or withdrawn by, the HA peer that owns them.
+const (
+	IPAMChunkAdded    = "added"
+	IPAMChunkReleased = "released"
+	IPAMChunkAdopted  = "adopted"
+	IPAMChunkRemoved  = "removed"
+)
+
+type IPAMChunkEvent struct {
+	Action string
+	Chunk  *models.IPAMChunk
+}
+
+// Blackhole actions: added and removed blackholes were requested or
+// lifted on this node or its HA peer; expired blackholes ran out.
+const (
+	BlackholeAdded   = "added"
+	BlackholeRemoved = "removed"
+	BlackholeExpired = "expired"
+)
+
+type BlackholeEvent struct {
+	Action    string
+	Blackhole *models.Blackhole
+}
+
+type NPTv6BindingEvent struct {
+	SRGName   string
+	SessionID string
+	Binding   *models.NPTv6Binding
+	IsAdd     bool
+}
+
+// ServiceScheduleEvent reports a session moved to new bindings at a
+// schedule boundary. Schedules are the schedules the session's service
+// group and QoS policies follow that are now active, empty once the
+// last of them closes.
+type ServiceScheduleEvent struct {
+	SessionID    string
+	ServiceGroup string
+	Schedules    []string
+}
+
+// AccessLineEvent reports new access line rates for a live session.
+// Attributes are the session's access-line attributes now in force;
+// Session is the session as it now stands.
+type AccessLineEvent struct {
+	SessionID  string
+	Attributes map[string]string
+	Session    models.SubscriberSession
+}
+
 type SubscriberMutationEvent struct {
 	RequestID      string
 	SessionID      string
@@ -144,6 +243,19 @@ type EVPNTunnelProgrammedEvent struct {
 	VNI       uint32
 }
 
+// L2GWHandoffFailoverEvent is the TopicL2GWHandoffFailover payload.
+// From is empty when the group had no usable member before; To is
+// empty when every member is down and the circuits stay where they
+// were. Moved and Failed count circuits.
+type L2GWHandoffFailoverEvent struct {
+	Group  string
+	From   string
+	To     string
+	Reason string
+	Moved  int
+	Failed int
+}
+
 // L2TPLACDecisionEvent communicates the LAC bring-up outcome back to
 // the PPPoE component. Published on TopicL2TPLACDecision once the L2TP
 // component has either established the tunneled session or exhausted
@@ -167,3 +279,26 @@ type L2TPLACDecisionEvent struct {
 	PeerSessionID        uint16
 	LACL2TPSessionIndex  uint32
 }
+
+// L2TPTunnelAccountingEvent is one RFC 2867 accounting record from
+// the L2TP component, published on TopicL2TPTunnelAccounting. Status
+// is tunnel-start / tunnel-stop / tunnel-reject / tunnel-link-start /
+// tunnel-link-stop. Endpoints follow RFC 2868: the client is the LAC,
+// the server the LNS. Link records also carry the session identity.
+type L2TPTunnelAccountingEvent struct {
+	Status             string
+	AcctSessionID      string
+	AcctMultiSessionID string
+	TunnelConnectionID string
+	Medium             string
+	ClientEndpoint     string
+	ServerEndpoint     string
+	ClientAuthID       string
+	ServerAuthID       string
+	SessionID          string
+	Username           string
+	SessionDuration    uint32
+	PacketsLost        uint32
+	TerminateCause     string
+	Attributes         map[string]string
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ha
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+)
+
+// ACLIndexStore holds the dataplane indexes of the access lists, which
+// the peers keep the same.
+type ACLIndexStore interface {
+	// ACLIndexes returns the index of each access list on this node.
+	ACLIndexes() map[string]uint32
+	// ApplyPeerACLIndexes takes the peer's indexes: lists not created
+	// yet are created at them, and with move, lists at another index
+	// are moved to them.
+	ApplyPeerACLIndexes(indexes map[string]uint32, move bool) error
+}
+
+func (m *Manager) RegisterACLIndexStore(store ACLIndexStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aclStore = store
+}
+
+func (m *Manager) aclIndexStore() ACLIndexStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.aclStore
+}
+
+// followsPeerACLIndexes reports whether this node moves its access lists
+// to the indexes of the peer with ID peerNodeID. Only one of the two
+// does, so they do not chase each other's indexes: the one with the
+// higher node ID.
+func (m *Manager) followsPeerACLIndexes(peerNodeID string) bool {
+	return peerNodeID != "" && m.cfg.NodeID > peerNodeID
+}
+
+// ReplicateACLIndexes sends this node's access-list indexes to the peer.
+// It is a no-op without a peer; the peer picks them up from
+// ListACLIndexes on its next SRG transition.
+func (m *Manager) ReplicateACLIndexes(ctx context.Context, indexes map[string]uint32) error {
+	if m.peer == nil {
+		return nil
+	}
+
+	resp, err := m.peer.SyncACLIndexes(ctx, &hapb.SyncACLIndexesRequest{
+		Sequence: m.aclSeq.Add(1),
+		NodeId:   m.cfg.NodeID,
+		Indexes:  aclIndexesToProto(indexes),
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New("peer rejected ACL indexes")
+	}
+	return nil
+}
+
+func (m *Manager) pullACLIndexes() {
+	store := m.aclIndexStore()
+	if store == nil || m.peer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.Ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := m.peer.ListACLIndexes(ctx, &hapb.ListACLIndexesRequest{})
+	if err != nil {
+		m.logger.Warn("ACL index list request failed", "error", err)
+		return
+	}
+
+	move := m.followsPeerACLIndexes(resp.NodeId)
+	if err := store.ApplyPeerACLIndexes(aclIndexesFromProto(resp.Indexes), move); err != nil {
+		m.logger.Warn("Failed to apply peer ACL indexes", "error", err)
+	}
+	m.logger.Info("ACL indexes reconciled with peer", "acls", len(resp.Indexes), "move", move)
+}
+
+func aclIndexesToProto(indexes map[string]uint32) []*hapb.ACLIndex {
+	out := make([]*hapb.ACLIndex, 0, len(indexes))
+	for name, index := range indexes {
+		out = append(out, &hapb.ACLIndex{Name: name, Index: index})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func aclIndexesFromProto(indexes []*hapb.ACLIndex) map[string]uint32 {
+	out := make(map[string]uint32, len(indexes))
+	for _, idx := range indexes {
+		out[idx.Name] = idx.Index
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ha
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// BlackholeStore holds the remote-triggered blackholes the peers share.
+// Each node replicates the blackholes requested on it; the peer
+// installs and advertises them too.
+type BlackholeStore interface {
+	// ApplySyncedBlackhole adds or lifts a blackhole replicated by the
+	// peer.
+	ApplySyncedBlackhole(add bool, b *models.Blackhole) error
+	// OwnedBlackholes returns the blackholes requested on this node.
+	OwnedBlackholes() []*models.Blackhole
+	// ReconcilePeerBlackholes brings the adopted blackholes in line with
+	// the peer's full list of owned blackholes.
+	ReconcilePeerBlackholes(blackholes []*models.Blackhole)
+}
+
+func (m *Manager) RegisterBlackholeStore(store BlackholeStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rtbhStore = store
+}
+
+func (m *Manager) blackholeStore() BlackholeStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rtbhStore
+}
+
+// ReplicateBlackhole sends a blackhole's addition or removal to the
+// peer. It is a no-op without a peer; the peer picks up anything it
+// missed from ListBlackholes on its next SRG transition.
+func (m *Manager) ReplicateBlackhole(ctx context.Context, add bool, b *models.Blackhole) error {
+	if m.peer == nil {
+		return nil
+	}
+
+	action := hapb.SyncAction_SYNC_ACTION_DELETE
+	if add {
+		action = hapb.SyncAction_SYNC_ACTION_CREATE
+	}
+	resp, err := m.peer.SyncBlackhole(ctx, &hapb.SyncBlackholeRequest{
+		Sequence:  m.rtbhSeq.Add(1),
+		Action:    action,
+		Blackhole: blackholeToCheckpoint(b),
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New("peer rejected blackhole")
+	}
+	return nil
+}
+
+func (m *Manager) pullBlackholes() {
+	store := m.blackholeStore()
+	if store == nil || m.peer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.Ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := m.peer.ListBlackholes(ctx, &hapb.ListBlackholesRequest{})
+	if err != nil {
+		m.logger.Warn("Blackhole list request failed", "error", err)
+		return
+	}
+
+	blackholes := make([]*models.Blackhole, 0, len(resp.Blackholes))
+	for _, cp := range resp.Blackholes {
+		blackholes = append(blackholes, checkpointToBlackhole(cp))
+	}
+	store.ReconcilePeerBlackholes(blackholes)
+	m.logger.Info("Blackholes reconciled with peer", "blackholes", len(blackholes))
+}
+
+func blackholeToCheckpoint(b *models.Blackhole) *hapb.BlackholeCheckpoint {
+	cp := &hapb.BlackholeCheckpoint{
+		Prefix:        b.Prefix,
+		Vrf:           b.VRF,
+		Source:        b.Source,
+		SessionId:     b.SessionID,
+		Reason:        b.Reason,
+		Owner:         b.Owner,
+		ExpiresAtUnix: b.ExpiresAt.Unix(),
+	}
+	if !b.CreatedAt.IsZero() {
+		cp.CreatedAtUnix = b.CreatedAt.Unix()
+	}
+	return cp
+}
+
+func checkpointToBlackhole(cp *hapb.BlackholeCheckpoint) *models.Blackhole {
+	b := &models.Blackhole{
+		Prefix:    cp.Prefix,
+		VRF:       cp.Vrf,
+		Source:    cp.Source,
+		SessionID: cp.SessionId,
+		Reason:    cp.Reason,
+		Owner:     cp.Owner,
+		ExpiresAt: time.Unix(cp.ExpiresAtUnix, 0),
+	}
+	if cp.CreatedAtUnix != 0 {
+		b.CreatedAt = time.Unix(cp.CreatedAtUnix, 0)
+	}
+	return b
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/veesix-networks/osvbng/pkg/events"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 	"github.com/veesix-networks/osvbng/pkg/models"
+	"google.golang.org/protobuf/proto"
 )
 
 type CGNATSyncSender struct {
@@ -156,18 +157,31 @@ func mappingToCheckpoint(srgName string, m *models.CGNATMapping) *hapb.CGNATMapp
 		PortBlockStart: uint32(m.PortBlockStart),
 		PortBlockEnd:   uint32(m.PortBlockEnd),
 		InsideVrfId:    m.InsideVRFID,
+		ExtraBlock:     m.Extra,
 	}
-	if m.InsideIP != nil {
-		cp.InsideIp = m.InsideIP.To4()
+	if v4 := m.InsideIP.To4(); v4 != nil {
+		cp.InsideIp = v4
+	} else if m.InsideIP != nil {
+		// DS-Lite B4 address.
+		cp.InsideIp = m.InsideIP.To16()
 	}
 	if m.OutsideIP != nil {
 		cp.OutsideIp = m.OutsideIP.To4()
 	}
+	if f := m.Forward; f != nil {
+		cp.ForwardProtocol = f.Protocol
+		cp.ForwardInsidePort = uint32(f.InsidePort)
+		cp.ForwardSource = f.Source
+		cp.ForwardNonce = f.Nonce
+		if f.ExpiresAt != nil {
+			cp.ForwardExpiresUnix = f.ExpiresAt.Unix()
+		}
+	}
 	return cp
 }
 
 func checkpointToMapping(cp *hapb.CGNATMappingCheckpoint) *models.CGNATMapping {
-	return &models.CGNATMapping{
+	m := &models.CGNATMapping{
 		SessionID:      cp.SessionId,
 		PoolName:       cp.PoolName,
 		InsideIP:       net.IP(cp.InsideIp),
@@ -175,5 +189,43 @@ func checkpointToMapping(cp *hapb.CGNATMappingCheckpoint) *models.CGNATMapping {
 		PortBlockStart: uint16(cp.PortBlockStart),
 		PortBlockEnd:   uint16(cp.PortBlockEnd),
 		InsideVRFID:    cp.InsideVrfId,
+		Extra:          cp.ExtraBlock,
+	}
+	if cp.ForwardProtocol != "" {
+		m.Forward = &models.CGNATPortForward{
+			Protocol:   cp.ForwardProtocol,
+			InsidePort: uint16(cp.ForwardInsidePort),
+			Source:     cp.ForwardSource,
+			Nonce:      cp.ForwardNonce,
+		}
+		if cp.ForwardExpiresUnix != 0 {
+			t := time.Unix(cp.ForwardExpiresUnix, 0)
+			m.Forward.ExpiresAt = &t
+		}
+	}
+	return m
+}
+
+// cgnatCheckpointKey is the synced-namespace key of a checkpoint: the
+// session ID for its first port block, with the forward ID appended for
+// a port forward and the block appended for an extra block, so a
+// subscriber's forwards and extra blocks sit beside its first block.
+func cgnatCheckpointKey(cp *hapb.CGNATMappingCheckpoint) string {
+	if cp.ExtraBlock {
+		return checkpointToMapping(cp).ExtraBlockKey()
+	}
+	if cp.ForwardProtocol == "" {
+		return cp.SessionId
+	}
+	return cp.SessionId + "/" + checkpointToMapping(cp).ForwardID()
+}
+
+// DecodeCGNATCheckpoint decodes a mapping stored in the synced CGNAT
+// namespace.
+func DecodeCGNATCheckpoint(data []byte) (*models.CGNATMapping, error) {
+	cp := &hapb.CGNATMappingCheckpoint{}
+	if err := proto.Unmarshal(data, cp); err != nil {
+		return nil, err
 	}
+	return checkpointToMapping(cp), nil
 }
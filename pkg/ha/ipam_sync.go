@@ -0,0 +1,128 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ha
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+// ErrPeerChunkInUse is returned by ReplicateIPAMChunk when the peer
+// refuses to drop a chunk it still has leases in.
+var ErrPeerChunkInUse = errors.New("peer has leases in chunk")
+
+// IPAMChunkStore holds the on-demand pool chunks the peers share. Each
+// node replicates the chunks it owns; the peer adds them to its own
+// allocator so sessions addressed from them survive a failover.
+type IPAMChunkStore interface {
+	// ApplySyncedChunk adds or removes a chunk owned by the peer. A
+	// removal returns allocator.ErrPoolInUse while the chunk has leases.
+	ApplySyncedChunk(add bool, chunk *models.IPAMChunk) error
+	// OwnedChunks returns the chunks this node took from the IPAM.
+	OwnedChunks() []*models.IPAMChunk
+	// ReconcilePeerChunks brings the adopted chunks in line with the
+	// peer's full list of owned chunks.
+	ReconcilePeerChunks(chunks []*models.IPAMChunk)
+}
+
+func (m *Manager) RegisterIPAMChunkStore(store IPAMChunkStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ipamStore = store
+}
+
+func (m *Manager) ipamChunkStore() IPAMChunkStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ipamStore
+}
+
+// ReplicateIPAMChunk sends an owned chunk's addition or removal to the
+// peer. It is a no-op without a peer; the peer picks up anything it
+// missed from ListIPAMChunks on its next SRG transition.
+func (m *Manager) ReplicateIPAMChunk(ctx context.Context, add bool, chunk *models.IPAMChunk) error {
+	if m.peer == nil {
+		return nil
+	}
+
+	action := hapb.SyncAction_SYNC_ACTION_DELETE
+	if add {
+		action = hapb.SyncAction_SYNC_ACTION_CREATE
+	}
+	resp, err := m.peer.SyncIPAMChunk(ctx, &hapb.SyncIPAMChunkRequest{
+		Sequence: m.ipamSeq.Add(1),
+		Action:   action,
+		Chunk:    chunkToCheckpoint(chunk),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.InUse {
+		return ErrPeerChunkInUse
+	}
+	if !resp.Success {
+		return errors.New("peer rejected chunk")
+	}
+	return nil
+}
+
+func (m *Manager) pullIPAMChunks() {
+	store := m.ipamChunkStore()
+	if store == nil || m.peer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.Ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := m.peer.ListIPAMChunks(ctx, &hapb.ListIPAMChunksRequest{})
+	if err != nil {
+		m.logger.Warn("IPAM chunk list request failed", "error", err)
+		return
+	}
+
+	chunks := make([]*models.IPAMChunk, 0, len(resp.Chunks))
+	for _, cp := range resp.Chunks {
+		chunks = append(chunks, checkpointToChunk(cp))
+	}
+	store.ReconcilePeerChunks(chunks)
+	m.logger.Info("IPAM chunks reconciled with peer", "chunks", len(chunks))
+}
+
+func chunkToCheckpoint(c *models.IPAMChunk) *hapb.IPAMChunkCheckpoint {
+	cp := &hapb.IPAMChunkCheckpoint{
+		Family:  c.Family,
+		Profile: c.Profile,
+		Pool:    c.Pool,
+		Prefix:  c.Prefix,
+		Vrf:     c.VRF,
+		Owner:   c.Owner,
+		IpamId:  c.IPAMID,
+	}
+	if !c.AllocatedAt.IsZero() {
+		cp.AllocatedAtUnix = c.AllocatedAt.Unix()
+	}
+	return cp
+}
+
+func checkpointToChunk(cp *hapb.IPAMChunkCheckpoint) *models.IPAMChunk {
+	c := &models.IPAMChunk{
+		Family:  cp.Family,
+		Profile: cp.Profile,
+		Pool:    cp.Pool,
+		Prefix:  cp.Prefix,
+		VRF:     cp.Vrf,
+		Owner:   cp.Owner,
+		IPAMID:  cp.IpamId,
+	}
+	if cp.AllocatedAtUnix != 0 {
+		c.AllocatedAt = time.Unix(cp.AllocatedAtUnix, 0)
+	}
+	return c
+}
@@ -65,6 +65,12 @@ type Manager struct {
 	syncReceiver    *SyncReceiver
 	registry        *allocator.Registry
 	opdbStore       opdb.Store
+	ipamStore       IPAMChunkStore
+	ipamSeq         atomic.Uint64
+	rtbhStore       BlackholeStore
+	rtbhSeq         atomic.Uint64
+	aclStore        ACLIndexStore
+	aclSeq          atomic.Uint64
 
 	peerSyncSeqs   map[string]uint64
 	bulkSyncCounts map[string]*atomic.Uint64
@@ -229,6 +235,7 @@ func (m *Manager) Start(ctx context.Context) error {
 		// HA-side adoption of setupSession + RestoreCauseHAFailover is
 		// tracked separately.
 		m.eventBus.Subscribe(events.TopicSessionLifecycle, m.syncSender.HandleEvent)
+		m.eventBus.Subscribe(events.TopicL2TPSessionSync, m.syncSender.HandleEvent)
 		m.eventBus.Subscribe(events.TopicSubscriberMutationResult, m.syncSender.HandleMutationResult)
 		m.Go(func() { m.syncSender.Run(m.Ctx) })
 
@@ -597,6 +604,12 @@ func (m *Manager) driveSync(t *StateTransition) {
 		go m.requestCGNATBulkSync(t.SRGName)
 	}
 
+	if (isActive && !wasActive) || (isStandby && !wasStandby) {
+		go m.pullIPAMChunks()
+		go m.pullBlackholes()
+		go m.pullACLIndexes()
+	}
+
 	if m.registry != nil && (t.NewState == SRGStateActive || t.NewState == SRGStateStandby) && t.OldState == SRGStateReady {
 		sm, ok := m.getSRG(t.SRGName)
 		if ok {
@@ -277,6 +277,78 @@ func (p *PeerClient) BulkSyncCGNAT(ctx context.Context, req *hapb.BulkSyncCGNATR
 	return client.BulkSyncCGNAT(ctx, req)
 }
 
+func (p *PeerClient) SyncIPAMChunk(ctx context.Context, req *hapb.SyncIPAMChunkRequest) (*hapb.SyncIPAMChunkResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.SyncIPAMChunk(ctx, req)
+}
+
+func (p *PeerClient) ListIPAMChunks(ctx context.Context, req *hapb.ListIPAMChunksRequest) (*hapb.ListIPAMChunksResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.ListIPAMChunks(ctx, req)
+}
+
+func (p *PeerClient) SyncBlackhole(ctx context.Context, req *hapb.SyncBlackholeRequest) (*hapb.SyncBlackholeResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.SyncBlackhole(ctx, req)
+}
+
+func (p *PeerClient) ListBlackholes(ctx context.Context, req *hapb.ListBlackholesRequest) (*hapb.ListBlackholesResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.ListBlackholes(ctx, req)
+}
+
+func (p *PeerClient) SyncACLIndexes(ctx context.Context, req *hapb.SyncACLIndexesRequest) (*hapb.SyncACLIndexesResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.SyncACLIndexes(ctx, req)
+}
+
+func (p *PeerClient) ListACLIndexes(ctx context.Context, req *hapb.ListACLIndexesRequest) (*hapb.ListACLIndexesResponse, error) {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return nil, errNotConnected
+	}
+
+	return client.ListACLIndexes(ctx, req)
+}
+
 func (p *PeerClient) GetState() PeerState {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -7,10 +7,12 @@ package ha
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"time"
 
 	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
+	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 	"github.com/veesix-networks/osvbng/pkg/models"
 )
@@ -166,6 +168,84 @@ func (s *HAPeerServer) SyncCGNATMapping(ctx context.Context, req *hapb.SyncCGNAT
 	return s.manager.syncReceiver.HandleSyncCGNATMapping(ctx, req)
 }
 
+func (s *HAPeerServer) SyncIPAMChunk(_ context.Context, req *hapb.SyncIPAMChunkRequest) (*hapb.SyncIPAMChunkResponse, error) {
+	store := s.manager.ipamChunkStore()
+	if store == nil || req.Chunk == nil {
+		return &hapb.SyncIPAMChunkResponse{Success: false}, nil
+	}
+
+	add := req.Action == hapb.SyncAction_SYNC_ACTION_CREATE
+	err := store.ApplySyncedChunk(add, checkpointToChunk(req.Chunk))
+	if errors.Is(err, allocator.ErrPoolInUse) {
+		return &hapb.SyncIPAMChunkResponse{InUse: true}, nil
+	}
+	if err != nil {
+		s.logger.Warn("Failed to apply synced IPAM chunk", "prefix", req.Chunk.Prefix, "add", add, "error", err)
+		return &hapb.SyncIPAMChunkResponse{Success: false}, nil
+	}
+	return &hapb.SyncIPAMChunkResponse{Success: true}, nil
+}
+
+func (s *HAPeerServer) ListIPAMChunks(_ context.Context, _ *hapb.ListIPAMChunksRequest) (*hapb.ListIPAMChunksResponse, error) {
+	resp := &hapb.ListIPAMChunksResponse{}
+	store := s.manager.ipamChunkStore()
+	if store == nil {
+		return resp, nil
+	}
+	for _, chunk := range store.OwnedChunks() {
+		resp.Chunks = append(resp.Chunks, chunkToCheckpoint(chunk))
+	}
+	return resp, nil
+}
+
+func (s *HAPeerServer) SyncBlackhole(_ context.Context, req *hapb.SyncBlackholeRequest) (*hapb.SyncBlackholeResponse, error) {
+	store := s.manager.blackholeStore()
+	if store == nil || req.Blackhole == nil {
+		return &hapb.SyncBlackholeResponse{Success: false}, nil
+	}
+
+	add := req.Action == hapb.SyncAction_SYNC_ACTION_CREATE
+	if err := store.ApplySyncedBlackhole(add, checkpointToBlackhole(req.Blackhole)); err != nil {
+		s.logger.Warn("Failed to apply synced blackhole", "prefix", req.Blackhole.Prefix, "add", add, "error", err)
+		return &hapb.SyncBlackholeResponse{Success: false}, nil
+	}
+	return &hapb.SyncBlackholeResponse{Success: true}, nil
+}
+
+func (s *HAPeerServer) ListBlackholes(_ context.Context, _ *hapb.ListBlackholesRequest) (*hapb.ListBlackholesResponse, error) {
+	resp := &hapb.ListBlackholesResponse{}
+	store := s.manager.blackholeStore()
+	if store == nil {
+		return resp, nil
+	}
+	for _, b := range store.OwnedBlackholes() {
+		resp.Blackholes = append(resp.Blackholes, blackholeToCheckpoint(b))
+	}
+	return resp, nil
+}
+
+func (s *HAPeerServer) SyncACLIndexes(_ context.Context, req *hapb.SyncACLIndexesRequest) (*hapb.SyncACLIndexesResponse, error) {
+	store := s.manager.aclIndexStore()
+	if store == nil {
+		return &hapb.SyncACLIndexesResponse{Success: false}, nil
+	}
+
+	if err := store.ApplyPeerACLIndexes(aclIndexesFromProto(req.Indexes), s.manager.followsPeerACLIndexes(req.NodeId)); err != nil {
+		s.logger.Warn("Failed to apply peer ACL indexes", "error", err)
+	}
+	return &hapb.SyncACLIndexesResponse{Success: true}, nil
+}
+
+func (s *HAPeerServer) ListACLIndexes(_ context.Context, _ *hapb.ListACLIndexesRequest) (*hapb.ListACLIndexesResponse, error) {
+	resp := &hapb.ListACLIndexesResponse{NodeId: s.manager.cfg.NodeID}
+	store := s.manager.aclIndexStore()
+	if store == nil {
+		return resp, nil
+	}
+	resp.Indexes = aclIndexesToProto(store.ACLIndexes())
+	return resp, nil
+}
+
 func (s *HAPeerServer) BulkSyncCGNAT(req *hapb.BulkSyncCGNATRequest, stream hapb.HAPeerService_BulkSyncCGNATServer) error {
 	if s.manager.opdbStore == nil {
 		return nil
@@ -185,27 +265,35 @@ func (s *HAPeerServer) BulkSyncCGNAT(req *hapb.BulkSyncCGNATRequest, stream hapb
 	pageSize := s.manager.cfg.GetSyncPageSize()
 	var page []*hapb.CGNATMappingCheckpoint
 
-	err := s.manager.opdbStore.Load(stream.Context(), "cgnat_mappings", func(key string, value []byte) error {
-		var m models.CGNATMapping
-		if err := json.Unmarshal(value, &m); err != nil {
-			return nil
-		}
+	// First port blocks, extra blocks and port forwards persist in
+	// separate namespaces; a forward only syncs while it is bound to a
+	// session's block.
+	for _, ns := range []string{"cgnat_mappings", "cgnat_extra_blocks", "cgnat_forwards"} {
+		err := s.manager.opdbStore.Load(stream.Context(), ns, func(key string, value []byte) error {
+			var m models.CGNATMapping
+			if err := json.Unmarshal(value, &m); err != nil {
+				return nil
+			}
+			if m.Forward != nil && m.SessionID == "" {
+				return nil
+			}
 
-		page = append(page, mappingToCheckpoint("", &m))
+			page = append(page, mappingToCheckpoint("", &m))
 
-		if len(page) >= pageSize {
-			if err := stream.Send(&hapb.BulkSyncCGNATResponse{
-				Mappings: page,
-				Sequence: snapshotSeq,
-			}); err != nil {
-				return err
+			if len(page) >= pageSize {
+				if err := stream.Send(&hapb.BulkSyncCGNATResponse{
+					Mappings: page,
+					Sequence: snapshotSeq,
+				}); err != nil {
+					return err
+				}
+				page = nil
 			}
-			page = nil
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-		return nil
-	})
-	if err != nil {
-		return err
 	}
 
 	return stream.Send(&hapb.BulkSyncCGNATResponse{
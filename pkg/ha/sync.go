@@ -276,6 +276,47 @@ func sessionToCheckpoint(sess models.SubscriberSession) *hapb.SessionCheckpoint
 		if !s.ActivatedAt.IsZero() {
 			cp.BoundAtNs = s.ActivatedAt.UnixNano()
 		}
+	case *models.PPPoL2TPSession:
+		cp.AccessType = "l2tp"
+		cp.Vrf = s.VRF
+		cp.PppoeSessionId = uint32(s.PPPoESessionID)
+		cp.LcpState = s.LCPState
+		cp.IpcpState = s.IPCPState
+		cp.Ipv6CpState = s.IPv6CPState
+		cp.Ipv4Pool = s.IPv4Pool
+		cp.IanaPool = s.IANAPool
+		cp.PdPool = s.PDPool
+		cp.LcpMagic = s.LCPMagic
+		cp.AaaAttributes = s.Attributes
+		cp.NegotiatedPppMtu = uint32(s.NegotiatedPPPMTU)
+		cp.L2TpLocalIp = s.LocalIP
+		cp.L2TpPeerIp = s.PeerIP
+		cp.L2TpLocalTunnelId = uint32(s.LocalTunnelID)
+		cp.L2TpPeerTunnelId = uint32(s.PeerTunnelID)
+		cp.L2TpLocalSessionId = uint32(s.LocalSessionID)
+		cp.L2TpPeerSessionId = uint32(s.PeerSessionID)
+		cp.L2TpLocalPort = uint32(s.LocalPort)
+		cp.L2TpPeerPort = uint32(s.PeerPort)
+		cp.L2TpRole = s.Role
+		cp.L2TpLocalHostname = s.LocalHostname
+		cp.L2TpPeerHostname = s.PeerHostname
+		cp.L2TpNs = uint32(s.Ns)
+		cp.L2TpNr = uint32(s.Nr)
+		cp.L2TpHelloInterval = uint32(s.HelloInterval / time.Second)
+		cp.L2TpPppHdrSkip = uint32(s.PPPHdrSkip)
+		cp.L2TpFailover = s.Failover
+		cp.L2TpPeerRecoveryTimeMs = uint32(s.PeerRecoveryTime / time.Millisecond)
+		if !s.ActivatedAt.IsZero() {
+			cp.BoundAtNs = s.ActivatedAt.UnixNano()
+		}
+		if s.IPv6Prefix != "" {
+			_, ipNet, err := net.ParseCIDR(s.IPv6Prefix)
+			if err == nil {
+				cp.Ipv6Prefix = ipNet.IP.To16()
+				ones, _ := ipNet.Mask.Size()
+				cp.Ipv6PrefixLen = uint32(ones)
+			}
+		}
 	case *models.PPPSession:
 		cp.AccessType = "pppoe"
 		cp.Vrf = s.VRF
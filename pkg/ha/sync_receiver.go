@@ -134,6 +134,9 @@ func (r *SyncReceiver) ClearSyncedNamespace(ctx context.Context, srgName string)
 	if err := r.opdb.Clear(ctx, opdb.NamespaceHASyncedL2GW); err != nil {
 		return fmt.Errorf("clear synced l2gw: %w", err)
 	}
+	if err := r.opdb.Clear(ctx, opdb.NamespaceHASyncedL2TP); err != nil {
+		return fmt.Errorf("clear synced l2tp: %w", err)
+	}
 	return nil
 }
 
@@ -159,6 +162,8 @@ func syncedNamespace(accessType string) string {
 		return opdb.NamespaceHASyncedPPPoE
 	case "l2gw":
 		return opdb.NamespaceHASyncedL2GW
+	case "l2tp":
+		return opdb.NamespaceHASyncedL2TP
 	default:
 		return opdb.NamespaceHASyncedIPoE
 	}
@@ -262,11 +267,11 @@ func (r *SyncReceiver) storeCGNATCheckpoint(ctx context.Context, cp *hapb.CGNATM
 	if err != nil {
 		return fmt.Errorf("marshal cgnat checkpoint: %w", err)
 	}
-	return r.opdb.Put(ctx, opdb.NamespaceHASyncedCGNAT, cp.SessionId, data)
+	return r.opdb.Put(ctx, opdb.NamespaceHASyncedCGNAT, cgnatCheckpointKey(cp), data)
 }
 
 func (r *SyncReceiver) deleteCGNATCheckpoint(ctx context.Context, cp *hapb.CGNATMappingCheckpoint) error {
-	return r.opdb.Delete(ctx, opdb.NamespaceHASyncedCGNAT, cp.SessionId)
+	return r.opdb.Delete(ctx, opdb.NamespaceHASyncedCGNAT, cgnatCheckpointKey(cp))
 }
 
 func (r *SyncReceiver) GetCGNATLastSeq(srgName string) uint64 {
@@ -10,12 +10,14 @@ import (
 	"net/netip"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	hapb "github.com/veesix-networks/osvbng/api/proto/ha"
 	"github.com/veesix-networks/osvbng/pkg/allocator"
 	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/models"
 	"github.com/veesix-networks/osvbng/pkg/opdb"
 )
 
@@ -177,6 +179,147 @@ func TestSyncReceiver_PPPoENamespace(t *testing.T) {
 	assert.False(t, store.has(opdb.NamespaceHASyncedIPoE, "ppp-1"))
 }
 
+func TestSyncReceiver_L2TPNamespace(t *testing.T) {
+	store := newMemStore()
+	recv := NewSyncReceiver(store, nil, logger.NewTest())
+	ctx := context.Background()
+
+	var applied *hapb.SessionCheckpoint
+	recv.RegisterApplier("l2tp", func(_ hapb.SyncAction, cp *hapb.SessionCheckpoint) {
+		applied = cp
+	})
+
+	req := &hapb.SyncSessionRequest{
+		SrgName:  "srg1",
+		Sequence: 1,
+		Action:   hapb.SyncAction_SYNC_ACTION_CREATE,
+		Session: &hapb.SessionCheckpoint{
+			SessionId:         "l2tp:10.0.0.2:5:9",
+			AccessType:        "l2tp",
+			L2TpLocalTunnelId: 5,
+			L2TpNs:            10,
+			L2TpNr:            20,
+		},
+	}
+
+	resp, err := recv.HandleSyncSession(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, store.has(opdb.NamespaceHASyncedL2TP, "l2tp:10.0.0.2:5:9"))
+	require.NotNil(t, applied)
+	assert.Equal(t, uint32(10), applied.L2TpNs)
+	assert.Equal(t, uint32(20), applied.L2TpNr)
+}
+
+func TestSyncReceiver_CGNATForwardKeyedBesideBlock(t *testing.T) {
+	store := newMemStore()
+	recv := NewSyncReceiver(store, nil, logger.NewTest())
+	ctx := context.Background()
+
+	block := &models.CGNATMapping{
+		SessionID:      "s1",
+		PoolName:       "p1",
+		InsideIP:       net.ParseIP("100.64.0.10"),
+		OutsideIP:      net.ParseIP("198.51.100.1"),
+		PortBlockStart: 2048,
+		PortBlockEnd:   2111,
+	}
+	expires := time.Unix(1700000000, 0)
+	forward := *block
+	forward.PortBlockStart, forward.PortBlockEnd = 2050, 2050
+	forward.Forward = &models.CGNATPortForward{
+		Protocol:   "tcp",
+		InsidePort: 8080,
+		Source:     models.CGNATForwardPCP,
+		ExpiresAt:  &expires,
+		Nonce:      []byte{1, 2, 3},
+	}
+
+	for i, m := range []*models.CGNATMapping{block, &forward} {
+		_, err := recv.HandleSyncCGNATMapping(ctx, &hapb.SyncCGNATMappingRequest{
+			SrgName:  "srg1",
+			Sequence: uint64(i + 1),
+			Action:   hapb.SyncAction_SYNC_ACTION_CREATE,
+			Mapping:  mappingToCheckpoint("srg1", m),
+		})
+		require.NoError(t, err)
+	}
+
+	key := "s1/tcp/100.64.0.10/8080"
+	assert.True(t, store.has(opdb.NamespaceHASyncedCGNAT, "s1"))
+	require.True(t, store.has(opdb.NamespaceHASyncedCGNAT, key))
+
+	got, err := DecodeCGNATCheckpoint(store.data[opdb.NamespaceHASyncedCGNAT][key])
+	require.NoError(t, err)
+	require.NotNil(t, got.Forward)
+	assert.Equal(t, uint16(2050), got.PortBlockStart)
+	assert.Equal(t, uint16(8080), got.Forward.InsidePort)
+	assert.Equal(t, models.CGNATForwardPCP, got.Forward.Source)
+	assert.Equal(t, []byte{1, 2, 3}, got.Forward.Nonce)
+	assert.True(t, got.Forward.ExpiresAt.Equal(expires))
+
+	_, err = recv.HandleSyncCGNATMapping(ctx, &hapb.SyncCGNATMappingRequest{
+		SrgName:  "srg1",
+		Sequence: 3,
+		Action:   hapb.SyncAction_SYNC_ACTION_DELETE,
+		Mapping:  mappingToCheckpoint("srg1", &forward),
+	})
+	require.NoError(t, err)
+	assert.False(t, store.has(opdb.NamespaceHASyncedCGNAT, key))
+	assert.True(t, store.has(opdb.NamespaceHASyncedCGNAT, "s1"))
+}
+
+func TestSyncReceiver_CGNATExtraBlockKeyedBesideSession(t *testing.T) {
+	store := newMemStore()
+	recv := NewSyncReceiver(store, nil, logger.NewTest())
+	ctx := context.Background()
+
+	first := &models.CGNATMapping{
+		SessionID:      "s1",
+		PoolName:       "p1",
+		InsideIP:       net.ParseIP("100.64.0.10"),
+		OutsideIP:      net.ParseIP("198.51.100.1"),
+		PortBlockStart: 2048,
+		PortBlockEnd:   2111,
+	}
+	extra := *first
+	extra.PortBlockStart, extra.PortBlockEnd = 2112, 2175
+	extra.Extra = true
+
+	for i, m := range []*models.CGNATMapping{first, &extra} {
+		_, err := recv.HandleSyncCGNATMapping(ctx, &hapb.SyncCGNATMappingRequest{
+			SrgName:  "srg1",
+			Sequence: uint64(i + 1),
+			Action:   hapb.SyncAction_SYNC_ACTION_CREATE,
+			Mapping:  mappingToCheckpoint("srg1", m),
+		})
+		require.NoError(t, err)
+	}
+
+	key := "s1/block/198.51.100.1/2112"
+	require.True(t, store.has(opdb.NamespaceHASyncedCGNAT, key))
+	got, err := DecodeCGNATCheckpoint(store.data[opdb.NamespaceHASyncedCGNAT][key])
+	require.NoError(t, err)
+	assert.True(t, got.Extra)
+	assert.Equal(t, uint16(2112), got.PortBlockStart)
+
+	// The extra block must not overwrite the session's first block.
+	got, err = DecodeCGNATCheckpoint(store.data[opdb.NamespaceHASyncedCGNAT]["s1"])
+	require.NoError(t, err)
+	assert.False(t, got.Extra)
+	assert.Equal(t, uint16(2048), got.PortBlockStart)
+
+	_, err = recv.HandleSyncCGNATMapping(ctx, &hapb.SyncCGNATMappingRequest{
+		SrgName:  "srg1",
+		Sequence: 3,
+		Action:   hapb.SyncAction_SYNC_ACTION_DELETE,
+		Mapping:  mappingToCheckpoint("srg1", &extra),
+	})
+	require.NoError(t, err)
+	assert.False(t, store.has(opdb.NamespaceHASyncedCGNAT, key))
+	assert.True(t, store.has(opdb.NamespaceHASyncedCGNAT, "s1"))
+}
+
 func TestSyncReceiver_SequenceTracking(t *testing.T) {
 	store := newMemStore()
 	recv := NewSyncReceiver(store, nil, logger.NewTest())
@@ -0,0 +1,170 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf/paths"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+func init() {
+	conf.RegisterFactory(NewAccessListHandler)
+}
+
+// AccessListHandler programs one named ACL into the dataplane.
+//
+// A changed list is replaced in place rather than deleted and recreated,
+// so sessions bound to it switch to the new rules in one step and the
+// ACL keeps its index. An ACL found from an earlier run is adopted by
+// name rather than created again, so a restart keeps the indexes the
+// dataplane's sessions are bound to; internal/aclindex keeps them the
+// same after the dataplane loses its state and on the HA peer.
+type AccessListHandler struct {
+	southbound southbound.ACL
+}
+
+func NewAccessListHandler(d *deps.ConfDeps) conf.Handler {
+	return &AccessListHandler{southbound: d.Southbound}
+}
+
+func (h *AccessListHandler) extractName(path string) (string, error) {
+	values, err := paths.AccessLists.ExtractWildcards(path, 1)
+	if err != nil {
+		return "", fmt.Errorf("extract access-list name from path: %w", err)
+	}
+	return values[0], nil
+}
+
+func (h *AccessListHandler) Validate(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		// Deleting a list a service group or QoS class still uses would
+		// leave its sessions unable to come up.
+		if hctx.Config != nil {
+			if refs := hctx.Config.ACLReferences(name); len(refs) > 0 {
+				sort.Strings(refs)
+				return fmt.Errorf("access-list %q is used by %s", name, strings.Join(refs, ", "))
+			}
+		}
+		return nil
+	}
+
+	cfg, ok := hctx.NewValue.(*aclcfg.AccessList)
+	if !ok {
+		return fmt.Errorf("expected *acl.AccessList, got %T", hctx.NewValue)
+	}
+	return cfg.Validate(name)
+}
+
+func (h *AccessListHandler) Apply(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		return h.southbound.DeleteACL(name)
+	}
+
+	cfg, ok := hctx.NewValue.(*aclcfg.AccessList)
+	if !ok {
+		return fmt.Errorf("expected *acl.AccessList, got %T", hctx.NewValue)
+	}
+
+	// Entries of one section commit in map order. Creating every list
+	// that sorts before this one first keeps the creation order, and so
+	// the indexes of a fresh dataplane, independent of map order; the
+	// earlier lists' own changes then replace them with the same rules.
+	if hctx.OldValue == nil && hctx.Config != nil {
+		for _, other := range sortedNames(hctx.Config.AccessLists) {
+			if other >= name {
+				break
+			}
+			if err := h.program(other, hctx.Config.AccessLists[other]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return h.program(name, cfg)
+}
+
+func (h *AccessListHandler) Rollback(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.OldValue == nil {
+		return h.southbound.DeleteACL(name)
+	}
+
+	old, ok := hctx.OldValue.(*aclcfg.AccessList)
+	if !ok {
+		return nil
+	}
+	return h.program(name, old)
+}
+
+func (h *AccessListHandler) program(name string, cfg *aclcfg.AccessList) error {
+	if cfg == nil {
+		return nil
+	}
+	entries, _, err := cfg.Expand()
+	if err != nil {
+		return fmt.Errorf("access-list %q: %w", name, err)
+	}
+	if _, err := h.southbound.AddReplaceACL(name, entries); err != nil {
+		return fmt.Errorf("access-list %q: %w", name, err)
+	}
+	return nil
+}
+
+func sortedNames(lists map[string]*aclcfg.AccessList) []string {
+	names := make([]string, 0, len(lists))
+	for name, list := range lists {
+		if list != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (h *AccessListHandler) PathPattern() paths.Path {
+	return paths.AccessLists
+}
+
+func (h *AccessListHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *AccessListHandler) Callbacks() *conf.Callbacks {
+	return nil
+}
+
+func (h *AccessListHandler) Summary() string {
+	return "Access list"
+}
+
+func (h *AccessListHandler) Description() string {
+	return "Define a named IPv4/IPv6 access list that service groups and AAA attach to subscriber sessions."
+}
+
+func (h *AccessListHandler) ValueType() interface{} {
+	return &aclcfg.AccessList{}
+}
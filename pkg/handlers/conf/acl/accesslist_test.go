@@ -0,0 +1,103 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type fakeACL struct {
+	southbound.ACL
+	calls []string
+}
+
+func (f *fakeACL) AddReplaceACL(name string, entries []aclcfg.Entry) (uint32, error) {
+	f.calls = append(f.calls, fmt.Sprintf("add %s %d", name, len(entries)))
+	return 0, nil
+}
+
+func (f *fakeACL) DeleteACL(name string) error {
+	f.calls = append(f.calls, "del "+name)
+	return nil
+}
+
+func list(rules ...aclcfg.Rule) *aclcfg.AccessList {
+	return &aclcfg.AccessList{Rules: rules}
+}
+
+func TestApplyCreatesInNameOrder(t *testing.T) {
+	sb := &fakeACL{}
+	h := &AccessListHandler{southbound: sb}
+	cfg := &config.Config{AccessLists: map[string]*aclcfg.AccessList{
+		"a": list(aclcfg.Rule{Action: aclcfg.ActionDeny}),
+		"b": list(aclcfg.Rule{Action: aclcfg.ActionPermit, Protocol: "tcp", Family: aclcfg.FamilyIPv4}),
+		"c": list(aclcfg.Rule{Action: aclcfg.ActionPermit}),
+	}}
+
+	err := h.Apply(context.Background(), &conf.HandlerContext{
+		Path: "access-lists.c", NewValue: cfg.AccessLists["c"], Config: cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(sb.calls, ", "); got != "add a 2, add b 1, add c 2" {
+		t.Fatalf("calls = %s", got)
+	}
+
+	// A change to an existing list is a replace of that list alone.
+	sb.calls = nil
+	err = h.Apply(context.Background(), &conf.HandlerContext{
+		Path: "access-lists.c", OldValue: cfg.AccessLists["c"], NewValue: cfg.AccessLists["b"], Config: cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(sb.calls, ", "); got != "add c 1" {
+		t.Fatalf("calls = %s", got)
+	}
+}
+
+func TestValidateRefusesDeletingAttachedList(t *testing.T) {
+	h := &AccessListHandler{southbound: &fakeACL{}}
+	cfg := &config.Config{
+		ServiceGroups: map[string]*servicegroup.Config{
+			"res": {ACL: &servicegroup.ACLConfig{Ingress: "web"}},
+		},
+		QoSPolicies: map[string]*qos.Policy{
+			"up": {Classes: []qos.Class{{Name: "sip", Match: qos.ClassMatch{AccessList: "sip"}}}},
+		},
+	}
+
+	err := h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "access-lists.web", OldValue: list(aclcfg.Rule{Action: aclcfg.ActionDeny}), Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "service-groups.res") {
+		t.Fatalf("err = %v", err)
+	}
+
+	err = h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "access-lists.sip", OldValue: list(aclcfg.Rule{Action: aclcfg.ActionDeny}), Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "qos-policies.up.classes.sip") {
+		t.Fatalf("err = %v", err)
+	}
+
+	err = h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "access-lists.other", OldValue: list(aclcfg.Rule{Action: aclcfg.ActionDeny}), Config: cfg,
+	})
+	if err != nil {
+		t.Fatalf("unreferenced delete refused: %v", err)
+	}
+}
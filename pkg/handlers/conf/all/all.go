@@ -1,6 +1,7 @@
 package all
 
 import (
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/acl"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/interface"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/interface/subinterfaces"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/internal"
@@ -14,7 +15,9 @@ import (
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/protocols/static"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/qos"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/routing_policy"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/schedules"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/servicegroups"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/steering"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/system"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/conf/vrfs"
 )
@@ -43,8 +43,10 @@ func (h *L2GWHandler) Validate(_ context.Context, hctx *conf.HandlerContext) err
 	}
 	if hctx.Config != nil {
 		for name, hg := range cfg.HandoffGroups {
-			if _, ok := hctx.Config.Interfaces[hg.Interface]; !ok {
-				return fmt.Errorf("l2gw handoff-group %q: interface %q is not defined in interfaces", name, hg.Interface)
+			for _, ifName := range hg.MemberInterfaces() {
+				if _, ok := hctx.Config.Interfaces[ifName]; !ok {
+					return fmt.Errorf("l2gw handoff-group %q: interface %q is not defined in interfaces", name, ifName)
+				}
 			}
 		}
 		for i, sm := range cfg.StaticMaps {
@@ -9,7 +9,11 @@ type Path string
 const (
 	L2GW                        Path = "l2gw"
 	ServiceGroups               Path = "service-groups.<*>"
+	AccessLists                 Path = "access-lists.<*>"
+	Schedules                   Path = "schedules.<*>"
+	SteeringPolicies            Path = "steering-policies.<*>"
 	QoSAggregate                Path = "qos-aggregates.<*>"
+	MarkingMaps                 Path = "marking-maps.<*>"
 	VRFS                        Path = "vrfs.<*>"
 	VRFSName                    Path = "vrfs.<*>.name"
 	VRFSDescription             Path = "vrfs.<*>.description"
@@ -0,0 +1,147 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	qoscfg "github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf/paths"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+func init() {
+	conf.RegisterFactory(NewMarkingMapHandler)
+}
+
+// MarkingMapHandler programs one named marking map into the dataplane.
+// Sessions name the map in their marking; a changed PCP map re-marks
+// them at once, a changed remark map when their bindings are next
+// applied.
+type MarkingMapHandler struct {
+	southbound southbound.Marking
+}
+
+func NewMarkingMapHandler(d *deps.ConfDeps) conf.Handler {
+	return &MarkingMapHandler{southbound: d.Southbound}
+}
+
+func (h *MarkingMapHandler) extractName(path string) (string, error) {
+	values, err := paths.MarkingMaps.ExtractWildcards(path, 1)
+	if err != nil {
+		return "", fmt.Errorf("extract marking-map name from path: %w", err)
+	}
+	return values[0], nil
+}
+
+func (h *MarkingMapHandler) Validate(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		if hctx.Config != nil {
+			if refs := hctx.Config.MarkingReferences(name); len(refs) > 0 {
+				sort.Strings(refs)
+				return fmt.Errorf("marking-map %q is used by %s", name, strings.Join(refs, ", "))
+			}
+		}
+		return nil
+	}
+
+	cfg, ok := hctx.NewValue.(*qoscfg.MarkingMap)
+	if !ok {
+		return fmt.Errorf("expected *qos.MarkingMap, got %T", hctx.NewValue)
+	}
+	if err := cfg.Validate(name); err != nil {
+		return err
+	}
+
+	// A changed map has to suit the groups already using it: the right
+	// kind, and a remark their ingress classes can follow.
+	if hctx.Config != nil {
+		for sgName, sg := range hctx.Config.ServiceGroups {
+			if sg == nil || sg.Marking == nil || (sg.Marking.IngressMap != name && sg.Marking.EgressPCPMap != name) {
+				continue
+			}
+			if err := hctx.Config.CheckMarking(sg); err != nil {
+				return fmt.Errorf("service-groups.%s.marking: %w", sgName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (h *MarkingMapHandler) Apply(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		return h.southbound.DeleteMarkingMap(name)
+	}
+
+	cfg, ok := hctx.NewValue.(*qoscfg.MarkingMap)
+	if !ok {
+		return fmt.Errorf("expected *qos.MarkingMap, got %T", hctx.NewValue)
+	}
+	return h.program(name, cfg)
+}
+
+func (h *MarkingMapHandler) Rollback(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.OldValue == nil {
+		return h.southbound.DeleteMarkingMap(name)
+	}
+
+	old, ok := hctx.OldValue.(*qoscfg.MarkingMap)
+	if !ok {
+		return nil
+	}
+	return h.program(name, old)
+}
+
+func (h *MarkingMapHandler) program(name string, cfg *qoscfg.MarkingMap) error {
+	table, err := cfg.Table()
+	if err != nil {
+		return fmt.Errorf("marking-map %q: %w", name, err)
+	}
+	return h.southbound.SetMarkingMap(name, cfg.IsPCP(), table)
+}
+
+func (h *MarkingMapHandler) PathPattern() paths.Path {
+	return paths.MarkingMaps
+}
+
+func (h *MarkingMapHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *MarkingMapHandler) Callbacks() *conf.Callbacks {
+	return nil
+}
+
+func (h *MarkingMapHandler) Summary() string {
+	return "DSCP and 802.1p marking map"
+}
+
+func (h *MarkingMapHandler) Description() string {
+	return "Define a named map from DSCP to a new DSCP or to an 802.1p priority that service groups and AAA use to mark subscriber sessions."
+}
+
+func (h *MarkingMapHandler) ValueType() interface{} {
+	return &qoscfg.MarkingMap{}
+}
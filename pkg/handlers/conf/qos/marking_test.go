@@ -0,0 +1,43 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	qoscfg "github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+)
+
+func TestMarkingMapValidate(t *testing.T) {
+	h := &MarkingMapHandler{}
+	pcp := &qoscfg.MarkingMap{PCP: map[string]uint8{"ef": 5}}
+	cfg := &config.Config{
+		MarkingMaps: map[string]*qoscfg.MarkingMap{"access": pcp},
+		ServiceGroups: map[string]*servicegroup.Config{"res": {
+			Marking: &qoscfg.Marking{EgressPCPMap: "access"},
+		}},
+	}
+
+	err := h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "marking-maps.access", OldValue: pcp, Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "service-groups.res") {
+		t.Fatalf("deleting used map: err = %v", err)
+	}
+
+	remark := &qoscfg.MarkingMap{DSCP: map[string]string{"ef": "be"}}
+	cfg.MarkingMaps["access"] = remark
+	err = h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "marking-maps.access", OldValue: pcp, NewValue: remark, Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "maps dscp, not pcp") {
+		t.Fatalf("changing the kind of a used map: err = %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package schedules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf/paths"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+func init() {
+	conf.RegisterFactory(NewScheduleHandler)
+}
+
+// ScheduleHandler registers a named schedule with the service group
+// resolver. Nothing is programmed here: the subscriber component notices
+// a schedule whose state changed and moves the affected sessions over.
+type ScheduleHandler struct {
+	resolver *svcgroup.Resolver
+}
+
+func NewScheduleHandler(d *deps.ConfDeps) conf.Handler {
+	return &ScheduleHandler{resolver: d.SvcGroupResolver}
+}
+
+func (h *ScheduleHandler) extractName(path string) (string, error) {
+	values, err := paths.Schedules.ExtractWildcards(path, 1)
+	if err != nil {
+		return "", fmt.Errorf("extract schedule name from path: %w", err)
+	}
+	return values[0], nil
+}
+
+func (h *ScheduleHandler) Validate(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		if hctx.Config != nil {
+			if refs := hctx.Config.ScheduleReferences(name); len(refs) > 0 {
+				sort.Strings(refs)
+				return fmt.Errorf("schedule %q is used by %s", name, strings.Join(refs, ", "))
+			}
+		}
+		return nil
+	}
+
+	cfg, ok := hctx.NewValue.(*schedule.Schedule)
+	if !ok {
+		return fmt.Errorf("expected *schedule.Schedule, got %T", hctx.NewValue)
+	}
+	return cfg.Validate(name)
+}
+
+func (h *ScheduleHandler) Apply(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		h.resolver.DeleteSchedule(name)
+		return nil
+	}
+
+	cfg, ok := hctx.NewValue.(*schedule.Schedule)
+	if !ok {
+		return fmt.Errorf("expected *schedule.Schedule, got %T", hctx.NewValue)
+	}
+
+	h.resolver.SetSchedule(name, cfg)
+	return nil
+}
+
+func (h *ScheduleHandler) Rollback(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.OldValue == nil {
+		h.resolver.DeleteSchedule(name)
+		return nil
+	}
+
+	cfg, ok := hctx.OldValue.(*schedule.Schedule)
+	if !ok {
+		return nil
+	}
+
+	h.resolver.SetSchedule(name, cfg)
+	return nil
+}
+
+func (h *ScheduleHandler) PathPattern() paths.Path {
+	return paths.Schedules
+}
+
+func (h *ScheduleHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ScheduleHandler) Callbacks() *conf.Callbacks {
+	return nil
+}
+
+func (h *ScheduleHandler) Summary() string {
+	return "Service schedule configuration"
+}
+
+func (h *ScheduleHandler) Description() string {
+	return "Configure a named set of weekly time windows that service groups and QoS policies switch on."
+}
+
+func (h *ScheduleHandler) ValueType() interface{} {
+	return &schedule.Schedule{}
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package schedules
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/svcgroup"
+)
+
+func TestValidateRefusesDeletingUsedSchedule(t *testing.T) {
+	h := &ScheduleHandler{resolver: svcgroup.New()}
+	cfg := &config.Config{
+		ServiceGroups: map[string]*servicegroup.Config{
+			"res": {Schedules: []servicegroup.ScheduleConfig{{Schedule: "night"}}},
+		},
+	}
+	old := &schedule.Schedule{Windows: []schedule.Window{{Start: "00:00", End: "06:00"}}}
+
+	err := h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "schedules.night", OldValue: old, Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "service-groups.res") {
+		t.Fatalf("err = %v", err)
+	}
+
+	err = h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "schedules.weekend", OldValue: old, Config: cfg,
+	})
+	if err != nil {
+		t.Fatalf("unreferenced delete refused: %v", err)
+	}
+}
+
+func TestApplyRegistersWithResolver(t *testing.T) {
+	r := svcgroup.New()
+	h := &ScheduleHandler{resolver: r}
+	always := &schedule.Schedule{Windows: []schedule.Window{{Start: "00:00", End: "00:00"}}}
+
+	if err := h.Apply(context.Background(), &conf.HandlerContext{Path: "schedules.always", NewValue: always}); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.ActiveSchedules(time.Now()); len(got) != 1 || got[0] != "always" {
+		t.Fatalf("active = %v", got)
+	}
+
+	if err := h.Apply(context.Background(), &conf.HandlerContext{Path: "schedules.always", OldValue: always}); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.ActiveSchedules(time.Now()); len(got) != 0 {
+		t.Fatalf("active after delete = %v", got)
+	}
+}
@@ -32,7 +32,7 @@ func (h *ServiceGroupHandler) extractName(path string) (string, error) {
 }
 
 func (h *ServiceGroupHandler) Validate(ctx context.Context, hctx *conf.HandlerContext) error {
-	_, err := h.extractName(hctx.Path)
+	name, err := h.extractName(hctx.Path)
 	if err != nil {
 		return err
 	}
@@ -41,10 +41,49 @@ func (h *ServiceGroupHandler) Validate(ctx context.Context, hctx *conf.HandlerCo
 		return nil
 	}
 
-	if _, ok := hctx.NewValue.(*servicegroup.Config); !ok {
+	cfg, ok := hctx.NewValue.(*servicegroup.Config)
+	if !ok {
 		return fmt.Errorf("expected *servicegroup.Config, got %T", hctx.NewValue)
 	}
 
+	if cfg.ACL != nil && hctx.Config != nil {
+		if err := hctx.Config.CheckACLReference(cfg.ACL.Ingress); err != nil {
+			return fmt.Errorf("service group %q: acl.ingress: %w", name, err)
+		}
+		if err := hctx.Config.CheckACLReference(cfg.ACL.Egress); err != nil {
+			return fmt.Errorf("service group %q: acl.egress: %w", name, err)
+		}
+	}
+
+	if cfg.QoS != nil {
+		if err := cfg.QoS.LineRate.Validate(); err != nil {
+			return fmt.Errorf("service group %q: qos.line-rate: %w", name, err)
+		}
+	}
+
+	if hctx.Config != nil {
+		if err := hctx.Config.CheckSteeringReference(cfg.Steering); err != nil {
+			return fmt.Errorf("service group %q: steering-policy: %w", name, err)
+		}
+		if err := hctx.Config.CheckMarking(cfg); err != nil {
+			return fmt.Errorf("service group %q: marking: %w", name, err)
+		}
+		for i, s := range cfg.Schedules {
+			if err := hctx.Config.CheckScheduleReference(s.Schedule); err != nil {
+				return fmt.Errorf("service group %q: schedules[%d]: %w", name, i, err)
+			}
+			if s.ACL == nil {
+				continue
+			}
+			if err := hctx.Config.CheckACLReference(s.ACL.Ingress); err != nil {
+				return fmt.Errorf("service group %q: schedules[%d].acl.ingress: %w", name, i, err)
+			}
+			if err := hctx.Config.CheckACLReference(s.ACL.Egress); err != nil {
+				return fmt.Errorf("service group %q: schedules[%d].acl.egress: %w", name, i, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,96 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	steeringcfg "github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf/paths"
+)
+
+func init() {
+	conf.RegisterFactory(NewSteeringPolicyHandler)
+}
+
+// SteeringPolicyHandler validates a named steering policy. Nothing is
+// programmed here: the steering component follows the running config,
+// probes the next hops and programs the policy with those that answer.
+type SteeringPolicyHandler struct{}
+
+func NewSteeringPolicyHandler(d *deps.ConfDeps) conf.Handler {
+	return &SteeringPolicyHandler{}
+}
+
+func (h *SteeringPolicyHandler) extractName(path string) (string, error) {
+	values, err := paths.SteeringPolicies.ExtractWildcards(path, 1)
+	if err != nil {
+		return "", fmt.Errorf("extract steering policy name from path: %w", err)
+	}
+	return values[0], nil
+}
+
+func (h *SteeringPolicyHandler) Validate(ctx context.Context, hctx *conf.HandlerContext) error {
+	name, err := h.extractName(hctx.Path)
+	if err != nil {
+		return err
+	}
+
+	if hctx.NewValue == nil {
+		if hctx.Config != nil {
+			if refs := hctx.Config.SteeringReferences(name); len(refs) > 0 {
+				sort.Strings(refs)
+				return fmt.Errorf("steering-policy %q is used by %s", name, strings.Join(refs, ", "))
+			}
+		}
+		return nil
+	}
+
+	cfg, ok := hctx.NewValue.(*steeringcfg.Policy)
+	if !ok {
+		return fmt.Errorf("expected *steering.Policy, got %T", hctx.NewValue)
+	}
+	if hctx.Config == nil {
+		return cfg.Validate(name)
+	}
+	return hctx.Config.CheckSteeringPolicy(name, cfg)
+}
+
+func (h *SteeringPolicyHandler) Apply(ctx context.Context, hctx *conf.HandlerContext) error {
+	return nil
+}
+
+func (h *SteeringPolicyHandler) Rollback(ctx context.Context, hctx *conf.HandlerContext) error {
+	return nil
+}
+
+func (h *SteeringPolicyHandler) PathPattern() paths.Path {
+	return paths.SteeringPolicies
+}
+
+func (h *SteeringPolicyHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *SteeringPolicyHandler) Callbacks() *conf.Callbacks {
+	return nil
+}
+
+func (h *SteeringPolicyHandler) Summary() string {
+	return "Traffic steering policy"
+}
+
+func (h *SteeringPolicyHandler) Description() string {
+	return "Define a policy that forwards the subscriber traffic an access list matches to health-checked next hops, failing open when none answer."
+}
+
+func (h *SteeringPolicyHandler) ValueType() interface{} {
+	return &steeringcfg.Policy{}
+}
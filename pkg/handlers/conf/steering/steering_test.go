@@ -0,0 +1,38 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
+	steeringcfg "github.com/veesix-networks/osvbng/pkg/config/steering"
+	"github.com/veesix-networks/osvbng/pkg/handlers/conf"
+)
+
+func TestValidate(t *testing.T) {
+	h := &SteeringPolicyHandler{}
+	cfg := &config.Config{
+		ServiceGroups: map[string]*servicegroup.Config{"res": {Steering: "dpi"}},
+	}
+	policy := &steeringcfg.Policy{AccessList: "web", NextHops: []steeringcfg.NextHop{{Address: "192.0.2.1"}}}
+
+	err := h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "steering-policies.dpi", OldValue: policy, Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), "service-groups.res") {
+		t.Fatalf("deleting used policy: err = %v", err)
+	}
+
+	err = h.Validate(context.Background(), &conf.HandlerContext{
+		Path: "steering-policies.dpi", NewValue: policy, Config: cfg,
+	})
+	if err == nil || !strings.Contains(err.Error(), `access-list "web" is not defined`) {
+		t.Fatalf("undefined access list: err = %v", err)
+	}
+}
@@ -7,7 +7,9 @@ package all
 import (
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/cgnat"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/ha"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/l2tp"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/qos"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/rtbh"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/subscriber"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/oper/system"
 )
@@ -0,0 +1,87 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &ArchiveLookupHandler{deps: d}
+	})
+}
+
+type ArchiveLookupHandler struct {
+	deps *deps.OperDeps
+}
+
+type ArchiveLookupRequest struct {
+	OutsideIP   string `json:"outside_ip"`
+	OutsidePort uint16 `json:"outside_port"`
+	Time        string `json:"time,omitempty" description:"RFC 3339 timestamp; defaults to now."`
+}
+
+func (h *ArchiveLookupHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+
+	var lookupReq ArchiveLookupRequest
+	if err := json.Unmarshal(req.Body, &lookupReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	ip := net.ParseIP(lookupReq.OutsideIP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid outside_ip: %s", lookupReq.OutsideIP)
+	}
+	if lookupReq.OutsidePort == 0 {
+		return nil, fmt.Errorf("outside_port is required")
+	}
+	at := time.Now()
+	if lookupReq.Time != "" {
+		t, err := time.Parse(time.RFC3339, lookupReq.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time: %w", err)
+		}
+		at = t
+	}
+
+	return h.deps.CGNAT.LookupMappingOwner(ip, lookupReq.OutsidePort, at)
+}
+
+func (h *ArchiveLookupHandler) PathPattern() paths.Path {
+	return paths.CGNATArchiveLookup
+}
+
+func (h *ArchiveLookupHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ArchiveLookupHandler) Summary() string {
+	return "Find who held a CGNAT outside address and port at a given time"
+}
+
+func (h *ArchiveLookupHandler) Description() string {
+	return "Resolve an outside IP, port and timestamp to the subscriber session, username, MAC and inside IP, from the mapping archive or, for deterministic pools, from the pool layout."
+}
+
+func (h *ArchiveLookupHandler) InputType() interface{} {
+	return &ArchiveLookupRequest{}
+}
+
+func (h *ArchiveLookupHandler) OutputType() interface{} {
+	return &models.CGNATMappingOwner{}
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PoolAddressAddHandler{deps: d}
+	})
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PoolAddressDrainHandler{deps: d}
+	})
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PoolAddressUndrainHandler{deps: d}
+	})
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PoolAddressRemoveHandler{deps: d}
+	})
+}
+
+type PoolAddressRequest struct {
+	Pool    string `json:"pool"`
+	Address string `json:"address"`
+}
+
+type PoolAddressDrainRequest struct {
+	Pool    string `json:"pool"`
+	Address string `json:"address"`
+	// MigrateAfter is how long subscribers keep their blocks on the
+	// draining addresses before they are moved, e.g. "4h". Empty waits
+	// for them to disconnect.
+	MigrateAfter string `json:"migrate_after,omitempty"`
+}
+
+type PoolAddressRemoveResponse struct {
+	Removed bool `json:"removed"`
+}
+
+func decodePoolAddress(req *oper.Request) (*PoolAddressRequest, error) {
+	var r PoolAddressRequest
+	if err := json.Unmarshal(req.Body, &r); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if r.Pool == "" || r.Address == "" {
+		return nil, fmt.Errorf("pool and address are required")
+	}
+	return &r, nil
+}
+
+type PoolAddressAddHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *PoolAddressAddHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+	r, err := decodePoolAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	return h.deps.CGNAT.AddPoolAddress(ctx, r.Pool, r.Address)
+}
+
+func (h *PoolAddressAddHandler) PathPattern() paths.Path {
+	return paths.CGNATPoolAddressAdd
+}
+
+func (h *PoolAddressAddHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoolAddressAddHandler) Summary() string {
+	return "Add an outside address to a running CGNAT pool"
+}
+
+func (h *PoolAddressAddHandler) Description() string {
+	return "Add an IPv4 outside address or prefix to a PBA pool without a restart. New blocks are taken from it straight away; it is kept across restarts until it is added to the pool's configuration."
+}
+
+func (h *PoolAddressAddHandler) InputType() interface{} {
+	return &PoolAddressRequest{}
+}
+
+func (h *PoolAddressAddHandler) OutputType() interface{} {
+	return []models.CGNATOutsideAddress{}
+}
+
+type PoolAddressDrainHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *PoolAddressDrainHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+
+	var r PoolAddressDrainRequest
+	if err := json.Unmarshal(req.Body, &r); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if r.Pool == "" || r.Address == "" {
+		return nil, fmt.Errorf("pool and address are required")
+	}
+	var migrateAfter time.Duration
+	if r.MigrateAfter != "" {
+		d, err := time.ParseDuration(r.MigrateAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migrate_after: %w", err)
+		}
+		migrateAfter = d
+	}
+
+	return h.deps.CGNAT.DrainPoolAddress(ctx, r.Pool, r.Address, migrateAfter)
+}
+
+func (h *PoolAddressDrainHandler) PathPattern() paths.Path {
+	return paths.CGNATPoolAddressDrain
+}
+
+func (h *PoolAddressDrainHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoolAddressDrainHandler) Summary() string {
+	return "Drain outside addresses of a CGNAT pool"
+}
+
+func (h *PoolAddressDrainHandler) Description() string {
+	return "Stop the pool's outside addresses within an address or prefix taking new blocks. Their blocks are released as subscribers disconnect or, after migrate_after, moved to other addresses of the pool, dropping the subscribers' translations on them."
+}
+
+func (h *PoolAddressDrainHandler) InputType() interface{} {
+	return &PoolAddressDrainRequest{}
+}
+
+func (h *PoolAddressDrainHandler) OutputType() interface{} {
+	return []models.CGNATOutsideAddress{}
+}
+
+type PoolAddressUndrainHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *PoolAddressUndrainHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+	r, err := decodePoolAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	return h.deps.CGNAT.UndrainPoolAddress(ctx, r.Pool, r.Address)
+}
+
+func (h *PoolAddressUndrainHandler) PathPattern() paths.Path {
+	return paths.CGNATPoolAddressUndrain
+}
+
+func (h *PoolAddressUndrainHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoolAddressUndrainHandler) Summary() string {
+	return "Cancel the drain of CGNAT outside addresses"
+}
+
+func (h *PoolAddressUndrainHandler) Description() string {
+	return "Let drained or draining outside addresses take new blocks again. The address or prefix must cover every address the drain was started on."
+}
+
+func (h *PoolAddressUndrainHandler) InputType() interface{} {
+	return &PoolAddressRequest{}
+}
+
+func (h *PoolAddressUndrainHandler) OutputType() interface{} {
+	return []models.CGNATOutsideAddress{}
+}
+
+type PoolAddressRemoveHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *PoolAddressRemoveHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+	r, err := decodePoolAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.deps.CGNAT.RemovePoolAddress(ctx, r.Pool, r.Address); err != nil {
+		return nil, err
+	}
+	return &PoolAddressRemoveResponse{Removed: true}, nil
+}
+
+func (h *PoolAddressRemoveHandler) PathPattern() paths.Path {
+	return paths.CGNATPoolAddressRemove
+}
+
+func (h *PoolAddressRemoveHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoolAddressRemoveHandler) Summary() string {
+	return "Remove a drained outside address from a CGNAT pool"
+}
+
+func (h *PoolAddressRemoveHandler) Description() string {
+	return "Remove one of a PBA pool's outside address entries once every address in it has drained. The removal is kept across restarts until the entry is taken out of the pool's configuration."
+}
+
+func (h *PoolAddressRemoveHandler) InputType() interface{} {
+	return &PoolAddressRequest{}
+}
+
+func (h *PoolAddressRemoveHandler) OutputType() interface{} {
+	return &PoolAddressRemoveResponse{}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PortForwardAddHandler{deps: d}
+	})
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &PortForwardDeleteHandler{deps: d}
+	})
+}
+
+type PortForwardAddHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *PortForwardAddHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+
+	var fwd models.CGNATPortForwardRequest
+	if err := json.Unmarshal(req.Body, &fwd); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	return h.deps.CGNAT.AddPortForward(ctx, &fwd)
+}
+
+func (h *PortForwardAddHandler) PathPattern() paths.Path {
+	return paths.CGNATPortForwardAdd
+}
+
+func (h *PortForwardAddHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PortForwardAddHandler) Summary() string {
+	return "Add a CGNAT port forward"
+}
+
+func (h *PortForwardAddHandler) Description() string {
+	return "Forward an outside address and port to a subscriber's inside address and port. The outside port must be in a port block the subscriber holds and not in use by a live translation."
+}
+
+func (h *PortForwardAddHandler) InputType() interface{} {
+	return &models.CGNATPortForwardRequest{}
+}
+
+func (h *PortForwardAddHandler) OutputType() interface{} {
+	return &models.CGNATMapping{}
+}
+
+type PortForwardDeleteHandler struct {
+	deps *deps.OperDeps
+}
+
+type PortForwardDeleteRequest struct {
+	Protocol   string `json:"protocol"`
+	InsideIP   string `json:"inside_ip"`
+	InsidePort uint16 `json:"inside_port"`
+}
+
+type PortForwardDeleteResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+func (h *PortForwardDeleteHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+
+	var delReq PortForwardDeleteRequest
+	if err := json.Unmarshal(req.Body, &delReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	ip := net.ParseIP(delReq.InsideIP)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid inside_ip")
+	}
+
+	if err := h.deps.CGNAT.DeletePortForward(ctx, delReq.Protocol, ip, delReq.InsidePort); err != nil {
+		return nil, err
+	}
+	return &PortForwardDeleteResponse{Deleted: true}, nil
+}
+
+func (h *PortForwardDeleteHandler) PathPattern() paths.Path {
+	return paths.CGNATPortForwardDelete
+}
+
+func (h *PortForwardDeleteHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PortForwardDeleteHandler) Summary() string {
+	return "Delete a CGNAT port forward"
+}
+
+func (h *PortForwardDeleteHandler) Description() string {
+	return "Remove an API or PCP port forward by its protocol and inside address and port. Static forwards are removed from the configuration instead."
+}
+
+func (h *PortForwardDeleteHandler) InputType() interface{} {
+	return &PortForwardDeleteRequest{}
+}
+
+func (h *PortForwardDeleteHandler) OutputType() interface{} {
+	return &PortForwardDeleteResponse{}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+)
+
+func init() {
+	oper.RegisterFactory(NewClearSessionHandler)
+}
+
+type ClearSessionRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+type ClearSessionResponse struct {
+	SessionID string `json:"session_id"`
+}
+
+type ClearSessionHandler struct {
+	deps *deps.OperDeps
+}
+
+func NewClearSessionHandler(deps *deps.OperDeps) oper.OperHandler {
+	return &ClearSessionHandler{deps: deps}
+}
+
+func (h *ClearSessionHandler) Execute(_ context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.L2TP == nil {
+		return nil, fmt.Errorf("l2tp component not available")
+	}
+
+	var body ClearSessionRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	if err := h.deps.L2TP.ClearSession(body.SessionID); err != nil {
+		return nil, err
+	}
+	return &ClearSessionResponse{SessionID: body.SessionID}, nil
+}
+
+func (h *ClearSessionHandler) PathPattern() paths.Path {
+	return paths.L2TPSessionClear
+}
+
+func (h *ClearSessionHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ClearSessionHandler) Summary() string {
+	return "Clear an L2TPv2 session"
+}
+
+func (h *ClearSessionHandler) Description() string {
+	return "Send CDN for one session (ID as listed by 'show l2tp sessions') and tear it down. The tunnel stays up."
+}
+
+func (h *ClearSessionHandler) InputType() interface{} {
+	return &ClearSessionRequest{}
+}
+
+func (h *ClearSessionHandler) OutputType() interface{} {
+	return &ClearSessionResponse{}
+}
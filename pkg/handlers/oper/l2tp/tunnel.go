@@ -0,0 +1,140 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+)
+
+func init() {
+	oper.RegisterFactory(NewClearTunnelHandler)
+	oper.RegisterFactory(NewHelloHandler)
+}
+
+// TunnelRequest identifies a tunnel the way `show l2tp tunnels` lists
+// it: peer address plus the local tunnel ID.
+type TunnelRequest struct {
+	PeerIP   string `json:"peer_ip"`
+	TunnelID uint16 `json:"tunnel_id"`
+}
+
+type TunnelResponse struct {
+	PeerIP   string `json:"peer_ip"`
+	TunnelID uint16 `json:"tunnel_id"`
+}
+
+func parseTunnelRequest(req *oper.Request) (net.IP, *TunnelRequest, error) {
+	var body TunnelRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return nil, nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	peer := net.ParseIP(body.PeerIP)
+	if peer == nil {
+		return nil, nil, fmt.Errorf("invalid peer_ip %q", body.PeerIP)
+	}
+	if body.TunnelID == 0 {
+		return nil, nil, fmt.Errorf("tunnel_id is required")
+	}
+	return peer, &body, nil
+}
+
+type ClearTunnelHandler struct {
+	deps *deps.OperDeps
+}
+
+func NewClearTunnelHandler(deps *deps.OperDeps) oper.OperHandler {
+	return &ClearTunnelHandler{deps: deps}
+}
+
+func (h *ClearTunnelHandler) Execute(_ context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.L2TP == nil {
+		return nil, fmt.Errorf("l2tp component not available")
+	}
+	peer, body, err := parseTunnelRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.deps.L2TP.ClearTunnel(peer, body.TunnelID); err != nil {
+		return nil, err
+	}
+	return &TunnelResponse{PeerIP: peer.String(), TunnelID: body.TunnelID}, nil
+}
+
+func (h *ClearTunnelHandler) PathPattern() paths.Path {
+	return paths.L2TPTunnelClear
+}
+
+func (h *ClearTunnelHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ClearTunnelHandler) Summary() string {
+	return "Clear an L2TPv2 tunnel"
+}
+
+func (h *ClearTunnelHandler) Description() string {
+	return "Send StopCCN to the peer and tear down the tunnel and every session it carries. Sessions are accounted with Acct-Terminate-Cause Admin-Reset."
+}
+
+func (h *ClearTunnelHandler) InputType() interface{} {
+	return &TunnelRequest{}
+}
+
+func (h *ClearTunnelHandler) OutputType() interface{} {
+	return &TunnelResponse{}
+}
+
+type HelloHandler struct {
+	deps *deps.OperDeps
+}
+
+func NewHelloHandler(deps *deps.OperDeps) oper.OperHandler {
+	return &HelloHandler{deps: deps}
+}
+
+func (h *HelloHandler) Execute(_ context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.L2TP == nil {
+		return nil, fmt.Errorf("l2tp component not available")
+	}
+	peer, body, err := parseTunnelRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.deps.L2TP.SendHello(peer, body.TunnelID); err != nil {
+		return nil, err
+	}
+	return &TunnelResponse{PeerIP: peer.String(), TunnelID: body.TunnelID}, nil
+}
+
+func (h *HelloHandler) PathPattern() paths.Path {
+	return paths.L2TPTunnelHello
+}
+
+func (h *HelloHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *HelloHandler) Summary() string {
+	return "Send an L2TPv2 Hello"
+}
+
+func (h *HelloHandler) Description() string {
+	return "Queue a Hello on the tunnel's control channel outside the regular schedule. The peer's ACK and any retransmits show up in the tunnel's control statistics."
+}
+
+func (h *HelloHandler) InputType() interface{} {
+	return &TunnelRequest{}
+}
+
+func (h *HelloHandler) OutputType() interface{} {
+	return &TunnelResponse{}
+}
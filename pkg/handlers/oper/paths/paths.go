@@ -19,9 +19,23 @@ const (
 
 	HASwitchover Path = "ha.switchover"
 
-	CGNATTestMapping Path = "cgnat.test-mapping"
+	CGNATTestMapping        Path = "cgnat.test-mapping"
+	CGNATPortForwardAdd     Path = "cgnat.port-forward.add"
+	CGNATPortForwardDelete  Path = "cgnat.port-forward.delete"
+	CGNATArchiveLookup      Path = "cgnat.archive.lookup"
+	CGNATPoolAddressAdd     Path = "cgnat.pool.address.add"
+	CGNATPoolAddressDrain   Path = "cgnat.pool.address.drain"
+	CGNATPoolAddressUndrain Path = "cgnat.pool.address.undrain"
+	CGNATPoolAddressRemove  Path = "cgnat.pool.address.remove"
+
+	L2TPTunnelClear  Path = "l2tp.tunnel.clear"
+	L2TPTunnelHello  Path = "l2tp.tunnel.hello"
+	L2TPSessionClear Path = "l2tp.session.clear"
 
 	QoSSchedulerSet Path = "qos.scheduler.set"
+
+	RTBHBlackholeAdd    Path = "rtbh.blackhole.add"
+	RTBHBlackholeDelete Path = "rtbh.blackhole.delete"
 )
 
 func (p Path) String() string {
@@ -0,0 +1,119 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper"
+	"github.com/veesix-networks/osvbng/pkg/handlers/oper/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &BlackholeAddHandler{deps: d}
+	})
+	oper.RegisterFactory(func(d *deps.OperDeps) oper.OperHandler {
+		return &BlackholeDeleteHandler{deps: d}
+	})
+}
+
+type BlackholeAddHandler struct {
+	deps *deps.OperDeps
+}
+
+func (h *BlackholeAddHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.RTBH == nil {
+		return nil, fmt.Errorf("RTBH not available")
+	}
+
+	var bh models.BlackholeRequest
+	if err := json.Unmarshal(req.Body, &bh); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	return h.deps.RTBH.Add(ctx, &bh)
+}
+
+func (h *BlackholeAddHandler) PathPattern() paths.Path {
+	return paths.RTBHBlackholeAdd
+}
+
+func (h *BlackholeAddHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *BlackholeAddHandler) Summary() string {
+	return "Blackhole an address or prefix"
+}
+
+func (h *BlackholeAddHandler) Description() string {
+	return "Drop traffic toward an address or prefix in the dataplane and, with BGP, advertise it with the blackhole communities so upstream networks drop it too. The blackhole expires after the duration given, or the configured default; requesting it again replaces the expiry."
+}
+
+func (h *BlackholeAddHandler) InputType() interface{} {
+	return &models.BlackholeRequest{}
+}
+
+func (h *BlackholeAddHandler) OutputType() interface{} {
+	return &models.BlackholeStatus{}
+}
+
+type BlackholeDeleteHandler struct {
+	deps *deps.OperDeps
+}
+
+type BlackholeDeleteRequest struct {
+	Prefix string `json:"prefix"`
+	VRF    string `json:"vrf,omitempty"`
+}
+
+type BlackholeDeleteResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+func (h *BlackholeDeleteHandler) Execute(ctx context.Context, req *oper.Request) (interface{}, error) {
+	if h.deps.RTBH == nil {
+		return nil, fmt.Errorf("RTBH not available")
+	}
+
+	var delReq BlackholeDeleteRequest
+	if err := json.Unmarshal(req.Body, &delReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := h.deps.RTBH.Remove(ctx, delReq.Prefix, delReq.VRF); err != nil {
+		return nil, err
+	}
+	return &BlackholeDeleteResponse{Deleted: true}, nil
+}
+
+func (h *BlackholeDeleteHandler) PathPattern() paths.Path {
+	return paths.RTBHBlackholeDelete
+}
+
+func (h *BlackholeDeleteHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *BlackholeDeleteHandler) Summary() string {
+	return "Lift a blackhole"
+}
+
+func (h *BlackholeDeleteHandler) Description() string {
+	return "Remove the blackhole of an address or prefix before it expires, on this node and its HA peer."
+}
+
+func (h *BlackholeDeleteHandler) InputType() interface{} {
+	return &BlackholeDeleteRequest{}
+}
+
+func (h *BlackholeDeleteHandler) OutputType() interface{} {
+	return &BlackholeDeleteResponse{}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &AccessListsHandler{deps: d}
+	})
+}
+
+// AccessListsHandler reports the configured ACLs with their dataplane
+// index and per-rule hit counters. A rule that applies to both families
+// is two dataplane entries; its counters are the sum of both.
+type AccessListsHandler struct {
+	deps *deps.ShowDeps
+}
+
+type AccessListInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Index       *uint32    `json:"index,omitempty"`
+	Rules       []RuleInfo `json:"rules"`
+}
+
+type RuleInfo struct {
+	Sequence int `json:"sequence"`
+	aclcfg.Rule
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+type AccessListsOptions struct {
+	Name string `query:"name" description:"Only the access list with this name."`
+}
+
+func (h *AccessListsHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.RunningConfig == nil {
+		return nil, fmt.Errorf("running config not available")
+	}
+	cfg, err := h.deps.RunningConfig.GetRunning()
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := map[string]uint32{}
+	var stats map[uint32][]southbound.ACLRuleStats
+	if h.deps.Southbound != nil {
+		acls, err := h.deps.Southbound.DumpACLs()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range acls {
+			indexes[a.Name] = a.Index
+		}
+		// Counters are best effort: without the stats segment the
+		// rules are still worth showing.
+		stats, _ = h.deps.Southbound.GetACLRuleStats()
+	}
+
+	name := req.Options["name"]
+	out := []AccessListInfo{}
+	for listName, list := range cfg.AccessLists {
+		if list == nil || (name != "" && listName != name) {
+			continue
+		}
+		info := AccessListInfo{Name: listName, Description: list.Description}
+		for i, rule := range list.Rules {
+			info.Rules = append(info.Rules, RuleInfo{Sequence: i + 1, Rule: rule})
+		}
+		if idx, ok := indexes[listName]; ok {
+			info.Index = &idx
+			if _, ruleOf, err := list.Expand(); err == nil {
+				for entry, counters := range stats[idx] {
+					if entry >= len(ruleOf) {
+						break
+					}
+					info.Rules[ruleOf[entry]].Packets += counters.Packets
+					info.Rules[ruleOf[entry]].Bytes += counters.Bytes
+				}
+			}
+		}
+		out = append(out, info)
+	}
+	if name != "" && len(out) == 0 {
+		return nil, fmt.Errorf("access-list %q not found", name)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (h *AccessListsHandler) PathPattern() paths.Path {
+	return paths.AccessLists
+}
+
+func (h *AccessListsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *AccessListsHandler) OptionsType() interface{} {
+	return &AccessListsOptions{}
+}
+
+func (h *AccessListsHandler) OutputType() interface{} {
+	return []AccessListInfo{}
+}
+
+func (h *AccessListsHandler) Summary() string {
+	return "Show access lists"
+}
+
+func (h *AccessListsHandler) Description() string {
+	return "Display the configured access lists, their dataplane index, and how many packets and bytes each rule has matched."
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package acl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+type fakeSouthbound struct {
+	southbound.Southbound
+}
+
+func (f *fakeSouthbound) DumpACLs() ([]southbound.ACLDetails, error) {
+	return []southbound.ACLDetails{{Index: 3, Name: "web", Entries: 3}}, nil
+}
+
+func (f *fakeSouthbound) GetACLRuleStats() (map[uint32][]southbound.ACLRuleStats, error) {
+	return map[uint32][]southbound.ACLRuleStats{
+		3: {{Packets: 10, Bytes: 1000}, {Packets: 1, Bytes: 60}, {Packets: 2, Bytes: 80}},
+	}, nil
+}
+
+type runningConfig struct{ cfg *config.Config }
+
+func (r runningConfig) GetRunning() (*config.Config, error) { return r.cfg, nil }
+
+func TestCollectAttributesCountersToRules(t *testing.T) {
+	cfg := &config.Config{AccessLists: map[string]*aclcfg.AccessList{
+		"web": {Rules: []aclcfg.Rule{
+			{Action: aclcfg.ActionPermit, Protocol: "tcp", Destination: "192.0.2.0/24", DestinationPort: "443"},
+			{Action: aclcfg.ActionDeny},
+		}},
+		"unused": {Rules: []aclcfg.Rule{{Action: aclcfg.ActionDeny}}},
+	}}
+	h := &AccessListsHandler{deps: &deps.ShowDeps{
+		Southbound:    &fakeSouthbound{},
+		RunningConfig: runningConfig{cfg},
+	}}
+
+	out, err := h.Collect(context.Background(), &show.Request{Options: map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := out.([]AccessListInfo)
+	if len(lists) != 2 || lists[0].Name != "unused" || lists[0].Index != nil {
+		t.Fatalf("lists = %+v", lists)
+	}
+	web := lists[1]
+	if web.Index == nil || *web.Index != 3 {
+		t.Fatalf("web index = %v", web.Index)
+	}
+	// The catch-all deny is one entry per family.
+	if web.Rules[0].Packets != 10 || web.Rules[1].Packets != 3 || web.Rules[1].Bytes != 140 {
+		t.Fatalf("rules = %+v", web.Rules)
+	}
+
+	if _, err := h.Collect(context.Background(), &show.Request{Options: map[string]string{"name": "nope"}}); err == nil {
+		t.Fatal("unknown name accepted")
+	}
+}
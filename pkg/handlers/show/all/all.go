@@ -5,6 +5,7 @@
 package all
 
 import (
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/acl"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/cgnat"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/dhcp"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/ha"
@@ -12,6 +13,7 @@ import (
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/ip"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/l2gw"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/l2tp"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/nptv6"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/plugins"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/protocols/bgp"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/protocols/bgp/vpn/ipv4"
@@ -25,7 +27,9 @@ import (
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/protocols/zebra"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/qos"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/routing_policy"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/rtbh"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/servicegroups"
+	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/steering"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/subscriber"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/system"
 	_ "github.com/veesix-networks/osvbng/pkg/handlers/show/system/dataplane"
@@ -0,0 +1,68 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &OutsideAddressesHandler{deps: d}
+	})
+}
+
+type OutsideAddressesHandler struct {
+	deps *deps.ShowDeps
+}
+
+type OutsideAddressesOptions struct {
+	Pool  string `query:"pool" description:"Only addresses of this PBA pool."`
+	State string `query:"state" description:"Only addresses in this state: active, draining or drained."`
+}
+
+func (h *OutsideAddressesHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return []models.CGNATOutsideAddress{}, nil
+	}
+
+	state := req.Options["state"]
+	switch state {
+	case "", models.CGNATAddressActive, models.CGNATAddressDraining, models.CGNATAddressDrained:
+	default:
+		return nil, fmt.Errorf("invalid state: %q", state)
+	}
+	return h.deps.CGNAT.OutsideAddresses(req.Options["pool"], state)
+}
+
+func (h *OutsideAddressesHandler) PathPattern() paths.Path {
+	return paths.CGNATOutsideAddresses
+}
+
+func (h *OutsideAddressesHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *OutsideAddressesHandler) OptionsType() interface{} {
+	return &OutsideAddressesOptions{}
+}
+
+func (h *OutsideAddressesHandler) OutputType() interface{} {
+	return []models.CGNATOutsideAddress{}
+}
+
+func (h *OutsideAddressesHandler) Summary() string {
+	return "List CGNAT pool outside addresses"
+}
+
+func (h *OutsideAddressesHandler) Description() string {
+	return "Return the outside addresses of the PBA pools with their drain state, whether they were added while running, and how many blocks and subscribers they hold."
+}
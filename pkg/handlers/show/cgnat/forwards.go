@@ -0,0 +1,48 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &ForwardsHandler{deps: d}
+	})
+}
+
+type ForwardsHandler struct {
+	deps *deps.ShowDeps
+}
+
+func (h *ForwardsHandler) Collect(_ context.Context, _ *show.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return []models.CGNATMapping{}, nil
+	}
+
+	return h.deps.CGNAT.GetPortForwards(), nil
+}
+
+func (h *ForwardsHandler) PathPattern() paths.Path {
+	return paths.CGNATForwards
+}
+
+func (h *ForwardsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ForwardsHandler) Summary() string {
+	return "List CGNAT port forwards"
+}
+
+func (h *ForwardsHandler) Description() string {
+	return "Return every static, API and PCP port forward with its outside and inside address and port, and whether it is active or waiting for the subscriber to hold its port block."
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &MAPLookupHandler{deps: d}
+	})
+}
+
+type MAPLookupHandler struct {
+	deps *deps.ShowDeps
+}
+
+func (h *MAPLookupHandler) Collect(ctx context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return nil, fmt.Errorf("CGNAT not configured")
+	}
+
+	ipStr := req.Options["ip"]
+	portStr := req.Options["port"]
+
+	if ipStr == "" || portStr == "" {
+		return nil, fmt.Errorf("ip and port parameters required")
+	}
+
+	ip := net.ParseIP(ipStr)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %s", ipStr)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port: %s", portStr)
+	}
+
+	return h.deps.CGNAT.MAPLookup(ctx, ip, uint16(port))
+}
+
+func (h *MAPLookupHandler) PathPattern() paths.Path {
+	return paths.CGNATMAPLookup
+}
+
+func (h *MAPLookupHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *MAPLookupHandler) Summary() string {
+	return "Find the MAP CE that owns an outside address and port"
+}
+
+func (h *MAPLookupHandler) Description() string {
+	return "Apply the MAP domain's mapping rule to an outside IPv4 address and port to find the CE's PSID and end-user prefix, and the subscriber holding that prefix."
+}
+
+type MAPLookupOptions struct {
+	IP   string `query:"ip" description:"Outside IPv4 address within a MAP domain" format:"ip-address"`
+	Port uint16 `query:"port" description:"Outside port"`
+}
+
+func (h *MAPLookupHandler) OptionsType() interface{} {
+	return &MAPLookupOptions{}
+}
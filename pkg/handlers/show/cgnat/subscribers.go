@@ -0,0 +1,71 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &TopSubscribersHandler{deps: d}
+	})
+}
+
+type TopSubscribersHandler struct {
+	deps *deps.ShowDeps
+}
+
+type TopSubscribersOptions struct {
+	Pool  string `query:"pool" description:"Only subscribers of this PBA pool."`
+	Limit int    `query:"limit" description:"Number of subscribers to return (default 20)."`
+}
+
+func (h *TopSubscribersHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.CGNAT == nil {
+		return []models.CGNATSubscriberUsage{}, nil
+	}
+
+	var limit int
+	if v := req.Options["limit"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit: %q", v)
+		}
+		limit = n
+	}
+	return h.deps.CGNAT.TopSubscribers(req.Options["pool"], limit)
+}
+
+func (h *TopSubscribersHandler) PathPattern() paths.Path {
+	return paths.CGNATTopSubscribers
+}
+
+func (h *TopSubscribersHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *TopSubscribersHandler) OptionsType() interface{} {
+	return &TopSubscribersOptions{}
+}
+
+func (h *TopSubscribersHandler) OutputType() interface{} {
+	return []models.CGNATSubscriberUsage{}
+}
+
+func (h *TopSubscribersHandler) Summary() string {
+	return "List the CGNAT subscribers using the most ports"
+}
+
+func (h *TopSubscribersHandler) Description() string {
+	return "Return the PBA subscribers with the most sessions open on their port blocks, with their blocks, port utilisation and how many packets they have dropped at max-sessions-per-subscriber or for want of a free port."
+}
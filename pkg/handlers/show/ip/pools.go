@@ -0,0 +1,60 @@
+package ip
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/allocator"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models/ip"
+	"github.com/veesix-networks/osvbng/pkg/telemetry"
+)
+
+type PoolsHandler struct {
+	daemons *deps.ShowDeps
+}
+
+func init() {
+	show.RegisterFactory(func(daemons *deps.ShowDeps) show.ShowHandler {
+		return &PoolsHandler{daemons: daemons}
+	})
+	telemetry.RegisterMetric[ip.PoolStats](paths.IPPools)
+}
+
+func (h *PoolsHandler) PathPattern() paths.Path {
+	return paths.IPPools
+}
+
+func (h *PoolsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoolsHandler) Collect(ctx context.Context, req *show.Request) (interface{}, error) {
+	usages := allocator.GetGlobalRegistry().PoolUsages()
+	stats := make([]ip.PoolStats, 0, len(usages))
+	for _, u := range usages {
+		s := ip.PoolStats{
+			Family:    string(u.Family),
+			Profile:   u.Profile,
+			Pool:      u.Pool,
+			Size:      uint64(u.Size),
+			Available: uint64(u.Available),
+			Level:     string(u.Level),
+			LevelCode: u.Level.Code(),
+		}
+		if u.Size > 0 {
+			s.Utilization = float64(u.Size-u.Available) / float64(u.Size)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+func (h *PoolsHandler) Summary() string {
+	return "Show address pool utilisation"
+}
+
+func (h *PoolsHandler) Description() string {
+	return "Display size, free count and watermark level for each local IPv4, IA_NA and PD pool."
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2gw
+
+import (
+	"context"
+
+	l2gwcomp "github.com/veesix-networks/osvbng/internal/l2gw"
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/telemetry"
+)
+
+func init() {
+	show.RegisterFactory(NewHandoffsHandler)
+	telemetry.RegisterMetric[l2gwcomp.HandoffSummary](paths.L2GWHandoffs)
+}
+
+type HandoffsHandler struct {
+	deps *deps.ShowDeps
+}
+
+func NewHandoffsHandler(d *deps.ShowDeps) show.ShowHandler {
+	return &HandoffsHandler{deps: d}
+}
+
+func (h *HandoffsHandler) Collect(_ context.Context, _ *show.Request) (interface{}, error) {
+	if h.deps.L2GW == nil {
+		return []l2gwcomp.HandoffSummary{}, nil
+	}
+	return h.deps.L2GW.SnapshotHandoffs(), nil
+}
+
+func (h *HandoffsHandler) PathPattern() paths.Path {
+	return paths.L2GWHandoffs
+}
+
+func (h *HandoffsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *HandoffsHandler) Summary() string {
+	return "Show redundant l2gw handoff groups"
+}
+
+func (h *HandoffsHandler) Description() string {
+	return "List handoff groups with member interfaces: link state per member, the member carrying the circuits, circuit count and failover count."
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(NewDenylistHandler)
+}
+
+type DenylistHandler struct {
+	deps *deps.ShowDeps
+}
+
+func NewDenylistHandler(d *deps.ShowDeps) show.ShowHandler {
+	return &DenylistHandler{deps: d}
+}
+
+func (h *DenylistHandler) Collect(_ context.Context, _ *show.Request) (interface{}, error) {
+	if h.deps.L2TP == nil || h.deps.L2TP.Denylist() == nil {
+		return []models.L2TPDenylistEntry{}, nil
+	}
+	return h.deps.L2TP.Denylist().Snapshot(), nil
+}
+
+func (h *DenylistHandler) PathPattern() paths.Path {
+	return paths.L2TPDenylist
+}
+
+func (h *DenylistHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *DenylistHandler) Summary() string {
+	return "Show the LAC's LNS peer denylist"
+}
+
+func (h *DenylistHandler) Description() string {
+	return "List LNS peers the LAC has stopped selecting after setup failures, with the reason and expiry. Expired entries are probe-eligible and stay listed until a successful tunnel clears them."
+}
+
+func (h *DenylistHandler) SortKey() string {
+	return "PeerIP"
+}
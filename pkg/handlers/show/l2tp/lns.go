@@ -0,0 +1,51 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+	"github.com/veesix-networks/osvbng/pkg/telemetry"
+)
+
+func init() {
+	show.RegisterFactory(NewLNSHandler)
+	telemetry.RegisterMetric[models.L2TPLNSStatus](paths.L2TPLNS)
+}
+
+type LNSHandler struct {
+	deps *deps.ShowDeps
+}
+
+func NewLNSHandler(d *deps.ShowDeps) show.ShowHandler {
+	return &LNSHandler{deps: d}
+}
+
+func (h *LNSHandler) Collect(_ context.Context, _ *show.Request) (interface{}, error) {
+	if h.deps.L2TP == nil {
+		return []models.L2TPLNSStatus{}, nil
+	}
+	return h.deps.L2TP.SnapshotLNS(), nil
+}
+
+func (h *LNSHandler) PathPattern() paths.Path {
+	return paths.L2TPLNS
+}
+
+func (h *LNSHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *LNSHandler) Summary() string {
+	return "Show LAC tunnel-pool LNS health and load"
+}
+
+func (h *LNSHandler) Description() string {
+	return "List every tunnel-pool LNS with its health-check state, probe counters, weight, session cap and the sessions and tunnels the LAC currently carries to it. Available reports whether new sessions may select the LNS."
+}
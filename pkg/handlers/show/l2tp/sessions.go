@@ -0,0 +1,84 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(NewSessionsHandler)
+}
+
+type SessionsHandler struct {
+	deps *deps.ShowDeps
+}
+
+func NewSessionsHandler(d *deps.ShowDeps) show.ShowHandler {
+	return &SessionsHandler{deps: d}
+}
+
+func (h *SessionsHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.L2TP == nil {
+		return []models.L2TPSessionDetail{}, nil
+	}
+	sessions := h.deps.L2TP.SnapshotSessions()
+
+	sessionID := req.Options["session_id"]
+	peer := req.Options["peer"]
+	role := req.Options["role"]
+	if sessionID == "" && peer == "" && role == "" {
+		return sessions, nil
+	}
+	out := make([]models.L2TPSessionDetail, 0, len(sessions))
+	for _, s := range sessions {
+		if sessionID != "" && s.SessionID != sessionID {
+			continue
+		}
+		if peer != "" && s.PeerIP != peer {
+			continue
+		}
+		if role != "" && s.Role != role {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (h *SessionsHandler) PathPattern() paths.Path {
+	return paths.L2TPSessions
+}
+
+func (h *SessionsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *SessionsHandler) Summary() string {
+	return "Show L2TPv2 sessions"
+}
+
+func (h *SessionsHandler) Description() string {
+	return "List L2TPv2 sessions (LAC and LNS) with tunnel and session IDs on both ends, session FSM state, PPP phase, subscriber identity and addresses."
+}
+
+type SessionsOptions struct {
+	SessionID string `query:"session_id" description:"Show a single session by ID"`
+	Peer      string `query:"peer" description:"Filter by tunnel peer IP"`
+	Role      string `query:"role" description:"Filter by role" enum:"LAC,LNS"`
+}
+
+func (h *SessionsHandler) OptionsType() interface{} {
+	return &SessionsOptions{}
+}
+
+func (h *SessionsHandler) SortKey() string {
+	return "SessionID"
+}
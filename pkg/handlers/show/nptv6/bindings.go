@@ -0,0 +1,71 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package nptv6
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &BindingsHandler{deps: d}
+	})
+}
+
+type BindingsHandler struct {
+	deps *deps.ShowDeps
+}
+
+type BindingsOptions struct {
+	SessionID string `query:"session_id" description:"Only the binding of this session."`
+}
+
+func (h *BindingsHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.NPTv6 == nil {
+		return []models.NPTv6Binding{}, nil
+	}
+
+	bindings := h.deps.NPTv6.Bindings()
+	sessionID := req.Options["session_id"]
+	if sessionID == "" {
+		return bindings, nil
+	}
+	out := []models.NPTv6Binding{}
+	for _, b := range bindings {
+		if b.SessionID == sessionID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (h *BindingsHandler) PathPattern() paths.Path {
+	return paths.NPTv6Bindings
+}
+
+func (h *BindingsHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *BindingsHandler) OptionsType() interface{} {
+	return &BindingsOptions{}
+}
+
+func (h *BindingsHandler) OutputType() interface{} {
+	return []models.NPTv6Binding{}
+}
+
+func (h *BindingsHandler) Summary() string {
+	return "List NPTv6 prefix translations"
+}
+
+func (h *BindingsHandler) Description() string {
+	return "Return the sessions' NPTv6 bindings: the internal prefix translated to each delegated prefix, the session interface it is programmed on, and whether the internal prefix came from the service group or AAA."
+}
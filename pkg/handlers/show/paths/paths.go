@@ -13,6 +13,7 @@ type Path string
 const (
 	AAARadiusServers                  Path = "aaa.radius.servers"
 	IPTable                           Path = "ip.table"
+	IPPools                           Path = "ip.pools"
 	PluginsInfo                       Path = "plugins.info"
 	ProtocolsBGPStatistics            Path = "protocols.bgp.statistics"
 	ProtocolsBGPIPv6Statistics        Path = "protocols.bgp.ipv6.statistics"
@@ -51,6 +52,8 @@ const (
 
 	ProtocolsBGPImportCheckTable Path = "protocols.bgp.import-check-table"
 
+	ProtocolsBGPFlowSpec Path = "protocols.bgp.flowspec"
+
 	ProtocolsBGPVPNIPv4              Path = "protocols.bgp.vpn.ipv4"
 	ProtocolsBGPVPNIPv6              Path = "protocols.bgp.vpn.ipv6"
 	ProtocolsBGPVPNIPv4Summary       Path = "protocols.bgp.vpn.ipv4.summary"
@@ -188,21 +191,38 @@ const (
 	DHCPProxy Path = "dhcp.proxy"
 
 	L2GWCircuits Path = "l2gw.circuits"
+	L2GWHandoffs Path = "l2gw.handoffs"
+
+	L2TPTunnels  Path = "l2tp.tunnels"
+	L2TPTunnel   Path = "l2tp.tunnels.<*>"
+	L2TPSessions Path = "l2tp.sessions"
+	L2TPDenylist Path = "l2tp.denylist"
+	L2TPLNS      Path = "l2tp.lns"
+
+	CGNATSessions         Path = "cgnat.sessions"
+	CGNATMappings         Path = "cgnat.mappings"
+	CGNATPools            Path = "cgnat.pools"
+	CGNATStatistics       Path = "cgnat.statistics"
+	CGNATLookup           Path = "cgnat.lookup"
+	CGNATMAPLookup        Path = "cgnat.map.lookup"
+	CGNATForwards         Path = "cgnat.port-forwards"
+	CGNATTopSubscribers   Path = "cgnat.top-subscribers"
+	CGNATOutsideAddresses Path = "cgnat.outside-addresses"
+
+	NPTv6Bindings Path = "nptv6.bindings"
+
+	SteeringPolicies Path = "steering.policies"
 
-	L2TPTunnels Path = "l2tp.tunnels"
-	L2TPTunnel  Path = "l2tp.tunnels.<*>"
+	RTBHBlackholes Path = "rtbh.blackholes"
 
-	CGNATSessions   Path = "cgnat.sessions"
-	CGNATMappings   Path = "cgnat.mappings"
-	CGNATPools      Path = "cgnat.pools"
-	CGNATStatistics Path = "cgnat.statistics"
-	CGNATLookup     Path = "cgnat.lookup"
+	AccessLists Path = "access-lists"
 
 	QoSScheduler        Path = "qos.scheduler"
 	QoSSchedulerSession Path = "qos.scheduler.session"
 	QoSSchedulerDetail  Path = "qos.scheduler.detail"
 	QoSAggregate        Path = "qos.aggregate"
 	QoSAggregateDetail  Path = "qos.aggregate.detail"
+	QoSClasses          Path = "qos.classes"
 
 	RoutingPolicyPrefixSets         Path = "routing-policies.prefix-sets"
 	RoutingPolicyPrefixSet          Path = "routing-policies.prefix-sets.<*>"
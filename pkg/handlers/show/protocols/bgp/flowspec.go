@@ -0,0 +1,72 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package bgp
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &BGPFlowSpecHandler{deps: d}
+	})
+}
+
+type BGPFlowSpecHandler struct {
+	deps *deps.ShowDeps
+}
+
+type BGPFlowSpecOptions struct {
+	Family string `query:"family" description:"Only rules of this family: ipv4 or ipv6."`
+	Status string `query:"status" description:"Only rules in this state: programmed, unsupported, over-limit, failed or no-action."`
+}
+
+func (h *BGPFlowSpecHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.FlowSpec == nil {
+		return []models.FlowSpecRule{}, nil
+	}
+
+	family, status := req.Options["family"], req.Options["status"]
+	out := []models.FlowSpecRule{}
+	for _, r := range h.deps.FlowSpec.Rules() {
+		if family != "" && r.Family != family {
+			continue
+		}
+		if status != "" && r.Status != status {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (h *BGPFlowSpecHandler) PathPattern() paths.Path {
+	return paths.ProtocolsBGPFlowSpec
+}
+
+func (h *BGPFlowSpecHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *BGPFlowSpecHandler) OptionsType() interface{} {
+	return &BGPFlowSpecOptions{}
+}
+
+func (h *BGPFlowSpecHandler) OutputType() interface{} {
+	return []models.FlowSpecRule{}
+}
+
+func (h *BGPFlowSpecHandler) Summary() string {
+	return "List BGP FlowSpec rules"
+}
+
+func (h *BGPFlowSpecHandler) Description() string {
+	return "Return the FlowSpec rules received over BGP in match order: the action each takes, whether it is programmed and why not, and on each interface the ACL, policer or ABF policy programming it with its hit counters."
+}
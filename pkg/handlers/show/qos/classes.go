@@ -0,0 +1,88 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package qos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/telemetry"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &ClassesHandler{deps: d}
+	})
+	telemetry.RegisterMetric[southbound.QoSClassState](paths.QoSClasses)
+}
+
+type ClassesHandler struct {
+	deps *deps.ShowDeps
+}
+
+// Collect with no options returns every class on every session, as the
+// telemetry poller expects.
+func (h *ClassesHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.Southbound == nil {
+		return nil, fmt.Errorf("southbound not available")
+	}
+
+	states, err := h.deps.Southbound.DumpQoSClasses()
+	if err != nil {
+		return nil, err
+	}
+
+	if ifOpt := req.Options["interface"]; ifOpt != "" {
+		swIfIndex, err := resolveIfIndex(h.deps, ifOpt)
+		if err != nil {
+			return nil, err
+		}
+		filtered := states[:0]
+		for _, s := range states {
+			if s.SwIfIndex == swIfIndex {
+				filtered = append(filtered, s)
+			}
+		}
+		states = filtered
+	}
+	if states == nil {
+		states = []southbound.QoSClassState{}
+	}
+
+	return states, nil
+}
+
+type ClassesOptions struct {
+	Interface string `query:"interface" description:"Limit to one session interface, by name or sw_if_index"`
+}
+
+func (h *ClassesHandler) OptionsType() interface{} {
+	return &ClassesOptions{}
+}
+
+func (h *ClassesHandler) PathPattern() paths.Path {
+	return paths.QoSClasses
+}
+
+func (h *ClassesHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *ClassesHandler) Summary() string {
+	return "Show QoS traffic classes"
+}
+
+func (h *ClassesHandler) Description() string {
+	return "Display the traffic classes programmed on each session with their counters. " +
+		"Ingress classes count through their own policer; egress classes report the scheduler tin they map to."
+}
+
+func (h *ClassesHandler) SortKey() string {
+	return "sw_if_index"
+}
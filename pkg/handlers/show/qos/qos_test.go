@@ -23,6 +23,7 @@ type fakeSouthbound struct {
 	ifMgr      *ifmgr.Manager
 	schedulers []southbound.SchedulerState
 	aggregates []southbound.AggregateState
+	classes    []southbound.QoSClassState
 	byParent   func(parentSwIfIndex uint32, level string, svlanID uint16) ([]southbound.SchedulerState, error)
 }
 
@@ -53,6 +54,10 @@ func (f *fakeSouthbound) DumpAggregates() ([]southbound.AggregateState, error) {
 	return append([]southbound.AggregateState(nil), f.aggregates...), nil
 }
 
+func (f *fakeSouthbound) DumpQoSClasses() ([]southbound.QoSClassState, error) {
+	return append([]southbound.QoSClassState(nil), f.classes...), nil
+}
+
 func testDeps(sb *fakeSouthbound) *deps.ShowDeps {
 	if sb.ifMgr == nil {
 		sb.ifMgr = ifmgr.New()
@@ -254,3 +259,28 @@ func TestSchedulerDetail_ResolvesParents(t *testing.T) {
 		t.Fatalf("expected the port parent, got %+v", view.ParentPort)
 	}
 }
+
+func TestClasses_InterfaceFilter(t *testing.T) {
+	sb := &fakeSouthbound{classes: []southbound.QoSClassState{
+		{SwIfIndex: 5, Direction: "ingress", Class: "voice", Packets: 10},
+		{SwIfIndex: 6, Direction: "ingress", Class: "voice"},
+	}}
+	h := &ClassesHandler{deps: testDeps(sb)}
+
+	out, err := h.Collect(context.Background(), req(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.([]southbound.QoSClassState); len(got) != 2 {
+		t.Fatalf("empty request returned %d classes, want 2", len(got))
+	}
+
+	out, err = h.Collect(context.Background(), req(map[string]string{"interface": "5"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.([]southbound.QoSClassState)
+	if len(got) != 1 || got[0].SwIfIndex != 5 || got[0].Packets != 10 {
+		t.Fatalf("filtered = %+v", got)
+	}
+}
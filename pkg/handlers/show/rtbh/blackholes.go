@@ -0,0 +1,72 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rtbh
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &BlackholesHandler{deps: d}
+	})
+}
+
+type BlackholesHandler struct {
+	deps *deps.ShowDeps
+}
+
+type BlackholesOptions struct {
+	VRF       string `query:"vrf" description:"Only blackholes in this VRF."`
+	SessionID string `query:"session_id" description:"Only blackholes of this subscriber session."`
+}
+
+func (h *BlackholesHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.RTBH == nil {
+		return []models.BlackholeStatus{}, nil
+	}
+
+	vrf, sessionID := req.Options["vrf"], req.Options["session_id"]
+	out := []models.BlackholeStatus{}
+	for _, b := range h.deps.RTBH.Blackholes() {
+		if vrf != "" && b.VRF != vrf {
+			continue
+		}
+		if sessionID != "" && b.SessionID != sessionID {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (h *BlackholesHandler) PathPattern() paths.Path {
+	return paths.RTBHBlackholes
+}
+
+func (h *BlackholesHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *BlackholesHandler) OptionsType() interface{} {
+	return &BlackholesOptions{}
+}
+
+func (h *BlackholesHandler) OutputType() interface{} {
+	return []models.BlackholeStatus{}
+}
+
+func (h *BlackholesHandler) Summary() string {
+	return "List remote-triggered blackholes"
+}
+
+func (h *BlackholesHandler) Description() string {
+	return "Return every blackhole this node holds, requested here or on the HA peer: where it came from, when it expires and the seconds remaining, and whether the drop route is installed and the prefix advertised over BGP."
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package steering
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/deps"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show"
+	"github.com/veesix-networks/osvbng/pkg/handlers/show/paths"
+	"github.com/veesix-networks/osvbng/pkg/models"
+)
+
+func init() {
+	show.RegisterFactory(func(d *deps.ShowDeps) show.ShowHandler {
+		return &PoliciesHandler{deps: d}
+	})
+}
+
+type PoliciesHandler struct {
+	deps *deps.ShowDeps
+}
+
+type PoliciesOptions struct {
+	Name string `query:"name" description:"Only the steering policy of this name."`
+}
+
+func (h *PoliciesHandler) Collect(_ context.Context, req *show.Request) (interface{}, error) {
+	if h.deps.Steering == nil {
+		return []models.SteeringPolicy{}, nil
+	}
+
+	policies := h.deps.Steering.Policies()
+	name := req.Options["name"]
+	if name == "" {
+		return policies, nil
+	}
+	out := []models.SteeringPolicy{}
+	for _, p := range policies {
+		if p.Name == name {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (h *PoliciesHandler) PathPattern() paths.Path {
+	return paths.SteeringPolicies
+}
+
+func (h *PoliciesHandler) Dependencies() []paths.Path {
+	return nil
+}
+
+func (h *PoliciesHandler) OptionsType() interface{} {
+	return &PoliciesOptions{}
+}
+
+func (h *PoliciesHandler) OutputType() interface{} {
+	return []models.SteeringPolicy{}
+}
+
+func (h *PoliciesHandler) Summary() string {
+	return "List steering policies and next-hop health"
+}
+
+func (h *PoliciesHandler) Description() string {
+	return "Return each steering policy with its access list and next hops: the health-check state of every next hop, whether the policy currently forwards to it, and whether the policy is failing open because none is up."
+}
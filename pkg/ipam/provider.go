@@ -0,0 +1,35 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"context"
+
+	"github.com/veesix-networks/osvbng/pkg/provider"
+)
+
+// Provider hands out subnet chunks from a central IPAM.
+type Provider interface {
+	provider.Provider
+	Allocate(ctx context.Context, req *ChunkRequest) (*Chunk, error)
+	Release(ctx context.Context, req *ChunkRequest, chunk *Chunk) error
+}
+
+// ChunkRequest describes the chunk wanted: Family is ipv4, iana or pd
+// and PrefixLength the chunk's length.
+type ChunkRequest struct {
+	Family       string
+	Profile      string
+	VRF          string
+	PrefixLength uint8
+	DeviceID     string
+}
+
+// Chunk is a subnet returned by the IPAM. ID is the IPAM's reference
+// for it, if any, and is handed back on release.
+type Chunk struct {
+	Prefix string
+	ID     string
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ipam
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+)
+
+type Factory func(*config.Config) (Provider, error)
+
+var registry = make(map[string]Factory)
+
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+func Get(name string) (Factory, bool) {
+	factory, exists := registry[name]
+	return factory, exists
+}
+
+func New(name string, cfg *config.Config) (Provider, error) {
+	factory, exists := registry[name]
+	if !exists {
+		return nil, fmt.Errorf("ipam provider %s not registered", name)
+	}
+	return factory(cfg)
+}
+
+func List() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
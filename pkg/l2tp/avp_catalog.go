@@ -50,6 +50,12 @@ const (
 	AVPPrivateGroupID       uint16 = 37 // §4.4.30
 	AVPRxConnectSpeed       uint16 = 38 // §4.4.20
 	AVPSequencingRequired   uint16 = 39 // §4.4.31
+
+	// RFC 4951 failover extensions.
+	AVPFailoverCapability       uint16 = 76 // RFC 4951 §4.1
+	AVPTunnelRecovery           uint16 = 77 // RFC 4951 §4.2
+	AVPSuggestedControlSequence uint16 = 78 // RFC 4951 §4.3
+	AVPFailoverSessionState     uint16 = 79 // RFC 4951 §4.4
 )
 
 // Message Type values per RFC 2661 §4.4.1.
@@ -68,6 +74,8 @@ const (
 	MsgTypeCDN     uint16 = 14 // Call-Disconnect-Notify
 	MsgTypeWEN     uint16 = 15 // WAN-Error-Notify
 	MsgTypeSLI     uint16 = 16 // Set-Link-Info
+	MsgTypeFSQ     uint16 = 21 // Failover-Session-Query (RFC 4951)
+	MsgTypeFSR     uint16 = 22 // Failover-Session-Response (RFC 4951)
 )
 
 // MessageTypeName returns the short name for a message type or "" if
@@ -102,6 +110,10 @@ func MessageTypeName(t uint16) string {
 		return "WEN"
 	case MsgTypeSLI:
 		return "SLI"
+	case MsgTypeFSQ:
+		return "FSQ"
+	case MsgTypeFSR:
+		return "FSR"
 	}
 	return ""
 }
@@ -6,6 +6,7 @@ package l2tp
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 )
 
@@ -96,6 +97,30 @@ type ControlChannel struct {
 	// ZLB deadline: when set, we owe an ACK and will emit a ZLB at
 	// this time if no piggyback has happened.
 	zlbDeadline time.Time
+
+	stats channelCounters
+}
+
+// ChannelStats counts control-channel traffic over the channel's
+// lifetime. Retransmits feed Acct-Tunnel-Packets-Lost (RFC 2867).
+type ChannelStats struct {
+	Sent        uint64
+	Retransmits uint64
+	Received    uint64
+	Duplicates  uint64
+	ZLBSent     uint64
+	ZLBReceived uint64
+}
+
+// channelCounters are atomic so show handlers can read them while the
+// owning tunnel goroutine drives the channel.
+type channelCounters struct {
+	sent        atomic.Uint64
+	retransmits atomic.Uint64
+	received    atomic.Uint64
+	duplicates  atomic.Uint64
+	zlbSent     atomic.Uint64
+	zlbReceived atomic.Uint64
 }
 
 // Config bundles per-tunnel knobs. All durations default to the
@@ -207,6 +232,7 @@ func (c *ControlChannel) driveSend(now time.Time) error {
 		if err := c.send(c.queue[i].body, c.queue[i].sessionID, c.queue[i].ns, c.nr); err != nil {
 			return err
 		}
+		c.stats.sent.Add(1)
 		inflight++
 		// Sending a message piggybacks the current Nr, satisfying any
 		// pending ZLB obligation.
@@ -251,18 +277,59 @@ func (c *ControlChannel) Recv(ns, nr uint16, now time.Time) (accept bool, err er
 	if ns != c.nr {
 		// Duplicate (ns < c.nr) or future (ns > c.nr): both call for
 		// a ZLB carrying the current expected.
+		c.stats.duplicates.Add(1)
 		c.scheduleZLB(now)
 		return false, nil
 	}
 
 	// In-order: advance and arm ZLB unless a piggyback opportunity
 	// arrives within zlbDelay.
+	c.stats.received.Add(1)
 	c.nr++
 	c.scheduleZLB(now)
 
 	return true, nil
 }
 
+// RecvZLB processes a Zero-Length Body ACK. Unlike Recv it only
+// consumes the Nr: a ZLB's Ns is the peer's next send sequence and
+// does not occupy a slot (RFC 2661 §5.8).
+func (c *ControlChannel) RecvZLB(ns, nr uint16, now time.Time) {
+	c.stats.zlbReceived.Add(1)
+	c.ackThrough(nr, now)
+}
+
+// Restore seeds the sequence state of a channel rebuilt from a
+// checkpoint (process restart or HA takeover). Nothing is in flight:
+// messages queued at checkpoint time are lost and the peer's
+// retransmissions fill the gap on its side.
+func (c *ControlChannel) Restore(ns, nr uint16) {
+	c.ns = ns
+	c.nr = nr
+	c.queue = c.queue[:0]
+	c.nextRTO = time.Time{}
+	c.zlbDeadline = time.Time{}
+}
+
+// RecoveryPoint returns the Ns / Nr a failed peer resumes the channel
+// with (RFC 4951 §5.2): it sends from our next-expected Ns, and
+// expects the oldest message it has not acknowledged. Unacknowledged
+// messages are rearmed so they go out again once the peer is back.
+func (c *ControlChannel) RecoveryPoint(now time.Time) (peerNs, peerNr uint16) {
+	peerNs, peerNr = c.nr, c.ns
+	if len(c.queue) > 0 {
+		peerNr = c.queue[0].ns
+	}
+	for i := range c.queue {
+		c.queue[i].attempts = 0
+		c.queue[i].deadline = time.Time{}
+	}
+	c.cwnd = 1
+	c.nextRTO = time.Time{}
+	_ = c.driveSend(now)
+	return peerNs, peerNr
+}
+
 // ackThrough removes all queued messages with ns < ackNr from the
 // queue and grows the congestion window per slow-start rules. The
 // retransmit timer is recomputed.
@@ -342,6 +409,7 @@ func (c *ControlChannel) Tick(now time.Time) time.Time {
 			rto = c.rtoMax
 		}
 		c.queue[i].deadline = now.Add(rto)
+		c.stats.retransmits.Add(1)
 		_ = c.send(c.queue[i].body, c.queue[i].sessionID, c.queue[i].ns, c.nr)
 	}
 	c.recomputeNextRTO()
@@ -351,6 +419,7 @@ func (c *ControlChannel) Tick(now time.Time) time.Time {
 	// incremented because the ZLB has no payload).
 	if !c.zlbDeadline.IsZero() && !now.Before(c.zlbDeadline) {
 		_ = c.send(nil, 0, c.ns, c.nr)
+		c.stats.zlbSent.Add(1)
 		c.zlbDeadline = time.Time{}
 	}
 
@@ -381,3 +450,16 @@ func (c *ControlChannel) Cwnd() int { return c.cwnd }
 
 // Ssthresh returns the current slow-start threshold.
 func (c *ControlChannel) Ssthresh() int { return c.ssthresh }
+
+// Stats returns the channel's traffic counters. Safe to call from any
+// goroutine.
+func (c *ControlChannel) Stats() ChannelStats {
+	return ChannelStats{
+		Sent:        c.stats.sent.Load(),
+		Retransmits: c.stats.retransmits.Load(),
+		Received:    c.stats.received.Load(),
+		Duplicates:  c.stats.duplicates.Load(),
+		ZLBSent:     c.stats.zlbSent.Load(),
+		ZLBReceived: c.stats.zlbReceived.Load(),
+	}
+}
@@ -236,3 +236,84 @@ func TestSendErrorPropagates(t *testing.T) {
 		t.Fatalf("want propagated error, got %v", err)
 	}
 }
+
+func TestControlChannelRecvZLBDoesNotAdvanceNr(t *testing.T) {
+	var sent []sentMsg
+	ch := NewControlChannel(Config{PeerRWS: 4}, recordingSend(&sent), nil)
+	now := time.Unix(0, 0)
+
+	_ = ch.Send([]byte("m1"), now)
+	ch.RecvZLB(0, 1, now)
+
+	if ch.Nr() != 0 {
+		t.Fatalf("ZLB must not consume an Ns slot, Nr=%d", ch.Nr())
+	}
+	if len(ch.queue) != 0 {
+		t.Fatalf("ZLB Nr=1 should ACK m1, outstanding=%d", len(ch.queue))
+	}
+}
+
+func TestControlChannelRecoveryPointRearmsUnacked(t *testing.T) {
+	// The peer failed while two of our messages were unacknowledged.
+	// It resumes expecting the older one, sending from our Nr, and the
+	// two go out again.
+	var sent []sentMsg
+	ch := NewControlChannel(Config{PeerRWS: 4}, recordingSend(&sent), nil)
+	now := time.Unix(0, 0)
+
+	ch.Restore(5, 7)
+	_ = ch.Send([]byte("m1"), now)
+	_ = ch.Send([]byte("m2"), now)
+	sent = sent[:0]
+
+	ns, nr := ch.RecoveryPoint(now)
+	if ns != 7 || nr != 5 {
+		t.Fatalf("recovery point: ns=%d nr=%d, want 7/5", ns, nr)
+	}
+	if len(sent) != 1 || sent[0].ns != 5 {
+		t.Fatalf("oldest unacked should be resent first, got %+v", sent)
+	}
+
+	accept, err := ch.Recv(7, 6, now)
+	if err != nil || !accept {
+		t.Fatalf("recv on recovered channel: accept=%v err=%v", accept, err)
+	}
+	if len(sent) != 2 || sent[1].ns != 6 {
+		t.Fatalf("ACK of m1 should release m2, got %+v", sent)
+	}
+}
+
+func TestControlChannelRecoveryPointIdle(t *testing.T) {
+	ch := NewControlChannel(Config{}, recordingSend(new([]sentMsg)), nil)
+	ch.Restore(3, 9)
+	if ns, nr := ch.RecoveryPoint(time.Unix(0, 0)); ns != 9 || nr != 3 {
+		t.Fatalf("idle recovery point: ns=%d nr=%d, want 9/3", ns, nr)
+	}
+}
+
+func TestControlChannelStats(t *testing.T) {
+	var sent []sentMsg
+	ch := NewControlChannel(Config{PeerRWS: 4, RTOInitial: time.Second, ZLBDelay: time.Millisecond}, recordingSend(&sent), nil)
+
+	now := time.Unix(0, 0)
+	if err := ch.Send([]byte("m1"), now); err != nil {
+		t.Fatal(err)
+	}
+	// RTO expiry retransmits m1.
+	ch.Tick(now.Add(2 * time.Second))
+	// In-order message, then its duplicate, then a ZLB ACK.
+	if accept, _ := ch.Recv(0, 0, now); !accept {
+		t.Fatal("in-order message rejected")
+	}
+	if accept, _ := ch.Recv(0, 0, now); accept {
+		t.Fatal("duplicate accepted")
+	}
+	ch.RecvZLB(1, 1, now)
+	// Owed ACK goes out as a ZLB.
+	ch.Tick(now.Add(3 * time.Second))
+
+	want := ChannelStats{Sent: 1, Retransmits: 1, Received: 1, Duplicates: 1, ZLBSent: 1, ZLBReceived: 1}
+	if got := ch.Stats(); got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+}
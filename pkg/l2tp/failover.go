@@ -0,0 +1,186 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// RFC 4951 failover. Both ends advertise a Failover Capability AVP in
+// SCCRQ / SCCRP; a tunnel is recoverable only when both did. An
+// endpoint that lost its control-channel state opens a recovery tunnel
+// whose SCCRQ names the old tunnel in a Tunnel Recovery AVP; the peer
+// answers with the Ns / Nr the old tunnel resumes from (Suggested
+// Control Sequence AVP). Session state is then reconciled with
+// FSQ / FSR.
+
+// Failover Capability flag bits (RFC 4951 §4.1).
+const (
+	failoverCapControl uint16 = 1 << 1 // C: control channel failover
+	failoverCapData    uint16 = 1 << 0 // D: data channel failover
+)
+
+// FailoverCapability is the content of a Failover Capability AVP.
+// RecoveryTime is how long the sender needs after a failure before it
+// can recover the tunnel; the peer keeps the tunnel that long.
+type FailoverCapability struct {
+	ControlChannel bool
+	DataChannel    bool
+	RecoveryTime   time.Duration
+}
+
+// TunnelRecovery is the content of a Tunnel Recovery AVP: the tunnel
+// being recovered, as identified by the failed endpoint (TunnelID) and
+// by its peer (RemoteTunnelID).
+type TunnelRecovery struct {
+	TunnelID       uint16
+	RemoteTunnelID uint16
+}
+
+// ControlSequence is the content of a Suggested Control Sequence AVP:
+// the Ns and Nr the failed endpoint resumes the recovered tunnel with.
+type ControlSequence struct {
+	Ns uint16
+	Nr uint16
+}
+
+// FailoverSessionState is the content of a Failover Session State
+// AVP. In FSQ SessionID is the sender's and RemoteSessionID the
+// receiver's; FSR answers with the IDs swapped and SessionID 0 for a
+// session the responder does not have.
+type FailoverSessionState struct {
+	SessionID       uint16
+	RemoteSessionID uint16
+}
+
+// maxSessionStatesPerMessage bounds the Failover Session State AVPs in
+// one FSQ / FSR so the message stays well under the 16-bit length.
+const maxSessionStatesPerMessage = 1000
+
+func appendFailoverCapabilityAVP(dst []byte, fc FailoverCapability) []byte {
+	var flags uint16
+	if fc.ControlChannel {
+		flags |= failoverCapControl
+	}
+	if fc.DataChannel {
+		flags |= failoverCapData
+	}
+	var v [6]byte
+	binary.BigEndian.PutUint16(v[0:2], flags)
+	binary.BigEndian.PutUint32(v[2:6], uint32(fc.RecoveryTime/time.Millisecond))
+	// M=0: peers without RFC 4951 support ignore the AVP.
+	return AppendAVP(dst, false, false, VendorIETF, AVPFailoverCapability, v[:])
+}
+
+func appendTunnelRecoveryAVP(dst []byte, tr TunnelRecovery) []byte {
+	return AppendAVP(dst, true, false, VendorIETF, AVPTunnelRecovery,
+		threeWords(tr.TunnelID, tr.RemoteTunnelID))
+}
+
+func appendSuggestedControlSequenceAVP(dst []byte, cs ControlSequence) []byte {
+	return AppendAVP(dst, true, false, VendorIETF, AVPSuggestedControlSequence,
+		threeWords(cs.Ns, cs.Nr))
+}
+
+func appendFailoverSessionStateAVP(dst []byte, ss FailoverSessionState) []byte {
+	return AppendAVP(dst, true, false, VendorIETF, AVPFailoverSessionState,
+		threeWords(ss.SessionID, ss.RemoteSessionID))
+}
+
+// threeWords encodes the reserved-word-plus-two-IDs layout shared by
+// the RFC 4951 AVPs.
+func threeWords(a, b uint16) []byte {
+	v := make([]byte, 6)
+	binary.BigEndian.PutUint16(v[2:4], a)
+	binary.BigEndian.PutUint16(v[4:6], b)
+	return v
+}
+
+// DecodeFailoverCapability returns the Failover Capability AVP of a
+// message, if present and well formed.
+func DecodeFailoverCapability(avps []AVP) (FailoverCapability, bool) {
+	a := FindFirst(avps, VendorIETF, AVPFailoverCapability)
+	if a == nil || len(a.Value) < 6 {
+		return FailoverCapability{}, false
+	}
+	flags := binary.BigEndian.Uint16(a.Value[0:2])
+	return FailoverCapability{
+		ControlChannel: flags&failoverCapControl != 0,
+		DataChannel:    flags&failoverCapData != 0,
+		RecoveryTime:   time.Duration(binary.BigEndian.Uint32(a.Value[2:6])) * time.Millisecond,
+	}, true
+}
+
+// DecodeTunnelRecovery returns the Tunnel Recovery AVP of an SCCRQ, if
+// present and well formed.
+func DecodeTunnelRecovery(avps []AVP) (TunnelRecovery, bool) {
+	a := FindFirst(avps, VendorIETF, AVPTunnelRecovery)
+	if a == nil || len(a.Value) < 6 {
+		return TunnelRecovery{}, false
+	}
+	return TunnelRecovery{
+		TunnelID:       binary.BigEndian.Uint16(a.Value[2:4]),
+		RemoteTunnelID: binary.BigEndian.Uint16(a.Value[4:6]),
+	}, true
+}
+
+// DecodeSuggestedControlSequence returns the Suggested Control
+// Sequence AVP of a recovery SCCRP, if present and well formed.
+func DecodeSuggestedControlSequence(avps []AVP) (ControlSequence, bool) {
+	a := FindFirst(avps, VendorIETF, AVPSuggestedControlSequence)
+	if a == nil || len(a.Value) < 6 {
+		return ControlSequence{}, false
+	}
+	return ControlSequence{
+		Ns: binary.BigEndian.Uint16(a.Value[2:4]),
+		Nr: binary.BigEndian.Uint16(a.Value[4:6]),
+	}, true
+}
+
+// DecodeSessionStates returns every well-formed Failover Session State
+// AVP of an FSQ or FSR.
+func DecodeSessionStates(avps []AVP) []FailoverSessionState {
+	var out []FailoverSessionState
+	for i := range avps {
+		a := &avps[i]
+		if a.VendorID != VendorIETF || a.Type != AVPFailoverSessionState || len(a.Value) < 6 {
+			continue
+		}
+		out = append(out, FailoverSessionState{
+			SessionID:       binary.BigEndian.Uint16(a.Value[2:4]),
+			RemoteSessionID: binary.BigEndian.Uint16(a.Value[4:6]),
+		})
+	}
+	return out
+}
+
+// BuildFSQ builds Failover-Session-Query bodies for the given
+// sessions, split so each message stays within the AVP budget.
+func BuildFSQ(states []FailoverSessionState) [][]byte {
+	return buildSessionStateMessages(MsgTypeFSQ, states)
+}
+
+// BuildFSR builds Failover-Session-Response bodies answering an FSQ.
+func BuildFSR(states []FailoverSessionState) [][]byte {
+	return buildSessionStateMessages(MsgTypeFSR, states)
+}
+
+func buildSessionStateMessages(msgType uint16, states []FailoverSessionState) [][]byte {
+	var out [][]byte
+	for len(states) > 0 {
+		n := len(states)
+		if n > maxSessionStatesPerMessage {
+			n = maxSessionStatesPerMessage
+		}
+		body := appendMessageTypeAVP(nil, msgType)
+		for _, ss := range states[:n] {
+			body = appendFailoverSessionStateAVP(body, ss)
+		}
+		out = append(out, body)
+		states = states[n:]
+	}
+	return out
+}
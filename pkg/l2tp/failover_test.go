@@ -0,0 +1,74 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package l2tp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailoverAVPsRoundTrip(t *testing.T) {
+	body := BuildSCCRQ(SCCRQParams{
+		LocalTunnelID:  5,
+		HostName:       "lac1",
+		Failover:       &FailoverCapability{ControlChannel: true, RecoveryTime: 45 * time.Second},
+		TunnelRecovery: &TunnelRecovery{TunnelID: 3, RemoteTunnelID: 40},
+	})
+	avps, err := ParseAVPs(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, ok := DecodeFailoverCapability(avps)
+	if !ok || !fc.ControlChannel || fc.DataChannel || fc.RecoveryTime != 45*time.Second {
+		t.Fatalf("failover capability: %+v ok=%v", fc, ok)
+	}
+	if a := FindFirst(avps, VendorIETF, AVPFailoverCapability); a.Mandatory {
+		t.Fatal("Failover Capability must not be mandatory")
+	}
+	if tr, ok := DecodeTunnelRecovery(avps); !ok || tr != (TunnelRecovery{TunnelID: 3, RemoteTunnelID: 40}) {
+		t.Fatalf("tunnel recovery: %+v ok=%v", tr, ok)
+	}
+
+	body = BuildSCCRP(SCCRPParams{
+		LocalTunnelID:     6,
+		HostName:          "lns1",
+		SuggestedSequence: &ControlSequence{Ns: 9, Nr: 11},
+	})
+	avps, _ = ParseAVPs(body)
+	if cs, ok := DecodeSuggestedControlSequence(avps); !ok || cs != (ControlSequence{Ns: 9, Nr: 11}) {
+		t.Fatalf("suggested sequence: %+v ok=%v", cs, ok)
+	}
+	if _, ok := DecodeFailoverCapability(avps); ok {
+		t.Fatal("SCCRP without Failover must not carry the AVP")
+	}
+}
+
+func TestBuildFSQSplitsLargeQueries(t *testing.T) {
+	states := make([]FailoverSessionState, maxSessionStatesPerMessage+1)
+	for i := range states {
+		states[i] = FailoverSessionState{SessionID: uint16(i + 1), RemoteSessionID: uint16(i + 100)}
+	}
+	bodies := BuildFSQ(states)
+	if len(bodies) != 2 {
+		t.Fatalf("want 2 messages, got %d", len(bodies))
+	}
+	var got []FailoverSessionState
+	for _, b := range bodies {
+		avps, err := ParseAVPs(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if DecodeMessageType(avps) != MsgTypeFSQ {
+			t.Fatal("not an FSQ")
+		}
+		got = append(got, DecodeSessionStates(avps)...)
+	}
+	if len(got) != len(states) || got[len(got)-1] != states[len(states)-1] {
+		t.Fatalf("round trip lost states: %d of %d", len(got), len(states))
+	}
+	if BuildFSR(nil) != nil {
+		t.Fatal("empty FSR should build no message")
+	}
+}
@@ -61,6 +61,12 @@ func NewSessionFSM(role SessionRole) *SessionFSM {
 	return &SessionFSM{state: SessionIdle, role: role}
 }
 
+// RestoreSessionFSM rebuilds an FSM in `state` for a session recovered
+// from a checkpoint.
+func RestoreSessionFSM(role SessionRole, state SessionState) *SessionFSM {
+	return &SessionFSM{state: state, role: role}
+}
+
 func (f *SessionFSM) State() SessionState { return f.state }
 func (f *SessionFSM) Role() SessionRole   { return f.role }
 
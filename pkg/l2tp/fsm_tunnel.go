@@ -67,6 +67,12 @@ func NewTunnelFSM(role TunnelRole) *TunnelFSM {
 	return &TunnelFSM{state: TunnelIdle, role: role}
 }
 
+// RestoreTunnelFSM rebuilds an FSM in `state` for a tunnel recovered
+// from a checkpoint. The establishment exchange is not replayed.
+func RestoreTunnelFSM(role TunnelRole, state TunnelState) *TunnelFSM {
+	return &TunnelFSM{state: state, role: role}
+}
+
 func (f *TunnelFSM) State() TunnelState { return f.state }
 func (f *TunnelFSM) Role() TunnelRole   { return f.role }
 
@@ -22,6 +22,11 @@ type SCCRQParams struct {
 	BearerCaps        uint32
 	FirmwareRevision  uint16
 	Challenge         []byte
+
+	// Failover advertises RFC 4951 failover support; TunnelRecovery
+	// marks the SCCRQ of a recovery tunnel. Both are omitted when nil.
+	Failover       *FailoverCapability
+	TunnelRecovery *TunnelRecovery
 }
 
 // BuildSCCRQ builds a Start-Control-Connection-Request body. Called by
@@ -43,6 +48,12 @@ func BuildSCCRQ(p SCCRQParams) []byte {
 	if len(p.Challenge) > 0 {
 		body = appendChallengeAVP(body, p.Challenge)
 	}
+	if p.Failover != nil {
+		body = appendFailoverCapabilityAVP(body, *p.Failover)
+	}
+	if p.TunnelRecovery != nil {
+		body = appendTunnelRecoveryAVP(body, *p.TunnelRecovery)
+	}
 	return body
 }
 
@@ -91,6 +102,11 @@ type SCCRPParams struct {
 	// with one of its own + a response per RFC 2661 §5.1.1.
 	Challenge         []byte
 	ChallengeResponse []byte
+
+	// Failover advertises RFC 4951 failover support; SuggestedSequence
+	// answers a recovery SCCRQ. Both are omitted when nil.
+	Failover          *FailoverCapability
+	SuggestedSequence *ControlSequence
 }
 
 // BuildSCCRP builds a Start-Control-Connection-Reply body. Called by
@@ -115,6 +131,12 @@ func BuildSCCRP(p SCCRPParams) []byte {
 	if len(p.ChallengeResponse) > 0 {
 		body = appendChallengeResponseAVP(body, p.ChallengeResponse)
 	}
+	if p.Failover != nil {
+		body = appendFailoverCapabilityAVP(body, *p.Failover)
+	}
+	if p.SuggestedSequence != nil {
+		body = appendSuggestedControlSequenceAVP(body, *p.SuggestedSequence)
+	}
 	return body
 }
 
@@ -4,7 +4,26 @@
 
 package models
 
-import "net"
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Port forward sources: operator configuration, the northbound API, or
+// a subscriber's CPE over PCP.
+const (
+	CGNATForwardStatic = "static"
+	CGNATForwardAPI    = "api"
+	CGNATForwardPCP    = "pcp"
+)
+
+// Port forward states: active forwards are programmed in the dataplane;
+// pending ones wait for the subscriber to hold the outside port's block.
+const (
+	CGNATForwardActive  = "active"
+	CGNATForwardPending = "pending"
+)
 
 type CGNATMapping struct {
 	SessionID      string `json:"session_id,omitempty"`
@@ -16,18 +35,177 @@ type CGNATMapping struct {
 	PortBlockStart uint16 `json:"port_block_start"`
 	PortBlockEnd   uint16 `json:"port_block_end"`
 	SwIfIndex      uint32 `json:"sw_if_index"`
+	// Forward is set when the mapping is a single-port forward; the
+	// outside port is then PortBlockStart (and PortBlockEnd).
+	Forward *CGNATPortForward `json:"forward,omitempty"`
+	// Extra is set on a block held on top of the subscriber's first,
+	// given by a subscriber-limits escalation.
+	Extra bool `json:"extra,omitempty"`
+}
+
+// CGNATPortForward is the single-port part of a forward mapping.
+type CGNATPortForward struct {
+	Protocol    string     `json:"protocol"`
+	InsidePort  uint16     `json:"inside_port"`
+	Source      string     `json:"source"`
+	State       string     `json:"state,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Nonce       []byte     `json:"nonce,omitempty"`
+}
+
+// CGNATPortForwardRequest asks for OutsideIP:OutsidePort to be
+// forwarded to InsideIP:InsidePort.
+type CGNATPortForwardRequest struct {
+	Protocol    string `json:"protocol"`
+	InsideIP    string `json:"inside_ip"`
+	InsidePort  uint16 `json:"inside_port"`
+	OutsideIP   string `json:"outside_ip"`
+	OutsidePort uint16 `json:"outside_port"`
+	Description string `json:"description,omitempty"`
+}
+
+// ExtraBlockKey identifies an extra block beside its session's first
+// block, as "<session>/block/<outside-ip>/<port-block-start>".
+func (m *CGNATMapping) ExtraBlockKey() string {
+	return fmt.Sprintf("%s/block/%s/%d", m.SessionID, m.OutsideIP, m.PortBlockStart)
+}
+
+// ForwardID identifies a forward by its inside tuple, which is unique
+// across forwards; it is "" for block mappings.
+func (m *CGNATMapping) ForwardID() string {
+	if m == nil || m.Forward == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%d", m.Forward.Protocol, m.InsideIP, m.Forward.InsidePort)
+}
+
+// Mapping archive events. Each segment opens with a snapshot marker
+// followed by an active record for every block held at that time.
+const (
+	CGNATArchiveAllocate = "allocate"
+	CGNATArchiveRelease  = "release"
+	CGNATArchiveSnapshot = "snapshot"
+	CGNATArchiveActive   = "active"
+)
+
+// CGNATArchiveRecord is one port block allocation or release in the
+// mapping archive. Username and MAC are those of the session when the
+// block was allocated. AllocatedAt is set on active records when the
+// allocation time is known.
+type CGNATArchiveRecord struct {
+	Time           time.Time  `json:"time"`
+	Event          string     `json:"event"`
+	PoolName       string     `json:"pool_name"`
+	OutsideIP      net.IP     `json:"outside_ip"`
+	PortBlockStart uint16     `json:"port_block_start"`
+	PortBlockEnd   uint16     `json:"port_block_end"`
+	InsideIP       net.IP     `json:"inside_ip"`
+	InsideVRFID    uint32     `json:"inside_vrf_id,omitempty"`
+	SessionID      string     `json:"session_id,omitempty"`
+	Username       string     `json:"username,omitempty"`
+	MAC            string     `json:"mac,omitempty"`
+	AllocatedAt    *time.Time `json:"allocated_at,omitempty"`
+}
+
+// CGNATMappingOwner answers who held an outside address and port at a
+// point in time. Method is "archive" when the answer comes from an
+// archived allocation, which then fills the session fields and, unless
+// the block was already held when the archive started, AllocatedAt, or
+// "deterministic" when it is computed from the pool layout.
+type CGNATMappingOwner struct {
+	Method         string     `json:"method"`
+	PoolName       string     `json:"pool_name"`
+	OutsideIP      net.IP     `json:"outside_ip"`
+	OutsidePort    uint16     `json:"outside_port"`
+	PortBlockStart uint16     `json:"port_block_start"`
+	PortBlockEnd   uint16     `json:"port_block_end"`
+	InsideIP       net.IP     `json:"inside_ip"`
+	InsideVRFID    uint32     `json:"inside_vrf_id,omitempty"`
+	SessionID      string     `json:"session_id,omitempty"`
+	Username       string     `json:"username,omitempty"`
+	MAC            string     `json:"mac,omitempty"`
+	AllocatedAt    *time.Time `json:"allocated_at,omitempty"`
 }
 
 type CGNATPoolStats struct {
-	Name               string  `json:"name"               metric:"label"`
-	Mode               string  `json:"mode"               metric:"label"`
-	TotalAddresses     uint32  `json:"total_addresses"    metric:"name=cgnat.pool.addresses_total,type=gauge,help=Total addresses in this CGNAT pool."`
-	AllocatedAddresses uint32  `json:"allocated_addresses" metric:"name=cgnat.pool.addresses_allocated,type=gauge,help=Allocated addresses in this CGNAT pool."`
-	FreeBlocks         uint32  `json:"free_blocks"        metric:"name=cgnat.pool.blocks_free,type=gauge,help=Free port-blocks in this CGNAT pool."`
-	TotalBlocks        uint32  `json:"total_blocks"       metric:"name=cgnat.pool.blocks_total,type=gauge,help=Total port-blocks in this CGNAT pool."`
-	ExcludedAddresses  uint32  `json:"excluded_addresses" metric:"name=cgnat.pool.addresses_excluded,type=gauge,help=Excluded addresses in this CGNAT pool."`
-	SubscriberCount    uint32  `json:"subscriber_count"   metric:"name=cgnat.pool.subscribers,type=gauge,help=Subscribers mapped to this CGNAT pool."`
-	Utilization        float64 `json:"utilization"        metric:"name=cgnat.pool.utilization,type=gauge,help=CGNAT pool utilization (0.0 to 1.0)."`
+	Name                string  `json:"name"               metric:"label"`
+	Mode                string  `json:"mode"               metric:"label"`
+	TotalAddresses      uint32  `json:"total_addresses"    metric:"name=cgnat.pool.addresses_total,type=gauge,help=Total addresses in this CGNAT pool."`
+	AllocatedAddresses  uint32  `json:"allocated_addresses" metric:"name=cgnat.pool.addresses_allocated,type=gauge,help=Allocated addresses in this CGNAT pool."`
+	FreeBlocks          uint32  `json:"free_blocks"        metric:"name=cgnat.pool.blocks_free,type=gauge,help=Free port-blocks in this CGNAT pool."`
+	TotalBlocks         uint32  `json:"total_blocks"       metric:"name=cgnat.pool.blocks_total,type=gauge,help=Total port-blocks in this CGNAT pool."`
+	ExcludedAddresses   uint32  `json:"excluded_addresses" metric:"name=cgnat.pool.addresses_excluded,type=gauge,help=Excluded addresses in this CGNAT pool."`
+	SubscriberCount     uint32  `json:"subscriber_count"   metric:"name=cgnat.pool.subscribers,type=gauge,help=Subscribers mapped to this CGNAT pool."`
+	Utilization         float64 `json:"utilization"        metric:"name=cgnat.pool.utilization,type=gauge,help=CGNAT pool utilization (0.0 to 1.0)."`
+	Level               string  `json:"level"`
+	LevelCode           uint8   `json:"-"                  metric:"name=cgnat.pool.threshold_level,type=gauge,help=CGNAT pool watermark level: 0 normal, 1 high, 2 exhausted."`
+	SessionLimitDrops   uint64  `json:"session_limit_drops" metric:"name=cgnat.pool.session_limit_drops,type=counter,help=Packets dropped at a subscriber's session limit in this CGNAT pool."`
+	PortExhaustionDrops uint64  `json:"port_exhaustion_drops" metric:"name=cgnat.pool.port_exhaustion_drops,type=counter,help=Packets dropped for want of a free port in a subscriber's blocks in this CGNAT pool."`
+}
+
+// CGNATSubscriberUsage is one PBA subscriber's port usage: the sessions
+// it has open against the ports of its blocks, and the packets the
+// dataplane has dropped at its limits since the session came up.
+type CGNATSubscriberUsage struct {
+	PoolName            string  `json:"pool_name"`
+	InsideIP            net.IP  `json:"inside_ip"`
+	InsideVRFID         uint32  `json:"inside_vrf_id,omitempty"`
+	SessionID           string  `json:"session_id,omitempty"`
+	Blocks              int     `json:"blocks"`
+	Ports               uint32  `json:"ports"`
+	ActiveSessions      uint32  `json:"active_sessions"`
+	Utilization         float64 `json:"utilization"`
+	SessionLimitDrops   uint64  `json:"session_limit_drops"`
+	PortExhaustionDrops uint64  `json:"port_exhaustion_drops"`
+}
+
+// Outside address states: active addresses take new blocks, draining
+// ones only keep the blocks they hold, and drained ones hold none and
+// can be removed.
+const (
+	CGNATAddressActive   = "active"
+	CGNATAddressDraining = "draining"
+	CGNATAddressDrained  = "drained"
+)
+
+// CGNATOutsideAddress is one outside address of a pool and the blocks
+// it holds. Runtime is set for addresses added to the pool after
+// start rather than configured.
+type CGNATOutsideAddress struct {
+	PoolName        string    `json:"pool_name"`
+	Address         net.IP    `json:"address"`
+	State           string    `json:"state"`
+	Runtime         bool      `json:"runtime,omitempty"`
+	Excluded        bool      `json:"excluded,omitempty"`
+	TotalBlocks     uint32    `json:"total_blocks"`
+	AllocatedBlocks uint32    `json:"allocated_blocks"`
+	Subscribers     uint32    `json:"subscribers"`
+	DrainStarted    time.Time `json:"drain_started,omitempty"`
+	MigrateAt       time.Time `json:"migrate_at,omitempty"`
+}
+
+// Runtime outside address changes.
+const (
+	CGNATAddressAdd    = "add"
+	CGNATAddressDrain  = "drain"
+	CGNATAddressRemove = "remove"
+)
+
+// CGNATAddressChange is a change to a pool's outside addresses made
+// while running, kept until the configuration catches up with it. A
+// drain covers every address of the pool within Prefix; an add or a
+// remove is of exactly Prefix.
+type CGNATAddressChange struct {
+	Pool      string    `json:"pool"`
+	Action    string    `json:"action"`
+	Prefix    string    `json:"prefix"`
+	Started   time.Time `json:"started"`
+	MigrateAt time.Time `json:"migrate_at,omitempty"`
+}
+
+func (c *CGNATAddressChange) Key() string {
+	return c.Pool + "/" + c.Action + "/" + c.Prefix
 }
 
 type CGNATSessionInfo struct {
@@ -90,3 +268,18 @@ type CGNATBypassEntry struct {
 	Prefix      string `json:"prefix"`
 	InsideVRFID uint32 `json:"inside_vrf_id"`
 }
+
+// MAPOwner is the CE a MAP domain's rules assign an outside IPv4 address
+// and port to, and the subscriber holding the CE's delegated prefix when
+// one is up.
+type MAPOwner struct {
+	Domain        string `json:"domain"`
+	IPv4Address   net.IP `json:"ipv4_address"`
+	Port          uint16 `json:"port"`
+	PSID          uint16 `json:"psid"`
+	EndUserPrefix string `json:"end_user_prefix"`
+	SessionID     string `json:"session_id,omitempty"`
+	Username      string `json:"username,omitempty"`
+	MAC           string `json:"mac,omitempty"`
+	IPv6Prefix    string `json:"ipv6_prefix,omitempty"`
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package models
+
+// FlowSpec rule states: programmed on every interface of its family,
+// matching or acting in a way the dataplane cannot, past the family's
+// max-rules, failed to program, or without an action to take.
+const (
+	FlowSpecProgrammed  = "programmed"
+	FlowSpecUnsupported = "unsupported"
+	FlowSpecOverLimit   = "over-limit"
+	FlowSpecFailed      = "failed"
+	FlowSpecNoAction    = "no-action"
+)
+
+// FlowSpecRule is a BGP FlowSpec rule installed by the routing daemon,
+// in match order within its family. Match holds the rule's components
+// as FRR prints them, keyed by FRR's short names ("to", "dstp", ...);
+// Actions its extended communities.
+type FlowSpecRule struct {
+	Key     string            `json:"key"`
+	Family  string            `json:"family"`
+	Match   map[string]string `json:"match"`
+	Actions string            `json:"actions,omitempty"`
+	// Action is what the rule does in the dataplane: deny, rate-limit
+	// or redirect.
+	Action    string `json:"action,omitempty"`
+	RateBytes uint64 `json:"rate_bytes_per_second,omitempty"`
+	VRF       string `json:"vrf,omitempty"`
+	// Entries is how many ACL entries the match expands to.
+	Entries    int                   `json:"entries,omitempty"`
+	Status     string                `json:"status"`
+	Reason     string                `json:"reason,omitempty"`
+	Interfaces []FlowSpecProgramming `json:"interfaces,omitempty"`
+}
+
+// FlowSpecProgramming is a rule as programmed on one interface, with
+// its hit counters. Redirects have none.
+type FlowSpecProgramming struct {
+	Interface   string `json:"interface"`
+	Programming string `json:"programming"`
+	Packets     uint64 `json:"packets"`
+	Bytes       uint64 `json:"bytes"`
+	Drops       uint64 `json:"drops"`
+}
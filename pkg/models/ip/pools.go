@@ -0,0 +1,12 @@
+package ip
+
+type PoolStats struct {
+	Family      string  `json:"family"      metric:"label"`
+	Profile     string  `json:"profile"     metric:"label"`
+	Pool        string  `json:"pool"        metric:"label"`
+	Size        uint64  `json:"size"        metric:"name=ip.pool.size,type=gauge,help=Allocatable addresses (or delegated prefixes) in this pool."`
+	Available   uint64  `json:"available"   metric:"name=ip.pool.available,type=gauge,help=Free addresses (or delegated prefixes) in this pool."`
+	Utilization float64 `json:"utilization" metric:"name=ip.pool.utilization,type=gauge,help=Pool utilization (0.0 to 1.0)."`
+	Level       string  `json:"level"`
+	LevelCode   uint8   `json:"-"           metric:"name=ip.pool.threshold_level,type=gauge,help=Pool watermark level: 0 normal, 1 high, 2 exhausted."`
+}
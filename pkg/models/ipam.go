@@ -0,0 +1,46 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// IPAM chunk families, matching the allocator's pool families.
+const (
+	IPAMChunkIPv4 = "ipv4"
+	IPAMChunkIANA = "iana"
+	IPAMChunkPD   = "pd"
+)
+
+// IPAMChunk is a subnet taken from a central IPAM and added to a profile
+// as a pool. Owner is the node ID of the BNG that requested it; the HA
+// peer holds a copy so it can serve the chunk's subscribers on failover.
+type IPAMChunk struct {
+	Family      string    `json:"family"`
+	Profile     string    `json:"profile"`
+	Pool        string    `json:"pool"`
+	Prefix      string    `json:"prefix"`
+	VRF         string    `json:"vrf,omitempty"`
+	Owner       string    `json:"owner,omitempty"`
+	IPAMID      string    `json:"ipam_id,omitempty"`
+	AllocatedAt time.Time `json:"allocated_at"`
+}
+
+// Key identifies the chunk across restarts and between HA peers.
+func (c *IPAMChunk) Key() string {
+	return c.Family + "/" + c.Profile + "/" + c.Pool
+}
+
+// PoolKey is the chunk's pool key in the allocator registry.
+func (c *IPAMChunk) PoolKey() string {
+	return c.Profile + "/" + c.Pool
+}
+
+// IPAMChunkPoolName names the pool a chunk of prefix is added as.
+func IPAMChunkPoolName(prefix string) string {
+	return "ipam-" + strings.NewReplacer("/", "_", ":", "-").Replace(prefix)
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package models
+
+import "time"
+
+// Where an NPTv6 binding's internal prefix came from.
+const (
+	NPTv6SourceAAA          = "aaa"
+	NPTv6SourceServiceGroup = "service-group"
+)
+
+// NPTv6Binding is the stateless prefix translation (RFC 6296) of one
+// session: packets from Internal leaving on the Uplink are rewritten to
+// External, the subscriber's delegated prefix, and back on return.
+// SwIfIndex is the uplink's, SessionSwIfIndex the session interface the
+// internal prefix is routed to.
+type NPTv6Binding struct {
+	SessionID        string    `json:"session_id"`
+	Username         string    `json:"username,omitempty"`
+	ServiceGroup     string    `json:"service_group,omitempty"`
+	VRF              string    `json:"vrf,omitempty"`
+	Uplink           string    `json:"uplink"`
+	SwIfIndex        uint32    `json:"sw_if_index"`
+	SessionSwIfIndex uint32    `json:"session_sw_if_index"`
+	Internal         string    `json:"internal_prefix"`
+	External         string    `json:"external_prefix"`
+	Source           string    `json:"source"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package models
+
+import "time"
+
+// Blackhole sources: the northbound API, or a RADIUS CoA (or API
+// mutation) carrying the blackhole attribute for a session.
+const (
+	BlackholeSourceAPI = "api"
+	BlackholeSourceAAA = "aaa"
+)
+
+// Blackhole is a remote-triggered blackhole: traffic toward Prefix is
+// dropped in the dataplane and, with BGP, upstream until ExpiresAt.
+// Owner is the node ID of the BNG it was requested on; the HA peer
+// holds a copy and drops the same traffic.
+type Blackhole struct {
+	Prefix    string    `json:"prefix"`
+	VRF       string    `json:"vrf,omitempty"`
+	Source    string    `json:"source"`
+	SessionID string    `json:"session_id,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Key identifies the blackhole across restarts and between HA peers.
+func (b *Blackhole) Key() string {
+	return b.VRF + "/" + b.Prefix
+}
+
+// BlackholeRequest asks for a blackhole of an address or prefix. A bare
+// address is a /32 or /128. Duration is a Go duration ("30m"); empty
+// is the configured default. Requesting a blackhole that exists
+// replaces its expiry.
+type BlackholeRequest struct {
+	Prefix   string `json:"prefix"`
+	VRF      string `json:"vrf,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BlackholeStatus is a blackhole with where it is installed.
+type BlackholeStatus struct {
+	Blackhole
+	// Remaining is the time left until it expires, in seconds.
+	Remaining  int64  `json:"remaining_seconds"`
+	Installed  bool   `json:"installed"`
+	Advertised bool   `json:"advertised"`
+	Error      string `json:"error,omitempty"`
+}
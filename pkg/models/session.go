@@ -49,6 +49,7 @@ type IPoESession struct {
 	AccessInterface string
 	VRF             string
 	ServiceGroup    string
+	SteeringPolicy  string
 	SRGName         string
 
 	IPv4Address net.IP
@@ -167,6 +168,7 @@ type PPPSession struct {
 	AccessInterface string
 	VRF             string
 	ServiceGroup    string
+	SteeringPolicy  string
 	SRGName         string
 
 	IPv4Address net.IP
@@ -231,6 +233,87 @@ type L2TPTunnelSummary struct {
 	State         string    `json:"State"`
 	SessionCount  int       `json:"SessionCount"`
 	CreatedAt     time.Time `json:"CreatedAt"`
+
+	// Standby tunnels are HA-synced control state owned by the peer.
+	Standby bool `json:"Standby,omitempty"`
+
+	// Control-channel sequence and congestion state (RFC 2661 §5.8).
+	Ns      uint16           `json:"Ns"`
+	Nr      uint16           `json:"Nr"`
+	Cwnd    int              `json:"Cwnd"`
+	Control L2TPControlStats `json:"Control"`
+}
+
+// L2TPControlStats counts a tunnel's control-channel traffic.
+// Retransmits are reported as Acct-Tunnel-Packets-Lost.
+type L2TPControlStats struct {
+	Sent        uint64 `json:"Sent"`
+	Retransmits uint64 `json:"Retransmits"`
+	Received    uint64 `json:"Received"`
+	Duplicates  uint64 `json:"Duplicates"`
+	ZLBSent     uint64 `json:"ZLBSent"`
+	ZLBReceived uint64 `json:"ZLBReceived"`
+}
+
+// L2TPSessionDetail is one row of `show l2tp sessions`: a session with
+// its tunnel identity, LAC and LNS alike.
+type L2TPSessionDetail struct {
+	SessionID      string    `json:"SessionID"`
+	AcctSessionID  string    `json:"AcctSessionID,omitempty"`
+	Role           string    `json:"Role"`
+	State          string    `json:"State"`
+	Standby        bool      `json:"Standby,omitempty"`
+	LocalIP        string    `json:"LocalIP"`
+	PeerIP         string    `json:"PeerIP"`
+	LocalTunnelID  uint16    `json:"LocalTunnelID"`
+	PeerTunnelID   uint16    `json:"PeerTunnelID"`
+	LocalSessionID uint16    `json:"LocalSessionID"`
+	PeerSessionID  uint16    `json:"PeerSessionID"`
+	PeerHostname   string    `json:"PeerHostname,omitempty"`
+	Username       string    `json:"Username,omitempty"`
+	PPPPhase       string    `json:"PPPPhase,omitempty"`
+	IPv4Address    string    `json:"IPv4Address,omitempty"`
+	IPv6Address    string    `json:"IPv6Address,omitempty"`
+	IPv6Prefix     string    `json:"IPv6Prefix,omitempty"`
+	VRF            string    `json:"VRF,omitempty"`
+	ServiceGroup   string    `json:"ServiceGroup,omitempty"`
+	SRGName        string    `json:"SRGName,omitempty"`
+	PPPoESessionID uint16    `json:"PPPoESessionID,omitempty"`
+	IfIndex        uint32    `json:"IfIndex,omitempty"`
+	ActivatedAt    time.Time `json:"ActivatedAt,omitempty"`
+}
+
+// L2TPDenylistEntry is one LNS peer the LAC has given up on. Expired
+// entries stay listed as probe-eligible until a success clears them.
+type L2TPDenylistEntry struct {
+	PeerIP        string    `json:"PeerIP"`
+	Reason        string    `json:"Reason"`
+	AddedAt       time.Time `json:"AddedAt"`
+	ExpiresAt     time.Time `json:"ExpiresAt"`
+	ProbeEligible bool      `json:"ProbeEligible"`
+}
+
+// L2TPLNSStatus is the LAC's view of one tunnel-pool LNS: its health
+// check state and the load the LAC puts on it.
+type L2TPLNSStatus struct {
+	Pool                string    `json:"Pool"                metric:"label"`
+	Name                string    `json:"Name"                metric:"label"`
+	Address             string    `json:"Address"             metric:"label"`
+	State               string    `json:"State"`
+	Up                  bool      `json:"Up"                  metric:"name=l2tp.lns.up,type=gauge,help=LNS passes its health check (1 if up)."`
+	Available           bool      `json:"Available"           metric:"name=l2tp.lns.available,type=gauge,help=LNS is selectable for new LAC sessions (1 if available)."`
+	Denylisted          bool      `json:"Denylisted"`
+	Preference          uint16    `json:"Preference"`
+	Weight              uint16    `json:"Weight"              metric:"name=l2tp.lns.weight,type=gauge,help=Configured LNS selection weight."`
+	MaxSessions         int       `json:"MaxSessions"`
+	Sessions            int       `json:"Sessions"            metric:"name=l2tp.lns.sessions,type=gauge,help=LAC sessions carried to this LNS."`
+	Tunnels             int       `json:"Tunnels"             metric:"name=l2tp.lns.tunnels,type=gauge,help=LAC tunnels open to this LNS."`
+	Probes              uint64    `json:"Probes"              metric:"name=l2tp.lns.probes,type=counter,help=Health-check probes sent to this LNS."`
+	ProbeFailures       uint64    `json:"ProbeFailures"       metric:"name=l2tp.lns.probe_failures,type=counter,help=Failed health-check probes to this LNS."`
+	ConsecutiveFailures int       `json:"ConsecutiveFailures"`
+	LastProbe           time.Time `json:"LastProbe"`
+	LastChange          time.Time `json:"LastChange"`
+	LastError           string    `json:"LastError,omitempty"`
 }
 
 func (s *PPPSession) GetSessionID() string      { return s.SessionID }
@@ -283,6 +366,28 @@ type PPPoL2TPSession struct {
 	TunnelAssignmentID string
 	LACHostname        string
 
+	// Tunnel state carried for restore and HA takeover. Role is "lac"
+	// or "lns"; Ns / Nr snapshot the control channel's next-send and
+	// next-expected sequence numbers when the model was built. Failover
+	// is set when both ends agreed to RFC 4951 failover, the only case
+	// in which the standby can recover the tunnel.
+	Role          string
+	LocalPort     uint16
+	PeerPort      uint16
+	LocalHostname string
+	PeerHostname  string
+	Ns            uint16
+	Nr            uint16
+	HelloInterval time.Duration
+	PPPHdrSkip    uint8
+
+	Failover         bool
+	PeerRecoveryTime time.Duration
+
+	// PPPoESessionID is the partner PPPoE session of a LAC-role
+	// session. Zero on the LNS.
+	PPPoESessionID uint16
+
 	IfIndex      uint32
 	VRF          string
 	ServiceGroup string
@@ -293,6 +398,7 @@ type PPPoL2TPSession struct {
 	IPv6Prefix  string
 	IPv4Pool    string
 	IANAPool    string
+	PDPool      string
 
 	LCPState    string
 	IPCPState   string
@@ -0,0 +1,38 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package models
+
+import "time"
+
+// Steering next-hop health states. A next hop without a health check is
+// unchecked and always used; one with a health check is used until its
+// probes mark it down.
+const (
+	SteeringHopUnchecked = "unchecked"
+	SteeringHopUnknown   = "unknown"
+	SteeringHopUp        = "up"
+	SteeringHopDown      = "down"
+)
+
+// SteeringPolicy is a steering policy as programmed. FailOpen is set
+// while none of its next hops is up and its sessions forward normally.
+type SteeringPolicy struct {
+	Name       string            `json:"name"`
+	AccessList string            `json:"access_list"`
+	FailOpen   bool              `json:"fail_open"`
+	NextHops   []SteeringNextHop `json:"next_hops"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// SteeringNextHop is one next hop of a steering policy. Active is set
+// when the policy currently forwards to it.
+type SteeringNextHop struct {
+	Address    string    `json:"address"`
+	VRF        string    `json:"vrf,omitempty"`
+	State      string    `json:"state"`
+	Active     bool      `json:"active"`
+	LastChange time.Time `json:"last_change,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
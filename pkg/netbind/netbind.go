@@ -112,6 +112,29 @@ func ListenTCP(ctx context.Context, network, addr string, b Binding) (net.Listen
 	return ln, err
 }
 
+// ListenICMP opens a raw ICMP socket of the family, for echo probes sent
+// from the binding's VRF and source IP. It needs CAP_NET_RAW.
+func ListenICMP(ctx context.Context, family Family, b Binding) (net.PacketConn, error) {
+	network, addr := "ip4:icmp", "0.0.0.0"
+	if family == FamilyV6 {
+		network, addr = "ip6:ipv6-icmp", "::"
+	}
+	if b.SourceIP.IsValid() {
+		addr = b.SourceIP.String()
+	}
+
+	var pc net.PacketConn
+	err := withNetNS(b, func() error {
+		c, lerr := b.listenConfig().ListenPacket(ctx, network, addr)
+		if lerr != nil {
+			return fmt.Errorf("netbind: listen %s %s: %w", network, b, lerr)
+		}
+		pc = c
+		return nil
+	})
+	return pc, err
+}
+
 func DialUDP(ctx context.Context, network string, raddr *net.UDPAddr, b Binding) (*net.UDPConn, error) {
 	var uc *net.UDPConn
 	err := withNetNS(b, func() error {
@@ -130,6 +153,19 @@ func DialUDP(ctx context.Context, network string, raddr *net.UDPAddr, b Binding)
 	return uc, err
 }
 
+func DialTCP(ctx context.Context, network, addr string, b Binding, timeout time.Duration) (net.Conn, error) {
+	var conn net.Conn
+	err := withNetNS(b, func() error {
+		c, derr := b.dialer(network, timeout).DialContext(ctx, network, addr)
+		if derr != nil {
+			return fmt.Errorf("netbind: dial %s %s: %w", network, b, derr)
+		}
+		conn = c
+		return nil
+	})
+	return conn, err
+}
+
 func HTTPClient(b Binding, timeout time.Duration) *http.Client {
 	d := b.dialer("tcp", timeout)
 
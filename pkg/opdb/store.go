@@ -28,6 +28,7 @@ const (
 	NamespaceHASyncedPPPoE     = "ha_synced_pppoe"
 	NamespaceHASyncedCGNAT     = "ha_synced_cgnat"
 	NamespaceHASyncedL2GW      = "ha_synced_l2gw"
+	NamespaceHASyncedL2TP      = "ha_synced_l2tp"
 	// NamespaceAcctSessions holds per-session accounting state owned and
 	// evolved entirely by the internal/aaa component. Kept separate from
 	// the per-protocol session namespaces so AAA can extend its schema
@@ -3,6 +3,8 @@ package pppoe
 import (
 	"encoding/binary"
 	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/accessline"
 )
 
 const (
@@ -39,6 +41,7 @@ type Tags struct {
 	VendorSpecific []byte
 	AgentCircuitID string
 	AgentRemoteID  string
+	AccessLine     accessline.Characteristics
 	PPPMaxPayload  uint16
 	Errors         []string
 	Raw            []Tag
@@ -114,6 +117,10 @@ func (t *Tags) parseVendorSpecific(data []byte) {
 		return
 	}
 
+	if vendorID == VendorIDBBF {
+		t.AccessLine = accessline.Parse(data[4:])
+	}
+
 	offset := 4
 	for offset+2 <= len(data) {
 		subType := data[offset]
@@ -135,6 +135,29 @@ func TestParseTags_EndOfList(t *testing.T) {
 	}
 }
 
+func TestParseTags_BBFAccessLine(t *testing.T) {
+	// Vendor-Specific, BBF: Agent-Circuit-ID "c1", Actual-Data-Rate
+	// Upstream 1024 and Downstream 16384 kbps
+	payload := []byte{
+		0x01, 0x05, 0x00, 0x14,
+		0x00, 0x00, 0x0D, 0xE9,
+		0x01, 0x02, 'c', '1',
+		0x81, 0x04, 0x00, 0x00, 0x04, 0x00,
+		0x82, 0x04, 0x00, 0x00, 0x40, 0x00,
+	}
+
+	tags, err := ParseTags(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tags.AgentCircuitID != "c1" {
+		t.Errorf("expected circuit-id 'c1', got %q", tags.AgentCircuitID)
+	}
+	if tags.AccessLine.ActualRateUp != 1024 || tags.AccessLine.ActualRateDown != 16384 {
+		t.Errorf("expected line rates 1024/16384, got %+v", tags.AccessLine)
+	}
+}
+
 func TestParseTags_TruncatedHeader(t *testing.T) {
 	// Only 3 bytes - not enough for tag header
 	// Parser gracefully handles this by returning empty tags
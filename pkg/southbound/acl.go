@@ -0,0 +1,43 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+
+// ACL programs the named access lists the Policy methods attach to
+// interfaces. An ACL keeps its dataplane index while it exists: a change
+// of rules replaces them in place, so interfaces bound to it pick up the
+// new rules in one transition without being rebound.
+type ACL interface {
+	// AddReplaceACL creates the ACL, or replaces the rules of the one
+	// already known by that name, and returns its index. An ACL left in
+	// the dataplane by an earlier run is found by name and adopted.
+	AddReplaceACL(name string, entries []aclcfg.Entry) (uint32, error)
+
+	// DeleteACL removes the ACL. Deleting an unknown name is a no-op;
+	// the dataplane refuses to delete one still bound to an interface.
+	DeleteACL(name string) error
+
+	// DumpACLs lists the ACLs in the dataplane in index order.
+	DumpACLs() ([]ACLDetails, error)
+
+	// GetACLRuleStats reads the per-entry match counters, keyed by ACL
+	// index, summed across workers.
+	GetACLRuleStats() (map[uint32][]ACLRuleStats, error)
+}
+
+// ACLDetails is one ACL as the dataplane holds it. Name is the tag it
+// was created with.
+type ACLDetails struct {
+	Index   uint32 `json:"index"`
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+}
+
+// ACLRuleStats is one entry's cumulative matches.
+type ACLRuleStats struct {
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
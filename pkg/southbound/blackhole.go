@@ -0,0 +1,21 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net/netip"
+
+// Blackhole drops the traffic toward a prefix. The discard route takes
+// precedence over every route the BNG installs for the prefix, such as
+// a session's host route, which takes over again when it is removed.
+type Blackhole interface {
+	// AddBlackholeRoute installs a discard route for prefix in the
+	// VRF's table, the default table when vrf is empty. Adding a route
+	// that is installed is a no-op.
+	AddBlackholeRoute(prefix netip.Prefix, vrf string) error
+
+	// DeleteBlackholeRoute removes the discard route. Removing a route
+	// that is not installed is a no-op.
+	DeleteBlackholeRoute(prefix netip.Prefix, vrf string) error
+}
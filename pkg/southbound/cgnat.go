@@ -5,9 +5,15 @@
 package southbound
 
 import (
+	"errors"
 	"net"
 )
 
+// ErrCGNATLimitCountersUnavailable reports a dataplane without the CGNAT
+// plugin's limit error counters, so subscriber-limits cannot see a
+// subscriber hitting its limits.
+var ErrCGNATLimitCountersUnavailable = errors.New("dataplane does not export the CGNAT limit error counters")
+
 type CGNATMapping struct {
 	PoolID         uint32
 	SwIfIndex      uint32
@@ -87,6 +93,16 @@ type CGNATSession struct {
 	TotalBytes     uint64
 }
 
+// CGNATLimitDrops are the plugin's limit error counters, summed across
+// its nodes and workers: packets dropped because a subscriber was at
+// its max-sessions-per-subscriber, and because a subscriber's port
+// blocks had no free port left for a new session. The plugin does not
+// count them per subscriber.
+type CGNATLimitDrops struct {
+	SessionLimitDrops   uint64
+	PortExhaustionDrops uint64
+}
+
 type CGNATDataplane interface {
 	CGNATPoolAddDel(poolID uint32, mode uint8, addressPooling uint8,
 		filtering uint8, blockSize uint16, maxBlocksPerSub uint8,
@@ -132,4 +148,9 @@ type CGNATDataplane interface {
 
 	CGNATDumpSessions(f CGNATSessionFilter) ([]CGNATSession, error)
 	CGNATSessionCount() (uint64, error)
+
+	// GetCGNATLimitDrops reads the limit error counters. It returns
+	// ErrCGNATLimitCountersUnavailable against a dataplane that does not
+	// export them rather than a silently zero answer.
+	GetCGNATLimitDrops() (CGNATLimitDrops, error)
 }
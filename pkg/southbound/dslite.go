@@ -0,0 +1,17 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// DSLite programs the dataplane's DS-Lite (RFC 6333) AFTR: the softwire
+// endpoint B4s tunnel to and the IPv4 addresses translations draw from.
+// The dataplane holds a single AFTR.
+type DSLite interface {
+	DSLiteSetAFTRAddr(ip6, ip4 net.IP) error
+	DSLiteGetAFTRAddr() (ip6, ip4 net.IP, err error)
+	DSLiteAddDelPoolAddrRange(start, end net.IP, isAdd bool) error
+	DSLiteAddressDump() ([]net.IP, error)
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+
+const (
+	FlowSpecDeny      = "deny"
+	FlowSpecRateLimit = "rate-limit"
+	FlowSpecRedirect  = "redirect"
+)
+
+// FlowSpec programs BGP FlowSpec rules on core-facing interfaces: deny
+// rules as an inbound ACL, rate limits as policers fed by classify
+// tables, and redirects as ABF policies that look the traffic up in
+// another VRF.
+type FlowSpec interface {
+	// SetFlowSpecRules makes rules the FlowSpec rules of the interface,
+	// in match order, replacing the ones it has. An interface given no
+	// rules is cleared. Setting the rules it already has is a no-op.
+	SetFlowSpecRules(swIfIndex uint32, rules []FlowSpecRule) error
+
+	// DumpFlowSpec reports how every programmed rule is programmed on
+	// each interface, with its hit counters.
+	DumpFlowSpec() ([]FlowSpecState, error)
+}
+
+// FlowSpecRule is one FlowSpec rule translated for the dataplane.
+// Entries are what it matches; their Action is ignored.
+type FlowSpecRule struct {
+	Key     string
+	Action  string
+	Entries []aclcfg.Entry
+	// RateBytes is the rate limit in bytes per second.
+	RateBytes uint64
+	// VRF is the redirect's target, the default table if empty.
+	VRF string
+}
+
+// FlowSpecState is one rule as programmed on one interface. A redirect
+// has no counters: ABF does not count its matches.
+type FlowSpecState struct {
+	SwIfIndex     uint32 `json:"sw_if_index"`
+	InterfaceName string `json:"interface,omitempty"`
+	Key           string `json:"key"`
+	Action        string `json:"action"`
+	Programming   string `json:"programming"`
+	Packets       uint64 `json:"packets"`
+	Bytes         uint64 `json:"bytes"`
+	Drops         uint64 `json:"drops"`
+}
@@ -41,10 +41,25 @@ type L2GW interface {
 	AddL2GWCircuit(circuit L2GWCircuit) (uint32, uint32, error)
 	DelL2GWCircuit(circuit L2GWCircuit) error
 	SetL2GWCircuitState(circuitID uint32, enabled bool) error
+	// MoveL2GWCircuits re-points circuits at another handoff interface
+	// for handoff-group failover, pipelining each circuit's delete and
+	// re-add so no round trip separates them. Each circuit is given as
+	// currently installed; results come back in input order.
+	MoveL2GWCircuits(circuits []L2GWCircuit, handoffIfIndex uint32) []L2GWMoveResult
 	DumpL2GWCircuits() ([]L2GWCircuitDetails, error)
 	GetL2GWStats() (map[uint32]L2GWEntryStats, error)
 }
 
+// L2GWMoveResult is the outcome of moving one circuit: the new circuit
+// id and handoff counter index, or the error that left it where it was
+// (Err set, Removed false) or deleted but not re-added (Removed true).
+type L2GWMoveResult struct {
+	CircuitID         uint32
+	HandoffEntryIndex uint32
+	Removed           bool
+	Err               error
+}
+
 // L2GWEntryStats is one direction's cumulative counters from the
 // /osvbng/l2gw stats segment, summed across workers.
 type L2GWEntryStats struct {
@@ -0,0 +1,20 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+// LocalSwitching filters the traffic subscribers send toward each other
+// through the BNG. Its ACL is matched on a session's inbound traffic
+// ahead of the session's inbound ACL: traffic the local-switching ACL
+// does not match goes on to the inbound ACL, or is permitted if the
+// session has none.
+type LocalSwitching interface {
+	// ApplyLocalSwitchingACL binds the ACL, created through
+	// AddReplaceACL, to the interface, replacing the one it has.
+	ApplyLocalSwitchingACL(swIfIndex uint32, aclName string) error
+
+	// RemoveLocalSwitchingACL unbinds it. Removing from an interface
+	// without one is a no-op.
+	RemoveLocalSwitchingACL(swIfIndex uint32) error
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// MAPDomain is one MAP domain (RFC 7597/7599) on the border relay. Src
+// is the BR address (MAP-E, a /128) or the DMR prefix (MAP-T); Tag names
+// the domain so reconciliation can find its own.
+type MAPDomain struct {
+	Index      uint32
+	IPv6Prefix net.IPNet
+	IPv4Prefix net.IPNet
+	Src        net.IPNet
+	EABitsLen  uint8
+	PSIDOffset uint8
+	PSIDLength uint8
+	MTU        uint16
+	Tag        string
+}
+
+// MAP programs the dataplane's stateless MAP-E/MAP-T border relay: the
+// domains and the interfaces the feature runs on, encapsulating or
+// translating.
+type MAP interface {
+	MAPAddDomain(d MAPDomain) (uint32, error)
+	MAPDelDomain(index uint32) error
+	MAPDomainDump() ([]MAPDomain, error)
+	MAPInterfaceEnableDisable(swIfIndex uint32, translation, enable bool) error
+}
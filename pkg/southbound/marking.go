@@ -0,0 +1,20 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+// Marking programs the marking maps sessions name through
+// Sessions.ApplyMarking. A table is indexed by DSCP and holds the new
+// DSCP of a remark map or the 802.1p priority of a PCP map.
+type Marking interface {
+	// SetMarkingMap creates the map or replaces its table. A PCP map
+	// changes the priority of its sessions' frames at once; a remark
+	// map is compiled into a session's classes, so sessions pick a
+	// new table up when their bindings are next applied.
+	SetMarkingMap(name string, pcp bool, table []uint8) error
+
+	// DeleteMarkingMap removes the map. Deleting an unknown name is a
+	// no-op.
+	DeleteMarkingMap(name string) error
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// NAT64Prefix is one translation prefix programmed in the dataplane.
+type NAT64Prefix struct {
+	Prefix net.IPNet
+	VRFID  uint32
+}
+
+// NAT64BIBEntry is one binding of the dataplane's NAT64 BIB: the
+// outside address and port the node chose for an inside transport
+// address.
+type NAT64BIBEntry struct {
+	InsideIP    net.IP
+	InsidePort  uint16
+	OutsideIP   net.IP
+	OutsidePort uint16
+	Protocol    uint8
+	VRFID       uint32
+}
+
+// NAT64 programs the dataplane's stateful NAT64 (RFC 6146): the
+// translation prefix, the IPv4 addresses translations draw from and the
+// interfaces the feature runs on. Inside interfaces are the subscriber
+// sessions; outside interfaces face the IPv4 network.
+type NAT64 interface {
+	NAT64Enable() error
+	NAT64AddDelPrefix(prefix net.IPNet, vrfID uint32, isAdd bool) error
+	NAT64PrefixDump() ([]NAT64Prefix, error)
+	NAT64AddDelPoolAddrRange(start, end net.IP, vrfID uint32, isAdd bool) error
+	NAT64PoolAddrDump() ([]net.IP, error)
+	NAT64AddDelInterface(swIfIndex uint32, inside, isAdd bool) error
+	NAT64SetTimeouts(udp, tcpEstablished, tcpTransitory, icmp uint32) error
+	NAT64BIBDump() ([]NAT64BIBEntry, error)
+}
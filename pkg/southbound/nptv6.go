@@ -0,0 +1,21 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// NPTv6 programs the dataplane's stateless IPv6-to-IPv6 network prefix
+// translation (RFC 6296): bindings on an interface that swap an internal
+// prefix for an external one of the same length, checksum-neutrally.
+// A binding rewrites the source of packets leaving the interface and the
+// destination of packets arriving on it, so it belongs on the uplink.
+// The dataplane holds one binding per interface.
+type NPTv6 interface {
+	NPTv6BindingAddDel(swIfIndex uint32, internal, external net.IPNet, isAdd bool) error
+	// NPTv6RouteAddDel routes the internal prefix to a session in the
+	// VRF's table: return traffic is looked up after its destination
+	// was translated back.
+	NPTv6RouteAddDel(internal net.IPNet, vrf string, swIfIndex uint32, nextHop net.IP, isAdd bool) error
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// PNATTuple is one side of a policy NAT binding. Unset fields (nil
+// addresses, zero ports or protocol) are left out of the match or the
+// rewrite.
+type PNATTuple struct {
+	Src     net.IP
+	Dst     net.IP
+	Proto   uint8
+	SrcPort uint16
+	DstPort uint16
+}
+
+// PNAT programs the dataplane's stateless policy NAT: bindings that
+// rewrite an exact 5-tuple match, attached to the IPv4 input path of an
+// interface. CGNAT uses it for single-port forwards the per-subscriber
+// block mapping cannot express.
+type PNAT interface {
+	PNATBindingAdd(match, rewrite PNATTuple) (uint32, error)
+	PNATBindingDel(index uint32) error
+	PNATBindingAttach(swIfIndex, index uint32) error
+	PNATBindingDetach(swIfIndex, index uint32) error
+	PNATFlowLookup(swIfIndex uint32, match PNATTuple) (uint32, error)
+}
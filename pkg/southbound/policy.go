@@ -10,7 +10,7 @@ package southbound
 // arguments replaces the existing programming in a single transition.
 //
 // The methods here are the integration surface used by pkg/svcgroup to drive
-// the service-group bindings of a subscriber session (ACL, URPF). QoS and
+// the service-group bindings of a subscriber session (ACL, URPF, steering). QoS and
 // scheduler programming still lives on the Sessions interface for backwards
 // compatibility; future work may consolidate them here.
 type Policy interface {
@@ -40,4 +40,14 @@ type Policy interface {
 	// DisableSourceVerify clears any uRPF programming on the interface
 	// for both IPv4 and IPv6 in the inbound direction.
 	DisableSourceVerify(swIfIndex uint32) error
+
+	// ApplySteering binds the interface to the named steering policy,
+	// replacing any policy it was bound to. A name the dataplane does
+	// not hold yet, or a policy failing open, is still recorded: the
+	// interface is attached once the policy is programmed.
+	ApplySteering(swIfIndex uint32, policy string) error
+
+	// RemoveSteering unbinds the interface from its steering policy.
+	// Idempotent on an interface with none.
+	RemoveSteering(swIfIndex uint32) error
 }
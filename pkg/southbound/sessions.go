@@ -40,6 +40,20 @@ type Sessions interface {
 	ApplyQoS(swIfIndex uint32, ingress, egress *qos.Policy) error
 	RemoveQoS(swIfIndex uint32) error
 
+	// ApplyQoSClasses programs the traffic classes of the session's
+	// policies: a classifier and a policer per ingress class, and the
+	// class-to-tin map of the egress scheduler. Idempotent per interface.
+	ApplyQoSClasses(swIfIndex uint32, ingress, egress *qos.Policy) error
+	RemoveQoSClasses(swIfIndex uint32) error
+	DumpQoSClasses() ([]QoSClassState, error)
+
+	// ApplyMarking records the session's DSCP and 802.1p marking and
+	// programs its egress priority. A remark is applied by the ingress
+	// classes, so it must precede ApplyQoSClasses, which is called for
+	// a session that remarks even when its policies have no classes.
+	ApplyMarking(swIfIndex uint32, m qos.Marking) error
+	RemoveMarking(swIfIndex uint32) error
+
 	ApplyScheduler(swIfIndex uint32, rateKbps uint32, cfg *qos.SchedulerConfig) error
 	RemoveScheduler(swIfIndex uint32) error
 	DumpSchedulers() ([]SchedulerState, error)
@@ -61,7 +75,8 @@ type Sessions interface {
 	DumpAggregates() ([]AggregateState, error)
 
 	// L2TPv2 tunnels (the transport protocol). IDs are passed in HOST
-	// byte order.
+	// byte order. Endpoints may be IPv4 or IPv6; both ends of one
+	// tunnel must share a family.
 	AddL2TPTunnel(local, peer net.IP, localID, peerID, localPort, peerPort uint16, dfBit bool) (uint32, error)
 	DeleteL2TPTunnel(local, peer net.IP, localID uint16) error
 
@@ -83,6 +98,23 @@ type Sessions interface {
 	PPPoL2TPSetDelegatedPrefix(swIfIndex uint32, prefix net.IPNet, nextHop net.IP, isAdd bool) error
 }
 
+// QoSClassState is one traffic class on one session. Ingress classes
+// count through their policer: conforming traffic is transmitted,
+// exceeding and violating traffic per the class's actions. Egress
+// classes are scheduler tins and count what the tin carried and dropped.
+type QoSClassState struct {
+	SwIfIndex      uint32 `json:"sw_if_index"         metric:"label"`
+	InterfaceName  string `json:"interface,omitempty" metric:"label"`
+	Direction      string `json:"direction"           metric:"label"`
+	Class          string `json:"class"               metric:"label"`
+	Tin            *uint8 `json:"tin,omitempty"`
+	Packets        uint64 `json:"packets"             metric:"name=qos.class.packets,type=counter,help=Packets matched by this QoS class."`
+	Bytes          uint64 `json:"bytes"               metric:"name=qos.class.bytes,type=counter,help=Bytes matched by this QoS class."`
+	ExceedPackets  uint64 `json:"exceed_packets"      metric:"name=qos.class.exceed_packets,type=counter,help=Packets of this QoS class above its committed rate."`
+	ViolatePackets uint64 `json:"violate_packets"     metric:"name=qos.class.violate_packets,type=counter,help=Packets of this QoS class above its excess rate."`
+	Drops          uint64 `json:"drops"               metric:"name=qos.class.drops,type=counter,help=Packets of this QoS class dropped."`
+}
+
 type SchedulerTinState struct {
 	// Tin is a label so each flattened element lands in its own series;
 	// without it all eight tins collapse into one label tuple.
@@ -18,7 +18,18 @@ type Southbound interface {
 	Tables
 	System
 	CGNATDataplane
+	DSLite
+	NAT64
+	MAP
+	PNAT
+	NPTv6
 	MSSClamp
 	Policy
+	ACL
+	Steering
+	Marking
+	Blackhole
+	FlowSpec
+	LocalSwitching
 	L2GW
 }
@@ -0,0 +1,32 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package southbound
+
+import "net"
+
+// Steering programs the policy-based forwarding behind steering
+// policies: traffic an access list permits on an interface bound through
+// Policy.ApplySteering is forwarded to the policy's next hops instead of
+// by the interface's table.
+type Steering interface {
+	// SetSteeringPolicy creates the policy or replaces its access list
+	// and next hops, keeping the interfaces bound to it. With no next
+	// hops the policy fails open: it is withdrawn from the dataplane
+	// and its interfaces forward normally until next hops are set
+	// again.
+	SetSteeringPolicy(name, accessList string, nextHops []SteeringNextHop) error
+
+	// DeleteSteeringPolicy withdraws the policy. Interfaces still bound
+	// to it forward normally and are attached again if a policy of the
+	// same name is set. Deleting an unknown name is a no-op.
+	DeleteSteeringPolicy(name string) error
+}
+
+// SteeringNextHop is a next hop resolved in VRF, or in the default table
+// if VRF is empty.
+type SteeringNextHop struct {
+	Address net.IP
+	VRF     string
+}
@@ -30,6 +30,7 @@ type IPv6RAConfig struct {
 	RouterLifetime uint32
 	MaxInterval    uint32
 	MinInterval    uint32
+	RDNSS          []net.IP // Recursive DNS Server option (RFC 8106)
 }
 
 type IPv6RAPrefixConfig struct {
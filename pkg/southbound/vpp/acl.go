@@ -5,27 +5,79 @@
 package vpp
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	govppapi "go.fd.io/govpp/api"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
 	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/acl"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/acl_types"
 	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
 )
 
-// aclRegistry tracks the name -> VPP ACL index mapping. ACL config-schema
-// work that populates this registry is tracked separately; until that lands,
-// ApplyIngressACL/ApplyEgressACL with a non-empty name resolves to "unknown
-// ACL" and the southbound logs a structured warning so operators can see the
-// gap. The per-interface list-set call is still issued on Remove and on
-// Apply-with-empty-name so the dataplane state always tracks the caller's
-// intent for the names that ARE wired.
+var _ southbound.ACL = (*VPP)(nil)
+
+// aclMaxProbes bounds the placeholder ACLs created while waiting for the
+// dataplane to hand out a pinned index.
+const aclMaxProbes = 4096
+
+// errACLIndexTaken is returned by createACLAtLocked when the index it
+// was asked for cannot be had.
+var errACLIndexTaken = errors.New("ACL index is not free")
+
+// aclRegistry tracks the name -> VPP ACL index mapping and the entries
+// behind each name, populated by the access-lists conf handler through
+// AddReplaceACL, and the ACLs bound to
+// each interface. VPP sets an interface's inbound and outbound ACLs in one
+// list, so binding one direction has to resend the other.
 type aclRegistry struct {
 	mu        sync.RWMutex
 	nameToIdx map[string]uint32
+	// entries are what AddReplaceACL last programmed, for QoS classes
+	// that match on an access list.
+	entries map[string][]aclcfg.Entry
+	// adopted is set once the ACLs left by an earlier run have been
+	// read back from the dataplane.
+	adopted bool
+	// unclaimed holds the adopted ACLs this run has not programmed yet,
+	// for PruneAdoptedACLs.
+	unclaimed map[string]struct{}
+	// pinned is the index each access list is created at: the one it
+	// had before the dataplane lost its state, or the HA peer's.
+	pinned map[string]uint32
+	// onIndexes is called after an access list's index changes.
+	onIndexes func()
+	// statsOn is set once per-ACL match counters are enabled.
+	statsOn bool
+
+	bindMu sync.Mutex
+	bound  map[uint32]aclBinding
+}
+
+type aclBinding struct {
+	// flowspec holds the interface's FlowSpec deny rules, matched first.
+	flowspec string
+	// local holds the session's local-switching deny rules, matched
+	// after FlowSpec and ahead of ingress.
+	local   string
+	ingress string
+	egress  string
 }
 
 func newACLRegistry() *aclRegistry {
-	return &aclRegistry{nameToIdx: make(map[string]uint32)}
+	return &aclRegistry{
+		nameToIdx: make(map[string]uint32),
+		entries:   make(map[string][]aclcfg.Entry),
+		unclaimed: make(map[string]struct{}),
+		pinned:    make(map[string]uint32),
+		bound:     make(map[uint32]aclBinding),
+	}
 }
 
 func (r *aclRegistry) lookup(name string) (uint32, bool) {
@@ -35,68 +87,521 @@ func (r *aclRegistry) lookup(name string) (uint32, bool) {
 	return idx, ok
 }
 
-// RegisterACL records the name -> index mapping returned by acl_add_replace.
-// Future ACL handler work in pkg/handlers/conf/acl/ will call this after
-// programming each operator-configured ACL into VPP.
+func (r *aclRegistry) entriesOf(name string) ([]aclcfg.Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.entries[name]
+	return e, ok
+}
+
+// RegisterACL records a name -> index mapping for an ACL programmed
+// outside AddReplaceACL.
 func (v *VPP) RegisterACL(name string, index uint32) {
 	v.aclReg.mu.Lock()
 	defer v.aclReg.mu.Unlock()
 	v.aclReg.nameToIdx[name] = index
 }
 
-// UnregisterACL removes the name -> index mapping. Called by the ACL handler
-// when an operator deletes an ACL from configuration.
+// UnregisterACL removes the name -> index mapping without touching the
+// dataplane.
 func (v *VPP) UnregisterACL(name string) {
 	v.aclReg.mu.Lock()
 	defer v.aclReg.mu.Unlock()
 	delete(v.aclReg.nameToIdx, name)
 }
 
-// ApplyIngressACL replaces the inbound ACL list on swIfIndex with [aclName].
-// If aclName is empty, the call clears the inbound list (equivalent to
-// RemoveIngressACL). Unknown names are reported as an error so callers can
-// decide whether to abort session bring-up.
+// AddReplaceACL creates or replaces the ACL tagged name. acl_add_replace
+// on an existing index swaps the rules atomically, so sessions bound to
+// the ACL never see a partial list. A new access list is created at its
+// pinned index when it has one and the index is free.
+func (v *VPP) AddReplaceACL(name string, entries []aclcfg.Entry) (uint32, error) {
+	v.aclReg.mu.Lock()
+	defer v.aclReg.mu.Unlock()
+
+	if !v.aclReg.adopted {
+		if err := v.adoptACLsLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	rules := make([]acl_types.ACLRule, 0, len(entries))
+	for _, e := range entries {
+		rules = append(rules, aclRule(e))
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return 0, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var index uint32
+	current, known := v.aclReg.nameToIdx[name]
+	want, pinned := v.aclReg.pinned[name]
+	switch {
+	case known:
+		index, err = aclAddReplace(ch, current, name, rules)
+	case pinned:
+		index, err = v.createACLAtLocked(ch, name, rules, want)
+		if errors.Is(err, errACLIndexTaken) {
+			v.logger.Warn("Pinned ACL index is not free; creating the ACL at another", "acl_name", name, "index", want)
+			index, err = aclAddReplace(ch, ^uint32(0), name, rules)
+		}
+	default:
+		index, err = aclAddReplace(ch, ^uint32(0), name, rules)
+	}
+	if err != nil {
+		return 0, err
+	}
+	v.aclReg.nameToIdx[name] = index
+	v.aclReg.entries[name] = entries
+	delete(v.aclReg.unclaimed, name)
+	if !known || current != index {
+		v.aclIndexChangedLocked(name)
+	}
+
+	if !v.aclReg.statsOn {
+		statsReply := &acl.ACLStatsIntfCountersEnableReply{}
+		if err := ch.SendRequest(&acl.ACLStatsIntfCountersEnable{Enable: true}).ReceiveReply(statsReply); err != nil || statsReply.Retval != 0 {
+			v.logger.Warn("Failed to enable ACL match counters", "error", err, "retval", statsReply.Retval)
+		} else {
+			v.aclReg.statsOn = true
+		}
+	}
+
+	return index, nil
+}
+
+func aclAddReplace(ch govppapi.Channel, index uint32, tag string, rules []acl_types.ACLRule) (uint32, error) {
+	reply := &acl.ACLAddReplaceReply{}
+	if err := ch.SendRequest(&acl.ACLAddReplace{ACLIndex: index, Tag: tag, R: rules}).ReceiveReply(reply); err != nil {
+		return 0, fmt.Errorf("acl_add_replace %q: %w", tag, err)
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("acl_add_replace %q retval=%d", tag, reply.Retval)
+	}
+	return reply.ACLIndex, nil
+}
+
+func aclDel(ch govppapi.Channel, index uint32) error {
+	reply := &acl.ACLDelReply{}
+	if err := ch.SendRequest(&acl.ACLDel{ACLIndex: index}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("acl_del %d: %w", index, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("acl_del %d retval=%d", index, reply.Retval)
+	}
+	return nil
+}
+
+// createACLAtLocked creates the ACL tagged name at index want. The
+// dataplane only creates an ACL at the index it hands out next, so
+// placeholder ACLs take the indexes handed out before want and are
+// deleted once it is reached. Returns errACLIndexTaken when another ACL
+// holds want. Caller holds aclReg.mu.
+func (v *VPP) createACLAtLocked(ch govppapi.Channel, name string, rules []acl_types.ACLRule, want uint32) (uint32, error) {
+	existing, err := v.DumpACLs()
+	if err != nil {
+		return 0, fmt.Errorf("dump existing ACLs: %w", err)
+	}
+	for _, d := range existing {
+		if d.Index == want {
+			return 0, errACLIndexTaken
+		}
+	}
+
+	placeholders, found, err := probeACLIndex(want, func(n int) (uint32, error) {
+		return aclAddReplace(ch, ^uint32(0), fmt.Sprintf("%splaceholder-%d", aclcfg.ReservedPrefix, n), rules)
+	})
+	if err == nil && found {
+		if _, err = aclAddReplace(ch, want, name, rules); err != nil {
+			placeholders = append(placeholders, want)
+		}
+	}
+	for _, idx := range placeholders {
+		if err := aclDel(ch, idx); err != nil {
+			v.logger.Warn("Failed to delete placeholder ACL", "index", idx, "error", err)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, errACLIndexTaken
+	}
+	return want, nil
+}
+
+// probeACLIndex creates ACLs through create until the dataplane hands
+// out want, and returns the indexes it was handed before. Every call
+// hands out another free index, so a free want is reached unless more
+// than aclMaxProbes indexes come first.
+func probeACLIndex(want uint32, create func(n int) (uint32, error)) ([]uint32, bool, error) {
+	var taken []uint32
+	for n := 0; n < aclMaxProbes; n++ {
+		idx, err := create(n)
+		if err != nil {
+			return taken, false, err
+		}
+		if idx == want {
+			return taken, true, nil
+		}
+		taken = append(taken, idx)
+	}
+	return taken, false, nil
+}
+
+// DeleteACL removes the ACL tagged name.
+func (v *VPP) DeleteACL(name string) error {
+	v.aclReg.mu.Lock()
+	defer v.aclReg.mu.Unlock()
+
+	index, ok := v.aclReg.nameToIdx[name]
+	if !ok {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := aclDel(ch, index); err != nil {
+		return fmt.Errorf("delete ACL %q: %w", name, err)
+	}
+	delete(v.aclReg.nameToIdx, name)
+	delete(v.aclReg.entries, name)
+	delete(v.aclReg.unclaimed, name)
+	delete(v.aclReg.pinned, name)
+	v.aclIndexChangedLocked(name)
+	return nil
+}
+
+// SetACLIndexObserver sets fn to be called after an access list is
+// created, moved or deleted. fn is called with the registry locked and
+// must not block or call back into it.
+func (v *VPP) SetACLIndexObserver(fn func()) {
+	v.aclReg.mu.Lock()
+	defer v.aclReg.mu.Unlock()
+	v.aclReg.onIndexes = fn
+}
+
+func (v *VPP) aclIndexChangedLocked(name string) {
+	if strings.HasPrefix(name, aclcfg.ReservedPrefix) || v.aclReg.onIndexes == nil {
+		return
+	}
+	v.aclReg.onIndexes()
+}
+
+// ACLIndexes returns the index of each access list programmed through
+// AddReplaceACL. The ACLs osvbng creates for its own use are left out.
+func (v *VPP) ACLIndexes() map[string]uint32 {
+	v.aclReg.mu.RLock()
+	defer v.aclReg.mu.RUnlock()
+
+	out := make(map[string]uint32, len(v.aclReg.entries))
+	for name := range v.aclReg.entries {
+		if strings.HasPrefix(name, aclcfg.ReservedPrefix) {
+			continue
+		}
+		out[name] = v.aclReg.nameToIdx[name]
+	}
+	return out
+}
+
+// PinACLIndexes sets the index each named access list is created at.
+// Lists that exist keep their index; see MoveACL.
+func (v *VPP) PinACLIndexes(indexes map[string]uint32) {
+	v.aclReg.mu.Lock()
+	defer v.aclReg.mu.Unlock()
+	for name, index := range indexes {
+		if !strings.HasPrefix(name, aclcfg.ReservedPrefix) {
+			v.aclReg.pinned[name] = index
+		}
+	}
+}
+
+// MoveACL moves the access list name to index: the list is created again
+// there, the interfaces bound to it are moved over and its old index is
+// deleted. Traffic is matched against the same rules throughout. A list
+// a steering policy matches on is not moved, since ABF cannot change a
+// policy's ACL in place.
+func (v *VPP) MoveACL(name string, index uint32) error {
+	if strings.HasPrefix(name, aclcfg.ReservedPrefix) {
+		return fmt.Errorf("ACL %q is local to the node", name)
+	}
+	old, ok := v.aclReg.lookup(name)
+	if !ok || old == index {
+		return nil
+	}
+	if policy, used := v.steeringACLUser(old); used {
+		return fmt.Errorf("ACL %q is matched by steering policy %q", name, policy)
+	}
+
+	v.aclReg.mu.Lock()
+	entries, programmed := v.aclReg.entries[name]
+	if !programmed || v.aclReg.nameToIdx[name] != old {
+		v.aclReg.mu.Unlock()
+		return fmt.Errorf("ACL %q has not been programmed by this run", name)
+	}
+	rules := make([]acl_types.ACLRule, 0, len(entries))
+	for _, e := range entries {
+		rules = append(rules, aclRule(e))
+	}
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		v.aclReg.mu.Unlock()
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+	if _, err := v.createACLAtLocked(ch, name, rules, index); err != nil {
+		v.aclReg.mu.Unlock()
+		return fmt.Errorf("move ACL %q to index %d: %w", name, index, err)
+	}
+	v.aclReg.nameToIdx[name] = index
+	v.aclReg.pinned[name] = index
+	v.aclReg.mu.Unlock()
+
+	// Bindings are kept by name, so reprogramming them picks up the new
+	// index.
+	v.aclReg.bindMu.Lock()
+	for swIfIndex, b := range v.aclReg.bound {
+		if b.ingress != name && b.egress != name {
+			continue
+		}
+		if err := v.setACLList(swIfIndex, b); err != nil {
+			v.logger.Warn("Failed to move interface to moved ACL", "acl_name", name, "sw_if_index", swIfIndex, "error", err)
+		}
+	}
+	v.aclReg.bindMu.Unlock()
+
+	if err := aclDel(ch, old); err != nil {
+		v.logger.Warn("Failed to delete the old index of a moved ACL", "acl_name", name, "index", old, "error", err)
+	}
+	v.logger.Info("Moved ACL", "acl_name", name, "from", old, "to", index)
+
+	v.aclReg.mu.Lock()
+	v.aclIndexChangedLocked(name)
+	v.aclReg.mu.Unlock()
+	return nil
+}
+
+// ResetACLs forgets the ACLs and bindings of a dataplane that lost its
+// state, before the configuration is applied to it again. Each access
+// list is pinned to the index it had, so it is created there again.
+func (v *VPP) ResetACLs() {
+	v.aclReg.mu.Lock()
+	for name := range v.aclReg.entries {
+		if !strings.HasPrefix(name, aclcfg.ReservedPrefix) {
+			v.aclReg.pinned[name] = v.aclReg.nameToIdx[name]
+		}
+	}
+	v.aclReg.nameToIdx = make(map[string]uint32)
+	v.aclReg.entries = make(map[string][]aclcfg.Entry)
+	v.aclReg.unclaimed = make(map[string]struct{})
+	v.aclReg.adopted = false
+	v.aclReg.statsOn = false
+	v.aclReg.mu.Unlock()
+
+	v.aclReg.bindMu.Lock()
+	v.aclReg.bound = make(map[uint32]aclBinding)
+	v.aclReg.bindMu.Unlock()
+}
+
+// DumpACLs lists every ACL in the dataplane.
+func (v *VPP) DumpACLs() ([]southbound.ACLDetails, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var out []southbound.ACLDetails
+	multi := ch.SendMultiRequest(&acl.ACLDump{ACLIndex: ^uint32(0)})
+	for {
+		d := &acl.ACLDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive acl details: %w", err)
+		}
+		out = append(out, southbound.ACLDetails{
+			Index:   d.ACLIndex,
+			Name:    d.Tag,
+			Entries: len(d.R),
+		})
+	}
+	return out, nil
+}
+
+// GetACLRuleStats reads the per-entry match counters.
+func (v *VPP) GetACLRuleStats() (map[uint32][]southbound.ACLRuleStats, error) {
+	return v.statsClient.GetACLRuleStats()
+}
+
+// adoptACLsLocked registers the ACLs an earlier run left in the
+// dataplane, by tag, so a restarted daemon replaces them in place and
+// keeps their indexes. Caller holds aclReg.mu.
+func (v *VPP) adoptACLsLocked() error {
+	existing, err := v.DumpACLs()
+	if err != nil {
+		return fmt.Errorf("dump existing ACLs: %w", err)
+	}
+	for _, d := range existing {
+		if d.Name == "" {
+			continue
+		}
+		if _, ok := v.aclReg.nameToIdx[d.Name]; !ok {
+			v.aclReg.nameToIdx[d.Name] = d.Index
+			v.aclReg.unclaimed[d.Name] = struct{}{}
+		}
+	}
+	v.aclReg.adopted = true
+	return nil
+}
+
+// PruneAdoptedACLs deletes the ACLs an earlier run left in the
+// dataplane that this run has not programmed again and no interface is
+// bound to, such as a list removed from the configuration while the
+// daemon was down. It is called once the startup configuration is
+// applied; an ACL still bound to a restored session's interface is kept
+// until its owner takes it over or deletes it.
+func (v *VPP) PruneAdoptedACLs() error {
+	v.aclReg.mu.Lock()
+	defer v.aclReg.mu.Unlock()
+
+	if !v.aclReg.adopted {
+		if err := v.adoptACLsLocked(); err != nil {
+			return err
+		}
+	}
+	if len(v.aclReg.unclaimed) == 0 {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	inUse := make(map[uint32]struct{})
+	multi := ch.SendMultiRequest(&acl.ACLInterfaceListDump{SwIfIndex: ^interface_types.InterfaceIndex(0)})
+	for {
+		d := &acl.ACLInterfaceListDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("receive acl interface lists: %w", err)
+		}
+		for _, idx := range d.Acls {
+			inUse[idx] = struct{}{}
+		}
+	}
+
+	var pruned []string
+	for name := range v.aclReg.unclaimed {
+		index := v.aclReg.nameToIdx[name]
+		if _, ok := inUse[index]; ok {
+			continue
+		}
+		if err := aclDel(ch, index); err != nil {
+			v.logger.Warn("Failed to delete ACL left by an earlier run", "acl_name", name, "index", index, "error", err)
+			continue
+		}
+		delete(v.aclReg.nameToIdx, name)
+		delete(v.aclReg.unclaimed, name)
+		pruned = append(pruned, name)
+	}
+	if len(pruned) > 0 {
+		sort.Strings(pruned)
+		v.logger.Info("Deleted ACLs left by an earlier run", "acls", pruned)
+	}
+	return nil
+}
+
+func aclRule(e aclcfg.Entry) acl_types.ACLRule {
+	action := acl_types.ACL_ACTION_API_DENY
+	switch e.Action {
+	case aclcfg.ActionPermit:
+		action = acl_types.ACL_ACTION_API_PERMIT
+	case aclcfg.ActionPermitReflect:
+		action = acl_types.ACL_ACTION_API_PERMIT_REFLECT
+	}
+	return acl_types.ACLRule{
+		IsPermit:               action,
+		SrcPrefix:              ip_types.NewPrefix(e.Source),
+		DstPrefix:              ip_types.NewPrefix(e.Destination),
+		Proto:                  ip_types.IPProto(e.Protocol),
+		SrcportOrIcmptypeFirst: e.SrcFirst,
+		SrcportOrIcmptypeLast:  e.SrcLast,
+		DstportOrIcmpcodeFirst: e.DstFirst,
+		DstportOrIcmpcodeLast:  e.DstLast,
+		TCPFlagsMask:           e.TCPFlagsMask,
+		TCPFlagsValue:          e.TCPFlagsValue,
+	}
+}
+
+// ApplyIngressACL sets swIfIndex's own inbound ACL to aclName, behind
+// its FlowSpec and local-switching ACLs, leaving the outbound list as it
+// is. If aclName is empty, the call clears it (equivalent to
+// RemoveIngressACL). Unknown names are reported as an error so callers
+// can decide whether to abort session bring-up.
 func (v *VPP) ApplyIngressACL(swIfIndex uint32, aclName string) error {
-	return v.setACLList(swIfIndex, aclName, "")
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.ingress = aclName })
 }
 
 // ApplyEgressACL is the egress counterpart of ApplyIngressACL.
 func (v *VPP) ApplyEgressACL(swIfIndex uint32, aclName string) error {
-	return v.setACLList(swIfIndex, "", aclName)
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.egress = aclName })
 }
 
-// RemoveIngressACL clears the inbound ACL list on swIfIndex.
+// RemoveIngressACL clears swIfIndex's own inbound ACL; its FlowSpec and
+// local-switching ACLs stay.
 func (v *VPP) RemoveIngressACL(swIfIndex uint32) error {
-	return v.setACLList(swIfIndex, "", "")
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.ingress = "" })
 }
 
 // RemoveEgressACL clears the outbound ACL list on swIfIndex.
 func (v *VPP) RemoveEgressACL(swIfIndex uint32) error {
-	return v.setACLList(swIfIndex, "", "")
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.egress = "" })
 }
 
-func (v *VPP) setACLList(swIfIndex uint32, ingress, egress string) error {
-	var acls []uint32
-	var nInput uint8
+// updateACLBinding applies one direction's change and programs the
+// interface's full list. The recorded binding only moves once the
+// dataplane has accepted it.
+func (v *VPP) updateACLBinding(swIfIndex uint32, change func(*aclBinding)) error {
+	v.aclReg.bindMu.Lock()
+	defer v.aclReg.bindMu.Unlock()
 
-	if ingress != "" {
-		idx, ok := v.aclReg.lookup(ingress)
-		if !ok {
-			v.logger.Warn("ACL name not registered; ingress binding skipped",
-				"acl_name", ingress, "sw_if_index", swIfIndex)
-			return fmt.Errorf("unknown ingress ACL %q", ingress)
-		}
-		acls = append(acls, idx)
-		nInput++
+	b := v.aclReg.bound[swIfIndex]
+	change(&b)
+	if err := v.setACLList(swIfIndex, b); err != nil {
+		return err
 	}
-	if egress != "" {
-		idx, ok := v.aclReg.lookup(egress)
-		if !ok {
-			v.logger.Warn("ACL name not registered; egress binding skipped",
-				"acl_name", egress, "sw_if_index", swIfIndex)
-			return fmt.Errorf("unknown egress ACL %q", egress)
+	if b == (aclBinding{}) {
+		delete(v.aclReg.bound, swIfIndex)
+	} else {
+		v.aclReg.bound[swIfIndex] = b
+	}
+	return nil
+}
+
+func (v *VPP) setACLList(swIfIndex uint32, b aclBinding) error {
+	if b.ingress == "" && (b.flowspec != "" || b.local != "") {
+		if err := v.ensurePermitAnyACL(); err != nil {
+			return err
 		}
-		acls = append(acls, idx)
+	}
+	acls, nInput, err := aclList(b, v.aclReg.lookup)
+	if err != nil {
+		v.logger.Warn("ACL name not registered; binding skipped", "sw_if_index", swIfIndex, "error", err)
+		return err
 	}
 
 	ch, err := v.conn.NewAPIChannel()
@@ -119,3 +624,50 @@ func (v *VPP) setACLList(swIfIndex uint32, ingress, egress string) error {
 	}
 	return nil
 }
+
+// aclList orders an interface's ACLs as the dataplane takes them: the
+// inbound ones first, FlowSpec, then local switching, then the
+// interface's own, followed by the outbound one. The FlowSpec and
+// local-switching ACLs only deny, so what they let through is still
+// decided by the interface's own ACL. An interface without one gets
+// the permit-any ACL behind them, since an inbound list denies what
+// none of its ACLs match.
+func aclList(b aclBinding, lookup func(string) (uint32, bool)) ([]uint32, uint8, error) {
+	var acls []uint32
+	var nInput uint8
+
+	ingress := b.ingress
+	for _, filter := range []struct{ kind, name string }{
+		{"FlowSpec", b.flowspec},
+		{"local-switching", b.local},
+	} {
+		if filter.name == "" {
+			continue
+		}
+		idx, ok := lookup(filter.name)
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown %s ACL %q", filter.kind, filter.name)
+		}
+		acls = append(acls, idx)
+		nInput++
+		if ingress == "" {
+			ingress = permitAnyACLName
+		}
+	}
+	if ingress != "" {
+		idx, ok := lookup(ingress)
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown ingress ACL %q", ingress)
+		}
+		acls = append(acls, idx)
+		nInput++
+	}
+	if b.egress != "" {
+		idx, ok := lookup(b.egress)
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown egress ACL %q", b.egress)
+		}
+		acls = append(acls, idx)
+	}
+	return acls, nInput, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestACLListOrder(t *testing.T) {
+	indexes := map[string]uint32{
+		"fs": 1, "local": 2, "in": 3, "out": 4, permitAnyACLName: 9,
+	}
+	lookup := func(name string) (uint32, bool) {
+		idx, ok := indexes[name]
+		return idx, ok
+	}
+
+	tests := []struct {
+		name   string
+		b      aclBinding
+		acls   []uint32
+		nInput uint8
+	}{
+		{"own only", aclBinding{ingress: "in", egress: "out"}, []uint32{3, 4}, 1},
+		{"flowspec ahead of own", aclBinding{flowspec: "fs", ingress: "in"}, []uint32{1, 3}, 2},
+		{"flowspec without own", aclBinding{flowspec: "fs"}, []uint32{1, 9}, 2},
+		{"all", aclBinding{flowspec: "fs", local: "local", ingress: "in", egress: "out"}, []uint32{1, 2, 3, 4}, 3},
+		{"filters without own", aclBinding{flowspec: "fs", local: "local", egress: "out"}, []uint32{1, 2, 9, 4}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acls, nInput, err := aclList(tt.b, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(acls, tt.acls) || nInput != tt.nInput {
+				t.Fatalf("acls = %v n_input = %d, want %v n_input = %d", acls, nInput, tt.acls, tt.nInput)
+			}
+		})
+	}
+
+	if _, _, err := aclList(aclBinding{flowspec: "gone", ingress: "in"}, lookup); err == nil {
+		t.Fatal("unknown FlowSpec ACL accepted")
+	}
+}
+
+func TestProbeACLIndex(t *testing.T) {
+	// A pool of six ACLs with 1 and 4 deleted hands out its free
+	// indexes last deleted first, then grows.
+	free := []uint32{1, 4}
+	next := uint32(6)
+	create := func(int) (uint32, error) {
+		if n := len(free); n > 0 {
+			idx := free[n-1]
+			free = free[:n-1]
+			return idx, nil
+		}
+		next++
+		return next - 1, nil
+	}
+
+	taken, found, err := probeACLIndex(1, create)
+	if err != nil || !found || !reflect.DeepEqual(taken, []uint32{4}) {
+		t.Fatalf("probe 1 = %v %t %v", taken, found, err)
+	}
+	taken, found, err = probeACLIndex(8, create)
+	if err != nil || !found || !reflect.DeepEqual(taken, []uint32{6, 7}) {
+		t.Fatalf("probe 8 = %v %t %v", taken, found, err)
+	}
+	// An index below the pool's end that is not free is never handed out.
+	if taken, found, _ := probeACLIndex(2, create); found || len(taken) != aclMaxProbes {
+		t.Fatalf("probe 2 found it after %d", len(taken))
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	govppapi "go.fd.io/govpp/api"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/fib"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/fib_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+)
+
+var _ southbound.Blackhole = (*VPP)(nil)
+
+// Blackhole routes are added from a FIB source of their own, so they
+// override the host route of a session (and any route the routing
+// daemon installs) for the same prefix without replacing it: removing
+// the blackhole uncovers the route underneath.
+const (
+	blackholeSourceName = "osvbng-rtbh"
+	// Only the special sources (drop, local, interface) are ahead.
+	blackholeSourcePriority = 1
+)
+
+// AddBlackholeRoute drops traffic toward prefix in the VRF's table.
+func (v *VPP) AddBlackholeRoute(prefix netip.Prefix, vrf string) error {
+	return v.blackholeRoute(prefix, vrf, true)
+}
+
+// DeleteBlackholeRoute removes the drop route, if any.
+func (v *VPP) DeleteBlackholeRoute(prefix netip.Prefix, vrf string) error {
+	return v.blackholeRoute(prefix, vrf, false)
+}
+
+func (v *VPP) blackholeRoute(prefix netip.Prefix, vrf string, isAdd bool) error {
+	var tableID uint32
+	if vrf != "" {
+		if v.vrfResolver == nil {
+			return fmt.Errorf("VRF resolver not configured")
+		}
+		id, _, _, err := v.vrfResolver(vrf)
+		if err != nil {
+			return fmt.Errorf("resolve VRF %q: %w", vrf, err)
+		}
+		tableID = id
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	src, err := v.blackholeSource(ch)
+	if err != nil {
+		return err
+	}
+
+	proto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if prefix.Addr().Is6() {
+		proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+	network := net.IPNet{
+		IP:   prefix.Addr().AsSlice(),
+		Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+	}
+
+	reply := &ip.IPRouteAddDelV2Reply{}
+	if err := ch.SendRequest(&ip.IPRouteAddDelV2{
+		IsAdd: isAdd,
+		Route: ip.IPRouteV2{
+			TableID: tableID,
+			Prefix:  ip_types.NewPrefix(network),
+			NPaths:  1,
+			Src:     src,
+			Paths: []fib_types.FibPath{{
+				SwIfIndex: ^uint32(0),
+				Type:      fib_types.FIB_API_PATH_TYPE_DROP,
+				Proto:     proto,
+			}},
+		},
+	}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("blackhole route %s: %w", prefix, err)
+	}
+	if reply.Retval != 0 && !(!isAdd && reply.Retval == retvalNoSuchEntry) {
+		return fmt.Errorf("blackhole route %s failed: retval=%d", prefix, reply.Retval)
+	}
+
+	if isAdd {
+		v.logger.Debug("Added blackhole route", "prefix", prefix, "vrf", vrf, "table_id", tableID)
+	} else {
+		v.logger.Debug("Removed blackhole route", "prefix", prefix, "vrf", vrf, "table_id", tableID)
+	}
+	return nil
+}
+
+// blackholeSource returns the ID of the blackhole FIB source, adding it
+// the first time. VPP keeps sources for its lifetime, so one left from
+// an earlier run is reused.
+func (v *VPP) blackholeSource(ch govppapi.Channel) (uint8, error) {
+	v.blackholeMu.Lock()
+	defer v.blackholeMu.Unlock()
+
+	if v.blackholeSrcOK {
+		return v.blackholeSrc, nil
+	}
+
+	multi := ch.SendMultiRequest(&fib.FibSourceDump{})
+	for {
+		d := &fib.FibSourceDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("receive fib sources: %w", err)
+		}
+		if d.Src.Name == blackholeSourceName {
+			v.blackholeSrc, v.blackholeSrcOK = d.Src.ID, true
+		}
+	}
+	if v.blackholeSrcOK {
+		return v.blackholeSrc, nil
+	}
+
+	reply := &fib.FibSourceAddReply{}
+	if err := ch.SendRequest(&fib.FibSourceAdd{
+		Src: fib.FibSource{Priority: blackholeSourcePriority, Name: blackholeSourceName},
+	}).ReceiveReply(reply); err != nil {
+		return 0, fmt.Errorf("fib source add: %w", err)
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("fib source add failed: retval=%d", reply.Retval)
+	}
+	v.blackholeSrc, v.blackholeSrcOK = reply.ID, true
+	return reply.ID, nil
+}
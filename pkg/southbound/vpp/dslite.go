@@ -0,0 +1,100 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/dslite"
+)
+
+var _ southbound.DSLite = (*VPP)(nil)
+
+func (v *VPP) DSLiteSetAFTRAddr(ip6, ip4 net.IP) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &dslite.DsliteSetAftrAddr{IP4Addr: ip4Addr(ip4)}
+	copy(req.IP6Addr[:], ip6.To16())
+
+	reply := &dslite.DsliteSetAftrAddrReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("set aftr addr: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("set aftr addr failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) DSLiteGetAFTRAddr() (net.IP, net.IP, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	reply := &dslite.DsliteGetAftrAddrReply{}
+	if err := ch.SendRequest(&dslite.DsliteGetAftrAddr{}).ReceiveReply(reply); err != nil {
+		return nil, nil, fmt.Errorf("get aftr addr: %w", err)
+	}
+	if reply.Retval != 0 {
+		return nil, nil, fmt.Errorf("get aftr addr failed: retval=%d", reply.Retval)
+	}
+	ip6 := make(net.IP, net.IPv6len)
+	copy(ip6, reply.IP6Addr[:])
+	return ip6, ip4FromAddr(reply.IP4Addr), nil
+}
+
+func (v *VPP) DSLiteAddDelPoolAddrRange(start, end net.IP, isAdd bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &dslite.DsliteAddDelPoolAddrRange{
+		StartAddr: ip4Addr(start),
+		EndAddr:   ip4Addr(end),
+		IsAdd:     isAdd,
+	}
+
+	reply := &dslite.DsliteAddDelPoolAddrRangeReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("dslite pool addr range: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("dslite pool addr range failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) DSLiteAddressDump() ([]net.IP, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var results []net.IP
+	multi := ch.SendMultiRequest(&dslite.DsliteAddressDump{})
+	for {
+		d := &dslite.DsliteAddressDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive dslite address details: %w", err)
+		}
+		results = append(results, ip4FromAddr(d.IPAddress))
+	}
+	return results, nil
+}
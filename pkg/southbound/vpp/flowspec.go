@@ -0,0 +1,445 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	govppapi "go.fd.io/govpp/api"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/classify"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/fib_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/policer"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/policer_types"
+)
+
+var _ southbound.FlowSpec = (*VPP)(nil)
+
+// flowspecPriority is the ABF priority of an interface's first
+// redirect; the ones after it follow in rule order.
+const flowspecPriority = 10
+
+// flowspecBinding is what SetFlowSpecRules programmed on one interface.
+type flowspecBinding struct {
+	rules []southbound.FlowSpecRule
+	// acl holds the deny rules; denyAt is each rule's first and last
+	// entry in it.
+	acl    string
+	denyAt map[string][2]int
+	// limits are the rate limits as ingress classes named by rule key.
+	limits    qosClassBinding
+	rates     map[string]uint32
+	redirects []flowspecRedirect
+}
+
+// flowspecRedirect is one redirect: an ABF policy per family it
+// matches, sharing an ACL, that looks the traffic up in tableID.
+type flowspecRedirect struct {
+	key      string
+	acl      string
+	id       uint32
+	families []int
+	tableID  uint32
+	priority uint32
+}
+
+// SetFlowSpecRules programs the interface's rules. The deny ACL is
+// replaced in place; rate limits and redirects are removed and added
+// again, so they lapse for the moment the rules change.
+func (v *VPP) SetFlowSpecRules(swIfIndex uint32, rules []southbound.FlowSpecRule) error {
+	v.flowspecMu.Lock()
+	defer v.flowspecMu.Unlock()
+
+	old := v.flowspec[swIfIndex]
+	if old != nil && reflect.DeepEqual(old.rules, rules) {
+		return nil
+	}
+	if old == nil && len(rules) == 0 {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if old != nil {
+		v.removeFlowSpecLimits(swIfIndex, old)
+		v.removeFlowSpecRedirects(ch, swIfIndex, old)
+		delete(v.flowspec, swIfIndex)
+	}
+
+	var deny, limit, redirect []southbound.FlowSpecRule
+	for _, r := range rules {
+		switch r.Action {
+		case southbound.FlowSpecDeny:
+			deny = append(deny, r)
+		case southbound.FlowSpecRateLimit:
+			limit = append(limit, r)
+		case southbound.FlowSpecRedirect:
+			redirect = append(redirect, r)
+		default:
+			return fmt.Errorf("flowspec rule %q: unknown action %q", r.Key, r.Action)
+		}
+	}
+
+	b := &flowspecBinding{acl: fmt.Sprintf("%sflowspec-%d", aclcfg.ReservedPrefix, swIfIndex)}
+	if err := v.setFlowSpecDeny(swIfIndex, b, deny); err != nil {
+		return err
+	}
+	if len(rules) > 0 {
+		// Recorded before the rest is programmed, with no rules, so a
+		// failure below is retried in full on the next call.
+		v.flowspec[swIfIndex] = b
+	}
+	if err := v.addFlowSpecLimits(ch, swIfIndex, b, limit); err != nil {
+		v.removeFlowSpecLimits(swIfIndex, b)
+		return err
+	}
+	if err := v.addFlowSpecRedirects(ch, swIfIndex, b, redirect); err != nil {
+		v.removeFlowSpecLimits(swIfIndex, b)
+		v.removeFlowSpecRedirects(ch, swIfIndex, b)
+		return err
+	}
+	b.rules = rules
+
+	v.logger.Debug("Programmed FlowSpec rules", "sw_if_index", swIfIndex,
+		"deny", len(deny), "rate_limit", len(limit), "redirect", len(redirect))
+	return nil
+}
+
+// setFlowSpecDeny programs the deny rules as the interface's FlowSpec
+// ACL, first in its inbound list ahead of its own inbound ACL, or
+// removes the ACL when there are none. The ACL only denies: what it does
+// not match goes on to the interface's inbound ACL.
+func (v *VPP) setFlowSpecDeny(swIfIndex uint32, b *flowspecBinding, rules []southbound.FlowSpecRule) error {
+	if len(rules) == 0 {
+		if _, ok := v.aclReg.lookup(b.acl); !ok {
+			return nil
+		}
+		if err := v.updateACLBinding(swIfIndex, func(ab *aclBinding) { ab.flowspec = "" }); err != nil {
+			return err
+		}
+		return v.DeleteACL(b.acl)
+	}
+
+	var entries []aclcfg.Entry
+	b.denyAt = make(map[string][2]int, len(rules))
+	for _, r := range rules {
+		first := len(entries)
+		for _, e := range r.Entries {
+			e.Action = aclcfg.ActionDeny
+			entries = append(entries, e)
+		}
+		b.denyAt[r.Key] = [2]int{first, len(entries) - 1}
+	}
+
+	if _, err := v.AddReplaceACL(b.acl, entries); err != nil {
+		return fmt.Errorf("flowspec deny rules: %w", err)
+	}
+	if err := v.updateACLBinding(swIfIndex, func(ab *aclBinding) { ab.flowspec = b.acl }); err != nil {
+		return fmt.Errorf("flowspec deny rules: %w", err)
+	}
+	return nil
+}
+
+// addFlowSpecLimits programs a policer per rate limit, fed by classify
+// tables as the ingress QoS classes of a session are.
+func (v *VPP) addFlowSpecLimits(ch govppapi.Channel, swIfIndex uint32, b *flowspecBinding, rules []southbound.FlowSpecRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var ip4, ip6 []classifyTable
+	for i, r := range rules {
+		var t4, t6 []classifyTable
+		for _, e := range r.Entries {
+			mask, match := classifyKey(e, -1, -1, 0)
+			if e.IPv6 {
+				t6 = addSession(t6, i, mask, match)
+			} else {
+				t4 = addSession(t4, i, mask, match)
+			}
+		}
+		ip4 = append(ip4, t4...)
+		ip6 = append(ip6, t6...)
+	}
+
+	b.rates = make(map[string]uint32, len(rules))
+	for i, r := range rules {
+		kbps := uint32(min(max(r.RateBytes*8/1000, 1), uint64(^uint32(0))))
+		p := qos.Policy{
+			CIR:     kbps,
+			Conform: qos.ActionConfig{Action: qos.ActionTransmit},
+			Exceed:  qos.ActionConfig{Action: qos.ActionDrop},
+			Violate: qos.ActionConfig{Action: qos.ActionDrop},
+		}
+		name := fmt.Sprintf("flowspec_%d_%d", swIfIndex, i)
+		index, err := addFlowSpecPolicer(ch, name, p.ToPolicerConfig())
+		if err != nil {
+			return fmt.Errorf("flowspec rule %q: %w", r.Key, err)
+		}
+		b.limits.ingress = append(b.limits.ingress, ingressClass{
+			name:         r.Key,
+			policer:      name,
+			policerIndex: index,
+			exceedDrops:  true,
+			violateDrops: true,
+		})
+		b.rates[r.Key] = kbps
+	}
+
+	var err error
+	if b.limits.ip4, err = v.addClassifyChain(ch, ip4, b.limits.ingress); err != nil {
+		return err
+	}
+	if b.limits.ip6, err = v.addClassifyChain(ch, ip6, b.limits.ingress); err != nil {
+		return err
+	}
+
+	req := &classify.PolicerClassifySetInterface{
+		SwIfIndex:     interface_types.InterfaceIndex(swIfIndex),
+		IP4TableIndex: chainHead(b.limits.ip4),
+		IP6TableIndex: chainHead(b.limits.ip6),
+		L2TableIndex:  ^uint32(0),
+		IsAdd:         true,
+	}
+	reply := &classify.PolicerClassifySetInterfaceReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("policer classify attach: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("policer classify attach failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+// addFlowSpecPolicer adds a policer, replacing one of the same name an
+// earlier run left behind.
+func addFlowSpecPolicer(ch govppapi.Channel, name string, cfg policer_types.PolicerConfig) (uint32, error) {
+	req := &policer.PolicerAddDel{
+		IsAdd:         true,
+		Name:          name,
+		Cir:           cfg.Cir,
+		Eir:           cfg.Eir,
+		Cb:            cfg.Cb,
+		Eb:            cfg.Eb,
+		RateType:      cfg.RateType,
+		RoundType:     cfg.RoundType,
+		Type:          cfg.Type,
+		ColorAware:    cfg.ColorAware,
+		ConformAction: cfg.ConformAction,
+		ExceedAction:  cfg.ExceedAction,
+		ViolateAction: cfg.ViolateAction,
+	}
+	for attempt := 0; ; attempt++ {
+		reply := &policer.PolicerAddDelReply{}
+		if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+			return 0, fmt.Errorf("policer add %s: %w", name, err)
+		}
+		if reply.Retval == retvalValueExist && attempt == 0 {
+			del := &policer.PolicerAddDel{IsAdd: false, Name: name}
+			if err := ch.SendRequest(del).ReceiveReply(&policer.PolicerAddDelReply{}); err != nil {
+				return 0, fmt.Errorf("policer delete %s: %w", name, err)
+			}
+			continue
+		}
+		if reply.Retval != 0 {
+			return 0, fmt.Errorf("policer add %s failed: retval=%d", name, reply.Retval)
+		}
+		return reply.PolicerIndex, nil
+	}
+}
+
+func (v *VPP) removeFlowSpecLimits(swIfIndex uint32, b *flowspecBinding) {
+	v.unprogramIngressClasses(swIfIndex, &b.limits)
+	b.limits = qosClassBinding{}
+	b.rates = nil
+}
+
+// addFlowSpecRedirects programs each redirect as an ABF policy matching
+// its own ACL, attached in rule order.
+func (v *VPP) addFlowSpecRedirects(ch govppapi.Channel, swIfIndex uint32, b *flowspecBinding, rules []southbound.FlowSpecRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := v.flushSteeringLocked(ch); err != nil {
+		return err
+	}
+
+	for i, rule := range rules {
+		tableID, err := v.flowspecTable(rule.VRF)
+		if err != nil {
+			return fmt.Errorf("flowspec rule %q: %w", rule.Key, err)
+		}
+
+		entries := make([]aclcfg.Entry, 0, len(rule.Entries))
+		var has [2]bool
+		for _, e := range rule.Entries {
+			e.Action = aclcfg.ActionPermit
+			entries = append(entries, e)
+			if e.IPv6 {
+				has[1] = true
+			} else {
+				has[0] = true
+			}
+		}
+		name := fmt.Sprintf("%s-%d", b.acl, i)
+		aclIndex, err := v.AddReplaceACL(name, entries)
+		if err != nil {
+			return fmt.Errorf("flowspec rule %q: %w", rule.Key, err)
+		}
+
+		r.nextID++
+		red := flowspecRedirect{key: rule.Key, acl: name, id: r.nextID, tableID: tableID, priority: flowspecPriority + uint32(i)}
+		for af, ok := range has {
+			if !ok {
+				continue
+			}
+			policyID := red.id*2 + uint32(af)
+			if err := abfPolicyAddDel(ch, true, policyID, aclIndex, flowspecPaths(tableID, af)); err != nil {
+				b.redirects = append(b.redirects, red)
+				return fmt.Errorf("flowspec rule %q: %w", rule.Key, err)
+			}
+			red.families = append(red.families, af)
+			if err := abfAttach(ch, true, policyID, swIfIndex, af, red.priority); err != nil {
+				b.redirects = append(b.redirects, red)
+				return fmt.Errorf("flowspec rule %q: %w", rule.Key, err)
+			}
+		}
+		b.redirects = append(b.redirects, red)
+	}
+	return nil
+}
+
+// removeFlowSpecRedirects is best effort, like removing QoS classes.
+func (v *VPP) removeFlowSpecRedirects(ch govppapi.Channel, swIfIndex uint32, b *flowspecBinding) {
+	if len(b.redirects) == 0 {
+		return
+	}
+
+	v.steeringReg.mu.Lock()
+	defer v.steeringReg.mu.Unlock()
+	for _, red := range b.redirects {
+		aclIndex, _ := v.aclReg.lookup(red.acl)
+		for _, af := range red.families {
+			policyID := red.id*2 + uint32(af)
+			if err := abfAttach(ch, false, policyID, swIfIndex, af, red.priority); err != nil {
+				v.logger.Warn("Failed to detach FlowSpec redirect", "sw_if_index", swIfIndex, "rule", red.key, "error", err)
+			}
+			if err := abfPolicyAddDel(ch, false, policyID, aclIndex, flowspecPaths(red.tableID, af)); err != nil {
+				v.logger.Warn("Failed to delete FlowSpec redirect", "sw_if_index", swIfIndex, "rule", red.key, "error", err)
+			}
+		}
+		if err := v.DeleteACL(red.acl); err != nil {
+			v.logger.Warn("Failed to delete FlowSpec redirect ACL", "acl", red.acl, "error", err)
+		}
+	}
+	b.redirects = nil
+}
+
+// flowspecPaths is a redirect's path: no next hop and no interface, so
+// the packet is looked up in the table.
+func flowspecPaths(tableID uint32, af int) []fib_types.FibPath {
+	proto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if af == 1 {
+		proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+	return []fib_types.FibPath{{SwIfIndex: ^uint32(0), TableID: tableID, Weight: 1, Proto: proto}}
+}
+
+func (v *VPP) flowspecTable(vrf string) (uint32, error) {
+	if vrf == "" {
+		return 0, nil
+	}
+	if v.vrfResolver == nil {
+		return 0, fmt.Errorf("VRF resolver not configured")
+	}
+	id, _, _, err := v.vrfResolver(vrf)
+	if err != nil {
+		return 0, fmt.Errorf("resolve VRF %q: %w", vrf, err)
+	}
+	return id, nil
+}
+
+// DumpFlowSpec reports every programmed rule per interface. Deny rules
+// count the matches of their ACL entries, all of them drops; rate
+// limits the packets through their policer, the drops being those
+// above the rate.
+func (v *VPP) DumpFlowSpec() ([]southbound.FlowSpecState, error) {
+	v.flowspecMu.Lock()
+	defer v.flowspecMu.Unlock()
+
+	if len(v.flowspec) == 0 {
+		return nil, nil
+	}
+
+	aclStats, err := v.statsClient.GetACLRuleStats()
+	if err != nil {
+		v.logger.Debug("ACL counters unavailable", "error", err)
+	}
+	policers, err := v.statsClient.GetPolicerStats()
+	if err != nil {
+		v.logger.Debug("Policer counters unavailable", "error", err)
+	}
+
+	var out []southbound.FlowSpecState
+	for sw, b := range v.flowspec {
+		name := v.interfaceName(sw)
+		aclIndex, hasACL := v.aclReg.lookup(b.acl)
+		for _, r := range b.rules {
+			s := southbound.FlowSpecState{SwIfIndex: sw, InterfaceName: name, Key: r.Key, Action: r.Action}
+			switch r.Action {
+			case southbound.FlowSpecDeny:
+				at, ok := b.denyAt[r.Key]
+				if !ok || !hasACL {
+					continue
+				}
+				s.Programming = fmt.Sprintf("acl %s (index %d) entries %d-%d", b.acl, aclIndex, at[0], at[1])
+				stats := aclStats[aclIndex]
+				for i := at[0]; i <= at[1] && i < len(stats); i++ {
+					s.Packets += stats[i].Packets
+					s.Bytes += stats[i].Bytes
+				}
+				s.Drops = s.Packets
+			case southbound.FlowSpecRateLimit:
+				for _, c := range b.limits.ingress {
+					if c.name != r.Key {
+						continue
+					}
+					s.Programming = fmt.Sprintf("policer %s (index %d) at %d kbps", c.policer, c.policerIndex, b.rates[r.Key])
+					if ctr, ok := policers[c.policerIndex]; ok {
+						s.Packets = ctr.conform.packets + ctr.exceed.packets + ctr.violate.packets
+						s.Bytes = ctr.conform.bytes + ctr.exceed.bytes + ctr.violate.bytes
+						s.Drops = ctr.exceed.packets + ctr.violate.packets
+					}
+				}
+			case southbound.FlowSpecRedirect:
+				for _, red := range b.redirects {
+					if red.key == r.Key && len(red.families) > 0 {
+						s.Programming = fmt.Sprintf("abf policy %d via acl %s, lookup in table %d", red.id*2+uint32(red.families[0]), red.acl, red.tableID)
+					}
+				}
+			}
+			if s.Programming != "" {
+				out = append(out, s)
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SwIfIndex < out[j].SwIfIndex })
+	return out, nil
+}
@@ -7,6 +7,8 @@ package vpp
 import (
 	"fmt"
 
+	"go.fd.io/govpp/api"
+
 	"github.com/veesix-networks/osvbng/pkg/southbound"
 	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
 	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/osvbng_l2gw"
@@ -180,6 +182,71 @@ func (v *VPP) SetL2GWCircuitState(circuitID uint32, enabled bool) error {
 	return nil
 }
 
+// l2gwMoveWindow bounds the circuits whose delete and add are in flight
+// at once, keeping the replies within the channel's reply buffer.
+const l2gwMoveWindow = 32
+
+// MoveL2GWCircuits pipelines each circuit's delete and re-add: both
+// requests are queued back to back before any reply is read, so VPP
+// handles them in sequence without a control-plane round trip between
+// them. The plugin has no in-place handoff update; this keeps the gap
+// a circuit spends without forwarding to one API message.
+func (v *VPP) MoveL2GWCircuits(circuits []southbound.L2GWCircuit, handoffIfIndex uint32) []southbound.L2GWMoveResult {
+	out := make([]southbound.L2GWMoveResult, len(circuits))
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		for i := range out {
+			out[i].Err = fmt.Errorf("create API channel: %w", err)
+		}
+		return out
+	}
+	defer ch.Close()
+
+	type pending struct{ del, add api.RequestCtx }
+	for lo := 0; lo < len(circuits); lo += l2gwMoveWindow {
+		hi := min(lo+l2gwMoveWindow, len(circuits))
+		reqs := make([]pending, 0, hi-lo)
+		for _, circuit := range circuits[lo:hi] {
+			moved := circuit
+			moved.HandoffIfIndex = handoffIfIndex
+			reqs = append(reqs, pending{
+				del: ch.SendRequest(buildL2GWCircuitReq(circuit, false)),
+				add: ch.SendRequest(buildL2GWCircuitReq(moved, true)),
+			})
+		}
+		for k, req := range reqs {
+			out[lo+k] = receiveL2GWMove(req.del, req.add)
+		}
+	}
+	return out
+}
+
+// receiveL2GWMove collects one circuit's delete and add replies. The
+// add decides the outcome: when the delete failed the circuit was still
+// installed and the add is refused, leaving it where it was.
+func receiveL2GWMove(del, add api.RequestCtx) southbound.L2GWMoveResult {
+	var delErr error
+	delReply := &osvbng_l2gw.OsvbngL2gwAddDelCircuitReply{}
+	if err := del.ReceiveReply(delReply); err != nil {
+		delErr = fmt.Errorf("move l2gw circuit: del: %w", err)
+	} else if delReply.Retval != 0 {
+		delErr = fmt.Errorf("move l2gw circuit: del retval=%d", delReply.Retval)
+	}
+
+	addReply := &osvbng_l2gw.OsvbngL2gwAddDelCircuitReply{}
+	addErr := add.ReceiveReply(addReply)
+	if addErr == nil && addReply.Retval == 0 {
+		return southbound.L2GWMoveResult{CircuitID: addReply.CircuitID, HandoffEntryIndex: addReply.HandoffEntryIndex}
+	}
+	if delErr != nil {
+		return southbound.L2GWMoveResult{Err: delErr}
+	}
+	if addErr != nil {
+		return southbound.L2GWMoveResult{Removed: true, Err: fmt.Errorf("move l2gw circuit: add: %w", addErr)}
+	}
+	return southbound.L2GWMoveResult{Removed: true, Err: fmt.Errorf("move l2gw circuit: add retval=%d", addReply.Retval)}
+}
+
 func (v *VPP) DumpL2GWCircuits() ([]southbound.L2GWCircuitDetails, error) {
 	ch, err := v.conn.NewAPIChannel()
 	if err != nil {
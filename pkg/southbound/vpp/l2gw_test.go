@@ -0,0 +1,48 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"errors"
+	"testing"
+
+	"go.fd.io/govpp/api"
+
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/osvbng_l2gw"
+)
+
+// stubReply answers ReceiveReply with a fixed add/del reply or error.
+type stubReply struct {
+	reply osvbng_l2gw.OsvbngL2gwAddDelCircuitReply
+	err   error
+}
+
+func (s stubReply) ReceiveReply(msg api.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	*msg.(*osvbng_l2gw.OsvbngL2gwAddDelCircuitReply) = s.reply
+	return nil
+}
+
+func TestReceiveL2GWMoveOutcomes(t *testing.T) {
+	ok := stubReply{reply: osvbng_l2gw.OsvbngL2gwAddDelCircuitReply{CircuitID: 7, HandoffEntryIndex: 8}}
+	refused := stubReply{reply: osvbng_l2gw.OsvbngL2gwAddDelCircuitReply{Retval: retvalEntryNeedsRefresh}}
+	lost := stubReply{err: errors.New("timeout")}
+
+	res := receiveL2GWMove(ok, ok)
+	if res.Err != nil || res.Removed || res.CircuitID != 7 || res.HandoffEntryIndex != 8 {
+		t.Fatalf("moved: %+v", res)
+	}
+	if res := receiveL2GWMove(lost, refused); res.Err == nil || res.Removed {
+		t.Fatalf("failed delete must leave the circuit in place: %+v", res)
+	}
+	if res := receiveL2GWMove(ok, refused); res.Err == nil || !res.Removed {
+		t.Fatalf("failed add after delete must report removal: %+v", res)
+	}
+	if res := receiveL2GWMove(lost, ok); res.Err != nil || res.CircuitID != 7 {
+		t.Fatalf("add that landed decides the outcome: %+v", res)
+	}
+}
@@ -19,8 +19,17 @@ const (
 )
 
 // AddL2TPTunnel installs a tunnel-level lookup entry in the VPP plugin.
-// Returns the plugin-assigned tunnel_index.
+// Returns the plugin-assigned tunnel_index. Both endpoints must be of
+// the same family; the plugin selects IPv4 or IPv6 encapsulation from
+// the address family. dfBit has no meaning for IPv6 and is cleared.
 func (v *VPP) AddL2TPTunnel(local, peer net.IP, localID, peerID, localPort, peerPort uint16, dfBit bool) (uint32, error) {
+	if (local.To4() != nil) != (peer.To4() != nil) {
+		return 0, fmt.Errorf("l2tp tunnel endpoints %s and %s are of different address families", local, peer)
+	}
+	if peer.To4() == nil {
+		dfBit = false
+	}
+
 	ch, err := v.conn.NewAPIChannel()
 	if err != nil {
 		return 0, fmt.Errorf("create API channel: %w", err)
@@ -0,0 +1,49 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+var _ southbound.LocalSwitching = (*VPP)(nil)
+
+// permitAnyACLName is the inbound ACL that stands in behind a
+// FlowSpec or local-switching ACL on interfaces without one of their
+// own.
+const permitAnyACLName = aclcfg.ReservedPrefix + "permit-any"
+
+// ApplyLocalSwitchingACL puts aclName, a deny-only ACL, in the
+// interface's inbound list behind its FlowSpec ACL and ahead of its own
+// inbound ACL.
+func (v *VPP) ApplyLocalSwitchingACL(swIfIndex uint32, aclName string) error {
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.local = aclName })
+}
+
+// RemoveLocalSwitchingACL takes the local-switching ACL out of the
+// interface's inbound list.
+func (v *VPP) RemoveLocalSwitchingACL(swIfIndex uint32) error {
+	return v.updateACLBinding(swIfIndex, func(b *aclBinding) { b.local = "" })
+}
+
+// ensurePermitAnyACL creates the permit-any ACL the first time an
+// interface needs it. It is shared by every such interface and kept.
+func (v *VPP) ensurePermitAnyACL() error {
+	if _, ok := v.aclReg.lookup(permitAnyACLName); ok {
+		return nil
+	}
+	list := aclcfg.AccessList{Rules: []aclcfg.Rule{{Action: aclcfg.ActionPermit}}}
+	entries, _, err := list.Expand()
+	if err != nil {
+		return err
+	}
+	if _, err := v.AddReplaceACL(permitAnyACLName, entries); err != nil {
+		return fmt.Errorf("create %s: %w", permitAnyACLName, err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+	maps "github.com/veesix-networks/osvbng/pkg/vpp/binapi/map"
+)
+
+var _ southbound.MAP = (*VPP)(nil)
+
+func (v *VPP) MAPAddDomain(d southbound.MAPDomain) (uint32, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return 0, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &maps.MapAddDomain{
+		IP6Prefix:  ip_types.NewIP6Prefix(d.IPv6Prefix),
+		IP4Prefix:  ip_types.NewIP4Prefix(d.IPv4Prefix),
+		IP6Src:     ip_types.NewIP6Prefix(d.Src),
+		EaBitsLen:  d.EABitsLen,
+		PsidOffset: d.PSIDOffset,
+		PsidLength: d.PSIDLength,
+		Mtu:        d.MTU,
+		Tag:        d.Tag,
+	}
+
+	reply := &maps.MapAddDomainReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, fmt.Errorf("map add domain %s: %w", d.Tag, err)
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("map add domain %s failed: retval=%d", d.Tag, reply.Retval)
+	}
+	return reply.Index, nil
+}
+
+func (v *VPP) MAPDelDomain(index uint32) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	reply := &maps.MapDelDomainReply{}
+	if err := ch.SendRequest(&maps.MapDelDomain{Index: index}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("map del domain %d: %w", index, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("map del domain %d failed: retval=%d", index, reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) MAPDomainDump() ([]southbound.MAPDomain, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var results []southbound.MAPDomain
+	multi := ch.SendMultiRequest(&maps.MapDomainDump{})
+	for {
+		d := &maps.MapDomainDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive map domain details: %w", err)
+		}
+		results = append(results, southbound.MAPDomain{
+			Index:      d.DomainIndex,
+			IPv6Prefix: *d.IP6Prefix.ToIPNet(),
+			IPv4Prefix: *d.IP4Prefix.ToIPNet(),
+			Src:        *d.IP6Src.ToIPNet(),
+			EABitsLen:  d.EaBitsLen,
+			PSIDOffset: d.PsidOffset,
+			PSIDLength: d.PsidLength,
+			MTU:        d.Mtu,
+			Tag:        d.Tag,
+		})
+	}
+	return results, nil
+}
+
+func (v *VPP) MAPInterfaceEnableDisable(swIfIndex uint32, translation, enable bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &maps.MapIfEnableDisable{
+		SwIfIndex:     interface_types.InterfaceIndex(swIfIndex),
+		IsEnable:      enable,
+		IsTranslation: translation,
+	}
+
+	reply := &maps.MapIfEnableDisableReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("map interface %d: %w", swIfIndex, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("map interface %d failed: retval=%d", swIfIndex, reply.Retval)
+	}
+	return nil
+}
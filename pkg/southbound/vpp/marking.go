@@ -0,0 +1,311 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	govppapi "go.fd.io/govpp/api"
+
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/ifmgr"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	vppqos "github.com/veesix-networks/osvbng/pkg/vpp/binapi/qos"
+)
+
+var _ southbound.Marking = (*VPP)(nil)
+
+// The egress priority of a session is a QoS mark on its interface: VPP
+// writes the 802.1p bits of the frame from an egress map indexed by the
+// value recorded when the packet came in. Recording the IP TOS on every
+// uplink and access port makes that the packet's DSCP.
+//
+// A remark is not done by the QoS infra, which only marks on output;
+// it is folded into the session's ingress classes (qos.RemarkClasses).
+
+// markingRegistry tracks the marking maps, the egress map each PCP map
+// is programmed as, and the marking of each session.
+type markingRegistry struct {
+	mu       sync.Mutex
+	nextID   uint32
+	maps     map[string]*markingMap
+	sessions map[uint32]sessionMarking
+	// recorded are the interfaces the IP TOS is recorded on.
+	recorded map[uint32]bool
+}
+
+type markingMap struct {
+	pcp   bool
+	table []uint8
+	// id is the egress map of a PCP map.
+	id uint32
+}
+
+type sessionMarking struct {
+	marking qos.Marking
+	// pcpMap is the egress map marking the session, if any.
+	pcpMap uint32
+	marked bool
+}
+
+func newMarkingRegistry() *markingRegistry {
+	return &markingRegistry{
+		maps:     make(map[string]*markingMap),
+		sessions: make(map[uint32]sessionMarking),
+		recorded: make(map[uint32]bool),
+	}
+}
+
+// SetMarkingMap stores the map and, for a PCP map, programs it as an
+// egress map. Updating the egress map in place re-marks its sessions.
+func (v *VPP) SetMarkingMap(name string, pcp bool, table []uint8) error {
+	if len(table) != 64 {
+		return fmt.Errorf("marking map %q: table has %d entries, want 64", name, len(table))
+	}
+	r := v.markingReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.maps[name]
+	if m != nil && m.pcp == pcp && bytes.Equal(m.table, table) {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if m != nil && m.pcp && !pcp {
+		if err := deleteEgressMap(ch, m.id); err != nil {
+			return fmt.Errorf("marking map %q: %w", name, err)
+		}
+	}
+	if m == nil || (pcp && !m.pcp) {
+		m = &markingMap{id: r.nextID}
+		r.nextID++
+	}
+
+	if pcp {
+		if err := updateEgressMap(ch, m.id, table); err != nil {
+			return fmt.Errorf("marking map %q: %w", name, err)
+		}
+	}
+	m.pcp = pcp
+	m.table = append([]uint8(nil), table...)
+	r.maps[name] = m
+	v.logger.Debug("Marking map programmed", "name", name, "pcp", pcp)
+	return nil
+}
+
+// DeleteMarkingMap forgets the map and deletes its egress map, which
+// stops the marking of the sessions still using it.
+func (v *VPP) DeleteMarkingMap(name string) error {
+	r := v.markingReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.maps[name]
+	if m == nil {
+		return nil
+	}
+	if m.pcp {
+		ch, err := v.conn.NewAPIChannel()
+		if err != nil {
+			return fmt.Errorf("create API channel: %w", err)
+		}
+		defer ch.Close()
+		if err := deleteEgressMap(ch, m.id); err != nil {
+			return fmt.Errorf("marking map %q: %w", name, err)
+		}
+	}
+	delete(r.maps, name)
+	v.logger.Debug("Marking map removed", "name", name)
+	return nil
+}
+
+func updateEgressMap(ch govppapi.Channel, id uint32, table []uint8) error {
+	var rows [4]vppqos.QosEgressMapRow
+	for i := range rows {
+		rows[i].Outputs = make([]byte, 256)
+	}
+	ip := rows[vppqos.QOS_API_SOURCE_IP].Outputs
+	for tos := range ip {
+		ip[tos] = table[tos>>2]
+	}
+
+	reply := &vppqos.QosEgressMapUpdateReply{}
+	if err := ch.SendRequest(&vppqos.QosEgressMapUpdate{
+		Map: vppqos.QosEgressMap{ID: id, Rows: rows},
+	}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("qos egress map update: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("qos egress map update failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+func deleteEgressMap(ch govppapi.Channel, id uint32) error {
+	reply := &vppqos.QosEgressMapDeleteReply{}
+	if err := ch.SendRequest(&vppqos.QosEgressMapDelete{ID: id}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("qos egress map delete: %w", err)
+	}
+	if reply.Retval != 0 && reply.Retval != retvalNoSuchEntry {
+		return fmt.Errorf("qos egress map delete failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+// ApplyMarking records the session's marking for ApplyQoSClasses and
+// marks its frames from the egress PCP map. A no-op when the session
+// already has this marking.
+func (v *VPP) ApplyMarking(swIfIndex uint32, m qos.Marking) error {
+	r := v.markingReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cur, ok := r.sessions[swIfIndex]; ok && cur.marking == m {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+	v.unmarkLocked(ch, swIfIndex)
+	delete(r.sessions, swIfIndex)
+
+	s := sessionMarking{marking: m}
+	if m.EgressPCPMap != "" {
+		mm := r.maps[m.EgressPCPMap]
+		if mm == nil || !mm.pcp {
+			return fmt.Errorf("marking map %q is not a programmed pcp map", m.EgressPCPMap)
+		}
+
+		v.recordUplinksLocked(ch)
+		reply := &vppqos.QosMarkEnableDisableReply{}
+		err = ch.SendRequest(&vppqos.QosMarkEnableDisable{
+			Enable: true,
+			Mark: vppqos.QosMark{
+				SwIfIndex:    swIfIndex,
+				MapID:        mm.id,
+				OutputSource: vppqos.QOS_API_SOURCE_VLAN,
+			},
+		}).ReceiveReply(reply)
+		if err != nil {
+			return fmt.Errorf("qos mark enable: %w", err)
+		}
+		if reply.Retval != 0 {
+			return fmt.Errorf("qos mark enable failed: retval=%d", reply.Retval)
+		}
+		s.pcpMap, s.marked = mm.id, true
+	}
+
+	r.sessions[swIfIndex] = s
+	v.logger.Debug("Applied marking", "sw_if_index", swIfIndex,
+		"ingress", m.Ingress, "ingress_map", m.IngressMap, "egress_pcp_map", m.EgressPCPMap)
+	return nil
+}
+
+// recordUplinksLocked records the IP TOS on the physical and VLAN
+// interfaces not yet recording it, so the egress maps see the DSCP of
+// traffic toward subscribers. Failures are retried with the next
+// session. Caller holds markingReg.mu.
+func (v *VPP) recordUplinksLocked(ch govppapi.Channel) {
+	r := v.markingReg
+	for _, iface := range v.ifMgr.List() {
+		if iface.Type != ifmgr.IfTypeHardware && iface.Type != ifmgr.IfTypeSub {
+			continue
+		}
+		if r.recorded[iface.SwIfIndex] {
+			continue
+		}
+		reply := &vppqos.QosRecordEnableDisableReply{}
+		err := ch.SendRequest(&vppqos.QosRecordEnableDisable{
+			Enable: true,
+			Record: vppqos.QosRecord{
+				SwIfIndex:   interface_types.InterfaceIndex(iface.SwIfIndex),
+				InputSource: vppqos.QOS_API_SOURCE_IP,
+			},
+		}).ReceiveReply(reply)
+		if err != nil || (reply.Retval != 0 && reply.Retval != retvalValueExist) {
+			v.logger.Warn("Failed to record IP QoS on interface",
+				"interface", iface.Name, "error", err, "retval", reply.Retval)
+			continue
+		}
+		r.recorded[iface.SwIfIndex] = true
+	}
+}
+
+// RemoveMarking stops marking the session's frames and forgets its
+// marking. Best effort: the session interface is usually gone by now.
+func (v *VPP) RemoveMarking(swIfIndex uint32) error {
+	r := v.markingReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[swIfIndex]; !ok {
+		return nil
+	}
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		delete(r.sessions, swIfIndex)
+		v.logger.Warn("Failed to remove marking", "sw_if_index", swIfIndex, "error", err)
+		return nil
+	}
+	defer ch.Close()
+	v.unmarkLocked(ch, swIfIndex)
+	delete(r.sessions, swIfIndex)
+	v.logger.Debug("Removed marking", "sw_if_index", swIfIndex)
+	return nil
+}
+
+// unmarkLocked disables the session's QoS mark, if any. Caller holds
+// markingReg.mu.
+func (v *VPP) unmarkLocked(ch govppapi.Channel, swIfIndex uint32) {
+	s, ok := v.markingReg.sessions[swIfIndex]
+	if !ok || !s.marked {
+		return
+	}
+	reply := &vppqos.QosMarkEnableDisableReply{}
+	err := ch.SendRequest(&vppqos.QosMarkEnableDisable{
+		Enable: false,
+		Mark: vppqos.QosMark{
+			SwIfIndex:    swIfIndex,
+			MapID:        s.pcpMap,
+			OutputSource: vppqos.QOS_API_SOURCE_VLAN,
+		},
+	}).ReceiveReply(reply)
+	if err != nil || reply.Retval != 0 {
+		v.logger.Debug("QoS mark disable failed", "sw_if_index", swIfIndex, "error", err, "retval", reply.Retval)
+	}
+}
+
+// remarkTable returns the table the session's ingress DSCP is rewritten
+// with, nil when it is trusted.
+func (v *VPP) remarkTable(swIfIndex uint32) ([]uint8, error) {
+	r := v.markingReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.sessions[swIfIndex].marking
+	switch m.Ingress {
+	case qos.MarkingBleach:
+		return qos.BleachTable(), nil
+	case qos.MarkingRemark:
+		mm := r.maps[m.IngressMap]
+		if mm == nil || mm.pcp {
+			return nil, fmt.Errorf("marking map %q is not a programmed dscp map", m.IngressMap)
+		}
+		return mm.table, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/nat64"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/nat_types"
+)
+
+var _ southbound.NAT64 = (*VPP)(nil)
+
+// vppAPIFeatureAlreadyEnabled is what the NAT64 plugin answers an enable
+// with when it is already running.
+const vppAPIFeatureAlreadyEnabled int32 = -81
+
+func (v *VPP) NAT64Enable() error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	reply := &nat64.Nat64PluginEnableDisableReply{}
+	if err := ch.SendRequest(&nat64.Nat64PluginEnableDisable{Enable: true}).ReceiveReply(reply); err != nil {
+		if isRetval(err, vppAPIFeatureAlreadyEnabled) {
+			return nil
+		}
+		return fmt.Errorf("nat64 plugin enable: %w", err)
+	}
+	return nil
+}
+
+func (v *VPP) NAT64AddDelPrefix(prefix net.IPNet, vrfID uint32, isAdd bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &nat64.Nat64AddDelPrefix{
+		Prefix: ip_types.NewIP6Prefix(prefix),
+		VrfID:  vrfID,
+		IsAdd:  isAdd,
+	}
+
+	reply := &nat64.Nat64AddDelPrefixReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("nat64 prefix %s: %w", prefix.String(), err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("nat64 prefix %s failed: retval=%d", prefix.String(), reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) NAT64PrefixDump() ([]southbound.NAT64Prefix, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var results []southbound.NAT64Prefix
+	multi := ch.SendMultiRequest(&nat64.Nat64PrefixDump{})
+	for {
+		d := &nat64.Nat64PrefixDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive nat64 prefix details: %w", err)
+		}
+		results = append(results, southbound.NAT64Prefix{
+			Prefix: *d.Prefix.ToIPNet(),
+			VRFID:  d.VrfID,
+		})
+	}
+	return results, nil
+}
+
+func (v *VPP) NAT64AddDelPoolAddrRange(start, end net.IP, vrfID uint32, isAdd bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &nat64.Nat64AddDelPoolAddrRange{
+		StartAddr: ip4Addr(start),
+		EndAddr:   ip4Addr(end),
+		VrfID:     vrfID,
+		IsAdd:     isAdd,
+	}
+
+	reply := &nat64.Nat64AddDelPoolAddrRangeReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("nat64 pool addr range: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("nat64 pool addr range failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) NAT64PoolAddrDump() ([]net.IP, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var results []net.IP
+	multi := ch.SendMultiRequest(&nat64.Nat64PoolAddrDump{})
+	for {
+		d := &nat64.Nat64PoolAddrDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive nat64 pool addr details: %w", err)
+		}
+		results = append(results, ip4FromAddr(d.Address))
+	}
+	return results, nil
+}
+
+func (v *VPP) NAT64AddDelInterface(swIfIndex uint32, inside, isAdd bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	flags := nat_types.NAT_IS_OUTSIDE
+	if inside {
+		flags = nat_types.NAT_IS_INSIDE
+	}
+	req := &nat64.Nat64AddDelInterface{
+		IsAdd:     isAdd,
+		Flags:     flags,
+		SwIfIndex: interface_types.InterfaceIndex(swIfIndex),
+	}
+
+	reply := &nat64.Nat64AddDelInterfaceReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("nat64 interface %d: %w", swIfIndex, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("nat64 interface %d failed: retval=%d", swIfIndex, reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) NAT64SetTimeouts(udp, tcpEstablished, tcpTransitory, icmp uint32) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &nat64.Nat64SetTimeouts{
+		UDP:            udp,
+		TCPEstablished: tcpEstablished,
+		TCPTransitory:  tcpTransitory,
+		ICMP:           icmp,
+	}
+
+	reply := &nat64.Nat64SetTimeoutsReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("nat64 set timeouts: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("nat64 set timeouts failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+// NAT64BIBDump returns every binding of the NAT64 BIB, across
+// protocols.
+func (v *VPP) NAT64BIBDump() ([]southbound.NAT64BIBEntry, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return nil, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var results []southbound.NAT64BIBEntry
+	multi := ch.SendMultiRequest(&nat64.Nat64BibDump{Proto: 255})
+	for {
+		d := &nat64.Nat64BibDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive nat64 bib details: %w", err)
+		}
+		results = append(results, southbound.NAT64BIBEntry{
+			InsideIP:    append(net.IP(nil), d.IAddr[:]...),
+			InsidePort:  d.IPort,
+			OutsideIP:   ip4FromAddr(d.OAddr),
+			OutsidePort: d.OPort,
+			Protocol:    d.Proto,
+			VRFID:       d.VrfID,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/fib_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/npt66"
+)
+
+var _ southbound.NPTv6 = (*VPP)(nil)
+
+// NPTv6BindingAddDel adds or removes an npt66 binding on swIfIndex, the
+// uplink the translated traffic leaves and returns on. A redundant add
+// is treated as success so restores can replay bindings.
+func (v *VPP) NPTv6BindingAddDel(swIfIndex uint32, internal, external net.IPNet, isAdd bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &npt66.Npt66BindingAddDel{
+		IsAdd:     isAdd,
+		SwIfIndex: interface_types.InterfaceIndex(swIfIndex),
+		Internal:  ip_types.NewIP6Prefix(internal),
+		External:  ip_types.NewIP6Prefix(external),
+	}
+
+	reply := &npt66.Npt66BindingAddDelReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("npt66 binding %s -> %s on %d: %w", internal.String(), external.String(), swIfIndex, err)
+	}
+	if isAdd && reply.Retval == retvalValueExist {
+		return nil
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("npt66 binding %s -> %s on %d failed: retval=%d", internal.String(), external.String(), swIfIndex, reply.Retval)
+	}
+	return nil
+}
+
+// NPTv6RouteAddDel routes internal via the session interface swIfIndex
+// in the VRF's table. A nil next hop makes the route attached.
+func (v *VPP) NPTv6RouteAddDel(internal net.IPNet, vrf string, swIfIndex uint32, nextHop net.IP, isAdd bool) error {
+	var tableID uint32
+	if vrf != "" {
+		if v.vrfResolver == nil {
+			return fmt.Errorf("VRF resolver not configured")
+		}
+		id, _, _, err := v.vrfResolver(vrf)
+		if err != nil {
+			return fmt.Errorf("resolve VRF %q: %w", vrf, err)
+		}
+		tableID = id
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	route := ip.IPRoute{
+		TableID: tableID,
+		Prefix:  ip_types.NewPrefix(internal),
+	}
+	// A delete without paths removes the whole entry.
+	if isAdd {
+		var nh ip_types.IP6Address
+		if nextHop != nil {
+			copy(nh[:], nextHop.To16())
+		}
+		route.NPaths = 1
+		route.Paths = []fib_types.FibPath{{
+			SwIfIndex: swIfIndex,
+			Proto:     fib_types.FIB_API_PATH_NH_PROTO_IP6,
+			Nh:        fib_types.FibPathNh{Address: ip_types.AddressUnionIP6(nh)},
+		}}
+	}
+	reply := &ip.IPRouteAddDelReply{}
+	if err := ch.SendRequest(&ip.IPRouteAddDel{IsAdd: isAdd, Route: route}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("npt66 route %s via %d: %w", internal.String(), swIfIndex, err)
+	}
+	if reply.Retval != 0 && !(!isAdd && reply.Retval == retvalNoSuchEntry) {
+		return fmt.Errorf("npt66 route %s via %d failed: retval=%d", internal.String(), swIfIndex, reply.Retval)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/pnat"
+)
+
+var _ southbound.PNAT = (*VPP)(nil)
+
+func pnatMatch(t southbound.PNATTuple) pnat.PnatMatchTuple {
+	m := pnat.PnatMatchTuple{
+		Proto: ip_types.IPProto(t.Proto),
+		Sport: t.SrcPort,
+		Dport: t.DstPort,
+	}
+	if t.Src != nil {
+		m.Src = ip4Addr(t.Src)
+		m.Mask |= pnat.PNAT_SA
+	}
+	if t.Dst != nil {
+		m.Dst = ip4Addr(t.Dst)
+		m.Mask |= pnat.PNAT_DA
+	}
+	if t.SrcPort != 0 {
+		m.Mask |= pnat.PNAT_SPORT
+	}
+	if t.DstPort != 0 {
+		m.Mask |= pnat.PNAT_DPORT
+	}
+	if t.Proto != 0 {
+		m.Mask |= pnat.PNAT_PROTO
+	}
+	return m
+}
+
+func pnatRewrite(t southbound.PNATTuple) pnat.PnatRewriteTuple {
+	r := pnat.PnatRewriteTuple{
+		Sport: t.SrcPort,
+		Dport: t.DstPort,
+	}
+	if t.Src != nil {
+		r.Src = ip4Addr(t.Src)
+		r.Mask |= pnat.PNAT_SA
+	}
+	if t.Dst != nil {
+		r.Dst = ip4Addr(t.Dst)
+		r.Mask |= pnat.PNAT_DA
+	}
+	if t.SrcPort != 0 {
+		r.Mask |= pnat.PNAT_SPORT
+	}
+	if t.DstPort != 0 {
+		r.Mask |= pnat.PNAT_DPORT
+	}
+	return r
+}
+
+func (v *VPP) PNATBindingAdd(match, rewrite southbound.PNATTuple) (uint32, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return 0, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &pnat.PnatBindingAdd{
+		Match:   pnatMatch(match),
+		Rewrite: pnatRewrite(rewrite),
+	}
+
+	reply := &pnat.PnatBindingAddReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, fmt.Errorf("pnat binding add: %w", err)
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("pnat binding add failed: retval=%d", reply.Retval)
+	}
+	return reply.BindingIndex, nil
+}
+
+func (v *VPP) PNATBindingDel(index uint32) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	reply := &pnat.PnatBindingDelReply{}
+	if err := ch.SendRequest(&pnat.PnatBindingDel{BindingIndex: index}).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("pnat binding del %d: %w", index, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("pnat binding del %d failed: retval=%d", index, reply.Retval)
+	}
+	return nil
+}
+
+func (v *VPP) pnatAttachDetach(swIfIndex, index uint32, attach bool) error {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	var retval int32
+	if attach {
+		reply := &pnat.PnatBindingAttachReply{}
+		err = ch.SendRequest(&pnat.PnatBindingAttach{
+			SwIfIndex:    interface_types.InterfaceIndex(swIfIndex),
+			Attachment:   pnat.PNAT_IP4_INPUT,
+			BindingIndex: index,
+		}).ReceiveReply(reply)
+		retval = reply.Retval
+	} else {
+		reply := &pnat.PnatBindingDetachReply{}
+		err = ch.SendRequest(&pnat.PnatBindingDetach{
+			SwIfIndex:    interface_types.InterfaceIndex(swIfIndex),
+			Attachment:   pnat.PNAT_IP4_INPUT,
+			BindingIndex: index,
+		}).ReceiveReply(reply)
+		retval = reply.Retval
+	}
+
+	op := "detach"
+	if attach {
+		op = "attach"
+	}
+	if err != nil {
+		return fmt.Errorf("pnat binding %s %d on sw_if_index %d: %w", op, index, swIfIndex, err)
+	}
+	if retval != 0 {
+		return fmt.Errorf("pnat binding %s %d on sw_if_index %d failed: retval=%d", op, index, swIfIndex, retval)
+	}
+	return nil
+}
+
+func (v *VPP) PNATBindingAttach(swIfIndex, index uint32) error {
+	return v.pnatAttachDetach(swIfIndex, index, true)
+}
+
+func (v *VPP) PNATBindingDetach(swIfIndex, index uint32) error {
+	return v.pnatAttachDetach(swIfIndex, index, false)
+}
+
+func (v *VPP) PNATFlowLookup(swIfIndex uint32, match southbound.PNATTuple) (uint32, error) {
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return 0, fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	req := &pnat.PnatFlowLookup{
+		SwIfIndex:  interface_types.InterfaceIndex(swIfIndex),
+		Attachment: pnat.PNAT_IP4_INPUT,
+		Match:      pnatMatch(match),
+	}
+
+	reply := &pnat.PnatFlowLookupReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, fmt.Errorf("pnat flow lookup: %w", err)
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("pnat flow lookup failed: retval=%d", reply.Retval)
+	}
+	return reply.BindingIndex, nil
+}
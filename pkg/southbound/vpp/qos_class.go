@@ -0,0 +1,567 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/classify"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/policer"
+	govppapi "go.fd.io/govpp/api"
+)
+
+// Ingress classes are policer-classify tables on the session interface.
+// The tables look at a 64-byte window starting 16 bytes before the IP
+// header, so the VLAN tag of the access frame is still in reach for
+// 802.1p matches: IPoE has the innermost tag's TCI 4 bytes before IP,
+// PPPoE 12 bytes before, behind the PPPoE and PPP headers.
+const (
+	classifyWindow     = 64
+	classifyOffset     = -16
+	classifyIPAt       = -classifyOffset
+	classifyPCPIPoE    = classifyIPAt - 4
+	classifyPCPPPPoE   = classifyIPAt - 12
+	classifyTableBytes = 1 << 16
+)
+
+// qosClassBinding is what ApplyQoSClasses programmed on one interface.
+type qosClassBinding struct {
+	ingress []ingressClass
+	egress  []egressClass
+	ip4     []uint32
+	ip6     []uint32
+}
+
+type ingressClass struct {
+	name         string
+	policer      string
+	policerIndex uint32
+	exceedDrops  bool
+	violateDrops bool
+}
+
+type egressClass struct {
+	name string
+	tin  uint8
+}
+
+// classifyTable is one table of a class: every session in it shares the
+// mask. A class needs one table per distinct set of fields it matches.
+type classifyTable struct {
+	class    int
+	mask     []byte
+	sessions [][]byte
+}
+
+// compileClasses turns ingress classes into per-family classify tables in
+// match order. lookupACL resolves match.access-list; pcpAt is where the
+// TCI's first byte sits in the window.
+func compileClasses(classes []qos.Class, lookupACL func(string) ([]aclcfg.Entry, bool), pcpAt int) (ip4, ip6 []classifyTable, err error) {
+	for i := range classes {
+		c := &classes[i]
+		dscps, err := c.Match.DSCPValues()
+		if err != nil {
+			return nil, nil, fmt.Errorf("class %q: %w", c.Name, err)
+		}
+		proto, err := c.Match.ProtocolNumber()
+		if err != nil {
+			return nil, nil, fmt.Errorf("class %q: %w", c.Name, err)
+		}
+
+		var entries []aclcfg.Entry
+		if c.Match.AccessList != "" {
+			list, ok := lookupACL(c.Match.AccessList)
+			if !ok {
+				return nil, nil, fmt.Errorf("class %q: access-list %q is not programmed", c.Name, c.Match.AccessList)
+			}
+			for _, e := range list {
+				if err := qos.ClassifiableEntry(e); err != nil {
+					return nil, nil, fmt.Errorf("class %q: access-list %q: %w", c.Name, c.Match.AccessList, err)
+				}
+				if proto != 0 {
+					if e.Protocol != 0 && e.Protocol != proto {
+						continue
+					}
+					e.Protocol = proto
+				}
+				entries = append(entries, e)
+			}
+		} else {
+			for _, v6 := range []bool{false, true} {
+				entries = append(entries, aclcfg.Entry{
+					IPv6: v6, Protocol: proto,
+					SrcFirst: 0, SrcLast: 65535, DstFirst: 0, DstLast: 65535,
+				})
+			}
+		}
+
+		// -1 stands for any value of a field the class does not match.
+		dscpKeys := []int{-1}
+		if len(dscps) > 0 {
+			dscpKeys = dscpKeys[:0]
+			for _, d := range dscps {
+				dscpKeys = append(dscpKeys, int(d))
+			}
+		}
+		pcpKeys := []int{-1}
+		if len(c.Match.PCP) > 0 {
+			pcpKeys = pcpKeys[:0]
+			for _, p := range c.Match.PCP {
+				pcpKeys = append(pcpKeys, int(p))
+			}
+		}
+
+		var t4, t6 []classifyTable
+		for _, e := range entries {
+			for _, d := range dscpKeys {
+				for _, p := range pcpKeys {
+					mask, match := classifyKey(e, d, p, pcpAt)
+					if e.IPv6 {
+						t6 = addSession(t6, i, mask, match)
+					} else {
+						t4 = addSession(t4, i, mask, match)
+					}
+				}
+			}
+		}
+		ip4 = append(ip4, t4...)
+		ip6 = append(ip6, t6...)
+	}
+	return ip4, ip6, nil
+}
+
+func addSession(tables []classifyTable, class int, mask, match []byte) []classifyTable {
+	for i := range tables {
+		if bytes.Equal(tables[i].mask, mask) {
+			tables[i].sessions = append(tables[i].sessions, match)
+			return tables
+		}
+	}
+	return append(tables, classifyTable{class: class, mask: mask, sessions: [][]byte{match}})
+}
+
+// classifyKey builds the mask and match of one entry with one DSCP and
+// one PCP value, -1 meaning any.
+func classifyKey(e aclcfg.Entry, dscp, pcp, pcpAt int) (mask, match []byte) {
+	mask = make([]byte, classifyWindow)
+	match = make([]byte, classifyWindow)
+	set := func(off int, m, v byte) {
+		mask[off] |= m
+		match[off] |= v & m
+	}
+	prefix := func(off int, n net.IPNet) {
+		ip := n.IP.To16()
+		if !e.IPv6 {
+			ip = n.IP.To4()
+		}
+		for i := range n.Mask {
+			set(off+i, n.Mask[i], ip[i])
+		}
+	}
+	h := classifyIPAt
+
+	if pcp >= 0 {
+		set(pcpAt, 0xE0, byte(pcp)<<5)
+	}
+
+	// Ports are read at a fixed offset, which an exact-match classifier
+	// cannot move with the header length. An IPv4 key that matches ports
+	// therefore also matches a 20-byte header and a first fragment, so a
+	// packet with options, or a later fragment, misses the class rather
+	// than being classified on the wrong bytes. IPv6 ports are only
+	// matched with the protocol as next header, which an extension
+	// header already fails.
+	l4 := h + 20
+	if e.IPv6 {
+		if dscp >= 0 {
+			set(h, 0x0F, byte(dscp)>>2)
+			set(h+1, 0xC0, byte(dscp)<<6)
+		}
+		if e.Protocol != 0 {
+			set(h+6, 0xFF, e.Protocol)
+		}
+		prefix(h+8, e.Source)
+		prefix(h+24, e.Destination)
+		l4 = h + 40
+	} else {
+		if dscp >= 0 {
+			set(h+1, 0xFC, byte(dscp)<<2)
+		}
+		if e.Protocol != 0 {
+			set(h+9, 0xFF, e.Protocol)
+		}
+		prefix(h+12, e.Source)
+		prefix(h+16, e.Destination)
+	}
+
+	ports := false
+	if e.Protocol == 1 || e.Protocol == 58 {
+		if e.SrcFirst == e.SrcLast {
+			set(l4, 0xFF, byte(e.SrcFirst))
+			ports = true
+		}
+		if e.DstFirst == e.DstLast {
+			set(l4+1, 0xFF, byte(e.DstFirst))
+			ports = true
+		}
+	} else if e.Protocol == 6 || e.Protocol == 17 {
+		if e.SrcFirst == e.SrcLast {
+			set(l4, 0xFF, byte(e.SrcFirst>>8))
+			set(l4+1, 0xFF, byte(e.SrcFirst))
+			ports = true
+		}
+		if e.DstFirst == e.DstLast {
+			set(l4+2, 0xFF, byte(e.DstFirst>>8))
+			set(l4+3, 0xFF, byte(e.DstFirst))
+			ports = true
+		}
+	}
+	if ports && !e.IPv6 {
+		set(h, 0x0F, 5)
+		set(h+6, 0x1F, 0)
+		set(h+7, 0xFF, 0)
+	}
+	return mask, match
+}
+
+// ApplyQoSClasses programs the ingress classes as a policer per class fed
+// by classify tables, and records the egress classes' tins for the
+// counters. A session that remarks has the remark folded into its ingress
+// classes first. A no-op when the interface already has its classes.
+func (v *VPP) ApplyQoSClasses(swIfIndex uint32, ingress, egress *qos.Policy) error {
+	var inClasses, outClasses []qos.Class
+	if ingress != nil {
+		inClasses = ingress.Classes
+	}
+	if egress != nil {
+		outClasses = egress.Classes
+	}
+	remark, err := v.remarkTable(swIfIndex)
+	if err != nil {
+		return err
+	}
+	if remark != nil {
+		if inClasses, err = qos.RemarkClasses(inClasses, remark); err != nil {
+			return err
+		}
+	}
+	if len(inClasses) == 0 && len(outClasses) == 0 {
+		return nil
+	}
+
+	v.qosClassMu.Lock()
+	defer v.qosClassMu.Unlock()
+	if _, ok := v.qosClasses[swIfIndex]; ok {
+		return nil
+	}
+
+	b := &qosClassBinding{}
+	if len(outClasses) > 0 && egress.Scheduler != nil {
+		for i := range outClasses {
+			tin, err := outClasses[i].Tin(egress.Scheduler.TinMode)
+			if err != nil {
+				return fmt.Errorf("egress class %q: %w", outClasses[i].Name, err)
+			}
+			b.egress = append(b.egress, egressClass{name: outClasses[i].Name, tin: tin})
+		}
+	}
+
+	if len(inClasses) > 0 {
+		if err := v.programIngressClasses(swIfIndex, inClasses, b); err != nil {
+			v.unprogramIngressClasses(swIfIndex, b)
+			return err
+		}
+	}
+
+	v.qosClasses[swIfIndex] = b
+	v.logger.Debug("Applied QoS classes", "sw_if_index", swIfIndex,
+		"ingress_classes", len(b.ingress), "egress_classes", len(b.egress))
+	return nil
+}
+
+func (v *VPP) programIngressClasses(swIfIndex uint32, classes []qos.Class, b *qosClassBinding) error {
+	pcpAt := classifyPCPIPoE
+	if strings.HasPrefix(v.interfaceName(swIfIndex), "pppoe-session-") {
+		pcpAt = classifyPCPPPPoE
+	}
+	ip4, ip6, err := compileClasses(classes, v.aclReg.entriesOf, pcpAt)
+	if err != nil {
+		return err
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	for i := range classes {
+		c := &classes[i]
+		p, err := c.ToPolicerConfig()
+		if err != nil {
+			return fmt.Errorf("class %q: %w", c.Name, err)
+		}
+		cfg := p.ToPolicerConfig()
+		name := fmt.Sprintf("sub_%d_in_%s", swIfIndex, c.Name)
+		reply := &policer.PolicerAddDelReply{}
+		err = ch.SendRequest(&policer.PolicerAddDel{
+			IsAdd:         true,
+			Name:          name,
+			Cir:           cfg.Cir,
+			Eir:           cfg.Eir,
+			Cb:            cfg.Cb,
+			Eb:            cfg.Eb,
+			RateType:      cfg.RateType,
+			RoundType:     cfg.RoundType,
+			Type:          cfg.Type,
+			ColorAware:    cfg.ColorAware,
+			ConformAction: cfg.ConformAction,
+			ExceedAction:  cfg.ExceedAction,
+			ViolateAction: cfg.ViolateAction,
+		}).ReceiveReply(reply)
+		if err != nil {
+			return fmt.Errorf("policer add class %q: %w", c.Name, err)
+		}
+		if reply.Retval != 0 {
+			return fmt.Errorf("policer add class %q failed: retval=%d", c.Name, reply.Retval)
+		}
+		b.ingress = append(b.ingress, ingressClass{
+			name:         c.Name,
+			policer:      name,
+			policerIndex: reply.PolicerIndex,
+			exceedDrops:  p.Exceed.Action == qos.ActionDrop,
+			violateDrops: p.Violate.Action == qos.ActionDrop,
+		})
+	}
+
+	if b.ip4, err = v.addClassifyChain(ch, ip4, b.ingress); err != nil {
+		return err
+	}
+	if b.ip6, err = v.addClassifyChain(ch, ip6, b.ingress); err != nil {
+		return err
+	}
+
+	req := &classify.PolicerClassifySetInterface{
+		SwIfIndex:     interface_types.InterfaceIndex(swIfIndex),
+		IP4TableIndex: chainHead(b.ip4),
+		IP6TableIndex: chainHead(b.ip6),
+		L2TableIndex:  ^uint32(0),
+		IsAdd:         true,
+	}
+	reply := &classify.PolicerClassifySetInterfaceReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("policer classify attach: %w", err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("policer classify attach failed: retval=%d", reply.Retval)
+	}
+	return nil
+}
+
+// addClassifyChain creates tables last first, so each can name its
+// successor as the next table on a miss. The returned indexes are in
+// match order; on failure they are the tables created so far.
+func (v *VPP) addClassifyChain(ch govppapi.Channel, tables []classifyTable, classes []ingressClass) ([]uint32, error) {
+	out := make([]uint32, len(tables))
+	next := ^uint32(0)
+	for i := len(tables) - 1; i >= 0; i-- {
+		t := tables[i]
+		nbuckets := uint32(2)
+		for nbuckets < uint32(len(t.sessions)) && nbuckets < 1024 {
+			nbuckets <<= 1
+		}
+		reply := &classify.ClassifyAddDelTableReply{}
+		err := ch.SendRequest(&classify.ClassifyAddDelTable{
+			IsAdd:             true,
+			TableIndex:        ^uint32(0),
+			Nbuckets:          nbuckets,
+			MemorySize:        classifyTableBytes,
+			MatchNVectors:     classifyWindow / 16,
+			NextTableIndex:    next,
+			MissNextIndex:     ^uint32(0),
+			CurrentDataFlag:   1,
+			CurrentDataOffset: classifyOffset,
+			Mask:              t.mask,
+		}).ReceiveReply(reply)
+		if err != nil {
+			return out[i+1:], fmt.Errorf("classify table add: %w", err)
+		}
+		if reply.Retval != 0 {
+			return out[i+1:], fmt.Errorf("classify table add failed: retval=%d", reply.Retval)
+		}
+		out[i] = reply.NewTableIndex
+		next = reply.NewTableIndex
+
+		for _, match := range t.sessions {
+			sreply := &classify.ClassifyAddDelSessionReply{}
+			// For a policer table the hit index is the policer itself.
+			err := ch.SendRequest(&classify.ClassifyAddDelSession{
+				IsAdd:        true,
+				TableIndex:   reply.NewTableIndex,
+				HitNextIndex: classes[t.class].policerIndex,
+				OpaqueIndex:  ^uint32(0),
+				Match:        match,
+			}).ReceiveReply(sreply)
+			if err != nil {
+				return out[i:], fmt.Errorf("classify session add: %w", err)
+			}
+			if sreply.Retval != 0 {
+				return out[i:], fmt.Errorf("classify session add failed: retval=%d", sreply.Retval)
+			}
+		}
+	}
+	return out, nil
+}
+
+func chainHead(tables []uint32) uint32 {
+	if len(tables) == 0 {
+		return ^uint32(0)
+	}
+	return tables[0]
+}
+
+// RemoveQoSClasses detaches and deletes what ApplyQoSClasses programmed.
+func (v *VPP) RemoveQoSClasses(swIfIndex uint32) error {
+	v.qosClassMu.Lock()
+	defer v.qosClassMu.Unlock()
+
+	b, ok := v.qosClasses[swIfIndex]
+	if !ok {
+		return nil
+	}
+	delete(v.qosClasses, swIfIndex)
+	v.unprogramIngressClasses(swIfIndex, b)
+
+	v.logger.Debug("Removed QoS classes", "sw_if_index", swIfIndex)
+	return nil
+}
+
+// unprogramIngressClasses is best effort: the session interface is
+// usually gone by now, and with it the classify attachment.
+func (v *VPP) unprogramIngressClasses(swIfIndex uint32, b *qosClassBinding) {
+	if len(b.ingress) == 0 && len(b.ip4) == 0 && len(b.ip6) == 0 {
+		return
+	}
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		v.logger.Warn("Failed to remove QoS classes", "sw_if_index", swIfIndex, "error", err)
+		return
+	}
+	defer ch.Close()
+
+	if len(b.ip4) > 0 || len(b.ip6) > 0 {
+		req := &classify.PolicerClassifySetInterface{
+			SwIfIndex:     interface_types.InterfaceIndex(swIfIndex),
+			IP4TableIndex: chainHead(b.ip4),
+			IP6TableIndex: chainHead(b.ip6),
+			L2TableIndex:  ^uint32(0),
+			IsAdd:         false,
+		}
+		if err := ch.SendRequest(req).ReceiveReply(&classify.PolicerClassifySetInterfaceReply{}); err != nil {
+			v.logger.Debug("Policer classify detach failed", "sw_if_index", swIfIndex, "error", err)
+		}
+	}
+
+	for _, idx := range append(append([]uint32{}, b.ip4...), b.ip6...) {
+		req := &classify.ClassifyAddDelTable{IsAdd: false, TableIndex: idx}
+		if err := ch.SendRequest(req).ReceiveReply(&classify.ClassifyAddDelTableReply{}); err != nil {
+			v.logger.Warn("Failed to delete classify table", "sw_if_index", swIfIndex, "table", idx, "error", err)
+		}
+	}
+
+	for _, c := range b.ingress {
+		req := &policer.PolicerAddDel{Name: c.policer, IsAdd: false}
+		if err := ch.SendRequest(req).ReceiveReply(&policer.PolicerAddDelReply{}); err != nil {
+			v.logger.Warn("Failed to delete class policer", "sw_if_index", swIfIndex, "policer", c.policer, "error", err)
+		}
+	}
+}
+
+// DumpQoSClasses reports every class on every session with its counters.
+// Ingress counters come from the class policers, egress counters from the
+// scheduler tin each class maps to.
+func (v *VPP) DumpQoSClasses() ([]southbound.QoSClassState, error) {
+	v.qosClassMu.Lock()
+	bindings := make(map[uint32]*qosClassBinding, len(v.qosClasses))
+	for sw, b := range v.qosClasses {
+		bindings[sw] = b
+	}
+	v.qosClassMu.Unlock()
+
+	if len(bindings) == 0 {
+		return nil, nil
+	}
+
+	policers, err := v.statsClient.GetPolicerStats()
+	if err != nil {
+		v.logger.Debug("Policer counters unavailable", "error", err)
+	}
+
+	var out []southbound.QoSClassState
+	for sw, b := range bindings {
+		name := v.interfaceName(sw)
+		for _, c := range b.ingress {
+			s := southbound.QoSClassState{
+				SwIfIndex:     sw,
+				InterfaceName: name,
+				Direction:     qos.DirectionIngress,
+				Class:         c.name,
+			}
+			if ctr, ok := policers[c.policerIndex]; ok {
+				s.Packets = ctr.conform.packets + ctr.exceed.packets + ctr.violate.packets
+				s.Bytes = ctr.conform.bytes + ctr.exceed.bytes + ctr.violate.bytes
+				s.ExceedPackets = ctr.exceed.packets
+				s.ViolatePackets = ctr.violate.packets
+				if c.exceedDrops {
+					s.Drops += ctr.exceed.packets
+				}
+				if c.violateDrops {
+					s.Drops += ctr.violate.packets
+				}
+			}
+			out = append(out, s)
+		}
+
+		if len(b.egress) == 0 {
+			continue
+		}
+		sched, err := v.DumpScheduler(sw)
+		if err != nil {
+			v.logger.Debug("Scheduler counters unavailable", "sw_if_index", sw, "error", err)
+		}
+		for _, c := range b.egress {
+			tin := c.tin
+			s := southbound.QoSClassState{
+				SwIfIndex:     sw,
+				InterfaceName: name,
+				Direction:     qos.DirectionEgress,
+				Class:         c.name,
+				Tin:           &tin,
+			}
+			if sched != nil && int(tin) < len(sched.Tins) {
+				s.Packets = sched.Tins[tin].Packets
+				s.Bytes = sched.Tins[tin].Bytes
+				s.Drops = sched.Tins[tin].Drops
+			}
+			out = append(out, s)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].SwIfIndex != out[j].SwIfIndex {
+			return out[i].SwIfIndex < out[j].SwIfIndex
+		}
+		return out[i].Direction > out[j].Direction
+	})
+	return out, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"testing"
+
+	aclcfg "github.com/veesix-networks/osvbng/pkg/config/acl"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+)
+
+func TestCompileClassesDSCPAndPCP(t *testing.T) {
+	classes := []qos.Class{
+		{Name: "voice", Match: qos.ClassMatch{DSCP: []string{"ef", "cs5"}, PCP: []uint8{5}}},
+	}
+	ip4, ip6, err := compileClasses(classes, nil, classifyPCPPPPoE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ip4) != 1 || len(ip6) != 1 {
+		t.Fatalf("tables = %d/%d, want one per family", len(ip4), len(ip6))
+	}
+	if len(ip4[0].sessions) != 2 {
+		t.Fatalf("ip4 sessions = %d, want one per dscp", len(ip4[0].sessions))
+	}
+
+	h := classifyIPAt
+	mask, match := ip4[0].mask, ip4[0].sessions[0]
+	if mask[h+1] != 0xFC || match[h+1] != 46<<2 {
+		t.Errorf("ipv4 tos mask/match = %#x/%#x", mask[h+1], match[h+1])
+	}
+	if mask[classifyPCPPPPoE] != 0xE0 || match[classifyPCPPPPoE] != 5<<5 {
+		t.Errorf("pcp mask/match = %#x/%#x", mask[classifyPCPPPPoE], match[classifyPCPPPPoE])
+	}
+
+	mask, match = ip6[0].mask, ip6[0].sessions[0]
+	if mask[h] != 0x0F || match[h] != 46>>2 || mask[h+1] != 0xC0 || match[h+1] != (46<<6)&0xC0 {
+		t.Errorf("ipv6 traffic class = %#x/%#x %#x/%#x", mask[h], match[h], mask[h+1], match[h+1])
+	}
+}
+
+func TestCompileClassesAccessList(t *testing.T) {
+	list := &aclcfg.AccessList{Rules: []aclcfg.Rule{
+		{Action: aclcfg.ActionPermit, Family: aclcfg.FamilyIPv4, Protocol: "udp", Destination: "198.51.100.0/24", DestinationPort: "5060"},
+		{Action: aclcfg.ActionPermit, Family: aclcfg.FamilyIPv4, Protocol: "udp", Destination: "198.51.100.7/32", DestinationPort: "5061"},
+	}}
+	entries, _, err := list.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) ([]aclcfg.Entry, bool) { return entries, name == "sip" }
+
+	classes := []qos.Class{
+		{Name: "sip", Match: qos.ClassMatch{AccessList: "sip"}},
+		{Name: "bulk", Match: qos.ClassMatch{DSCP: []string{"cs1"}}},
+	}
+	ip4, ip6, err := compileClasses(classes, lookup, classifyPCPIPoE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The two rules differ in prefix length, so two tables for the first
+	// class, then the second class; no IPv6 rules in the list.
+	if len(ip4) != 3 || ip4[0].class != 0 || ip4[1].class != 0 || ip4[2].class != 1 {
+		t.Fatalf("ip4 tables = %+v", ip4)
+	}
+	if len(ip6) != 1 || ip6[0].class != 1 {
+		t.Fatalf("ip6 tables = %d", len(ip6))
+	}
+
+	h := classifyIPAt
+	m := ip4[0].sessions[0]
+	if m[h+9] != 17 || m[h+16] != 198 || m[h+18] != 100 || m[h+19] != 0 || m[h+22] != 0x13 || m[h+23] != 0xC4 {
+		t.Fatalf("sip match = % x", m[h:h+24])
+	}
+	if ip4[0].mask[h+19] != 0 || ip4[1].mask[h+19] != 0xFF {
+		t.Fatalf("prefix masks = %#x, %#x", ip4[0].mask[h+19], ip4[1].mask[h+19])
+	}
+	// Ports sit behind a 20-byte header only in a first fragment.
+	if ip4[0].mask[h] != 0x0F || m[h] != 5 || ip4[0].mask[h+6] != 0x1F || ip4[0].mask[h+7] != 0xFF || m[h+6] != 0 || m[h+7] != 0 {
+		t.Fatalf("header length and fragment mask/match = % x / % x", ip4[0].mask[h:h+8], m[h:h+8])
+	}
+	if ip4[2].mask[h] != 0 || ip4[2].mask[h+7] != 0 {
+		t.Fatalf("dscp-only class matches header length or fragment: % x", ip4[2].mask[h:h+8])
+	}
+
+	if _, _, err := compileClasses(classes, func(string) ([]aclcfg.Entry, bool) { return nil, false }, classifyPCPIPoE); err == nil {
+		t.Fatal("unprogrammed access-list accepted")
+	}
+}
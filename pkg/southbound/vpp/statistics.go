@@ -43,3 +43,7 @@ func (v *VPP) GetBufferStats() ([]southbound.BufferStats, error) {
 func (v *VPP) GetL2GWStats() (map[uint32]southbound.L2GWEntryStats, error) {
 	return v.statsClient.GetL2GWStats()
 }
+
+func (v *VPP) GetCGNATLimitDrops() (southbound.CGNATLimitDrops, error) {
+	return v.statsClient.GetCGNATLimitDrops()
+}
@@ -2,6 +2,8 @@ package vpp
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/veesix-networks/osvbng/pkg/southbound"
@@ -319,3 +321,168 @@ func (s *StatsClient) GetL2GWStats() (map[uint32]southbound.L2GWEntryStats, erro
 
 	return result, nil
 }
+
+// The CGNAT plugin's limit error counters, registered on each of its
+// in2out nodes as /err/<node>/<name>.
+const (
+	cgnatErrSessionLimit  = "Per-subscriber session limit reached"
+	cgnatErrPortExhausted = "Port block exhausted"
+)
+
+// GetCGNATLimitDrops reads the CGNAT plugin's session-limit and
+// port-exhaustion error counters, summed across its nodes and workers.
+// A dataplane without them yields ErrCGNATLimitCountersUnavailable.
+func (s *StatsClient) GetCGNATLimitDrops() (southbound.CGNATLimitDrops, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.connected {
+		return southbound.CGNATLimitDrops{}, fmt.Errorf("not connected to stats")
+	}
+
+	entries, err := s.client.DumpStats("/err/cgnat-")
+	if err != nil {
+		return southbound.CGNATLimitDrops{}, fmt.Errorf("dump cgnat error stats: %w", err)
+	}
+	return cgnatLimitDrops(entries)
+}
+
+// cgnatLimitDrops sums the limit error counters among entries.
+func cgnatLimitDrops(entries []adapter.StatEntry) (southbound.CGNATLimitDrops, error) {
+	var drops southbound.CGNATLimitDrops
+	found := false
+	for _, entry := range entries {
+		name := string(entry.Name)
+		var counter *uint64
+		switch {
+		case strings.HasSuffix(name, "/"+cgnatErrSessionLimit):
+			counter = &drops.SessionLimitDrops
+		case strings.HasSuffix(name, "/"+cgnatErrPortExhausted):
+			counter = &drops.PortExhaustionDrops
+		default:
+			continue
+		}
+		found = true
+		switch data := entry.Data.(type) {
+		case adapter.ErrorStat:
+			for _, v := range data {
+				*counter += uint64(v)
+			}
+		case adapter.SimpleCounterStat:
+			for _, worker := range data {
+				for _, v := range worker {
+					*counter += uint64(v)
+				}
+			}
+		}
+	}
+	if !found {
+		return drops, southbound.ErrCGNATLimitCountersUnavailable
+	}
+	return drops, nil
+}
+
+// GetACLRuleStats reads the ACL plugin's per-rule match counters from
+// the /acl/<index>/matches stats entries, summed across workers, keyed
+// by ACL index. The entries only count once interface counters are
+// enabled.
+func (s *StatsClient) GetACLRuleStats() (map[uint32][]southbound.ACLRuleStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.connected {
+		return nil, fmt.Errorf("not connected to stats")
+	}
+
+	entries, err := s.client.DumpStats("/acl/")
+	if err != nil {
+		return nil, fmt.Errorf("dump acl stats: %w", err)
+	}
+
+	result := make(map[uint32][]southbound.ACLRuleStats)
+	for _, entry := range entries {
+		combined, ok := entry.Data.(adapter.CombinedCounterStat)
+		if !ok {
+			continue
+		}
+		idxStr, ok := strings.CutSuffix(strings.TrimPrefix(string(entry.Name), "/acl/"), "/matches")
+		if !ok {
+			continue
+		}
+		aclIndex, err := strconv.ParseUint(idxStr, 10, 32)
+		if err != nil {
+			continue
+		}
+		var rules []southbound.ACLRuleStats
+		for _, worker := range combined {
+			for idx, ctr := range worker {
+				for len(rules) <= idx {
+					rules = append(rules, southbound.ACLRuleStats{})
+				}
+				rules[idx].Packets += ctr.Packets()
+				rules[idx].Bytes += ctr.Bytes()
+			}
+		}
+		result[uint32(aclIndex)] = rules
+	}
+
+	return result, nil
+}
+
+type policerCounter struct {
+	packets uint64
+	bytes   uint64
+}
+
+type policerCounters struct {
+	conform policerCounter
+	exceed  policerCounter
+	violate policerCounter
+}
+
+// GetPolicerStats reads the per-colour policer counters from the
+// /net/policer/{conform,exceed,violate} stats entries, summed across
+// workers, keyed by policer index.
+func (s *StatsClient) GetPolicerStats() (map[uint32]policerCounters, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.connected {
+		return nil, fmt.Errorf("not connected to stats")
+	}
+
+	entries, err := s.client.DumpStats("/net/policer/")
+	if err != nil {
+		return nil, fmt.Errorf("dump policer stats: %w", err)
+	}
+
+	result := make(map[uint32]policerCounters)
+	for _, entry := range entries {
+		combined, ok := entry.Data.(adapter.CombinedCounterStat)
+		if !ok {
+			continue
+		}
+		colour := strings.TrimPrefix(string(entry.Name), "/net/policer/")
+		for _, worker := range combined {
+			for idx, ctr := range worker {
+				c := result[uint32(idx)]
+				var dst *policerCounter
+				switch colour {
+				case "conform":
+					dst = &c.conform
+				case "exceed":
+					dst = &c.exceed
+				case "violate":
+					dst = &c.violate
+				default:
+					continue
+				}
+				dst.packets += ctr.Packets()
+				dst.bytes += ctr.Bytes()
+				result[uint32(idx)] = c
+			}
+		}
+	}
+
+	return result, nil
+}
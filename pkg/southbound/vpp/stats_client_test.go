@@ -0,0 +1,55 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"go.fd.io/govpp/adapter"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+)
+
+func TestCGNATLimitDrops(t *testing.T) {
+	entry := func(name string, data adapter.Stat) adapter.StatEntry {
+		return adapter.StatEntry{StatIdentifier: adapter.StatIdentifier{Name: adapter.Name(name)}, Data: data}
+	}
+	entries := []adapter.StatEntry{
+		entry("/err/cgnat-in2out/Per-subscriber session limit reached", adapter.SimpleCounterStat{{3}, {4}}),
+		entry("/err/cgnat-in2out-slowpath/Per-subscriber session limit reached", adapter.SimpleCounterStat{{1}, {0}}),
+		entry("/err/cgnat-in2out-slowpath/Port block exhausted", adapter.ErrorStat{5, 6}),
+		entry("/err/cgnat-in2out/Packets dropped", adapter.SimpleCounterStat{{100}}),
+	}
+
+	drops, err := cgnatLimitDrops(entries)
+	if err != nil {
+		t.Fatalf("cgnatLimitDrops: %v", err)
+	}
+	if drops.SessionLimitDrops != 8 || drops.PortExhaustionDrops != 11 {
+		t.Fatalf("drops = %+v, want 8/11", drops)
+	}
+
+	_, err = cgnatLimitDrops(entries[3:])
+	if !errors.Is(err, southbound.ErrCGNATLimitCountersUnavailable) {
+		t.Fatalf("err = %v, want ErrCGNATLimitCountersUnavailable", err)
+	}
+}
+
+// TestCGNATLimitCounterNames checks the counter names against the
+// shipped plugin.
+func TestCGNATLimitCounterNames(t *testing.T) {
+	plugin, err := os.ReadFile("../../../test-infra/vpp-plugins/osvbng_cgnat_plugin.so")
+	if err != nil {
+		t.Skipf("plugin not available: %v", err)
+	}
+	for _, name := range []string{cgnatErrSessionLimit, cgnatErrPortExhausted, "cgnat-in2out"} {
+		if !bytes.Contains(plugin, []byte(name+"\x00")) {
+			t.Errorf("plugin has no %q", name)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package vpp
+
+import (
+	"fmt"
+	"sync"
+
+	govppapi "go.fd.io/govpp/api"
+
+	"github.com/veesix-networks/osvbng/pkg/southbound"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/abf"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/fib_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/interface_types"
+	"github.com/veesix-networks/osvbng/pkg/vpp/binapi/ip_types"
+)
+
+var _ southbound.Steering = (*VPP)(nil)
+
+// retvalNoSuchEntry matches VNET_API_ERROR_NO_SUCH_ENTRY (-6), returned
+// when detaching an ABF policy the interface is no longer attached to,
+// as after the interface was deleted and its index reused.
+const retvalNoSuchEntry = -6
+
+// steeringPriority is the ABF attachment priority. An interface is bound
+// to at most one steering policy per family, so it only has to be
+// consistent.
+const steeringPriority = 100
+
+// steeringRegistry tracks the steering policies programmed as ABF
+// policies and the policy each interface is bound to. A binding is kept
+// while its policy is unknown or failing open, so the interface is
+// attached as soon as the policy is programmed again.
+type steeringRegistry struct {
+	mu sync.Mutex
+	// flushed is set once the ABF state left by an earlier run has been
+	// removed; restored sessions rebind through ApplySteering.
+	flushed  bool
+	nextID   uint32
+	policies map[string]*steeringPolicy
+	bound    map[uint32]string
+}
+
+// steeringPolicy is one steering policy as programmed: an ABF policy per
+// address family that has next hops, both matching on the same ACL.
+type steeringPolicy struct {
+	id       uint32
+	aclIndex uint32
+	// paths are indexed by family, IPv4 then IPv6.
+	paths [2][]fib_types.FibPath
+}
+
+// abfID is the ABF policy ID of the family's half of the policy.
+func (p *steeringPolicy) abfID(af int) uint32 {
+	return p.id*2 + uint32(af)
+}
+
+func newSteeringRegistry() *steeringRegistry {
+	return &steeringRegistry{
+		policies: make(map[string]*steeringPolicy),
+		bound:    make(map[uint32]string),
+	}
+}
+
+// SetSteeringPolicy programs the policy. ABF merges the paths of an add
+// into an existing policy and removes those of a delete, so next hops are
+// changed by adding the new ones before removing the stale ones, and
+// bound interfaces keep forwarding throughout. ABF cannot change a
+// policy's ACL; a new access list rebuilds the policy.
+func (v *VPP) SetSteeringPolicy(name, accessList string, nextHops []southbound.SteeringNextHop) error {
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := v.flushSteeringLocked(ch); err != nil {
+		return err
+	}
+	if len(nextHops) == 0 {
+		return v.withdrawSteeringLocked(ch, name)
+	}
+
+	aclIndex, ok := v.aclReg.lookup(accessList)
+	if !ok {
+		return fmt.Errorf("steering policy %q: unknown access list %q", name, accessList)
+	}
+
+	var paths [2][]fib_types.FibPath
+	for _, hop := range nextHops {
+		af, path, err := v.steeringPath(hop)
+		if err != nil {
+			return fmt.Errorf("steering policy %q: %w", name, err)
+		}
+		paths[af] = append(paths[af], path)
+	}
+
+	p := r.policies[name]
+	if p != nil && p.aclIndex != aclIndex {
+		if err := v.withdrawSteeringLocked(ch, name); err != nil {
+			return err
+		}
+		p = nil
+	}
+	if p == nil {
+		r.nextID++
+		p = &steeringPolicy{id: r.nextID, aclIndex: aclIndex}
+		r.policies[name] = p
+	}
+
+	for af := range paths {
+		if err := v.syncSteeringFamily(ch, name, p, af, paths[af]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// steeringACLUser returns a steering policy that matches on the ACL at
+// aclIndex.
+func (v *VPP) steeringACLUser(aclIndex uint32) (string, bool) {
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, p := range r.policies {
+		if p.aclIndex == aclIndex {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// DeleteSteeringPolicy withdraws the policy, keeping the bindings to it.
+func (v *VPP) DeleteSteeringPolicy(name string) error {
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := v.flushSteeringLocked(ch); err != nil {
+		return err
+	}
+	return v.withdrawSteeringLocked(ch, name)
+}
+
+// ApplySteering binds swIfIndex to the policy. The attach is re-sent
+// even when the binding is unchanged: a session torn down without
+// RemoveSteering leaves its index to be reused, and ABF reports an
+// attachment it already has as existing.
+func (v *VPP) ApplySteering(swIfIndex uint32, policy string) error {
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := v.flushSteeringLocked(ch); err != nil {
+		return err
+	}
+
+	if old, ok := r.bound[swIfIndex]; ok && old != policy {
+		if err := v.detachSteeringLocked(ch, swIfIndex, old); err != nil {
+			return err
+		}
+		delete(r.bound, swIfIndex)
+	}
+	if p := r.policies[policy]; p != nil {
+		for af := range p.paths {
+			if len(p.paths[af]) == 0 {
+				continue
+			}
+			if err := abfAttach(ch, true, p.abfID(af), swIfIndex, af, steeringPriority); err != nil {
+				return fmt.Errorf("steering policy %q: %w", policy, err)
+			}
+		}
+	}
+	r.bound[swIfIndex] = policy
+	return nil
+}
+
+// RemoveSteering unbinds swIfIndex from its steering policy.
+func (v *VPP) RemoveSteering(swIfIndex uint32) error {
+	r := v.steeringReg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name, ok := r.bound[swIfIndex]
+	if !ok {
+		return nil
+	}
+
+	ch, err := v.conn.NewAPIChannel()
+	if err != nil {
+		return fmt.Errorf("create API channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := v.detachSteeringLocked(ch, swIfIndex, name); err != nil {
+		return err
+	}
+	delete(r.bound, swIfIndex)
+	return nil
+}
+
+// syncSteeringFamily moves the family's half of the policy to want,
+// creating it and attaching the bound interfaces when it gains its first
+// path and detaching them and deleting it when it loses its last. Caller
+// holds steeringReg.mu.
+func (v *VPP) syncSteeringFamily(ch govppapi.Channel, name string, p *steeringPolicy, af int, want []fib_types.FibPath) error {
+	have := p.paths[af]
+	if len(want) == 0 {
+		if len(have) == 0 {
+			return nil
+		}
+		return v.deleteSteeringFamily(ch, name, p, af)
+	}
+
+	if added := missingPaths(want, have); len(added) > 0 {
+		if err := abfPolicyAddDel(ch, true, p.abfID(af), p.aclIndex, added); err != nil {
+			return fmt.Errorf("steering policy %q: %w", name, err)
+		}
+	}
+	if len(have) == 0 {
+		p.paths[af] = want
+		for swIfIndex, bound := range v.steeringReg.bound {
+			if bound != name {
+				continue
+			}
+			if err := abfAttach(ch, true, p.abfID(af), swIfIndex, af, steeringPriority); err != nil {
+				v.logger.Warn("Failed to attach steering policy", "policy", name, "sw_if_index", swIfIndex, "error", err)
+			}
+		}
+	}
+	if stale := missingPaths(have, want); len(stale) > 0 {
+		if err := abfPolicyAddDel(ch, false, p.abfID(af), p.aclIndex, stale); err != nil {
+			return fmt.Errorf("steering policy %q: %w", name, err)
+		}
+	}
+	p.paths[af] = want
+	return nil
+}
+
+// deleteSteeringFamily detaches the bound interfaces from the family's
+// half of the policy and deletes it. Caller holds steeringReg.mu.
+func (v *VPP) deleteSteeringFamily(ch govppapi.Channel, name string, p *steeringPolicy, af int) error {
+	for swIfIndex, bound := range v.steeringReg.bound {
+		if bound != name {
+			continue
+		}
+		if err := abfAttach(ch, false, p.abfID(af), swIfIndex, af, steeringPriority); err != nil {
+			return fmt.Errorf("steering policy %q: %w", name, err)
+		}
+	}
+	if err := abfPolicyAddDel(ch, false, p.abfID(af), p.aclIndex, p.paths[af]); err != nil {
+		return fmt.Errorf("steering policy %q: %w", name, err)
+	}
+	p.paths[af] = nil
+	return nil
+}
+
+// withdrawSteeringLocked removes the policy from the dataplane, leaving
+// its interfaces forwarding normally. Caller holds steeringReg.mu.
+func (v *VPP) withdrawSteeringLocked(ch govppapi.Channel, name string) error {
+	p := v.steeringReg.policies[name]
+	if p == nil {
+		return nil
+	}
+	for af := range p.paths {
+		if len(p.paths[af]) == 0 {
+			continue
+		}
+		if err := v.deleteSteeringFamily(ch, name, p, af); err != nil {
+			return err
+		}
+	}
+	delete(v.steeringReg.policies, name)
+	return nil
+}
+
+// detachSteeringLocked detaches swIfIndex from the policy. Caller holds
+// steeringReg.mu.
+func (v *VPP) detachSteeringLocked(ch govppapi.Channel, swIfIndex uint32, name string) error {
+	p := v.steeringReg.policies[name]
+	if p == nil {
+		return nil
+	}
+	for af := range p.paths {
+		if len(p.paths[af]) == 0 {
+			continue
+		}
+		if err := abfAttach(ch, false, p.abfID(af), swIfIndex, af, steeringPriority); err != nil {
+			return fmt.Errorf("steering policy %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// flushSteeringLocked removes the ABF attachments and policies an
+// earlier run left in the dataplane. Only steering and FlowSpec program
+// ABF, both with IDs from nextID, which start again from one. Caller
+// holds steeringReg.mu.
+func (v *VPP) flushSteeringLocked(ch govppapi.Channel) error {
+	if v.steeringReg.flushed {
+		return nil
+	}
+
+	var attachments []abf.AbfItfAttach
+	multi := ch.SendMultiRequest(&abf.AbfItfAttachDump{})
+	for {
+		d := &abf.AbfItfAttachDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("receive abf attachments: %w", err)
+		}
+		attachments = append(attachments, d.Attach)
+	}
+
+	var policies []abf.AbfPolicy
+	multi = ch.SendMultiRequest(&abf.AbfPolicyDump{})
+	for {
+		d := &abf.AbfPolicyDetails{}
+		stop, err := multi.ReceiveReply(d)
+		if stop {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("receive abf policies: %w", err)
+		}
+		policies = append(policies, d.Policy)
+	}
+
+	for _, a := range attachments {
+		af := 0
+		if a.IsIPv6 {
+			af = 1
+		}
+		if err := abfAttach(ch, false, a.PolicyID, uint32(a.SwIfIndex), af, a.Priority); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+	}
+	for _, p := range policies {
+		if err := abfPolicyAddDel(ch, false, p.PolicyID, p.ACLIndex, p.Paths); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+	}
+
+	if len(attachments) > 0 || len(policies) > 0 {
+		v.logger.Info("Removed stale steering state", "policies", len(policies), "attachments", len(attachments))
+	}
+	v.steeringReg.flushed = true
+	return nil
+}
+
+// steeringPath builds the FIB path for a next hop and reports its
+// family, resolving its VRF to a table.
+func (v *VPP) steeringPath(hop southbound.SteeringNextHop) (int, fib_types.FibPath, error) {
+	af, proto := 0, fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if hop.Address.To4() == nil {
+		af, proto = 1, fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+
+	var tableID uint32
+	if hop.VRF != "" {
+		if v.vrfResolver == nil {
+			return 0, fib_types.FibPath{}, fmt.Errorf("VRF resolver not configured")
+		}
+		id, _, _, err := v.vrfResolver(hop.VRF)
+		if err != nil {
+			return 0, fib_types.FibPath{}, fmt.Errorf("resolve VRF %q: %w", hop.VRF, err)
+		}
+		tableID = id
+	}
+
+	return af, fib_types.FibPath{
+		SwIfIndex: ^uint32(0),
+		TableID:   tableID,
+		Weight:    1,
+		Proto:     proto,
+		Nh:        fib_types.FibPathNh{Address: ip_types.NewAddress(hop.Address).Un},
+	}, nil
+}
+
+// missingPaths returns the paths in a that are not in b.
+func missingPaths(a, b []fib_types.FibPath) []fib_types.FibPath {
+	var out []fib_types.FibPath
+	for _, p := range a {
+		found := false
+		for _, q := range b {
+			if p.TableID == q.TableID && p.Proto == q.Proto && p.Nh.Address == q.Nh.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func abfPolicyAddDel(ch govppapi.Channel, isAdd bool, policyID, aclIndex uint32, paths []fib_types.FibPath) error {
+	req := &abf.AbfPolicyAddDel{
+		IsAdd: isAdd,
+		Policy: abf.AbfPolicy{
+			PolicyID: policyID,
+			ACLIndex: aclIndex,
+			Paths:    paths,
+		},
+	}
+	reply := &abf.AbfPolicyAddDelReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("abf_policy_add_del %d: %w", policyID, err)
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("abf_policy_add_del %d retval=%d", policyID, reply.Retval)
+	}
+	return nil
+}
+
+// abfAttach attaches or detaches a policy, treating an attachment that
+// already exists, or is already gone, as done. Of the policies attached
+// to an interface, the one with the lowest priority is matched first.
+func abfAttach(ch govppapi.Channel, isAdd bool, policyID, swIfIndex uint32, af int, priority uint32) error {
+	req := &abf.AbfItfAttachAddDel{
+		IsAdd: isAdd,
+		Attach: abf.AbfItfAttach{
+			PolicyID:  policyID,
+			SwIfIndex: interface_types.InterfaceIndex(swIfIndex),
+			Priority:  priority,
+			IsIPv6:    af == 1,
+		},
+	}
+	reply := &abf.AbfItfAttachAddDelReply{}
+	if err := ch.SendRequest(req).ReceiveReply(reply); err != nil {
+		return fmt.Errorf("abf_itf_attach_add_del %d on %d: %w", policyID, swIfIndex, err)
+	}
+	switch {
+	case reply.Retval == 0:
+	case isAdd && reply.Retval == retvalValueExist:
+	case !isAdd && reply.Retval == retvalNoSuchEntry:
+	default:
+		return fmt.Errorf("abf_itf_attach_add_del %d on %d retval=%d", policyID, swIfIndex, reply.Retval)
+	}
+	return nil
+}
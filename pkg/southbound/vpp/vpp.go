@@ -32,7 +32,11 @@ type VPP struct {
 	policerMu    sync.Mutex
 	schedulerIfs map[uint32]bool
 	schedulerMu  sync.Mutex
+	qosClasses   map[uint32]*qosClassBinding
+	qosClassMu   sync.Mutex
 	aclReg       *aclRegistry
+	steeringReg  *steeringRegistry
+	markingReg   *markingRegistry
 	numRxQueues  int
 
 	tunnelDecapMu     sync.Mutex
@@ -45,6 +49,13 @@ type VPP struct {
 
 	pwMu       sync.Mutex
 	pwBindings map[string]pwBinding
+
+	blackholeMu    sync.Mutex
+	blackholeSrc   uint8
+	blackholeSrcOK bool
+
+	flowspecMu sync.Mutex
+	flowspec   map[uint32]*flowspecBinding
 }
 
 type VPPConfig struct {
@@ -94,9 +105,13 @@ func NewVPP(cfg VPPConfig) (*VPP, error) {
 		statsClient:  statsClient,
 		policerNames: make(map[uint32][2]string),
 		schedulerIfs: make(map[uint32]bool),
+		qosClasses:   make(map[uint32]*qosClassBinding),
 		aclReg:       newACLRegistry(),
+		steeringReg:  newSteeringRegistry(),
+		markingReg:   newMarkingRegistry(),
 		numRxQueues:  cfg.NumRxQueues,
 		pwBindings:   make(map[string]pwBinding),
+		flowspec:     make(map[uint32]*flowspecBinding),
 	}
 
 	if err := v.LoadInterfaces(); err != nil {
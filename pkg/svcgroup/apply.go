@@ -25,15 +25,24 @@ type PolicyApplier interface {
 	EnableSourceVerify(swIfIndex uint32, strict bool) error
 	DisableSourceVerify(swIfIndex uint32) error
 
+	ApplySteering(swIfIndex uint32, policy string) error
+	RemoveSteering(swIfIndex uint32) error
+
 	ApplyQoS(swIfIndex uint32, ingress, egress *qos.Policy) error
 	RemoveQoS(swIfIndex uint32) error
 	ApplyScheduler(swIfIndex uint32, rateKbps uint32, cfg *qos.SchedulerConfig) error
 	RemoveScheduler(swIfIndex uint32) error
+	ApplyQoSClasses(swIfIndex uint32, ingress, egress *qos.Policy) error
+	RemoveQoSClasses(swIfIndex uint32) error
+	ApplyMarking(swIfIndex uint32, m qos.Marking) error
+	RemoveMarking(swIfIndex uint32) error
 }
 
 // ApplyToSession programs every per-session policy binding implied by sg
-// onto swIfIndex: uRPF, ingress ACL, egress ACL, ingress QoS / scheduler /
-// egress QoS. Each underlying southbound call is idempotent — re-applying
+// onto swIfIndex: uRPF, ingress ACL, egress ACL, the steering policy,
+// the DSCP and 802.1p marking, ingress QoS / scheduler / egress QoS,
+// then the policies' traffic classes, which carry an ingress remark.
+// Each underlying southbound call is idempotent — re-applying
 // the same configuration is a no-op — so this is safe to invoke both at
 // fresh post-auth bring-up AND during opdb restore, where the dataplane
 // state may already match.
@@ -41,9 +50,11 @@ type PolicyApplier interface {
 // qosPolicies is the runningConfig's qos.Policy registry; service-group
 // references to QoS policy names are resolved against it.
 //
-// Policy programming proceeds in this order: uRPF, ACLs, then QoS. ACL
-// resolution failures are surfaced as errors so the caller can decide
-// whether to abort bring-up; QoS failures are logged but do not abort,
+// Policy programming proceeds in this order: uRPF, ACLs, steering, then
+// marking and QoS. ACL resolution failures are surfaced as errors so the
+// caller can decide whether to abort bring-up; steering, marking and QoS
+// failures are logged
+// but do not abort, steering failing open like an unreachable next hop,
 // matching the prior best-effort behaviour of the subscriber-component
 // activateSession path this code lifted from.
 func ApplyToSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, qosPolicies map[string]*qos.Policy) error {
@@ -75,17 +86,30 @@ func ApplyToSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, qosPoli
 		}
 	}
 
-	var ingress, egress *qos.Policy
-	if sg.QoSIngress != "" {
-		ingress = qosPolicies[sg.QoSIngress]
+	if sg.SteeringPolicy != "" {
+		if err := sb.ApplySteering(swIfIndex, sg.SteeringPolicy); err != nil {
+			log.Warn("Failed to apply steering policy",
+				"error", err, "sw_if_index", swIfIndex, "service_group", sg.Name,
+				"steering_policy", sg.SteeringPolicy)
+		}
 	}
-	if sg.QoSEgress != "" {
-		egress = qosPolicies[sg.QoSEgress]
+
+	if !sg.Marking.IsZero() {
+		if err := sb.ApplyMarking(swIfIndex, sg.Marking); err != nil {
+			log.Warn("Failed to apply marking",
+				"error", err, "sw_if_index", swIfIndex, "service_group", sg.Name)
+		}
 	}
+
+	ingress, egress := sg.Policies(qosPolicies)
 	if ingress == nil && egress == nil {
+		applyQoSClasses(sb, swIfIndex, sg, nil, nil)
 		return nil
 	}
 
+	upLimit, downLimit := sg.LineLimits()
+	ingress = capPolicy(ingress, upLimit)
+
 	if egress != nil && egress.Scheduler != nil {
 		downloadRate := egress.CIR
 		if sg.DownloadRate > 0 {
@@ -93,6 +117,9 @@ func ApplyToSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, qosPoli
 			// the scheduler takes kbps.
 			downloadRate = uint32(sg.DownloadRate / 1000)
 		}
+		if downLimit > 0 && (downloadRate == 0 || downloadRate > downLimit) {
+			downloadRate = downLimit
+		}
 		if downloadRate > 0 {
 			if err := sb.ApplyScheduler(swIfIndex, downloadRate, egress.Scheduler); err != nil {
 				log.Warn("Failed to apply scheduler",
@@ -112,9 +139,11 @@ func ApplyToSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, qosPoli
 					"error", err, "sw_if_index", swIfIndex, "service_group", sg.Name)
 			}
 		}
+		applyQoSClasses(sb, swIfIndex, sg, ingress, egress)
 		return nil
 	}
 
+	egress = capPolicy(egress, downLimit)
 	if err := sb.ApplyQoS(swIfIndex, ingress, egress); err != nil {
 		log.Warn("Failed to apply QoS",
 			"error", err, "sw_if_index", swIfIndex, "service_group", sg.Name)
@@ -124,24 +153,151 @@ func ApplyToSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, qosPoli
 		"sw_if_index", swIfIndex,
 		"service_group", sg.Name,
 		"ingress_policy", sg.QoSIngress,
-		"egress_policy", sg.QoSEgress)
+		"egress_policy", sg.QoSEgress,
+		"line_limit_up", upLimit,
+		"line_limit_down", downLimit)
+	applyQoSClasses(sb, swIfIndex, sg, ingress, egress)
 	return nil
 }
 
+// applyQoSClasses programs the policies' traffic classes on top of the
+// subscriber rate, and the classes of an ingress remark. Like the rest
+// of QoS it is best effort: a session whose classes fail still gets its
+// overall rate.
+func applyQoSClasses(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup, ingress, egress *qos.Policy) {
+	if (ingress == nil || len(ingress.Classes) == 0) && (egress == nil || len(egress.Classes) == 0) && !sg.Marking.Remarks() {
+		return
+	}
+	if err := sb.ApplyQoSClasses(swIfIndex, ingress, egress); err != nil {
+		logger.Get(logger.SvcGroup).Warn("Failed to apply QoS classes",
+			"error", err, "sw_if_index", swIfIndex, "service_group", sg.Name)
+	}
+}
+
+// LineLimits returns the rates in kbps the access line holds each
+// direction to: the line's actual rate times the group's overhead
+// factor. A direction is 0, unlimited, when the group does not shape to
+// the line or its rate was not reported.
+func (sg ServiceGroup) LineLimits() (up, down uint32) {
+	limit := func(kbps uint32) uint32 {
+		if sg.LineRateFactor == 0 || kbps == 0 {
+			return 0
+		}
+		return uint32(float64(kbps) * sg.LineRateFactor)
+	}
+	return limit(sg.LineRateUp), limit(sg.LineRateDown)
+}
+
+// capPolicy returns p with its rates held to limit kbps, copying it
+// rather than changing the shared policy. A limit of 0 leaves p as is.
+func capPolicy(p *qos.Policy, limit uint32) *qos.Policy {
+	if p == nil || limit == 0 || (p.CIR <= limit && p.EIR <= limit) {
+		return p
+	}
+	capped := *p
+	if capped.CIR > limit {
+		capped.CIR = limit
+	}
+	if capped.EIR > limit {
+		capped.EIR = limit
+	}
+	return &capped
+}
+
+// Policies resolves the group's QoS policy names against qosPolicies.
+// A policy with a schedule that was active when the group was resolved
+// is swapped for that schedule's policy.
+func (sg ServiceGroup) Policies(qosPolicies map[string]*qos.Policy) (ingress, egress *qos.Policy) {
+	return sg.scheduledPolicy(qosPolicies, sg.QoSIngress), sg.scheduledPolicy(qosPolicies, sg.QoSEgress)
+}
+
+func (sg ServiceGroup) scheduledPolicy(qosPolicies map[string]*qos.Policy, name string) *qos.Policy {
+	if name == "" {
+		return nil
+	}
+	p := qosPolicies[name]
+	if p == nil {
+		return nil
+	}
+	for _, s := range p.Schedules {
+		if alt := qosPolicies[s.Policy]; alt != nil && sg.ScheduleActive(s.Schedule) {
+			return alt
+		}
+	}
+	return p
+}
+
+// SameBindings reports whether a and b program the same policy onto a
+// session, so a schedule boundary or a line rate change can skip the
+// sessions it leaves as they were.
+func SameBindings(a, b ServiceGroup, qosPolicies map[string]*qos.Policy) bool {
+	if a.URPF != b.URPF || a.ACLIngress != b.ACLIngress || a.ACLEgress != b.ACLEgress ||
+		a.SteeringPolicy != b.SteeringPolicy || a.Marking != b.Marking ||
+		a.UploadRate != b.UploadRate || a.DownloadRate != b.DownloadRate {
+		return false
+	}
+	aUp, aDown := a.LineLimits()
+	bUp, bDown := b.LineLimits()
+	if aUp != bUp || aDown != bDown {
+		return false
+	}
+	aIn, aOut := a.Policies(qosPolicies)
+	bIn, bOut := b.Policies(qosPolicies)
+	return aIn == bIn && aOut == bOut
+}
+
+// ReapplyToSession moves a live session from the bindings of from to
+// those of to. QoS and marking are torn down and rebuilt, since the
+// policer, scheduler and classes of the two may differ in shape; ACLs, steering and uRPF
+// are replaced in place and only removed where to has none, so the
+// session is never left unfiltered in between.
+func ReapplyToSession(sb PolicyApplier, swIfIndex uint32, from, to ServiceGroup, qosPolicies map[string]*qos.Policy) error {
+	log := logger.Get(logger.SvcGroup)
+
+	removeQoS(sb, swIfIndex)
+
+	if from.ACLIngress != "" && to.ACLIngress == "" {
+		if err := sb.RemoveIngressACL(swIfIndex); err != nil {
+			log.Debug("RemoveIngressACL error during reapply",
+				"error", err, "sw_if_index", swIfIndex)
+		}
+	}
+	if from.ACLEgress != "" && to.ACLEgress == "" {
+		if err := sb.RemoveEgressACL(swIfIndex); err != nil {
+			log.Debug("RemoveEgressACL error during reapply",
+				"error", err, "sw_if_index", swIfIndex)
+		}
+	}
+	if from.SteeringPolicy != "" && to.SteeringPolicy == "" {
+		if err := sb.RemoveSteering(swIfIndex); err != nil {
+			log.Debug("RemoveSteering error during reapply",
+				"error", err, "sw_if_index", swIfIndex)
+		}
+	}
+	if from.URPF != "" && from.URPF != "off" && (to.URPF == "" || to.URPF == "off") {
+		if err := sb.DisableSourceVerify(swIfIndex); err != nil {
+			log.Debug("DisableSourceVerify error during reapply",
+				"error", err, "sw_if_index", swIfIndex)
+		}
+	}
+
+	return ApplyToSession(sb, swIfIndex, to, qosPolicies)
+}
+
 // ReverseFromSession unwinds every binding ApplyToSession installed for sg
-// in inverse order: QoS / scheduler, then ACLs, then uRPF. Best-effort:
+// in inverse order: QoS / scheduler, steering, then ACLs, then uRPF. Best-effort:
 // individual step failures are logged but do not abort the rest of the
 // teardown, and "already removed" is treated as success.
 func ReverseFromSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup) {
 	log := logger.Get(logger.SvcGroup)
 
-	if err := sb.RemoveScheduler(swIfIndex); err != nil {
-		log.Debug("RemoveScheduler error during teardown",
-			"error", err, "sw_if_index", swIfIndex)
-	}
-	if err := sb.RemoveQoS(swIfIndex); err != nil {
-		log.Debug("RemoveQoS error during teardown",
-			"error", err, "sw_if_index", swIfIndex)
+	removeQoS(sb, swIfIndex)
+
+	if sg.SteeringPolicy != "" {
+		if err := sb.RemoveSteering(swIfIndex); err != nil {
+			log.Debug("RemoveSteering error during teardown",
+				"error", err, "sw_if_index", swIfIndex)
+		}
 	}
 
 	if sg.ACLIngress != "" {
@@ -164,3 +320,26 @@ func ReverseFromSession(sb PolicyApplier, swIfIndex uint32, sg ServiceGroup) {
 		}
 	}
 }
+
+// removeQoS removes the classes, scheduler, policers and marking of a
+// session.
+func removeQoS(sb PolicyApplier, swIfIndex uint32) {
+	log := logger.Get(logger.SvcGroup)
+
+	if err := sb.RemoveQoSClasses(swIfIndex); err != nil {
+		log.Debug("RemoveQoSClasses error during teardown",
+			"error", err, "sw_if_index", swIfIndex)
+	}
+	if err := sb.RemoveScheduler(swIfIndex); err != nil {
+		log.Debug("RemoveScheduler error during teardown",
+			"error", err, "sw_if_index", swIfIndex)
+	}
+	if err := sb.RemoveQoS(swIfIndex); err != nil {
+		log.Debug("RemoveQoS error during teardown",
+			"error", err, "sw_if_index", swIfIndex)
+	}
+	if err := sb.RemoveMarking(swIfIndex); err != nil {
+		log.Debug("RemoveMarking error during teardown",
+			"error", err, "sw_if_index", swIfIndex)
+	}
+}
@@ -11,19 +11,71 @@ import (
 )
 
 type fakeApplier struct {
+	aclRemoved bool
+	qosRemoved bool
+
 	schedulerCalls int
 	schedulerRate  uint32
+
+	qosIngress *qos.Policy
+	qosEgress  *qos.Policy
+
+	classCalls   int
+	classIngress *qos.Policy
+	classEgress  *qos.Policy
+	classRemoved bool
+
+	steering        string
+	steeringRemoved bool
+
+	marking        qos.Marking
+	markingRemoved bool
+}
+
+func (f *fakeApplier) ApplyIngressACL(uint32, string) error  { return nil }
+func (f *fakeApplier) ApplyEgressACL(uint32, string) error   { return nil }
+func (f *fakeApplier) RemoveIngressACL(uint32) error         { f.aclRemoved = true; return nil }
+func (f *fakeApplier) RemoveEgressACL(uint32) error          { return nil }
+func (f *fakeApplier) EnableSourceVerify(uint32, bool) error { return nil }
+func (f *fakeApplier) DisableSourceVerify(uint32) error      { return nil }
+func (f *fakeApplier) RemoveQoS(uint32) error                { f.qosRemoved = true; return nil }
+func (f *fakeApplier) RemoveScheduler(uint32) error          { return nil }
+
+func (f *fakeApplier) ApplySteering(_ uint32, policy string) error {
+	f.steering = policy
+	return nil
 }
 
-func (f *fakeApplier) ApplyIngressACL(uint32, string) error            { return nil }
-func (f *fakeApplier) ApplyEgressACL(uint32, string) error             { return nil }
-func (f *fakeApplier) RemoveIngressACL(uint32) error                   { return nil }
-func (f *fakeApplier) RemoveEgressACL(uint32) error                    { return nil }
-func (f *fakeApplier) EnableSourceVerify(uint32, bool) error           { return nil }
-func (f *fakeApplier) DisableSourceVerify(uint32) error                { return nil }
-func (f *fakeApplier) ApplyQoS(uint32, *qos.Policy, *qos.Policy) error { return nil }
-func (f *fakeApplier) RemoveQoS(uint32) error                          { return nil }
-func (f *fakeApplier) RemoveScheduler(uint32) error                    { return nil }
+func (f *fakeApplier) RemoveSteering(uint32) error {
+	f.steering, f.steeringRemoved = "", true
+	return nil
+}
+
+func (f *fakeApplier) ApplyQoS(_ uint32, ingress, egress *qos.Policy) error {
+	f.qosIngress, f.qosEgress = ingress, egress
+	return nil
+}
+
+func (f *fakeApplier) ApplyQoSClasses(_ uint32, ingress, egress *qos.Policy) error {
+	f.classCalls++
+	f.classIngress, f.classEgress = ingress, egress
+	return nil
+}
+
+func (f *fakeApplier) RemoveQoSClasses(uint32) error {
+	f.classRemoved = true
+	return nil
+}
+
+func (f *fakeApplier) ApplyMarking(_ uint32, m qos.Marking) error {
+	f.marking = m
+	return nil
+}
+
+func (f *fakeApplier) RemoveMarking(uint32) error {
+	f.marking, f.markingRemoved = qos.Marking{}, true
+	return nil
+}
 
 func (f *fakeApplier) ApplyScheduler(_ uint32, rateKbps uint32, _ *qos.SchedulerConfig) error {
 	f.schedulerCalls++
@@ -76,3 +128,197 @@ func TestApplyToSessionSchedulerRateFallsBackToCIR(t *testing.T) {
 		t.Errorf("scheduler rate = %d kbps, want CIR passthrough 100000", sb.schedulerRate)
 	}
 }
+
+func TestApplyToSessionCapsAtLineRate(t *testing.T) {
+	policies := map[string]*qos.Policy{
+		"plan":  {CIR: 100_000, EIR: 120_000},
+		"up":    {CIR: 20_000},
+		"cake":  {CIR: 100_000, Scheduler: &qos.SchedulerConfig{}},
+		"small": {CIR: 8_000},
+	}
+	line := ServiceGroup{LineRateFactor: 0.9, LineRateUp: 10_000, LineRateDown: 50_000}
+
+	sb := &fakeApplier{}
+	sg := line
+	sg.QoSIngress, sg.QoSEgress = "up", "plan"
+	if err := ApplyToSession(sb, 1, sg, policies); err != nil {
+		t.Fatal(err)
+	}
+	if sb.qosIngress.CIR != 9_000 || sb.qosEgress.CIR != 45_000 || sb.qosEgress.EIR != 45_000 {
+		t.Fatalf("policers = %+v / %+v, want capped at 9000 / 45000", sb.qosIngress, sb.qosEgress)
+	}
+	if policies["plan"].CIR != 100_000 || policies["up"].CIR != 20_000 {
+		t.Fatal("shared policy modified")
+	}
+
+	sb = &fakeApplier{}
+	sg = line
+	sg.QoSEgress = "cake"
+	if err := ApplyToSession(sb, 1, sg, policies); err != nil {
+		t.Fatal(err)
+	}
+	if sb.schedulerRate != 45_000 {
+		t.Fatalf("scheduler rate = %d, want 45000", sb.schedulerRate)
+	}
+
+	// The plan is below the line: min(plan, line) is the plan.
+	sb = &fakeApplier{}
+	sg = line
+	sg.QoSEgress = "small"
+	if err := ApplyToSession(sb, 1, sg, policies); err != nil {
+		t.Fatal(err)
+	}
+	if sb.qosEgress != policies["small"] {
+		t.Fatalf("egress = %+v, want the plan unchanged", sb.qosEgress)
+	}
+
+	// Line rates without line-rate shaping change nothing.
+	sb = &fakeApplier{}
+	sg = ServiceGroup{QoSEgress: "plan", LineRateDown: 50_000}
+	if err := ApplyToSession(sb, 1, sg, policies); err != nil {
+		t.Fatal(err)
+	}
+	if sb.qosEgress != policies["plan"] {
+		t.Fatalf("egress = %+v, want the plan unchanged", sb.qosEgress)
+	}
+}
+
+func TestSameBindingsComparesLineLimits(t *testing.T) {
+	a := ServiceGroup{QoSEgress: "plan", LineRateFactor: 1, LineRateDown: 50_000}
+	b := a
+	b.LineRateDown = 40_000
+	if SameBindings(a, b, nil) {
+		t.Fatal("different line limits reported the same")
+	}
+	a.LineRateFactor, b.LineRateFactor = 0, 0
+	if !SameBindings(a, b, nil) {
+		t.Fatal("line rates without shaping reported different")
+	}
+}
+
+func TestApplyToSessionQoSClasses(t *testing.T) {
+	voice := qos.Class{Name: "voice", Match: qos.ClassMatch{DSCP: []string{"ef"}}}
+	policies := map[string]*qos.Policy{
+		"plain": {CIR: 100_000},
+		"up":    {CIR: 20_000, Classes: []qos.Class{voice}},
+		"down":  {CIR: 100_000, Scheduler: &qos.SchedulerConfig{TinMode: "diffserv4"}, Classes: []qos.Class{voice}},
+	}
+
+	sb := &fakeApplier{}
+	if err := ApplyToSession(sb, 1, ServiceGroup{QoSIngress: "plain", QoSEgress: "plain"}, policies); err != nil {
+		t.Fatalf("ApplyToSession: %v", err)
+	}
+	if sb.classCalls != 0 {
+		t.Fatalf("ApplyQoSClasses called for policies without classes")
+	}
+
+	sb = &fakeApplier{}
+	if err := ApplyToSession(sb, 1, ServiceGroup{QoSIngress: "up", QoSEgress: "down"}, policies); err != nil {
+		t.Fatalf("ApplyToSession: %v", err)
+	}
+	if sb.classCalls != 1 || sb.classIngress != policies["up"] || sb.classEgress != policies["down"] {
+		t.Fatalf("ApplyQoSClasses calls = %d, ingress = %v, egress = %v", sb.classCalls, sb.classIngress, sb.classEgress)
+	}
+
+	ReverseFromSession(sb, 1, ServiceGroup{QoSIngress: "up", QoSEgress: "down"})
+	if !sb.classRemoved {
+		t.Fatal("ReverseFromSession did not remove the classes")
+	}
+}
+
+func TestPoliciesFollowActiveSchedule(t *testing.T) {
+	policies := map[string]*qos.Policy{
+		"plan-100m": {CIR: 100_000, Schedules: []qos.PolicySchedule{{Schedule: "night", Policy: "plan-1g"}}},
+		"plan-1g":   {CIR: 1_000_000},
+	}
+	day := ServiceGroup{QoSEgress: "plan-100m"}
+	night := ServiceGroup{QoSEgress: "plan-100m", Schedules: []string{"night"}}
+
+	if _, egress := day.Policies(policies); egress != policies["plan-100m"] {
+		t.Fatalf("day egress = %v", egress)
+	}
+	if _, egress := night.Policies(policies); egress != policies["plan-1g"] {
+		t.Fatalf("night egress = %v", egress)
+	}
+	if SameBindings(day, night, policies) {
+		t.Fatal("SameBindings = true across a policy swap")
+	}
+	if !SameBindings(day, ServiceGroup{QoSEgress: "plan-100m", Schedules: []string{"weekend"}}, policies) {
+		t.Fatal("SameBindings = false for a schedule the policy does not follow")
+	}
+}
+
+func TestReapplyToSession(t *testing.T) {
+	policies := map[string]*qos.Policy{"plan": {CIR: 100_000}}
+	sb := &fakeApplier{}
+	from := ServiceGroup{ACLIngress: "day", QoSEgress: "plan"}
+
+	if err := ReapplyToSession(sb, 1, from, ServiceGroup{ACLIngress: "night", QoSEgress: "plan"}, policies); err != nil {
+		t.Fatal(err)
+	}
+	if !sb.qosRemoved || sb.aclRemoved {
+		t.Fatalf("replacing an ACL: qos removed %v, acl removed %v", sb.qosRemoved, sb.aclRemoved)
+	}
+
+	sb = &fakeApplier{}
+	if err := ReapplyToSession(sb, 1, from, ServiceGroup{QoSEgress: "plan"}, policies); err != nil {
+		t.Fatal(err)
+	}
+	if !sb.aclRemoved {
+		t.Fatal("ACL the new bindings drop was left on the session")
+	}
+}
+
+func TestSteeringAppliedAndRemoved(t *testing.T) {
+	sb := &fakeApplier{}
+	steered := ServiceGroup{Name: "res", SteeringPolicy: "dpi"}
+	if err := ApplyToSession(sb, 1, steered, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sb.steering != "dpi" {
+		t.Fatalf("steering = %q, want dpi", sb.steering)
+	}
+
+	plain := ServiceGroup{Name: "res"}
+	if SameBindings(steered, plain, nil) {
+		t.Fatal("SameBindings ignored the steering policy")
+	}
+	if err := ReapplyToSession(sb, 1, steered, plain, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !sb.steeringRemoved || sb.steering != "" {
+		t.Fatalf("steering not removed: %+v", sb)
+	}
+}
+
+// A session that remarks gets classes even with no QoS policy: they are
+// what rewrites its DSCP.
+func TestApplyToSessionMarking(t *testing.T) {
+	sb := &fakeApplier{}
+	sg := ServiceGroup{
+		Name:    "test",
+		Marking: qos.Marking{Ingress: qos.MarkingBleach, EgressPCPMap: "access"},
+	}
+	if err := ApplyToSession(sb, 1, sg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sb.marking != sg.Marking {
+		t.Errorf("marking = %+v, want %+v", sb.marking, sg.Marking)
+	}
+	if sb.classCalls != 1 || sb.classIngress != nil {
+		t.Errorf("class calls = %d with ingress %v, want one with none", sb.classCalls, sb.classIngress)
+	}
+
+	to := sg
+	to.Marking = qos.Marking{EgressPCPMap: "access"}
+	if SameBindings(sg, to, nil) {
+		t.Fatal("different marking reported as the same bindings")
+	}
+	sb = &fakeApplier{}
+	if err := ReapplyToSession(sb, 1, sg, to, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !sb.markingRemoved || sb.marking != to.Marking || sb.classCalls != 0 {
+		t.Errorf("after reapply: removed %v, marking %+v, class calls %d", sb.markingRemoved, sb.marking, sb.classCalls)
+	}
+}
@@ -3,10 +3,15 @@ package svcgroup
 import (
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
 	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
 	"github.com/veesix-networks/osvbng/pkg/logger"
 )
@@ -22,11 +27,22 @@ type ServiceGroup struct {
 	QoSEgress    string
 	UploadRate   uint64
 	DownloadRate uint64
-	Pool         string
-	IANAPool     string
-	PDPool       string
-	IPv4Profile  string
-	IPv6Profile  string
+	// LineRateFactor is the overhead factor QoS is capped at the line
+	// rate with, 0 when the group does not shape to the line.
+	// LineRateUp and LineRateDown are the line's actual rates in kbps.
+	LineRateFactor float64
+	LineRateUp     uint32
+	LineRateDown   uint32
+	Pool           string
+	IANAPool       string
+	PDPool         string
+	IPv4Profile    string
+	IPv6Profile    string
+	SteeringPolicy string
+	Marking        qos.Marking
+	// Schedules are the schedules active when the group was resolved,
+	// sorted. ApplyToSession follows QoS policy schedules among them.
+	Schedules []string
 }
 
 func (r ServiceGroup) LogAttrs() []slog.Attr {
@@ -61,6 +77,15 @@ func (r ServiceGroup) LogAttrs() []slog.Attr {
 	if r.DownloadRate != 0 {
 		attrs = append(attrs, slog.Uint64("download_rate", r.DownloadRate))
 	}
+	if r.LineRateFactor != 0 {
+		attrs = append(attrs, slog.Float64("line_rate_factor", r.LineRateFactor))
+	}
+	if r.LineRateUp != 0 {
+		attrs = append(attrs, slog.Uint64("line_rate_up", uint64(r.LineRateUp)))
+	}
+	if r.LineRateDown != 0 {
+		attrs = append(attrs, slog.Uint64("line_rate_down", uint64(r.LineRateDown)))
+	}
 	if r.Pool != "" {
 		attrs = append(attrs, slog.String("pool", r.Pool))
 	}
@@ -76,19 +101,36 @@ func (r ServiceGroup) LogAttrs() []slog.Attr {
 	if r.IPv6Profile != "" {
 		attrs = append(attrs, slog.String("ipv6_profile", r.IPv6Profile))
 	}
+	if r.SteeringPolicy != "" {
+		attrs = append(attrs, slog.String("steering_policy", r.SteeringPolicy))
+	}
+	if r.Marking.Ingress != "" {
+		attrs = append(attrs, slog.String("marking_ingress", r.Marking.Ingress))
+	}
+	if r.Marking.IngressMap != "" {
+		attrs = append(attrs, slog.String("marking_ingress_map", r.Marking.IngressMap))
+	}
+	if r.Marking.EgressPCPMap != "" {
+		attrs = append(attrs, slog.String("marking_egress_pcp_map", r.Marking.EgressPCPMap))
+	}
+	if len(r.Schedules) > 0 {
+		attrs = append(attrs, slog.String("schedules", strings.Join(r.Schedules, ",")))
+	}
 	return attrs
 }
 
 type Resolver struct {
-	mu     sync.RWMutex
-	groups map[string]*servicegroup.Config
-	logger *logger.Logger
+	mu        sync.RWMutex
+	groups    map[string]*servicegroup.Config
+	schedules map[string]*schedule.Schedule
+	logger    *logger.Logger
 }
 
 func New() *Resolver {
 	return &Resolver{
-		groups: make(map[string]*servicegroup.Config),
-		logger: logger.Get(logger.SvcGroup),
+		groups:    make(map[string]*servicegroup.Config),
+		schedules: make(map[string]*schedule.Schedule),
+		logger:    logger.Get(logger.SvcGroup),
 	}
 }
 
@@ -126,13 +168,81 @@ func (r *Resolver) GetAll() map[string]*servicegroup.Config {
 	return result
 }
 
-// Resolve performs three-layer merge:
+func (r *Resolver) SetSchedule(name string, s *schedule.Schedule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schedules[name] = s
+	r.logger.Info("Set schedule", "name", name, "timezone", s.Timezone)
+}
+
+func (r *Resolver) DeleteSchedule(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.schedules, name)
+	r.logger.Info("Deleted schedule", "name", name)
+}
+
+// ActiveSchedules returns the names of the schedules active at t, sorted.
+func (r *Resolver) ActiveSchedules(t time.Time) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.activeLocked(t)
+}
+
+// NextTransition returns the first time after t at which any schedule
+// opens or closes, or the zero time if none ever will.
+func (r *Resolver) NextTransition(t time.Time) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var next time.Time
+	for _, s := range r.schedules {
+		if n := s.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+func (r *Resolver) activeLocked(t time.Time) []string {
+	var active []string
+	for name, s := range r.schedules {
+		if s.Active(t) {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+// Resolve is ResolveWith the schedules active now.
+func (r *Resolver) Resolve(sgName, defaultSG string, aaaAttrs map[string]interface{}) ServiceGroup {
+	return r.ResolveWith(sgName, defaultSG, aaaAttrs, r.ActiveSchedules(time.Now()))
+}
+
+// ResolveWith performs three-layer merge:
 // 1. Start with default service group config (if defaultSG set)
 // 2. Override with AAA service group config (if sgName set)
 // 3. Override with per-field AAA attributes
-func (r *Resolver) Resolve(sgName, defaultSG string, aaaAttrs map[string]interface{}) ServiceGroup {
+//
+// The access line's rates are taken from the attributes as well; the
+// access components pass the rates the access node reported there.
+//
+// Each group's overrides for the active schedules are merged over that
+// group's own config, so AAA attributes still win over them. Taking the
+// active set rather than a time lets a caller resolve a session as it
+// was before a schedule changed state, as well as after.
+func (r *Resolver) ResolveWith(sgName, defaultSG string, aaaAttrs map[string]interface{}, active []string) ServiceGroup {
 	var result ServiceGroup
 
+	if len(active) > 0 {
+		result.Schedules = append([]string(nil), active...)
+		sort.Strings(result.Schedules)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -140,6 +250,7 @@ func (r *Resolver) Resolve(sgName, defaultSG string, aaaAttrs map[string]interfa
 		if cfg, ok := r.groups[defaultSG]; ok {
 			result.Name = defaultSG
 			applyConfig(&result, cfg)
+			applyScheduleOverrides(&result, cfg)
 		} else {
 			r.logger.Warn("Default service group not found", "name", defaultSG)
 		}
@@ -149,6 +260,7 @@ func (r *Resolver) Resolve(sgName, defaultSG string, aaaAttrs map[string]interfa
 		if cfg, ok := r.groups[sgName]; ok {
 			result.Name = sgName
 			applyConfig(&result, cfg)
+			applyScheduleOverrides(&result, cfg)
 		} else {
 			r.logger.Warn("Service group not found", "name", sgName)
 		}
@@ -159,6 +271,24 @@ func (r *Resolver) Resolve(sgName, defaultSG string, aaaAttrs map[string]interfa
 	return result
 }
 
+// applyScheduleOverrides merges the group's overrides for the schedules
+// active in r, in config order.
+func applyScheduleOverrides(r *ServiceGroup, cfg *servicegroup.Config) {
+	for _, s := range cfg.Schedules {
+		if !r.ScheduleActive(s.Schedule) {
+			continue
+		}
+		applyConfig(r, &servicegroup.Config{ACL: s.ACL, QoS: s.QoS})
+	}
+}
+
+// ScheduleActive reports whether the named schedule was active when the
+// group was resolved.
+func (r ServiceGroup) ScheduleActive(name string) bool {
+	i := sort.SearchStrings(r.Schedules, name)
+	return i < len(r.Schedules) && r.Schedules[i] == name
+}
+
 func applyConfig(r *ServiceGroup, cfg *servicegroup.Config) {
 	if cfg.VRF != "" {
 		r.VRF = cfg.VRF
@@ -190,6 +320,9 @@ func applyConfig(r *ServiceGroup, cfg *servicegroup.Config) {
 		if cfg.QoS.DownloadRate != 0 {
 			r.DownloadRate = cfg.QoS.DownloadRate
 		}
+		if cfg.QoS.LineRate != nil {
+			r.LineRateFactor = cfg.QoS.LineRate.Factor()
+		}
 	}
 	if cfg.Pool != "" {
 		r.Pool = cfg.Pool
@@ -206,6 +339,18 @@ func applyConfig(r *ServiceGroup, cfg *servicegroup.Config) {
 	if cfg.IPv6Profile != "" {
 		r.IPv6Profile = cfg.IPv6Profile
 	}
+	if cfg.Steering != "" {
+		r.SteeringPolicy = cfg.Steering
+	}
+	if cfg.Marking != nil {
+		if cfg.Marking.Ingress != "" {
+			r.Marking.Ingress = cfg.Marking.Ingress
+			r.Marking.IngressMap = cfg.Marking.IngressMap
+		}
+		if cfg.Marking.EgressPCPMap != "" {
+			r.Marking.EgressPCPMap = cfg.Marking.EgressPCPMap
+		}
+	}
 }
 
 func applyAAAOverrides(r *ServiceGroup, attrs map[string]interface{}) {
@@ -239,12 +384,33 @@ func applyAAAOverrides(r *ServiceGroup, attrs map[string]interface{}) {
 	if v := getUint64Attr(attrs, aaa.AttrQoSDownloadRate); v != 0 {
 		r.DownloadRate = v
 	}
+	if v := getUint64Attr(attrs, aaa.AttrAccessLineActualRateUp); v != 0 {
+		r.LineRateUp = uint32(v)
+	}
+	if v := getUint64Attr(attrs, aaa.AttrAccessLineActualRateDown); v != 0 {
+		r.LineRateDown = uint32(v)
+	}
 	if v := getStringAttr(attrs, aaa.AttrIPv4Profile); v != "" {
 		r.IPv4Profile = v
 	}
 	if v := getStringAttr(attrs, aaa.AttrIPv6Profile); v != "" {
 		r.IPv6Profile = v
 	}
+	if v := getStringAttr(attrs, aaa.AttrSteeringPolicy); v != "" {
+		r.SteeringPolicy = v
+	}
+	// A map alone selects remark; a mode alone drops the map of the
+	// group's remark.
+	ingress, ingressMap := getStringAttr(attrs, aaa.AttrMarkingIngress), getStringAttr(attrs, aaa.AttrMarkingIngressMap)
+	switch {
+	case ingress != "":
+		r.Marking.Ingress, r.Marking.IngressMap = ingress, ingressMap
+	case ingressMap != "":
+		r.Marking.Ingress, r.Marking.IngressMap = qos.MarkingRemark, ingressMap
+	}
+	if v := getStringAttr(attrs, aaa.AttrMarkingEgressPCPMap); v != "" {
+		r.Marking.EgressPCPMap = v
+	}
 }
 
 func getStringAttr(attrs map[string]interface{}, key string) string {
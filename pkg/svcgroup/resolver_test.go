@@ -1,8 +1,13 @@
 package svcgroup
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/config/qos"
+	"github.com/veesix-networks/osvbng/pkg/config/schedule"
 	"github.com/veesix-networks/osvbng/pkg/config/servicegroup"
 )
 
@@ -115,6 +120,32 @@ func TestResolvePerFieldAAAOverrides(t *testing.T) {
 	}
 }
 
+func TestResolveLineRate(t *testing.T) {
+	r := New()
+	r.Set("dsl", &servicegroup.Config{
+		QoS: &servicegroup.QoSConfig{
+			EgressPolicy: "plan",
+			LineRate:     &servicegroup.LineRateConfig{Enabled: true, OverheadFactor: 0.87},
+		},
+	})
+	r.Set("flat", &servicegroup.Config{QoS: &servicegroup.QoSConfig{EgressPolicy: "plan"}})
+
+	attrs := map[string]interface{}{
+		"access-line.actual-rate-up":   "1024",
+		"access-line.actual-rate-down": "16384",
+	}
+
+	result := r.Resolve("dsl", "", attrs)
+	if result.LineRateFactor != 0.87 || result.LineRateUp != 1024 || result.LineRateDown != 16384 {
+		t.Errorf("got factor %g, rates %d/%d", result.LineRateFactor, result.LineRateUp, result.LineRateDown)
+	}
+
+	result = r.Resolve("flat", "", attrs)
+	if result.LineRateFactor != 0 {
+		t.Errorf("expected no line-rate shaping, got factor %g", result.LineRateFactor)
+	}
+}
+
 func TestResolveUnknownServiceGroup(t *testing.T) {
 	r := New()
 
@@ -131,7 +162,7 @@ func TestResolveEmptyNames(t *testing.T) {
 	r := New()
 
 	result := r.Resolve("", "", nil)
-	if result != (ServiceGroup{}) {
+	if !reflect.DeepEqual(result, ServiceGroup{}) {
 		t.Errorf("expected zero-value ServiceGroup, got %+v", result)
 	}
 }
@@ -157,3 +188,78 @@ func TestResolveACLOverrides(t *testing.T) {
 		t.Errorf("expected ACLEgress default-out, got %s", result.ACLEgress)
 	}
 }
+
+func TestResolveWithScheduleOverrides(t *testing.T) {
+	r := New()
+	r.SetSchedule("night", &schedule.Schedule{Timezone: "UTC", Windows: []schedule.Window{{Start: "00:00", End: "06:00"}}})
+	r.Set("res", &servicegroup.Config{
+		ACL: &servicegroup.ACLConfig{Ingress: "day"},
+		QoS: &servicegroup.QoSConfig{EgressPolicy: "plan-100m", DownloadRate: 100_000_000},
+		Schedules: []servicegroup.ScheduleConfig{{
+			Schedule: "night",
+			QoS:      &servicegroup.QoSConfig{EgressPolicy: "plan-1g", DownloadRate: 1_000_000_000},
+		}},
+	})
+
+	noon := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+
+	day := r.ResolveWith("res", "", nil, r.ActiveSchedules(noon))
+	if day.QoSEgress != "plan-100m" || len(day.Schedules) != 0 {
+		t.Fatalf("noon: egress %q, schedules %v", day.QoSEgress, day.Schedules)
+	}
+
+	got := r.ResolveWith("res", "", nil, r.ActiveSchedules(night))
+	if got.QoSEgress != "plan-1g" || got.DownloadRate != 1_000_000_000 || !got.ScheduleActive("night") {
+		t.Fatalf("night: egress %q, rate %d, schedules %v", got.QoSEgress, got.DownloadRate, got.Schedules)
+	}
+	if got.ACLIngress != "day" {
+		t.Errorf("night: fields the schedule leaves alone changed: acl %q", got.ACLIngress)
+	}
+
+	// A per-subscriber AAA attribute still wins over the schedule.
+	got = r.ResolveWith("res", "", map[string]interface{}{aaa.AttrQoSEgressPolicy: "vip"}, r.ActiveSchedules(night))
+	if got.QoSEgress != "vip" {
+		t.Errorf("AAA override lost to the schedule: %q", got.QoSEgress)
+	}
+
+	if next := r.NextTransition(noon); !next.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextTransition = %s", next)
+	}
+}
+
+func TestResolveSteeringOverride(t *testing.T) {
+	r := New()
+	r.Set("sg1", &servicegroup.Config{Steering: "dpi"})
+
+	if result := r.Resolve("sg1", "", nil); result.SteeringPolicy != "dpi" {
+		t.Errorf("expected SteeringPolicy dpi, got %q", result.SteeringPolicy)
+	}
+
+	attrs := map[string]interface{}{"steering-policy": "parental"}
+	if result := r.Resolve("sg1", "", attrs); result.SteeringPolicy != "parental" {
+		t.Errorf("expected SteeringPolicy parental, got %q", result.SteeringPolicy)
+	}
+}
+
+func TestResolveMarkingOverride(t *testing.T) {
+	r := New()
+	r.Set("sg1", &servicegroup.Config{Marking: &qos.Marking{
+		Ingress: qos.MarkingRemark, IngressMap: "untrusted", EgressPCPMap: "access",
+	}})
+
+	result := r.Resolve("sg1", "", nil)
+	if result.Marking.IngressMap != "untrusted" || result.Marking.EgressPCPMap != "access" {
+		t.Errorf("expected group marking, got %+v", result.Marking)
+	}
+
+	result = r.Resolve("sg1", "", map[string]interface{}{"marking.ingress": "bleach"})
+	if result.Marking.Ingress != qos.MarkingBleach || result.Marking.IngressMap != "" || result.Marking.EgressPCPMap != "access" {
+		t.Errorf("expected bleach with group pcp map, got %+v", result.Marking)
+	}
+
+	result = r.Resolve("sg1", "", map[string]interface{}{"marking.ingress-map": "gold"})
+	if result.Marking.Ingress != qos.MarkingRemark || result.Marking.IngressMap != "gold" {
+		t.Errorf("expected remark through gold, got %+v", result.Marking)
+	}
+}
@@ -6,5 +6,6 @@ import (
 	_ "github.com/veesix-networks/osvbng/plugins/dhcp4/all"
 	_ "github.com/veesix-networks/osvbng/plugins/dhcp6/all"
 	_ "github.com/veesix-networks/osvbng/plugins/exporter/all"
+	_ "github.com/veesix-networks/osvbng/plugins/ipam/all"
 	_ "github.com/veesix-networks/osvbng/plugins/northbound/all"
 )
@@ -102,14 +102,8 @@ func (p *Provider) sendAccounting(session *auth.Session, statusType uint32) erro
 	}
 
 	for i := range p.acctMappings {
-		v, ok := session.Attributes[p.acctMappings[i].internal]
-		if !ok {
-			continue
-		}
-		if p.acctMappings[i].vendorID > 0 {
-			packet.Add(26, encodeVSARequest(p.acctMappings[i].vendorID, p.acctMappings[i].vendorType, []byte(v)))
-		} else {
-			packet.Add(p.acctMappings[i].attrType, radius.Attribute(v))
+		if v, ok := session.Attributes[p.acctMappings[i].internal]; ok {
+			p.acctMappings[i].add(packet, v)
 		}
 	}
 
@@ -18,6 +18,15 @@ const (
 	vsaL2GWHandoffGroup = 1
 	vsaL2GWSVLAN        = 2
 	vsaL2GWCVLAN        = 3
+
+	vsaNPTv6InternalPrefix = 4
+	vsaNPTv6ExternalPrefix = 5
+
+	vsaServiceSchedules = 6
+
+	vsaSteeringPolicy = 7
+
+	vsaBlackhole = 8
 )
 
 // osvbngVendorMappings are the built-in tier-2 response mappings under
@@ -28,6 +37,9 @@ func osvbngVendorMappings(vendorID uint32) []vendorMapping {
 		{vendorID: vendorID, vendorType: vsaL2GWHandoffGroup, internal: aaa.AttrL2GWHandoffGroup, decode: decodeVSAString},
 		{vendorID: vendorID, vendorType: vsaL2GWSVLAN, internal: aaa.AttrL2GWSVLAN, decode: decodeVSAString},
 		{vendorID: vendorID, vendorType: vsaL2GWCVLAN, internal: aaa.AttrL2GWCVLAN, decode: decodeVSAString},
+		{vendorID: vendorID, vendorType: vsaNPTv6InternalPrefix, internal: aaa.AttrNPTv6InternalPrefix, decode: decodeVSAString},
+		{vendorID: vendorID, vendorType: vsaSteeringPolicy, internal: aaa.AttrSteeringPolicy, decode: decodeVSAString},
+		{vendorID: vendorID, vendorType: vsaBlackhole, internal: aaa.AttrBlackhole, decode: decodeVSAString},
 	}
 }
 
@@ -39,5 +51,40 @@ func osvbngAcctMappings(vendorID uint32) []compiledRequestMapping {
 		{internal: aaa.AttrL2GWHandoffGroup, vendorID: vendorID, vendorType: vsaL2GWHandoffGroup},
 		{internal: aaa.AttrL2GWSVLAN, vendorID: vendorID, vendorType: vsaL2GWSVLAN},
 		{internal: aaa.AttrL2GWCVLAN, vendorID: vendorID, vendorType: vsaL2GWCVLAN},
+		{internal: aaa.AttrNPTv6InternalPrefix, vendorID: vendorID, vendorType: vsaNPTv6InternalPrefix},
+		{internal: aaa.AttrNPTv6ExternalPrefix, vendorID: vendorID, vendorType: vsaNPTv6ExternalPrefix},
+		{internal: aaa.AttrServiceSchedules, vendorID: vendorID, vendorType: vsaServiceSchedules},
+		{internal: aaa.AttrSteeringPolicy, vendorID: vendorID, vendorType: vsaSteeringPolicy},
+	}
+}
+
+// VendorIDADSLForum is the ADSL Forum (now Broadband Forum) enterprise
+// number its access-loop attributes are defined under (RFC 4679).
+const VendorIDADSLForum = 3561
+
+const (
+	vsaActualDataRateUpstream       = 129
+	vsaActualDataRateDownstream     = 130
+	vsaMinimumDataRateUpstream      = 131
+	vsaMinimumDataRateDownstream    = 132
+	vsaAttainableDataRateUpstream   = 133
+	vsaAttainableDataRateDownstream = 134
+	vsaMaximumDataRateUpstream      = 135
+	vsaMaximumDataRateDownstream    = 136
+)
+
+// accessLineMappings send the access-loop characteristics the access
+// node reported as the standard ADSL-Forum attributes, integer kbps, on
+// Access-Request and accounting alike.
+func accessLineMappings() []compiledRequestMapping {
+	return []compiledRequestMapping{
+		{internal: aaa.AttrAccessLineActualRateUp, vendorID: VendorIDADSLForum, vendorType: vsaActualDataRateUpstream, integer: true},
+		{internal: aaa.AttrAccessLineActualRateDown, vendorID: VendorIDADSLForum, vendorType: vsaActualDataRateDownstream, integer: true},
+		{internal: aaa.AttrAccessLineMinRateUp, vendorID: VendorIDADSLForum, vendorType: vsaMinimumDataRateUpstream, integer: true},
+		{internal: aaa.AttrAccessLineMinRateDown, vendorID: VendorIDADSLForum, vendorType: vsaMinimumDataRateDownstream, integer: true},
+		{internal: aaa.AttrAccessLineAttainableRateUp, vendorID: VendorIDADSLForum, vendorType: vsaAttainableDataRateUpstream, integer: true},
+		{internal: aaa.AttrAccessLineAttainableRateDown, vendorID: VendorIDADSLForum, vendorType: vsaAttainableDataRateDownstream, integer: true},
+		{internal: aaa.AttrAccessLineMaxRateUp, vendorID: VendorIDADSLForum, vendorType: vsaMaximumDataRateUpstream, integer: true},
+		{internal: aaa.AttrAccessLineMaxRateDown, vendorID: VendorIDADSLForum, vendorType: vsaMaximumDataRateDownstream, integer: true},
 	}
 }
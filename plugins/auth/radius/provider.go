@@ -45,6 +45,26 @@ type compiledRequestMapping struct {
 	attrType   radius.Type
 	vendorID   uint32
 	vendorType byte
+	// integer encodes the decimal value as a 4-byte integer rather than
+	// a string; a value that does not parse is not sent.
+	integer bool
+}
+
+// add appends the attribute m maps v to onto packet.
+func (m *compiledRequestMapping) add(packet *radius.Packet, v string) {
+	data := []byte(v)
+	if m.integer {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return
+		}
+		data = encodeUint32(uint32(n))
+	}
+	if m.vendorID > 0 {
+		packet.Add(26, encodeVSARequest(m.vendorID, m.vendorType, data))
+	} else {
+		packet.Add(m.attrType, radius.Attribute(data))
+	}
 }
 
 type Provider struct {
@@ -150,7 +170,7 @@ func New(cfg *config.Config) (auth.AuthProvider, error) {
 		tier3 = append(tier3, cm)
 	}
 
-	var reqMappings []compiledRequestMapping
+	reqMappings := accessLineMappings()
 	for _, m := range pluginCfg.RequestMappings {
 		cm := compiledRequestMapping{
 			internal:   m.Internal,
@@ -167,7 +187,7 @@ func New(cfg *config.Config) (auth.AuthProvider, error) {
 		reqMappings = append(reqMappings, cm)
 	}
 
-	acctMappings := osvbngAcctMappings(pluginCfg.VendorID)
+	acctMappings := append(osvbngAcctMappings(pluginCfg.VendorID), accessLineMappings()...)
 	for _, m := range pluginCfg.AccountingMappings {
 		cm := compiledRequestMapping{
 			internal:   m.Internal,
@@ -266,6 +286,7 @@ func (p *Provider) Authenticate(ctx context.Context, req *auth.AuthRequest) (*au
 
 	p.radiusStats.IncrAuthAccept(rc.addr)
 	attrs := p.extractAttributes(resp)
+	extractTunnelAttributes(resp, rc.secret, packet.Authenticator[:], attrs)
 
 	p.logger.Debug("authentication accepted",
 		"username", req.Username,
@@ -393,14 +414,8 @@ func (p *Provider) addRequestAVPs(packet *radius.Packet, req *auth.AuthRequest)
 	}
 
 	for i := range p.requestMappings {
-		v, ok := req.Attributes[p.requestMappings[i].internal]
-		if !ok {
-			continue
-		}
-		if p.requestMappings[i].vendorID > 0 {
-			packet.Add(26, encodeVSARequest(p.requestMappings[i].vendorID, p.requestMappings[i].vendorType, []byte(v)))
-		} else {
-			packet.Add(p.requestMappings[i].attrType, radius.Attribute(v))
+		if v, ok := req.Attributes[p.requestMappings[i].internal]; ok {
+			p.requestMappings[i].add(packet, v)
 		}
 	}
 
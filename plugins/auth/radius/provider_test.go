@@ -326,6 +326,30 @@ func TestExtractVSA(t *testing.T) {
 	})
 }
 
+func TestAccessLineMappingsEncodeInteger(t *testing.T) {
+	packet := radius.New(radius.CodeAccessRequest, []byte("secret"))
+	attrs := map[string]string{
+		aaa.AttrAccessLineActualRateDown: "16384",
+		aaa.AttrAccessLineActualRateUp:   "bogus",
+	}
+
+	mappings := accessLineMappings()
+	for i := range mappings {
+		if v, ok := attrs[mappings[i].internal]; ok {
+			mappings[i].add(packet, v)
+		}
+	}
+
+	if len(packet.Attributes) != 1 {
+		t.Fatalf("got %d attributes, want 1", len(packet.Attributes))
+	}
+	avp := packet.Attributes[0]
+	want := buildVSA(VendorIDADSLForum, vsaActualDataRateDownstream, encodeUint32(16384))
+	if avp.Type != 26 || string(avp.Attribute) != string(want) {
+		t.Fatalf("attribute %d = %x, want VSA %x", avp.Type, []byte(avp.Attribute), want)
+	}
+}
+
 func TestConfigValidation(t *testing.T) {
 	t.Run("no servers", func(t *testing.T) {
 		cfg := &Config{}
@@ -0,0 +1,157 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package radius
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"layeh.com/radius"
+)
+
+// RFC 2868 tunnel attribute types.
+const (
+	attrTunnelType           = 64
+	attrTunnelMediumType     = 65
+	attrTunnelClientEndpoint = 66
+	attrTunnelServerEndpoint = 67
+	attrTunnelPassword       = 69
+	attrTunnelAssignmentID   = 82
+	attrTunnelPreference     = 83
+	attrTunnelClientAuthID   = 90
+	attrTunnelServerAuthID   = 91
+)
+
+const (
+	tunnelTypeL2TP   = 3
+	tunnelMediumIPv4 = 1
+	tunnelMediumIPv6 = 2
+
+	// tunnelTagMax is the highest valid RFC 2868 tag; a first octet
+	// above it on an optionally-tagged string is part of the value.
+	tunnelTagMax byte = 0x1f
+)
+
+type tunnelMapping struct {
+	internal string
+	// integer attributes always carry a tag octet followed by a
+	// 3-octet value; string attributes carry an optional tag.
+	integer bool
+	decode  func(string) string
+}
+
+var tunnelMappings = map[byte]tunnelMapping{
+	attrTunnelType:           {internal: aaa.AttrTunnelType, integer: true, decode: decodeTunnelType},
+	attrTunnelMediumType:     {internal: aaa.AttrTunnelMediumType, integer: true, decode: decodeTunnelMedium},
+	attrTunnelClientEndpoint: {internal: aaa.AttrTunnelClientEndpoint, decode: decodeTunnelEndpoint},
+	attrTunnelServerEndpoint: {internal: aaa.AttrTunnelServerEndpoint, decode: decodeTunnelEndpoint},
+	attrTunnelAssignmentID:   {internal: aaa.AttrTunnelAssignmentID},
+	attrTunnelPreference:     {internal: aaa.AttrTunnelPreference, integer: true},
+	attrTunnelClientAuthID:   {internal: aaa.AttrTunnelClientAuthID},
+	attrTunnelServerAuthID:   {internal: aaa.AttrTunnelServerAuthID},
+}
+
+// extractTunnelAttributes decodes the RFC 2868 tagged tunnel attributes
+// of an Access-Accept into `attrs`. Tag 0 (untagged) lands on the base
+// internal name; tags 1-31 use "<name>:<tag>" so the L2TP LAC can build
+// one candidate per tag. Tunnel-Password is decrypted with the shared
+// secret and the Request Authenticator of the originating request.
+func extractTunnelAttributes(resp *radius.Packet, secret, requestAuthenticator []byte, attrs map[string]string) {
+	for _, avp := range resp.Attributes {
+		t := byte(avp.Type)
+		if t == attrTunnelPassword {
+			if len(avp.Attribute) < 4 {
+				continue
+			}
+			tag := avp.Attribute[0]
+			pw, _, err := radius.TunnelPassword(avp.Attribute[1:], secret, requestAuthenticator)
+			if err != nil {
+				continue
+			}
+			attrs[taggedName(aaa.AttrTunnelPassword, tag)] = string(pw)
+			continue
+		}
+		m, ok := tunnelMappings[t]
+		if !ok {
+			continue
+		}
+		tag, val, ok := splitTunnelValue(avp.Attribute, m.integer)
+		if !ok {
+			continue
+		}
+		if m.decode != nil {
+			val = m.decode(val)
+		}
+		if val == "" {
+			continue
+		}
+		attrs[taggedName(m.internal, tag)] = val
+	}
+}
+
+// splitTunnelValue strips the tag octet from a tunnel attribute and
+// returns the value as a string. Integer values are rendered in
+// decimal.
+func splitTunnelValue(a radius.Attribute, integer bool) (byte, string, bool) {
+	if integer {
+		if len(a) != 4 {
+			return 0, "", false
+		}
+		tag := a[0]
+		if tag > tunnelTagMax {
+			tag = 0
+		}
+		v := uint32(a[1])<<16 | uint32(a[2])<<8 | uint32(a[3])
+		return tag, strconv.FormatUint(uint64(v), 10), true
+	}
+	if len(a) == 0 {
+		return 0, "", false
+	}
+	if a[0] <= tunnelTagMax {
+		return a[0], string(a[1:]), true
+	}
+	return 0, string(a), true
+}
+
+func taggedName(base string, tag byte) string {
+	if tag == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s:%d", base, tag)
+}
+
+func decodeTunnelType(v string) string {
+	if v == strconv.Itoa(tunnelTypeL2TP) {
+		return "L2TP"
+	}
+	return v
+}
+
+func decodeTunnelMedium(v string) string {
+	switch v {
+	case strconv.Itoa(tunnelMediumIPv4):
+		return "IPv4"
+	case strconv.Itoa(tunnelMediumIPv6):
+		return "IPv6"
+	}
+	return v
+}
+
+// decodeTunnelEndpoint normalises an endpoint string. RFC 2868 carries
+// endpoints as text; an address that parses is re-rendered canonically
+// (IPv6 literals lose any brackets), anything else (an FQDN) passes
+// through unchanged.
+func decodeTunnelEndpoint(v string) string {
+	s := v
+	if len(s) > 2 && s[0] == '[' && s[len(s)-1] == ']' {
+		s = s[1 : len(s)-1]
+	}
+	if ip := net.ParseIP(s); ip != nil {
+		return ip.String()
+	}
+	return v
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package radius
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"layeh.com/radius"
+)
+
+// RFC 2867 Acct-Status-Type values.
+const (
+	acctStatusTunnelStart     = 9
+	acctStatusTunnelStop      = 10
+	acctStatusTunnelReject    = 11
+	acctStatusTunnelLinkStart = 12
+	acctStatusTunnelLinkStop  = 13
+)
+
+const (
+	attrAcctTerminateCause    = 49
+	attrAcctMultiSessionID    = 50
+	attrAcctTunnelConnection  = 68
+	attrAcctTunnelPacketsLost = 86
+)
+
+var tunnelAcctStatusTypes = map[auth.TunnelAcctStatus]uint32{
+	auth.TunnelAcctStart:     acctStatusTunnelStart,
+	auth.TunnelAcctStop:      acctStatusTunnelStop,
+	auth.TunnelAcctReject:    acctStatusTunnelReject,
+	auth.TunnelAcctLinkStart: acctStatusTunnelLinkStart,
+	auth.TunnelAcctLinkStop:  acctStatusTunnelLinkStop,
+}
+
+// RFC 2866 Acct-Terminate-Cause values.
+var terminateCauses = map[string]uint32{
+	auth.TerminateCauseUserRequest: 1,
+	auth.TerminateCauseLostCarrier: 2,
+	auth.TerminateCauseAdminReset:  6,
+	auth.TerminateCauseNASError:    9,
+	auth.TerminateCauseNASRequest:  10,
+}
+
+func (p *Provider) TunnelAccounting(_ context.Context, rec *auth.TunnelAccountingRecord) error {
+	packet, err := p.buildTunnelAccounting(rec)
+	if err != nil {
+		return err
+	}
+
+	resp, rc, err := p.sendAcctWithFailover(packet)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != radius.CodeAccountingResponse {
+		p.radiusStats.IncrAcctError(rc.addr, fmt.Errorf("unexpected code %d", resp.Code))
+		return fmt.Errorf("unexpected accounting response code: %d", resp.Code)
+	}
+
+	p.radiusStats.IncrAcctResponse(rc.addr)
+	return nil
+}
+
+// buildTunnelAccounting encodes an RFC 2867 Accounting-Request. Tunnel
+// attributes are sent untagged (tag 0): a record describes exactly one
+// tunnel.
+func (p *Provider) buildTunnelAccounting(rec *auth.TunnelAccountingRecord) (*radius.Packet, error) {
+	statusType, ok := tunnelAcctStatusTypes[rec.Status]
+	if !ok {
+		return nil, fmt.Errorf("unknown tunnel accounting status %q", rec.Status)
+	}
+
+	packet := radius.New(radius.CodeAccountingRequest, nil)
+	packet.Add(40, encodeUint32(statusType))
+
+	if rec.AcctSessionID != "" {
+		packet.Add(44, radius.Attribute(rec.AcctSessionID))
+	}
+	if rec.AcctMultiSessionID != "" {
+		packet.Add(attrAcctMultiSessionID, radius.Attribute(rec.AcctMultiSessionID))
+	}
+	if rec.Username != "" {
+		packet.Add(1, radius.Attribute(rec.Username))
+	}
+
+	if p.cfg.NASIdentifier != "" {
+		packet.Add(32, radius.Attribute(p.cfg.NASIdentifier))
+	}
+	if p.cfg.NASIP != "" {
+		if ip := net.ParseIP(p.cfg.NASIP); ip != nil {
+			packet.Add(4, radius.Attribute(ip.To4()))
+		}
+	}
+
+	packet.Add(attrTunnelType, taggedUint24(tunnelTypeL2TP))
+	switch rec.Medium {
+	case "ipv4":
+		packet.Add(attrTunnelMediumType, taggedUint24(tunnelMediumIPv4))
+	case "ipv6":
+		packet.Add(attrTunnelMediumType, taggedUint24(tunnelMediumIPv6))
+	}
+	if rec.ClientEndpoint != "" {
+		packet.Add(attrTunnelClientEndpoint, radius.Attribute(rec.ClientEndpoint))
+	}
+	if rec.ServerEndpoint != "" {
+		packet.Add(attrTunnelServerEndpoint, radius.Attribute(rec.ServerEndpoint))
+	}
+	if rec.ClientAuthID != "" {
+		packet.Add(attrTunnelClientAuthID, radius.Attribute(rec.ClientAuthID))
+	}
+	if rec.ServerAuthID != "" {
+		packet.Add(attrTunnelServerAuthID, radius.Attribute(rec.ServerAuthID))
+	}
+	if rec.TunnelConnectionID != "" {
+		packet.Add(attrAcctTunnelConnection, radius.Attribute(rec.TunnelConnectionID))
+	}
+
+	switch rec.Status {
+	case auth.TunnelAcctStop, auth.TunnelAcctLinkStop:
+		packet.Add(46, encodeUint32(rec.SessionDuration))
+		packet.Add(attrAcctTunnelPacketsLost, encodeUint32(rec.PacketsLost))
+	case auth.TunnelAcctReject:
+		packet.Add(attrAcctTunnelPacketsLost, encodeUint32(rec.PacketsLost))
+	}
+	if cause, ok := terminateCauses[rec.TerminateCause]; ok {
+		packet.Add(attrAcctTerminateCause, encodeUint32(cause))
+	}
+
+	packet.Add(55, encodeUint32(uint32(time.Now().Unix())))
+	return packet, nil
+}
+
+// taggedUint24 encodes an RFC 2868 integer tunnel attribute with tag 0.
+func taggedUint24(v uint32) radius.Attribute {
+	return radius.Attribute{0, byte(v >> 16), byte(v >> 8), byte(v)}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package radius
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/aaa"
+	"github.com/veesix-networks/osvbng/pkg/auth"
+	"layeh.com/radius"
+)
+
+func TestExtractTunnelAttributes(t *testing.T) {
+	secret := []byte("testing123")
+	reqAuth := make([]byte, 16)
+	for i := range reqAuth {
+		reqAuth[i] = byte(i)
+	}
+
+	pw, err := radius.NewTunnelPassword([]byte("l2tp-secret"), []byte{0x80, 0x01}, secret, reqAuth)
+	if err != nil {
+		t.Fatalf("NewTunnelPassword: %v", err)
+	}
+
+	resp := radius.New(radius.CodeAccessAccept, secret)
+	resp.Add(attrTunnelType, radius.Attribute{1, 0, 0, tunnelTypeL2TP})
+	resp.Add(attrTunnelMediumType, radius.Attribute{1, 0, 0, tunnelMediumIPv6})
+	resp.Add(attrTunnelServerEndpoint, append(radius.Attribute{1}, "2001:db8::10"...))
+	resp.Add(attrTunnelPreference, radius.Attribute{1, 0, 0, 5})
+	resp.Add(attrTunnelPassword, append(radius.Attribute{1}, pw...))
+	resp.Add(attrTunnelType, radius.Attribute{0, 0, 0, tunnelTypeL2TP})
+	resp.Add(attrTunnelServerEndpoint, radius.Attribute("192.0.2.10"))
+
+	attrs := make(map[string]string)
+	extractTunnelAttributes(resp, secret, reqAuth, attrs)
+
+	want := map[string]string{
+		aaa.AttrTunnelType + ":1":           "L2TP",
+		aaa.AttrTunnelMediumType + ":1":     "IPv6",
+		aaa.AttrTunnelServerEndpoint + ":1": "2001:db8::10",
+		aaa.AttrTunnelPreference + ":1":     "5",
+		aaa.AttrTunnelPassword + ":1":       "l2tp-secret",
+		aaa.AttrTunnelType:                  "L2TP",
+		aaa.AttrTunnelServerEndpoint:        "192.0.2.10",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("attrs[%q] = %q, want %q", k, attrs[k], v)
+		}
+	}
+}
+
+func TestDecodeTunnelEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"[2001:db8::1]":   "2001:db8::1",
+		"2001:DB8:0::1":   "2001:db8::1",
+		"192.0.2.1":       "192.0.2.1",
+		"lns.example.net": "lns.example.net",
+	}
+	for in, want := range cases {
+		if got := decodeTunnelEndpoint(in); got != want {
+			t.Errorf("decodeTunnelEndpoint(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildTunnelAccountingLinkStop(t *testing.T) {
+	p := &Provider{cfg: &Config{NASIdentifier: "bng1"}}
+	packet, err := p.buildTunnelAccounting(&auth.TunnelAccountingRecord{
+		Status:             auth.TunnelAcctLinkStop,
+		AcctSessionID:      "sess-1",
+		AcctMultiSessionID: "tun-1",
+		TunnelConnectionID: "3/40/7/70",
+		Medium:             "ipv6",
+		ClientEndpoint:     "2001:db8::1",
+		ServerEndpoint:     "2001:db8::2",
+		Username:           "alice",
+		SessionDuration:    120,
+		PacketsLost:        4,
+		TerminateCause:     auth.TerminateCauseAdminReset,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u32 := func(typ radius.Type) uint32 {
+		a, ok := packet.Lookup(typ)
+		if !ok || len(a) != 4 {
+			t.Fatalf("attribute %d missing", typ)
+		}
+		return binary.BigEndian.Uint32(a)
+	}
+	if got := u32(40); got != acctStatusTunnelLinkStop {
+		t.Errorf("Acct-Status-Type = %d", got)
+	}
+	if got := u32(attrAcctTerminateCause); got != 6 {
+		t.Errorf("Acct-Terminate-Cause = %d", got)
+	}
+	if got := u32(attrAcctTunnelPacketsLost); got != 4 {
+		t.Errorf("Acct-Tunnel-Packets-Lost = %d", got)
+	}
+	if got := u32(46); got != 120 {
+		t.Errorf("Acct-Session-Time = %d", got)
+	}
+	if a, _ := packet.Lookup(attrTunnelMediumType); string(a) != string(radius.Attribute{0, 0, 0, tunnelMediumIPv6}) {
+		t.Errorf("Tunnel-Medium-Type = %v", a)
+	}
+	for typ, want := range map[radius.Type]string{
+		44:                       "sess-1",
+		attrAcctMultiSessionID:   "tun-1",
+		attrAcctTunnelConnection: "3/40/7/70",
+		attrTunnelClientEndpoint: "2001:db8::1",
+		attrTunnelServerEndpoint: "2001:db8::2",
+		1:                        "alice",
+		32:                       "bng1",
+	} {
+		if a, _ := packet.Lookup(typ); string(a) != want {
+			t.Errorf("attribute %d = %q, want %q", typ, a, want)
+		}
+	}
+}
+
+func TestBuildTunnelAccountingUnknownStatus(t *testing.T) {
+	p := &Provider{cfg: &Config{}}
+	if _, err := p.buildTunnelAccounting(&auth.TunnelAccountingRecord{Status: "bogus"}); err == nil {
+		t.Fatal("unknown status should fail")
+	}
+}
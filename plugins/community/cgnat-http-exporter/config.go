@@ -82,6 +82,12 @@ type Config struct {
 	// privacy/compliance reasons — outside IP + port range are still
 	// sufficient to correlate via session_id.
 	IncludeInsideIP *bool `json:"include_inside_ip,omitempty" yaml:"include_inside_ip,omitempty"`
+
+	// IncludeNPTv6 also forwards the sessions' NPTv6 prefix
+	// translations (TopicNPTv6Binding) as nptv6-allocate and
+	// nptv6-release events, so the same log answers "which subscriber
+	// held this external prefix". Default false.
+	IncludeNPTv6 bool `json:"include_nptv6,omitempty" yaml:"include_nptv6,omitempty"`
 }
 
 type TLSConfig struct {
@@ -45,9 +45,10 @@ type Component struct {
 	failed   atomic.Uint64
 	dropped  atomic.Uint64 // queue was full on ingest
 
-	sub    events.Subscription
-	wg     sync.WaitGroup
-	cancel context.CancelFunc
+	sub      events.Subscription
+	nptv6Sub events.Subscription
+	wg       sync.WaitGroup
+	cancel   context.CancelFunc
 }
 
 // NewComponent is the plugin entry point wired via component.Register
@@ -102,6 +103,9 @@ func (c *Component) Start(ctx context.Context) error {
 	}
 
 	c.sub = c.bus.Subscribe(events.TopicCGNATMapping, c.handleEvent)
+	if c.cfg.IncludeNPTv6 {
+		c.nptv6Sub = c.bus.Subscribe(events.TopicNPTv6Binding, c.handleNPTv6Event)
+	}
 
 	c.logger.Info("cgnat-http-exporter started",
 		"endpoint", c.cfg.Endpoint,
@@ -119,6 +123,9 @@ func (c *Component) Stop(ctx context.Context) error {
 	if c.sub != nil {
 		c.sub.Unsubscribe()
 	}
+	if c.nptv6Sub != nil {
+		c.nptv6Sub.Unsubscribe()
+	}
 	// Close the queue so workers see EOF and exit after draining.
 	close(c.queue)
 
@@ -165,7 +172,28 @@ func (c *Component) handleEvent(ev events.Event) {
 		c.logger.Warn("cgnat-http-exporter: marshal failed", "err", err)
 		return
 	}
+	c.enqueue(body, data.SessionID)
+}
+
+// handleNPTv6Event is the TopicNPTv6Binding counterpart of handleEvent.
+func (c *Component) handleNPTv6Event(ev events.Event) {
+	data, ok := ev.Data.(*events.NPTv6BindingEvent)
+	if !ok || data == nil || data.Binding == nil {
+		return
+	}
+	c.received.Add(1)
+
+	body, err := c.marshalNPTv6(data)
+	if err != nil {
+		c.failed.Add(1)
+		c.logger.Warn("cgnat-http-exporter: marshal failed", "err", err)
+		return
+	}
+	c.enqueue(body, data.SessionID)
+}
 
+// enqueue hands a marshalled event to the workers.
+func (c *Component) enqueue(body []byte, sessionID string) {
 	// Non-blocking send: drop if the queue is saturated. Dropping is
 	// preferable to blocking the publisher (CGNAT mapping hot path).
 	// Operators should alert on a non-zero drop counter.
@@ -174,7 +202,7 @@ func (c *Component) handleEvent(ev events.Event) {
 	default:
 		c.dropped.Add(1)
 		c.logger.Warn("cgnat-http-exporter: queue full, dropping event",
-			"session_id", data.SessionID,
+			"session_id", sessionID,
 			"queue_size", c.cfg.QueueSize,
 		)
 	}
@@ -235,6 +263,15 @@ type payload struct {
 	PortBlockEnd   uint16    `json:"port_block_end"`
 	InsideIP       string    `json:"inside_ip,omitempty"`
 	InsideVRFID    uint32    `json:"inside_vrf_id,omitempty"`
+	// Forward is set for a single-port forward, whose outside port is
+	// PortBlockStart.
+	Forward *forwardPayload `json:"forward,omitempty"`
+}
+
+type forwardPayload struct {
+	Protocol   string `json:"protocol"`
+	InsidePort uint16 `json:"inside_port"`
+	Source     string `json:"source"` // "static" | "api" | "pcp"
 }
 
 func (c *Component) marshal(ev *events.CGNATMappingEvent) ([]byte, error) {
@@ -254,6 +291,36 @@ func (c *Component) marshal(ev *events.CGNATMappingEvent) ([]byte, error) {
 		p.InsideIP = ipString(m.InsideIP)
 		p.InsideVRFID = m.InsideVRFID
 	}
+	if f := m.Forward; f != nil {
+		p.Forward = &forwardPayload{Protocol: f.Protocol, InsidePort: f.InsidePort, Source: f.Source}
+	}
+	return json.Marshal(&p)
+}
+
+// nptv6Payload is the JSON structure POSTed per NPTv6 binding event.
+type nptv6Payload struct {
+	Event          string    `json:"event"` // "nptv6-allocate" | "nptv6-release"
+	At             time.Time `json:"at"`
+	SRGName        string    `json:"srg_name,omitempty"`
+	SessionID      string    `json:"session_id,omitempty"`
+	Username       string    `json:"username,omitempty"`
+	ExternalPrefix string    `json:"external_prefix"`
+	InternalPrefix string    `json:"internal_prefix,omitempty"`
+}
+
+func (c *Component) marshalNPTv6(ev *events.NPTv6BindingEvent) ([]byte, error) {
+	b := ev.Binding
+	p := nptv6Payload{
+		Event:          "nptv6-" + eventKind(ev.IsAdd),
+		At:             time.Now().UTC(),
+		SRGName:        ev.SRGName,
+		SessionID:      ev.SessionID,
+		Username:       b.Username,
+		ExternalPrefix: b.External,
+	}
+	if c.cfg.IncludeInsideIP != nil && *c.cfg.IncludeInsideIP {
+		p.InternalPrefix = b.Internal
+	}
 	return json.Marshal(&p)
 }
 
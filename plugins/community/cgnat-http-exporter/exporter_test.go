@@ -294,6 +294,58 @@ func TestComponent_Marshal_NilMapping(t *testing.T) {
 	}
 }
 
+func TestComponent_Marshal_Forward(t *testing.T) {
+	cfg := testConfig("http://ignored")
+	c := &Component{logger: loggerForTest(), cfg: cfg}
+	data, err := c.marshal(&events.CGNATMappingEvent{
+		SessionID: "s1",
+		IsAdd:     true,
+		Mapping: &models.CGNATMapping{
+			PoolName:       "p1",
+			OutsideIP:      net.ParseIP("198.51.100.1"),
+			PortBlockStart: 2048,
+			PortBlockEnd:   2048,
+			Forward:        &models.CGNATPortForward{Protocol: "tcp", InsidePort: 80, Source: models.CGNATForwardPCP},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.Forward == nil || got.Forward.Protocol != "tcp" || got.Forward.InsidePort != 80 || got.Forward.Source != "pcp" {
+		t.Fatalf("forward = %+v", got.Forward)
+	}
+	if got.PortBlockStart != 2048 || got.PortBlockEnd != 2048 {
+		t.Fatalf("outside port = %d-%d, want 2048", got.PortBlockStart, got.PortBlockEnd)
+	}
+}
+
+func TestComponent_Marshal_NPTv6(t *testing.T) {
+	cfg := testConfig("http://ignored")
+	c := &Component{logger: loggerForTest(), cfg: cfg}
+	data, err := c.marshalNPTv6(&events.NPTv6BindingEvent{
+		SessionID: "s1",
+		Binding: &models.NPTv6Binding{
+			SessionID: "s1",
+			Internal:  "fd00:1::/56",
+			External:  "2001:db8:100::/56",
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got nptv6Payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.Event != "nptv6-release" || got.ExternalPrefix != "2001:db8:100::/56" || got.InternalPrefix != "fd00:1::/56" {
+		t.Fatalf("payload = %+v", got)
+	}
+}
+
 // waitFor polls predicate up to d; fails the test if it never becomes true.
 func waitFor(t *testing.T, d time.Duration, ok func() bool) {
 	t.Helper()
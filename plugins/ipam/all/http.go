@@ -0,0 +1,3 @@
+package all
+
+import _ "github.com/veesix-networks/osvbng/plugins/ipam/http"
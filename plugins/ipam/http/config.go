@@ -0,0 +1,61 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package http
+
+import (
+	"time"
+
+	"github.com/veesix-networks/osvbng/pkg/configmgr"
+	"github.com/veesix-networks/osvbng/pkg/ipam"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+)
+
+const Namespace = "ipam.http"
+
+type Config struct {
+	netbind.EndpointBinding `json:",inline" yaml:",inline"`
+	Endpoint                string            `json:"endpoint" yaml:"endpoint"`
+	Timeout                 time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	TLS                     *TLSConfig        `json:"tls,omitempty" yaml:"tls,omitempty"`
+	Auth                    *AuthConfig       `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Headers                 map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Allocate                *RequestConfig    `json:"allocate,omitempty" yaml:"allocate,omitempty"`
+	Release                 *RequestConfig    `json:"release,omitempty" yaml:"release,omitempty"`
+	Response                *ResponseConfig   `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+type TLSConfig struct {
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+	CACertFile         string `json:"ca_cert_file,omitempty" yaml:"ca_cert_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+}
+
+type AuthConfig struct {
+	Type     string `json:"type,omitempty" yaml:"type,omitempty"` // "basic" or "bearer"
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Token    string `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// RequestConfig overrides the endpoint, method or body of one call.
+// Endpoint and Template are Go templates over TemplateContext.
+type RequestConfig struct {
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Method   string `json:"method,omitempty" yaml:"method,omitempty"`
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// ResponseConfig locates the chunk in the allocate response body, as
+// dotted paths such as "data.prefix" or "results[0].id".
+type ResponseConfig struct {
+	PrefixPath string `json:"prefix_path,omitempty" yaml:"prefix_path,omitempty"`
+	IDPath     string `json:"id_path,omitempty" yaml:"id_path,omitempty"`
+}
+
+func init() {
+	configmgr.RegisterPluginConfig(Namespace, Config{})
+	ipam.Register("http", New)
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package http
+
+import "time"
+
+const DefaultAllocateTemplate = `{
+  "action": "allocate",
+  "family": "{{.Family}}",
+  "profile": "{{.Profile}}",
+  "vrf": "{{.VRF}}",
+  "prefix_length": {{.PrefixLength}},
+  "device_id": "{{.DeviceID}}"
+}`
+
+const DefaultReleaseTemplate = `{
+  "action": "release",
+  "family": "{{.Family}}",
+  "profile": "{{.Profile}}",
+  "vrf": "{{.VRF}}",
+  "prefix": "{{.Prefix}}",
+  "id": "{{.ID}}",
+  "device_id": "{{.DeviceID}}"
+}`
+
+const DefaultMethod = "POST"
+
+const DefaultTimeout = 10 * time.Second
+
+const (
+	DefaultPrefixPath = "prefix"
+	DefaultIDPath     = "id"
+)
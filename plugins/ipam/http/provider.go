@@ -0,0 +1,330 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/veesix-networks/osvbng/pkg/config"
+	"github.com/veesix-networks/osvbng/pkg/configmgr"
+	"github.com/veesix-networks/osvbng/pkg/ipam"
+	"github.com/veesix-networks/osvbng/pkg/logger"
+	"github.com/veesix-networks/osvbng/pkg/netbind"
+	"github.com/veesix-networks/osvbng/pkg/provider"
+)
+
+// TemplateContext is what endpoint and body templates are rendered with.
+// Prefix and ID are only set on release.
+type TemplateContext struct {
+	Action       string
+	Family       string
+	Profile      string
+	VRF          string
+	PrefixLength uint8
+	Prefix       string
+	ID           string
+	DeviceID     string
+}
+
+type call struct {
+	method   string
+	endpoint *template.Template
+	body     *template.Template
+}
+
+type Provider struct {
+	cfg      *Config
+	client   *http.Client
+	logger   *logger.Logger
+	allocate call
+	release  call
+}
+
+func New(cfg *config.Config) (ipam.Provider, error) {
+	pluginCfgRaw, ok := configmgr.GetPluginConfig(Namespace)
+	if !ok {
+		return nil, fmt.Errorf("%s is not configured", Namespace)
+	}
+
+	pluginCfg, ok := pluginCfgRaw.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for %s", Namespace)
+	}
+	return newProvider(pluginCfg)
+}
+
+func newProvider(cfg *Config) (*Provider, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required for HTTP IPAM provider")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	p := &Provider{
+		cfg:    cfg,
+		logger: logger.Get(Namespace),
+	}
+
+	client, err := p.buildHTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build HTTP client: %w", err)
+	}
+	p.client = client
+
+	if p.allocate, err = p.buildCall("allocate", cfg.Allocate, DefaultAllocateTemplate); err != nil {
+		return nil, err
+	}
+	if p.release, err = p.buildCall("release", cfg.Release, DefaultReleaseTemplate); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("HTTP IPAM provider initialized", "endpoint", cfg.Endpoint)
+	return p, nil
+}
+
+func (p *Provider) Info() provider.Info {
+	return provider.Info{
+		Name:    "http",
+		Version: "0.0.1",
+		Author:  "osvbng Core Team",
+	}
+}
+
+func (p *Provider) Allocate(ctx context.Context, req *ipam.ChunkRequest) (*ipam.Chunk, error) {
+	body, err := p.do(ctx, p.allocate, p.templateContext("allocate", req, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("decode allocate response: %w", err)
+	}
+
+	prefixPath, idPath := DefaultPrefixPath, DefaultIDPath
+	if p.cfg.Response != nil {
+		if p.cfg.Response.PrefixPath != "" {
+			prefixPath = p.cfg.Response.PrefixPath
+		}
+		if p.cfg.Response.IDPath != "" {
+			idPath = p.cfg.Response.IDPath
+		}
+	}
+
+	prefix, ok := extractString(data, prefixPath)
+	if !ok {
+		return nil, fmt.Errorf("allocate response has no %q", prefixPath)
+	}
+	parsed, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("allocate response prefix: %w", err)
+	}
+	if parsed.Bits() != int(req.PrefixLength) {
+		return nil, fmt.Errorf("IPAM returned %s, want a /%d", parsed, req.PrefixLength)
+	}
+	id, _ := extractString(data, idPath)
+
+	return &ipam.Chunk{Prefix: parsed.Masked().String(), ID: id}, nil
+}
+
+func (p *Provider) Release(ctx context.Context, req *ipam.ChunkRequest, chunk *ipam.Chunk) error {
+	_, err := p.do(ctx, p.release, p.templateContext("release", req, chunk))
+	return err
+}
+
+func (p *Provider) templateContext(action string, req *ipam.ChunkRequest, chunk *ipam.Chunk) *TemplateContext {
+	tc := &TemplateContext{
+		Action:       action,
+		Family:       req.Family,
+		Profile:      req.Profile,
+		VRF:          req.VRF,
+		PrefixLength: req.PrefixLength,
+		DeviceID:     req.DeviceID,
+	}
+	if chunk != nil {
+		tc.Prefix = chunk.Prefix
+		tc.ID = chunk.ID
+	}
+	return tc
+}
+
+func (p *Provider) do(ctx context.Context, c call, tc *TemplateContext) ([]byte, error) {
+	var endpoint, body bytes.Buffer
+	if err := c.endpoint.Execute(&endpoint, tc); err != nil {
+		return nil, fmt.Errorf("failed to render %s endpoint template: %w", tc.Action, err)
+	}
+	if err := c.body.Execute(&body, tc); err != nil {
+		return nil, fmt.Errorf("failed to render %s body template: %w", tc.Action, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, c.method, endpoint.String(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	p.setRequestHeaders(httpReq)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	p.logger.Debug("IPAM response",
+		"action", tc.Action,
+		"status", resp.StatusCode,
+		"profile", tc.Profile)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("IPAM %s returned status %d", tc.Action, resp.StatusCode)
+	}
+	return respBody, nil
+}
+
+func (p *Provider) buildCall(name string, rc *RequestConfig, defaultBody string) (call, error) {
+	c := call{method: DefaultMethod}
+	endpoint, body := p.cfg.Endpoint, defaultBody
+	if rc != nil {
+		if rc.Method != "" {
+			c.method = rc.Method
+		}
+		if rc.Endpoint != "" {
+			endpoint = rc.Endpoint
+		}
+		if rc.Template != "" {
+			body = rc.Template
+		}
+	}
+
+	var err error
+	if c.endpoint, err = template.New(name + "_endpoint").Parse(endpoint); err != nil {
+		return c, fmt.Errorf("failed to parse %s endpoint template: %w", name, err)
+	}
+	if c.body, err = template.New(name + "_body").Parse(body); err != nil {
+		return c, fmt.Errorf("failed to parse %s body template: %w", name, err)
+	}
+	return c, nil
+}
+
+func (p *Provider) buildHTTPClient() (*http.Client, error) {
+	binding, err := p.cfg.EndpointBinding.Resolve(netbind.FamilyV4)
+	if err != nil {
+		return nil, fmt.Errorf("resolve binding: %w", err)
+	}
+	client := netbind.HTTPClient(binding, p.cfg.Timeout)
+
+	if p.cfg.TLS != nil {
+		tlsConfig, err := p.buildTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := client.Transport.(*http.Transport); ok {
+			t.TLSClientConfig = tlsConfig
+		}
+	}
+
+	return client, nil
+}
+
+func (p *Provider) buildTLSConfig() (*tls.Config, error) {
+	tlsCfg := p.cfg.TLS
+	config := &tls.Config{
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+	}
+
+	if tlsCfg.CACertFile != "" {
+		caCert, err := os.ReadFile(tlsCfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA cert file: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA cert")
+		}
+		config.RootCAs = caCertPool
+	}
+
+	if tlsCfg.CertFile != "" && tlsCfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func (p *Provider) setRequestHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+
+	if p.cfg.Auth != nil {
+		switch strings.ToLower(p.cfg.Auth.Type) {
+		case "basic":
+			req.SetBasicAuth(p.cfg.Auth.Username, p.cfg.Auth.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+p.cfg.Auth.Token)
+		}
+	}
+
+	for k, v := range p.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// extractString follows a dotted path, with [n] for array indexes,
+// through decoded JSON.
+func extractString(data map[string]interface{}, path string) (string, bool) {
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		key, index := part, -1
+		if open := strings.Index(part, "["); open >= 0 && strings.HasSuffix(part, "]") {
+			n, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil {
+				return "", false
+			}
+			key, index = part[:open], n
+		}
+		if key != "" {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			current = m[key]
+		}
+		if index >= 0 {
+			arr, ok := current.([]interface{})
+			if !ok || index >= len(arr) {
+				return "", false
+			}
+			current = arr[index]
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The osvbng Authors
+// Licensed under the GNU General Public License v3.0 or later.
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/veesix-networks/osvbng/pkg/ipam"
+)
+
+func TestProviderAllocateAndRelease(t *testing.T) {
+	var released map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]interface{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("request body %q: %v", body, err)
+		}
+		switch r.URL.Path {
+		case "/chunks":
+			if req["prefix_length"] != float64(26) || req["profile"] != "residential" {
+				t.Errorf("allocate body = %v", req)
+			}
+			w.Write([]byte(`{"data":{"cidr":"100.64.8.0/26","ref":42}}`))
+		case "/chunks/42":
+			if r.Method != http.MethodDelete {
+				t.Errorf("release method = %s", r.Method)
+			}
+			released = req
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := newProvider(&Config{
+		Endpoint: srv.URL + "/chunks",
+		Auth:     &AuthConfig{Type: "bearer", Token: "secret"},
+		Release: &RequestConfig{
+			Endpoint: srv.URL + "/chunks/{{.ID}}",
+			Method:   http.MethodDelete,
+		},
+		Response: &ResponseConfig{PrefixPath: "data.cidr", IDPath: "data.ref"},
+	})
+	if err != nil {
+		t.Fatalf("newProvider: %v", err)
+	}
+
+	req := &ipam.ChunkRequest{Family: "ipv4", Profile: "residential", PrefixLength: 26, DeviceID: "bng1"}
+	chunk, err := p.Allocate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if chunk.Prefix != "100.64.8.0/26" || chunk.ID != "42" {
+		t.Fatalf("chunk = %+v", chunk)
+	}
+
+	if err := p.Release(context.Background(), req, chunk); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if released["action"] != "release" || released["prefix"] != "100.64.8.0/26" {
+		t.Fatalf("release body = %v", released)
+	}
+}
+
+func TestProviderAllocateRejectsWrongLength(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"prefix":"100.64.8.0/24"}`))
+	}))
+	defer srv.Close()
+
+	p, err := newProvider(&Config{Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("newProvider: %v", err)
+	}
+	if _, err := p.Allocate(context.Background(), &ipam.ChunkRequest{Family: "ipv4", PrefixLength: 26}); err == nil {
+		t.Fatal("expected an error for a /24 when a /26 was requested")
+	}
+}
+
+func TestProviderAllocateErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "exhausted", http.StatusConflict)
+	}))
+	defer srv.Close()
+
+	p, err := newProvider(&Config{Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("newProvider: %v", err)
+	}
+	if _, err := p.Allocate(context.Background(), &ipam.ChunkRequest{Family: "ipv4", PrefixLength: 26}); err == nil {
+		t.Fatal("expected an error for a non-2xx status")
+	}
+}